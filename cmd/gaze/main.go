@@ -10,6 +10,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -21,12 +22,14 @@ import (
 	"github.com/unbound-force/gaze/internal/config"
 	"github.com/unbound-force/gaze/internal/crap"
 	"github.com/unbound-force/gaze/internal/docscan"
+	"github.com/unbound-force/gaze/internal/gitdiff"
 	"github.com/unbound-force/gaze/internal/loader"
 	"github.com/unbound-force/gaze/internal/quality"
 	"github.com/unbound-force/gaze/internal/report"
 	"github.com/unbound-force/gaze/internal/scaffold"
 	"github.com/unbound-force/gaze/internal/taxonomy"
 	"golang.org/x/tools/go/packages"
+	"gopkg.in/yaml.v3"
 )
 
 // logger is the application-wide structured logger (writes to stderr).
@@ -41,7 +44,63 @@ var (
 	date    = "unknown"
 )
 
+// Exit codes returned by the gaze CLI.
+const (
+	// exitOK means the command succeeded.
+	exitOK = 0
+
+	// exitError means the command could not run: invalid flags or
+	// arguments, or packages that failed to load or analyze.
+	exitError = 1
+
+	// exitGate means the command ran but a CI gate failed, such as
+	// --max-crapload, --fail-on-type, or a baseline regression.
+	exitGate = 2
+)
+
+// gateError reports a CI gate violation. main exits with exitGate
+// when a command returns one.
+type gateError struct {
+	err error
+}
+
+func (e *gateError) Error() string { return e.err.Error() }
+
+func (e *gateError) Unwrap() error { return e.err }
+
+// exitCode maps an error returned by a command to the process exit
+// code.
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	var gate *gateError
+	if errors.As(err, &gate) {
+		return exitGate
+	}
+	return exitError
+}
+
 func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the gaze CLI with args, writing command output to
+// stdout and errors to stderr, and returns the process exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	root := newRootCmd()
+	root.SetArgs(args)
+	root.SetOut(stdout)
+	root.SetErr(stderr)
+	err := root.Execute()
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+	}
+	return exitCode(err)
+}
+
+// newRootCmd creates the "gaze" root command with every subcommand.
+func newRootCmd() *cobra.Command {
 	root := &cobra.Command{
 		Use:   "gaze",
 		Short: "Gaze — test quality analysis via side effect detection",
@@ -59,15 +118,16 @@ produced by their test targets.`,
 	root.AddCommand(newCrapCmd())
 	root.AddCommand(newInitCmd())
 	root.AddCommand(newQualityCmd())
+	root.AddCommand(newCoverageCmd())
+	root.AddCommand(newGraphCmd())
+	root.AddCommand(newExplainCmd())
+	root.AddCommand(newDiffCmd())
 	root.AddCommand(newReportCmd())
 	root.AddCommand(newSchemaCmd())
 	root.AddCommand(newDocscanCmd())
+	root.AddCommand(newConfigCmd())
 	root.AddCommand(newSelfCheckCmd())
-
-	if err := root.Execute(); err != nil {
-		_, _ = fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
-	}
+	return root
 }
 
 // initParams holds the parsed flags for the init command.
@@ -124,34 +184,63 @@ type analyzeParams struct {
 	format            string
 	function          string
 	includeUnexported bool
+	includeTests      bool
 	interactive       bool
 	classify          bool
 	verbose           bool
 	configPath        string
 	contractualThresh int
 	incidentalThresh  int
+	testCallers       bool
+	cacheDir          string
+	stream            bool
+	legacySentinels   bool
+	failOnTypes       []string
+	quiet             bool
+	color             string
+	summary           bool
+	since             string
+	depth             int
+	exclude           []string
+	sort              string
+	groupBy           string
+	confidenceBelow   int
+	labels            []string
+	minTier           string
+	types             []string
+	locations         string
+	deferTraps        bool
+	timeout           time.Duration
+	enable            []string
+	disable           []string
+	listFunctions     bool
+	tags              []string
+	goos              string
+	goarch            string
+	jsonCompact       bool
+	watch             bool
 	stdout            io.Writer
 	stderr            io.Writer
 }
 
-// loadConfig loads the GazeConfig from the given path (or searches
-// the current directory if path is empty), then applies any CLI
-// threshold overrides. A threshold value of -1 means "not set"
-// (use config/default). Any other value overrides the loaded config.
+// loadConfig loads the GazeConfig from the given path (or, if path is
+// empty, discovers .gaze.yaml by walking up from startDir to the
+// module root), then applies any CLI threshold overrides. A
+// threshold value of -1 means "not set" (use config/default). Any
+// other value overrides the loaded config.
 //
 // Valid threshold values are in [1, 99]. The contractual threshold
 // must be strictly greater than the incidental threshold to prevent
 // degenerate classifications (e.g., contractual=0 would classify
 // every side effect as contractual regardless of signal strength).
-func loadConfig(path string, contractualThresh, incidentalThresh int) (*config.GazeConfig, error) {
+func loadConfig(path, startDir string, contractualThresh, incidentalThresh int) (*config.GazeConfig, error) {
+	var cfg *config.GazeConfig
+	var err error
 	if path == "" {
-		cwd, err := os.Getwd()
-		if err != nil {
-			return config.DefaultConfig(), nil
-		}
-		path = filepath.Join(cwd, ".gaze.yaml")
+		cfg, path, err = config.Discover(startDir)
+	} else {
+		cfg, err = config.Load(path)
 	}
-	cfg, err := config.Load(path)
 	if err != nil {
 		return nil, err
 	}
@@ -197,25 +286,230 @@ func loadConfig(path string, contractualThresh, incidentalThresh int) (*config.G
 	return cfg, nil
 }
 
+// firstPattern returns the first package pattern, or "." when there
+// are none.
+func firstPattern(patterns []string) string {
+	if len(patterns) == 0 {
+		return "."
+	}
+	return patterns[0]
+}
+
+// configStartDir returns the directory config discovery starts from
+// for a package argument: the package directory when pkgPath names
+// one on disk (e.g. "./internal/crap" or "./..."), the directory
+// holding it when pkgPath names a .go file, otherwise the current
+// directory.
+func configStartDir(pkgPath string) string {
+	dir := strings.TrimSuffix(pkgPath, "/...")
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "."
+	}
+	if info.IsDir() {
+		return dir
+	}
+	if filepath.Ext(dir) == ".go" {
+		return filepath.Dir(dir)
+	}
+	return "."
+}
+
 // runAnalyze is the extracted, testable body of the analyze command.
 func runAnalyze(p analyzeParams) error {
-	if p.format != "text" && p.format != "json" {
-		return fmt.Errorf("invalid format %q: must be 'text' or 'json'", p.format)
+	if p.format != "text" && p.format != "json" && p.format != "yaml" && p.format != "github" {
+		return fmt.Errorf("invalid format %q: must be 'text', 'json', 'yaml', or 'github'", p.format)
+	}
+	forbidden, err := parseTypes("--fail-on-type", p.failOnTypes)
+	if err != nil {
+		return err
+	}
+	if len(forbidden) > 0 && p.interactive {
+		return fmt.Errorf("--fail-on-type cannot be combined with --interactive")
+	}
+	if p.quiet && (p.format != "text" || p.interactive) {
+		return fmt.Errorf("--quiet requires --format=text and cannot be combined with --interactive")
+	}
+	if p.summary && (p.format == "yaml" || p.format == "github" || p.interactive || p.stream) {
+		return fmt.Errorf("--summary requires --format=text or json and cannot be combined with --interactive or --stream")
+	}
+	if p.jsonCompact && (p.format != "json" || p.summary || p.listFunctions) {
+		return fmt.Errorf("--json-compact requires --format=json and cannot be combined with --summary or --list-functions")
+	}
+	if p.deferTraps && (p.stream || p.interactive || p.summary) {
+		return fmt.Errorf("--defer-traps cannot be combined with --stream, --interactive, or --summary")
+	}
+	if p.includeTests && (p.classify || p.verbose || p.stream) {
+		return fmt.Errorf("--include-tests cannot be combined with --classify, --verbose, or --stream")
+	}
+	if p.listFunctions && (p.format == "yaml" || p.format == "github" || p.interactive || p.classify || p.verbose || p.stream) {
+		return fmt.Errorf("--list-functions requires --format=text or json and cannot be combined with --interactive, --classify, --verbose, or --stream")
+	}
+	if p.depth < 0 {
+		return fmt.Errorf("--depth=%d is invalid: must be 0 or greater", p.depth)
+	}
+	if p.timeout < 0 {
+		return fmt.Errorf("--timeout=%s is invalid: must be 0 (no limit) or greater", p.timeout)
+	}
+	disabled, err := analysis.SelectAnalyzers(p.enable, p.disable)
+	if err != nil {
+		return fmt.Errorf("--enable/--disable: %w (see --list-analyzers)", err)
+	}
+	for _, pattern := range p.exclude {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("--exclude=%q is invalid: %w", pattern, err)
+		}
+	}
+	// An empty color (struct literals in tests) means auto.
+	colorMode := report.ColorAuto
+	if p.color != "" {
+		if colorMode, err = report.ParseColorMode(p.color); err != nil {
+			return fmt.Errorf("--color: %w", err)
+		}
+	}
+	// Likewise an empty sort means location.
+	sortOrder := report.SortLocation
+	if p.sort != "" {
+		if sortOrder, err = report.ParseSortOrder(p.sort); err != nil {
+			return fmt.Errorf("--sort: %w", err)
+		}
+	}
+	// Likewise an empty group-by means function.
+	groupBy := report.GroupFunction
+	if p.groupBy != "" {
+		if groupBy, err = report.ParseGroupBy(p.groupBy); err != nil {
+			return fmt.Errorf("--group-by: %w", err)
+		}
+	}
+	if groupBy != report.GroupFunction && (p.format != "text" || p.interactive || p.summary || p.deferTraps || p.verbose) {
+		return fmt.Errorf("--group-by=%s requires --format=text and cannot be combined with --interactive, --summary, --defer-traps, or --verbose", groupBy)
+	}
+	// Likewise an empty locations means string.
+	locations := report.LocationString
+	if p.locations != "" {
+		if locations, err = report.ParseLocationFormat(p.locations); err != nil {
+			return fmt.Errorf("--locations: %w", err)
+		}
+	}
+	if locations != report.LocationString && p.format != "json" {
+		return fmt.Errorf("--locations=%s requires --format=json", locations)
+	}
+	if sortOrder == report.SortConfidence && !p.classify && !p.verbose {
+		return fmt.Errorf("--sort=confidence requires --classify")
+	}
+	if sortOrder != report.SortLocation && p.stream {
+		return fmt.Errorf("--sort=%s cannot be combined with --stream", sortOrder)
+	}
+	if p.confidenceBelow < 0 || p.confidenceBelow > 100 {
+		return fmt.Errorf("--confidence-below=%d is invalid: must be in [1, 100], or 0 for no limit", p.confidenceBelow)
+	}
+	labels, err := parseLabels(p.labels)
+	if err != nil {
+		return err
+	}
+	if (p.confidenceBelow > 0 || len(labels) > 0) && !p.classify && !p.verbose {
+		return fmt.Errorf("--confidence-below and --label require --classify")
+	}
+	if p.testCallers && !p.classify && !p.verbose {
+		return fmt.Errorf("--test-callers requires --classify")
+	}
+	minTier, err := parseTier(p.minTier)
+	if err != nil {
+		return err
+	}
+	types, err := parseTypes("--type", p.types)
+	if err != nil {
+		return err
+	}
+	if (minTier != "" || len(types) > 0) && p.stream {
+		return fmt.Errorf("--min-tier and --type cannot be combined with --stream")
+	}
+	if p.watch {
+		if p.interactive {
+			return fmt.Errorf("--watch cannot be combined with --interactive")
+		}
+		return runAnalyzeWatch(p)
 	}
 
 	opts := analysis.Options{
 		IncludeUnexported: p.includeUnexported,
+		IncludeTests:      p.includeTests,
 		FunctionFilter:    p.function,
 		Version:           version,
+		CacheDir:          p.cacheDir,
+		Depth:             p.depth,
+		Exclude:           p.exclude,
+		IgnoreGenerated:   true,
+		Disable:           disabled,
+		Build:             loader.Build{Tags: p.tags, GOOS: p.goos, GOARCH: p.goarch},
+	}
+	if p.since != "" {
+		changed, err := gitdiff.Changed(".", p.since)
+		if err != nil {
+			return fmt.Errorf("--since: %w", err)
+		}
+		opts.ChangedLines = changed
+	}
+
+	ctx := context.Background()
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	if p.listFunctions {
+		targets, err := analysis.LoadFunctions(ctx, p.pkgPath, opts)
+		if err != nil {
+			return fmt.Errorf("loading %s: %w", p.pkgPath, err)
+		}
+		return writeFunctions(p.stdout, targets, p.format)
+	}
+
+	if p.stream {
+		return runAnalyzeStream(ctx, p, opts, forbidden, sortOrder, locations)
+	}
+
+	// --verbose implies --classify.
+	if p.verbose {
+		p.classify = true
+	}
+
+	// Classification needs every package in the module, so when it
+	// is requested the target is served from that same module load
+	// instead of being type-checked a second time.
+	var session *loader.Session
+	if p.classify {
+		session = loader.NewSessionBuild(ctx, moduleDir(), opts.Build)
 	}
 
 	logger.Info("analyzing package", "pkg", p.pkgPath)
-	results, err := analysis.LoadAndAnalyze(p.pkgPath, opts)
+	var results []taxonomy.AnalysisResult
+	var targets []*packages.Package
+	if session != nil {
+		results, targets, err = analyzeSession(ctx, session, p.pkgPath, opts)
+		if err != nil && len(targets) == 0 {
+			return timeoutError(ctx, p.timeout, err)
+		}
+	} else {
+		results, err = analysis.LoadAndAnalyzeContext(ctx, p.pkgPath, opts)
+	}
+	// A timeout during analysis still reports the functions that
+	// completed; the error is returned once they are written.
+	var partial error
 	if err != nil {
-		return err
+		if ctx.Err() == nil || len(results) == 0 {
+			return timeoutError(ctx, p.timeout, err)
+		}
+		partial = timeoutError(ctx, p.timeout, err)
+		logger.Warn("analysis timed out, reporting partial results", "functions", len(results))
 	}
 
 	if len(results) == 0 {
+		if p.since != "" {
+			logger.Info("no functions changed", "since", p.since)
+			return nil
+		}
 		if p.function != "" {
 			return fmt.Errorf("function %q not found in package %q", p.function, p.pkgPath)
 		}
@@ -225,11 +519,6 @@ func runAnalyze(p analyzeParams) error {
 
 	logger.Info("analysis complete", "functions", len(results))
 
-	// --verbose implies --classify.
-	if p.verbose {
-		p.classify = true
-	}
-
 	// Run mechanical classification if requested.
 	if p.classify {
 		// Normalize zero to -1 (not set). The flag default is -1 but
@@ -243,57 +532,371 @@ func runAnalyze(p analyzeParams) error {
 		if incidentalThresh == 0 {
 			incidentalThresh = -1
 		}
-		cfg, cfgErr := loadConfig(p.configPath, contractualThresh, incidentalThresh)
+		cfg, cfgErr := loadConfig(p.configPath, configStartDir(p.pkgPath), contractualThresh, incidentalThresh)
 		if cfgErr != nil {
 			return fmt.Errorf("loading config: %w", cfgErr)
 		}
-		results, err = runClassify(results, p.pkgPath, cfg, p.verbose)
+		results, err = runClassify(results, targets, session, cfg, p.verbose, p.testCallers)
 		if err != nil {
 			return fmt.Errorf("classification: %w", err)
 		}
 	}
 
+	// --fail-on-type gates on every effect, not just the ones the
+	// classification filters leave in the report.
+	var violations []effectViolation
+	for _, r := range results {
+		violations = append(violations, forbiddenEffects(r, forbidden)...)
+	}
+	results = filterEffects(results, minTier, types)
+	results = filterClassified(results, p.confidenceBelow, labels)
+
+	if p.deferTraps && p.format == "text" {
+		if err := report.WriteDeferTraps(p.stdout, results, report.TextOptions{
+			Color: colorMode,
+			Sort:  sortOrder,
+		}); err != nil {
+			return err
+		}
+		return errors.Join(partial, checkForbiddenEffects(p.stderr, violations))
+	}
+	if p.deferTraps {
+		results = report.DeferTraps(results)
+	}
+
 	if p.interactive {
-		return runInteractiveAnalyze(results)
+		return errors.Join(partial, runInteractiveAnalyze(results))
 	}
 
 	switch p.format {
 	case "json":
-		return report.WriteJSON(p.stdout, results, version)
+		if p.summary {
+			err = report.WriteJSONSummary(p.stdout, results)
+			break
+		}
+		err = report.WriteJSONOptions(p.stdout, results, report.JSONOptions{
+			Version:         version,
+			LegacySentinels: p.legacySentinels,
+			Sort:            sortOrder,
+			Locations:       locations,
+			Compact:         p.jsonCompact,
+		})
+	case "yaml":
+		err = report.WriteYAML(p.stdout, report.SortResults(results, sortOrder), version)
+	case "github":
+		err = report.WriteGitHubActions(p.stdout, report.SortResults(results, sortOrder))
 	default:
 		textOpts := report.TextOptions{
 			Classify: p.classify,
 			Verbose:  p.verbose,
+			Quiet:    p.quiet,
+			Color:    colorMode,
+			Sort:     sortOrder,
+			GroupBy:  groupBy,
+		}
+		if p.summary {
+			err = report.WriteSummaryOptions(p.stdout, results, textOpts)
+		} else {
+			err = report.WriteTextOptions(p.stdout, results, textOpts)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return errors.Join(partial, checkForbiddenEffects(p.stderr, violations))
+}
+
+// timeoutError returns err, prefixed with the --timeout that expired
+// when ctx is done.
+func timeoutError(ctx context.Context, timeout time.Duration, err error) error {
+	if ctx.Err() == nil {
+		return err
+	}
+	return fmt.Errorf("--timeout=%s exceeded: %w", timeout, err)
+}
+
+// effectViolation is a side effect whose type was forbidden with
+// --fail-on-type.
+type effectViolation struct {
+	function   string
+	effectType taxonomy.SideEffectType
+	location   string
+}
+
+// parseTypes validates the side effect types given to flag against
+// the taxonomy and returns them as a set.
+func parseTypes(flag string, values []string) (map[taxonomy.SideEffectType]bool, error) {
+	types := make(map[taxonomy.SideEffectType]bool, len(values))
+	for _, v := range values {
+		t := taxonomy.SideEffectType(v)
+		if !taxonomy.IsKnownType(t) {
+			return nil, fmt.Errorf("invalid %s %q: not a known side effect type", flag, v)
 		}
-		return report.WriteTextOptions(p.stdout, results, textOpts)
+		types[t] = true
+	}
+	return types, nil
+}
+
+// parseTier validates a --min-tier value. An empty value means no
+// tier filter.
+func parseTier(value string) (taxonomy.Tier, error) {
+	if value == "" {
+		return "", nil
+	}
+	for _, t := range taxonomy.Tiers() {
+		if taxonomy.Tier(value) == t {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("invalid --min-tier %q: must be P0, P1, P2, P3, or P4", value)
+}
+
+// forbiddenEffects returns the side effects of r whose type is in
+// forbidden.
+func forbiddenEffects(r taxonomy.AnalysisResult, forbidden map[taxonomy.SideEffectType]bool) []effectViolation {
+	var out []effectViolation
+	for _, e := range r.SideEffects {
+		if forbidden[e.Type] {
+			out = append(out, effectViolation{
+				function:   r.Target.QualifiedName(),
+				effectType: e.Type,
+				location:   e.Location,
+			})
+		}
+	}
+	return out
+}
+
+// parseLabels validates --label values and returns them as a set.
+func parseLabels(values []string) (map[taxonomy.ClassificationLabel]bool, error) {
+	labels := make(map[taxonomy.ClassificationLabel]bool, len(values))
+	for _, v := range values {
+		l := taxonomy.ClassificationLabel(v)
+		switch l {
+		case taxonomy.Contractual, taxonomy.Incidental, taxonomy.Ambiguous:
+			labels[l] = true
+		default:
+			return nil, fmt.Errorf("invalid --label %q: must be 'contractual', 'incidental', or 'ambiguous'", v)
+		}
+	}
+	return labels, nil
+}
+
+// filterEffects keeps only the side effects at minTier or a more
+// severe tier (when minTier is set) whose type is in types (when it
+// is non-empty). Functions left without effects are dropped. With
+// neither filter set, results are returned unchanged.
+func filterEffects(
+	results []taxonomy.AnalysisResult,
+	minTier taxonomy.Tier,
+	types map[taxonomy.SideEffectType]bool,
+) []taxonomy.AnalysisResult {
+	if minTier == "" && len(types) == 0 {
+		return results
+	}
+	var out []taxonomy.AnalysisResult
+	for _, r := range results {
+		var kept []taxonomy.SideEffect
+		for _, e := range r.SideEffects {
+			// Tiers are named P0 (most severe) through P4, so they
+			// order as strings.
+			if minTier != "" && e.Tier > minTier {
+				continue
+			}
+			if len(types) > 0 && !types[e.Type] {
+				continue
+			}
+			kept = append(kept, e)
+		}
+		if len(kept) > 0 {
+			r.SideEffects = kept
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// filterClassified keeps only the classified side effects whose
+// confidence is below confidenceBelow (when it is non-zero) and
+// whose label is in labels (when it is non-empty). Functions left
+// without effects are dropped. With neither filter set, results are
+// returned unchanged.
+func filterClassified(
+	results []taxonomy.AnalysisResult,
+	confidenceBelow int,
+	labels map[taxonomy.ClassificationLabel]bool,
+) []taxonomy.AnalysisResult {
+	if confidenceBelow == 0 && len(labels) == 0 {
+		return results
+	}
+	var out []taxonomy.AnalysisResult
+	for _, r := range results {
+		var kept []taxonomy.SideEffect
+		for _, e := range r.SideEffects {
+			c := e.Classification
+			if c == nil {
+				continue
+			}
+			if confidenceBelow > 0 && c.Confidence >= confidenceBelow {
+				continue
+			}
+			if len(labels) > 0 && !labels[c.Label] {
+				continue
+			}
+			kept = append(kept, e)
+		}
+		if len(kept) > 0 {
+			r.SideEffects = kept
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// checkForbiddenEffects prints each violation to w and returns an
+// error if there are any, mirroring how crap enforces thresholds.
+func checkForbiddenEffects(w io.Writer, violations []effectViolation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	for _, v := range violations {
+		_, _ = fmt.Fprintf(w, "forbidden side effect %s in %s (%s)\n",
+			v.effectType, v.function, v.location)
 	}
+	return &gateError{fmt.Errorf("found %d forbidden side effect(s)", len(violations))}
+}
+
+// runAnalyzeStream implements "gaze analyze --stream": results are
+// written as JSON one at a time as analysis produces them, so peak
+// memory does not grow with the number of functions. Streaming is
+// only available for plain JSON output; classification and the TUI
+// need the full result set.
+func runAnalyzeStream(
+	ctx context.Context,
+	p analyzeParams,
+	opts analysis.Options,
+	forbidden map[taxonomy.SideEffectType]bool,
+	sortOrder report.SortOrder,
+	locations report.LocationFormat,
+) error {
+	if p.format != "json" {
+		return fmt.Errorf("--stream requires --format=json")
+	}
+	if p.interactive || p.classify || p.verbose {
+		return fmt.Errorf("--stream cannot be combined with --interactive, --classify, or --verbose")
+	}
+
+	logger.Info("analyzing package", "pkg", p.pkgPath)
+	loaded, err := loader.LoadAll(p.pkgPath, loader.Options{Context: ctx, Build: opts.Build})
+	if err != nil {
+		return timeoutError(ctx, p.timeout, err)
+	}
+
+	// Packages matched by a pattern such as ./... are streamed one
+	// after another into a single document.
+	results := make(chan taxonomy.AnalysisResult)
+	go func() {
+		defer close(results)
+		for _, l := range loaded {
+			for r := range analysis.AnalyzeStreamContext(ctx, l.Pkg, opts) {
+				results <- r
+			}
+		}
+	}()
+
+	// Count results and collect forbidden effects as they pass
+	// through so both can be reported once the stream is closed.
+	counted := make(chan taxonomy.AnalysisResult)
+	count := 0
+	var violations []effectViolation
+	go func() {
+		defer close(counted)
+		for r := range results {
+			count++
+			violations = append(violations, forbiddenEffects(r, forbidden)...)
+			counted <- r
+		}
+	}()
+
+	jsonOpts := report.JSONOptions{
+		Version:         version,
+		LegacySentinels: p.legacySentinels,
+		Sort:            sortOrder,
+		Locations:       locations,
+		Compact:         p.jsonCompact,
+	}
+	if err := report.StreamJSONOptions(p.stdout, counted, jsonOpts); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return errors.Join(
+			timeoutError(ctx, p.timeout, fmt.Errorf("results are partial (%d function(s) written): %w", count, err)),
+			checkForbiddenEffects(p.stderr, violations),
+		)
+	}
+	if count == 0 && p.function != "" && p.since == "" {
+		return fmt.Errorf("function %q not found in package %q", p.function, p.pkgPath)
+	}
+
+	logger.Info("analysis complete", "functions", count)
+	return checkForbiddenEffects(p.stderr, violations)
+}
+
+// analyzeSession loads every package pattern matches from session,
+// with its test files when opts.IncludeTests is set, and analyzes
+// each in import path order, as analysis.LoadAndAnalyzeContext does.
+// It returns the results, the analyzed packages (each package and,
+// with tests, its external test package), and the first error; on
+// an analysis error the results so far are returned with it.
+func analyzeSession(
+	ctx context.Context,
+	session *loader.Session,
+	pattern string,
+	opts analysis.Options,
+) ([]taxonomy.AnalysisResult, []*packages.Package, error) {
+	loaded, err := session.LoadAll(pattern, loader.Options{Tests: opts.IncludeTests})
+	if err != nil {
+		return nil, nil, err
+	}
+	var results []taxonomy.AnalysisResult
+	var pkgs []*packages.Package
+	for _, result := range loaded {
+		for _, pkg := range []*packages.Package{result.Pkg, result.XTest} {
+			if pkg == nil {
+				continue
+			}
+			pkgs = append(pkgs, pkg)
+			pkgResults, err := analysis.AnalyzeContext(ctx, pkg, opts)
+			results = append(results, pkgResults...)
+			if err != nil {
+				return results, pkgs, err
+			}
+		}
+	}
+	return results, pkgs, nil
 }
 
 // runClassify runs the mechanical classification pipeline on
-// analysis results and returns classified results. It adds a
-// metadata warning noting that document-enhanced classification
-// is not applied (the gaze-reporter agent handles that in full mode).
+// analysis results and returns classified results. targets are the
+// packages the results were produced from; each result is classified
+// against the target whose import path it carries. The module
+// packages used for caller and interface analysis come from session,
+// so they are loaded at most once per command. It adds a metadata warning noting
+// that document-enhanced classification is not applied (the
+// gaze-reporter agent handles that in full mode). When testCallers
+// is true, the module is additionally loaded with test files so the
+// test caller signal can contribute.
 func runClassify(
 	results []taxonomy.AnalysisResult,
-	pkgPath string,
+	targets []*packages.Package,
+	session *loader.Session,
 	cfg *config.GazeConfig,
 	verbose bool,
+	testCallers bool,
 ) ([]taxonomy.AnalysisResult, error) {
-	// Load the target package for AST access.
-	targetResult, err := loader.Load(pkgPath)
-	if err != nil {
-		return nil, fmt.Errorf("loading target package: %w", err)
-	}
-
-	// Load the module for caller/interface analysis. Use the
-	// directory containing the target package if possible.
 	logger.Info("loading module packages for classification")
-	cwd, err := os.Getwd()
-	if err != nil {
-		logger.Debug("could not determine working directory for module load", "err", err)
-		cwd = ""
-	}
-	modResult, modErr := loader.LoadModule(cwd)
+	cwd := moduleDir()
+	modResult, modErr := session.Module()
 	var modPkgs []*packages.Package
 	if modErr != nil {
 		// Non-fatal: module loading failure means caller analysis
@@ -303,14 +906,43 @@ func runClassify(
 		modPkgs = modResult.Packages
 	}
 
-	clOpts := classify.Options{
-		Config:         cfg,
-		ModulePackages: modPkgs,
-		TargetPkg:      targetResult.Pkg,
-		Verbose:        verbose,
+	var testPkgs []*packages.Package
+	if testCallers {
+		logger.Info("loading module test packages for test caller signal")
+		testResult, testErr := loader.LoadModuleWithTests(cwd)
+		if testErr != nil {
+			// Non-fatal: the test caller signal is simply omitted.
+			logger.Warn("module test loading failed; test caller signal skipped", "err", testErr)
+		} else {
+			testPkgs = testResult.Packages
+		}
+	}
+
+	byPath := make(map[string]*packages.Package, len(targets))
+	for _, t := range targets {
+		byPath[t.PkgPath] = t
 	}
 
-	classified := classify.Classify(results, clOpts)
+	// Results come grouped by package; classify each group against
+	// its own package.
+	var classified []taxonomy.AnalysisResult
+	for start := 0; start < len(results); {
+		pkgPath := results[start].Target.Package
+		end := start + 1
+		for end < len(results) && results[end].Target.Package == pkgPath {
+			end++
+		}
+		target := byPath[pkgPath]
+		classified = append(classified, classify.Classify(results[start:end], classify.Options{
+			Config:             cfg,
+			ModulePackages:     modPkgs,
+			ModuleTestPackages: testPkgs,
+			TargetPkg:          target,
+			ModuleRoot:         targetModuleRoot(target, cwd),
+			Verbose:            verbose,
+		})...)
+		start = end
+	}
 
 	// Add a warning to each result noting mechanical-only mode.
 	for i := range classified {
@@ -324,17 +956,70 @@ func runClassify(
 	return classified, nil
 }
 
+// targetModuleRoot returns the root of the module containing target,
+// whose README and docs feed the documentation signal, or fallback
+// when go/packages reports no module (e.g. GOPATH mode). The target
+// may live in a different module than the current directory.
+func targetModuleRoot(target *packages.Package, fallback string) string {
+	if target != nil && target.Module != nil && target.Module.Dir != "" {
+		return target.Module.Dir
+	}
+	return fallback
+}
+
+// moduleDir returns the directory module-wide loads start from:
+// the current directory, or "" (which go/packages treats the same
+// way) if it cannot be determined.
+func moduleDir() string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		logger.Debug("could not determine working directory for module load", "err", err)
+		return ""
+	}
+	return cwd
+}
+
 func newAnalyzeCmd() *cobra.Command {
 	var (
 		function          string
 		format            string
 		includeUnexported bool
+		includeTests      bool
 		interactive       bool
 		classifyFlag      bool
 		verboseFlag       bool
 		configPath        string
 		contractualThresh int
 		incidentalThresh  int
+		testCallers       bool
+		cacheDir          string
+		stream            bool
+		legacySentinels   bool
+		failOnTypes       []string
+		quiet             bool
+		color             string
+		summary           bool
+		since             string
+		depth             int
+		exclude           []string
+		sortFlag          string
+		groupBy           string
+		confidenceBelow   int
+		labels            []string
+		minTier           string
+		types             []string
+		locations         string
+		deferTraps        bool
+		timeout           time.Duration
+		enable            []string
+		disable           []string
+		listAnalyzers     bool
+		listFunctions     bool
+		tags              []string
+		goos              string
+		goarch            string
+		jsonCompact       bool
+		watch             bool
 	)
 
 	cmd := &cobra.Command{
@@ -343,33 +1028,88 @@ func newAnalyzeCmd() *cobra.Command {
 		Long: `Analyze a Go package (or specific function) and report all
 observable side effects each function produces.
 
+The package may be an import path, a pattern such as ./..., or a
+directory or .go file path, relative or absolute; a file selects the
+package that contains it.
+
 Use --classify to attach contractual classification (mechanical signals).
-Use /gaze in OpenCode (full mode) for document-enhanced classification.`,
-		Args: cobra.ExactArgs(1),
-		RunE: func(_ *cobra.Command, args []string) error {
+Use /gaze in OpenCode (full mode) for document-enhanced classification.
+
+Functions in generated files (those with a "// Code generated ... DO NOT
+EDIT." header) are skipped, as are files matching an --exclude glob.
+Functions in _test.go files are skipped unless --include-tests is set.
+
+Use --enable or --disable to choose which analyzers run; --list-analyzers
+prints their names. --list-functions prints the functions the filters
+select without analyzing them.
+
+Files are selected for the host platform by default. Use --tags,
+--goos, and --goarch to analyze files behind build constraints, such
+as //go:build linux files on a Mac.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if listAnalyzers {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if listAnalyzers {
+				return writeAnalyzers(cmd.OutOrStdout())
+			}
 			return runAnalyze(analyzeParams{
 				pkgPath:           args[0],
 				format:            format,
 				function:          function,
 				includeUnexported: includeUnexported,
+				includeTests:      includeTests,
 				interactive:       interactive,
 				classify:          classifyFlag,
 				verbose:           verboseFlag,
 				configPath:        configPath,
 				contractualThresh: contractualThresh,
 				incidentalThresh:  incidentalThresh,
-				stdout:            os.Stdout,
-				stderr:            os.Stderr,
+				testCallers:       testCallers,
+				cacheDir:          cacheDir,
+				stream:            stream,
+				legacySentinels:   legacySentinels,
+				failOnTypes:       failOnTypes,
+				quiet:             quiet,
+				color:             color,
+				summary:           summary,
+				since:             since,
+				depth:             depth,
+				exclude:           exclude,
+				sort:              sortFlag,
+				groupBy:           groupBy,
+				confidenceBelow:   confidenceBelow,
+				labels:            labels,
+				minTier:           minTier,
+				types:             types,
+				locations:         locations,
+				deferTraps:        deferTraps,
+				timeout:           timeout,
+				enable:            enable,
+				disable:           disable,
+				listFunctions:     listFunctions,
+				tags:              tags,
+				goos:              goos,
+				goarch:            goarch,
+				jsonCompact:       jsonCompact,
+				watch:             watch,
+				stdout:            cmd.OutOrStdout(),
+				stderr:            cmd.ErrOrStderr(),
 			})
 		},
 	}
 
 	cmd.Flags().StringVarP(&function, "function", "f", "",
-		"analyze a specific function (default: all exported)")
+		"analyze a specific function, or a method as Type.Method or (*Type).Method (default: all exported)")
 	cmd.Flags().StringVar(&format, "format", "text",
-		"output format: text or json")
+		"output format: text, json, yaml, or github (GitHub Actions annotations)")
 	cmd.Flags().BoolVar(&includeUnexported, "include-unexported", false,
 		"include unexported functions")
+	cmd.Flags().BoolVar(&includeTests, "include-tests", false,
+		"also analyze functions declared in _test.go files, marked as test functions in the output")
 	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false,
 		"launch interactive TUI for browsing results")
 	cmd.Flags().BoolVar(&classifyFlag, "classify", false,
@@ -377,15 +1117,113 @@ Use /gaze in OpenCode (full mode) for document-enhanced classification.`,
 	cmd.Flags().BoolVarP(&verboseFlag, "verbose", "v", false,
 		"print full signal breakdown (implies --classify)")
 	cmd.Flags().StringVar(&configPath, "config", "",
-		"path to .gaze.yaml config file (default: search CWD)")
+		"path to .gaze.yaml config file (default: discover from the package directory up to the module root)")
 	cmd.Flags().IntVar(&contractualThresh, "contractual-threshold", -1,
 		"override contractual confidence threshold (default: from config or 80)")
 	cmd.Flags().IntVar(&incidentalThresh, "incidental-threshold", -1,
 		"override incidental confidence threshold (default: from config or 50)")
+	cmd.Flags().BoolVar(&testCallers, "test-callers", false,
+		"load _test.go files and boost effects referenced by existing tests (requires --classify)")
+	cmd.Flags().StringVar(&cacheDir, "cache-dir", "",
+		"reuse analysis results for unchanged packages from this directory")
+	cmd.Flags().BoolVar(&stream, "stream", false,
+		"write JSON results as they are produced instead of buffering (requires --format=json)")
+	cmd.Flags().BoolVar(&legacySentinels, "legacy-sentinels", false,
+		"in JSON output, report sentinel errors as a '<package>' result instead of a top-level sentinels array")
+	cmd.Flags().StringArrayVar(&failOnTypes, "fail-on-type", nil,
+		"exit with code 2 if any function has a side effect of this type (e.g. GlobalMutation); repeatable")
+	cmd.Flags().BoolVarP(&quiet, "quiet", "q", false,
+		"omit functions with no side effects from text output (still counted in the summary)")
+	cmd.Flags().StringVar(&color, "color", "auto",
+		"color text output: auto (only on a terminal; honors NO_COLOR), always, or never")
+	cmd.Flags().BoolVar(&summary, "summary", false,
+		"print one row per function (effect count and highest tier) instead of every effect; with --format=json, print only effect counts by tier and type")
+	cmd.Flags().StringVar(&since, "since", "",
+		"only analyze functions changed since this git ref (e.g. origin/main), including uncommitted changes")
+	cmd.Flags().IntVar(&depth, "depth", 0,
+		"also report side effects of called functions in the module, following calls this many levels deep")
+	cmd.Flags().StringArrayVar(&exclude, "exclude", nil,
+		"skip functions declared in files matching this glob (e.g. '*_mock.go' or 'internal/fixtures/**'); repeatable")
+	cmd.Flags().StringVar(&sortFlag, "sort", "location",
+		"order side effects and functions by: location, tier (P0 first), type, or confidence (requires --classify)")
+	cmd.Flags().StringVar(&groupBy, "group-by", "function",
+		"organize text output by: function, type (each side effect type with every function producing it), or tier")
+	cmd.Flags().IntVar(&confidenceBelow, "confidence-below", 0,
+		"only report classified side effects with confidence below this value, 1-100 (requires --classify)")
+	cmd.Flags().StringArrayVar(&labels, "label", nil,
+		"only report classified side effects with this label: contractual, incidental, or ambiguous (requires --classify); repeatable")
+	cmd.Flags().StringVar(&minTier, "min-tier", "",
+		"only report side effects at this priority tier or a more severe one: P0 (most severe) through P4")
+	cmd.Flags().StringArrayVar(&types, "type", nil,
+		"only report side effects of this type (e.g. GlobalMutation); repeatable")
+	cmd.Flags().StringVar(&locations, "locations", "string",
+		"JSON source position format: string (file:line:col) or structured ({file, line, col} objects)")
+	cmd.Flags().BoolVar(&deferTraps, "defer-traps", false,
+		"only report named returns modified in a defer, with the deferred call that modifies them")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0,
+		"stop analysis after this long (e.g. 2m) and report the functions completed so far, exiting non-zero; 0 means no limit")
+	cmd.Flags().StringArrayVar(&enable, "enable", nil,
+		"run only this analyzer (see --list-analyzers); repeatable")
+	cmd.Flags().StringArrayVar(&disable, "disable", nil,
+		"do not run this analyzer (see --list-analyzers); repeatable")
+	cmd.Flags().BoolVar(&listAnalyzers, "list-analyzers", false,
+		"list the analyzers --enable and --disable accept, with the side effect types each reports, and exit")
+	cmd.Flags().BoolVar(&listFunctions, "list-functions", false,
+		"list the functions that would be analyzed with the current filters, without analyzing them")
+	cmd.Flags().StringSliceVar(&tags, "tags", nil,
+		"build tags to satisfy when selecting files, as with go build -tags (e.g. integration,linux); repeatable")
+	cmd.Flags().StringVar(&goos, "goos", "",
+		"load packages for this target operating system (e.g. linux) instead of the host's")
+	cmd.Flags().StringVar(&goarch, "goarch", "",
+		"load packages for this target architecture (e.g. arm64) instead of the host's")
+	cmd.Flags().BoolVar(&jsonCompact, "json-compact", false,
+		"write newline-delimited JSON, one compact line per function, instead of an indented document (requires --format=json)")
+	cmd.Flags().BoolVar(&watch, "watch", false,
+		"re-run the analysis whenever a .go file in the target package changes, until interrupted")
 
 	return cmd
 }
 
+// writeAnalyzers prints one line per analyzer, in the order they
+// run: its name and the side effect types it reports.
+func writeAnalyzers(w io.Writer) error {
+	for _, a := range analysis.Analyzers() {
+		doc := a.Doc
+		if a.Custom {
+			doc = "(custom detector)"
+		}
+		if _, err := fmt.Fprintf(w, "%-10s %s\n", a.Name, doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFunctions prints the functions --list-functions selected:
+// one line per function with its location and a count in text
+// format, or an array of function targets in JSON format.
+func writeFunctions(w io.Writer, targets []taxonomy.FunctionTarget, format string) error {
+	if format == "json" {
+		if targets == nil {
+			targets = []taxonomy.FunctionTarget{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(targets)
+	}
+	for _, t := range targets {
+		name := t.Package + "." + t.QualifiedName()
+		if t.Test {
+			name += " (test)"
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%s\n", name, t.Location); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d function(s) would be analyzed\n", len(targets))
+	return err
+}
+
 // crapParams holds the parsed flags for the crap command.
 type crapParams struct {
 	patterns        []string
@@ -393,9 +1231,12 @@ type crapParams struct {
 	opts            crap.Options
 	maxCrapload     int
 	maxGazeCrapload int
+	baseline        string
 	moduleDir       string
+	configPath      string
 	aiMapper        string
 	aiMapperModel   string
+	color           string
 	stdout          io.Writer
 	stderr          io.Writer
 
@@ -425,17 +1266,52 @@ validating output or generating client types.`,
 
 // runCrap is the extracted, testable body of the crap command.
 func runCrap(p crapParams) error {
-	if p.format != "text" && p.format != "json" {
-		return fmt.Errorf("invalid format %q: must be 'text' or 'json'", p.format)
+	if p.format != "text" && p.format != "json" && p.format != "yaml" {
+		return fmt.Errorf("invalid format %q: must be 'text', 'json', or 'yaml'", p.format)
+	}
+	// An empty color (struct literals in tests) means auto.
+	colorMode := report.ColorAuto
+	if p.color != "" {
+		var err error
+		if colorMode, err = report.ParseColorMode(p.color); err != nil {
+			return fmt.Errorf("--color: %w", err)
+		}
+	}
+	// Validate before the quality pipeline runs so bad thresholds
+	// fail fast.
+	if err := p.opts.Validate(); err != nil {
+		return err
+	}
+
+	var baseline *crap.Report
+	if p.baseline != "" {
+		if p.maxCrapload > 0 || p.maxGazeCrapload > 0 {
+			return fmt.Errorf("--baseline cannot be combined with --max-crapload or --max-gaze-crapload")
+		}
+		var err error
+		baseline, err = crap.LoadReport(p.baseline)
+		if err != nil {
+			return fmt.Errorf("loading baseline: %w", err)
+		}
+		crap.AssignIDs(baseline, p.moduleDir)
 	}
 
 	// Wire the quality pipeline to provide contract coverage for
 	// GazeCRAP scoring. This is best-effort: if quality analysis
 	// fails for any package, GazeCRAP falls back to unavailable.
-	if p.opts.ContractCoverageFunc == nil {
+	// Complexity-only runs skip it, since it loads test packages.
+	noTests := p.opts.CoverageMode == crap.CoverageNone
+	if p.opts.ContractCoverageFunc == nil && !noTests {
 		var ccFunc func(string, string) (crap.ContractCoverageInfo, bool)
 		var degradedPkgs []string
 
+		// The quality pipeline classifies with the project's
+		// thresholds, so a broken config must not be ignored.
+		cfg, err := loadConfig(p.configPath, configStartDir(firstPattern(p.patterns)), -1, -1)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+
 		if p.coverageFunc != nil {
 			// Test override — use the injected coverage function.
 			ccFunc, degradedPkgs = p.coverageFunc(p.patterns, p.moduleDir, p.stderr)
@@ -450,7 +1326,7 @@ func runCrap(p crapParams) error {
 				}
 			}
 			ccFunc, degradedPkgs = crap.BuildContractCoverageFunc(
-				p.patterns, p.moduleDir, p.stderr, aiMapperFn,
+				p.patterns, p.moduleDir, cfg, p.stderr, aiMapperFn,
 			)
 		}
 
@@ -475,30 +1351,60 @@ func runCrap(p crapParams) error {
 
 	logger.Info("analysis complete", "functions", len(rpt.Scores))
 
+	if baseline != nil {
+		rpt.Diff, err = crap.Diff(baseline, rpt)
+		if err != nil {
+			return fmt.Errorf("comparing against baseline: %w", err)
+		}
+	}
+
 	// FR-015: Warn when GazeCRAP is unavailable. GazeCRAP requires
 	// contract coverage data from `gaze quality`. If no
 	// ContractCoverageFunc was provided, GazeCRAP fields are nil.
-	if rpt.Summary.GazeCRAPload == nil {
+	if rpt.Summary.GazeCRAPload == nil && !noTests {
 		_, _ = fmt.Fprintln(p.stderr,
 			"note: GazeCRAP unavailable — run 'gaze quality' to compute contract coverage")
 	}
 
-	if err := writeCrapReport(p.stdout, p.format, rpt); err != nil {
+	if err := writeCrapReport(p.stdout, p.format, colorMode, rpt); err != nil {
 		return err
 	}
 
+	if rpt.Diff != nil {
+		return checkBaseline(p.stderr, rpt.Diff)
+	}
+
 	printCISummary(p.stderr, rpt, p.maxCrapload, p.maxGazeCrapload)
 
 	return checkCIThresholds(rpt, p.maxCrapload, p.maxGazeCrapload)
 }
 
-// writeCrapReport outputs the CRAP report in the requested format.
-func writeCrapReport(w io.Writer, format string, rpt *crap.Report) error {
+// checkBaseline prints a one-line ratchet summary to w and returns
+// an error if any function regressed against the baseline.
+func checkBaseline(w io.Writer, d *crap.ReportDiff) error {
+	regressions := d.Regressions()
+	status := "PASS"
+	if len(regressions) > 0 {
+		status = "FAIL"
+	}
+	_, _ = fmt.Fprintf(w, "Baseline: %d worsened, %d added above threshold (%s)\n",
+		len(d.Worsened), len(regressions)-len(d.Worsened), status)
+	if len(regressions) > 0 {
+		return &gateError{fmt.Errorf("CRAP regressed against baseline for %d function(s)", len(regressions))}
+	}
+	return nil
+}
+
+// writeCrapReport outputs the CRAP report in the requested format,
+// coloring text output as color selects.
+func writeCrapReport(w io.Writer, format string, color report.ColorMode, rpt *crap.Report) error {
 	switch format {
 	case "json":
 		return crap.WriteJSON(w, rpt)
+	case "yaml":
+		return crap.WriteYAML(w, rpt)
 	default:
-		return crap.WriteText(w, rpt)
+		return crap.WriteTextOptions(w, rpt, crap.TextOptions{Color: color})
 	}
 }
 
@@ -532,13 +1438,13 @@ func printCISummary(w io.Writer, rpt *crap.Report, maxCrapload, maxGazeCrapload
 // checkCIThresholds returns an error if any CI thresholds are exceeded.
 func checkCIThresholds(rpt *crap.Report, maxCrapload, maxGazeCrapload int) error {
 	if maxCrapload > 0 && rpt.Summary.CRAPload > maxCrapload {
-		return fmt.Errorf("CRAPload %d exceeds maximum %d",
-			rpt.Summary.CRAPload, maxCrapload)
+		return &gateError{fmt.Errorf("CRAPload %d exceeds maximum %d",
+			rpt.Summary.CRAPload, maxCrapload)}
 	}
 	if maxGazeCrapload > 0 && rpt.Summary.GazeCRAPload != nil &&
 		*rpt.Summary.GazeCRAPload > maxGazeCrapload {
-		return fmt.Errorf("GazeCRAPload %d exceeds maximum %d",
-			*rpt.Summary.GazeCRAPload, maxGazeCrapload)
+		return &gateError{fmt.Errorf("GazeCRAPload %d exceeds maximum %d",
+			*rpt.Summary.GazeCRAPload, maxGazeCrapload)}
 	}
 	return nil
 }
@@ -546,13 +1452,23 @@ func checkCIThresholds(rpt *crap.Report, maxCrapload, maxGazeCrapload int) error
 func newCrapCmd() *cobra.Command {
 	var (
 		format            string
-		coverProfile      string
+		coverProfiles     []string
 		crapThreshold     float64
 		gazeCrapThreshold float64
 		maxCrapload       int
 		maxGazeCrapload   int
+		baseline          string
 		aiMapper          string
 		aiMapperModel     string
+		exportedOnly      bool
+		coverageMode      string
+		complexityThresh  int
+		coverageThresh    float64
+		noTests           bool
+		explainComplexity bool
+		ambiguous         string
+		color             string
+		configPath        string
 	)
 
 	cmd := &cobra.Command{
@@ -566,43 +1482,78 @@ the threshold).
 If no coverage profile is provided, runs 'go test -coverprofile'
 automatically.`,
 		Args: cobra.MinimumNArgs(1),
-		RunE: func(_ *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if noTests {
+				if cmd.Flags().Changed("coverprofile") || cmd.Flags().Changed("coverage-mode") {
+					return fmt.Errorf("--no-tests cannot be combined with --coverprofile or --coverage-mode")
+				}
+				coverageMode = string(crap.CoverageNone)
+			}
 			moduleDir, err := os.Getwd()
 			if err != nil {
 				return fmt.Errorf("getting working directory: %w", err)
 			}
 			opts := crap.DefaultOptions()
-			opts.CoverProfile = coverProfile
+			opts.CoverProfiles = coverProfiles
 			opts.CRAPThreshold = crapThreshold
 			opts.GazeCRAPThreshold = gazeCrapThreshold
-			opts.Stderr = os.Stderr
+			opts.IncludeUnexported = !exportedOnly
+			opts.CoverageMode = crap.CoverageMode(coverageMode)
+			opts.ExplainComplexity = explainComplexity
+			opts.AmbiguousPolicy = crap.AmbiguousPolicy(ambiguous)
+			opts.ComplexityThreshold = complexityThresh
+			opts.CoverageThreshold = coverageThresh
+			opts.Stderr = cmd.ErrOrStderr()
 			return runCrap(crapParams{
 				patterns:        args,
 				format:          format,
 				opts:            opts,
 				maxCrapload:     maxCrapload,
 				maxGazeCrapload: maxGazeCrapload,
+				baseline:        baseline,
 				moduleDir:       moduleDir,
+				configPath:      configPath,
 				aiMapper:        aiMapper,
 				aiMapperModel:   aiMapperModel,
-				stdout:          os.Stdout,
-				stderr:          os.Stderr,
+				color:           color,
+				stdout:          cmd.OutOrStdout(),
+				stderr:          cmd.ErrOrStderr(),
 			})
 		},
 	}
 
 	cmd.Flags().StringVar(&format, "format", "text",
-		"output format: text or json")
-	cmd.Flags().StringVar(&coverProfile, "coverprofile", "",
-		"path to coverage profile (default: generate via go test)")
+		"output format: text, json, or yaml")
+	cmd.Flags().StringVar(&color, "color", "auto",
+		"color text output: auto (only on a terminal; honors NO_COLOR), always, or never")
+	cmd.Flags().StringArrayVar(&coverProfiles, "coverprofile", nil,
+		"path to coverage profile; repeat to merge several (default: generate via go test)")
 	cmd.Flags().Float64Var(&crapThreshold, "crap-threshold", 15,
 		"CRAP score threshold for flagging functions")
 	cmd.Flags().Float64Var(&gazeCrapThreshold, "gaze-crap-threshold", 15,
 		"GazeCRAP score threshold (used when contract coverage available)")
+	cmd.Flags().IntVar(&complexityThresh, "complexity-threshold", 0,
+		"cyclomatic complexity at or above which a function is complex in the quadrant breakdown (0 = use --crap-threshold)")
+	cmd.Flags().Float64Var(&coverageThresh, "coverage-threshold", 0,
+		"contract coverage percentage (0-100) below which a function is underspecified in the quadrant breakdown (0 = use --gaze-crap-threshold)")
 	cmd.Flags().IntVar(&maxCrapload, "max-crapload", 0,
 		"fail if CRAPload exceeds this (0 = no limit)")
 	cmd.Flags().IntVar(&maxGazeCrapload, "max-gaze-crapload", 0,
 		"fail if GazeCRAPload exceeds this (0 = no limit)")
+	cmd.Flags().StringVar(&baseline, "baseline", "",
+		"prior JSON report; fail only if a function's CRAP increased or a new function is above threshold")
+	cmd.Flags().StringVar(&coverageMode, "coverage-mode", "line",
+		"coverage fed into CRAP: line, or branch (share of coverage blocks executed)")
+	cmd.Flags().BoolVar(&noTests, "no-tests", false,
+		"skip running tests; rank by complexity only, with coverage shown as n/a")
+	cmd.Flags().BoolVar(&explainComplexity, "explain-complexity", false,
+		"break each function's complexity down by construct (if/for/case/&&/||...)")
+	cmd.Flags().StringVar(&ambiguous, "ambiguous", string(crap.AmbiguousIgnore),
+		"how GazeCRAP's contract coverage counts ambiguous effects: contractual (must be asserted), incidental, or ignore")
+	cmd.Flags().StringVar(&configPath, "config", "",
+		"path to .gaze.yaml config file used by GazeCRAP's quality pipeline (default: discover from the package directory up to the module root)")
+	cmd.Flags().BoolVar(&exportedOnly, "exported-only", false,
+		"score only exported functions and methods (excluded from the report and CRAPload)")
 	cmd.Flags().StringVar(&aiMapper, "ai-mapper", "",
 		"AI backend for assertion mapping fallback: claude, gemini, ollama, or opencode")
 	cmd.Flags().StringVar(&aiMapperModel, "ai-mapper-model", "",
@@ -621,7 +1572,7 @@ type docscanParams struct {
 
 // runDocscan is the extracted, testable body of the docscan command.
 func runDocscan(p docscanParams) error {
-	cfg, err := loadConfig(p.configPath, -1, -1)
+	cfg, err := loadConfig(p.configPath, configStartDir(p.pkgPath), -1, -1)
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
@@ -674,7 +1625,7 @@ Priority:
   2 = module root
   3 = other locations`,
 		Args: cobra.MaximumNArgs(1),
-		RunE: func(_ *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			pkgPath := "."
 			if len(args) > 0 {
 				pkgPath = args[0]
@@ -682,14 +1633,102 @@ Priority:
 			return runDocscan(docscanParams{
 				pkgPath:    pkgPath,
 				configPath: configPath,
-				stdout:     os.Stdout,
-				stderr:     os.Stderr,
+				stdout:     cmd.OutOrStdout(),
+				stderr:     cmd.ErrOrStderr(),
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&configPath, "config", "",
+		"path to .gaze.yaml config file (default: discover from the package directory up to the module root)")
+
+	return cmd
+}
+
+// configParams holds the parsed flags for the config command.
+type configParams struct {
+	pkgPath    string
+	configPath string
+	format     string
+	stdout     io.Writer
+}
+
+// runConfig is the extracted, testable body of the config command.
+// It prints the effective configuration: the file's values merged
+// over the defaults, with every fallback resolved.
+func runConfig(p configParams) error {
+	if p.format != "json" && p.format != "yaml" {
+		return fmt.Errorf("invalid format %q: must be 'json' or 'yaml'", p.format)
+	}
+
+	var cfg *config.GazeConfig
+	path := p.configPath
+	var err error
+	if path == "" {
+		cfg, path, err = config.Discover(configStartDir(p.pkgPath))
+	} else {
+		cfg, err = config.Load(path)
+	}
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if path == "" {
+		logger.Info("no config file found, showing defaults")
+	} else {
+		logger.Info("using config", "path", path)
+	}
+
+	effective := cfg.Effective()
+	if p.format == "yaml" {
+		enc := yaml.NewEncoder(p.stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(effective); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+	enc := json.NewEncoder(p.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(effective)
+}
+
+// newConfigCmd creates the "config" subcommand.
+func newConfigCmd() *cobra.Command {
+	var (
+		configPath string
+		format     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "config [package]",
+		Short: "Print the configuration in effect",
+		Long: `Print the configuration Gaze uses: the .gaze.yaml values merged
+over the built-in defaults, with defaulted signal weights, naming
+prefixes, and architecture doc headings filled in. Like "go env",
+this shows what a run would actually see, which helps explain why a
+side effect was classified the way it was.
+
+Without --config, .gaze.yaml is discovered from the package directory
+(default: the current directory) up to the module root.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pkgPath := "."
+			if len(args) > 0 {
+				pkgPath = args[0]
+			}
+			return runConfig(configParams{
+				pkgPath:    pkgPath,
+				configPath: configPath,
+				format:     format,
+				stdout:     cmd.OutOrStdout(),
 			})
 		},
 	}
 
 	cmd.Flags().StringVar(&configPath, "config", "",
-		"path to .gaze.yaml config file (default: search CWD)")
+		"path to .gaze.yaml config file (default: discover from the package directory up to the module root)")
+	cmd.Flags().StringVar(&format, "format", "json",
+		"output format: json or yaml")
 
 	return cmd
 }
@@ -708,6 +1747,7 @@ type qualityParams struct {
 	maxOverSpecification int
 	aiMapper             string
 	aiMapperModel        string
+	jsonCompact          bool
 	stdout               io.Writer
 	stderr               io.Writer
 }
@@ -717,7 +1757,45 @@ func runQuality(p qualityParams) error {
 	if p.format != "text" && p.format != "json" {
 		return fmt.Errorf("invalid format %q: must be 'text' or 'json'", p.format)
 	}
+	if p.jsonCompact && p.format != "json" {
+		return fmt.Errorf("--json-compact requires --format=json")
+	}
+
+	_, reports, summary, err := assessQuality(p)
+	if err != nil || summary == nil {
+		return err
+	}
+
+	// Step 5: Write report.
+	switch p.format {
+	case "json":
+		write := quality.WriteJSON
+		if p.jsonCompact {
+			write = quality.WriteCompactJSON
+		}
+		if err := write(p.stdout, reports, summary); err != nil {
+			return err
+		}
+	default:
+		if err := quality.WriteText(p.stdout, reports, summary); err != nil {
+			return err
+		}
+	}
+
+	// Step 6: Check CI thresholds.
+	return checkQualityThresholds(p, reports, summary)
+}
 
+// assessQuality runs steps 1-4 of the quality pipeline (analyze,
+// classify, load tests, assess) and returns the classified analysis
+// results alongside the quality reports. All return values are nil
+// when the package has no functions to analyze.
+func assessQuality(p qualityParams) (
+	[]taxonomy.AnalysisResult,
+	[]taxonomy.QualityReport,
+	*taxonomy.PackageSummary,
+	error,
+) {
 	// Step 1: Load and analyze the package (Spec 001).
 	opts := analysis.Options{
 		IncludeUnexported: p.includeUnexported,
@@ -733,14 +1811,21 @@ func runQuality(p qualityParams) error {
 		}
 	}
 
+	// Classification loads the whole module; serve the target
+	// package from that load rather than type-checking it twice.
+	session := loader.NewSession(moduleDir())
 	logger.Info("analyzing package", "pkg", p.pkgPath)
-	results, err := analysis.LoadAndAnalyze(p.pkgPath, opts)
+	target, err := session.Load(p.pkgPath)
 	if err != nil {
-		return err
+		return nil, nil, nil, err
+	}
+	results, err := analysis.Analyze(target.Pkg, opts)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 	if len(results) == 0 {
 		logger.Warn("no functions found to analyze")
-		return nil
+		return nil, nil, nil, nil
 	}
 
 	// Step 2: Classify side effects (Spec 002).
@@ -752,57 +1837,44 @@ func runQuality(p qualityParams) error {
 	if incidentalThresh == 0 {
 		incidentalThresh = -1
 	}
-	cfg, cfgErr := loadConfig(p.configPath, contractualThresh, incidentalThresh)
+	cfg, cfgErr := loadConfig(p.configPath, configStartDir(p.pkgPath), contractualThresh, incidentalThresh)
 	if cfgErr != nil {
-		return fmt.Errorf("loading config: %w", cfgErr)
+		return nil, nil, nil, fmt.Errorf("loading config: %w", cfgErr)
 	}
-	results, err = runClassify(results, p.pkgPath, cfg, p.verbose)
+	results, err = runClassify(results, []*packages.Package{target.Pkg}, session, cfg, p.verbose, false)
 	if err != nil {
-		return fmt.Errorf("classification: %w", err)
+		return nil, nil, nil, fmt.Errorf("classification: %w", err)
 	}
 
 	// Step 3: Load the test package with test files.
 	testPkg, err := loadTestPackage(p.pkgPath)
 	if err != nil {
-		return fmt.Errorf("loading test package: %w", err)
+		return nil, nil, nil, fmt.Errorf("loading test package: %w", err)
 	}
 
 	// Step 4: Assess test quality (Spec 003).
 	qualOpts := quality.Options{
-		TargetFunc: p.targetFunc,
-		Verbose:    p.verbose,
-		Version:    version,
-		Stderr:     p.stderr,
+		TargetFunc:       p.targetFunc,
+		Verbose:          p.verbose,
+		Version:          version,
+		Stderr:           p.stderr,
+		AssertionHelpers: cfg.Quality.AssertionHelpers,
 	}
 
 	// Wire AI-assisted assertion mapping when --ai-mapper is set.
 	if p.aiMapper != "" {
 		aiMapperFn, aiErr := buildAIMapperFunc(p.aiMapper, p.aiMapperModel)
 		if aiErr != nil {
-			return aiErr
+			return nil, nil, nil, aiErr
 		}
 		qualOpts.AIMapperFunc = aiMapperFn
 	}
 
 	reports, summary, err := quality.Assess(results, testPkg, qualOpts)
 	if err != nil {
-		return fmt.Errorf("quality assessment: %w", err)
-	}
-
-	// Step 5: Write report.
-	switch p.format {
-	case "json":
-		if err := quality.WriteJSON(p.stdout, reports, summary); err != nil {
-			return err
-		}
-	default:
-		if err := quality.WriteText(p.stdout, reports, summary); err != nil {
-			return err
-		}
+		return nil, nil, nil, fmt.Errorf("quality assessment: %w", err)
 	}
-
-	// Step 6: Check CI thresholds.
-	return checkQualityThresholds(p, reports, summary)
+	return results, reports, summary, nil
 }
 
 // loadTestPackage loads a Go package with test files included.
@@ -937,7 +2009,7 @@ func checkQualityThresholds(
 	// Return all failures so users see every violation at once,
 	// rather than fixing one at a time (Actionable Output principle).
 	if len(failures) > 0 {
-		return errors.New(strings.Join(failures, "\n"))
+		return &gateError{errors.New(strings.Join(failures, "\n"))}
 	}
 
 	return nil
@@ -967,6 +2039,7 @@ func newQualityCmd() *cobra.Command {
 		maxOverSpecification int
 		aiMapper             string
 		aiMapperModel        string
+		jsonCompact          bool
 	)
 
 	cmd := &cobra.Command{
@@ -979,7 +2052,7 @@ Specification Score (assertions on incidental implementation details).
 
 Requires the target package to have existing test files.`,
 		Args: cobra.ExactArgs(1),
-		RunE: func(_ *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			return runQuality(qualityParams{
 				pkgPath:              args[0],
 				format:               format,
@@ -993,8 +2066,9 @@ Requires the target package to have existing test files.`,
 				maxOverSpecification: maxOverSpecification,
 				aiMapper:             aiMapper,
 				aiMapperModel:        aiMapperModel,
-				stdout:               os.Stdout,
-				stderr:               os.Stderr,
+				jsonCompact:          jsonCompact,
+				stdout:               cmd.OutOrStdout(),
+				stderr:               cmd.ErrOrStderr(),
 			})
 		},
 	}
@@ -1008,7 +2082,7 @@ Requires the target package to have existing test files.`,
 	cmd.Flags().BoolVar(&includeUnexported, "include-unexported", false,
 		"include unexported functions")
 	cmd.Flags().StringVar(&configPath, "config", "",
-		"path to .gaze.yaml config file (default: search CWD)")
+		"path to .gaze.yaml config file (default: discover from the package directory up to the module root)")
 	cmd.Flags().IntVar(&contractualThresh, "contractual-threshold", -1,
 		"override contractual confidence threshold (default: from config or 80)")
 	cmd.Flags().IntVar(&incidentalThresh, "incidental-threshold", -1,
@@ -1021,6 +2095,477 @@ Requires the target package to have existing test files.`,
 		"AI backend for assertion mapping fallback: claude, gemini, ollama, or opencode")
 	cmd.Flags().StringVar(&aiMapperModel, "ai-mapper-model", "",
 		"model name for AI mapper (required for ollama)")
+	cmd.Flags().BoolVar(&jsonCompact, "json-compact", false,
+		"write the JSON report on a single line instead of indented (requires --format=json)")
+
+	return cmd
+}
+
+// coverageParams holds the parsed flags for the coverage command.
+type coverageParams struct {
+	pkgPath           string
+	format            string
+	includeUnexported bool
+	configPath        string
+	contractualThresh int
+	incidentalThresh  int
+	stdout            io.Writer
+	stderr            io.Writer
+}
+
+// runCoverage is the extracted, testable body of the coverage command.
+func runCoverage(p coverageParams) error {
+	if p.format != "text" && p.format != "json" {
+		return fmt.Errorf("invalid format %q: must be 'text' or 'json'", p.format)
+	}
+
+	results, reports, summary, err := assessQuality(qualityParams{
+		pkgPath:           p.pkgPath,
+		includeUnexported: p.includeUnexported,
+		configPath:        p.configPath,
+		contractualThresh: p.contractualThresh,
+		incidentalThresh:  p.incidentalThresh,
+		stdout:            p.stdout,
+		stderr:            p.stderr,
+	})
+	if err != nil {
+		return err
+	}
+	// Without SSA no assertion can be mapped, so every effect would
+	// be reported as unasserted.
+	if summary != nil && summary.SSADegraded {
+		return fmt.Errorf("contract coverage unavailable: SSA construction failed for %s",
+			strings.Join(summary.SSADegradedPackages, ", "))
+	}
+
+	coverage := quality.AggregateFunctionCoverage(results, reports)
+	if p.format == "json" {
+		return quality.WriteCoverageJSON(p.stdout, coverage)
+	}
+	return quality.WriteCoverageText(p.stdout, coverage)
+}
+
+func newCoverageCmd() *cobra.Command {
+	var (
+		format            string
+		includeUnexported bool
+		configPath        string
+		contractualThresh int
+		incidentalThresh  int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "coverage [package]",
+		Short: "Report per-function contract coverage",
+		Long: `Report, for each function in a package, how many of its
+contractual side effects are asserted on by at least one test, and
+list the contractual effects that no test asserts on.
+
+Unlike quality, which reports each test separately, coverage combines
+all tests that target a function. Requires the target package to have
+existing test files.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCoverage(coverageParams{
+				pkgPath:           args[0],
+				format:            format,
+				includeUnexported: includeUnexported,
+				configPath:        configPath,
+				contractualThresh: contractualThresh,
+				incidentalThresh:  incidentalThresh,
+				stdout:            cmd.OutOrStdout(),
+				stderr:            cmd.ErrOrStderr(),
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "text",
+		"output format: text or json")
+	cmd.Flags().BoolVar(&includeUnexported, "include-unexported", false,
+		"include unexported functions")
+	cmd.Flags().StringVar(&configPath, "config", "",
+		"path to .gaze.yaml config file (default: discover from the package directory up to the module root)")
+	cmd.Flags().IntVar(&contractualThresh, "contractual-threshold", -1,
+		"override contractual confidence threshold (default: from config or 80)")
+	cmd.Flags().IntVar(&incidentalThresh, "incidental-threshold", -1,
+		"override incidental confidence threshold (default: from config or 50)")
+
+	return cmd
+}
+
+// graphParams holds the parsed flags for the graph command.
+type graphParams struct {
+	function string
+	pkgPath  string
+	depth    int
+	callees  bool
+	stdout   io.Writer
+}
+
+// runGraph is the extracted, testable body of the graph command.
+func runGraph(p graphParams) error {
+	if p.depth < 1 {
+		return fmt.Errorf("--depth=%d is invalid: must be at least 1", p.depth)
+	}
+
+	session := loader.NewSession(moduleDir())
+	logger.Info("loading module packages for call graph")
+	target, err := session.Load(p.pkgPath)
+	if err != nil {
+		return err
+	}
+	obj := classify.LookupFunction(target.Pkg.Types, p.function)
+	if obj == nil {
+		return fmt.Errorf("function %q not found in package %q", p.function, p.pkgPath)
+	}
+	mod, err := session.Module()
+	if err != nil {
+		return err
+	}
+
+	g := classify.BuildCallGraph(obj, mod.Packages, p.depth, p.callees)
+	return g.WriteDOT(p.stdout)
+}
+
+func newGraphCmd() *cobra.Command {
+	var (
+		depth   int
+		callees bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "graph <function> [package]",
+		Short: "Export the callers of a function as a Graphviz DOT graph",
+		Long: `Emit a Graphviz DOT graph of the functions in the module that
+reference the given function, following callers up to --depth levels.
+These are the references counted by the caller classification signal,
+so the graph shows why an effect has many callers or none.
+
+The function is a package-level function ("Save") or a method
+("Store.Save") in the given package, which defaults to the current
+directory. Render the output with, e.g., "gaze graph Save | dot -Tsvg".`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pkgPath := "."
+			if len(args) == 2 {
+				pkgPath = args[1]
+			}
+			return runGraph(graphParams{
+				function: args[0],
+				pkgPath:  pkgPath,
+				depth:    depth,
+				callees:  callees,
+				stdout:   cmd.OutOrStdout(),
+			})
+		},
+	}
+
+	cmd.Flags().IntVar(&depth, "depth", 2,
+		"number of caller (and callee) levels to follow")
+	cmd.Flags().BoolVar(&callees, "callees", false,
+		"also include the module functions the function references")
+
+	return cmd
+}
+
+// explainParams holds the parsed flags for the explain command.
+type explainParams struct {
+	pkgPath           string
+	function          string
+	effectType        string
+	configPath        string
+	contractualThresh int
+	incidentalThresh  int
+	stdout            io.Writer
+}
+
+// runExplain is the extracted, testable body of the explain command.
+// It analyzes and classifies one function, then prints the scoring
+// arithmetic for each of its side effects of the requested type.
+func runExplain(p explainParams) error {
+	effectType := taxonomy.SideEffectType(p.effectType)
+	if !taxonomy.IsKnownType(effectType) {
+		return fmt.Errorf("invalid effect type %q: not a known side effect type", p.effectType)
+	}
+	// Zero thresholds (struct literals in tests) mean "not set".
+	contractualThresh, incidentalThresh := p.contractualThresh, p.incidentalThresh
+	if contractualThresh == 0 {
+		contractualThresh = -1
+	}
+	if incidentalThresh == 0 {
+		incidentalThresh = -1
+	}
+	cfg, err := loadConfig(p.configPath, configStartDir(p.pkgPath), contractualThresh, incidentalThresh)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	session := loader.NewSession(moduleDir())
+	target, err := session.Load(p.pkgPath)
+	if err != nil {
+		return err
+	}
+	results, err := analysis.Analyze(target.Pkg, analysis.Options{
+		IncludeUnexported: true,
+		FunctionFilter:    p.function,
+		Version:           version,
+	})
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("function %q not found in package %q", p.function, p.pkgPath)
+	}
+	results, err = runClassify(results, []*packages.Package{target.Pkg}, session, cfg, true, false)
+	if err != nil {
+		return fmt.Errorf("classification: %w", err)
+	}
+
+	found := false
+	var others []string
+	for _, r := range results {
+		for _, e := range r.SideEffects {
+			if e.Type != effectType {
+				if !slices.Contains(others, string(e.Type)) {
+					others = append(others, string(e.Type))
+				}
+				continue
+			}
+			if e.Classification == nil {
+				continue
+			}
+			if found {
+				_, _ = fmt.Fprintln(p.stdout)
+			}
+			found = true
+			if _, err := fmt.Fprintf(p.stdout, "%s: %s at %s\n  %s\n",
+				r.Target.QualifiedName(), e.Type, e.Location, e.Description); err != nil {
+				return err
+			}
+			if err := classify.ExplainClassification(e.Type, *e.Classification, cfg).WriteText(p.stdout); err != nil {
+				return err
+			}
+		}
+	}
+	if !found {
+		if len(others) == 0 {
+			return fmt.Errorf("function %q has no %s side effect (it has no side effects)", p.function, effectType)
+		}
+		return fmt.Errorf("function %q has no %s side effect (it has: %s)",
+			p.function, effectType, strings.Join(others, ", "))
+	}
+	return nil
+}
+
+func newExplainCmd() *cobra.Command {
+	var (
+		configPath        string
+		contractualThresh int
+		incidentalThresh  int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "explain <package> <function> <effectType>",
+		Short: "Show the scoring arithmetic behind a side effect's classification",
+		Long: `Classify the side effects of one function and print, for each effect
+of the given type, the arithmetic behind its label: the base
+confidence, the tier boost, every signal weight, the contradiction
+penalty, the clamp to 0-100, and the threshold comparisons that chose
+contractual, incidental, or ambiguous. A configured override is shown
+as the final step.
+
+The function is selected as with "gaze analyze --function": a bare
+name, "Type.Method", or "(*Type).Method". Unexported functions are
+included.`,
+		Example: `  gaze explain ./internal/store Save ReceiverMutation
+  gaze explain ./internal/store "(*Store).Save" ErrorReturn --contractual-threshold=70`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExplain(explainParams{
+				pkgPath:           args[0],
+				function:          args[1],
+				effectType:        args[2],
+				configPath:        configPath,
+				contractualThresh: contractualThresh,
+				incidentalThresh:  incidentalThresh,
+				stdout:            cmd.OutOrStdout(),
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&configPath, "config", "",
+		"path to .gaze.yaml config file (default: discover from the package directory up to the module root)")
+	cmd.Flags().IntVar(&contractualThresh, "contractual-threshold", -1,
+		"override contractual confidence threshold (default: from config or 80)")
+	cmd.Flags().IntVar(&incidentalThresh, "incidental-threshold", -1,
+		"override incidental confidence threshold (default: from config or 50)")
+
+	return cmd
+}
+
+// diffParams holds the parsed flags for the diff command.
+type diffParams struct {
+	// old and current are two analyze JSON files, or a git ref and
+	// a package pattern.
+	old               string
+	current           string
+	format            string
+	match             string
+	includeUnexported bool
+	stdout            io.Writer
+}
+
+// runDiff is the extracted, testable body of the diff command.
+func runDiff(p diffParams) error {
+	if p.format != "text" && p.format != "json" {
+		return fmt.Errorf("invalid format %q: must be 'text' or 'json'", p.format)
+	}
+	files := isFile(p.old) && isFile(p.current)
+	// An empty match means id for files and target for a ref: the
+	// IDs of return values include the file path, which differs
+	// between the worktree and the checkout of the ref.
+	match := report.MatchTarget
+	if files {
+		match = report.MatchID
+	}
+	if p.match != "" {
+		var err error
+		if match, err = report.ParseDiffMatch(p.match); err != nil {
+			return fmt.Errorf("--match: %w", err)
+		}
+	}
+
+	var old, current []taxonomy.AnalysisResult
+	var err error
+	if files {
+		if old, err = readAnalysis(p.old); err != nil {
+			return err
+		}
+		if current, err = readAnalysis(p.current); err != nil {
+			return err
+		}
+	} else {
+		opts := analysis.Options{
+			IncludeUnexported: p.includeUnexported,
+			Version:           version,
+			IgnoreGenerated:   true,
+		}
+		if old, err = analyzeAtRef(p.old, p.current, opts); err != nil {
+			return err
+		}
+		logger.Info("analyzing working tree", "package", p.current)
+		if current, err = analysis.LoadAndAnalyze(p.current, opts); err != nil {
+			return fmt.Errorf("analyzing %s: %w", p.current, err)
+		}
+	}
+
+	d := report.DiffResults(old, current, match)
+	if p.format == "json" {
+		return report.WriteDiffJSON(p.stdout, d)
+	}
+	return report.WriteDiffText(p.stdout, d)
+}
+
+// isFile reports whether path names an existing regular file.
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// readAnalysis reads a gaze analyze --format=json file.
+func readAnalysis(path string) ([]taxonomy.AnalysisResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	results, err := report.ReadJSON(f)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return results, nil
+}
+
+// analyzeAtRef analyzes the packages matching pattern as of the git
+// ref, in a temporary worktree. Positions in the results are
+// rewritten to the corresponding working tree paths.
+func analyzeAtRef(ref, pattern string, opts analysis.Options) ([]taxonomy.AnalysisResult, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	if resolved, err := filepath.EvalSymlinks(cwd); err == nil {
+		cwd = resolved
+	}
+	wt, err := gitdiff.Checkout(cwd, ref)
+	if err != nil {
+		return nil, fmt.Errorf("checking out %s: %w", ref, err)
+	}
+	defer func() {
+		if err := wt.Remove(); err != nil {
+			logger.Warn("could not remove temporary worktree", "path", wt.Root, "err", err)
+		}
+	}()
+
+	logger.Info("analyzing", "ref", ref, "package", pattern)
+	opts.Dir = wt.Path(cwd)
+	results, err := analysis.LoadAndAnalyze(pattern, opts)
+	if err != nil {
+		return nil, fmt.Errorf("analyzing %s at %s: %w", pattern, ref, err)
+	}
+	for i := range results {
+		r := &results[i]
+		r.Target.Location = strings.Replace(r.Target.Location, wt.Root, wt.RepoRoot, 1)
+		for j := range r.SideEffects {
+			e := &r.SideEffects[j]
+			e.Location = strings.Replace(e.Location, wt.Root, wt.RepoRoot, 1)
+			e.EndLocation = strings.Replace(e.EndLocation, wt.Root, wt.RepoRoot, 1)
+		}
+	}
+	return results, nil
+}
+
+func newDiffCmd() *cobra.Command {
+	var (
+		format            string
+		match             string
+		includeUnexported bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff <old.json> <new.json> | diff <ref> <package>",
+		Short: "Compare the side effects of two analyses",
+		Long: `Report the side effects added, removed, or changed between two
+analyses, per function. Either pass two files written by
+"gaze analyze --format=json", or a git ref and a package pattern to
+compare the package as of the ref (checked out in a temporary
+worktree) with the working tree.
+
+With --match=id, effects are matched by their stable ID within each
+function. The IDs of some effect types, such as ReturnValue, include
+the source position, so code that only moved shows as removed and
+added. --match=target matches by function, type, and target instead
+and never reports moved code. The default is id for two files and
+target for a ref and package.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDiff(diffParams{
+				old:               args[0],
+				current:           args[1],
+				format:            format,
+				match:             match,
+				includeUnexported: includeUnexported,
+				stdout:            cmd.OutOrStdout(),
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "text",
+		"output format: text or json")
+	cmd.Flags().StringVar(&match, "match", "",
+		"how to match effects: id (stable ID) or target (function, type, and target, ignoring positions) (default: id for files, target for a ref)")
+	cmd.Flags().BoolVar(&includeUnexported, "include-unexported", false,
+		"include unexported functions when analyzing a ref and package")
 
 	return cmd
 }
@@ -1117,13 +2662,13 @@ CRAPload and the worst offenders by CRAP score. GazeCRAP
 scores are included when contract coverage data is available
 (requires integration with the quality pipeline).`,
 		Args: cobra.NoArgs,
-		RunE: func(_ *cobra.Command, _ []string) error {
+		RunE: func(cmd *cobra.Command, _ []string) error {
 			return runSelfCheck(selfCheckParams{
 				format:          format,
 				maxCrapload:     maxCrapload,
 				maxGazeCrapload: maxGazeCrapload,
-				stdout:          os.Stdout,
-				stderr:          os.Stderr,
+				stdout:          cmd.OutOrStdout(),
+				stderr:          cmd.ErrOrStderr(),
 			})
 		},
 	}
@@ -1163,7 +2708,8 @@ type reportParams struct {
 // In text mode it validates the --ai flag, resolves the adapter, loads the
 // system prompt, and calls the 4-step analysis pipeline via aireport.Run.
 // In json mode it skips AI adapter validation entirely (FR-015).
-// Threshold evaluation runs after the pipeline and may set exit code 1.
+// Threshold evaluation runs after the pipeline; a failed threshold is
+// returned as a gateError.
 func runReport(p reportParams) error {
 	// In text mode, --ai is required (FR-002).
 	if p.format != "json" && p.adapterName == "" {
@@ -1254,7 +2800,11 @@ func runReport(p reportParams) error {
 		runFn = aireport.Run
 	}
 
-	return runFn(opts)
+	err = runFn(opts)
+	if errors.Is(err, aireport.ErrThresholdFailed) {
+		return &gateError{err}
+	}
+	return err
 }
 
 // newReportCmd creates the "report" subcommand that orchestrates gaze's four
@@ -6,12 +6,19 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	"golang.org/x/tools/go/packages"
+	"gopkg.in/yaml.v3"
 
 	"github.com/unbound-force/gaze/internal/aireport"
 	"github.com/unbound-force/gaze/internal/crap"
+	"github.com/unbound-force/gaze/internal/report"
 	"github.com/unbound-force/gaze/internal/taxonomy"
 )
 
@@ -22,14 +29,14 @@ import (
 func TestRunAnalyze_InvalidFormat(t *testing.T) {
 	err := runAnalyze(analyzeParams{
 		pkgPath: "./...",
-		format:  "yaml",
+		format:  "xml",
 		stdout:  &bytes.Buffer{},
 		stderr:  &bytes.Buffer{},
 	})
 	if err == nil {
 		t.Fatal("expected error for invalid format")
 	}
-	if !strings.Contains(err.Error(), `invalid format "yaml"`) {
+	if !strings.Contains(err.Error(), `invalid format "xml"`) {
 		t.Errorf("unexpected error message: %s", err)
 	}
 }
@@ -144,6 +151,907 @@ func TestRunAnalyze_BadPackage(t *testing.T) {
 	}
 }
 
+func TestRunAnalyze_StreamMatchesBuffered(t *testing.T) {
+	const pkg = "github.com/unbound-force/gaze/internal/analysis/testdata/src/sentinel"
+
+	var buffered, streamed bytes.Buffer
+	if err := runAnalyze(analyzeParams{
+		pkgPath: pkg, format: "json", stdout: &buffered, stderr: io.Discard,
+	}); err != nil {
+		t.Fatalf("buffered run: %v", err)
+	}
+	if err := runAnalyze(analyzeParams{
+		pkgPath: pkg, format: "json", stream: true, stdout: &streamed, stderr: io.Discard,
+	}); err != nil {
+		t.Fatalf("streamed run: %v", err)
+	}
+
+	// Metadata timestamps differ between runs; compare the rest.
+	decode := func(b []byte) report.JSONReport {
+		t.Helper()
+		var r report.JSONReport
+		if err := json.Unmarshal(b, &r); err != nil {
+			t.Fatalf("output is not valid JSON: %v", err)
+		}
+		for i := range r.Results {
+			r.Results[i].Metadata = taxonomy.Metadata{}
+		}
+		return r
+	}
+	if !reflect.DeepEqual(decode(streamed.Bytes()), decode(buffered.Bytes())) {
+		t.Errorf("streamed output differs from buffered output")
+	}
+}
+
+func TestRunAnalyze_StreamInvalidCombinations(t *testing.T) {
+	tests := []struct {
+		name string
+		p    analyzeParams
+	}{
+		{"text format", analyzeParams{format: "text", stream: true}},
+		{"classify", analyzeParams{format: "json", stream: true, classify: true}},
+		{"interactive", analyzeParams{format: "json", stream: true, interactive: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.p.pkgPath = "github.com/unbound-force/gaze/internal/analysis/testdata/src/returns"
+			tt.p.stdout = io.Discard
+			tt.p.stderr = io.Discard
+			err := runAnalyze(tt.p)
+			if err == nil || !strings.Contains(err.Error(), "--stream") {
+				t.Errorf("expected --stream error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRunAnalyze_StreamFunctionNotFound(t *testing.T) {
+	err := runAnalyze(analyzeParams{
+		pkgPath:  "github.com/unbound-force/gaze/internal/analysis/testdata/src/returns",
+		format:   "json",
+		function: "DoesNotExist",
+		stream:   true,
+		stdout:   io.Discard,
+		stderr:   io.Discard,
+	})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected function-not-found error, got %v", err)
+	}
+}
+
+func TestRunAnalyze_FailOnType(t *testing.T) {
+	const pkg = "github.com/unbound-force/gaze/internal/analysis/testdata/src/p1effects"
+
+	for _, stream := range []bool{false, true} {
+		var stdout, stderr bytes.Buffer
+		err := runAnalyze(analyzeParams{
+			pkgPath: pkg, format: "json", stream: stream,
+			failOnTypes: []string{"GlobalMutation"},
+			stdout:      &stdout, stderr: &stderr,
+		})
+		if err == nil || !strings.Contains(err.Error(), "forbidden side effect") {
+			t.Fatalf("stream=%v: expected forbidden effect error, got %v", stream, err)
+		}
+		for _, fn := range []string{"MutateGlobal", "MutateTwoGlobals"} {
+			if !strings.Contains(stderr.String(), "GlobalMutation in "+fn+" (") {
+				t.Errorf("stream=%v: expected %s in violations:\n%s", stream, fn, stderr.String())
+			}
+		}
+		if strings.Contains(stderr.String(), "ReadGlobal") {
+			t.Errorf("stream=%v: ReadGlobal should not be reported:\n%s", stream, stderr.String())
+		}
+		// The report is still written in full.
+		if !json.Valid(stdout.Bytes()) {
+			t.Errorf("stream=%v: expected complete JSON output", stream)
+		}
+	}
+
+	// A type no function produces passes.
+	err := runAnalyze(analyzeParams{
+		pkgPath: pkg, format: "text", failOnTypes: []string{"FileSystemWrite"},
+		stdout: io.Discard, stderr: io.Discard,
+	})
+	if err != nil {
+		t.Errorf("expected no error for absent effect type, got %v", err)
+	}
+}
+
+func TestRunAnalyze_Quiet(t *testing.T) {
+	const pkg = "github.com/unbound-force/gaze/internal/analysis/testdata/src/edgecases"
+
+	var stdout bytes.Buffer
+	err := runAnalyze(analyzeParams{
+		pkgPath: pkg, format: "text", quiet: true,
+		stdout: &stdout, stderr: io.Discard,
+	})
+	if err != nil {
+		t.Fatalf("runAnalyze() error: %v", err)
+	}
+	out := stdout.String()
+	if strings.Contains(out, "EmptyFunction") || strings.Contains(out, "No side effects detected") {
+		t.Errorf("expected functions without side effects to be omitted:\n%s", out)
+	}
+	if !strings.Contains(out, "MultiReturn") {
+		t.Errorf("expected MultiReturn in output:\n%s", out)
+	}
+	if !strings.Contains(out, "without side effects not shown") {
+		t.Errorf("expected hidden count in summary:\n%s", out)
+	}
+
+	err = runAnalyze(analyzeParams{
+		pkgPath: pkg, format: "json", quiet: true,
+		stdout: io.Discard, stderr: io.Discard,
+	})
+	if err == nil || !strings.Contains(err.Error(), "--quiet requires --format=text") {
+		t.Errorf("expected --quiet/json error, got %v", err)
+	}
+}
+
+func TestRunAnalyze_Color(t *testing.T) {
+	const pkg = "github.com/unbound-force/gaze/internal/analysis/testdata/src/p1effects"
+
+	tests := []struct {
+		format    string
+		color     string
+		wantColor bool
+	}{
+		{"text", "auto", false},
+		{"text", "never", false},
+		{"text", "always", true},
+		// JSON output never carries escape codes.
+		{"json", "always", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format+"/"+tt.color, func(t *testing.T) {
+			var stdout bytes.Buffer
+			err := runAnalyze(analyzeParams{
+				pkgPath: pkg, format: tt.format, color: tt.color,
+				stdout: &stdout, stderr: io.Discard,
+			})
+			if err != nil {
+				t.Fatalf("runAnalyze() error: %v", err)
+			}
+			if got := strings.Contains(stdout.String(), "\x1b["); got != tt.wantColor {
+				t.Errorf("escape codes present = %v, want %v", got, tt.wantColor)
+			}
+		})
+	}
+
+	err := runAnalyze(analyzeParams{
+		pkgPath: pkg, format: "text", color: "rainbow",
+		stdout: io.Discard, stderr: io.Discard,
+	})
+	if err == nil || !strings.Contains(err.Error(), "invalid color mode") {
+		t.Errorf("expected invalid color error, got %v", err)
+	}
+}
+
+func TestRunAnalyze_Summary(t *testing.T) {
+	const pkg = "github.com/unbound-force/gaze/internal/analysis/testdata/src/p1effects"
+
+	var stdout bytes.Buffer
+	err := runAnalyze(analyzeParams{
+		pkgPath: pkg, format: "text", summary: true,
+		stdout: &stdout, stderr: io.Discard,
+	})
+	if err != nil {
+		t.Fatalf("runAnalyze() error: %v", err)
+	}
+	out := stdout.String()
+	if !strings.Contains(out, "HIGHEST TIER") || !strings.Contains(out, "MutateGlobal") {
+		t.Errorf("expected summary table:\n%s", out)
+	}
+	if strings.Contains(out, "DESCRIPTION") {
+		t.Errorf("expected no per-effect listing:\n%s", out)
+	}
+
+	stdout.Reset()
+	err = runAnalyze(analyzeParams{
+		pkgPath: pkg, format: "json", summary: true,
+		stdout: &stdout, stderr: io.Discard,
+	})
+	if err != nil {
+		t.Fatalf("runAnalyze() json summary error: %v", err)
+	}
+	var sum struct {
+		Functions     int            `json:"functions"`
+		EffectsByType map[string]int `json:"effects_by_type"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &sum); err != nil {
+		t.Fatalf("invalid JSON summary: %v\n%s", err, stdout.String())
+	}
+	if sum.Functions == 0 || sum.EffectsByType["GlobalMutation"] == 0 {
+		t.Errorf("expected functions and GlobalMutation counts, got %+v", sum)
+	}
+	if _, ok := sum.EffectsByType["CgoCall"]; !ok {
+		t.Errorf("expected zero-count CgoCall key, got %v", sum.EffectsByType)
+	}
+
+	err = runAnalyze(analyzeParams{
+		pkgPath: pkg, format: "github", summary: true,
+		stdout: io.Discard, stderr: io.Discard,
+	})
+	if err == nil || !strings.Contains(err.Error(), "--summary requires --format=text or json") {
+		t.Errorf("expected --summary/github error, got %v", err)
+	}
+}
+
+func TestRunAnalyze_SinceInvalidRef(t *testing.T) {
+	err := runAnalyze(analyzeParams{
+		pkgPath: "github.com/unbound-force/gaze/internal/analysis/testdata/src/p1effects",
+		format:  "text", since: "no-such-ref-for-gaze-tests",
+		stdout: io.Discard, stderr: io.Discard,
+	})
+	if err == nil || !strings.Contains(err.Error(), "--since") {
+		t.Errorf("expected --since error, got %v", err)
+	}
+}
+
+func TestRunAnalyze_Depth(t *testing.T) {
+	const pkg = "github.com/unbound-force/gaze/internal/analysis/testdata/src/interproc"
+
+	err := runAnalyze(analyzeParams{
+		pkgPath: pkg, format: "text", depth: -1,
+		stdout: io.Discard, stderr: io.Discard,
+	})
+	if err == nil || !strings.Contains(err.Error(), "--depth=-1 is invalid") {
+		t.Errorf("expected --depth error, got %v", err)
+	}
+
+	var stdout bytes.Buffer
+	err = runAnalyze(analyzeParams{
+		pkgPath: pkg, format: "json", function: "Do", depth: 1,
+		stdout: &stdout, stderr: io.Discard,
+	})
+	if err != nil {
+		t.Fatalf("runAnalyze --depth=1: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "(via store.(*Store).Save)") {
+		t.Errorf("expected effect propagated from Store.Save:\n%s", stdout.String())
+	}
+}
+
+func TestRunAnalyze_Exclude(t *testing.T) {
+	const pkg = "github.com/unbound-force/gaze/internal/analysis/testdata/src/generated"
+
+	err := runAnalyze(analyzeParams{
+		pkgPath: pkg, format: "text", exclude: []string{"["},
+		stdout: io.Discard, stderr: io.Discard,
+	})
+	if err == nil || !strings.Contains(err.Error(), `--exclude="[" is invalid`) {
+		t.Errorf("expected --exclude error, got %v", err)
+	}
+
+	var stdout bytes.Buffer
+	err = runAnalyze(analyzeParams{
+		pkgPath: pkg, format: "json", exclude: []string{"mock_*.go"},
+		stdout: &stdout, stderr: io.Discard,
+	})
+	if err != nil {
+		t.Fatalf("runAnalyze --exclude: %v", err)
+	}
+	out := stdout.String()
+	if !strings.Contains(out, `"Handwritten"`) {
+		t.Errorf("expected Handwritten in output:\n%s", out)
+	}
+	// Generated files are skipped without any flag.
+	for _, skipped := range []string{`"Generated"`, `"Mocked"`} {
+		if strings.Contains(out, skipped) {
+			t.Errorf("expected %s to be skipped:\n%s", skipped, out)
+		}
+	}
+}
+
+func TestRunAnalyze_BuildTags(t *testing.T) {
+	const pkg = "github.com/unbound-force/gaze/internal/analysis/testdata/src/buildtags"
+
+	for _, tt := range []struct {
+		p    analyzeParams
+		want []string
+		not  []string
+	}{
+		{analyzeParams{}, []string{`"Always"`}, []string{`"Tagged"`, `"OnPlan9"`}},
+		{analyzeParams{tags: []string{"gazetag"}}, []string{`"Always"`, `"Tagged"`}, []string{`"OnPlan9"`}},
+		{analyzeParams{goos: "plan9", goarch: "amd64"}, []string{`"Always"`, `"OnPlan9"`}, []string{`"Tagged"`}},
+		{analyzeParams{tags: []string{"gazetag"}, stream: true}, []string{`"Tagged"`}, nil},
+	} {
+		var stdout bytes.Buffer
+		p := tt.p
+		p.pkgPath, p.format, p.stdout, p.stderr = pkg, "json", &stdout, io.Discard
+		if err := runAnalyze(p); err != nil {
+			t.Fatalf("runAnalyze(tags=%v, goos=%q): %v", p.tags, p.goos, err)
+		}
+		out := stdout.String()
+		for _, fn := range tt.want {
+			if !strings.Contains(out, fn) {
+				t.Errorf("tags=%v goos=%q: expected %s in output:\n%s", p.tags, p.goos, fn, out)
+			}
+		}
+		for _, fn := range tt.not {
+			if strings.Contains(out, fn) {
+				t.Errorf("tags=%v goos=%q: expected %s to be excluded:\n%s", p.tags, p.goos, fn, out)
+			}
+		}
+	}
+}
+
+func TestRunAnalyze_JSONCompact(t *testing.T) {
+	const pkg = "github.com/unbound-force/gaze/internal/analysis/testdata/src/p1effects"
+
+	for _, p := range []analyzeParams{
+		{format: "text"},
+		{format: "json", summary: true},
+		{format: "json", listFunctions: true},
+	} {
+		p.pkgPath, p.jsonCompact, p.stdout, p.stderr = pkg, true, io.Discard, io.Discard
+		err := runAnalyze(p)
+		if err == nil || !strings.Contains(err.Error(), "--json-compact requires --format=json") {
+			t.Errorf("format=%q summary=%v list=%v: expected --json-compact error, got %v",
+				p.format, p.summary, p.listFunctions, err)
+		}
+	}
+
+	for _, stream := range []bool{false, true} {
+		var stdout bytes.Buffer
+		err := runAnalyze(analyzeParams{
+			pkgPath:     pkg,
+			format:      "json",
+			jsonCompact: true,
+			stream:      stream,
+			stdout:      &stdout,
+			stderr:      io.Discard,
+		})
+		if err != nil {
+			t.Fatalf("runAnalyze(stream=%v): %v", stream, err)
+		}
+		lines := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+		if len(lines) < 2 {
+			t.Fatalf("stream=%v: expected one line per result, got %d", stream, len(lines))
+		}
+		for i, line := range lines {
+			var r taxonomy.AnalysisResult
+			if err := json.Unmarshal([]byte(line), &r); err != nil {
+				t.Fatalf("stream=%v: line %d is not a JSON result: %v", stream, i, err)
+			}
+			if r.Target.Package == "" {
+				t.Errorf("stream=%v: line %d has no target package", stream, i)
+			}
+		}
+	}
+}
+
+func TestRunAnalyze_Sort(t *testing.T) {
+	const pkg = "github.com/unbound-force/gaze/internal/analysis/testdata/src/p1effects"
+
+	for _, tt := range []struct {
+		p    analyzeParams
+		want string
+	}{
+		{analyzeParams{sort: "severity"}, `invalid sort order "severity"`},
+		{analyzeParams{sort: "confidence"}, "--sort=confidence requires --classify"},
+		{analyzeParams{sort: "tier", format: "json", stream: true}, "--sort=tier cannot be combined with --stream"},
+	} {
+		tt.p.pkgPath, tt.p.stdout, tt.p.stderr = pkg, io.Discard, io.Discard
+		if tt.p.format == "" {
+			tt.p.format = "text"
+		}
+		if err := runAnalyze(tt.p); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("runAnalyze(sort=%q): expected error containing %q, got %v", tt.p.sort, tt.want, err)
+		}
+	}
+
+	var stdout bytes.Buffer
+	err := runAnalyze(analyzeParams{
+		pkgPath: pkg, format: "json", sort: "tier",
+		stdout: &stdout, stderr: io.Discard,
+	})
+	if err != nil {
+		t.Fatalf("runAnalyze --sort=tier: %v", err)
+	}
+	var rpt struct {
+		Results []taxonomy.AnalysisResult `json:"results"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &rpt); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	prev := taxonomy.Tier("")
+	for _, r := range rpt.Results {
+		if len(r.SideEffects) == 0 {
+			continue
+		}
+		for i, e := range r.SideEffects {
+			if i > 0 && e.Tier < r.SideEffects[i-1].Tier {
+				t.Errorf("%s: effects not sorted by tier: %s after %s", r.Target.Function, e.Tier, r.SideEffects[i-1].Tier)
+			}
+		}
+		if first := r.SideEffects[0].Tier; first < prev {
+			t.Errorf("%s: functions not sorted by top tier: %s after %s", r.Target.Function, first, prev)
+		} else {
+			prev = first
+		}
+	}
+}
+
+func TestRunAnalyze_YAMLFormat(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	err := runAnalyze(analyzeParams{
+		pkgPath: "github.com/unbound-force/gaze/internal/analysis/testdata/src/returns",
+		format:  "yaml",
+		stdout:  &stdout,
+		stderr:  &stderr,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var parsed map[string]interface{}
+	if err := yaml.Unmarshal(stdout.Bytes(), &parsed); err != nil {
+		t.Errorf("output is not valid YAML: %v\noutput:\n%s", err, stdout.String())
+	}
+	if _, ok := parsed["results"]; !ok {
+		t.Errorf("YAML output missing 'results' key")
+	}
+
+	err = runAnalyze(analyzeParams{
+		pkgPath: "github.com/unbound-force/gaze/internal/analysis/testdata/src/returns",
+		format:  "yaml",
+		summary: true,
+		stdout:  io.Discard,
+		stderr:  io.Discard,
+	})
+	if err == nil || !strings.Contains(err.Error(), "--summary requires") {
+		t.Errorf("expected --summary error with --format=yaml, got %v", err)
+	}
+}
+
+func TestRunAnalyze_GitHubFormat(t *testing.T) {
+	const pkg = "github.com/unbound-force/gaze/internal/analysis/testdata/src/p1effects"
+
+	var stdout bytes.Buffer
+	err := runAnalyze(analyzeParams{pkgPath: pkg, format: "github", stdout: &stdout, stderr: io.Discard})
+	if err != nil {
+		t.Fatalf("runAnalyze --format=github: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) == 0 || lines[0] == "" {
+		t.Fatal("expected annotations for p1effects")
+	}
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "::warning file=") && !strings.HasPrefix(line, "::notice file=") {
+			t.Errorf("not a workflow command: %q", line)
+		}
+		if !strings.Contains(line, ",line=") {
+			t.Errorf("annotation missing line: %q", line)
+		}
+	}
+}
+
+func TestRunAnalyze_Locations(t *testing.T) {
+	const pkg = "github.com/unbound-force/gaze/internal/analysis/testdata/src/p1effects"
+
+	err := runAnalyze(analyzeParams{pkgPath: pkg, format: "json", locations: "lsp", stdout: io.Discard, stderr: io.Discard})
+	if err == nil || !strings.Contains(err.Error(), `invalid location format "lsp"`) {
+		t.Errorf("expected invalid --locations error, got %v", err)
+	}
+	err = runAnalyze(analyzeParams{pkgPath: pkg, format: "text", locations: "structured", stdout: io.Discard, stderr: io.Discard})
+	if err == nil || !strings.Contains(err.Error(), "--locations=structured requires --format=json") {
+		t.Errorf("expected --format=json error, got %v", err)
+	}
+
+	var stdout bytes.Buffer
+	err = runAnalyze(analyzeParams{pkgPath: pkg, format: "json", locations: "structured", stdout: &stdout, stderr: io.Discard})
+	if err != nil {
+		t.Fatalf("runAnalyze --locations=structured: %v", err)
+	}
+	var rpt struct {
+		Results []struct {
+			SideEffects []struct {
+				Location report.Position `json:"location"`
+			} `json:"side_effects"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &rpt); err != nil {
+		t.Fatalf("invalid structured JSON: %v", err)
+	}
+	found := false
+	for _, r := range rpt.Results {
+		for _, e := range r.SideEffects {
+			found = true
+			if !strings.HasSuffix(e.Location.File, ".go") || e.Location.Line == 0 || e.Location.Col == 0 {
+				t.Errorf("incomplete structured location: %+v", e.Location)
+			}
+		}
+	}
+	if !found {
+		t.Error("expected side effects in p1effects")
+	}
+}
+
+func TestRunAnalyze_DeferTraps(t *testing.T) {
+	const pkg = "github.com/unbound-force/gaze/internal/analysis/testdata/src/returns"
+
+	err := runAnalyze(analyzeParams{pkgPath: pkg, format: "text", deferTraps: true, summary: true, stdout: io.Discard, stderr: io.Discard})
+	if err == nil || !strings.Contains(err.Error(), "--defer-traps cannot be combined") {
+		t.Errorf("expected --defer-traps/--summary error, got %v", err)
+	}
+
+	var stdout bytes.Buffer
+	if err := runAnalyze(analyzeParams{pkgPath: pkg, format: "text", deferTraps: true, stdout: &stdout, stderr: io.Discard}); err != nil {
+		t.Fatalf("runAnalyze --defer-traps: %v", err)
+	}
+	out := stdout.String()
+	if !strings.Contains(out, "NamedReturnModifiedInDefer") || !strings.Contains(out, "defer func() {...}()") {
+		t.Errorf("expected defer trap for NamedReturnModifiedInDefer, got:\n%s", out)
+	}
+	if strings.Contains(out, "SingleReturn") {
+		t.Errorf("function without defer traps listed:\n%s", out)
+	}
+
+	stdout.Reset()
+	if err := runAnalyze(analyzeParams{pkgPath: pkg, format: "json", deferTraps: true, stdout: &stdout, stderr: io.Discard}); err != nil {
+		t.Fatalf("runAnalyze --defer-traps --format=json: %v", err)
+	}
+	var rpt struct {
+		Results []struct {
+			SideEffects []struct {
+				Type string `json:"type"`
+			} `json:"side_effects"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &rpt); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(rpt.Results) == 0 {
+		t.Fatal("expected defer trap results")
+	}
+	for _, r := range rpt.Results {
+		for _, e := range r.SideEffects {
+			if e.Type != "DeferredReturnMutation" {
+				t.Errorf("unexpected effect type %s in --defer-traps output", e.Type)
+			}
+		}
+	}
+}
+
+func TestRunAnalyze_Timeout(t *testing.T) {
+	const pkg = "github.com/unbound-force/gaze/internal/analysis/testdata/src/returns"
+
+	err := runAnalyze(analyzeParams{pkgPath: pkg, format: "text", timeout: -time.Second, stdout: io.Discard, stderr: io.Discard})
+	if err == nil || !strings.Contains(err.Error(), "--timeout=-1s is invalid") {
+		t.Errorf("expected invalid --timeout error, got %v", err)
+	}
+
+	for _, stream := range []bool{false, true} {
+		err := runAnalyze(analyzeParams{
+			pkgPath: pkg,
+			format:  "json",
+			stream:  stream,
+			timeout: time.Nanosecond,
+			stdout:  io.Discard,
+			stderr:  io.Discard,
+		})
+		if err == nil || !strings.Contains(err.Error(), "--timeout=1ns exceeded") {
+			t.Errorf("stream=%v: expected --timeout exceeded error, got %v", stream, err)
+		}
+	}
+
+	var stdout bytes.Buffer
+	if err := runAnalyze(analyzeParams{pkgPath: pkg, format: "text", timeout: time.Hour, stdout: &stdout, stderr: io.Discard}); err != nil {
+		t.Fatalf("runAnalyze with a generous --timeout: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "SingleReturn") {
+		t.Errorf("expected complete output, got:\n%s", stdout.String())
+	}
+}
+
+func TestRunConfig(t *testing.T) {
+	if err := runConfig(configParams{format: "xml", stdout: io.Discard}); err == nil {
+		t.Error("expected an error for an invalid format")
+	}
+
+	path := filepath.Join(t.TempDir(), ".gaze.yaml")
+	cfgYAML := "classification:\n  thresholds:\n    contractual: 75\n  doc_scan:\n    timeout: 45s\n"
+	if err := os.WriteFile(path, []byte(cfgYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout bytes.Buffer
+	if err := runConfig(configParams{configPath: path, format: "json", stdout: &stdout}); err != nil {
+		t.Fatalf("runConfig: %v", err)
+	}
+	var got struct {
+		Classification struct {
+			Thresholds struct {
+				Contractual int `json:"contractual"`
+				Incidental  int `json:"incidental"`
+			} `json:"thresholds"`
+			DocScan struct {
+				Exclude []string `json:"exclude"`
+				Timeout string   `json:"timeout"`
+			} `json:"doc_scan"`
+			Weights map[string]struct {
+				Base int `json:"base"`
+			} `json:"weights"`
+		} `json:"classification"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout.String())
+	}
+	c := got.Classification
+	if c.Thresholds.Contractual != 75 || c.Thresholds.Incidental != 50 {
+		t.Errorf("thresholds = %+v, want the file's contractual merged with the default incidental", c.Thresholds)
+	}
+	if c.DocScan.Timeout != "45s" || len(c.DocScan.Exclude) == 0 {
+		t.Errorf("doc_scan = %+v, want the parsed timeout and default excludes", c.DocScan)
+	}
+	if c.Weights["interface"].Base != 30 {
+		t.Errorf("weights = %+v, want defaults filled in", c.Weights)
+	}
+
+	stdout.Reset()
+	if err := runConfig(configParams{configPath: path, format: "yaml", stdout: &stdout}); err != nil {
+		t.Fatalf("runConfig --format=yaml: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "contractual: 75") || !strings.Contains(stdout.String(), "timeout: 45s") {
+		t.Errorf("unexpected YAML output:\n%s", stdout.String())
+	}
+}
+
+func TestRunAnalyze_ClassificationFilters(t *testing.T) {
+	const pkg = "github.com/unbound-force/gaze/internal/analysis/testdata/src/p1effects"
+
+	for _, tt := range []struct {
+		p    analyzeParams
+		want string
+	}{
+		{analyzeParams{confidenceBelow: 101, classify: true}, "--confidence-below=101 is invalid"},
+		{analyzeParams{labels: []string{"maybe"}, classify: true}, `invalid --label "maybe"`},
+		{analyzeParams{confidenceBelow: 60}, "--confidence-below and --label require --classify"},
+		{analyzeParams{labels: []string{"ambiguous"}}, "--confidence-below and --label require --classify"},
+		{analyzeParams{testCallers: true}, "--test-callers requires --classify"},
+		{analyzeParams{minTier: "P5"}, `invalid --min-tier "P5"`},
+		{analyzeParams{types: []string{"Mutation"}}, `invalid --type "Mutation"`},
+		{analyzeParams{minTier: "P1", stream: true}, "--min-tier and --type cannot be combined with --stream"},
+	} {
+		tt.p.pkgPath, tt.p.format, tt.p.stdout, tt.p.stderr = pkg, "text", io.Discard, io.Discard
+		if err := runAnalyze(tt.p); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("runAnalyze(%+v): expected error containing %q, got %v", tt.p, tt.want, err)
+		}
+	}
+}
+
+func TestFilterClassified(t *testing.T) {
+	effect := func(id string, label taxonomy.ClassificationLabel, confidence int) taxonomy.SideEffect {
+		e := taxonomy.SideEffect{ID: id}
+		if label != "" {
+			e.Classification = &taxonomy.Classification{Label: label, Confidence: confidence}
+		}
+		return e
+	}
+	results := []taxonomy.AnalysisResult{
+		{Target: taxonomy.FunctionTarget{Function: "A"}, SideEffects: []taxonomy.SideEffect{
+			effect("a1", taxonomy.Ambiguous, 45),
+			effect("a2", taxonomy.Contractual, 90),
+			effect("a3", "", 0),
+		}},
+		{Target: taxonomy.FunctionTarget{Function: "B"}, SideEffects: []taxonomy.SideEffect{
+			effect("b1", taxonomy.Ambiguous, 65),
+			effect("b2", taxonomy.Incidental, 30),
+		}},
+		{Target: taxonomy.FunctionTarget{Function: "C"}},
+	}
+
+	ids := func(rs []taxonomy.AnalysisResult) []string {
+		var out []string
+		for _, r := range rs {
+			for _, e := range r.SideEffects {
+				out = append(out, r.Target.Function+"/"+e.ID)
+			}
+		}
+		return out
+	}
+
+	for _, tt := range []struct {
+		name   string
+		below  int
+		labels []taxonomy.ClassificationLabel
+		want   []string
+	}{
+		{"no filters", 0, nil, []string{"A/a1", "A/a2", "A/a3", "B/b1", "B/b2"}},
+		{"confidence below", 60, nil, []string{"A/a1", "B/b2"}},
+		{"label", 0, []taxonomy.ClassificationLabel{taxonomy.Ambiguous}, []string{"A/a1", "B/b1"}},
+		{"both", 60, []taxonomy.ClassificationLabel{taxonomy.Ambiguous}, []string{"A/a1"}},
+		{"two labels", 0, []taxonomy.ClassificationLabel{taxonomy.Contractual, taxonomy.Incidental}, []string{"A/a2", "B/b2"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			labels := make(map[taxonomy.ClassificationLabel]bool)
+			for _, l := range tt.labels {
+				labels[l] = true
+			}
+			got := filterClassified(results, tt.below, labels)
+			if !reflect.DeepEqual(ids(got), tt.want) {
+				t.Errorf("filterClassified = %v, want %v", ids(got), tt.want)
+			}
+			if tt.below == 0 && len(tt.labels) == 0 {
+				return
+			}
+			for _, r := range got {
+				if len(r.SideEffects) == 0 {
+					t.Errorf("%s kept with no side effects", r.Target.Function)
+				}
+			}
+		})
+	}
+	if len(results[0].SideEffects) != 3 {
+		t.Errorf("filterClassified modified its input: %d effects", len(results[0].SideEffects))
+	}
+}
+
+func TestFilterEffects(t *testing.T) {
+	effect := func(id string, typ taxonomy.SideEffectType) taxonomy.SideEffect {
+		return taxonomy.SideEffect{ID: id, Type: typ, Tier: taxonomy.TierOf(typ)}
+	}
+	results := []taxonomy.AnalysisResult{
+		{Target: taxonomy.FunctionTarget{Function: "A"}, SideEffects: []taxonomy.SideEffect{
+			effect("a1", taxonomy.ReturnValue),
+			effect("a2", taxonomy.GlobalMutation),
+			effect("a3", taxonomy.StdoutWrite),
+		}},
+		{Target: taxonomy.FunctionTarget{Function: "B"}, SideEffects: []taxonomy.SideEffect{
+			effect("b1", taxonomy.LogWrite),
+		}},
+	}
+
+	ids := func(rs []taxonomy.AnalysisResult) []string {
+		var out []string
+		for _, r := range rs {
+			for _, e := range r.SideEffects {
+				out = append(out, r.Target.Function+"/"+e.ID)
+			}
+		}
+		return out
+	}
+
+	for _, tt := range []struct {
+		name    string
+		minTier taxonomy.Tier
+		types   []taxonomy.SideEffectType
+		want    []string
+	}{
+		{"no filters", "", nil, []string{"A/a1", "A/a2", "A/a3", "B/b1"}},
+		{"min tier", taxonomy.TierP1, nil, []string{"A/a1", "A/a2"}},
+		{"type", "", []taxonomy.SideEffectType{taxonomy.LogWrite, taxonomy.StdoutWrite}, []string{"A/a3", "B/b1"}},
+		{"both", taxonomy.TierP2, []taxonomy.SideEffectType{taxonomy.LogWrite, taxonomy.StdoutWrite}, []string{"B/b1"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			types := make(map[taxonomy.SideEffectType]bool)
+			for _, typ := range tt.types {
+				types[typ] = true
+			}
+			got := filterEffects(results, tt.minTier, types)
+			if !reflect.DeepEqual(ids(got), tt.want) {
+				t.Errorf("filterEffects = %v, want %v", ids(got), tt.want)
+			}
+		})
+	}
+	if len(results[0].SideEffects) != 3 {
+		t.Errorf("filterEffects modified its input: %d effects", len(results[0].SideEffects))
+	}
+}
+
+func TestRunGraph(t *testing.T) {
+	err := runGraph(graphParams{function: "runGraph", pkgPath: ".", depth: 0, stdout: io.Discard})
+	if err == nil || !strings.Contains(err.Error(), "--depth=0 is invalid") {
+		t.Errorf("expected --depth error, got %v", err)
+	}
+
+	err = runGraph(graphParams{function: "noSuchFunc", pkgPath: ".", depth: 1, stdout: io.Discard})
+	if err == nil || !strings.Contains(err.Error(), `function "noSuchFunc" not found`) {
+		t.Errorf("expected not-found error, got %v", err)
+	}
+
+	// The working directory is cmd/gaze, so the module load covers
+	// this package: runGraph is referenced by newGraphCmd.
+	var stdout bytes.Buffer
+	err = runGraph(graphParams{function: "runGraph", pkgPath: ".", depth: 1, stdout: &stdout})
+	if err != nil {
+		t.Fatalf("runGraph: %v", err)
+	}
+	out := stdout.String()
+	if !strings.HasPrefix(out, `digraph "main.runGraph" {`) {
+		t.Errorf("unexpected DOT header:\n%s", out)
+	}
+	if !strings.Contains(out, `"github.com/unbound-force/gaze/cmd/gaze.newGraphCmd" -> "github.com/unbound-force/gaze/cmd/gaze.runGraph";`) {
+		t.Errorf("expected newGraphCmd -> runGraph edge:\n%s", out)
+	}
+}
+
+func TestRunExplain(t *testing.T) {
+	const pkg = "github.com/unbound-force/gaze/internal/analysis/testdata/src/returns"
+
+	for _, tt := range []struct {
+		p    explainParams
+		want string
+	}{
+		{explainParams{function: "NamedReturnModifiedInDefer", effectType: "ErrorReturns"}, `invalid effect type "ErrorReturns"`},
+		{explainParams{function: "NoSuchFunc", effectType: "ErrorReturn"}, `function "NoSuchFunc" not found`},
+		{explainParams{function: "NamedReturnModifiedInDefer", effectType: "ChannelSend"}, "has no ChannelSend side effect (it has: "},
+	} {
+		tt.p.pkgPath, tt.p.stdout = pkg, io.Discard
+		err := runExplain(tt.p)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("runExplain(%s, %s): expected error containing %q, got %v", tt.p.function, tt.p.effectType, tt.want, err)
+		}
+	}
+
+	var stdout bytes.Buffer
+	err := runExplain(explainParams{
+		pkgPath:           pkg,
+		function:          "NamedReturnModifiedInDefer",
+		effectType:        "ErrorReturn",
+		contractualThresh: 90,
+		incidentalThresh:  40,
+		stdout:            &stdout,
+	})
+	if err != nil {
+		t.Fatalf("runExplain: %v", err)
+	}
+	out := stdout.String()
+	for _, want := range []string{
+		"NamedReturnModifiedInDefer: ErrorReturn at ",
+		"base confidence                 50 =  50",
+		"tier boost (P0)                +25 =  75",
+		"contractual: ",
+		">= 90? ",
+		"label: ",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunAnalyze_FailOnTypeInvalid(t *testing.T) {
+	err := runAnalyze(analyzeParams{
+		pkgPath: "./...", format: "text", failOnTypes: []string{"GlobalMutations"},
+		stdout: io.Discard, stderr: io.Discard,
+	})
+	if err == nil || !strings.Contains(err.Error(), `invalid --fail-on-type "GlobalMutations"`) {
+		t.Errorf("expected invalid type error, got %v", err)
+	}
+}
+
+func TestRunAnalyze_SentinelGrouping(t *testing.T) {
+	const pkg = "github.com/unbound-force/gaze/internal/analysis/testdata/src/sentinel"
+
+	for _, legacy := range []bool{false, true} {
+		var stdout bytes.Buffer
+		if err := runAnalyze(analyzeParams{
+			pkgPath: pkg, format: "json", legacySentinels: legacy,
+			stdout: &stdout, stderr: io.Discard,
+		}); err != nil {
+			t.Fatalf("legacy=%v: unexpected error: %v", legacy, err)
+		}
+
+		var rpt report.JSONReport
+		if err := json.Unmarshal(stdout.Bytes(), &rpt); err != nil {
+			t.Fatalf("legacy=%v: output is not valid JSON: %v", legacy, err)
+		}
+		hasPackage := false
+		for _, r := range rpt.Results {
+			if r.Target.Function == "<package>" {
+				hasPackage = true
+			}
+		}
+		if hasPackage != legacy {
+			t.Errorf("legacy=%v: <package> result present = %v", legacy, hasPackage)
+		}
+		if (len(rpt.Sentinels) > 0) == legacy {
+			t.Errorf("legacy=%v: got %d grouped sentinels", legacy, len(rpt.Sentinels))
+		}
+	}
+}
+
 // ---------------------------------------------------------------------------
 // writeCrapReport tests
 // ---------------------------------------------------------------------------
@@ -172,7 +1080,7 @@ func TestWriteCrapReport_JSON(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	err := writeCrapReport(&buf, "json", rpt)
+	err := writeCrapReport(&buf, "json", report.ColorAuto, rpt)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -207,7 +1115,7 @@ func TestWriteCrapReport_Text(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	err := writeCrapReport(&buf, "text", rpt)
+	err := writeCrapReport(&buf, "text", report.ColorAuto, rpt)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -534,6 +1442,42 @@ func TestRunAnalyze_ClassifyFlag_JSONFormat(t *testing.T) {
 	}
 }
 
+func TestRunAnalyze_ClassifyMultiplePackages(t *testing.T) {
+	// --classify must analyze and classify every package the
+	// pattern matches, not just the first.
+	var stdout, stderr bytes.Buffer
+	err := runAnalyze(analyzeParams{
+		pkgPath:  "../../internal/report/testdata/src/multipkg/...",
+		format:   "json",
+		classify: true,
+		stdout:   &stdout,
+		stderr:   &stderr,
+	})
+	if err != nil {
+		t.Fatalf("runAnalyze --classify error: %v", err)
+	}
+
+	var rpt report.JSONReport
+	if err := json.Unmarshal(stdout.Bytes(), &rpt); err != nil {
+		t.Fatalf("output is not valid JSON: %v\noutput:\n%s", err, stdout.String())
+	}
+	const base = "github.com/unbound-force/gaze/internal/report/testdata/src/multipkg/"
+	seen := make(map[string]bool)
+	for _, r := range rpt.Results {
+		seen[r.Target.Package] = true
+		for _, se := range r.SideEffects {
+			if se.Classification == nil {
+				t.Errorf("%s: %s effect not classified", r.Target.QualifiedName(), se.Type)
+			}
+		}
+	}
+	for _, pkg := range []string{base + "alpha", base + "beta"} {
+		if !seen[pkg] {
+			t.Errorf("expected results for %s, got packages %v", pkg, seen)
+		}
+	}
+}
+
 func TestRunAnalyze_VerboseImpliesClassify(t *testing.T) {
 	// --verbose without --classify should still produce classification output.
 	var stdout, stderr bytes.Buffer
@@ -561,7 +1505,7 @@ func TestRunAnalyze_VerboseImpliesClassify(t *testing.T) {
 // TestLoadConfig_ContractualThresholdOverride verifies that a positive
 // contractual threshold value is applied to the config.
 func TestLoadConfig_ContractualThresholdOverride(t *testing.T) {
-	cfg, err := loadConfig("", 90, -1)
+	cfg, err := loadConfig("", t.TempDir(), 90, -1)
 	if err != nil {
 		t.Fatalf("loadConfig error: %v", err)
 	}
@@ -579,7 +1523,7 @@ func TestLoadConfig_ContractualThresholdOverride(t *testing.T) {
 // TestLoadConfig_IncidentalThresholdOverride verifies that a positive
 // incidental threshold value is applied to the config.
 func TestLoadConfig_IncidentalThresholdOverride(t *testing.T) {
-	cfg, err := loadConfig("", -1, 30)
+	cfg, err := loadConfig("", t.TempDir(), -1, 30)
 	if err != nil {
 		t.Fatalf("loadConfig error: %v", err)
 	}
@@ -597,7 +1541,7 @@ func TestLoadConfig_IncidentalThresholdOverride(t *testing.T) {
 // TestLoadConfig_BothThresholdsOverride verifies that both thresholds
 // can be overridden simultaneously.
 func TestLoadConfig_BothThresholdsOverride(t *testing.T) {
-	cfg, err := loadConfig("", 95, 35)
+	cfg, err := loadConfig("", t.TempDir(), 95, 35)
 	if err != nil {
 		t.Fatalf("loadConfig error: %v", err)
 	}
@@ -611,10 +1555,72 @@ func TestLoadConfig_BothThresholdsOverride(t *testing.T) {
 	}
 }
 
+func TestConfigStartDir(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.go")
+	if err := os.WriteFile(file, []byte("package a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		pkgPath string
+		want    string
+	}{
+		{dir, dir},
+		{dir + "/...", dir},
+		{file, dir},
+		{"github.com/unbound-force/gaze/internal/crap", "."},
+		{filepath.Join(dir, "missing"), "."},
+	}
+	for _, tt := range tests {
+		if got := configStartDir(tt.pkgPath); got != tt.want {
+			t.Errorf("configStartDir(%q) = %q, want %q", tt.pkgPath, got, tt.want)
+		}
+	}
+}
+
+func TestTargetModuleRoot(t *testing.T) {
+	// Docs come from the target's module, not the current directory.
+	other := &packages.Package{Module: &packages.Module{Path: "example.com/other", Dir: "/src/other"}}
+	if got := targetModuleRoot(other, "/cwd"); got != "/src/other" {
+		t.Errorf("targetModuleRoot = %q, want %q", got, "/src/other")
+	}
+	for _, pkg := range []*packages.Package{nil, {}, {Module: &packages.Module{}}} {
+		if got := targetModuleRoot(pkg, "/cwd"); got != "/cwd" {
+			t.Errorf("targetModuleRoot(%+v) = %q, want fallback", pkg, got)
+		}
+	}
+}
+
+// TestLoadConfig_Discovers verifies that an empty path discovers
+// .gaze.yaml in a parent of the start directory.
+func TestLoadConfig_Discovers(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module m\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	content := []byte("classification:\n  thresholds:\n    contractual: 70\n")
+	if err := os.WriteFile(filepath.Join(root, ".gaze.yaml"), content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	pkgDir := filepath.Join(root, "internal", "pkg")
+	if err := os.MkdirAll(pkgDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := loadConfig("", pkgDir, -1, -1)
+	if err != nil {
+		t.Fatalf("loadConfig error: %v", err)
+	}
+	if cfg.Classification.Thresholds.Contractual != 70 {
+		t.Errorf("contractual threshold = %d, want 70 (discovered)",
+			cfg.Classification.Thresholds.Contractual)
+	}
+}
+
 // TestLoadConfig_NoOverride verifies that -1 sentinel leaves
 // thresholds at their config/default values.
 func TestLoadConfig_NoOverride(t *testing.T) {
-	cfg, err := loadConfig("", -1, -1)
+	cfg, err := loadConfig("", t.TempDir(), -1, -1)
 	if err != nil {
 		t.Fatalf("loadConfig error: %v", err)
 	}
@@ -645,7 +1651,7 @@ func TestLoadConfig_YAMLInvertedThresholdsRejected(t *testing.T) {
 		t.Fatalf("writing temp config: %v", err)
 	}
 
-	_, err := loadConfig(cfgPath, -1, -1)
+	_, err := loadConfig(cfgPath, "", -1, -1)
 	if err == nil {
 		t.Fatal("expected error for inverted YAML thresholds, got nil")
 	}
@@ -658,7 +1664,7 @@ func TestLoadConfig_YAMLInvertedThresholdsRejected(t *testing.T) {
 // TestLoadConfig_ZeroThresholdRejected verifies that a threshold of 0
 // is rejected with an error (prevents degenerate all-contractual state).
 func TestLoadConfig_ZeroThresholdRejected(t *testing.T) {
-	_, err := loadConfig("", 0, -1)
+	_, err := loadConfig("", t.TempDir(), 0, -1)
 	if err == nil {
 		t.Fatal("expected error for contractual-threshold=0, got nil")
 	}
@@ -671,7 +1677,7 @@ func TestLoadConfig_ZeroThresholdRejected(t *testing.T) {
 // is rejected with an error.
 func TestLoadConfig_InvertedThresholdsRejected(t *testing.T) {
 	// contractual=40 < incidental=60 — invalid.
-	_, err := loadConfig("", 40, 60)
+	_, err := loadConfig("", t.TempDir(), 40, 60)
 	if err == nil {
 		t.Fatal("expected error for contractual=40 < incidental=60, got nil")
 	}
@@ -692,6 +1698,26 @@ func TestRunCrap_InvalidFormat(t *testing.T) {
 	}
 }
 
+func TestRunCrap_InvalidCoverageThreshold(t *testing.T) {
+	opts := crap.DefaultOptions()
+	opts.ComplexityThreshold = 10
+	opts.CoverageThreshold = 120
+	err := runCrap(crapParams{
+		patterns: []string{"./..."},
+		format:   "text",
+		opts:     opts,
+		stdout:   &bytes.Buffer{},
+		stderr:   &bytes.Buffer{},
+		coverageFunc: func([]string, string, io.Writer) (func(string, string) (crap.ContractCoverageInfo, bool), []string) {
+			t.Fatal("quality pipeline should not run with invalid options")
+			return nil, nil
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), "invalid coverage threshold") {
+		t.Errorf("expected invalid coverage threshold error, got %v", err)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // runCrap fast unit tests (US3 — T016)
 // ---------------------------------------------------------------------------
@@ -745,19 +1771,41 @@ func TestRunCrap_TextOutput(t *testing.T) {
 	if err != nil {
 		t.Fatalf("runCrap returned error: %v", err)
 	}
-	if stdout.Len() == 0 {
-		t.Error("expected non-empty text output")
-	}
-	if !strings.Contains(stdout.String(), "Foo") {
-		t.Errorf("expected output to contain 'Foo', got: %s", stdout.String())
+	if stdout.Len() == 0 {
+		t.Error("expected non-empty text output")
+	}
+	if !strings.Contains(stdout.String(), "Foo") {
+		t.Errorf("expected output to contain 'Foo', got: %s", stdout.String())
+	}
+}
+
+func TestRunCrap_JSONOutput(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	err := runCrap(crapParams{
+		patterns:     []string{"./..."},
+		format:       "json",
+		opts:         crap.DefaultOptions(),
+		moduleDir:    ".",
+		stdout:       &stdout,
+		stderr:       &stderr,
+		analyzeFunc:  stubAnalyze,
+		coverageFunc: stubCoverageNil,
+	})
+	if err != nil {
+		t.Fatalf("runCrap returned error: %v", err)
+	}
+	// Verify JSON output is valid.
+	var result map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Errorf("output is not valid JSON: %v", err)
 	}
 }
 
-func TestRunCrap_JSONOutput(t *testing.T) {
+func TestRunCrap_YAMLOutput(t *testing.T) {
 	var stdout, stderr bytes.Buffer
 	err := runCrap(crapParams{
 		patterns:     []string{"./..."},
-		format:       "json",
+		format:       "yaml",
 		opts:         crap.DefaultOptions(),
 		moduleDir:    ".",
 		stdout:       &stdout,
@@ -768,10 +1816,12 @@ func TestRunCrap_JSONOutput(t *testing.T) {
 	if err != nil {
 		t.Fatalf("runCrap returned error: %v", err)
 	}
-	// Verify JSON output is valid.
 	var result map[string]interface{}
-	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
-		t.Errorf("output is not valid JSON: %v", err)
+	if err := yaml.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Errorf("output is not valid YAML: %v", err)
+	}
+	if _, ok := result["summary"]; !ok {
+		t.Errorf("YAML output missing 'summary' key:\n%s", stdout.String())
 	}
 }
 
@@ -841,6 +1891,162 @@ func TestRunCrap_ThresholdBreach(t *testing.T) {
 	}
 }
 
+func writeBaseline(t *testing.T, rpt *crap.Report) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := crap.WriteJSON(&buf, rpt); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunCrap_BaselineRatchet(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutate   func(*crap.Report)
+		wantFail bool
+	}{
+		{"unchanged", func(*crap.Report) {}, false},
+		{"improved", func(r *crap.Report) { r.Scores[0].CRAP = 5 }, false},
+		{"worsened", func(r *crap.Report) { r.Scores[0].CRAP = 6 }, true},
+		{"added below threshold", func(r *crap.Report) {
+			r.Scores = append(r.Scores, crap.Score{Package: "example.com/pkg", Function: "Bar", CRAP: 3})
+		}, false},
+		{"added above threshold", func(r *crap.Report) {
+			r.Scores = append(r.Scores, crap.Score{Package: "example.com/pkg", Function: "Bar", CRAP: 30})
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The baseline already carries pre-existing debt
+			// (CRAPload 5) that must not fail the run.
+			base := stubReport()
+			base.Summary.CRAPload = 5
+			path := writeBaseline(t, base)
+
+			analyze := func(_ []string, _ string, _ crap.Options) (*crap.Report, error) {
+				rpt := stubReport()
+				rpt.Summary.CRAPload = 5
+				tt.mutate(rpt)
+				return rpt, nil
+			}
+
+			var stdout, stderr bytes.Buffer
+			err := runCrap(crapParams{
+				patterns:     []string{"./..."},
+				format:       "json",
+				opts:         crap.DefaultOptions(),
+				baseline:     path,
+				moduleDir:    ".",
+				stdout:       &stdout,
+				stderr:       &stderr,
+				analyzeFunc:  analyze,
+				coverageFunc: stubCoverageNil,
+			})
+			if tt.wantFail && err == nil {
+				t.Fatal("expected regression error")
+			}
+			if !tt.wantFail && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var out crap.Report
+			if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			if out.Diff == nil {
+				t.Fatal("expected diff in JSON output")
+			}
+			if !strings.Contains(stderr.String(), "Baseline:") {
+				t.Errorf("expected baseline summary on stderr, got %q", stderr.String())
+			}
+		})
+	}
+}
+
+func TestRunCrap_MalformedConfig(t *testing.T) {
+	// A broken .gaze.yaml must fail the run rather than silently
+	// fall back to the default thresholds.
+	path := filepath.Join(t.TempDir(), ".gaze.yaml")
+	if err := os.WriteFile(path, []byte("classification: [\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err := runCrap(crapParams{
+		patterns:     []string{"./..."},
+		format:       "text",
+		opts:         crap.DefaultOptions(),
+		moduleDir:    ".",
+		configPath:   path,
+		stdout:       io.Discard,
+		stderr:       io.Discard,
+		analyzeFunc:  func([]string, string, crap.Options) (*crap.Report, error) { return stubReport(), nil },
+		coverageFunc: stubCoverageNil,
+	})
+	if err == nil || !strings.Contains(err.Error(), "loading config") {
+		t.Errorf("expected config load error, got %v", err)
+	}
+}
+
+func TestRunCrap_BaselineWithMaxCrapload(t *testing.T) {
+	err := runCrap(crapParams{
+		patterns:    []string{"./..."},
+		format:      "text",
+		opts:        crap.DefaultOptions(),
+		baseline:    writeBaseline(t, stubReport()),
+		maxCrapload: 3,
+		stdout:      &bytes.Buffer{},
+		stderr:      &bytes.Buffer{},
+		analyzeFunc: stubAnalyze,
+	})
+	if err == nil || !strings.Contains(err.Error(), "--baseline cannot be combined") {
+		t.Errorf("expected combination error, got %v", err)
+	}
+}
+
+func TestRunCrap_BaselineMissing(t *testing.T) {
+	err := runCrap(crapParams{
+		patterns:    []string{"./..."},
+		format:      "text",
+		opts:        crap.DefaultOptions(),
+		baseline:    filepath.Join(t.TempDir(), "missing.json"),
+		stdout:      &bytes.Buffer{},
+		stderr:      &bytes.Buffer{},
+		analyzeFunc: stubAnalyze,
+	})
+	if err == nil || !strings.Contains(err.Error(), "loading baseline") {
+		t.Errorf("expected baseline load error, got %v", err)
+	}
+}
+
+func TestRunCrap_NoTestsSkipsQualityPipeline(t *testing.T) {
+	opts := crap.DefaultOptions()
+	opts.CoverageMode = crap.CoverageNone
+	var stdout, stderr bytes.Buffer
+	err := runCrap(crapParams{
+		patterns:    []string{"./..."},
+		format:      "text",
+		opts:        opts,
+		moduleDir:   ".",
+		stdout:      &stdout,
+		stderr:      &stderr,
+		analyzeFunc: stubAnalyze,
+		coverageFunc: func([]string, string, io.Writer) (func(string, string) (crap.ContractCoverageInfo, bool), []string) {
+			t.Fatal("quality pipeline should not run with --no-tests")
+			return nil, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(stderr.String(), "GazeCRAP unavailable") {
+		t.Errorf("expected no GazeCRAP note in no-tests mode, got %q", stderr.String())
+	}
+}
+
 func TestRunCrap_EmptyPatterns(t *testing.T) {
 	var capturedPatterns []string
 	capturingAnalyze := func(patterns []string, _ string, _ crap.Options) (*crap.Report, error) {
@@ -951,6 +2157,19 @@ func TestRunQuality_InvalidFormat(t *testing.T) {
 	}
 }
 
+func TestRunQuality_JSONCompactRequiresJSON(t *testing.T) {
+	err := runQuality(qualityParams{
+		pkgPath:     "github.com/unbound-force/gaze/internal/quality/testdata/src/welltested",
+		format:      "text",
+		jsonCompact: true,
+		stdout:      &bytes.Buffer{},
+		stderr:      &bytes.Buffer{},
+	})
+	if err == nil || !strings.Contains(err.Error(), "--json-compact requires --format=json") {
+		t.Errorf("expected --json-compact error, got %v", err)
+	}
+}
+
 func TestRunQuality_TextFormat(t *testing.T) {
 	var stdout, stderr bytes.Buffer
 	err := runQuality(qualityParams{
@@ -1056,6 +2275,61 @@ func TestRunQuality_BadPackage(t *testing.T) {
 	}
 }
 
+func TestRunCoverage_InvalidFormat(t *testing.T) {
+	err := runCoverage(coverageParams{
+		pkgPath: "github.com/unbound-force/gaze/internal/quality/testdata/src/welltested",
+		format:  "yaml",
+		stdout:  &bytes.Buffer{},
+		stderr:  &bytes.Buffer{},
+	})
+	if err == nil || !strings.Contains(err.Error(), `invalid format "yaml"`) {
+		t.Errorf("expected invalid format error, got %v", err)
+	}
+}
+
+func TestRunCoverage_JSONFormat(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping quality pipeline in short mode")
+	}
+	var stdout, stderr bytes.Buffer
+	err := runCoverage(coverageParams{
+		pkgPath: "github.com/unbound-force/gaze/internal/quality/testdata/src/welltested",
+		format:  "json",
+		stdout:  &stdout,
+		stderr:  &stderr,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var output struct {
+		Functions []taxonomy.FunctionCoverage `json:"function_coverage"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if len(output.Functions) == 0 {
+		t.Fatal("expected per-function coverage entries")
+	}
+	for _, fc := range output.Functions {
+		if fc.AssertedCount+len(fc.Unasserted) != fc.TotalContractual {
+			t.Errorf("%s: asserted %d + unasserted %d != total %d",
+				fc.Target.QualifiedName(), fc.AssertedCount, len(fc.Unasserted), fc.TotalContractual)
+		}
+	}
+}
+
+func TestRunCoverage_BadPackage(t *testing.T) {
+	err := runCoverage(coverageParams{
+		pkgPath: "github.com/nonexistent/package",
+		format:  "text",
+		stdout:  &bytes.Buffer{},
+		stderr:  &bytes.Buffer{},
+	})
+	if err == nil {
+		t.Error("expected error for nonexistent package")
+	}
+}
+
 // ---------------------------------------------------------------------------
 // checkQualityThresholds tests (SC-005)
 // ---------------------------------------------------------------------------
@@ -1890,7 +3164,7 @@ func TestSC002_GazeCRAPloadMatchBetweenCrapAndReport(t *testing.T) {
 	// Run gaze crap standalone with BuildContractCoverageFunc.
 	crapOpts := crap.DefaultOptions()
 	crapOpts.Stderr = io.Discard
-	ccFunc, _ := crap.BuildContractCoverageFunc([]string{pattern}, moduleDir, io.Discard)
+	ccFunc, _ := crap.BuildContractCoverageFunc([]string{pattern}, moduleDir, nil, io.Discard)
 	if ccFunc != nil {
 		crapOpts.ContractCoverageFunc = ccFunc
 	}
@@ -2328,3 +3602,313 @@ func TestBuildAIMapperFunc_OllamaWithModel(t *testing.T) {
 		t.Fatal("expected non-nil AIMapperFunc for ollama with model")
 	}
 }
+
+func TestRunAnalyze_EnableDisable(t *testing.T) {
+	const pkg = "github.com/unbound-force/gaze/internal/analysis/testdata/src/p1effects"
+
+	err := runAnalyze(analyzeParams{pkgPath: pkg, format: "text", disable: []string{"TimeDependency"}, stdout: io.Discard, stderr: io.Discard})
+	if err == nil || !strings.Contains(err.Error(), `unknown analyzer "TimeDependency"`) {
+		t.Errorf("expected unknown analyzer error, got %v", err)
+	}
+
+	var stdout bytes.Buffer
+	err = runAnalyze(analyzeParams{
+		pkgPath:  pkg,
+		format:   "json",
+		function: "MutateGlobal",
+		enable:   []string{"returns", "p1"},
+		disable:  []string{"p1"},
+		stdout:   &stdout,
+		stderr:   io.Discard,
+	})
+	if err != nil {
+		t.Fatalf("runAnalyze: %v", err)
+	}
+	if strings.Contains(stdout.String(), "GlobalMutation") {
+		t.Errorf("GlobalMutation reported with the p1 analyzer disabled:\n%s", stdout.String())
+	}
+}
+
+func TestWriteAnalyzers(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeAnalyzers(&buf); err != nil {
+		t.Fatalf("writeAnalyzers: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected 5 analyzers, got:\n%s", buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "returns ") || !strings.Contains(lines[4], "AtomicOp") {
+		t.Errorf("unexpected analyzer list:\n%s", buf.String())
+	}
+}
+
+func TestRunAnalyze_IncludeTests(t *testing.T) {
+	const pkg = "github.com/unbound-force/gaze/internal/analysis/testdata/src/testfiles"
+
+	err := runAnalyze(analyzeParams{pkgPath: pkg, format: "text", includeTests: true, classify: true, stdout: io.Discard, stderr: io.Discard})
+	if err == nil || !strings.Contains(err.Error(), "--include-tests cannot be combined") {
+		t.Errorf("expected --include-tests/--classify error, got %v", err)
+	}
+
+	var stdout bytes.Buffer
+	err = runAnalyze(analyzeParams{
+		pkgPath:      pkg,
+		format:       "text",
+		color:        "never",
+		includeTests: true,
+		stdout:       &stdout,
+		stderr:       io.Discard,
+	})
+	if err != nil {
+		t.Fatalf("runAnalyze: %v", err)
+	}
+	out := stdout.String()
+	for _, want := range []string{"=== Clamp ===", "=== SetupLimit (test) ===", "=== ResetLimit (test) ==="} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunAnalyze_GroupBy(t *testing.T) {
+	const pkg = "github.com/unbound-force/gaze/internal/analysis/testdata/src/p1effects"
+
+	err := runAnalyze(analyzeParams{pkgPath: pkg, format: "json", groupBy: "type", stdout: io.Discard, stderr: io.Discard})
+	if err == nil || !strings.Contains(err.Error(), "--group-by=type requires --format=text") {
+		t.Errorf("expected --group-by/--format error, got %v", err)
+	}
+	err = runAnalyze(analyzeParams{pkgPath: pkg, format: "text", groupBy: "package", stdout: io.Discard, stderr: io.Discard})
+	if err == nil || !strings.Contains(err.Error(), `invalid grouping "package"`) {
+		t.Errorf("expected invalid grouping error, got %v", err)
+	}
+
+	var stdout bytes.Buffer
+	err = runAnalyze(analyzeParams{
+		pkgPath: pkg,
+		format:  "text",
+		color:   "never",
+		groupBy: "type",
+		stdout:  &stdout,
+		stderr:  io.Discard,
+	})
+	if err != nil {
+		t.Fatalf("runAnalyze: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "=== GlobalMutation (P1) ===\n    MutateGlobal: ") {
+		t.Errorf("expected a GlobalMutation group listing MutateGlobal:\n%s", stdout.String())
+	}
+}
+
+func TestRunAnalyze_ListFunctions(t *testing.T) {
+	const pkg = "github.com/unbound-force/gaze/internal/analysis/testdata/src/mutation"
+
+	err := runAnalyze(analyzeParams{pkgPath: pkg, format: "text", listFunctions: true, classify: true, stdout: io.Discard, stderr: io.Discard})
+	if err == nil || !strings.Contains(err.Error(), "--list-functions requires") {
+		t.Errorf("expected --list-functions/--classify error, got %v", err)
+	}
+
+	var stdout bytes.Buffer
+	err = runAnalyze(analyzeParams{
+		pkgPath:       pkg,
+		format:        "text",
+		function:      "Counter.Increment",
+		listFunctions: true,
+		stdout:        &stdout,
+		stderr:        io.Discard,
+	})
+	if err != nil {
+		t.Fatalf("runAnalyze: %v", err)
+	}
+	out := stdout.String()
+	for _, want := range []string{pkg + ".(*Counter).Increment\t", "1 function(s) would be analyzed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "===") {
+		t.Errorf("expected no analysis output:\n%s", out)
+	}
+
+	stdout.Reset()
+	err = runAnalyze(analyzeParams{
+		pkgPath:       pkg,
+		format:        "json",
+		function:      "Counter.Increment",
+		listFunctions: true,
+		stdout:        &stdout,
+		stderr:        io.Discard,
+	})
+	if err != nil {
+		t.Fatalf("runAnalyze: %v", err)
+	}
+	var targets []taxonomy.FunctionTarget
+	if err := json.Unmarshal(stdout.Bytes(), &targets); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout.String())
+	}
+	if len(targets) != 1 || targets[0].Function != "Increment" || targets[0].Receiver != "*Counter" {
+		t.Errorf("unexpected targets: %+v", targets)
+	}
+}
+
+func TestRunDiff_Files(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, results []taxonomy.AnalysisResult) string {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := report.WriteJSON(&buf, results, "test"); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	target := taxonomy.FunctionTarget{Package: "example.com/m", Function: "Reset"}
+	effect := taxonomy.SideEffect{
+		ID:          "se-00000001",
+		Type:        taxonomy.GlobalMutation,
+		Tier:        taxonomy.TierP1,
+		Location:    "m.go:5:2",
+		Description: "assigns to package-level variable 'count'",
+		Target:      "count",
+	}
+	moved := effect
+	moved.ID, moved.Location = "se-00000002", "m.go:9:2"
+	oldPath := write("old.json", []taxonomy.AnalysisResult{{Target: target, SideEffects: []taxonomy.SideEffect{effect}}})
+	newPath := write("new.json", []taxonomy.AnalysisResult{{Target: target, SideEffects: []taxonomy.SideEffect{moved}}})
+
+	var stdout bytes.Buffer
+	if err := runDiff(diffParams{old: oldPath, current: newPath, format: "json", stdout: &stdout}); err != nil {
+		t.Fatalf("runDiff: %v", err)
+	}
+	var d report.EffectDiff
+	if err := json.Unmarshal(stdout.Bytes(), &d); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout.String())
+	}
+	if len(d.Added) != 1 || len(d.Removed) != 1 {
+		t.Errorf("id: expected one removal and one addition, got %+v", d)
+	}
+
+	stdout.Reset()
+	if err := runDiff(diffParams{old: oldPath, current: newPath, format: "text", match: "target", stdout: &stdout}); err != nil {
+		t.Fatalf("runDiff: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "0 added, 0 removed, 0 changed") {
+		t.Errorf("target: expected no differences:\n%s", stdout.String())
+	}
+
+	if err := runDiff(diffParams{old: oldPath, current: newPath, format: "text", match: "line", stdout: io.Discard}); err == nil {
+		t.Error("expected error for invalid --match")
+	}
+}
+
+func TestRunDiff_Ref(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com",
+			"GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("go.mod", "module example.com/m\n\ngo 1.24\n")
+	write("m.go", "package m\n\nvar count int\n\nfunc Count() int {\n\treturn count\n}\n")
+	git("init", "-q")
+	git("add", ".")
+	git("commit", "-q", "-m", "init")
+	write("m.go", "package m\n\nvar count int\n\nfunc Count() int {\n\tcount++\n\treturn count\n}\n")
+	t.Chdir(dir)
+
+	var stdout bytes.Buffer
+	if err := runDiff(diffParams{old: "HEAD", current: "./...", format: "text", stdout: &stdout}); err != nil {
+		t.Fatalf("runDiff: %v", err)
+	}
+	out := stdout.String()
+	for _, want := range []string{
+		"=== example.com/m.Count ===",
+		"  + GlobalMutation: modifies package-level variable 'count'",
+		"1 added, 0 removed, 0 changed side effect(s) in 1 function(s)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	// By ID, the return value moved a line and no longer matches.
+	stdout.Reset()
+	if err := runDiff(diffParams{old: "HEAD", current: "./...", format: "text", match: "id", stdout: &stdout}); err != nil {
+		t.Fatalf("runDiff: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "2 added, 1 removed, 0 changed") {
+		t.Errorf("id: expected the moved return value as removed and added:\n%s", stdout.String())
+	}
+
+	if err := runDiff(diffParams{old: "no-such-ref", current: "./...", format: "text", stdout: io.Discard}); err == nil {
+		t.Error("expected error for unknown ref")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Exit code tests
+// ---------------------------------------------------------------------------
+
+func TestRun_ExitCodes(t *testing.T) {
+	const pkg = "github.com/unbound-force/gaze/internal/analysis/testdata/src/p1effects"
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"success", []string{"analyze", "--format=json", pkg}, exitOK},
+		{"unknown flag", []string{"analyze", "--no-such-flag", pkg}, exitError},
+		{"invalid format", []string{"analyze", "--format=xml", pkg}, exitError},
+		{"load error", []string{"analyze", "github.com/unbound-force/gaze/does/not/exist"}, exitError},
+		{"gate violation", []string{"analyze", "--fail-on-type=GlobalMutation", pkg}, exitGate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stderr bytes.Buffer
+			if got := run(tt.args, io.Discard, &stderr); got != tt.want {
+				t.Errorf("run(%q) = %d, want %d; stderr:\n%s", tt.args, got, tt.want, stderr.String())
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	gate := &gateError{fmt.Errorf("CRAPload 3 exceeds maximum 1")}
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, exitOK},
+		{"plain error", fmt.Errorf("loading packages failed"), exitError},
+		{"gate", gate, exitGate},
+		{"wrapped gate", fmt.Errorf("crap: %w", gate), exitGate},
+		{"report threshold", &gateError{aireport.ErrThresholdFailed}, exitGate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
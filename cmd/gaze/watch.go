@@ -0,0 +1,151 @@
+// Package main implements the gaze CLI.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"golang.org/x/tools/go/packages"
+)
+
+// watchDebounce is how long analyze --watch waits after the last
+// change before re-running, so an editor's burst of writes on save
+// triggers one run.
+const watchDebounce = 250 * time.Millisecond
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\x1b[H\x1b[2J"
+
+// runAnalyzeWatch runs the analysis described by p, then re-runs it
+// whenever a .go file in the directories of the target packages
+// changes, until interrupted. When stdout is a terminal the screen is
+// cleared before each run. Analysis errors are printed and watching
+// continues; only failing to set up the watcher is returned.
+func runAnalyzeWatch(p analyzeParams) error {
+	p.watch = false
+
+	dirs, err := watchDirs(p.pkgPath)
+	if err != nil {
+		return fmt.Errorf("--watch: %w", err)
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("--watch: %w", err)
+	}
+	defer func() { _ = watcher.Close() }()
+	for _, dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			return fmt.Errorf("--watch: watching %s: %w", dir, err)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	clearFirst := isTerminal(p.stdout)
+	watchLoop(ctx, watcher.Events, watcher.Errors, watchDebounce, func() {
+		if clearFirst {
+			_, _ = fmt.Fprint(p.stdout, clearScreen)
+		}
+		if err := runAnalyze(p); err != nil {
+			_, _ = fmt.Fprintf(p.stderr, "Error: %v\n", err)
+		}
+		_, _ = fmt.Fprintf(p.stderr, "\nwatching %s for changes (Ctrl+C to stop)\n", p.pkgPath)
+	})
+	return nil
+}
+
+// watchLoop calls run once, then again each time events delivers a
+// change to a .go file and debounce passes without another one.
+// Watcher errors are logged. It returns when ctx is done or events
+// is closed.
+func watchLoop(
+	ctx context.Context,
+	events <-chan fsnotify.Event,
+	errs <-chan error,
+	debounce time.Duration,
+	run func(),
+) {
+	run()
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if isSourceChange(ev) {
+				timer.Reset(debounce)
+			}
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			logger.Warn("watch error", "err", err)
+		case <-timer.C:
+			run()
+		}
+	}
+}
+
+// isSourceChange reports whether ev changes a .go file. Attribute
+// changes and editor swap or backup files are ignored.
+func isSourceChange(ev fsnotify.Event) bool {
+	if !strings.HasSuffix(ev.Name, ".go") {
+		return false
+	}
+	return ev.Has(fsnotify.Write) || ev.Has(fsnotify.Create) ||
+		ev.Has(fsnotify.Remove) || ev.Has(fsnotify.Rename)
+}
+
+// watchDirs returns the sorted directories holding the packages that
+// pkgPath matches, including files excluded by build constraints.
+func watchDirs(pkgPath string) ([]string, error) {
+	cfg := &packages.Config{Mode: packages.NeedName | packages.NeedFiles}
+	pkgs, err := packages.Load(cfg, pkgPath)
+	if err != nil {
+		return nil, fmt.Errorf("resolving %s: %w", pkgPath, err)
+	}
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, pkg := range pkgs {
+		files := append(append([]string{}, pkg.GoFiles...), pkg.IgnoredFiles...)
+		for _, f := range files {
+			dir := filepath.Dir(f)
+			if !seen[dir] {
+				seen[dir] = true
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no Go files found for %s", pkgPath)
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// isTerminal reports whether w is a character device such as a
+// terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
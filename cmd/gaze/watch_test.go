@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+func TestWatchLoop_Debounce(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan fsnotify.Event)
+	runs := make(chan struct{}, 10)
+	done := make(chan struct{})
+	go func() {
+		watchLoop(ctx, events, nil, 50*time.Millisecond, func() { runs <- struct{}{} })
+		close(done)
+	}()
+
+	<-runs // initial run
+	// A burst of saves triggers a single re-run; non-Go files and
+	// attribute changes are ignored.
+	events <- fsnotify.Event{Name: "pkg/a.go", Op: fsnotify.Write}
+	events <- fsnotify.Event{Name: "pkg/a.go", Op: fsnotify.Write}
+	events <- fsnotify.Event{Name: "pkg/b.go", Op: fsnotify.Create}
+	events <- fsnotify.Event{Name: "pkg/a.go.swp", Op: fsnotify.Write}
+	events <- fsnotify.Event{Name: "pkg/a.go", Op: fsnotify.Chmod}
+
+	select {
+	case <-runs:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a re-run after the burst of changes")
+	}
+	select {
+	case <-runs:
+		t.Fatal("expected one re-run for the burst, got two")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	events <- fsnotify.Event{Name: "pkg/a.go.swp", Op: fsnotify.Write}
+	events <- fsnotify.Event{Name: "pkg/a.go", Op: fsnotify.Chmod}
+	select {
+	case <-runs:
+		t.Fatal("expected ignored events not to trigger a run")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("watchLoop did not return after cancel")
+	}
+}
+
+func TestWatchDirs(t *testing.T) {
+	dirs, err := watchDirs("github.com/unbound-force/gaze/internal/analysis/testdata/src/buildtags")
+	if err != nil {
+		t.Fatalf("watchDirs: %v", err)
+	}
+	if len(dirs) != 1 || !strings.HasSuffix(filepath.ToSlash(dirs[0]), "testdata/src/buildtags") {
+		t.Errorf("watchDirs = %v, want the buildtags fixture directory", dirs)
+	}
+
+	if _, err := watchDirs("github.com/unbound-force/gaze/nonexistent"); err == nil {
+		t.Error("expected error for a package with no files")
+	}
+}
+
+func TestRunAnalyze_WatchInteractive(t *testing.T) {
+	err := runAnalyze(analyzeParams{
+		pkgPath:     "github.com/unbound-force/gaze/internal/analysis/testdata/src/p1effects",
+		format:      "text",
+		watch:       true,
+		interactive: true,
+		stdout:      io.Discard,
+		stderr:      io.Discard,
+	})
+	if err == nil || !strings.Contains(err.Error(), "--watch cannot be combined with --interactive") {
+		t.Errorf("expected --watch/--interactive error, got %v", err)
+	}
+}
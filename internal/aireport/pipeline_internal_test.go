@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/unbound-force/gaze/internal/crap"
+	"github.com/unbound-force/gaze/internal/loader"
 )
 
 // fakeSteps returns a pipelineStepFuncs with all four steps returning
@@ -306,10 +307,10 @@ func TestRunProductionPipeline_SummaryFields(t *testing.T) {
 }
 
 // TestResolveModulePackages_EmptyDir verifies that resolveModulePackages
-// with an empty moduleDir uses the current working directory and
+// with a session for an empty moduleDir uses the current working directory and
 // successfully loads module packages (when run from a Go module root).
 func TestResolveModulePackages_EmptyDir(t *testing.T) {
-	pkgs := resolveModulePackages("")
+	pkgs := resolveModulePackages(loader.NewSession(""))
 	// When run from the gaze module root, this should return packages.
 	// In CI, the working directory is the repo root.
 	if pkgs == nil {
@@ -323,7 +324,7 @@ func TestResolveModulePackages_EmptyDir(t *testing.T) {
 // TestResolveModulePackages_InvalidDir verifies that resolveModulePackages
 // returns nil for a directory that is not a Go module.
 func TestResolveModulePackages_InvalidDir(t *testing.T) {
-	pkgs := resolveModulePackages(t.TempDir())
+	pkgs := resolveModulePackages(loader.NewSession(t.TempDir()))
 	if pkgs != nil {
 		t.Errorf("expected nil for non-module directory, got %d packages", len(pkgs))
 	}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -12,6 +13,10 @@ import (
 	"github.com/unbound-force/gaze/internal/crap"
 )
 
+// ErrThresholdFailed is returned by Run when one or more configured
+// quality thresholds are not met.
+var ErrThresholdFailed = errors.New("one or more quality thresholds failed")
+
 // RunnerOptions configures the report pipeline runner.
 type RunnerOptions struct {
 	// Patterns is the package pattern(s) to analyze (e.g., "./...").
@@ -180,7 +185,7 @@ func evaluateAndPrintThresholds(cfg ThresholdConfig, payload *ReportPayload, std
 		_, _ = fmt.Fprintf(stderr, "%s: %d/%d (%s)\n", r.Name, r.Actual, r.Limit, status)
 	}
 	if !allPassed {
-		return fmt.Errorf("one or more quality thresholds failed")
+		return ErrThresholdFailed
 	}
 	return nil
 }
@@ -240,7 +245,11 @@ func runProductionPipeline(patterns []string, moduleDir string, coverProfile str
 	// Build contract coverage callback for GazeCRAP scoring (spec 022).
 	// This runs the quality pipeline per-package to build a lookup
 	// closure. Best-effort: returns nil if all packages fail.
-	ccFunc, degradedPkgs := crap.BuildContractCoverageFunc(patterns, moduleDir, stderr)
+	gazeConfig, err := loadGazeConfig(moduleDir)
+	if err != nil {
+		return nil, err
+	}
+	ccFunc, degradedPkgs := crap.BuildContractCoverageFunc(patterns, moduleDir, gazeConfig, stderr)
 	if len(degradedPkgs) > 0 {
 		payload.Summary.SSADegraded = true
 		payload.Summary.SSADegradedPackages = append(
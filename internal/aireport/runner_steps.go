@@ -4,8 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"os"
-	"path/filepath"
 	"strings"
 
 	"golang.org/x/tools/go/packages"
@@ -91,15 +89,19 @@ func runQualityStep(patterns []string, moduleDir string, stderr io.Writer) (*qua
 		return nil, fmt.Errorf("no packages matched patterns %v", patterns)
 	}
 
-	gazeConfig := loadGazeConfigBestEffort()
+	gazeConfig, err := loadGazeConfig(moduleDir)
+	if err != nil {
+		return nil, err
+	}
 
-	// Hoist LoadModule out of the per-package loop — O(1) instead of O(n).
-	modPkgs := resolveModulePackages(moduleDir)
+	// Load the module once, outside the per-package loop — O(1)
+	// instead of O(n). Each package is served from the same load.
+	session := loader.NewSession(moduleDir)
 
 	var allReports []taxonomy.QualityReport
 	var degradedPkgs []string
 	for _, pkgPath := range pkgPaths {
-		reports, degradedPkg := runQualityForPackage(pkgPath, gazeConfig, modPkgs, stderr)
+		reports, degradedPkg := runQualityForPackage(pkgPath, gazeConfig, session, stderr)
 		if degradedPkg != "" {
 			degradedPkgs = append(degradedPkgs, degradedPkg)
 		}
@@ -131,14 +133,15 @@ func runQualityStep(patterns []string, moduleDir string, stderr io.Writer) (*qua
 }
 
 // runQualityForPackage runs the quality pipeline on a single package.
-// modPkgs should be pre-resolved by the caller (hoist LoadModule out of loops).
+// session should be shared by the caller across packages so the module
+// is loaded once.
 // Returns (nil, "") if the package has no tests or analysis fails.
 // The second return value is the degraded package path (empty string
 // if not degraded, package path if SSA construction failed).
 func runQualityForPackage(
 	pkgPath string,
 	gazeConfig *config.GazeConfig,
-	modPkgs []*packages.Package,
+	session *loader.Session,
 	stderr io.Writer,
 ) ([]taxonomy.QualityReport, string) {
 	includeUnexported := isMainPkg(pkgPath)
@@ -146,14 +149,18 @@ func runQualityForPackage(
 		_, _ = fmt.Fprintf(stderr, "package main detected for %s, including unexported functions\n", pkgPath)
 	}
 	analysisOpts := analysis.Options{IncludeUnexported: includeUnexported}
-	results, err := analysis.LoadAndAnalyze(pkgPath, analysisOpts)
+	target, err := session.Load(pkgPath)
+	if err != nil {
+		return nil, ""
+	}
+	results, err := analysis.Analyze(target.Pkg, analysisOpts)
 	if err != nil || len(results) == 0 {
 		return nil, ""
 	}
 
 	cfg := gazeConfig
-	classified, err := runClassifyResults(results, pkgPath, cfg, modPkgs)
-	if err != nil || len(classified) == 0 {
+	classified := runClassifyResults(results, target.Pkg, cfg, resolveModulePackages(session))
+	if len(classified) == 0 {
 		return nil, ""
 	}
 
@@ -163,6 +170,9 @@ func runQualityForPackage(
 	}
 
 	qualOpts := quality.Options{Stderr: stderr}
+	if cfg != nil {
+		qualOpts.AssertionHelpers = cfg.Quality.AssertionHelpers
+	}
 	reports, summary, err := quality.Assess(classified, testPkg, qualOpts)
 	if err != nil {
 		return nil, ""
@@ -193,22 +203,27 @@ func runClassifyStep(patterns []string, moduleDir string) (*classifyStepResult,
 		return nil, fmt.Errorf("no packages matched patterns %v", patterns)
 	}
 
-	// Hoist LoadModule out of the per-package loop — O(1) instead of O(n).
-	modPkgs := resolveModulePackages(moduleDir)
+	// Load the module once, outside the per-package loop — O(1)
+	// instead of O(n). Each package is served from the same load.
+	session := loader.NewSession(moduleDir)
 
-	gazeConfig := loadGazeConfigBestEffort()
+	gazeConfig, err := loadGazeConfig(moduleDir)
+	if err != nil {
+		return nil, err
+	}
 	var allResults []taxonomy.AnalysisResult
 
 	for _, pkgPath := range pkgPaths {
 		analysisOpts := analysis.Options{IncludeUnexported: isMainPkg(pkgPath)}
-		results, err := analysis.LoadAndAnalyze(pkgPath, analysisOpts)
-		if err != nil || len(results) == 0 {
+		target, err := session.Load(pkgPath)
+		if err != nil {
 			continue
 		}
-		classified, err := runClassifyResults(results, pkgPath, gazeConfig, modPkgs)
-		if err != nil {
+		results, err := analysis.Analyze(target.Pkg, analysisOpts)
+		if err != nil || len(results) == 0 {
 			continue
 		}
+		classified := runClassifyResults(results, target.Pkg, gazeConfig, resolveModulePackages(session))
 		allResults = append(allResults, classified...)
 	}
 
@@ -230,7 +245,10 @@ func runClassifyStep(patterns []string, moduleDir string) (*classifyStepResult,
 
 // runDocscanStep runs the documentation scanner and returns the JSON output.
 func runDocscanStep(moduleDir string) (json.RawMessage, error) {
-	cfg := loadGazeConfigBestEffort()
+	cfg, err := loadGazeConfig(moduleDir)
+	if err != nil {
+		return nil, err
+	}
 	scanOpts := docscan.ScanOptions{Config: cfg}
 
 	docs, err := docscan.Scan(moduleDir, scanOpts)
@@ -267,59 +285,44 @@ func resolvePackagePaths(patterns []string, moduleDir string) ([]string, error)
 	return pkgPaths, nil
 }
 
-// runClassifyResults runs the mechanical classification pipeline.
-// modPkgs must be pre-resolved by the caller via resolveModulePackages to
-// avoid calling loader.LoadModule inside a per-package loop (O(n) → O(1)).
+// runClassifyResults runs the mechanical classification pipeline on
+// the results analyzed from target. modPkgs should come from the
+// caller's shared session via resolveModulePackages, so the module is
+// loaded once rather than per package.
 func runClassifyResults(
 	results []taxonomy.AnalysisResult,
-	pkgPath string,
+	target *packages.Package,
 	cfg *config.GazeConfig,
 	modPkgs []*packages.Package,
-) ([]taxonomy.AnalysisResult, error) {
-	targetResult, err := loader.Load(pkgPath)
-	if err != nil {
-		return nil, fmt.Errorf("loading target package for classification: %w", err)
-	}
-
+) []taxonomy.AnalysisResult {
 	clOpts := classify.Options{
 		Config:         cfg,
 		ModulePackages: modPkgs,
-		TargetPkg:      targetResult.Pkg,
+		TargetPkg:      target,
 	}
-	return classify.Classify(results, clOpts), nil
+	return classify.Classify(results, clOpts)
 }
 
-// resolveModulePackages loads all module packages from moduleDir for use in
-// classification. Returns nil (not an error) if loading fails, so callers can
-// degrade gracefully.
-func resolveModulePackages(moduleDir string) []*packages.Package {
-	if moduleDir == "" {
-		var err error
-		moduleDir, err = os.Getwd()
-		if err != nil {
-			return nil
-		}
-	}
-	modResult, err := loader.LoadModule(moduleDir)
+// resolveModulePackages returns the module packages loaded by session
+// for use in classification. Returns nil (not an error) if loading
+// fails, so callers can degrade gracefully.
+func resolveModulePackages(session *loader.Session) []*packages.Package {
+	modResult, err := session.Module()
 	if err != nil {
 		return nil
 	}
 	return modResult.Packages
 }
 
-// loadGazeConfigBestEffort loads the GazeConfig from cwd, falling back to
-// the default config on any error.
-func loadGazeConfigBestEffort() *config.GazeConfig {
-	cwd, err := os.Getwd()
-	if err != nil {
-		return config.DefaultConfig()
-	}
-	cfgPath := filepath.Join(cwd, ".gaze.yaml")
-	cfg, err := config.Load(cfgPath)
+// loadGazeConfig discovers the GazeConfig from moduleDir up to the
+// module root. It returns the default config when there is no
+// .gaze.yaml, and an error when one exists but cannot be loaded.
+func loadGazeConfig(moduleDir string) (*config.GazeConfig, error) {
+	cfg, _, err := config.Discover(moduleDir)
 	if err != nil {
-		return config.DefaultConfig()
+		return nil, fmt.Errorf("loading config: %w", err)
 	}
-	return cfg
+	return cfg, nil
 }
 
 // loadTestPackageForQuality loads a Go package with test files included.
@@ -2,8 +2,10 @@ package aireport
 
 import (
 	"io"
+	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -46,12 +48,37 @@ func TestResolvePackagePaths_EmptyPatterns(t *testing.T) {
 	}
 }
 
-// TestLoadGazeConfigBestEffort_AlwaysNonNil verifies that the function always
-// returns a non-nil config, even in a directory with no .gaze.yaml.
-func TestLoadGazeConfigBestEffort_AlwaysNonNil(t *testing.T) {
-	cfg := loadGazeConfigBestEffort()
+// TestLoadGazeConfig_NoFile verifies that a directory with no
+// .gaze.yaml yields the default config.
+func TestLoadGazeConfig_NoFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/m\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := loadGazeConfig(dir)
+	if err != nil {
+		t.Fatalf("loadGazeConfig: %v", err)
+	}
 	if cfg == nil {
-		t.Error("expected non-nil GazeConfig from loadGazeConfigBestEffort")
+		t.Error("expected non-nil GazeConfig from loadGazeConfig")
+	}
+}
+
+// TestLoadGazeConfig_Malformed verifies that a .gaze.yaml that fails
+// to parse is reported rather than replaced by the defaults.
+func TestLoadGazeConfig_Malformed(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/m\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".gaze.yaml"), []byte("classification: [\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadGazeConfig(dir); err == nil || !strings.Contains(err.Error(), "loading config") {
+		t.Errorf("expected config load error, got %v", err)
+	}
+	if _, err := runDocscanStep(dir); err == nil {
+		t.Error("expected runDocscanStep to fail on a malformed config")
 	}
 }
 
@@ -1,13 +1,24 @@
 package analysis_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"testing"
 
 	"github.com/unbound-force/gaze/internal/analysis"
+	"github.com/unbound-force/gaze/internal/loader"
 	"github.com/unbound-force/gaze/internal/taxonomy"
 	"golang.org/x/tools/go/packages"
 	"golang.org/x/tools/go/ssa"
@@ -363,6 +374,113 @@ func TestSentinels_WrappedDetection(t *testing.T) {
 	}
 }
 
+func TestSentinels_ErrorTypedVarsAndTypes(t *testing.T) {
+	pkg := loadTestPackage(t, "sentinel")
+
+	results, err := analysis.Analyze(pkg, analysis.Options{
+		IncludeUnexported: true,
+	})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	sentinels := make(map[string]taxonomy.SideEffect)
+	for _, r := range results {
+		for _, e := range r.SideEffects {
+			if e.Type == taxonomy.SentinelError {
+				sentinels[e.Target] = e
+			}
+		}
+	}
+
+	tests := []struct {
+		target   string
+		detected bool
+		desc     string
+	}{
+		{"ErrTimeout", true, "(error-typed var)"},
+		{"ErrClosed", true, "(error-typed var)"},
+		{"NotFoundError", true, "exported error type"},
+		{"ErrRetries", false, ""},
+		{"timeoutError", false, ""},
+		{"Options", false, ""},
+		// errors.New sentinels keep their plain description.
+		{"ErrNotFound", true, "sentinel error 'ErrNotFound'"},
+		{"ErrWrapped", true, taxonomy.SentinelWrapSuffix},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			e, ok := sentinels[tt.target]
+			if ok != tt.detected {
+				t.Fatalf("detected = %v, want %v", ok, tt.detected)
+			}
+			if ok && !strings.Contains(e.Description, tt.desc) {
+				t.Errorf("description %q does not contain %q", e.Description, tt.desc)
+			}
+		})
+	}
+}
+
+func TestSentinels_NilTypesInfo(t *testing.T) {
+	pkg := loadTestPackage(t, "sentinel")
+
+	// Without type information only call-initialized sentinels are
+	// recognized.
+	var targets []string
+	for _, file := range pkg.Syntax {
+		for _, e := range analysis.AnalyzeSentinels(pkg.Fset, nil, file, pkg.PkgPath) {
+			targets = append(targets, e.Target)
+		}
+	}
+	for _, target := range targets {
+		if target == "ErrTimeout" || target == "NotFoundError" {
+			t.Errorf("%s should not be detected without type information", target)
+		}
+	}
+	if len(targets) != 4 {
+		t.Errorf("got %d sentinels without type information, want 4: %v", len(targets), targets)
+	}
+}
+
+func TestSentinels_DuplicateMessages(t *testing.T) {
+	pkg := loadTestPackage(t, "dupsentinel")
+
+	results, err := analysis.Analyze(pkg, analysis.Options{})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	closed := `sentinel: 'ErrClosed', 'ErrShutdown' share the message "closed"`
+	missing := `sentinel: 'ErrMissing', 'ErrGone' share the message "missing"`
+	want := map[string][]string{
+		"dupsentinel.go": {closed, missing},
+		"other.go":       {closed},
+	}
+	for _, r := range results {
+		if r.Target.Function != "<package>" {
+			continue
+		}
+		file := filepath.Base(r.Target.Location)
+		got := r.Metadata.Warnings
+		if len(got) != len(want[file]) {
+			t.Errorf("%s: got warnings %q, want %d", file, got, len(want[file]))
+			continue
+		}
+		for i, prefix := range want[file] {
+			if !strings.HasPrefix(got[i], prefix) {
+				t.Errorf("%s: warning %d = %q, want prefix %q", file, i, got[i], prefix)
+			}
+			if strings.Contains(got[i], "ErrUnique") || strings.Contains(got[i], "ErrClosedWrapped") {
+				t.Errorf("%s: warning %q names a sentinel with a distinct message", file, got[i])
+			}
+		}
+		delete(want, file)
+	}
+	for file := range want {
+		t.Errorf("no sentinel result for %s", file)
+	}
+}
+
 // --- Mutation Analyzer Tests ---
 
 func TestMutation_PointerReceiverIncrement(t *testing.T) {
@@ -467,6 +585,68 @@ func TestMutation_DeepNestedMutation(t *testing.T) {
 	}
 }
 
+func TestMutation_ReceiverSliceAndMapFields(t *testing.T) {
+	tests := []struct {
+		method      string
+		field       string
+		description string
+	}{
+		{"Add", "items", "grows receiver slice field 'items' (append)"},
+		{"Put", "cache", "writes to receiver map field 'cache'"},
+		{"Record", "inner", "grows receiver slice field 'inner' (append)"},
+		{"Replace", "items", "mutates receiver field 'items'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			result := analyzeMethod(t, "mutation", "*Store", tt.method)
+
+			if n := countEffects(result.SideEffects, taxonomy.ReceiverMutation); n != 1 {
+				t.Fatalf("expected 1 ReceiverMutation, got %d: %v", n, result.SideEffects)
+			}
+			e := effectWithTarget(result.SideEffects, taxonomy.ReceiverMutation, tt.field)
+			if e == nil {
+				t.Fatalf("expected ReceiverMutation for field '%s'", tt.field)
+			}
+			if e.Description != tt.description {
+				t.Errorf("description = %q, want %q", e.Description, tt.description)
+			}
+		})
+	}
+}
+
+func TestMutation_EmbeddedPointerFields(t *testing.T) {
+	tests := []struct {
+		recv, method string
+		field        string
+		description  string
+	}{
+		{"*Outer", "Set", "Inner.Field", "mutates receiver field 'Inner.Field'"},
+		{"*Outer", "SetExplicit", "Inner.Field", "mutates receiver field 'Inner.Field'"},
+		{"*Outer", "AddItem", "Inner.items", "grows receiver slice field 'Inner.items' (append)"},
+		{"*Outer", "SetDeep", "Inner.Deep.Level", "mutates receiver field 'Inner.Deep.Level'"},
+		{"*Outer", "SetInner", "Inner", "mutates receiver field 'Inner'"},
+		// Value embedding keeps the top-level field, as for any
+		// nested struct.
+		{"*Wrapper", "SetID", "Base", "mutates receiver field 'Base'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			result := analyzeMethod(t, "mutation", tt.recv, tt.method)
+
+			if n := countEffects(result.SideEffects, taxonomy.ReceiverMutation); n != 1 {
+				t.Fatalf("expected 1 ReceiverMutation, got %d: %v", n, result.SideEffects)
+			}
+			e := effectWithTarget(result.SideEffects, taxonomy.ReceiverMutation, tt.field)
+			if e == nil {
+				t.Fatalf("expected ReceiverMutation for field '%s', got %v", tt.field, result.SideEffects)
+			}
+			if e.Description != tt.description {
+				t.Errorf("description = %q, want %q", e.Description, tt.description)
+			}
+		})
+	}
+}
+
 // --- Analysis Metadata Tests ---
 
 func TestAnalysis_MetadataPopulated(t *testing.T) {
@@ -480,6 +660,328 @@ func TestAnalysis_MetadataPopulated(t *testing.T) {
 	}
 }
 
+func TestAnalysis_WarningsForIncompleteAnalysis(t *testing.T) {
+	tests := []struct {
+		funcName string
+		want     string // substring of the single expected warning; "" for none
+	}{
+		{"AsmAdd", "no Go body"},
+		{"ReflectSet", "reflect.Value"},
+		{"ReflectInspect", ""},
+		{"DotExit", "dot-imported"},
+		{"Plain", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.funcName, func(t *testing.T) {
+			result := analyzeFunc(t, "incomplete", tt.funcName)
+			warnings := result.Metadata.Warnings
+			if tt.want == "" {
+				if len(warnings) != 0 {
+					t.Errorf("expected no warnings, got %v", warnings)
+				}
+				return
+			}
+			if len(warnings) != 1 || !strings.Contains(warnings[0], tt.want) {
+				t.Errorf("expected one warning containing %q, got %v", tt.want, warnings)
+			}
+		})
+	}
+}
+
+func TestAnalysis_LoopCaptureWarnings(t *testing.T) {
+	results, err := analysis.LoadAndAnalyze(testdataPath("loopvar"), analysis.Options{})
+	if err != nil {
+		t.Fatalf("LoadAndAnalyze failed: %v", err)
+	}
+	tests := []struct {
+		funcName string
+		want     string // substring of the single expected warning; "" for none
+	}{
+		{"RangeCapture", "captures loop variable v"},
+		{"ForCapture", "captures loop variable i"},
+		{"NestedCapture", "captures loop variable k"},
+		{"PassedAsArgument", ""},
+		{"Shadowed", ""},
+		{"NoGoroutine", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.funcName, func(t *testing.T) {
+			var warnings []string
+			for _, r := range results {
+				if r.Target.Function == tt.funcName {
+					warnings = r.Metadata.Warnings
+				}
+			}
+			if tt.want == "" {
+				if len(warnings) != 0 {
+					t.Errorf("expected no warnings, got %v", warnings)
+				}
+				return
+			}
+			if len(warnings) != 1 || !strings.Contains(warnings[0], tt.want) ||
+				!strings.Contains(warnings[0], "go 1.21") {
+				t.Errorf("expected one warning containing %q, got %v", tt.want, warnings)
+			}
+		})
+	}
+}
+
+func TestAnalysis_LoopCaptureWarnings_Go122(t *testing.T) {
+	// The same code in a module on Go 1.22 or later gives every
+	// iteration its own variable, so nothing is reported.
+	dir := t.TempDir()
+	src, err := os.ReadFile(filepath.Join(testdataPath("loopvar"), "loopvar.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "loopvar.go"), src, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	goMod := "module example.com/loopvar\n\ngo 1.22\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	results, err := analysis.LoadAndAnalyze(dir, analysis.Options{})
+	if err != nil {
+		t.Fatalf("LoadAndAnalyze failed: %v", err)
+	}
+	for _, r := range results {
+		if len(r.Metadata.Warnings) != 0 {
+			t.Errorf("%s: expected no warnings on go 1.22, got %v", r.Target.Function, r.Metadata.Warnings)
+		}
+	}
+}
+
+func TestLoadAndAnalyze_Build(t *testing.T) {
+	tests := []struct {
+		name  string
+		build loader.Build
+		want  []string
+	}{
+		{"default", loader.Build{}, []string{"Always"}},
+		{"tag", loader.Build{Tags: []string{"gazetag"}}, []string{"Always", "Tagged"}},
+		{"goos", loader.Build{GOOS: "plan9", GOARCH: "amd64"}, []string{"Always", "OnPlan9"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := analysis.LoadAndAnalyze(testdataPath("buildtags"), analysis.Options{Build: tt.build})
+			if err != nil {
+				t.Fatalf("LoadAndAnalyze failed: %v", err)
+			}
+			var got []string
+			for _, r := range results {
+				got = append(got, r.Target.Function)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("analyzed functions = %v, want %v", got, tt.want)
+			}
+			if tt.name == "tag" && !hasEffect(results[1].SideEffects, taxonomy.GlobalMutation) {
+				t.Error("expected GlobalMutation for Tagged")
+			}
+		})
+	}
+}
+
+func TestAnalyze_IncludesBodylessFunctions(t *testing.T) {
+	pkg := loadTestPackage(t, "incomplete")
+	results, err := analysis.Analyze(pkg, analysis.Options{FunctionFilter: "AsmAdd"})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result for AsmAdd, got %d", len(results))
+	}
+	// The signature still yields the return value effect, and the
+	// warning explains why nothing else was found.
+	if !hasEffect(results[0].SideEffects, taxonomy.ReturnValue) {
+		t.Error("expected ReturnValue effect from the signature")
+	}
+	if len(results[0].Metadata.Warnings) != 1 {
+		t.Errorf("expected the no-body warning, got %v", results[0].Metadata.Warnings)
+	}
+}
+
+func TestIsTriviallyPure(t *testing.T) {
+	pkg := loadTestPackage(t, "returns")
+	tests := []struct {
+		funcName string
+		want     bool
+	}{
+		{"PureFunction", true},
+		{"SingleReturn", true},
+		{"MultipleReturns", true},
+		{"ErrorReturn", true},
+		{"NamedReturnModifiedInDefer", false},
+		{"NamedReturnIncrementedInDefer", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.funcName, func(t *testing.T) {
+			fd := analysis.FindFuncDecl(pkg, tt.funcName)
+			if fd == nil {
+				t.Fatalf("%s not found", tt.funcName)
+			}
+			if got := analysis.IsTriviallyPure(pkg.TypesInfo, fd); got != tt.want {
+				t.Errorf("IsTriviallyPure(%s) = %v, want %v", tt.funcName, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestAnalyze_PureFastPathMatchesFullPath verifies that the fast path
+// for trivially pure functions reports exactly what the full set of
+// analyzers does, for every function in the fixtures.
+func TestAnalyze_PureFastPathMatchesFullPath(t *testing.T) {
+	pkgNames := []string{"returns", "sentinel", "mutation", "p1effects", "p2effects", "p3effects"}
+	pure := 0
+	for _, pkgName := range pkgNames {
+		pkg := loadTestPackage(t, pkgName)
+		opts := analysis.Options{IncludeUnexported: true, Workers: 1}
+
+		fast, err := analysis.Analyze(pkg, opts)
+		if err != nil {
+			t.Fatalf("Analyze(%q) failed: %v", pkgName, err)
+		}
+		restore := analysis.SetFastPath(false)
+		full, err := analysis.Analyze(pkg, opts)
+		restore()
+		if err != nil {
+			t.Fatalf("Analyze(%q) without fast path failed: %v", pkgName, err)
+		}
+
+		if len(fast) != len(full) {
+			t.Fatalf("%s: %d results with fast path, %d without", pkgName, len(fast), len(full))
+		}
+		for i := range fast {
+			name := fast[i].Target.QualifiedName()
+			if !reflect.DeepEqual(fast[i].SideEffects, full[i].SideEffects) {
+				t.Errorf("%s: fast path effects %+v, full path %+v",
+					name, fast[i].SideEffects, full[i].SideEffects)
+			}
+			if !reflect.DeepEqual(fast[i].Metadata.Warnings, full[i].Metadata.Warnings) {
+				t.Errorf("%s: fast path warnings %v, full path %v",
+					name, fast[i].Metadata.Warnings, full[i].Metadata.Warnings)
+			}
+			if fd := analysis.FindFuncDecl(pkg, fast[i].Target.Function); fd != nil &&
+				fast[i].Target.Receiver == "" && analysis.IsTriviallyPure(pkg.TypesInfo, fd) {
+				pure++
+			}
+		}
+	}
+	if pure == 0 {
+		t.Error("expected the fixtures to contain trivially pure functions")
+	}
+}
+
+func TestAnalyzeContext_Canceled(t *testing.T) {
+	pkg := loadTestPackage(t, "returns")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := analysis.AnalyzeContext(ctx, pkg, analysis.Options{IncludeUnexported: true})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "not analyzed") {
+		t.Errorf("error %q should say how many functions were not analyzed", err)
+	}
+	for _, r := range results {
+		if r.Target.Function != "<package>" {
+			t.Errorf("unexpected result for %s after cancellation", r.Target.QualifiedName())
+		}
+	}
+
+	full, err := analysis.AnalyzeContext(context.Background(), pkg, analysis.Options{IncludeUnexported: true})
+	if err != nil {
+		t.Fatalf("AnalyzeContext failed: %v", err)
+	}
+	for _, r := range full {
+		for _, w := range r.Metadata.Warnings {
+			if strings.Contains(w, "results are partial") {
+				t.Errorf("%s: unexpected partial warning on a complete run", r.Target.QualifiedName())
+			}
+		}
+	}
+}
+
+func TestFuncDeclsInLines(t *testing.T) {
+	const src = `package m
+
+// A is documented.
+func A() {
+	_ = 1
+}
+
+func B() {}
+
+var v = 1
+
+func C() {
+}
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "m.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		lines []int
+		want  []string
+	}{
+		{"doc comment", []int{3}, []string{"A"}},
+		{"body", []int{5}, []string{"A"}},
+		{"between functions", []int{7, 10}, nil},
+		{"several", []int{8, 13}, []string{"B", "C"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := make(map[int]bool)
+			for _, l := range tt.lines {
+				lines[l] = true
+			}
+			var got []string
+			for _, fd := range analysis.FuncDeclsInLines(fset, f, lines) {
+				got = append(got, fd.Name.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FuncDeclsInLines(%v) = %v, want %v", tt.lines, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyze_ChangedLines(t *testing.T) {
+	pkg := loadTestPackage(t, "p1effects")
+	file := filepath.Join(testdataPath("p1effects"), "p1effects.go")
+
+	// Line 16 is inside MutateGlobal's body.
+	results, err := analysis.Analyze(pkg, analysis.Options{
+		ChangedLines: map[string]map[int]bool{file: {16: true}},
+	})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Target.Function != "MutateGlobal" {
+		var names []string
+		for _, r := range results {
+			names = append(names, r.Target.Function)
+		}
+		t.Errorf("expected only MutateGlobal, got %v", names)
+	}
+
+	// No changed lines means nothing to analyze.
+	results, err = analysis.Analyze(pkg, analysis.Options{
+		ChangedLines: map[string]map[int]bool{},
+	})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected no results without changes, got %d", len(results))
+	}
+}
+
 func TestAnalysis_TargetPopulated(t *testing.T) {
 	result := analyzeFunc(t, "returns", "SingleReturn")
 
@@ -529,6 +1031,101 @@ func TestAnalysis_StableIDs(t *testing.T) {
 	}
 }
 
+func TestAnalysis_UniqueIDsOnSharedPosition(t *testing.T) {
+	result := analyzeFunc(t, "returns", "Bounds")
+
+	var returns []taxonomy.SideEffect
+	for _, e := range result.SideEffects {
+		if e.Type == taxonomy.ReturnValue {
+			returns = append(returns, e)
+		}
+	}
+	if len(returns) != 2 {
+		t.Fatalf("expected 2 ReturnValue effects, got %d", len(returns))
+	}
+	if returns[0].Location != returns[1].Location {
+		t.Fatalf("expected both results at one position, got %s and %s",
+			returns[0].Location, returns[1].Location)
+	}
+	if returns[0].ID == returns[1].ID {
+		t.Errorf("effects at the same position share ID %s", returns[0].ID)
+	}
+
+	again := analyzeFunc(t, "returns", "Bounds")
+	for i := range result.SideEffects {
+		if result.SideEffects[i].ID != again.SideEffects[i].ID {
+			t.Errorf("unstable ID for effect %d: %q vs %q",
+				i, result.SideEffects[i].ID, again.SideEffects[i].ID)
+		}
+	}
+}
+
+// --- Side Effect Range Tests ---
+
+func TestAnalyze_EndLocationFollowsLocation(t *testing.T) {
+	// parse splits "file:line:col" into its file and a comparable
+	// (line, col) pair.
+	parse := func(loc string) (string, [2]int, bool) {
+		parts := strings.Split(loc, ":")
+		if len(parts) < 3 {
+			return "", [2]int{}, false
+		}
+		line, err1 := strconv.Atoi(parts[len(parts)-2])
+		col, err2 := strconv.Atoi(parts[len(parts)-1])
+		return strings.Join(parts[:len(parts)-2], ":"), [2]int{line, col}, err1 == nil && err2 == nil
+	}
+
+	for _, name := range []string{"returns", "sentinel", "mutation", "p1effects", "p2effects", "p3effects"} {
+		pkg := loadTestPackage(t, name)
+		results, err := analysis.Analyze(pkg, analysis.Options{IncludeUnexported: true})
+		if err != nil {
+			t.Fatalf("Analyze(%s) failed: %v", name, err)
+		}
+		for _, r := range results {
+			for _, e := range r.SideEffects {
+				if e.Location == "<unknown>" {
+					continue
+				}
+				startFile, start, ok1 := parse(e.Location)
+				endFile, end, ok2 := parse(e.EndLocation)
+				if !ok1 || !ok2 {
+					t.Errorf("%s.%s %s: want a file:line:col range, got %q to %q",
+						name, r.Target.QualifiedName(), e.Type, e.Location, e.EndLocation)
+					continue
+				}
+				if startFile != endFile || end[0] < start[0] || (end[0] == start[0] && end[1] <= start[1]) {
+					t.Errorf("%s.%s %s: end %q does not follow start %q",
+						name, r.Target.QualifiedName(), e.Type, e.EndLocation, e.Location)
+				}
+			}
+		}
+	}
+}
+
+func TestAnalyze_EndLocationCoversStatement(t *testing.T) {
+	// SSA instructions only carry a start position; the end is
+	// taken from the enclosing assignment statement.
+	pkg, ssaPkg := loadTestPackageWithSSA(t, "mutation")
+	fd := analysis.FindMethodDecl(pkg, "*Counter", "SetBoth")
+	if fd == nil {
+		t.Fatal("(*Counter).SetBoth not found")
+	}
+	result := analysis.AnalyzeFunctionWithSSA(pkg, fd, ssaPkg)
+
+	want := map[string]string{
+		"count": pkg.Fset.Position(fd.Body.List[0].End()).String(),
+		"name":  pkg.Fset.Position(fd.Body.List[1].End()).String(),
+	}
+	for _, e := range result.SideEffects {
+		if e.Type != taxonomy.ReceiverMutation {
+			continue
+		}
+		if e.EndLocation != want[e.Target] {
+			t.Errorf("ReceiverMutation %s: end %q, want %q", e.Target, e.EndLocation, want[e.Target])
+		}
+	}
+}
+
 // --- Analyze() option tests ---
 
 func TestAnalyze_ExportedOnlyByDefault(t *testing.T) {
@@ -576,6 +1173,43 @@ func TestAnalyze_FunctionFilter(t *testing.T) {
 	}
 }
 
+func TestAnalyze_FunctionFilterMethod(t *testing.T) {
+	// The sentinel fixture declares Error on *NotFoundError and on
+	// the value type timeoutError.
+	pkg := loadTestPackage(t, "sentinel")
+
+	for _, tt := range []struct {
+		filter string
+		want   []string
+	}{
+		{"Error", []string{"(*NotFoundError).Error", "(timeoutError).Error"}},
+		{"NotFoundError.Error", []string{"(*NotFoundError).Error"}},
+		{"(*NotFoundError).Error", []string{"(*NotFoundError).Error"}},
+		{"(NotFoundError).Error", nil},
+		{"timeoutError.Error", []string{"(timeoutError).Error"}},
+		{"(timeoutError).Error", []string{"(timeoutError).Error"}},
+		{"(*timeoutError).Error", nil},
+		{"NotFoundError.Missing", nil},
+		{"(*NotFoundError.Error", nil},
+	} {
+		results, err := analysis.Analyze(pkg, analysis.Options{
+			IncludeUnexported: true,
+			FunctionFilter:    tt.filter,
+		})
+		if err != nil {
+			t.Fatalf("Analyze(%q) failed: %v", tt.filter, err)
+		}
+		var got []string
+		for _, r := range results {
+			got = append(got, r.Target.QualifiedName())
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("FunctionFilter %q matched %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
 // --- All Tiers are P0 ---
 
 func TestAnalysis_AllP0EffectsAreP0(t *testing.T) {
@@ -657,6 +1291,36 @@ func TestP1_WriterOutput_ReadOnly(t *testing.T) {
 	}
 }
 
+func TestP1_WriterOutput_OutputSinks(t *testing.T) {
+	tests := []struct {
+		function, target, description string
+	}{
+		{"RenderToBuffer", "buf", "writes to *bytes.Buffer 'buf'"},
+		{"RenderToBuilder", "sb", "writes to *strings.Builder 'sb'"},
+		{"PrintToWriter", "w", "writes to io.Writer 'w'"},
+	}
+	for _, tt := range tests {
+		result := analyzeFunc(t, "p1effects", tt.function)
+		if n := countEffects(result.SideEffects, taxonomy.WriterOutput); n != 1 {
+			t.Errorf("%s: expected 1 WriterOutput, got %d", tt.function, n)
+			continue
+		}
+		e := effectWithTarget(result.SideEffects, taxonomy.WriterOutput, tt.target)
+		if e == nil {
+			t.Errorf("%s: expected WriterOutput on %s, got %+v", tt.function, tt.target, result.SideEffects)
+			continue
+		}
+		if e.Description != tt.description {
+			t.Errorf("%s: description %q, want %q", tt.function, e.Description, tt.description)
+		}
+	}
+
+	result := analyzeFunc(t, "p1effects", "LocalBuffer")
+	if hasEffect(result.SideEffects, taxonomy.WriterOutput) {
+		t.Error("LocalBuffer should NOT produce WriterOutput")
+	}
+}
+
 func TestP1_HTTPResponseWrite(t *testing.T) {
 	result := analyzeFunc(t, "p1effects", "HandleHTTP")
 
@@ -697,6 +1361,26 @@ func TestP1_SliceMutation_ReadOnly(t *testing.T) {
 	}
 }
 
+func TestP1_SliceMutation_AppendReturn(t *testing.T) {
+	result := analyzeFunc(t, "p1effects", "AppendToParam")
+
+	e := effectWithTarget(result.SideEffects, taxonomy.SliceMutation, "s")
+	if e == nil {
+		t.Fatalf("expected SliceMutation on s for AppendToParam, got %+v", result.SideEffects)
+	}
+	if !strings.Contains(e.Description, "may mutate the caller's backing array if cap permits") {
+		t.Errorf("unexpected description %q", e.Description)
+	}
+	if !hasEffect(result.SideEffects, taxonomy.ReturnValue) {
+		t.Error("expected ReturnValue for AppendToParam")
+	}
+
+	result = analyzeFunc(t, "p1effects", "AppendToLocal")
+	if hasEffect(result.SideEffects, taxonomy.SliceMutation) {
+		t.Error("AppendToLocal should NOT produce SliceMutation")
+	}
+}
+
 func TestP1_PureFunction(t *testing.T) {
 	result := analyzeFunc(t, "p1effects", "PureP1")
 
@@ -930,6 +1614,146 @@ func TestP2_NoCallback(t *testing.T) {
 	}
 }
 
+func TestP2_MethodValueEscape(t *testing.T) {
+	tests := []struct {
+		name       string
+		wantTarget string
+		wantDesc   string
+	}{
+		{"RegisterMethodValue", "c.Inc", "passed to Subscribe; c may be mutated"},
+		{"ReturnMethodValue", "c.Inc", "returned; c may be mutated"},
+		{"RegisterMethodExpr", "(*Counter).Inc", "method expression (*Counter).Inc passed to apply"},
+		{"RegisterValueMethod", "", ""},
+		{"RegisterInterfaceMethod", "", ""},
+		{"CallMethodDirectly", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := analyzeFunc(t, "p2effects", tt.name)
+			var found []taxonomy.SideEffect
+			for _, e := range result.SideEffects {
+				if e.Type == taxonomy.MethodValueEscape {
+					found = append(found, e)
+				}
+			}
+			if tt.wantTarget == "" {
+				if len(found) != 0 {
+					t.Errorf("expected no MethodValueEscape, got %+v", found)
+				}
+				return
+			}
+			if len(found) != 1 {
+				t.Fatalf("expected 1 MethodValueEscape, got %d: %+v", len(found), found)
+			}
+			if found[0].Target != tt.wantTarget {
+				t.Errorf("Target = %q, want %q", found[0].Target, tt.wantTarget)
+			}
+			if !strings.Contains(found[0].Description, tt.wantDesc) {
+				t.Errorf("Description = %q, want it to contain %q", found[0].Description, tt.wantDesc)
+			}
+			if found[0].Tier != taxonomy.TierP2 {
+				t.Errorf("Tier = %s, want P2", found[0].Tier)
+			}
+		})
+	}
+}
+
+func TestP2_LocalPointerEscape(t *testing.T) {
+	tests := []struct {
+		name       string
+		wantTarget string
+		wantType   string
+	}{
+		{"NewSettings", "&Settings{}", "*Settings"},
+		{"AllocSettings", "new(Settings)", "*Settings"},
+		{"LocalSettings", "&s", "*Settings"},
+		{"BuiltSettings", "s", "*Settings"},
+		{"LocalSlot", "&s.slots[0]", "*int"},
+		{"PassThrough", "", ""},
+		{"FieldOfParam", "", ""},
+		{"SettingsAsAny", "", ""},
+		{"SettingsFactory", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := analyzeFunc(t, "p2effects", tt.name)
+			var found []taxonomy.SideEffect
+			for _, e := range result.SideEffects {
+				if e.Type == taxonomy.LocalPointerEscape {
+					found = append(found, e)
+				}
+			}
+			if tt.wantTarget == "" {
+				if len(found) != 0 {
+					t.Errorf("expected no LocalPointerEscape, got %+v", found)
+				}
+				return
+			}
+			if len(found) != 1 {
+				t.Fatalf("expected 1 LocalPointerEscape, got %d: %+v", len(found), found)
+			}
+			if found[0].Target != tt.wantTarget || found[0].TargetType != tt.wantType {
+				t.Errorf("Target, TargetType = %q, %q, want %q, %q",
+					found[0].Target, found[0].TargetType, tt.wantTarget, tt.wantType)
+			}
+			if !strings.Contains(found[0].Description, "the caller receives a mutable reference") {
+				t.Errorf("Description = %q, want it to mention the mutable reference", found[0].Description)
+			}
+			if found[0].Tier != taxonomy.TierP2 {
+				t.Errorf("Tier = %s, want P2", found[0].Tier)
+			}
+		})
+	}
+}
+
+func TestP2_InternalPointerEscape(t *testing.T) {
+	tests := []struct {
+		name       string
+		wantTarget string
+		wantField  string
+	}{
+		{"Current", "s.cur", "cur"},
+		{"EntryRef", "&s.entry", "entry"},
+		{"At", "&s.entries[i]", "entries"},
+		{"Owner", "s.meta.owner", "meta"},
+		{"CurrentByValue", "s.cur", "cur"},
+		{"CopyRef", "", ""},
+		{"PublicEntry", "", ""},
+		{"CurrentAsAny", "", ""},
+		{"CurrentOf", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := analyzeFunc(t, "p2effects", tt.name)
+			var found []taxonomy.SideEffect
+			for _, e := range result.SideEffects {
+				if e.Type == taxonomy.InternalPointerEscape {
+					found = append(found, e)
+				}
+			}
+			if tt.wantTarget == "" {
+				if len(found) != 0 {
+					t.Errorf("expected no InternalPointerEscape, got %+v", found)
+				}
+				return
+			}
+			if len(found) != 1 {
+				t.Fatalf("expected 1 InternalPointerEscape, got %d: %+v", len(found), found)
+			}
+			if found[0].Target != tt.wantTarget || found[0].TargetType != "*Entry" {
+				t.Errorf("Target, TargetType = %q, %q, want %q, %q",
+					found[0].Target, found[0].TargetType, tt.wantTarget, "*Entry")
+			}
+			if want := "Store's unexported field '" + tt.wantField + "'"; !strings.Contains(found[0].Description, want) {
+				t.Errorf("Description = %q, want it to contain %q", found[0].Description, want)
+			}
+			if found[0].Tier != taxonomy.TierP2 {
+				t.Errorf("Tier = %s, want P2", found[0].Tier)
+			}
+		})
+	}
+}
+
 func TestP2_DatabaseWrite(t *testing.T) {
 	result := analyzeFunc(t, "p2effects", "DBExec")
 
@@ -1202,3 +2026,363 @@ func TestEdge_AnalyzePackage(t *testing.T) {
 	}
 	t.Logf("edgecases: %d functions analyzed without errors", len(results))
 }
+
+// --- Result cache ---
+
+func TestAnalyze_CacheHitEqualsMiss(t *testing.T) {
+	pkg := loadTestPackage(t, "p1effects")
+	dir := t.TempDir()
+	opts := analysis.Options{IncludeUnexported: true, CacheDir: dir}
+
+	miss, err := analysis.Analyze(pkg, opts)
+	if err != nil {
+		t.Fatalf("Analyze (miss) failed: %v", err)
+	}
+	entries, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected 1 cache entry after first run, got %d (%v)", len(entries), err)
+	}
+
+	hit, err := analysis.Analyze(pkg, opts)
+	if err != nil {
+		t.Fatalf("Analyze (hit) failed: %v", err)
+	}
+
+	// Metadata carries timestamps and durations; everything else
+	// must be identical.
+	for i := range miss {
+		miss[i].Metadata = taxonomy.Metadata{}
+	}
+	for i := range hit {
+		hit[i].Metadata = taxonomy.Metadata{}
+	}
+	if !reflect.DeepEqual(hit, miss) {
+		t.Errorf("cache hit results differ from cache miss results")
+	}
+}
+
+// --- Concurrency ---
+
+func TestAnalyze_ParallelMatchesSequential(t *testing.T) {
+	for _, pkgName := range []string{"mutation", "sentinel", "p1effects"} {
+		t.Run(pkgName, func(t *testing.T) {
+			pkg := loadTestPackage(t, pkgName)
+
+			seq, err := analysis.Analyze(pkg, analysis.Options{IncludeUnexported: true, Workers: 1})
+			if err != nil {
+				t.Fatalf("Analyze (sequential) failed: %v", err)
+			}
+			par, err := analysis.Analyze(pkg, analysis.Options{IncludeUnexported: true, Workers: 8})
+			if err != nil {
+				t.Fatalf("Analyze (parallel) failed: %v", err)
+			}
+
+			for i := range seq {
+				seq[i].Metadata = taxonomy.Metadata{}
+			}
+			for i := range par {
+				par[i].Metadata = taxonomy.Metadata{}
+			}
+			if !reflect.DeepEqual(par, seq) {
+				t.Errorf("parallel results differ from sequential results")
+			}
+		})
+	}
+}
+
+func TestAnalyzeStream_MatchesAnalyze(t *testing.T) {
+	pkg := loadTestPackage(t, "sentinel")
+	opts := analysis.Options{IncludeUnexported: true}
+
+	want, err := analysis.Analyze(pkg, opts)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	var got []taxonomy.AnalysisResult
+	for r := range analysis.AnalyzeStream(pkg, opts) {
+		got = append(got, r)
+	}
+
+	for i := range want {
+		want[i].Metadata = taxonomy.Metadata{}
+	}
+	for i := range got {
+		got[i].Metadata = taxonomy.Metadata{}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AnalyzeStream results differ from Analyze results")
+	}
+}
+
+func TestAnalysis_IgnoreDirective(t *testing.T) {
+	tests := []struct {
+		funcName   string
+		want       []taxonomy.SideEffectType
+		suppressed int
+		warning    string // substring of the single expected warning; "" for none
+	}{
+		{"Unsuppressed", []taxonomy.SideEffectType{taxonomy.GlobalMutation, taxonomy.ErrorReturn}, 0, ""},
+		{"All", nil, 2, ""},
+		{"Global", []taxonomy.SideEffectType{taxonomy.ErrorReturn}, 1, ""},
+		{"CommaList", nil, 2, ""},
+		{"Unknown", []taxonomy.SideEffectType{taxonomy.GlobalMutation, taxonomy.ErrorReturn}, 0, "NoSuchEffect"},
+		{"NotADirective", []taxonomy.SideEffectType{taxonomy.GlobalMutation, taxonomy.ErrorReturn}, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.funcName, func(t *testing.T) {
+			result := analyzeFunc(t, "ignore", tt.funcName)
+			if len(result.SideEffects) != len(tt.want) {
+				t.Errorf("expected %d effects, got %d: %v",
+					len(tt.want), len(result.SideEffects), result.SideEffects)
+			}
+			for _, typ := range tt.want {
+				if !hasEffect(result.SideEffects, typ) {
+					t.Errorf("expected %s effect", typ)
+				}
+			}
+			if result.Suppressed != tt.suppressed {
+				t.Errorf("Suppressed = %d, want %d", result.Suppressed, tt.suppressed)
+			}
+			warnings := result.Metadata.Warnings
+			if tt.warning == "" {
+				if len(warnings) != 0 {
+					t.Errorf("expected no warnings, got %v", warnings)
+				}
+				return
+			}
+			if len(warnings) != 1 || !strings.Contains(warnings[0], tt.warning) {
+				t.Errorf("expected one warning containing %q, got %v", tt.warning, warnings)
+			}
+		})
+	}
+}
+
+func TestAnalyze_DepthPropagatesCalleeEffects(t *testing.T) {
+	// loader.Load records module information, which propagation
+	// needs to follow calls into the store package.
+	loaded, err := loader.Load("github.com/unbound-force/gaze/internal/analysis/testdata/src/interproc")
+	if err != nil {
+		t.Fatalf("loading interproc: %v", err)
+	}
+
+	// via returns the propagated effects of fn (those whose
+	// description names the call chain) as "Type Target" strings.
+	via := func(results []taxonomy.AnalysisResult, fn string) []string {
+		var out []string
+		for _, r := range results {
+			if r.Target.QualifiedName() != fn {
+				continue
+			}
+			for _, e := range r.SideEffects {
+				if strings.Contains(e.Description, "(via ") {
+					out = append(out, string(e.Type)+" "+e.Target)
+				}
+			}
+		}
+		return out
+	}
+
+	tests := []struct {
+		depth int
+		fn    string
+		want  []string
+	}{
+		{0, "Do", nil},
+		{1, "Do", []string{"PointerArgMutation s"}},
+		{1, "(*Service).Flush", []string{"ReceiverMutation st"}},
+		{1, "(*Service).Clear", []string{"ReceiverMutation st"}},
+		{1, "Outer", nil},
+		{2, "Outer", []string{"PointerArgMutation s"}},
+		{2, "Scratch", nil},
+		{2, "Ping", nil},
+		{2, "Tick", nil},
+		{2, "Tock", []string{"GlobalMutation ticks"}},
+		{3, "Tack", []string{"GlobalMutation ticks"}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/depth=%d", tt.fn, tt.depth), func(t *testing.T) {
+			results, err := analysis.Analyze(loaded.Pkg, analysis.Options{Depth: tt.depth})
+			if err != nil {
+				t.Fatalf("Analyze failed: %v", err)
+			}
+			got := via(results, tt.fn)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("propagated effects = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyze_DepthWithBodylessFunctions(t *testing.T) {
+	// Propagation must not walk the missing body of an
+	// assembly-backed declaration.
+	loaded, err := loader.Load("github.com/unbound-force/gaze/internal/analysis/testdata/src/incomplete")
+	if err != nil {
+		t.Fatalf("loading incomplete: %v", err)
+	}
+	results, err := analysis.Analyze(loaded.Pkg, analysis.Options{Depth: 1})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	var found bool
+	for _, r := range results {
+		if r.Target.Function == "AsmAdd" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a result for the body-less AsmAdd, got %d results", len(results))
+	}
+}
+
+func TestAnalyze_DepthDescribesCallChain(t *testing.T) {
+	loaded, err := loader.Load("github.com/unbound-force/gaze/internal/analysis/testdata/src/interproc")
+	if err != nil {
+		t.Fatalf("loading interproc: %v", err)
+	}
+	results, err := analysis.Analyze(loaded.Pkg, analysis.Options{Depth: 2, FunctionFilter: "Outer"})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(results) != 1 || len(results[0].SideEffects) != 1 {
+		t.Fatalf("expected one propagated effect for Outer, got %+v", results)
+	}
+	e := results[0].SideEffects[0]
+	if !strings.Contains(e.Description, "(via interproc.Do → store.(*Store).Save)") {
+		t.Errorf("expected call chain in description, got %q", e.Description)
+	}
+	if !strings.Contains(e.Location, "interproc.go") {
+		t.Errorf("expected the call site in Outer as location, got %q", e.Location)
+	}
+}
+
+func TestLoadAndAnalyze_IncludeTests(t *testing.T) {
+	const path = "github.com/unbound-force/gaze/internal/analysis/testdata/src/testfiles"
+
+	results, err := analysis.LoadAndAnalyze(path, analysis.Options{})
+	if err != nil {
+		t.Fatalf("LoadAndAnalyze: %v", err)
+	}
+	for _, r := range results {
+		if r.Target.Test {
+			t.Errorf("%s: test function analyzed without IncludeTests", r.Target.QualifiedName())
+		}
+	}
+
+	results, err = analysis.LoadAndAnalyze(path, analysis.Options{IncludeTests: true})
+	if err != nil {
+		t.Fatalf("LoadAndAnalyze: %v", err)
+	}
+	byName := make(map[string]taxonomy.AnalysisResult)
+	for _, r := range results {
+		byName[r.Target.Function] = r
+	}
+	tests := []struct {
+		function string
+		pkg      string
+		test     bool
+	}{
+		{"Clamp", path, false},
+		{"SetupLimit", path, true},
+		{"ResetLimit", path + "_test", true},
+	}
+	for _, tt := range tests {
+		r, ok := byName[tt.function]
+		if !ok {
+			t.Errorf("%s not analyzed", tt.function)
+			continue
+		}
+		if r.Target.Package != tt.pkg || r.Target.Test != tt.test {
+			t.Errorf("%s: package %q test %v, want %q test %v",
+				tt.function, r.Target.Package, r.Target.Test, tt.pkg, tt.test)
+		}
+	}
+	if !hasEffect(byName["SetupLimit"].SideEffects, taxonomy.GlobalMutation) {
+		t.Errorf("expected GlobalMutation for SetupLimit, got %+v", byName["SetupLimit"].SideEffects)
+	}
+}
+
+func TestTargetType(t *testing.T) {
+	tests := []struct {
+		pkg, recv, function string
+		effect              taxonomy.SideEffectType
+		target, want        string
+	}{
+		{"mutation", "*Counter", "Increment", taxonomy.ReceiverMutation, "count", "int"},
+		{"mutation", "*Store", "Put", taxonomy.ReceiverMutation, "cache", "map[string]int"},
+		{"mutation", "*Config", "UpdateNested", taxonomy.ReceiverMutation, "Nested", "struct{Value string}"},
+		{"mutation", "*Outer", "Set", taxonomy.ReceiverMutation, "Inner.Field", "int"},
+		{"mutation", "", "FillSlice", taxonomy.PointerArgMutation, "dst", "[]int"},
+		{"mutation", "", "SwapInto", taxonomy.PointerArgMutation, "dst", "Pair"},
+		{"p1effects", "", "MutateGlobal", taxonomy.GlobalMutation, "globalCounter", "int"},
+		{"p1effects", "", "SendOnChannel", taxonomy.ChannelSend, "ch", "int"},
+		{"p1effects", "", "CloseChannel", taxonomy.ChannelClose, "ch", "int"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			var result taxonomy.AnalysisResult
+			if tt.recv != "" {
+				result = analyzeMethod(t, tt.pkg, tt.recv, tt.function)
+			} else {
+				result = analyzeFunc(t, tt.pkg, tt.function)
+			}
+			for _, e := range result.SideEffects {
+				if e.Type == tt.effect && e.Target == tt.target {
+					if e.TargetType != tt.want {
+						t.Errorf("TargetType = %q, want %q", e.TargetType, tt.want)
+					}
+					return
+				}
+			}
+			t.Errorf("no %s on %q in %+v", tt.effect, tt.target, result.SideEffects)
+		})
+	}
+}
+
+func TestLoadFunctions_MatchesAnalysis(t *testing.T) {
+	const path = "github.com/unbound-force/gaze/internal/analysis/testdata/src/mutation"
+
+	for _, opts := range []analysis.Options{
+		{},
+		{IncludeUnexported: true},
+		{FunctionFilter: "Counter.Increment"},
+	} {
+		targets, err := analysis.LoadFunctions(context.Background(), path, opts)
+		if err != nil {
+			t.Fatalf("LoadFunctions(%+v): %v", opts, err)
+		}
+		results, err := analysis.LoadAndAnalyze(path, opts)
+		if err != nil {
+			t.Fatalf("LoadAndAnalyze(%+v): %v", opts, err)
+		}
+		var want []taxonomy.FunctionTarget
+		for _, r := range results {
+			if r.Target.Function != "<package>" {
+				want = append(want, r.Target)
+			}
+		}
+		if len(want) == 0 {
+			t.Fatalf("%+v: no functions analyzed", opts)
+		}
+		if !reflect.DeepEqual(targets, want) {
+			t.Errorf("%+v: LoadFunctions = %v\nwant %v", opts, targets, want)
+		}
+	}
+}
+
+func TestLoadFunctions_IncludeTests(t *testing.T) {
+	const path = "github.com/unbound-force/gaze/internal/analysis/testdata/src/testfiles"
+
+	targets, err := analysis.LoadFunctions(context.Background(), path, analysis.Options{IncludeTests: true})
+	if err != nil {
+		t.Fatalf("LoadFunctions: %v", err)
+	}
+	var got []string
+	for _, target := range targets {
+		got = append(got, fmt.Sprintf("%s test=%v", target.Function, target.Test))
+	}
+	want := []string{"SetupLimit test=true", "Clamp test=false", "ResetLimit test=true"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadFunctions = %v, want %v", got, want)
+	}
+}
@@ -4,15 +4,22 @@
 package analysis
 
 import (
+	"context"
+	"fmt"
 	"go/ast"
 	"go/token"
-	"go/types"
+	"log"
 	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/tools/go/packages"
 	"golang.org/x/tools/go/ssa"
 
+	"github.com/unbound-force/gaze/internal/cache"
 	"github.com/unbound-force/gaze/internal/loader"
 	"github.com/unbound-force/gaze/internal/taxonomy"
 )
@@ -24,50 +31,254 @@ type Options struct {
 	IncludeUnexported bool
 
 	// FunctionFilter limits analysis to a specific function name.
-	// Empty string means analyze all functions.
+	// A bare name matches every function and method with that name;
+	// "Type.Method" matches the method on Type with either receiver
+	// kind, and "(*Type).Method" or "(Type).Method" match only that
+	// receiver. Empty string means analyze all functions.
 	FunctionFilter string
 
 	// Version is the Gaze version string to embed in metadata.
 	// If empty, defaults to "dev".
 	Version string
+
+	// CacheDir, when non-empty, enables the content-addressed
+	// result cache in that directory. Packages whose source (and
+	// same-module dependencies) are unchanged reuse cached results
+	// instead of being re-analyzed.
+	CacheDir string
+
+	// Workers bounds the number of functions analyzed concurrently.
+	// Zero or negative means runtime.GOMAXPROCS(0); 1 analyzes
+	// functions sequentially.
+	Workers int
+
+	// ChangedLines, when non-nil, limits analysis to functions whose
+	// declaration overlaps a changed line. It maps absolute file
+	// paths to line numbers; files absent from the map have no
+	// changes. Sentinel errors are reported only for changed files.
+	// The result cache is bypassed.
+	ChangedLines map[string]map[int]bool
+
+	// Exclude lists glob patterns for source files whose functions
+	// (and sentinel errors) are skipped, such as "*_mock.go" or
+	// "internal/fixtures/**". Paths are matched relative to the
+	// working directory with filepath.Match syntax; "dir/**" matches
+	// everything under dir, and a pattern without a slash also
+	// matches the file's base name.
+	Exclude []string
+
+	// IgnoreGenerated skips generated files, as detected by
+	// gofiles.IsGenerated: files carrying the standard
+	// "// Code generated ... DO NOT EDIT." header or named by a
+	// generator convention such as "*.pb.go".
+	IgnoreGenerated bool
+
+	// Depth enables interprocedural analysis: the observable side
+	// effects of functions called from the analyzed function, within
+	// the same module, are added to its own, following calls up to
+	// Depth levels deep. Effects on a callee's receiver or parameters
+	// are attributed to the caller's receiver or parameters when the
+	// arguments flow from them. Zero disables propagation.
+	Depth int
+
+	// IncludeTests makes LoadAndAnalyze and LoadAndAnalyzeContext
+	// load the package with its _test.go files and analyze the
+	// functions declared in them as well. Their results have
+	// FunctionTarget.Test set. Packages passed to Analyze are
+	// analyzed as loaded.
+	IncludeTests bool
+
+	// Dir is the directory LoadAndAnalyze, LoadAndAnalyzeContext,
+	// and LoadFunctions resolve their pattern in. Empty means the
+	// current directory.
+	Dir string
+
+	// Build sets the build tags and target platform LoadAndAnalyze,
+	// LoadAndAnalyzeContext, and LoadFunctions load packages under,
+	// so that files excluded on the host platform, such as those
+	// marked //go:build linux on macOS, can be analyzed.
+	Build loader.Build
+
+	// Disable names analyzers, as listed by Analyzers, whose effects
+	// are not reported. Names are not validated here; use
+	// SelectAnalyzers for user input. Disabling "returns" also drops
+	// package-level sentinel errors.
+	Disable []string
 }
 
 // Analyze performs side effect analysis on all functions in the
 // loaded package. Returns a slice of AnalysisResult (one per
 // function) and any error encountered during analysis.
 func Analyze(pkg *packages.Package, opts Options) ([]taxonomy.AnalysisResult, error) {
+	return AnalyzeContext(context.Background(), pkg, opts)
+}
+
+// AnalyzeContext is like Analyze but stops when ctx is done. No new
+// function is started after that point and functions still being
+// analyzed are abandoned, so a deadline bounds the run even when a
+// single function is pathologically slow. The results completed so
+// far are returned, in order, together with an error wrapping
+// ctx.Err(); each of them carries a metadata warning that the
+// package was only partially analyzed. Partial results are never
+// written to the result cache.
+func AnalyzeContext(ctx context.Context, pkg *packages.Package, opts Options) ([]taxonomy.AnalysisResult, error) {
 	start := time.Now()
 
-	fset := pkg.Fset
+	if opts.CacheDir != "" && opts.ChangedLines == nil {
+		return analyzeCached(ctx, pkg, opts, start)
+	}
+	return analyzePackage(ctx, pkg, opts, start)
+}
 
-	// Build SSA once for the entire package to avoid redundant
-	// reconstruction per function.
-	ssaPkg := BuildSSA(pkg)
+// analyzeCached wraps analyzePackage with the result cache. Cache
+// failures are logged and never fail the analysis; on a hit, the
+// cached results receive fresh metadata exactly as a miss would.
+func analyzeCached(ctx context.Context, pkg *packages.Package, opts Options, start time.Time) ([]taxonomy.AnalysisResult, error) {
+	c, err := cache.New(opts.CacheDir)
+	if err != nil {
+		log.Printf("warning: result cache disabled: %v", err)
+		return analyzePackage(ctx, pkg, opts, start)
+	}
+	key, err := cache.Key(pkg,
+		opts.Version, runtime.Version(),
+		strconv.FormatBool(opts.IncludeUnexported), opts.FunctionFilter,
+		strconv.Itoa(opts.Depth),
+		strings.Join(opts.Exclude, "\x00"), strconv.FormatBool(opts.IgnoreGenerated),
+		strings.Join(DetectorNames(), "\x00"), strings.Join(opts.Disable, "\x00"),
+	)
+	if err != nil {
+		log.Printf("warning: result cache disabled: %v", err)
+		return analyzePackage(ctx, pkg, opts, start)
+	}
 
-	var results []taxonomy.AnalysisResult
+	if results, ok := c.Load(key); ok {
+		for i := range results {
+			results[i].Metadata = buildMetadata(start, opts.Version, results[i].Metadata.Warnings)
+		}
+		return results, nil
+	}
 
-	for _, file := range pkg.Syntax {
-		for _, decl := range file.Decls {
-			fd, ok := decl.(*ast.FuncDecl)
-			if !ok || fd.Name == nil || fd.Body == nil {
-				continue
-			}
+	results, err := analyzePackage(ctx, pkg, opts, start)
+	if err != nil {
+		return results, err
+	}
+	if err := c.Store(key, results); err != nil {
+		log.Printf("warning: could not write result cache: %v", err)
+	}
+	return results, nil
+}
 
-			// Apply filters.
-			if opts.FunctionFilter != "" && fd.Name.Name != opts.FunctionFilter {
-				continue
+// AnalyzeStream is like Analyze but sends each result on the
+// returned channel as soon as it and every result before it are
+// ready, then closes the channel. Results arrive in the same order
+// Analyze returns them. Callers must drain the channel. When
+// opts.CacheDir is set, the package is analyzed (or loaded from the
+// cache) in full before the first result is sent.
+func AnalyzeStream(pkg *packages.Package, opts Options) <-chan taxonomy.AnalysisResult {
+	return AnalyzeStreamContext(context.Background(), pkg, opts)
+}
+
+// AnalyzeStreamContext is like AnalyzeStream but stops when ctx is
+// done, as AnalyzeContext does: the channel is closed after the
+// results completed so far have been sent. Results already sent
+// cannot be marked as partial, so callers should check ctx.Err()
+// once the channel is closed.
+func AnalyzeStreamContext(ctx context.Context, pkg *packages.Package, opts Options) <-chan taxonomy.AnalysisResult {
+	out := make(chan taxonomy.AnalysisResult)
+	go func() {
+		defer close(out)
+		if opts.CacheDir != "" && opts.ChangedLines == nil {
+			results, _ := AnalyzeContext(ctx, pkg, opts)
+			for _, r := range results {
+				out <- r
 			}
-			if !opts.IncludeUnexported && !fd.Name.IsExported() {
-				continue
+			return
+		}
+		_, _ = streamPackage(ctx, pkg, opts, time.Now(), func(r taxonomy.AnalysisResult) {
+			out <- r
+		})
+	}()
+	return out
+}
+
+// analyzePackage runs side effect analysis on every selected
+// function in pkg and returns the results in source order. If ctx
+// is done first, the results completed so far are returned with a
+// partial-analysis warning and an error.
+func analyzePackage(ctx context.Context, pkg *packages.Package, opts Options, start time.Time) ([]taxonomy.AnalysisResult, error) {
+	var results []taxonomy.AnalysisResult
+	skipped, err := streamPackage(ctx, pkg, opts, start, func(r taxonomy.AnalysisResult) {
+		results = append(results, r)
+	})
+	if err != nil {
+		warning := fmt.Sprintf("analysis stopped early (%v): %d function(s) were not analyzed, results are partial",
+			err, skipped)
+		for i := range results {
+			results[i].Metadata.Warnings = append(results[i].Metadata.Warnings, warning)
+		}
+		return results, fmt.Errorf("analyzing %s: %d function(s) not analyzed: %w", pkg.PkgPath, skipped, err)
+	}
+	return results, nil
+}
+
+// streamPackage runs side effect analysis on every selected
+// function in pkg and calls emit once per result, ordered by source
+// location. Functions are analyzed concurrently by up to
+// opts.Workers goroutines; each result is emitted as soon as it and
+// all results before it have completed, so output order does not
+// depend on scheduling.
+//
+// If ctx is done before every function has been analyzed, the
+// functions that completed are still emitted in order, the rest are
+// skipped, and streamPackage returns their number with ctx.Err().
+// Workers busy with a skipped function are not waited for; they
+// finish in the background and their results are discarded.
+func streamPackage(
+	ctx context.Context,
+	pkg *packages.Package,
+	opts Options,
+	start time.Time,
+	emit func(taxonomy.AnalysisResult),
+) (skipped int, err error) {
+	// The FileSet is shared by all workers. token.FileSet methods
+	// are internally synchronized and workers only resolve
+	// positions (no files are added), so concurrent use is safe
+	// without an additional lock.
+	fset := pkg.Fset
+	skip := skipSet(opts.Disable)
+
+	var jobs []analysisJob
+
+	// Sentinels sharing a message may be declared in different
+	// files, so collisions are found across the whole package before
+	// each file's sentinel result is built.
+	var sentinelWarnings map[string][]string
+	if opts.FunctionFilter == "" && !skip["returns"] {
+		var files []*ast.File
+		for _, file := range pkg.Syntax {
+			if !skipFile(fset.Position(file.Pos()).Filename, opts) {
+				files = append(files, file)
 			}
+		}
+		sentinelWarnings = duplicateSentinelWarnings(fset, pkg.TypesInfo, files)
+	}
+
+	for _, file := range pkg.Syntax {
+		if skipFile(fset.Position(file.Pos()).Filename, opts) {
+			continue
+		}
 
-			result := analyzeFunction(fset, pkg, ssaPkg, fd)
-			results = append(results, result)
+		if opts.ChangedLines != nil && len(opts.ChangedLines[fset.Position(file.Pos()).Filename]) == 0 {
+			continue
+		}
+
+		for _, fd := range selectedFuncs(fset, file, opts) {
+			jobs = append(jobs, analysisJob{pos: fset.Position(fd.Pos()), fd: fd})
 		}
 
 		// Analyze sentinel errors at file level.
-		if opts.FunctionFilter == "" {
-			sentinels := AnalyzeSentinels(fset, file, pkg.PkgPath)
+		if opts.FunctionFilter == "" && !skip["returns"] {
+			sentinels := AnalyzeSentinels(fset, pkg.TypesInfo, file, pkg.PkgPath)
 			if len(sentinels) > 0 {
 				// Attach sentinels to a synthetic package-level
 				// result. The function name "<package>" indicates
@@ -75,27 +286,181 @@ func Analyze(pkg *packages.Package, opts Options) ([]taxonomy.AnalysisResult, er
 				// associated with any specific function. Each
 				// sentinel's Target field identifies the specific
 				// variable (e.g., "ErrNotFound") and the Location
-				// field points to its declaration site.
+				// field points to its declaration site. The result
+				// sorts at the end of its file, after the file's
+				// functions. Sentinels sharing a message with
+				// another are named in its warnings.
 				fileName := fset.Position(file.Pos()).Filename
-				results = append(results, taxonomy.AnalysisResult{
-					Target: taxonomy.FunctionTarget{
-						Package:  pkg.PkgPath,
-						Function: "<package>",
-						Location: fileName,
+				jobs = append(jobs, analysisJob{
+					pos: fset.Position(file.End()),
+					result: taxonomy.AnalysisResult{
+						Target: taxonomy.FunctionTarget{
+							Package:  pkg.PkgPath,
+							Function: "<package>",
+							Location: fileName,
+						},
+						SideEffects: sentinels,
+						Metadata:    taxonomy.Metadata{Warnings: sentinelWarnings[fileName]},
 					},
-					SideEffects: sentinels,
-					Metadata:    buildMetadata(start, opts.Version),
 				})
 			}
 		}
 	}
 
-	// Update metadata timing for all results.
-	for i := range results {
-		results[i].Metadata = buildMetadata(start, opts.Version)
+	sort.SliceStable(jobs, func(i, j int) bool {
+		a, b := jobs[i].pos, jobs[j].pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+
+	// Each job gets a completion channel so results can be emitted
+	// in order while later jobs are still running. Sentinel jobs
+	// have no function to analyze and are complete immediately.
+	done := make([]chan struct{}, len(jobs))
+	queue := make(chan int)
+	pending := 0
+	for i := range jobs {
+		done[i] = make(chan struct{})
+		if jobs[i].fd == nil {
+			close(done[i])
+		} else {
+			pending++
+		}
 	}
 
-	return results, nil
+	// Build SSA once for the entire package to avoid redundant
+	// reconstruction per function. The SSA package is fully built
+	// before any worker starts and is read-only afterwards. Only
+	// mutation analysis uses it.
+	var ssaPkg *ssa.Package
+	if !skip["mutations"] {
+		ssaPkg, err = buildSSAContext(ctx, pkg)
+		if err != nil {
+			return pending, err
+		}
+	}
+	sum := newSummarizer(pkg, ssaPkg, opts.Depth, skip)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workerCount(opts.Workers, pending); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				jobs[i].result = analyzeFunction(fset, pkg, ssaPkg, jobs[i].fd, sum, skip)
+				close(done[i])
+			}
+		}()
+	}
+	go func() {
+		defer close(queue)
+		for i := range jobs {
+			if jobs[i].fd == nil {
+				continue
+			}
+			select {
+			case queue <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for i := range jobs {
+		select {
+		case <-done[i]:
+		case <-ctx.Done():
+			// Both may be ready; only a closed done channel means
+			// the result can be read.
+			select {
+			case <-done[i]:
+			default:
+				skipped++
+				continue
+			}
+		}
+		result := jobs[i].result
+		result.Metadata = buildMetadata(start, opts.Version, result.Metadata.Warnings)
+		// Drop the job's copy so emitted results can be released.
+		jobs[i].result = taxonomy.AnalysisResult{}
+		emit(result)
+	}
+	if skipped > 0 {
+		return skipped, ctx.Err()
+	}
+	wg.Wait()
+	return 0, nil
+}
+
+// buildSSAContext builds the SSA form of pkg like BuildSSA, but
+// returns ctx.Err() as soon as ctx is done. SSA construction cannot
+// be interrupted, so an abandoned build runs to completion in the
+// background.
+func buildSSAContext(ctx context.Context, pkg *packages.Package) (*ssa.Package, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if ctx.Done() == nil {
+		return BuildSSA(pkg), nil
+	}
+	built := make(chan *ssa.Package, 1)
+	go func() { built <- BuildSSA(pkg) }()
+	select {
+	case ssaPkg := <-built:
+		return ssaPkg, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// analysisJob is one unit of work in streamPackage: either a
+// function to analyze or a precomputed sentinel result. pos orders
+// the job's result in the output.
+type analysisJob struct {
+	pos    token.Position
+	fd     *ast.FuncDecl
+	result taxonomy.AnalysisResult
+}
+
+// workerCount returns the number of analysis goroutines to start:
+// requested if positive, otherwise GOMAXPROCS, and never more than
+// the number of jobs.
+func workerCount(requested, jobs int) int {
+	n := requested
+	if n <= 0 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	if n > jobs {
+		n = jobs
+	}
+	return n
+}
+
+// FuncDeclsInLines returns the function declarations in file whose
+// source range, including the doc comment, contains at least one of
+// lines, in source order.
+func FuncDeclsInLines(fset *token.FileSet, file *ast.File, lines map[int]bool) []*ast.FuncDecl {
+	var out []*ast.FuncDecl
+	for _, decl := range file.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		start := fd.Pos()
+		if fd.Doc != nil {
+			start = fd.Doc.Pos()
+		}
+		first, last := fset.Position(start).Line, fset.Position(fd.End()).Line
+		for l := first; l <= last; l++ {
+			if lines[l] {
+				out = append(out, fd)
+				break
+			}
+		}
+	}
+	return out
 }
 
 // AnalyzeFunction performs side effect analysis on a single function.
@@ -123,64 +488,84 @@ func AnalyzeFunctionWithSSA(
 	start := time.Now()
 	fset := pkg.Fset
 
-	if ssaPkg == nil {
+	// Trivially pure functions never reach the SSA-based analyzers,
+	// so building SSA for them would be wasted work.
+	if ssaPkg == nil && !(fastPath && isTriviallyPure(pkg.TypesInfo, fd)) {
 		ssaPkg = BuildSSA(pkg)
 	}
 
-	result := analyzeFunction(fset, pkg, ssaPkg, fd)
-	result.Metadata = buildMetadata(start, "")
+	result := analyzeFunction(fset, pkg, ssaPkg, fd, nil, nil)
+	result.Metadata = buildMetadata(start, "", result.Metadata.Warnings)
 	return result
 }
 
-// analyzeFunction runs all analyzers on a single function declaration.
+// analyzeFunction runs the analyzers not in skip on a single function
+// declaration, in the order of the analyzers table followed by the
+// registered detectors, adding the effects of its callees when sum
+// is non-nil. Functions that isTriviallyPure accepts skip the
+// built-in analyzers other than return analysis, which could report
+// nothing else for them; registered detectors always run. Constructs
+// the analyzers cannot see through, and goroutines capturing shared
+// loop variables in modules before Go 1.22, are recorded in
+// Metadata.Warnings; the rest of Metadata is left for the caller.
 func analyzeFunction(
 	fset *token.FileSet,
 	pkg *packages.Package,
 	ssaPkg *ssa.Package,
 	fd *ast.FuncDecl,
+	sum *summarizer,
+	skip map[string]bool,
 ) taxonomy.AnalysisResult {
-	funcName := fd.Name.Name
 	pkgPath := pkg.PkgPath
-
-	target := taxonomy.FunctionTarget{
-		Package:   pkgPath,
-		Function:  funcName,
-		Receiver:  receiverName(fd),
-		Signature: funcSignature(fset, fd),
-		Location:  fset.Position(fd.Pos()).String(),
-	}
+	target := functionTarget(fset, pkgPath, fd)
 
 	var effects []taxonomy.SideEffect
 
-	// 1. Return value analysis (AST-based).
-	returnEffects := AnalyzeReturns(fset, pkg.TypesInfo, fd, pkgPath, funcName)
-	effects = append(effects, returnEffects...)
-
-	// 2. Mutation analysis (SSA-based).
-	obj := pkg.TypesInfo.Defs[fd.Name]
-	if obj != nil {
-		if fnObj, ok := obj.(*types.Func); ok {
-			mutationEffects := AnalyzeMutations(fset, ssaPkg, fd, fnObj, pkgPath, funcName)
-			effects = append(effects, mutationEffects...)
+	// 1-5. The built-in analyzers: return values, mutations, and
+	// the P1-P3 detectors.
+	pure := fastPath && isTriviallyPure(pkg.TypesInfo, fd)
+	for _, a := range analyzers {
+		if skip[a.name] || (pure && a.skipPure) {
+			continue
 		}
+		d := a.detector(fset, ssaPkg, pkgPath)
+		effects = append(effects, d.Detect(fd, pkg.TypesInfo)...)
 	}
 
-	// 3. P1-tier effects (AST-based).
-	p1Effects := AnalyzeP1Effects(fset, pkg.TypesInfo, fd, pkgPath, funcName)
-	effects = append(effects, p1Effects...)
+	// 6. Detectors added with RegisterDetector. They run even for
+	// trivially pure functions, whose bodies only the built-in
+	// detectors are known to find nothing in.
+	effects = append(effects, customEffects(fset, pkg.TypesInfo, fd, pkgPath, skip)...)
 
-	// 4. P2-tier effects (AST-based).
-	p2Effects := AnalyzeP2Effects(fset, pkg.TypesInfo, fd, pkgPath, funcName)
-	effects = append(effects, p2Effects...)
+	// 7. Effects of called functions (interprocedural mode only).
+	if !pure && sum != nil {
+		effects = append(effects, sum.propagated(fd)...)
+	}
+
+	// 8. Give effects whose IDs collide distinct ones.
+	taxonomy.UniqueIDs(effects)
+
+	// 9. Suppress effects named by //gaze:ignore directives.
+	effects, suppressed, ignoreWarnings := applyIgnoreDirective(fd, effects)
+
+	warnings := analysisWarnings(pkg.TypesInfo, pkg.Types, fd)
+	if v := sharedLoopVarVersion(pkg); v != "" {
+		warnings = append(warnings, loopCaptureWarnings(fset, pkg.TypesInfo, fd, v)...)
+	}
 
 	return taxonomy.AnalysisResult{
 		Target:      target,
 		SideEffects: effects,
+		Suppressed:  suppressed,
+		Metadata: taxonomy.Metadata{
+			Warnings: append(warnings, ignoreWarnings...),
+		},
 	}
 }
 
-// buildMetadata creates analysis metadata with current timing.
-func buildMetadata(start time.Time, version string) taxonomy.Metadata {
+// buildMetadata creates analysis metadata with current timing,
+// carrying over any warnings produced by the analyzers.
+func buildMetadata(start time.Time, version string, warnings []string) taxonomy.Metadata {
 	if version == "" {
 		version = "dev"
 	}
@@ -189,10 +574,146 @@ func buildMetadata(start time.Time, version string) taxonomy.Metadata {
 		GoVersion:   runtime.Version(),
 		Timestamp:   start,
 		Duration:    time.Since(start),
-		Warnings:    nil,
+		Warnings:    warnings,
+	}
+}
+
+// selectedFuncs returns the function declarations in file that opts
+// selects for analysis, in declaration order: those matching
+// FunctionFilter, exported unless IncludeUnexported is set, and
+// overlapping a changed line when ChangedLines is set. File-level
+// filters (Exclude, IgnoreGenerated) are applied by the caller.
+func selectedFuncs(fset *token.FileSet, file *ast.File, opts Options) []*ast.FuncDecl {
+	var changed map[*ast.FuncDecl]bool
+	if opts.ChangedLines != nil {
+		changed = make(map[*ast.FuncDecl]bool)
+		lines := opts.ChangedLines[fset.Position(file.Pos()).Filename]
+		for _, fd := range FuncDeclsInLines(fset, file, lines) {
+			changed[fd] = true
+		}
+	}
+
+	var fds []*ast.FuncDecl
+	for _, decl := range file.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Name == nil {
+			continue
+		}
+		if changed != nil && !changed[fd] {
+			continue
+		}
+		if opts.FunctionFilter != "" && !matchesFunction(fd, opts.FunctionFilter) {
+			continue
+		}
+		if !opts.IncludeUnexported && !fd.Name.IsExported() {
+			continue
+		}
+		fds = append(fds, fd)
+	}
+	return fds
+}
+
+// functionTarget describes the function declared by fd in the
+// package with import path pkgPath.
+func functionTarget(fset *token.FileSet, pkgPath string, fd *ast.FuncDecl) taxonomy.FunctionTarget {
+	return taxonomy.FunctionTarget{
+		Package:   pkgPath,
+		Function:  fd.Name.Name,
+		Receiver:  receiverName(fd),
+		Signature: funcSignature(fset, fd),
+		Location:  fset.Position(fd.Pos()).String(),
+		Test:      strings.HasSuffix(fset.Position(fd.Pos()).Filename, "_test.go"),
 	}
 }
 
+// Functions returns the functions in pkg that Analyze would analyze
+// with opts, ordered by source location, without running any
+// analyzer. Package-level sentinel results are not included.
+func Functions(pkg *packages.Package, opts Options) []taxonomy.FunctionTarget {
+	var fds []*ast.FuncDecl
+	for _, file := range pkg.Syntax {
+		if skipFile(pkg.Fset.Position(file.Pos()).Filename, opts) {
+			continue
+		}
+		fds = append(fds, selectedFuncs(pkg.Fset, file, opts)...)
+	}
+	sort.SliceStable(fds, func(i, j int) bool {
+		a, b := pkg.Fset.Position(fds[i].Pos()), pkg.Fset.Position(fds[j].Pos())
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+
+	targets := make([]taxonomy.FunctionTarget, len(fds))
+	for i, fd := range fds {
+		targets[i] = functionTarget(pkg.Fset, pkg.PkgPath, fd)
+	}
+	return targets
+}
+
+// LoadFunctions loads the packages matching pattern, as
+// LoadAndAnalyzeContext does, and returns the functions each of them
+// would have analyzed; see Functions.
+func LoadFunctions(ctx context.Context, pattern string, opts Options) ([]taxonomy.FunctionTarget, error) {
+	loaded, err := loader.LoadAll(pattern, loader.Options{Context: ctx, Dir: opts.Dir, Tests: opts.IncludeTests, Build: opts.Build})
+	if err != nil {
+		return nil, err
+	}
+	var targets []taxonomy.FunctionTarget
+	for _, result := range loaded {
+		for _, pkg := range []*packages.Package{result.Pkg, result.XTest} {
+			if pkg != nil {
+				targets = append(targets, Functions(pkg, opts)...)
+			}
+		}
+	}
+	return targets, nil
+}
+
+// matchesFunction reports whether fd is selected by a FunctionFilter
+// value. See Options.FunctionFilter for the accepted forms.
+func matchesFunction(fd *ast.FuncDecl, filter string) bool {
+	i := strings.LastIndexByte(filter, '.')
+	if i < 0 {
+		return fd.Name.Name == filter
+	}
+	recv, name := filter[:i], filter[i+1:]
+	if fd.Name.Name != name || fd.Recv == nil || len(fd.Recv.List) == 0 {
+		return false
+	}
+	typeName, pointer := receiverTypeName(fd.Recv.List[0].Type)
+	if inner, ok := strings.CutPrefix(recv, "("); ok {
+		inner, ok = strings.CutSuffix(inner, ")")
+		if !ok {
+			return false
+		}
+		if star, isPtr := strings.CutPrefix(inner, "*"); isPtr {
+			return pointer && star == typeName
+		}
+		return !pointer && inner == typeName
+	}
+	return recv == typeName
+}
+
+// receiverTypeName returns the name of a receiver's base type,
+// without type parameters, and whether the receiver is a pointer.
+func receiverTypeName(expr ast.Expr) (name string, pointer bool) {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr, pointer = star.X, true
+	}
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		expr = t.X
+	case *ast.IndexListExpr:
+		expr = t.X
+	}
+	if id, ok := expr.(*ast.Ident); ok {
+		return id.Name, pointer
+	}
+	return "", pointer
+}
+
 // findFuncDecl finds a FuncDecl by name in a package.
 // Returns nil if not found.
 func findFuncDecl(pkg *packages.Package, name string) *ast.FuncDecl {
@@ -231,12 +752,38 @@ func findMethodDecl(pkg *packages.Package, recvType, methodName string) *ast.Fun
 	return nil
 }
 
-// LoadAndAnalyze is a convenience function that loads a package and
-// runs analysis with the given options.
+// LoadAndAnalyze is a convenience function that loads the packages
+// matching pattern and runs analysis with the given options.
 func LoadAndAnalyze(pattern string, opts Options) ([]taxonomy.AnalysisResult, error) {
-	result, err := loader.Load(pattern)
+	return LoadAndAnalyzeContext(context.Background(), pattern, opts)
+}
+
+// LoadAndAnalyzeContext is like LoadAndAnalyze but bounded by ctx,
+// covering both package loading and analysis. See AnalyzeContext for
+// what is returned when ctx is done during analysis.
+//
+// A pattern such as ./... may match several packages; they are
+// analyzed in import path order and their results concatenated.
+// With opts.IncludeTests, each package's _test.go files are loaded
+// and analyzed too: the in-package test files together with the
+// package, then the external test package ("pkg_test"), if any.
+func LoadAndAnalyzeContext(ctx context.Context, pattern string, opts Options) ([]taxonomy.AnalysisResult, error) {
+	loaded, err := loader.LoadAll(pattern, loader.Options{Context: ctx, Dir: opts.Dir, Tests: opts.IncludeTests, Build: opts.Build})
 	if err != nil {
 		return nil, err
 	}
-	return Analyze(result.Pkg, opts)
+	var results []taxonomy.AnalysisResult
+	for _, result := range loaded {
+		for _, pkg := range []*packages.Package{result.Pkg, result.XTest} {
+			if pkg == nil {
+				continue
+			}
+			pkgResults, err := AnalyzeContext(ctx, pkg, opts)
+			results = append(results, pkgResults...)
+			if err != nil {
+				return results, err
+			}
+		}
+	}
+	return results, nil
 }
@@ -1,10 +1,21 @@
 package analysis_test
 
 import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 
+	"golang.org/x/tools/go/packages"
+
 	"github.com/unbound-force/gaze/internal/analysis"
+	"github.com/unbound-force/gaze/internal/loader"
 	"github.com/unbound-force/gaze/internal/taxonomy"
 )
 
@@ -45,6 +56,40 @@ func BenchmarkAnalyze_AllFunctions(b *testing.B) {
 	}
 }
 
+// BenchmarkAnalyze_PureFastPath compares single-function analysis of
+// the returns fixture's trivially pure functions with and without the
+// fast path. AnalyzeFunction builds SSA on demand, so the fast path
+// also saves the SSA construction.
+func BenchmarkAnalyze_PureFastPath(b *testing.B) {
+	pkg := loadTestPackageBench(b, "returns")
+	var pure []*ast.FuncDecl
+	for _, name := range []string{"PureFunction", "SingleReturn", "MultipleReturns", "ErrorReturn"} {
+		fd := analysis.FindFuncDecl(pkg, name)
+		if fd == nil {
+			b.Fatalf("%s not found", name)
+		}
+		pure = append(pure, fd)
+	}
+
+	for _, bc := range []struct {
+		name    string
+		enabled bool
+	}{
+		{"full", false},
+		{"fast", true},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			defer analysis.SetFastPath(bc.enabled)()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for _, fd := range pure {
+					analysis.AnalyzeFunction(pkg, fd)
+				}
+			}
+		})
+	}
+}
+
 func BenchmarkAnalyze_MutationPackage(b *testing.B) {
 	pkg := loadTestPackageBench(b, "mutation")
 
@@ -56,6 +101,100 @@ func BenchmarkAnalyze_MutationPackage(b *testing.B) {
 	}
 }
 
+func BenchmarkAnalyze_ScaledMutation(b *testing.B) {
+	pkg := loadScaledMutationPackage(b, 50)
+
+	for _, bc := range []struct {
+		name    string
+		workers int
+	}{
+		{"sequential", 1},
+		{"parallel", 0},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			opts := analysis.Options{IncludeUnexported: true, Workers: bc.workers}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, _ = analysis.Analyze(pkg, opts)
+			}
+		})
+	}
+}
+
+// scaledMutationDecls returns a pattern matching the top-level
+// identifiers declared in src: functions, types, variables, and
+// constants. Methods are scoped to their receiver and need no
+// renaming.
+func scaledMutationDecls(src []byte) (*regexp.Regexp, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil {
+				names = append(names, d.Name.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch sp := spec.(type) {
+				case *ast.TypeSpec:
+					names = append(names, sp.Name.Name)
+				case *ast.ValueSpec:
+					for _, n := range sp.Names {
+						if n.Name != "_" {
+							names = append(names, n.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+	return regexp.Compile(`\b(` + strings.Join(names, "|") + `)\b`)
+}
+
+// loadScaledMutationPackage writes copies of the mutation fixture
+// into a single package in a temporary module, suffixing each
+// copy's top-level identifiers so they do not collide, and loads
+// the result.
+func loadScaledMutationPackage(b *testing.B, copies int) *packages.Package {
+	b.Helper()
+	src, err := os.ReadFile(filepath.Join(testdataPath("mutation"), "mutation.go"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	decls, err := scaledMutationDecls(src)
+	if err != nil {
+		b.Fatalf("parsing mutation fixture: %v", err)
+	}
+
+	dir := b.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module scaled\n\ngo 1.25\n"), 0o644); err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < copies; i++ {
+		copySrc := decls.ReplaceAll(src, []byte(fmt.Sprintf("${1}%d", i)))
+		name := filepath.Join(dir, fmt.Sprintf("mutation%d.go", i))
+		if err := os.WriteFile(name, copySrc, 0o644); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	pkgs, err := packages.Load(&packages.Config{Mode: loader.LoadMode, Dir: dir}, ".")
+	if err != nil {
+		b.Fatalf("loading scaled mutation package: %v", err)
+	}
+	if len(pkgs) != 1 {
+		b.Fatalf("loading scaled mutation package: expected 1 package, got %d", len(pkgs))
+	}
+	if len(pkgs[0].Errors) > 0 {
+		b.Fatalf("loading scaled mutation package: %v", pkgs[0].Errors)
+	}
+	return pkgs[0]
+}
+
 // ---------------------------------------------------------------------------
 // SC-001: 100% P0 detection with zero false positives across 50+ functions
 // ---------------------------------------------------------------------------
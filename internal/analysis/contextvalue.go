@@ -0,0 +1,154 @@
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// detectContextValues reports calls to context.WithValue in fd whose
+// resulting context leaves the function: it is returned, passed as a
+// call argument (e.g. r.WithContext(ctx) or next(ctx)), or assigned
+// to a variable that is later returned, passed on, or is a named
+// result. A context that is only used locally is not reported, since
+// no caller or callee can observe the injected value.
+//
+// The effect's Target is the key expression, which is what
+// downstream code looks the value up by.
+func detectContextValues(
+	fset *token.FileSet,
+	info *types.Info,
+	fd *ast.FuncDecl,
+	pkg string,
+	funcName string,
+	seen map[string]bool,
+) []taxonomy.SideEffect {
+	if fd.Body == nil {
+		return nil
+	}
+
+	// Pass 1: WithValue calls, and the variables they are assigned to.
+	type assignment struct {
+		call *ast.CallExpr
+		pos  token.Pos
+	}
+	var calls []*ast.CallExpr
+	assigned := make(map[types.Object][]assignment)
+	record := func(lhs ast.Expr, rhs ast.Expr) {
+		call, ok := ast.Unparen(rhs).(*ast.CallExpr)
+		if !ok || !isContextWithValue(call, info) || info == nil {
+			return
+		}
+		id, ok := ast.Unparen(lhs).(*ast.Ident)
+		if !ok {
+			return
+		}
+		if obj := info.ObjectOf(id); obj != nil {
+			assigned[obj] = append(assigned[obj], assignment{call: call, pos: call.End()})
+		}
+	}
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			if isContextWithValue(node, info) {
+				calls = append(calls, node)
+			}
+		case *ast.AssignStmt:
+			if len(node.Lhs) == len(node.Rhs) {
+				for i := range node.Lhs {
+					record(node.Lhs[i], node.Rhs[i])
+				}
+			}
+		case *ast.ValueSpec:
+			if len(node.Names) == len(node.Values) {
+				for i := range node.Names {
+					record(node.Names[i], node.Values[i])
+				}
+			}
+		}
+		return true
+	})
+	if len(calls) == 0 {
+		return nil
+	}
+
+	// Pass 2: which of them leave the function.
+	escapes := make(map[*ast.CallExpr]bool)
+	onward := func(e ast.Expr) {
+		switch x := ast.Unparen(e).(type) {
+		case *ast.CallExpr:
+			if isContextWithValue(x, info) {
+				escapes[x] = true
+			}
+		case *ast.Ident:
+			if info == nil {
+				return
+			}
+			// Only assignments made before the use reach it.
+			for _, a := range assigned[info.Uses[x]] {
+				if a.pos < x.Pos() {
+					escapes[a.call] = true
+				}
+			}
+		}
+	}
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.ReturnStmt:
+			for _, r := range node.Results {
+				onward(r)
+			}
+		case *ast.CallExpr:
+			for _, arg := range node.Args {
+				onward(arg)
+			}
+		}
+		return true
+	})
+	if info != nil && fd.Type.Results != nil {
+		for _, field := range fd.Type.Results.List {
+			for _, name := range field.Names {
+				for _, a := range assigned[info.Defs[name]] {
+					escapes[a.call] = true
+				}
+			}
+		}
+	}
+
+	var effects []taxonomy.SideEffect
+	for _, call := range calls {
+		if !escapes[call] || len(call.Args) < 2 {
+			continue
+		}
+		keyExpr := types.ExprString(call.Args[1])
+		key := fmt.Sprintf("contextvalue:%s:%d", keyExpr, fset.Position(call.Pos()).Line)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		effects = append(effects, taxonomy.SideEffect{
+			ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.ContextValue), key),
+			Type:        taxonomy.ContextValue,
+			Tier:        taxonomy.TierP2,
+			Location:    fset.Position(call.Pos()).String(),
+			EndLocation: fset.Position(call.End()).String(),
+			Description: fmt.Sprintf("injects context value for key '%s'", keyExpr),
+			Target:      keyExpr,
+		})
+	}
+	return effects
+}
+
+// isContextWithValue reports whether call is context.WithValue,
+// resolving import aliases through info when available.
+func isContextWithValue(call *ast.CallExpr, info *types.Info) bool {
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "WithValue" {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && resolveImportPath(ident, info) == "context"
+}
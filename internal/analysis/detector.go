@@ -0,0 +1,268 @@
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"sync"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// Detector finds side effects in a single function declaration. The
+// built-in analyzers implement it, and programs embedding Gaze can
+// add their own with RegisterDetector for domain-specific effects
+// (e.g. calls into a feature-flag SDK that mutate global state).
+//
+// Detect is called once per analyzed function, possibly from several
+// goroutines at once, with the type information of the function's
+// package. It must not modify fn or info.
+type Detector interface {
+	Detect(fn *ast.FuncDecl, info *types.Info) []taxonomy.SideEffect
+}
+
+var (
+	detectorsMu sync.RWMutex
+	detectors   = make(map[string]Detector)
+)
+
+// RegisterDetector makes d run alongside the built-in analyzers for
+// every function analyzed afterwards. It is intended to be called
+// from an init function. Registering a nil detector, or a name that
+// is already registered, panics.
+//
+// The Type of a custom effect may be any name, including one outside
+// the taxonomy; unknown types are placed in tier P4. Fields left
+// empty are filled in: Tier from the type, Location with the
+// position of the function declaration, and ID from the function,
+// type, and Target.
+func RegisterDetector(name string, d Detector) {
+	detectorsMu.Lock()
+	defer detectorsMu.Unlock()
+	if d == nil {
+		panic("analysis: RegisterDetector detector is nil")
+	}
+	if _, dup := detectors[name]; dup {
+		panic("analysis: RegisterDetector called twice for detector " + name)
+	}
+	detectors[name] = d
+}
+
+// DetectorNames returns the names of the registered detectors in
+// sorted order.
+func DetectorNames() []string {
+	detectorsMu.RLock()
+	defer detectorsMu.RUnlock()
+	names := make([]string, 0, len(detectors))
+	for name := range detectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// customEffects runs the registered detectors not in skip on fd and
+// completes the effects they return; see RegisterDetector.
+func customEffects(fset *token.FileSet, info *types.Info, fd *ast.FuncDecl, pkgPath string, skip map[string]bool) []taxonomy.SideEffect {
+	detectorsMu.RLock()
+	registered := make(map[string]Detector, len(detectors))
+	for name, d := range detectors {
+		registered[name] = d
+	}
+	detectorsMu.RUnlock()
+
+	var effects []taxonomy.SideEffect
+	// Run in name order so results do not depend on registration
+	// order.
+	for _, name := range DetectorNames() {
+		d, ok := registered[name]
+		if !ok || skip[name] {
+			continue
+		}
+		for _, e := range d.Detect(fd, info) {
+			if e.Tier == "" {
+				e.Tier = taxonomy.TierOf(e.Type)
+			}
+			if e.Location == "" {
+				e.Location = fset.Position(fd.Pos()).String()
+			}
+			if e.ID == "" {
+				e.ID = taxonomy.GenerateID(pkgPath, fd.Name.Name, string(e.Type), e.Target)
+			}
+			effects = append(effects, e)
+		}
+	}
+	return effects
+}
+
+// returnDetector is the built-in Detector for return value effects.
+type returnDetector struct {
+	fset    *token.FileSet
+	pkgPath string
+}
+
+func (d returnDetector) Detect(fd *ast.FuncDecl, info *types.Info) []taxonomy.SideEffect {
+	return AnalyzeReturns(d.fset, info, fd, d.pkgPath, fd.Name.Name)
+}
+
+// mutationDetector is the built-in Detector for receiver and pointer
+// argument mutations. It uses the package's SSA form when available.
+type mutationDetector struct {
+	fset    *token.FileSet
+	ssaPkg  *ssa.Package
+	pkgPath string
+}
+
+func (d mutationDetector) Detect(fd *ast.FuncDecl, info *types.Info) []taxonomy.SideEffect {
+	fnObj, ok := info.Defs[fd.Name].(*types.Func)
+	if !ok {
+		return nil
+	}
+	return AnalyzeMutations(d.fset, d.ssaPkg, fd, fnObj, d.pkgPath, fd.Name.Name)
+}
+
+// tierDetector is the built-in Detector for one of the AST-based
+// P1-P3 analyzers.
+type tierDetector struct {
+	fset    *token.FileSet
+	pkgPath string
+	analyze func(*token.FileSet, *types.Info, *ast.FuncDecl, string, string) []taxonomy.SideEffect
+}
+
+func (d tierDetector) Detect(fd *ast.FuncDecl, info *types.Info) []taxonomy.SideEffect {
+	return d.analyze(d.fset, info, fd, d.pkgPath, fd.Name.Name)
+}
+
+// analyzer is a named built-in Detector of the analysis pipeline.
+type analyzer struct {
+	name string
+	doc  string
+
+	// skipPure marks analyzers that cannot report anything for a
+	// function isTriviallyPure accepts.
+	skipPure bool
+
+	detector func(fset *token.FileSet, ssaPkg *ssa.Package, pkgPath string) Detector
+}
+
+// analyzers lists the built-in analyzers in the order their effects
+// are reported.
+var analyzers = []analyzer{
+	{
+		name: "returns",
+		doc:  "ReturnValue, ErrorReturn, SentinelError, DeferredReturnMutation",
+		detector: func(fset *token.FileSet, _ *ssa.Package, pkgPath string) Detector {
+			return returnDetector{fset: fset, pkgPath: pkgPath}
+		},
+	},
+	{
+		name:     "mutations",
+		doc:      "ReceiverMutation, PointerArgMutation",
+		skipPure: true,
+		detector: func(fset *token.FileSet, ssaPkg *ssa.Package, pkgPath string) Detector {
+			return mutationDetector{fset: fset, ssaPkg: ssaPkg, pkgPath: pkgPath}
+		},
+	},
+	{
+		name:     "p1",
+		doc:      "SliceMutation, MapMutation, GlobalMutation, WriterOutput, HTTPResponseWrite, ChannelSend, ChannelClose",
+		skipPure: true,
+		detector: func(fset *token.FileSet, _ *ssa.Package, pkgPath string) Detector {
+			return tierDetector{fset: fset, pkgPath: pkgPath, analyze: AnalyzeP1Effects}
+		},
+	},
+	{
+		name:     "p2",
+		doc:      "FileSystemWrite, FileSystemDelete, FileSystemMeta, DatabaseWrite, DatabaseTransaction, GoroutineSpawn, Panic, CallbackInvocation, LogWrite, ContextCancellation, ContextValue, MethodValueEscape, LocalPointerEscape, InternalPointerEscape",
+		skipPure: true,
+		detector: func(fset *token.FileSet, _ *ssa.Package, pkgPath string) Detector {
+			return tierDetector{fset: fset, pkgPath: pkgPath, analyze: AnalyzeP2Effects}
+		},
+	},
+	{
+		name:     "p3",
+		doc:      "AtomicOp, DeferredCleanup",
+		skipPure: true,
+		detector: func(fset *token.FileSet, _ *ssa.Package, pkgPath string) Detector {
+			return tierDetector{fset: fset, pkgPath: pkgPath, analyze: AnalyzeP3Effects}
+		},
+	},
+}
+
+// AnalyzerInfo describes an analyzer that can be enabled or disabled
+// by name.
+type AnalyzerInfo struct {
+	// Name selects the analyzer in Options.Disable.
+	Name string
+
+	// Doc lists the side effect types the analyzer reports. Empty
+	// for detectors added with RegisterDetector.
+	Doc string
+
+	// Custom is true for detectors added with RegisterDetector.
+	Custom bool
+}
+
+// Analyzers returns every analyzer in the order they run: the
+// built-in ones, then the registered detectors sorted by name.
+func Analyzers() []AnalyzerInfo {
+	var out []AnalyzerInfo
+	for _, a := range analyzers {
+		out = append(out, AnalyzerInfo{Name: a.name, Doc: a.doc})
+	}
+	for _, name := range DetectorNames() {
+		out = append(out, AnalyzerInfo{Name: name, Custom: true})
+	}
+	return out
+}
+
+// SelectAnalyzers turns --enable and --disable style lists into the
+// names to set as Options.Disable. A non-empty enable runs only the
+// named analyzers; disable then removes analyzers from what would
+// run. An unknown name is an error.
+func SelectAnalyzers(enable, disable []string) ([]string, error) {
+	all := Analyzers()
+	known := make(map[string]bool, len(all))
+	for _, a := range all {
+		known[a.Name] = true
+	}
+	off := make(map[string]bool)
+	for _, name := range disable {
+		off[name] = true
+	}
+	on := make(map[string]bool)
+	for _, name := range enable {
+		on[name] = true
+	}
+	for _, names := range [][]string{enable, disable} {
+		for _, name := range names {
+			if !known[name] {
+				return nil, fmt.Errorf("unknown analyzer %q", name)
+			}
+		}
+	}
+
+	var out []string
+	for _, a := range all {
+		if off[a.Name] || (len(enable) > 0 && !on[a.Name]) {
+			out = append(out, a.Name)
+		}
+	}
+	return out, nil
+}
+
+// skipSet returns the analyzer names in Options.Disable as a set.
+func skipSet(names []string) map[string]bool {
+	if len(names) == 0 {
+		return nil
+	}
+	skip := make(map[string]bool, len(names))
+	for _, name := range names {
+		skip[name] = true
+	}
+	return skip
+}
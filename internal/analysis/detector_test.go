@@ -0,0 +1,230 @@
+package analysis_test
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/unbound-force/gaze/internal/analysis"
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// storeDetector reports a custom effect for every call to a function
+// or method whose name starts with "Store", leaving Tier, Location,
+// and ID for the analyzer to fill in.
+type storeDetector struct{}
+
+func (storeDetector) Detect(fn *ast.FuncDecl, info *types.Info) []taxonomy.SideEffect {
+	var effects []taxonomy.SideEffect
+	ast.Inspect(fn, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if f, ok := info.Uses[sel.Sel].(*types.Func); ok && strings.HasPrefix(f.Name(), "Store") {
+			effects = append(effects, taxonomy.SideEffect{
+				Type:        "CounterReset",
+				Description: "resets a counter via " + f.Name(),
+				Target:      f.Name(),
+			})
+		}
+		return true
+	})
+	return effects
+}
+
+// constDetector reports one fully populated effect for every function.
+type constDetector struct{}
+
+func (constDetector) Detect(fn *ast.FuncDecl, _ *types.Info) []taxonomy.SideEffect {
+	return []taxonomy.SideEffect{{
+		ID:       "se-custom",
+		Type:     taxonomy.LogWrite,
+		Tier:     taxonomy.TierP3,
+		Location: "custom.go:1:1",
+		Target:   fn.Name.Name,
+	}}
+}
+
+func registerDetector(t *testing.T, name string, d analysis.Detector) {
+	t.Helper()
+	analysis.RegisterDetector(name, d)
+	t.Cleanup(func() { analysis.UnregisterDetector(name) })
+}
+
+func TestRegisterDetector_CustomEffects(t *testing.T) {
+	registerDetector(t, "test-store", storeDetector{})
+
+	result := analyzeFunc(t, "p3effects", "ResetHits")
+	var got *taxonomy.SideEffect
+	for i := range result.SideEffects {
+		if result.SideEffects[i].Type == "CounterReset" {
+			got = &result.SideEffects[i]
+		}
+	}
+	if got == nil {
+		t.Fatalf("expected a CounterReset effect, got %+v", result.SideEffects)
+	}
+	if !hasEffect(result.SideEffects, taxonomy.AtomicOp) {
+		t.Error("built-in AtomicOp effect missing with a custom detector registered")
+	}
+
+	// Fields the detector left empty are filled in.
+	if got.Tier != taxonomy.TierP4 {
+		t.Errorf("Tier = %q, want P4 for a type outside the taxonomy", got.Tier)
+	}
+	if got.Location != result.Target.Location {
+		t.Errorf("Location = %q, want the function location %q", got.Location, result.Target.Location)
+	}
+	want := taxonomy.GenerateID(result.Target.Package, "ResetHits", "CounterReset", "StoreInt64")
+	if got.ID != want {
+		t.Errorf("ID = %q, want %q", got.ID, want)
+	}
+
+	other := analyzeFunc(t, "p3effects", "AddHit")
+	if hasEffect(other.SideEffects, "CounterReset") {
+		t.Errorf("AddHit calls no Store function, got %+v", other.SideEffects)
+	}
+}
+
+func TestRegisterDetector_KeepsPopulatedFields(t *testing.T) {
+	registerDetector(t, "test-const", constDetector{})
+
+	result := analyzeFunc(t, "returns", "PureFunction")
+	for _, e := range result.SideEffects {
+		if e.ID != "se-custom" {
+			continue
+		}
+		if e.Tier != taxonomy.TierP3 || e.Location != "custom.go:1:1" {
+			t.Errorf("custom fields overwritten: %+v", e)
+		}
+		return
+	}
+	t.Errorf("custom effect missing on trivially pure function: %+v", result.SideEffects)
+}
+
+func TestRegisterDetector_Panics(t *testing.T) {
+	registerDetector(t, "test-dup", constDetector{})
+
+	tests := []struct {
+		name    string
+		regName string
+		d       analysis.Detector
+		want    string
+	}{
+		{"duplicate", "test-dup", constDetector{}, "called twice for detector test-dup"},
+		{"nil", "test-nil", nil, "detector is nil"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				r := recover()
+				msg, _ := r.(string)
+				if !strings.Contains(msg, tt.want) {
+					t.Errorf("panic = %v, want containing %q", r, tt.want)
+				}
+			}()
+			analysis.RegisterDetector(tt.regName, tt.d)
+		})
+	}
+}
+
+func TestDetectorNames(t *testing.T) {
+	registerDetector(t, "test-b", constDetector{})
+	registerDetector(t, "test-a", storeDetector{})
+
+	names := analysis.DetectorNames()
+	if len(names) != 2 || names[0] != "test-a" || names[1] != "test-b" {
+		t.Errorf("DetectorNames() = %v, want [test-a test-b]", names)
+	}
+}
+
+func TestSelectAnalyzers(t *testing.T) {
+	tests := []struct {
+		name    string
+		enable  []string
+		disable []string
+		want    []string
+		wantErr string
+	}{
+		{name: "default", want: nil},
+		{name: "disable", disable: []string{"p3", "mutations"}, want: []string{"mutations", "p3"}},
+		{name: "enable", enable: []string{"returns", "p1"}, want: []string{"mutations", "p2", "p3"}},
+		{name: "enable and disable", enable: []string{"returns", "p1"}, disable: []string{"p1"}, want: []string{"mutations", "p1", "p2", "p3"}},
+		{name: "unknown", disable: []string{"TimeDependency"}, wantErr: `unknown analyzer "TimeDependency"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := analysis.SelectAnalyzers(tt.enable, tt.disable)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SelectAnalyzers: %v", err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("SelectAnalyzers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzers_IncludesRegistered(t *testing.T) {
+	registerDetector(t, "test-store", storeDetector{})
+
+	var names []string
+	for _, a := range analysis.Analyzers() {
+		names = append(names, a.Name)
+		if a.Custom != (a.Name == "test-store") {
+			t.Errorf("%s: Custom = %v", a.Name, a.Custom)
+		}
+	}
+	if got := strings.Join(names, ","); got != "returns,mutations,p1,p2,p3,test-store" {
+		t.Errorf("Analyzers() names = %s", got)
+	}
+
+	disabled, err := analysis.SelectAnalyzers([]string{"test-store"}, nil)
+	if err != nil {
+		t.Fatalf("SelectAnalyzers: %v", err)
+	}
+	if got := strings.Join(disabled, ","); got != "returns,mutations,p1,p2,p3" {
+		t.Errorf("enabling only test-store disabled %s", got)
+	}
+}
+
+func TestAnalyze_Disable(t *testing.T) {
+	pkg := loadTestPackage(t, "p1effects")
+	results, err := analysis.Analyze(pkg, analysis.Options{
+		FunctionFilter: "MutateGlobal",
+		Disable:        []string{"p1"},
+	})
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	if hasEffect(results[0].SideEffects, taxonomy.GlobalMutation) {
+		t.Error("GlobalMutation reported with the p1 analyzer disabled")
+	}
+
+	pkg = loadTestPackage(t, "sentinel")
+	results, err = analysis.Analyze(pkg, analysis.Options{Disable: []string{"returns"}})
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	for _, r := range results {
+		if r.Target.Function == "<package>" {
+			t.Errorf("sentinel result reported with the returns analyzer disabled: %+v", r.SideEffects)
+		}
+		for _, e := range r.SideEffects {
+			if e.Type == taxonomy.ReturnValue || e.Type == taxonomy.ErrorReturn {
+				t.Errorf("%s: %s reported with the returns analyzer disabled", r.Target.Function, e.Type)
+			}
+		}
+	}
+}
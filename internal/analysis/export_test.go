@@ -37,3 +37,24 @@ func SafeSSABuild(buildFn func()) any {
 func ExprRootIdent(expr ast.Expr) *ast.Ident {
 	return exprRootIdent(expr)
 }
+
+// IsTriviallyPure is exported for testing. See isTriviallyPure.
+func IsTriviallyPure(info *types.Info, fd *ast.FuncDecl) bool {
+	return isTriviallyPure(info, fd)
+}
+
+// SetFastPath enables or disables the pure-function fast path and
+// returns a function that restores the previous setting.
+func SetFastPath(enabled bool) (restore func()) {
+	prev := fastPath
+	fastPath = enabled
+	return func() { fastPath = prev }
+}
+
+// UnregisterDetector removes a detector added with RegisterDetector
+// so tests do not leak detectors into each other.
+func UnregisterDetector(name string) {
+	detectorsMu.Lock()
+	defer detectorsMu.Unlock()
+	delete(detectors, name)
+}
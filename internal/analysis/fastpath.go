@@ -0,0 +1,65 @@
+package analysis
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// fastPath enables the pure-function early-out in analyzeFunction.
+// It is a variable so tests and benchmarks can compare the fast path
+// against the full detector run.
+var fastPath = true
+
+// isTriviallyPure reports whether fd's body is simple enough that no
+// detector other than return analysis can report an effect for it: it
+// contains no variable declarations, assignments, or increments, no
+// calls (which includes conversions and builtins), no go, defer, or
+// select statements, no channel sends or receives, no address-of
+// operators, no range loops, no function literals, no method values,
+// and no returned pointer-typed fields. Such a function can only
+// compute its results from its inputs, so the SSA-based mutation
+// analysis and the P1-P3 detectors are skipped. The check is
+// deliberately conservative; a false negative only costs the full
+// analysis.
+func isTriviallyPure(info *types.Info, fd *ast.FuncDecl) bool {
+	if fd.Body == nil || info == nil {
+		return false
+	}
+	pure := true
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		if !pure {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.AssignStmt, *ast.IncDecStmt, *ast.CallExpr,
+			*ast.GoStmt, *ast.DeferStmt, *ast.SendStmt,
+			*ast.SelectStmt, *ast.RangeStmt, *ast.FuncLit:
+			pure = false
+		case *ast.GenDecl:
+			// A local variable can hold a nil map or short slice
+			// that detectRuntimePanics reports an index into.
+			if node.Tok == token.VAR {
+				pure = false
+			}
+		case *ast.UnaryExpr:
+			// A returned &T{} is a LocalPointerEscape.
+			if node.Op == token.ARROW || node.Op == token.AND {
+				pure = false
+			}
+		case *ast.SelectorExpr:
+			if sel, ok := info.Selections[node]; ok && sel.Kind() != types.FieldVal {
+				pure = false
+			}
+		case *ast.ReturnStmt:
+			// A returned s.cur is an InternalPointerEscape.
+			for _, r := range node.Results {
+				if _, ok := ast.Unparen(r).(*ast.SelectorExpr); ok && isPointerType(info.TypeOf(r)) {
+					pure = false
+				}
+			}
+		}
+		return pure
+	})
+	return pure
+}
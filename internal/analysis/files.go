@@ -0,0 +1,48 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/unbound-force/gaze/internal/gofiles"
+)
+
+// skipFile reports whether the functions declared in the file at
+// path are excluded by opts: the file matches one of opts.Exclude,
+// or opts.IgnoreGenerated is set and gofiles.IsGenerated reports it.
+func skipFile(path string, opts Options) bool {
+	if len(opts.Exclude) > 0 {
+		rel := path
+		if cwd, err := os.Getwd(); err == nil {
+			if r, err := filepath.Rel(cwd, path); err == nil {
+				rel = r
+			}
+		}
+		rel = filepath.ToSlash(rel)
+		for _, pattern := range opts.Exclude {
+			if matchExclude(pattern, rel) {
+				return true
+			}
+		}
+	}
+	return opts.IgnoreGenerated && gofiles.IsGenerated(path)
+}
+
+// matchExclude matches a slash-separated path against an exclude
+// glob with the same rules as the docscan exclude list: filepath.Match
+// syntax, a "dir/**" suffix for everything under dir, and patterns
+// without a slash also matching the base name.
+func matchExclude(pattern, rel string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+		return rel == prefix || strings.HasPrefix(rel, prefix+"/")
+	}
+	if matched, err := filepath.Match(pattern, rel); err == nil && matched {
+		return true
+	}
+	if !strings.Contains(pattern, "/") {
+		matched, err := filepath.Match(pattern, filepath.Base(rel))
+		return err == nil && matched
+	}
+	return false
+}
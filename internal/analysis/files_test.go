@@ -0,0 +1,39 @@
+package analysis_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/unbound-force/gaze/internal/analysis"
+)
+
+func TestAnalyze_ExcludeAndIgnoreGenerated(t *testing.T) {
+	pkg := loadTestPackage(t, "generated")
+
+	tests := []struct {
+		name string
+		opts analysis.Options
+		want []string
+	}{
+		{"default", analysis.Options{}, []string{"Handwritten", "Generated", "Mocked"}},
+		{"ignore generated", analysis.Options{IgnoreGenerated: true}, []string{"Handwritten", "Mocked"}},
+		{"exclude base name", analysis.Options{Exclude: []string{"mock_*.go"}}, []string{"Handwritten", "Generated"}},
+		{"exclude directory", analysis.Options{Exclude: []string{"testdata/**"}}, nil},
+		{"exclude no match", analysis.Options{Exclude: []string{"other/*.go"}}, []string{"Handwritten", "Generated", "Mocked"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := analysis.Analyze(pkg, tt.opts)
+			if err != nil {
+				t.Fatalf("Analyze failed: %v", err)
+			}
+			var got []string
+			for _, r := range results {
+				got = append(got, r.Target.Function)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("functions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
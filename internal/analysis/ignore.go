@@ -0,0 +1,71 @@
+package analysis
+
+import (
+	"go/ast"
+	"strings"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// ignoreDirective is the comment prefix that suppresses side effects
+// on the function it documents:
+//
+//	//gaze:ignore                             suppress every effect
+//	//gaze:ignore SliceMutation GlobalMutation suppress these types
+//
+// Types may be separated by spaces or commas. Multiple directives on
+// one function combine.
+const ignoreDirective = "//gaze:ignore"
+
+// parseIgnoreDirective reads the //gaze:ignore directives in doc. It
+// reports whether all types are suppressed, the set of suppressed
+// types otherwise, and a warning for each unknown type name.
+func parseIgnoreDirective(doc *ast.CommentGroup) (all bool, types map[taxonomy.SideEffectType]bool, warnings []string) {
+	if doc == nil {
+		return false, nil, nil
+	}
+	for _, c := range doc.List {
+		rest, ok := strings.CutPrefix(c.Text, ignoreDirective)
+		if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+			continue
+		}
+		fields := strings.FieldsFunc(rest, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == ','
+		})
+		if len(fields) == 0 {
+			all = true
+			continue
+		}
+		for _, f := range fields {
+			t := taxonomy.SideEffectType(f)
+			if !taxonomy.IsKnownType(t) {
+				warnings = append(warnings,
+					"gaze:ignore: unknown side effect type "+f+"; ignoring it")
+				continue
+			}
+			if types == nil {
+				types = make(map[taxonomy.SideEffectType]bool)
+			}
+			types[t] = true
+		}
+	}
+	return all, types, warnings
+}
+
+// applyIgnoreDirective removes the effects suppressed by fd's
+// //gaze:ignore directives. It returns the remaining effects, the
+// number suppressed, and any warnings about the directives.
+func applyIgnoreDirective(fd *ast.FuncDecl, effects []taxonomy.SideEffect) ([]taxonomy.SideEffect, int, []string) {
+	all, types, warnings := parseIgnoreDirective(fd.Doc)
+	if !all && len(types) == 0 {
+		return effects, 0, warnings
+	}
+	kept := effects[:0:0]
+	for _, e := range effects {
+		if all || types[e.Type] {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	return kept, len(effects) - len(kept), warnings
+}
@@ -0,0 +1,436 @@
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// summarizer propagates the observable side effects of called
+// functions to their callers. It resolves static calls to function
+// declarations in the analyzed package's module and caches one
+// effect summary per function and remaining depth. It is safe for
+// concurrent use by the analysis workers.
+type summarizer struct {
+	depth  int
+	target *packages.Package
+	ssaPkg *ssa.Package
+	decls  map[*types.Func]funcDecl
+	skip   map[string]bool
+
+	mu   sync.Mutex
+	memo map[summaryKey][]taxonomy.SideEffect
+}
+
+// funcDecl is a function declaration and the package it belongs to.
+type funcDecl struct {
+	pkg *packages.Package
+	fd  *ast.FuncDecl
+}
+
+// summaryKey identifies a memoized summary.
+type summaryKey struct {
+	fn    *types.Func
+	depth int
+}
+
+// newSummarizer indexes the function declarations of every package
+// in pkg's module that was loaded with syntax, which with
+// loader.LoadMode includes pkg's same-module dependencies. ssaPkg is
+// used for mutation analysis of functions in pkg; functions in other
+// packages use the AST mutation fallback to avoid building SSA for
+// them. Callee effects from the analyzers in skip are not
+// propagated. Returns nil when depth is not positive.
+func newSummarizer(pkg *packages.Package, ssaPkg *ssa.Package, depth int, skip map[string]bool) *summarizer {
+	if depth <= 0 {
+		return nil
+	}
+	s := &summarizer{
+		depth:  depth,
+		target: pkg,
+		ssaPkg: ssaPkg,
+		decls:  make(map[*types.Func]funcDecl),
+		skip:   skip,
+		memo:   make(map[summaryKey][]taxonomy.SideEffect),
+	}
+	packages.Visit([]*packages.Package{pkg}, nil, func(p *packages.Package) {
+		if p != pkg && !sameModule(pkg, p) {
+			return
+		}
+		if p.TypesInfo == nil {
+			return
+		}
+		for _, file := range p.Syntax {
+			for _, decl := range file.Decls {
+				fd, ok := decl.(*ast.FuncDecl)
+				if !ok || fd.Body == nil {
+					continue
+				}
+				if fn, ok := p.TypesInfo.Defs[fd.Name].(*types.Func); ok {
+					s.decls[fn] = funcDecl{pkg: p, fd: fd}
+				}
+			}
+		}
+	})
+	return s
+}
+
+// sameModule reports whether p belongs to the same module as pkg.
+func sameModule(pkg, p *packages.Package) bool {
+	return pkg.Module != nil && p.Module != nil && pkg.Module.Path == p.Module.Path
+}
+
+// propagated returns the effects fd inherits from the functions it
+// calls, up to the summarizer's depth. A declaration without a body
+// (assembly or linkname) calls nothing and inherits nothing. Calls
+// back into fd are not followed, so fd does not inherit its own
+// effects through a cycle.
+func (s *summarizer) propagated(fd *ast.FuncDecl) []taxonomy.SideEffect {
+	if fd.Body == nil {
+		return nil
+	}
+	visiting := make(map[*types.Func]bool)
+	if fn, ok := s.target.TypesInfo.Defs[fd.Name].(*types.Func); ok {
+		visiting[fn] = true
+	}
+	effects, _ := s.calleeEffects(funcDecl{pkg: s.target, fd: fd}, s.depth, visiting)
+	return effects
+}
+
+// summary returns fn's own side effects plus, while depth remains,
+// those of its callees, in fn's own terms (its parameters and
+// receiver). Effects a caller cannot observe through the call —
+// return values and errors — are excluded. visiting holds the
+// functions on the current call path, so recursion terminates.
+//
+// The second result holds the functions outside fn's own call tree
+// whose calls were skipped because they were already being visited.
+// Such a summary depends on the call path that reached fn, so it is
+// only memoized when that set is empty.
+func (s *summarizer) summary(fn *types.Func, decl funcDecl, depth int, visiting map[*types.Func]bool) ([]taxonomy.SideEffect, map[*types.Func]bool) {
+	key := summaryKey{fn: fn, depth: depth}
+	s.mu.Lock()
+	cached, ok := s.memo[key]
+	s.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	var ssaPkg *ssa.Package
+	if decl.pkg == s.target {
+		ssaPkg = s.ssaPkg
+	}
+	own := analyzeFunction(decl.pkg.Fset, decl.pkg, ssaPkg, decl.fd, nil, s.skip)
+	var effects []taxonomy.SideEffect
+	for _, e := range own.SideEffects {
+		if !isReturnEffect(e.Type) {
+			effects = append(effects, e)
+		}
+	}
+	var cut map[*types.Func]bool
+	if depth > 0 {
+		visiting[fn] = true
+		var callees []taxonomy.SideEffect
+		callees, cut = s.calleeEffects(decl, depth, visiting)
+		inherited, _, _ := applyIgnoreDirective(decl.fd, callees)
+		effects = append(effects, inherited...)
+		delete(visiting, fn)
+		delete(cut, fn)
+	}
+
+	if len(cut) == 0 {
+		s.mu.Lock()
+		s.memo[key] = effects
+		s.mu.Unlock()
+	}
+	return effects, cut
+}
+
+// isReturnEffect reports whether t describes a function's results,
+// which belong to its own contract rather than to its callers'.
+func isReturnEffect(t taxonomy.SideEffectType) bool {
+	switch t {
+	case taxonomy.ReturnValue, taxonomy.ErrorReturn,
+		taxonomy.SentinelError, taxonomy.DeferredReturnMutation:
+		return true
+	}
+	return false
+}
+
+// calleeEffects walks caller's body for static calls to functions in
+// the module and maps each callee's summary (at depth-1) onto the
+// caller. Duplicate effects reached through several call sites are
+// reported once. It also returns the functions whose calls were
+// skipped, at any depth, because they were already being visited.
+func (s *summarizer) calleeEffects(caller funcDecl, depth int, visiting map[*types.Func]bool) ([]taxonomy.SideEffect, map[*types.Func]bool) {
+	if caller.fd.Body == nil {
+		return nil, nil
+	}
+	info := caller.pkg.TypesInfo
+	scope := newCallerScope(info, caller.fd)
+	pkgPath := caller.pkg.PkgPath
+	funcName := caller.fd.Name.Name
+
+	var effects []taxonomy.SideEffect
+	var cut map[*types.Func]bool
+	seen := make(map[string]bool)
+	ast.Inspect(caller.fd.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		callee := typeutil.StaticCallee(info, call)
+		if callee == nil {
+			return true
+		}
+		callee = callee.Origin()
+		decl, ok := s.decls[callee]
+		if !ok {
+			return true
+		}
+		if visiting[callee] {
+			if cut == nil {
+				cut = make(map[*types.Func]bool)
+			}
+			cut[callee] = true
+			return true
+		}
+
+		loc := caller.pkg.Fset.Position(call.Pos()).String()
+		end := caller.pkg.Fset.Position(call.End()).String()
+		site := newCallSite(info, call, decl.fd)
+		summary, calleeCut := s.summary(callee, decl, depth-1, visiting)
+		for f := range calleeCut {
+			if cut == nil {
+				cut = make(map[*types.Func]bool)
+			}
+			cut[f] = true
+		}
+		for _, e := range summary {
+			mapped, ok := site.attribute(scope, e)
+			if !ok {
+				continue
+			}
+			mapped.Description = viaDescription(mapped.Description, calleeName(callee))
+			key := string(mapped.Type) + "|" + mapped.Target + "|" + mapped.Description
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			mapped.ID = taxonomy.GenerateID(pkgPath, funcName, string(mapped.Type), loc+"|"+mapped.Target+"|"+e.ID)
+			mapped.Location = loc
+			mapped.EndLocation = end
+			mapped.Classification = nil
+			effects = append(effects, mapped)
+		}
+		return true
+	})
+	return effects, cut
+}
+
+// calleeName returns a short display name such as "Save" or
+// "(*Store).Save", qualified with the package name.
+func calleeName(fn *types.Func) string {
+	name := fn.Name()
+	if sig, ok := fn.Type().(*types.Signature); ok && sig.Recv() != nil {
+		recv := sig.Recv().Type()
+		ptr := ""
+		if p, ok := recv.(*types.Pointer); ok {
+			recv, ptr = p.Elem(), "*"
+		}
+		if named, ok := recv.(*types.Named); ok {
+			name = fmt.Sprintf("(%s%s).%s", ptr, named.Obj().Name(), name)
+		}
+	}
+	if fn.Pkg() != nil {
+		name = fn.Pkg().Name() + "." + name
+	}
+	return name
+}
+
+// viaDescription records that an effect was reached through callee,
+// extending an existing "(via ...)" chain rather than nesting it.
+func viaDescription(desc, callee string) string {
+	if i := strings.LastIndex(desc, " (via "); i >= 0 && strings.HasSuffix(desc, ")") {
+		return desc[:i] + " (via " + callee + " → " + desc[i+len(" (via "):]
+	}
+	return desc + " (via " + callee + ")"
+}
+
+// callerScope identifies the receiver and parameters of a caller, so
+// that state reached through a call can be attributed to them.
+type callerScope struct {
+	info   *types.Info
+	recv   types.Object
+	params map[types.Object]bool
+}
+
+func newCallerScope(info *types.Info, fd *ast.FuncDecl) callerScope {
+	cs := callerScope{info: info, params: make(map[types.Object]bool)}
+	if fd.Recv != nil && len(fd.Recv.List) > 0 && len(fd.Recv.List[0].Names) > 0 {
+		cs.recv = info.Defs[fd.Recv.List[0].Names[0]]
+	}
+	for _, field := range fd.Type.Params.List {
+		for _, n := range field.Names {
+			if obj := info.Defs[n]; obj != nil {
+				cs.params[obj] = true
+			}
+		}
+	}
+	return cs
+}
+
+// owner is who holds the state a call argument refers to.
+type owner int
+
+const (
+	ownerLocal    owner = iota // a caller local; changes are not observable
+	ownerReceiver              // the caller's receiver
+	ownerParam                 // one of the caller's parameters
+	ownerGlobal                // a package-level variable
+)
+
+// owner classifies the variable at the root of expr, looking through
+// a leading & so that &s.cfg is owned by s.
+func (cs callerScope) owner(expr ast.Expr) (owner, *ast.Ident) {
+	if u, ok := expr.(*ast.UnaryExpr); ok && u.Op == token.AND {
+		expr = u.X
+	}
+	root := exprRootIdent(expr)
+	if root == nil {
+		return ownerLocal, nil
+	}
+	obj := cs.info.Uses[root]
+	switch {
+	case obj == nil:
+		return ownerLocal, nil
+	case obj.Pkg() != nil && obj.Parent() == obj.Pkg().Scope():
+		return ownerGlobal, root
+	case obj == cs.recv:
+		return ownerReceiver, root
+	case cs.params[obj]:
+		return ownerParam, root
+	}
+	return ownerLocal, nil
+}
+
+// sharesState reports whether a variable of type t refers to state
+// its holder's caller can also reach, so that writes through it are
+// observable outside the function.
+func sharesState(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Slice, *types.Map, *types.Chan, *types.Interface:
+		return true
+	}
+	return false
+}
+
+// callSite maps a callee's receiver and parameter names to the
+// argument expressions of one call.
+type callSite struct {
+	recvName string
+	recvExpr ast.Expr
+	args     map[string]ast.Expr
+}
+
+func newCallSite(info *types.Info, call *ast.CallExpr, fd *ast.FuncDecl) callSite {
+	site := callSite{args: make(map[string]ast.Expr)}
+	if fd.Recv != nil && len(fd.Recv.List) > 0 && len(fd.Recv.List[0].Names) > 0 {
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+			if selection := info.Selections[sel]; selection != nil && selection.Kind() == types.MethodVal {
+				site.recvName = fd.Recv.List[0].Names[0].Name
+				site.recvExpr = sel.X
+			}
+		}
+	}
+	i := 0
+	for _, field := range fd.Type.Params.List {
+		if _, variadic := field.Type.(*ast.Ellipsis); variadic {
+			break
+		}
+		for _, n := range field.Names {
+			if i < len(call.Args) {
+				site.args[n.Name] = call.Args[i]
+			}
+			i++
+		}
+		if len(field.Names) == 0 {
+			i++
+		}
+	}
+	return site
+}
+
+// attribute rewrites a callee effect in the caller's terms. Effects
+// on the callee's receiver or parameters follow the corresponding
+// call argument: they become receiver or pointer argument mutations
+// of the caller, or global mutations, and are dropped when the
+// argument is a local of the caller. Other effects carry over as is.
+func (site callSite) attribute(cs callerScope, e taxonomy.SideEffect) (taxonomy.SideEffect, bool) {
+	var calleeRoot string
+	switch e.Type {
+	case taxonomy.ReceiverMutation:
+		calleeRoot = site.recvName
+	case taxonomy.PointerArgMutation, taxonomy.SliceMutation, taxonomy.MapMutation:
+		calleeRoot, _, _ = strings.Cut(e.Target, ".")
+	default:
+		return e, true
+	}
+
+	var arg ast.Expr
+	if calleeRoot != "" && calleeRoot == site.recvName {
+		arg = site.recvExpr
+	} else if a, ok := site.args[calleeRoot]; ok {
+		arg = a
+	} else if e.Type == taxonomy.SliceMutation || e.Type == taxonomy.MapMutation {
+		// Not rooted in a parameter: the callee's own state.
+		return e, true
+	} else {
+		return e, false
+	}
+
+	kind, root := cs.owner(arg)
+	if kind == ownerReceiver || kind == ownerParam {
+		// Writes through a value receiver or parameter change the
+		// caller's own copy, unless they reach shared slice or map
+		// storage.
+		isWrite := e.Type == taxonomy.ReceiverMutation || e.Type == taxonomy.PointerArgMutation
+		if isWrite && !sharesState(cs.info.Uses[root].Type()) {
+			return e, false
+		}
+	}
+	name := types.ExprString(arg)
+	name = strings.TrimPrefix(name, "&")
+	// The target becomes a caller variable or field, whose type the
+	// callee's effect does not describe.
+	e.TargetType = ""
+	switch kind {
+	case ownerReceiver:
+		if e.Type == taxonomy.ReceiverMutation || e.Type == taxonomy.PointerArgMutation {
+			e.Type, e.Tier = taxonomy.ReceiverMutation, taxonomy.TierP0
+		}
+		if field := strings.TrimPrefix(name, root.Name+"."); field != name {
+			e.Target = field
+		}
+	case ownerParam:
+		if e.Type == taxonomy.ReceiverMutation {
+			e.Type, e.Tier = taxonomy.PointerArgMutation, taxonomy.TierP0
+		}
+		e.Target = root.Name
+	case ownerGlobal:
+		e.Type, e.Tier = taxonomy.GlobalMutation, taxonomy.TierP1
+		e.Target = root.Name
+	default:
+		return e, false
+	}
+	return e, true
+}
@@ -0,0 +1,130 @@
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"go/version"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// loopVarGoVersion is the first Go version in which each loop
+// iteration declares fresh loop variables.
+const loopVarGoVersion = "go1.22"
+
+// sharedLoopVarVersion returns the Go version pkg is compiled with
+// when it predates Go 1.22, where a variable declared by a for or
+// range clause is shared by every iteration, and "" otherwise. The
+// version is the go directive of the package's module; a go.mod
+// without one means Go 1.16. Packages outside a module build with
+// the toolchain's own language version and return "".
+func sharedLoopVarVersion(pkg *packages.Package) string {
+	if pkg.Module == nil {
+		return ""
+	}
+	v := pkg.Module.GoVersion
+	if v == "" {
+		v = "1.16"
+	}
+	if !version.IsValid("go"+v) || version.Compare("go"+v, loopVarGoVersion) >= 0 {
+		return ""
+	}
+	return v
+}
+
+// loopCaptureWarnings reports each goroutine in fd started from a
+// function literal that refers to a variable declared by an
+// enclosing for or range clause. Before Go 1.22 every iteration
+// shares that variable, so the goroutine sees whatever value the
+// loop has moved on to. Passing the variable as an argument, or
+// shadowing it with v := v, gives each goroutine its own copy and is
+// not reported. Warnings are in source order, one per goroutine and
+// variable.
+func loopCaptureWarnings(fset *token.FileSet, info *types.Info, fd *ast.FuncDecl, goVersion string) []string {
+	if fd.Body == nil {
+		return nil
+	}
+	var warnings []string
+	seen := make(map[string]bool)
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		var vars []*ast.Ident
+		var body *ast.BlockStmt
+		switch loop := n.(type) {
+		case *ast.RangeStmt:
+			if loop.Tok == token.DEFINE {
+				vars = identsOf(loop.Key, loop.Value)
+			}
+			body = loop.Body
+		case *ast.ForStmt:
+			if init, ok := loop.Init.(*ast.AssignStmt); ok && init.Tok == token.DEFINE {
+				vars = identsOf(init.Lhs...)
+			}
+			body = loop.Body
+		default:
+			return true
+		}
+		for _, ident := range vars {
+			obj := info.Defs[ident]
+			if obj == nil {
+				continue
+			}
+			for _, g := range capturingGoStmts(body, info, obj) {
+				pos := fset.Position(g.Pos())
+				key := fmt.Sprintf("%s:%s", pos, ident.Name)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				warnings = append(warnings, fmt.Sprintf(
+					"analysis: goroutine at %s captures loop variable %s, "+
+						"which every iteration shares before Go 1.22 (module declares go %s); "+
+						"pass it as an argument or copy it with %s := %s",
+					pos, ident.Name, goVersion, ident.Name, ident.Name))
+			}
+		}
+		return true
+	})
+	return warnings
+}
+
+// identsOf returns the non-blank identifiers among exprs.
+func identsOf(exprs ...ast.Expr) []*ast.Ident {
+	var idents []*ast.Ident
+	for _, e := range exprs {
+		if ident, ok := e.(*ast.Ident); ok && ident.Name != "_" {
+			idents = append(idents, ident)
+		}
+	}
+	return idents
+}
+
+// capturingGoStmts returns the go statements in body whose function
+// literal refers to obj, in source order.
+func capturingGoStmts(body *ast.BlockStmt, info *types.Info, obj types.Object) []*ast.GoStmt {
+	var stmts []*ast.GoStmt
+	ast.Inspect(body, func(n ast.Node) bool {
+		g, ok := n.(*ast.GoStmt)
+		if !ok {
+			return true
+		}
+		if lit, ok := g.Call.Fun.(*ast.FuncLit); ok && refersTo(lit.Body, info, obj) {
+			stmts = append(stmts, g)
+		}
+		return true
+	})
+	return stmts
+}
+
+// refersTo reports whether any identifier in node uses obj.
+func refersTo(node ast.Node, info *types.Info, obj types.Object) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && info.Uses[ident] == obj {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
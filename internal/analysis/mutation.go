@@ -168,8 +168,12 @@ func baseTypeName(expr ast.Expr) string {
 	}
 }
 
-// detectMutations walks SSA instructions to find Store operations
-// that represent receiver or pointer argument mutations.
+// detectMutations walks SSA instructions to find Store and
+// MapUpdate operations that represent receiver or pointer argument
+// mutations. Receiver mutations are reported once per top-level
+// field, described by the first kind of write seen: an append that
+// grows a slice field, a write into a map field, or a plain
+// assignment.
 func detectMutations(
 	fset *token.FileSet,
 	ssaFn *ssa.Function,
@@ -192,8 +196,36 @@ func detectMutations(
 
 	var effects []taxonomy.SideEffect
 
+	addReceiverEffect := func(instr ssa.Instruction, addr ssa.Value, fieldName, description string) {
+		if seenReceiverFields[fieldName] {
+			return
+		}
+		seenReceiverFields[fieldName] = true
+		effects = append(effects, taxonomy.SideEffect{
+			ID:          taxonomy.GenerateID(pkgPath, funcName, string(taxonomy.ReceiverMutation), fieldName),
+			Type:        taxonomy.ReceiverMutation,
+			Tier:        taxonomy.TierP0,
+			Location:    instrLocation(fset, instr),
+			EndLocation: stmtEndLocation(fset, fd, instr.Pos()),
+			Description: description,
+			Target:      fieldName,
+			TargetType:  targetTypeString(fieldAddrType(addr), pkgPath),
+		})
+	}
+
 	for _, block := range ssaFn.Blocks {
 		for _, instr := range block.Instrs {
+			// Writes into a map field: s.cache[k] = v.
+			if update, ok := instr.(*ssa.MapUpdate); ok {
+				if isMethod && receiverParam != nil {
+					if fieldName, ok := isReceiverMapUpdate(update, receiverParam); ok {
+						addReceiverEffect(update, update.Map.(*ssa.UnOp).X, fieldName,
+							fmt.Sprintf("writes to receiver map field '%s'", fieldName))
+					}
+				}
+				continue
+			}
+
 			store, ok := instr.(*ssa.Store)
 			if !ok {
 				continue
@@ -204,17 +236,11 @@ func detectMutations(
 			// Check for receiver field mutation.
 			if isMethod && receiverParam != nil {
 				if fieldName, ok := isReceiverFieldStore(store, receiverParam); ok {
-					if !seenReceiverFields[fieldName] {
-						seenReceiverFields[fieldName] = true
-						effects = append(effects, taxonomy.SideEffect{
-							ID:          taxonomy.GenerateID(pkgPath, funcName, string(taxonomy.ReceiverMutation), fieldName),
-							Type:        taxonomy.ReceiverMutation,
-							Tier:        taxonomy.TierP0,
-							Location:    loc,
-							Description: fmt.Sprintf("mutates receiver field '%s'", fieldName),
-							Target:      fieldName,
-						})
+					description := fmt.Sprintf("mutates receiver field '%s'", fieldName)
+					if isSelfAppend(store) {
+						description = fmt.Sprintf("grows receiver slice field '%s' (append)", fieldName)
 					}
+					addReceiverEffect(store, store.Addr, fieldName, description)
 				}
 			}
 
@@ -227,8 +253,10 @@ func detectMutations(
 						Type:        taxonomy.PointerArgMutation,
 						Tier:        taxonomy.TierP0,
 						Location:    loc,
+						EndLocation: stmtEndLocation(fset, fd, store.Pos()),
 						Description: fmt.Sprintf("mutates pointer argument '%s'", paramName),
 						Target:      paramName,
+						TargetType:  targetTypeString(ptrParams[paramName].Type().(*types.Pointer).Elem(), pkgPath),
 					})
 				}
 			}
@@ -269,8 +297,70 @@ func pointerParams(fn *ssa.Function, isMethod bool) map[string]*ssa.Parameter {
 // For nested field access like `c.Nested.Value = v`, this reports
 // "Nested" (the top-level field through the receiver).
 func isReceiverFieldStore(store *ssa.Store, receiver *ssa.Parameter) (string, bool) {
-	addr := store.Addr
+	return receiverField(store.Addr, receiver)
+}
+
+// isReceiverMapUpdate checks if a MapUpdate instruction writes into
+// a map held in a field of the receiver, as in `s.cache[k] = v`.
+// Returns the top-level field name if true.
+func isReceiverMapUpdate(update *ssa.MapUpdate, receiver *ssa.Parameter) (string, bool) {
+	load, ok := update.Map.(*ssa.UnOp)
+	if !ok || load.Op != token.MUL {
+		return "", false
+	}
+	return receiverField(load.X, receiver)
+}
+
+// isSelfAppend reports whether a Store writes back the result of
+// appending to the value loaded from the same address, as in
+// `s.items = append(s.items, x)`.
+func isSelfAppend(store *ssa.Store) bool {
+	call, ok := store.Val.(*ssa.Call)
+	if !ok {
+		return false
+	}
+	builtin, ok := call.Call.Value.(*ssa.Builtin)
+	if !ok || builtin.Name() != "append" || len(call.Call.Args) == 0 {
+		return false
+	}
+	load, ok := call.Call.Args[0].(*ssa.UnOp)
+	if !ok || load.Op != token.MUL {
+		return false
+	}
+	return sameFieldAddr(load.X, store.Addr)
+}
+
+// sameFieldAddr reports whether a and b address the same field of
+// the same base value, following loads of embedded or other pointer
+// fields along the way.
+func sameFieldAddr(a, b ssa.Value) bool {
+	if a == b {
+		return true
+	}
+	if la, ok := a.(*ssa.UnOp); ok && la.Op == token.MUL {
+		lb, ok := b.(*ssa.UnOp)
+		return ok && lb.Op == token.MUL && sameFieldAddr(la.X, lb.X)
+	}
+	fa, ok := a.(*ssa.FieldAddr)
+	if !ok {
+		return false
+	}
+	fb, ok := b.(*ssa.FieldAddr)
+	if !ok {
+		return false
+	}
+	return fa.Field == fb.Field && sameFieldAddr(fa.X, fb.X)
+}
 
+// receiverField reports whether addr is the address of a field of
+// the receiver, possibly nested, and returns the top-level field
+// name (closest to the receiver). Writes to a field promoted through
+// an embedded pointer, as in `o.Field = 1` for
+// `type Outer struct{ *Inner }`, land in the separately allocated
+// Inner rather than in Outer itself, so the embedded field is kept
+// as a qualifier: "Inner.Field". Each further embedded pointer adds
+// its own segment.
+func receiverField(addr ssa.Value, receiver *ssa.Parameter) (string, bool) {
 	// Walk up the FieldAddr chain to find the one whose base
 	// traces to the receiver parameter. We want the top-level
 	// field (closest to the receiver).
@@ -279,23 +369,60 @@ func isReceiverFieldStore(store *ssa.Store, receiver *ssa.Parameter) (string, bo
 		return "", false
 	}
 
-	// Walk up nested FieldAddr chain to find the one closest
-	// to the receiver.
-	topFA := fa
+	var name string
 	for {
-		innerFA, ok := topFA.X.(*ssa.FieldAddr)
-		if !ok {
-			break
+		// Walk up nested FieldAddr chain to find the one closest
+		// to the receiver.
+		topFA := fa
+		for {
+			innerFA, ok := topFA.X.(*ssa.FieldAddr)
+			if !ok {
+				break
+			}
+			topFA = innerFA
+		}
+		if name == "" {
+			name = fieldNameFromFieldAddr(topFA)
+		} else {
+			name = fieldNameFromFieldAddr(topFA) + "." + name
+		}
+
+		// Continue through a load of an embedded pointer field.
+		if embedded, ok := embeddedPointerFieldAddr(topFA.X); ok {
+			fa = embedded
+			continue
 		}
-		topFA = innerFA
-	}
 
-	// The base of the topmost FieldAddr should trace to the receiver.
-	if tracesToParam(topFA.X, receiver) {
-		return fieldNameFromFieldAddr(topFA), true
+		// The base of the topmost FieldAddr should trace to the
+		// receiver.
+		if tracesToParam(topFA.X, receiver) {
+			return name, true
+		}
+		return "", false
 	}
+}
 
-	return "", false
+// embeddedPointerFieldAddr reports whether v loads an embedded
+// pointer field, as the SSA builder does when a selector is promoted
+// through `*Inner`, and returns the address of that field.
+func embeddedPointerFieldAddr(v ssa.Value) (*ssa.FieldAddr, bool) {
+	load, ok := v.(*ssa.UnOp)
+	if !ok || load.Op != token.MUL {
+		return nil, false
+	}
+	fa, ok := load.X.(*ssa.FieldAddr)
+	if !ok {
+		return nil, false
+	}
+	pt, ok := fa.X.Type().Underlying().(*types.Pointer)
+	if !ok {
+		return nil, false
+	}
+	st, ok := pt.Elem().Underlying().(*types.Struct)
+	if !ok || fa.Field >= st.NumFields() || !st.Field(fa.Field).Embedded() {
+		return nil, false
+	}
+	return fa, true
 }
 
 // isPointerArgStore checks if a Store instruction writes through a
@@ -376,6 +503,29 @@ func tracesToParamVisited(v ssa.Value, param *ssa.Parameter, visited map[ssa.Val
 	return false
 }
 
+// fieldAddrType returns the type of the receiver field that
+// receiverField names for addr: the field closest to the receiver,
+// or for a write promoted through an embedded pointer, the field the
+// write lands in. It returns nil if addr is not a field address.
+func fieldAddrType(addr ssa.Value) types.Type {
+	fa, ok := addr.(*ssa.FieldAddr)
+	if !ok {
+		return nil
+	}
+	for {
+		inner, ok := fa.X.(*ssa.FieldAddr)
+		if !ok {
+			break
+		}
+		fa = inner
+	}
+	pt, ok := fa.Type().(*types.Pointer)
+	if !ok {
+		return nil
+	}
+	return pt.Elem()
+}
+
 // fieldNameFromFieldAddr extracts the struct field name from a
 // FieldAddr instruction.
 func fieldNameFromFieldAddr(fa *ssa.FieldAddr) string {
@@ -402,6 +552,32 @@ func instrLocation(fset *token.FileSet, instr ssa.Instruction) string {
 	return fset.Position(pos).String()
 }
 
+// stmtEndLocation returns the end position (file:line:col) of the
+// innermost statement in fd's body containing pos, so that SSA
+// instructions, which only carry a start position, can be reported
+// as a range. It returns "" when no statement contains pos.
+func stmtEndLocation(fset *token.FileSet, fd *ast.FuncDecl, pos token.Pos) string {
+	if !pos.IsValid() || fd.Body == nil {
+		return ""
+	}
+	var end token.Pos
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		if n == nil || pos < n.Pos() || pos >= n.End() {
+			return false
+		}
+		if _, ok := n.(ast.Stmt); ok {
+			if _, block := n.(*ast.BlockStmt); !block {
+				end = n.End()
+			}
+		}
+		return true
+	})
+	if !end.IsValid() {
+		return ""
+	}
+	return fset.Position(end).String()
+}
+
 // exprRootIdent recursively unwraps composite AST expressions to
 // find the base *ast.Ident. It handles SelectorExpr (x.Field),
 // IndexExpr (x[i]), StarExpr (*x), and ParenExpr ((x)). Returns
@@ -453,8 +629,13 @@ func analyzeASTMutations(
 
 // detectASTReceiverMutations checks if a method mutates its pointer
 // receiver via field assignments or method calls on receiver fields.
-// Returns nil for non-methods, value receivers, or unnamed receivers
-// (FR-004: value receiver mutations are not observable by the caller).
+// Every left-hand side of an assignment is inspected, so tuple
+// assignments such as `s.a, s.b = f()` report each field; blank
+// identifiers are skipped. One effect is returned per top-level
+// field, as the SSA detector does; assignments through the whole
+// receiver (`*s = T{}`) target the receiver name. Returns nil for
+// non-methods, value receivers, or unnamed receivers (FR-004: value
+// receiver mutations are not observable by the caller).
 func detectASTReceiverMutations(
 	fset *token.FileSet,
 	fd *ast.FuncDecl,
@@ -474,7 +655,7 @@ func detectASTReceiverMutations(
 	}
 
 	// Must have a name to trace assignments.
-	if len(recv.Names) == 0 {
+	if len(recv.Names) == 0 || recv.Names[0].Name == "_" {
 		return nil
 	}
 	receiverName := recv.Names[0].Name
@@ -483,73 +664,102 @@ func detectASTReceiverMutations(
 		return nil
 	}
 
-	// Walk the function body looking for mutations.
-	found := false
-	var foundPos token.Pos
-	ast.Inspect(fd.Body, func(n ast.Node) bool {
-		if found {
-			return false // short-circuit after first find
+	// Walk the function body looking for mutations, recording the
+	// first position at which each target is written.
+	var targets []string
+	firstPos := make(map[string]token.Pos)
+	record := func(expr ast.Expr, pos token.Pos) {
+		// A bare identifier (`_`, a local, or the receiver variable
+		// itself being reassigned) does not write through the
+		// receiver.
+		if _, ok := expr.(*ast.Ident); ok {
+			return
+		}
+		if ident := exprRootIdent(expr); ident == nil || ident.Name != receiverName {
+			return
 		}
+		target := receiverName
+		if field, ok := receiverFieldExpr(expr, receiverName); ok {
+			target = field
+		}
+		if _, seen := firstPos[target]; !seen {
+			firstPos[target] = pos
+			targets = append(targets, target)
+		}
+	}
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
 		switch node := n.(type) {
 		case *ast.AssignStmt:
-			// Check if any LHS expression has a root ident
-			// matching the receiver name (FR-003: only receiver
-			// field assignments, not local variables).
+			// Only receiver field assignments count, not local
+			// variables (FR-003).
 			for _, lhs := range node.Lhs {
-				if ident := exprRootIdent(lhs); ident != nil && ident.Name == receiverName {
-					// Ensure this is a field access, not a bare
-					// receiver assignment (e.g., `recv = something`).
-					if _, ok := lhs.(*ast.Ident); !ok {
-						found = true
-						foundPos = node.Pos()
-						return false
-					}
-				}
+				record(lhs, node.Pos())
 			}
 		case *ast.IncDecStmt:
 			// Handle c.count++ / c.count--
-			if ident := exprRootIdent(node.X); ident != nil && ident.Name == receiverName {
-				if _, ok := node.X.(*ast.Ident); !ok {
-					found = true
-					foundPos = node.Pos()
-					return false
-				}
-			}
+			record(node.X, node.Pos())
 		case *ast.CallExpr:
 			// Check for method calls on receiver fields
-			// (FR-008: e.g., si.index.Delete(key)).
+			// (FR-008: e.g., si.index.Delete(key)). A direct
+			// method call on the receiver itself (si.Method())
+			// is not a field mutation.
 			if sel, ok := node.Fun.(*ast.SelectorExpr); ok {
-				if ident := exprRootIdent(sel.X); ident != nil && ident.Name == receiverName {
-					// Must be a method call on a field, not a
-					// direct method call on the receiver itself
-					// (e.g., si.Method() vs si.field.Method()).
-					if innerSel, ok := sel.X.(*ast.SelectorExpr); ok {
-						if root := exprRootIdent(innerSel.X); root != nil && root.Name == receiverName {
-							found = true
-							foundPos = node.Pos()
-							return false
-						}
-					}
+				if _, ok := receiverFieldExpr(sel.X, receiverName); ok {
+					record(sel.X, node.Pos())
 				}
 			}
 		}
 		return true
 	})
 
-	if !found {
-		return nil
+	var effects []taxonomy.SideEffect
+	for _, target := range targets {
+		description := fmt.Sprintf("mutates receiver field '%s' (AST fallback)", target)
+		if target == receiverName {
+			description = fmt.Sprintf("mutates receiver %s (AST fallback)", receiverName)
+		}
+		effects = append(effects, taxonomy.SideEffect{
+			ID:          taxonomy.GenerateID(pkgPath, funcName, string(taxonomy.ReceiverMutation), target),
+			Type:        taxonomy.ReceiverMutation,
+			Tier:        taxonomy.TierP0,
+			Description: description,
+			Target:      target,
+			Location:    fset.Position(firstPos[target]).String(),
+			EndLocation: stmtEndLocation(fset, fd, firstPos[target]),
+		})
 	}
+	return effects
+}
 
-	// Deduplicate to one effect per receiver (consistent with SSA
-	// detector behavior for the fallback scope).
-	return []taxonomy.SideEffect{{
-		ID:          taxonomy.GenerateID(pkgPath, funcName, string(taxonomy.ReceiverMutation), receiverName),
-		Type:        taxonomy.ReceiverMutation,
-		Tier:        taxonomy.TierP0,
-		Description: fmt.Sprintf("mutates receiver %s (AST fallback)", receiverName),
-		Target:      funcName,
-		Location:    fset.Position(foundPos).String(),
-	}}
+// receiverFieldExpr returns the top-level receiver field that expr
+// accesses, e.g. "inner" for `s.inner.log[i]` or `(*s).inner`, when
+// expr is rooted at the receiver named receiverName.
+func receiverFieldExpr(expr ast.Expr, receiverName string) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.SelectorExpr:
+		base := e.X
+		for {
+			if p, ok := base.(*ast.ParenExpr); ok {
+				base = p.X
+			} else if s, ok := base.(*ast.StarExpr); ok {
+				base = s.X
+			} else {
+				break
+			}
+		}
+		if ident, ok := base.(*ast.Ident); ok {
+			return e.Sel.Name, ident.Name == receiverName
+		}
+		return receiverFieldExpr(e.X, receiverName)
+	case *ast.IndexExpr:
+		return receiverFieldExpr(e.X, receiverName)
+	case *ast.StarExpr:
+		return receiverFieldExpr(e.X, receiverName)
+	case *ast.ParenExpr:
+		return receiverFieldExpr(e.X, receiverName)
+	default:
+		return "", false
+	}
 }
 
 // detectASTPointerArgMutations checks if a function mutates any of
@@ -592,6 +802,8 @@ func detectASTPointerArgMutations(
 	ast.Inspect(fd.Body, func(n ast.Node) bool {
 		switch node := n.(type) {
 		case *ast.AssignStmt:
+			// Every LHS is inspected so tuple assignments such as
+			// `p.a, p.b = f()` report each mutated parameter.
 			for _, lhs := range node.Lhs {
 				// exprRootIdent unwraps SelectorExpr, IndexExpr,
 				// StarExpr, and ParenExpr to find the base ident.
@@ -630,6 +842,7 @@ func detectASTPointerArgMutations(
 				Description: fmt.Sprintf("mutates pointer argument '%s' (AST fallback)", name),
 				Target:      name,
 				Location:    fset.Position(pos).String(),
+				EndLocation: stmtEndLocation(fset, fd, pos),
 			})
 		}
 	}
@@ -4,12 +4,15 @@ import (
 	"errors"
 	"go/ast"
 	"go/types"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
 
 	"github.com/unbound-force/gaze/internal/analysis"
 	"github.com/unbound-force/gaze/internal/taxonomy"
 	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
 )
 
 // ---------------------------------------------------------------------------
@@ -219,6 +222,54 @@ func TestAnalyzeASTMutations_SSAPrecedence(t *testing.T) {
 	}
 }
 
+// TestAnalyzeMutations_TupleAssignment verifies that every target of
+// a multi-value assignment is reported, by both the SSA detector and
+// the AST fallback, and that blank identifiers and locals are not.
+func TestAnalyzeMutations_TupleAssignment(t *testing.T) {
+	pkg, ssaPkg := loadTestPackageWithSSA(t, "mutation")
+
+	tests := []struct {
+		recv, name string
+		effectType taxonomy.SideEffectType
+		want       []string
+	}{
+		{"*Pair", "SetFromCall", taxonomy.ReceiverMutation, []string{"a", "b"}},
+		{"*Pair", "SetMixed", taxonomy.ReceiverMutation, []string{"b"}},
+		{"*Pair", "SetWithBlank", taxonomy.ReceiverMutation, []string{"b"}},
+		{"", "SwapInto", taxonomy.PointerArgMutation, []string{"dst", "src"}},
+	}
+	for _, tt := range tests {
+		var fd *ast.FuncDecl
+		if tt.recv != "" {
+			fd = analysis.FindMethodDecl(pkg, tt.recv, tt.name)
+		} else {
+			fd = analysis.FindFuncDecl(pkg, tt.name)
+		}
+		if fd == nil {
+			t.Fatalf("%s not found in mutation package", tt.name)
+		}
+		paths := map[string]*ssa.Package{"AST": nil}
+		if ssaPkg != nil {
+			paths["SSA"] = ssaPkg
+		}
+		for path, sp := range paths {
+			t.Run(tt.name+"/"+path, func(t *testing.T) {
+				effects := analysis.AnalyzeMutations(pkg.Fset, sp, fd, toTypesFunc(pkg, fd), pkg.PkgPath, tt.name)
+				var got []string
+				for _, e := range effects {
+					if e.Type == tt.effectType {
+						got = append(got, e.Target)
+					}
+				}
+				sort.Strings(got)
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("%s targets = %v, want %v (effects: %v)", tt.effectType, got, tt.want, effects)
+				}
+			})
+		}
+	}
+}
+
 // TestExprRootIdent_AllBranches verifies that exprRootIdent correctly
 // unwraps composite AST expressions to find the base identifier.
 // Covers all branches: Ident, SelectorExpr, IndexExpr, StarExpr,
@@ -13,17 +13,19 @@ import (
 // AnalyzeP1Effects detects P1-tier side effects in a function body
 // using AST inspection. This covers:
 //   - GlobalMutation: assignment to package-level variables
-//   - WriterOutput: calls to io.Writer.Write or fmt.Fprint* with
-//     a non-stdout/stderr writer parameter
+//   - WriterOutput: calls to io.Writer.Write, and writes to a writer
+//     parameter with fmt.Fprint*, io.WriteString, or, for the output
+//     sinks *bytes.Buffer and *strings.Builder, their Write* methods
 //   - ChannelSend: send statements (ch <- v)
 //   - ChannelClose: calls to close(ch)
 //   - HTTPResponseWrite: calls to http.ResponseWriter methods
-//   - SliceMutation: direct index assignment on slice parameters
+//   - SliceMutation: direct index assignment on slice parameters,
+//     and returning append(s, ...) on a slice parameter s
 //   - MapMutation: map index assignment on map parameters
 //
 // Internally, the function dispatches to per-node-type handlers:
-// detectAssignEffects, detectIncDecEffects, detectSendEffects, and
-// detectP1CallEffects. The shared seen map preserves deduplication
+// detectAssignEffects, detectIncDecEffects, detectSendEffects,
+// detectAppendReturnEffects, and detectP1CallEffects. The shared seen map preserves deduplication
 // across all handlers.
 func AnalyzeP1Effects(
 	fset *token.FileSet,
@@ -52,10 +54,13 @@ func AnalyzeP1Effects(
 				detectIncDecEffects(fset, info, node, pkg, funcName, seen, locals)...)
 		case *ast.SendStmt:
 			effects = append(effects,
-				detectSendEffects(fset, node, pkg, funcName, seen)...)
+				detectSendEffects(fset, info, node, pkg, funcName, seen)...)
+		case *ast.ReturnStmt:
+			effects = append(effects,
+				detectAppendReturnEffects(fset, info, fd, node, pkg, funcName, seen)...)
 		case *ast.CallExpr:
 			effects = append(effects,
-				detectP1CallEffects(fset, info, node, pkg, funcName, seen)...)
+				detectP1CallEffects(fset, info, fd, node, pkg, funcName, seen)...)
 		}
 		return true
 	})
@@ -90,8 +95,10 @@ func detectAssignEffects(
 						Type:        taxonomy.GlobalMutation,
 						Tier:        taxonomy.TierP1,
 						Location:    loc,
+						EndLocation: fset.Position(node.End()).String(),
 						Description: fmt.Sprintf("assigns to package-level variable '%s'", ident.Name),
 						Target:      ident.Name,
+						TargetType:  exprTypeString(info, ident, pkg),
 					})
 				}
 			}
@@ -109,6 +116,7 @@ func detectAssignEffects(
 						Type:        taxonomy.MapMutation,
 						Tier:        taxonomy.TierP1,
 						Location:    loc,
+						EndLocation: fset.Position(node.End()).String(),
 						Description: fmt.Sprintf("writes to map '%s'", name),
 						Target:      name,
 					})
@@ -124,6 +132,7 @@ func detectAssignEffects(
 						Type:        taxonomy.SliceMutation,
 						Tier:        taxonomy.TierP1,
 						Location:    loc,
+						EndLocation: fset.Position(node.End()).String(),
 						Description: fmt.Sprintf("writes to slice element '%s'", name),
 						Target:      name,
 					})
@@ -135,6 +144,78 @@ func detectAssignEffects(
 	return effects
 }
 
+// detectAppendReturnEffects handles *ast.ReturnStmt nodes, detecting
+// SliceMutation when a result is append(s, ...) on a slice parameter
+// s. The returned slice shares s's backing array whenever s has
+// spare capacity, so the append writes into memory the caller can
+// still see through its own slice; whether it does depends on the
+// capacity at run time.
+func detectAppendReturnEffects(
+	fset *token.FileSet,
+	info *types.Info,
+	fd *ast.FuncDecl,
+	node *ast.ReturnStmt,
+	pkg string,
+	funcName string,
+	seen map[string]bool,
+) []taxonomy.SideEffect {
+	if info == nil {
+		return nil
+	}
+	var effects []taxonomy.SideEffect
+	for _, result := range node.Results {
+		call, ok := ast.Unparen(result).(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 || !isAppendCall(call, info) {
+			continue
+		}
+		ident, ok := ast.Unparen(call.Args[0]).(*ast.Ident)
+		if !ok || !isParam(fd, info.Uses[ident]) {
+			continue
+		}
+		key := "sliceappend:" + ident.Name
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		effects = append(effects, taxonomy.SideEffect{
+			ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.SliceMutation), key),
+			Type:        taxonomy.SliceMutation,
+			Tier:        taxonomy.TierP1,
+			Location:    fset.Position(call.Pos()).String(),
+			EndLocation: fset.Position(call.End()).String(),
+			Description: fmt.Sprintf("returns append to slice parameter '%s', which may mutate the caller's backing array if cap permits", ident.Name),
+			Target:      ident.Name,
+			TargetType:  exprTypeString(info, ident, pkg),
+		})
+	}
+	return effects
+}
+
+// isAppendCall reports whether call is the builtin append.
+func isAppendCall(call *ast.CallExpr, info *types.Info) bool {
+	ident, ok := ast.Unparen(call.Fun).(*ast.Ident)
+	if !ok || ident.Name != "append" {
+		return false
+	}
+	_, isBuiltin := info.Uses[ident].(*types.Builtin)
+	return isBuiltin
+}
+
+// isParam reports whether obj is one of fd's parameters.
+func isParam(fd *ast.FuncDecl, obj types.Object) bool {
+	if obj == nil || fd.Type.Params == nil {
+		return false
+	}
+	for _, field := range fd.Type.Params.List {
+		for _, name := range field.Names {
+			if name.Pos() == obj.Pos() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // detectIncDecEffects handles *ast.IncDecStmt nodes, detecting
 // GlobalMutation via increment (++) or decrement (--) operators
 // on package-level variables.
@@ -165,8 +246,10 @@ func detectIncDecEffects(
 		Type:        taxonomy.GlobalMutation,
 		Tier:        taxonomy.TierP1,
 		Location:    loc,
+		EndLocation: fset.Position(node.End()).String(),
 		Description: fmt.Sprintf("modifies package-level variable '%s'", ident.Name),
 		Target:      ident.Name,
+		TargetType:  exprTypeString(info, ident, pkg),
 	}}
 }
 
@@ -174,6 +257,7 @@ func detectIncDecEffects(
 // ChannelSend effects (ch <- value).
 func detectSendEffects(
 	fset *token.FileSet,
+	info *types.Info,
 	node *ast.SendStmt,
 	pkg string,
 	funcName string,
@@ -191,8 +275,10 @@ func detectSendEffects(
 		Type:        taxonomy.ChannelSend,
 		Tier:        taxonomy.TierP1,
 		Location:    loc,
+		EndLocation: fset.Position(node.End()).String(),
 		Description: fmt.Sprintf("sends on channel '%s'", name),
 		Target:      name,
+		TargetType:  chanElemType(info, node.Chan, pkg),
 	}}
 }
 
@@ -203,6 +289,7 @@ func detectSendEffects(
 func detectP1CallEffects(
 	fset *token.FileSet,
 	info *types.Info,
+	fd *ast.FuncDecl,
 	node *ast.CallExpr,
 	pkg string,
 	funcName string,
@@ -222,31 +309,41 @@ func detectP1CallEffects(
 				Type:        taxonomy.ChannelClose,
 				Tier:        taxonomy.TierP1,
 				Location:    loc,
+				EndLocation: fset.Position(node.End()).String(),
 				Description: fmt.Sprintf("closes channel '%s'", name),
 				Target:      name,
+				TargetType:  chanElemType(info, node.Args[0], pkg),
 			})
 		}
 	}
 
 	// Writer output and HTTP response writes via selector expressions.
 	if sel, ok := node.Fun.(*ast.SelectorExpr); ok {
-		if sel.Sel.Name == "Write" && isWriterType(info, sel.X) {
-			name := exprName(sel.X)
-			key := "writer:" + name
-			if !seen[key] {
-				seen[key] = true
-				loc := fset.Position(node.Pos()).String()
-				effects = append(effects, taxonomy.SideEffect{
-					ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.WriterOutput), name),
-					Type:        taxonomy.WriterOutput,
-					Tier:        taxonomy.TierP1,
-					Location:    loc,
-					Description: fmt.Sprintf("writes to io.Writer '%s'", name),
-					Target:      name,
-				})
+		// Package-level helpers writing to a writer parameter:
+		// fmt.Fprintf(w, ...), io.WriteString(w, s).
+		if pkgIdent, ok := sel.X.(*ast.Ident); ok && len(node.Args) > 0 && isParamExpr(fd, info, node.Args[0]) {
+			path := resolveImportPath(pkgIdent, info)
+			if (path == "fmt" && fprintFuncs[sel.Sel.Name]) || (path == "io" && sel.Sel.Name == "WriteString") {
+				if isWriterType(info, node.Args[0]) {
+					effects = append(effects,
+						writerOutputEffect(fset, info, node, node.Args[0], pkg, funcName, seen)...)
+				}
+			}
+		}
+
+		// Write* methods of an output sink parameter.
+		if sinkWriteMethods[sel.Sel.Name] && isParamExpr(fd, info, sel.X) {
+			if _, ok := outputSink(info, sel.X); ok {
+				effects = append(effects,
+					writerOutputEffect(fset, info, node, sel.X, pkg, funcName, seen)...)
 			}
 		}
 
+		if sel.Sel.Name == "Write" && isWriterType(info, sel.X) {
+			effects = append(effects,
+				writerOutputEffect(fset, info, node, sel.X, pkg, funcName, seen)...)
+		}
+
 		// HTTP response writes: calls to
 		// ResponseWriter.Write, .WriteHeader, .Header.
 		if isHTTPResponseWriter(info, sel.X) {
@@ -262,6 +359,7 @@ func detectP1CallEffects(
 						Type:        taxonomy.HTTPResponseWrite,
 						Tier:        taxonomy.TierP1,
 						Location:    loc,
+						EndLocation: fset.Position(node.End()).String(),
 						Description: fmt.Sprintf("calls %s.%s()", name, method),
 						Target:      name + "." + method,
 					})
@@ -273,6 +371,86 @@ func detectP1CallEffects(
 	return effects
 }
 
+// fprintFuncs are the fmt functions that write to an io.Writer
+// argument.
+var fprintFuncs = map[string]bool{
+	"Fprint":   true,
+	"Fprintf":  true,
+	"Fprintln": true,
+}
+
+// sinkWriteMethods are the methods of *bytes.Buffer and
+// *strings.Builder that append to the buffer.
+var sinkWriteMethods = map[string]bool{
+	"Write":       true,
+	"WriteString": true,
+	"WriteByte":   true,
+	"WriteRune":   true,
+	"ReadFrom":    true,
+}
+
+// writerOutputEffect returns a WriterOutput effect for the call node
+// writing to w, unless one was already reported for w. The
+// description names the concrete type of an output sink.
+func writerOutputEffect(
+	fset *token.FileSet,
+	info *types.Info,
+	node *ast.CallExpr,
+	w ast.Expr,
+	pkg string,
+	funcName string,
+	seen map[string]bool,
+) []taxonomy.SideEffect {
+	name := exprName(w)
+	key := "writer:" + name
+	if seen[key] {
+		return nil
+	}
+	seen[key] = true
+	kind := "io.Writer"
+	if sink, ok := outputSink(info, w); ok {
+		kind = sink
+	}
+	return []taxonomy.SideEffect{{
+		ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.WriterOutput), name),
+		Type:        taxonomy.WriterOutput,
+		Tier:        taxonomy.TierP1,
+		Location:    fset.Position(node.Pos()).String(),
+		EndLocation: fset.Position(node.End()).String(),
+		Description: fmt.Sprintf("writes to %s '%s'", kind, name),
+		Target:      name,
+	}}
+}
+
+// outputSink returns "*bytes.Buffer" or "*strings.Builder" when expr
+// has that type. Their methods append output without implementing
+// io.Writer through an interface parameter, so callers passing one in
+// observe everything written to it.
+func outputSink(info *types.Info, expr ast.Expr) (string, bool) {
+	if info == nil {
+		return "", false
+	}
+	ptr, ok := info.TypeOf(expr).(*types.Pointer)
+	if !ok {
+		return "", false
+	}
+	named, ok := ptr.Elem().(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return "", false
+	}
+	switch name := named.Obj().Pkg().Path() + "." + named.Obj().Name(); name {
+	case "bytes.Buffer", "strings.Builder":
+		return "*" + name, true
+	}
+	return "", false
+}
+
+// isParamExpr reports whether expr is one of fd's parameters.
+func isParamExpr(fd *ast.FuncDecl, info *types.Info, expr ast.Expr) bool {
+	id, ok := ast.Unparen(expr).(*ast.Ident)
+	return ok && info != nil && isParam(fd, info.Uses[id])
+}
+
 // collectLocals returns a set of names that are unambiguously local
 // to the function signature (parameters, named returns, and
 // receiver). This is used as a fast-path in isGlobalIdent to skip
@@ -428,6 +606,32 @@ func isHTTPResponseWriter(info *types.Info, expr ast.Expr) bool {
 	return tv.Type.String() == "net/http.ResponseWriter"
 }
 
+// exprTypeString returns the type of expr formatted for
+// SideEffect.TargetType, or "" without type information.
+func exprTypeString(info *types.Info, expr ast.Expr, pkgPath string) string {
+	if info == nil {
+		return ""
+	}
+	return targetTypeString(info.TypeOf(expr), pkgPath)
+}
+
+// chanElemType returns the element type of the channel expr
+// formatted for SideEffect.TargetType, or "" if it is unknown.
+func chanElemType(info *types.Info, expr ast.Expr, pkgPath string) string {
+	if info == nil {
+		return ""
+	}
+	t := info.TypeOf(expr)
+	if t == nil {
+		return ""
+	}
+	ch, ok := t.Underlying().(*types.Chan)
+	if !ok {
+		return ""
+	}
+	return targetTypeString(ch.Elem(), pkgPath)
+}
+
 // exprName returns a short readable name for an expression.
 func exprName(expr ast.Expr) string {
 	switch e := expr.(type) {
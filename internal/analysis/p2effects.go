@@ -67,15 +67,24 @@ var p2SelectorEffects = map[string]map[string]taxonomy.SideEffectType{
 // AnalyzeP2Effects detects P2-tier side effects in a function body
 // using AST inspection. This covers:
 //   - GoroutineSpawn: go statements
-//   - Panic: calls to builtin panic()
+//   - Panic: calls to builtin panic(), writes to a nil map, and
+//     constant indexes past the end of a constant-length slice
 //   - FileSystemWrite: os.WriteFile, os.Create, os.OpenFile, os.Mkdir, etc.
 //   - FileSystemDelete: os.Remove, os.RemoveAll
 //   - FileSystemMeta: os.Chmod, os.Chown, os.Symlink, etc.
 //   - LogWrite: log.Print*, log.Fatal*, slog.Info, etc.
 //   - ContextCancellation: context.WithCancel, WithTimeout, WithDeadline
+//   - ContextValue: context.WithValue whose result is returned or
+//     passed on
 //   - CallbackInvocation: calling function-typed parameters
 //   - DatabaseWrite: db.Exec, db.ExecContext on *sql.DB/*sql.Tx/*sql.Stmt
 //   - DatabaseTransaction: db.Begin, db.BeginTx on *sql.DB
+//   - MethodValueEscape: pointer-receiver method values passed as
+//     call arguments or returned
+//   - LocalPointerEscape: pointers to values allocated in the
+//     function returned to the caller
+//   - InternalPointerEscape: methods returning a pointer into the
+//     receiver's unexported fields
 func AnalyzeP2Effects(
 	fset *token.FileSet,
 	info *types.Info,
@@ -102,10 +111,23 @@ func AnalyzeP2Effects(
 		case *ast.CallExpr:
 			effects = append(effects,
 				detectP2CallEffects(fset, info, node, pkg, funcName, seen, funcParams)...)
+			effects = append(effects,
+				detectMethodValueEscapes(fset, info, node.Args,
+					"passed to "+types.ExprString(node.Fun), pkg, funcName, seen)...)
+
+		case *ast.ReturnStmt:
+			effects = append(effects,
+				detectMethodValueEscapes(fset, info, node.Results,
+					"returned", pkg, funcName, seen)...)
 		}
 		return true
 	})
 
+	effects = append(effects, detectRuntimePanics(fset, info, fd, pkg, funcName, seen)...)
+	effects = append(effects, detectContextValues(fset, info, fd, pkg, funcName, seen)...)
+	effects = append(effects, detectLocalPointerEscapes(fset, info, fd, pkg, funcName, seen)...)
+	effects = append(effects, detectInternalPointerEscapes(fset, info, fd, pkg, funcName, seen)...)
+
 	return effects
 }
 
@@ -130,6 +152,7 @@ func detectGoroutineEffects(
 		Type:        taxonomy.GoroutineSpawn,
 		Tier:        taxonomy.TierP2,
 		Location:    loc,
+		EndLocation: fset.Position(node.End()).String(),
 		Description: "spawns a goroutine",
 	}}
 }
@@ -162,6 +185,7 @@ func detectP2CallEffects(
 				Type:        taxonomy.Panic,
 				Tier:        taxonomy.TierP2,
 				Location:    loc,
+				EndLocation: fset.Position(node.End()).String(),
 				Description: "calls panic()",
 			})
 		}
@@ -190,6 +214,7 @@ func detectP2CallEffects(
 							Type:        effectType,
 							Tier:        taxonomy.TierP2,
 							Location:    loc,
+							EndLocation: fset.Position(node.End()).String(),
 							Description: fmt.Sprintf("calls %s.%s", ident.Name, sel.Sel.Name),
 							Target:      fmt.Sprintf("%s.%s", ident.Name, sel.Sel.Name),
 						})
@@ -213,6 +238,7 @@ func detectP2CallEffects(
 						Type:        effectType,
 						Tier:        taxonomy.TierP2,
 						Location:    loc,
+						EndLocation: fset.Position(node.End()).String(),
 						Description: fmt.Sprintf("calls %s on database type", sel.Sel.Name),
 						Target:      sel.Sel.Name,
 					})
@@ -234,6 +260,7 @@ func detectP2CallEffects(
 					Type:        taxonomy.CallbackInvocation,
 					Tier:        taxonomy.TierP2,
 					Location:    loc,
+					EndLocation: fset.Position(node.End()).String(),
 					Description: fmt.Sprintf("invokes callback parameter '%s'", ident.Name),
 					Target:      ident.Name,
 				})
@@ -244,6 +271,80 @@ func detectP2CallEffects(
 	return effects
 }
 
+// detectMethodValueEscapes handles MethodValueEscape detection for
+// the given argument or result expressions. A method value (s.Save)
+// or method expression ((*T).Save) bound to a pointer-receiver
+// method lets whoever holds the function value mutate the receiver
+// later, outside the call being analyzed — a deferred mutation that
+// is otherwise invisible. via describes how the value escapes (e.g.
+// "passed to bus.Subscribe" or "returned"). Interface method values
+// are skipped because the receiver's mutability is unknown.
+func detectMethodValueEscapes(
+	fset *token.FileSet,
+	info *types.Info,
+	exprs []ast.Expr,
+	via string,
+	pkg string,
+	funcName string,
+	seen map[string]bool,
+) []taxonomy.SideEffect {
+	if info == nil {
+		return nil
+	}
+	var effects []taxonomy.SideEffect
+	for _, expr := range exprs {
+		sel, ok := ast.Unparen(expr).(*ast.SelectorExpr)
+		if !ok || !isPointerReceiverMethodValue(sel, info) {
+			continue
+		}
+		name := types.ExprString(sel)
+		key := fmt.Sprintf("methodvalue:%s:%d", name, fset.Position(sel.Pos()).Line)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		receiver := types.ExprString(sel.X)
+		desc := fmt.Sprintf("method value %s %s; %s may be mutated when it is invoked", name, via, receiver)
+		if info.Selections[sel].Kind() == types.MethodExpr {
+			desc = fmt.Sprintf("method expression %s %s; its receiver argument may be mutated when it is invoked", name, via)
+		}
+		effects = append(effects, taxonomy.SideEffect{
+			ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.MethodValueEscape), key),
+			Type:        taxonomy.MethodValueEscape,
+			Tier:        taxonomy.TierP2,
+			Location:    fset.Position(sel.Pos()).String(),
+			EndLocation: fset.Position(sel.End()).String(),
+			Description: desc,
+			Target:      name,
+		})
+	}
+	return effects
+}
+
+// isPointerReceiverMethodValue reports whether sel denotes a method
+// value or method expression (not a call) of a concrete method
+// declared with a pointer receiver.
+func isPointerReceiverMethodValue(sel *ast.SelectorExpr, info *types.Info) bool {
+	selection, ok := info.Selections[sel]
+	if !ok {
+		return false
+	}
+	if selection.Kind() != types.MethodVal && selection.Kind() != types.MethodExpr {
+		return false
+	}
+	fn, ok := selection.Obj().(*types.Func)
+	if !ok {
+		return false
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return false
+	}
+	_, isPtr := recv.Type().(*types.Pointer)
+	return isPtr
+}
+
 // resolveImportPath resolves an AST identifier to its actual import
 // path using type information. For example, if the source has
 // `import myos "os"`, then `myos.WriteFile(...)` will resolve to
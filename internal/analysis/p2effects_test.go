@@ -3,6 +3,7 @@ package analysis_test
 import (
 	"go/ast"
 	"go/token"
+	"strings"
 	"testing"
 
 	"github.com/unbound-force/gaze/internal/analysis"
@@ -248,3 +249,105 @@ func TestAnalyzeP2Effects_Direct_NilBody(t *testing.T) {
 		t.Errorf("nil body: expected empty slice, got %d effects", len(effects))
 	}
 }
+
+// TestAnalyzeP2Effects_Direct_RuntimePanics verifies that
+// AnalyzeP2Effects reports nil-map writes and constant out-of-range
+// slice indexes as Panic only when the panic is certain.
+func TestAnalyzeP2Effects_Direct_RuntimePanics(t *testing.T) {
+	pkg := loadTestPackage(t, "p2effects")
+	tests := []struct {
+		funcName string
+		want     string // expected description substring; "" for no Panic
+	}{
+		{"NilMapWrite", "writes to nil map 'm'"},
+		{"NilMapIncrement", "writes to nil map 'counts'"},
+		{"SliceIndexPastLen", "index 5 is out of range for 'buf' (length 3)"},
+		{"SliceLiteralIndexPastLen", "index 2 is out of range for 'names' (length 2)"},
+		{"NilSliceIndex", "index 0 is out of range for 'b' (length 0)"},
+		{"MadeMapWrite", ""},
+		{"MapMadeInClosure", ""},
+		{"MapAddressTaken", ""},
+		{"NilMapRead", ""},
+		{"SliceIndexInBounds", ""},
+		{"SliceAppended", ""},
+		{"SliceVariableIndex", ""},
+		{"SliceVariableLen", ""},
+		{"ShadowedMap", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.funcName, func(t *testing.T) {
+			fd := analysis.FindFuncDecl(pkg, tt.funcName)
+			if fd == nil {
+				t.Fatalf("%s not found in p2effects package", tt.funcName)
+			}
+			effects := analysis.AnalyzeP2Effects(pkg.Fset, pkg.TypesInfo, fd, pkg.PkgPath, tt.funcName)
+
+			var panics []taxonomy.SideEffect
+			for _, e := range effects {
+				if e.Type == taxonomy.Panic {
+					panics = append(panics, e)
+				}
+			}
+			if tt.want == "" {
+				if len(panics) != 0 {
+					t.Errorf("expected no Panic, got %+v", panics)
+				}
+				return
+			}
+			if len(panics) != 1 {
+				t.Fatalf("expected 1 Panic, got %+v", panics)
+			}
+			if !strings.Contains(panics[0].Description, tt.want) {
+				t.Errorf("description %q does not contain %q", panics[0].Description, tt.want)
+			}
+			if panics[0].Tier != taxonomy.TierP2 {
+				t.Errorf("tier: got %s, want P2", panics[0].Tier)
+			}
+		})
+	}
+}
+
+// TestAnalyzeP2Effects_Direct_ContextValue verifies that
+// AnalyzeP2Effects reports context.WithValue only when the derived
+// context leaves the function, targeting the key.
+func TestAnalyzeP2Effects_Direct_ContextValue(t *testing.T) {
+	pkg := loadTestPackage(t, "p2effects")
+	tests := []struct {
+		funcName string
+		want     bool
+	}{
+		{"WithUser", true},
+		{"UserMiddleware", true},
+		{"WithUserNamed", true},
+		{"LocalContextValue", false},
+		{"PassedBeforeValue", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.funcName, func(t *testing.T) {
+			fd := analysis.FindFuncDecl(pkg, tt.funcName)
+			if fd == nil {
+				t.Fatalf("%s not found in p2effects package", tt.funcName)
+			}
+			effects := analysis.AnalyzeP2Effects(pkg.Fset, pkg.TypesInfo, fd, pkg.PkgPath, tt.funcName)
+
+			var values []taxonomy.SideEffect
+			for _, e := range effects {
+				if e.Type == taxonomy.ContextValue {
+					values = append(values, e)
+				}
+			}
+			if !tt.want {
+				if len(values) != 0 {
+					t.Errorf("expected no ContextValue, got %+v", values)
+				}
+				return
+			}
+			if len(values) != 1 {
+				t.Fatalf("expected 1 ContextValue, got %+v", values)
+			}
+			if values[0].Target != "userKey" || values[0].Tier != taxonomy.TierP2 {
+				t.Errorf("got target %q tier %s, want userKey P2", values[0].Target, values[0].Tier)
+			}
+		})
+	}
+}
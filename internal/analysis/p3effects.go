@@ -0,0 +1,505 @@
+// Package analysis provides the core side effect detection engine.
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// atomicMutators are the sync/atomic operations that write: the
+// prefixes of the free functions (atomic.AddInt64, StoreUint32,
+// CompareAndSwapPointer, ...) and the names of the methods on the
+// typed wrappers (atomic.Int64.Add, atomic.Bool.Store, ...). Load
+// is deliberately absent.
+var atomicMutators = []string{"CompareAndSwap", "Add", "Store", "Swap", "And", "Or"}
+
+// cleanupMethods maps the method names recognized as resource
+// cleanup in a defer statement to the format of the action in the
+// effect description; the verb takes the receiver expression.
+var cleanupMethods = map[string]string{
+	"Close":    "closes %s",
+	"Unlock":   "unlocks %s",
+	"RUnlock":  "read-unlocks %s",
+	"Done":     "marks %s done",
+	"Rollback": "rolls back %s",
+}
+
+// AnalyzeP3Effects detects P3-tier side effects in a function body
+// using AST inspection. This covers:
+//   - AtomicOp: mutating sync/atomic calls, both the free functions
+//     (atomic.AddInt64, atomic.StorePointer, ...) and methods on the
+//     typed atomics (atomic.Int64, atomic.Bool, atomic.Pointer[T],
+//     atomic.Value, ...). Loads are not reported.
+//   - DeferredCleanup: defer statements that release a resource by
+//     calling Close, Unlock, RUnlock, Done, or Rollback, or a
+//     context.CancelFunc, either directly or inside a deferred func
+//     literal. Defers inside nested func literals belong to the
+//     literal and are not reported.
+//   - RecoverBehavior: a deferred func literal that calls recover,
+//     described by what it does with the panic: always swallows it,
+//     converts it to a named result (typically the error), or
+//     re-panics, always or only on some paths. See recoverHandler.
+//   - TimeDependency: calls to time.Sleep, which block the calling
+//     goroutine. The description gives the duration when it is a
+//     constant, so tests that fake or shorten sleeps can inventory
+//     them.
+func AnalyzeP3Effects(
+	fset *token.FileSet,
+	info *types.Info,
+	fd *ast.FuncDecl,
+	pkg string,
+	funcName string,
+) []taxonomy.SideEffect {
+	if fd.Body == nil || info == nil {
+		return nil
+	}
+
+	var effects []taxonomy.SideEffect
+	seen := make(map[string]bool)
+
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			effects = append(effects,
+				detectAtomicEffects(fset, info, call, pkg, funcName, seen)...)
+			effects = append(effects,
+				detectSleep(fset, info, call, pkg, funcName, seen)...)
+		}
+		return true
+	})
+
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.DeferStmt:
+			effects = append(effects,
+				detectDeferredCleanup(fset, info, n, pkg, funcName, seen)...)
+			effects = append(effects,
+				detectRecoverBehavior(fset, info, fd, n, pkg, funcName, seen)...)
+		}
+		return true
+	})
+
+	return effects
+}
+
+// detectDeferredCleanup handles DeferredCleanup detection for a
+// defer statement. A deferred cleanup call is reported directly; a
+// deferred func literal is searched for cleanup calls in its own
+// body. The description notes the defer so the effect reads as
+// complementary to the MutexOp or ContextCancellation that acquired
+// the resource rather than a duplicate of it.
+func detectDeferredCleanup(
+	fset *token.FileSet,
+	info *types.Info,
+	node *ast.DeferStmt,
+	pkg string,
+	funcName string,
+	seen map[string]bool,
+) []taxonomy.SideEffect {
+	calls := []*ast.CallExpr{node.Call}
+	inClosure := false
+	if lit, ok := ast.Unparen(node.Call.Fun).(*ast.FuncLit); ok {
+		calls = nil
+		inClosure = true
+		ast.Inspect(lit.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.CallExpr:
+				calls = append(calls, n)
+			}
+			return true
+		})
+	}
+
+	var effects []taxonomy.SideEffect
+	for _, call := range calls {
+		target, verb, ok := cleanupCall(info, call)
+		if !ok {
+			continue
+		}
+		key := fmt.Sprintf("defer:%s:%d", target, fset.Position(call.Pos()).Line)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		desc := fmt.Sprintf("defers %s() (%s on every return path)", target, verb)
+		if inClosure {
+			desc = fmt.Sprintf("defers %s() in a closure (%s on every return path)", target, verb)
+		}
+		effects = append(effects, taxonomy.SideEffect{
+			ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.DeferredCleanup), key),
+			Type:        taxonomy.DeferredCleanup,
+			Tier:        taxonomy.TierP3,
+			Location:    fset.Position(call.Pos()).String(),
+			EndLocation: fset.Position(call.End()).String(),
+			Description: desc,
+			Target:      target,
+		})
+	}
+	return effects
+}
+
+// cleanupCall reports whether call releases a resource, returning
+// the callee expression (e.g. "f.Close" or "cancel") and the verb
+// describing the release.
+func cleanupCall(info *types.Info, call *ast.CallExpr) (target, verb string, ok bool) {
+	fun := ast.Unparen(call.Fun)
+	if isCancelFunc(info.TypeOf(fun)) {
+		return types.ExprString(fun), "cancels the context", true
+	}
+	sel, isSel := fun.(*ast.SelectorExpr)
+	if !isSel {
+		return "", "", false
+	}
+	selection, isMethod := info.Selections[sel]
+	if !isMethod || selection.Kind() != types.MethodVal {
+		return "", "", false
+	}
+	action, known := cleanupMethods[sel.Sel.Name]
+	if !known {
+		return "", "", false
+	}
+	return types.ExprString(sel), fmt.Sprintf(action, types.ExprString(sel.X)), true
+}
+
+// isCancelFunc reports whether t is context.CancelFunc or
+// context.CancelCauseFunc.
+func isCancelFunc(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != "context" {
+		return false
+	}
+	name := named.Obj().Name()
+	return name == "CancelFunc" || name == "CancelCauseFunc"
+}
+
+// detectAtomicEffects handles AtomicOp detection for a call
+// expression. The callee is resolved through types.Info, so import
+// aliases, embedded typed atomics, and user types with methods of
+// the same name are all handled correctly. It returns any new side
+// effects found, using the shared seen map for deduplication.
+func detectAtomicEffects(
+	fset *token.FileSet,
+	info *types.Info,
+	node *ast.CallExpr,
+	pkg string,
+	funcName string,
+	seen map[string]bool,
+) []taxonomy.SideEffect {
+	sel, ok := ast.Unparen(node.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	fn, ok := info.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "sync/atomic" || !isAtomicMutator(fn.Name()) {
+		return nil
+	}
+
+	var target, desc string
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		// Method on a typed atomic: the target is the atomic
+		// value being written, e.g. "c.hits".
+		target = types.ExprString(sel.X)
+		desc = fmt.Sprintf("calls %s on %s '%s'", fn.Name(), atomicTypeName(recv.Type()), target)
+	} else {
+		target = "atomic." + fn.Name()
+		desc = "calls " + target
+	}
+
+	key := fmt.Sprintf("atomic:%s.%s:%d", target, fn.Name(), fset.Position(node.Pos()).Line)
+	if seen[key] {
+		return nil
+	}
+	seen[key] = true
+	return []taxonomy.SideEffect{{
+		ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.AtomicOp), key),
+		Type:        taxonomy.AtomicOp,
+		Tier:        taxonomy.TierP3,
+		Location:    fset.Position(node.Pos()).String(),
+		EndLocation: fset.Position(node.End()).String(),
+		Description: desc,
+		Target:      target,
+	}}
+}
+
+// isAtomicMutator reports whether a sync/atomic function or method
+// name is a write.
+func isAtomicMutator(name string) bool {
+	for _, prefix := range atomicMutators {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// atomicTypeName returns the short name of a typed atomic receiver,
+// such as "atomic.Int64" or "atomic.Pointer".
+func atomicTypeName(t types.Type) string {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := t.(*types.Named); ok {
+		return "atomic." + named.Obj().Name()
+	}
+	return "atomic value"
+}
+
+// detectSleep handles TimeDependency detection for a call
+// expression: a call to time.Sleep, resolved through types.Info so
+// import aliases are followed and methods named Sleep (such as an
+// injected fake clock's) are not matched. The description calls the
+// sleep blocking, to set it apart from reads of the clock like
+// time.Now, and includes the duration when the argument is a
+// constant.
+func detectSleep(
+	fset *token.FileSet,
+	info *types.Info,
+	node *ast.CallExpr,
+	pkg string,
+	funcName string,
+	seen map[string]bool,
+) []taxonomy.SideEffect {
+	sel, ok := ast.Unparen(node.Fun).(*ast.SelectorExpr)
+	if !ok || len(node.Args) != 1 {
+		return nil
+	}
+	fn, ok := info.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "time" || fn.Name() != "Sleep" {
+		return nil
+	}
+
+	key := fmt.Sprintf("sleep:%d", fset.Position(node.Pos()).Line)
+	if seen[key] {
+		return nil
+	}
+	seen[key] = true
+
+	desc := "blocks the goroutine in time.Sleep(" + types.ExprString(node.Args[0]) + ")"
+	if tv, ok := info.Types[node.Args[0]]; ok && tv.Value != nil {
+		if d, exact := constant.Int64Val(constant.ToInt(tv.Value)); exact {
+			desc = fmt.Sprintf("blocks the goroutine in time.Sleep for %s", time.Duration(d))
+		}
+	}
+	return []taxonomy.SideEffect{{
+		ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.TimeDependency), key),
+		Type:        taxonomy.TimeDependency,
+		Tier:        taxonomy.TierP3,
+		Location:    fset.Position(node.Pos()).String(),
+		EndLocation: fset.Position(node.End()).String(),
+		Description: desc,
+		Target:      "time.Sleep",
+	}}
+}
+
+// recoverHandler is what a deferred func literal does with the
+// panic it recovers.
+type recoverHandler struct {
+	// recover is the recover() call, or nil if the literal makes
+	// none.
+	recover *ast.CallExpr
+
+	// value is the variable recover()'s result is assigned to, or
+	// nil. A comparison of it (or of the call) against nil guards
+	// the handling code rather than making it conditional.
+	value types.Object
+
+	// panics is set when the literal calls panic, and conditional
+	// when a panic or return is nested in an if, switch, select, or
+	// loop other than the nil guard, so some panics escape the
+	// handler and others do not.
+	panics      bool
+	conditional bool
+
+	// results are the named results of the enclosing function the
+	// literal assigns, in order of first assignment.
+	results []string
+}
+
+// detectRecoverBehavior handles RecoverBehavior detection for a
+// defer statement whose function literal calls recover. The
+// description names the variant, since a handler that swallows
+// panics and one that may re-panic call for different tests.
+func detectRecoverBehavior(
+	fset *token.FileSet,
+	info *types.Info,
+	fd *ast.FuncDecl,
+	node *ast.DeferStmt,
+	pkg string,
+	funcName string,
+	seen map[string]bool,
+) []taxonomy.SideEffect {
+	lit, ok := ast.Unparen(node.Call.Fun).(*ast.FuncLit)
+	if !ok {
+		return nil
+	}
+	h := inspectRecoverHandler(info, fd, lit)
+	if h.recover == nil {
+		return nil
+	}
+
+	key := fmt.Sprintf("recover:%d", fset.Position(h.recover.Pos()).Line)
+	if seen[key] {
+		return nil
+	}
+	seen[key] = true
+
+	named := make([]string, len(h.results))
+	for i, r := range h.results {
+		named[i] = "'" + r + "'"
+	}
+	var desc string
+	switch {
+	case h.panics && !h.conditional:
+		desc = "deferred recover() always re-panics; the function still panics after the handler runs"
+	case h.panics:
+		desc = "deferred recover() conditionally re-panics; the function may still panic"
+		if len(named) > 0 {
+			desc += ", and converts other panics to named result " + strings.Join(named, ", ")
+		}
+	case len(named) > 0:
+		desc = "deferred recover() converts a panic to named result " + strings.Join(named, ", ") +
+			"; the function returns instead of panicking"
+	default:
+		desc = "deferred recover() always swallows the panic; the function returns normally"
+	}
+
+	return []taxonomy.SideEffect{{
+		ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.RecoverBehavior), key),
+		Type:        taxonomy.RecoverBehavior,
+		Tier:        taxonomy.TierP3,
+		Location:    fset.Position(h.recover.Pos()).String(),
+		EndLocation: fset.Position(node.End()).String(),
+		Description: desc,
+		Target:      "recover",
+	}}
+}
+
+// inspectRecoverHandler walks the body of lit, a deferred function
+// literal in fd, for the recover call and what is done with its
+// result. Nested function literals are not entered: recover only
+// stops a panic when called directly by the deferred function.
+func inspectRecoverHandler(info *types.Info, fd *ast.FuncDecl, lit *ast.FuncLit) recoverHandler {
+	var h recoverHandler
+	resultObjs := make(map[types.Object]string)
+	if fd.Type.Results != nil {
+		for _, field := range fd.Type.Results.List {
+			for _, name := range field.Names {
+				if obj := info.Defs[name]; obj != nil && name.Name != "_" {
+					resultObjs[obj] = name.Name
+				}
+			}
+		}
+	}
+
+	var stack []ast.Node
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			switch {
+			case isBuiltinCall(info, n, "recover") && h.recover == nil:
+				h.recover = n
+			case isBuiltinCall(info, n, "panic"):
+				h.panics = true
+				h.conditional = h.conditional || h.underCondition(info, stack)
+			}
+		case *ast.ReturnStmt:
+			h.conditional = h.conditional || h.underCondition(info, stack)
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				id, ok := ast.Unparen(lhs).(*ast.Ident)
+				if !ok {
+					continue
+				}
+				if name, ok := resultObjs[info.Uses[id]]; ok && !slices.Contains(h.results, name) {
+					h.results = append(h.results, name)
+				}
+			}
+			if len(n.Lhs) == 1 && len(n.Rhs) == 1 {
+				if call, ok := ast.Unparen(n.Rhs[0]).(*ast.CallExpr); ok && isBuiltinCall(info, call, "recover") {
+					if id, ok := n.Lhs[0].(*ast.Ident); ok {
+						h.value = info.ObjectOf(id)
+					}
+				}
+			}
+		}
+		stack = append(stack, n)
+		return true
+	})
+	return h
+}
+
+// underCondition reports whether the node whose ancestors within
+// the handler are stack runs only on some paths: it is nested in a
+// switch, select, or loop, or in an if statement other than the
+// body of the nil guard on the recovered value.
+func (h *recoverHandler) underCondition(info *types.Info, stack []ast.Node) bool {
+	for i, n := range stack {
+		switch n := n.(type) {
+		case *ast.IfStmt:
+			inBody := i+1 < len(stack) && stack[i+1] == n.Body
+			if !inBody || !h.isNilGuard(info, n.Cond) {
+				return true
+			}
+		case *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt,
+			*ast.ForStmt, *ast.RangeStmt:
+			return true
+		}
+	}
+	return false
+}
+
+// isNilGuard reports whether cond is "r != nil" for the recovered
+// value r, or "recover() != nil".
+func (h *recoverHandler) isNilGuard(info *types.Info, cond ast.Expr) bool {
+	bin, ok := ast.Unparen(cond).(*ast.BinaryExpr)
+	if !ok || bin.Op != token.NEQ {
+		return false
+	}
+	isNil := func(e ast.Expr) bool {
+		id, ok := ast.Unparen(e).(*ast.Ident)
+		if !ok {
+			return false
+		}
+		_, isNil := info.Uses[id].(*types.Nil)
+		return isNil
+	}
+	isRecovered := func(e ast.Expr) bool {
+		switch e := ast.Unparen(e).(type) {
+		case *ast.CallExpr:
+			return e == h.recover
+		case *ast.Ident:
+			return h.value != nil && info.Uses[e] == h.value
+		}
+		return false
+	}
+	return (isRecovered(bin.X) && isNil(bin.Y)) || (isNil(bin.X) && isRecovered(bin.Y))
+}
+
+// isBuiltinCall reports whether call calls the builtin function
+// name, such as recover or panic.
+func isBuiltinCall(info *types.Info, call *ast.CallExpr, name string) bool {
+	id, ok := ast.Unparen(call.Fun).(*ast.Ident)
+	if !ok || id.Name != name {
+		return false
+	}
+	_, isBuiltin := info.Uses[id].(*types.Builtin)
+	return isBuiltin
+}
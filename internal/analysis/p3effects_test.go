@@ -0,0 +1,246 @@
+package analysis_test
+
+import (
+	"go/ast"
+	"go/token"
+	"reflect"
+	"testing"
+
+	"github.com/unbound-force/gaze/internal/analysis"
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// TestAnalyzeP3Effects_Direct_AtomicOp verifies that AnalyzeP3Effects
+// reports mutating sync/atomic calls, both free functions and typed
+// atomic methods, and ignores loads and look-alike user methods.
+func TestAnalyzeP3Effects_Direct_AtomicOp(t *testing.T) {
+	pkg := loadTestPackage(t, "p3effects")
+
+	for _, tt := range []struct {
+		recv, name string
+		want       []string
+	}{
+		{"", "AddHit", []string{"atomic.AddInt64"}},
+		{"", "ResetHits", []string{"atomic.StoreInt64"}},
+		{"", "ReadHits", nil},
+		{"*Counters", "Inc", []string{"c.n"}},
+		{"*Counters", "Publish", []string{"c.ready", "c.current", "c.current", "c.value"}},
+		{"*Counters", "Count", nil},
+		{"*Embedded", "Bump", []string{"e"}},
+		{"", "AddToTally", nil},
+	} {
+		var fd *ast.FuncDecl
+		if tt.recv == "" {
+			fd = analysis.FindFuncDecl(pkg, tt.name)
+		} else {
+			fd = analysis.FindMethodDecl(pkg, tt.recv, tt.name)
+		}
+		if fd == nil {
+			t.Fatalf("%s not found in p3effects package", tt.name)
+		}
+
+		effects := analysis.AnalyzeP3Effects(pkg.Fset, pkg.TypesInfo, fd, pkg.PkgPath, tt.name)
+
+		var got []string
+		for _, e := range effects {
+			if e.Type != taxonomy.AtomicOp {
+				t.Errorf("%s: unexpected effect type %s", tt.name, e.Type)
+				continue
+			}
+			if e.Tier != taxonomy.TierP3 {
+				t.Errorf("%s: AtomicOp tier: got %s, want P3", tt.name, e.Tier)
+			}
+			if e.Description == "" {
+				t.Errorf("%s: AtomicOp description must not be empty", tt.name)
+			}
+			got = append(got, e.Target)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: AtomicOp targets = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestAnalyzeP3Effects_Direct_TypedDescription verifies that typed
+// atomic effects name the method and the atomic type.
+func TestAnalyzeP3Effects_Direct_TypedDescription(t *testing.T) {
+	pkg := loadTestPackage(t, "p3effects")
+	fd := analysis.FindMethodDecl(pkg, "*Counters", "Inc")
+	if fd == nil {
+		t.Fatal("(*Counters).Inc not found in p3effects package")
+	}
+
+	effects := analysis.AnalyzeP3Effects(pkg.Fset, pkg.TypesInfo, fd, pkg.PkgPath, "Inc")
+
+	if len(effects) != 1 {
+		t.Fatalf("expected 1 effect, got %d: %v", len(effects), effects)
+	}
+	if want := "calls Add on atomic.Int64 'c.n'"; effects[0].Description != want {
+		t.Errorf("description: got %q, want %q", effects[0].Description, want)
+	}
+}
+
+// TestAnalyzeP3Effects_Direct_DeferredCleanup verifies that
+// AnalyzeP3Effects reports deferred Close, Unlock, RUnlock, Done,
+// Rollback, and cancel calls, and ignores other deferred calls and
+// defers that belong to a nested func literal.
+func TestAnalyzeP3Effects_Direct_DeferredCleanup(t *testing.T) {
+	pkg := loadTestPackage(t, "p3effects")
+
+	for _, tt := range []struct {
+		name string
+		want []string
+	}{
+		{"UseResource", []string{"r.Close"}},
+		{"Guarded", []string{"mu.Unlock", "rw.RUnlock"}},
+		{"Worker", []string{"wg.Done"}},
+		{"WithTimeout", []string{"cancel"}},
+		{"Transact", []string{"tx.Rollback"}},
+		{"DeferLog", nil},
+		{"NestedDefer", nil},
+	} {
+		fd := analysis.FindFuncDecl(pkg, tt.name)
+		if fd == nil {
+			t.Fatalf("%s not found in p3effects package", tt.name)
+		}
+
+		effects := analysis.AnalyzeP3Effects(pkg.Fset, pkg.TypesInfo, fd, pkg.PkgPath, tt.name)
+
+		var got []string
+		for _, e := range effects {
+			if e.Type != taxonomy.DeferredCleanup {
+				t.Errorf("%s: unexpected effect type %s", tt.name, e.Type)
+				continue
+			}
+			if e.Tier != taxonomy.TierP3 {
+				t.Errorf("%s: DeferredCleanup tier: got %s, want P3", tt.name, e.Tier)
+			}
+			got = append(got, e.Target)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: DeferredCleanup targets = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestAnalyzeP3Effects_Direct_DeferredDescription verifies that
+// deferred cleanup effects describe the defer and what it releases.
+func TestAnalyzeP3Effects_Direct_DeferredDescription(t *testing.T) {
+	pkg := loadTestPackage(t, "p3effects")
+
+	for name, want := range map[string]string{
+		"UseResource": "defers r.Close() (closes r on every return path)",
+		"Worker":      "defers wg.Done() (marks wg done on every return path)",
+		"WithTimeout": "defers cancel() (cancels the context on every return path)",
+		"Transact":    "defers tx.Rollback() in a closure (rolls back tx on every return path)",
+	} {
+		fd := analysis.FindFuncDecl(pkg, name)
+		if fd == nil {
+			t.Fatalf("%s not found in p3effects package", name)
+		}
+		effects := analysis.AnalyzeP3Effects(pkg.Fset, pkg.TypesInfo, fd, pkg.PkgPath, name)
+		if len(effects) != 1 {
+			t.Fatalf("%s: expected 1 effect, got %d: %v", name, len(effects), effects)
+		}
+		if effects[0].Description != want {
+			t.Errorf("%s: description: got %q, want %q", name, effects[0].Description, want)
+		}
+	}
+}
+
+// TestAnalyzeP3Effects_Direct_Sleep verifies that calls to
+// time.Sleep are reported as blocking TimeDependency effects, with
+// the duration when it is constant, and that clock reads and
+// look-alike Sleep methods are not.
+func TestAnalyzeP3Effects_Direct_Sleep(t *testing.T) {
+	pkg := loadTestPackage(t, "p3effects")
+
+	for name, want := range map[string]string{
+		"Backoff": "blocks the goroutine in time.Sleep for 2s",
+		"Poll":    "blocks the goroutine in time.Sleep for 250ms",
+		"Delay":   "blocks the goroutine in time.Sleep(d)",
+		"Stamp":   "",
+		"WaitOn":  "",
+	} {
+		fd := analysis.FindFuncDecl(pkg, name)
+		if fd == nil {
+			t.Fatalf("%s not found in p3effects package", name)
+		}
+		effects := analysis.AnalyzeP3Effects(pkg.Fset, pkg.TypesInfo, fd, pkg.PkgPath, name)
+		if want == "" {
+			if len(effects) != 0 {
+				t.Errorf("%s: expected no effects, got %v", name, effects)
+			}
+			continue
+		}
+		if len(effects) != 1 {
+			t.Fatalf("%s: expected 1 effect, got %d: %v", name, len(effects), effects)
+		}
+		e := effects[0]
+		if e.Type != taxonomy.TimeDependency || e.Tier != taxonomy.TierP3 || e.Target != "time.Sleep" {
+			t.Errorf("%s: got %s/%s target %q, want TimeDependency/P3 target time.Sleep", name, e.Type, e.Tier, e.Target)
+		}
+		if e.Description != want {
+			t.Errorf("%s: description: got %q, want %q", name, e.Description, want)
+		}
+	}
+}
+
+// TestAnalyzeP3Effects_Direct_RecoverBehavior verifies that a
+// deferred recover is reported with what it does with the panic:
+// swallows it, converts it to a named result, or re-panics always
+// or on some paths.
+func TestAnalyzeP3Effects_Direct_RecoverBehavior(t *testing.T) {
+	pkg := loadTestPackage(t, "p3effects")
+
+	for name, want := range map[string]string{
+		"Swallow":       "deferred recover() always swallows the panic; the function returns normally",
+		"ToError":       "deferred recover() converts a panic to named result 'err'; the function returns instead of panicking",
+		"Filter":        "deferred recover() conditionally re-panics; the function may still panic, and converts other panics to named result 'err'",
+		"Rethrow":       "deferred recover() always re-panics; the function still panics after the handler runs",
+		"SwitchOnPanic": "deferred recover() conditionally re-panics; the function may still panic",
+		"NestedRecover": "",
+	} {
+		fd := analysis.FindFuncDecl(pkg, name)
+		if fd == nil {
+			t.Fatalf("%s not found in p3effects package", name)
+		}
+		var got []taxonomy.SideEffect
+		for _, e := range analysis.AnalyzeP3Effects(pkg.Fset, pkg.TypesInfo, fd, pkg.PkgPath, name) {
+			if e.Type == taxonomy.RecoverBehavior {
+				got = append(got, e)
+			}
+		}
+		if want == "" {
+			if len(got) != 0 {
+				t.Errorf("%s: expected no RecoverBehavior, got %v", name, got)
+			}
+			continue
+		}
+		if len(got) != 1 {
+			t.Fatalf("%s: expected 1 RecoverBehavior effect, got %d: %v", name, len(got), got)
+		}
+		if got[0].Tier != taxonomy.TierP3 || got[0].Target != "recover" {
+			t.Errorf("%s: got tier %s target %q, want P3 target recover", name, got[0].Tier, got[0].Target)
+		}
+		if got[0].Description != want {
+			t.Errorf("%s: description:\n got %q\nwant %q", name, got[0].Description, want)
+		}
+	}
+}
+
+// TestAnalyzeP3Effects_Direct_NilBody verifies that AnalyzeP3Effects
+// handles a FuncDecl with nil Body gracefully.
+func TestAnalyzeP3Effects_Direct_NilBody(t *testing.T) {
+	fd := &ast.FuncDecl{
+		Name: ast.NewIdent("NilBodyFunc"),
+		Type: &ast.FuncType{},
+		Body: nil,
+	}
+
+	effects := analysis.AnalyzeP3Effects(token.NewFileSet(), nil, fd, "test/pkg", "NilBodyFunc")
+
+	if len(effects) != 0 {
+		t.Errorf("nil body: expected empty slice, got %d effects", len(effects))
+	}
+}
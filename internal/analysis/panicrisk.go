@@ -0,0 +1,250 @@
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// knownVar is a local variable whose value is fixed at its
+// declaration because nothing in the function can change it.
+type knownVar struct {
+	decl token.Pos
+
+	// nilMap is set for a map declared without an initializer.
+	nilMap bool
+
+	// length is the length of a slice declared with a constant
+	// length, or -1 when the variable is not such a slice.
+	length int64
+}
+
+// detectRuntimePanics reports operations in fd that are certain to
+// panic at run time when executed: writes to a local map that is
+// declared without an initializer and never assigned, and constant
+// indexes past the end of a local slice whose length is a constant
+// and that is never assigned. Constant out-of-range indexes into
+// arrays are compile errors, so slices are the remaining case.
+//
+// The detector is deliberately conservative. A variable whose
+// address is taken, that is assigned after its declaration (including
+// in a closure or a range clause), or whose methods or fields are
+// selected is not considered known, and any non-constant index is
+// ignored.
+func detectRuntimePanics(
+	fset *token.FileSet,
+	info *types.Info,
+	fd *ast.FuncDecl,
+	pkg string,
+	funcName string,
+	seen map[string]bool,
+) []taxonomy.SideEffect {
+	if info == nil || fd.Body == nil {
+		return nil
+	}
+	known := knownVars(info, fd.Body)
+	if len(known) == 0 {
+		return nil
+	}
+
+	var effects []taxonomy.SideEffect
+	report := func(node ast.Node, name, cause string) {
+		key := fmt.Sprintf("panic:%s:%d", name, fset.Position(node.Pos()).Line)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		effects = append(effects, taxonomy.SideEffect{
+			ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.Panic), key),
+			Type:        taxonomy.Panic,
+			Tier:        taxonomy.TierP2,
+			Location:    fset.Position(node.Pos()).String(),
+			EndLocation: fset.Position(node.End()).String(),
+			Description: cause,
+			Target:      name,
+		})
+	}
+	nilMapWrite := func(lhs ast.Expr) {
+		ix, ok := ast.Unparen(lhs).(*ast.IndexExpr)
+		if !ok {
+			return
+		}
+		id, ok := ast.Unparen(ix.X).(*ast.Ident)
+		if !ok {
+			return
+		}
+		if v, ok := known[info.Uses[id]]; ok && v.nilMap {
+			report(ix, id.Name, fmt.Sprintf(
+				"writes to nil map '%s' (declared at line %d without make), which panics",
+				id.Name, fset.Position(v.decl).Line))
+		}
+	}
+
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range node.Lhs {
+				nilMapWrite(lhs)
+			}
+		case *ast.IncDecStmt:
+			nilMapWrite(node.X)
+		case *ast.IndexExpr:
+			id, ok := ast.Unparen(node.X).(*ast.Ident)
+			if !ok {
+				return true
+			}
+			v, ok := known[info.Uses[id]]
+			if !ok || v.length < 0 {
+				return true
+			}
+			if idx, ok := constInt(info, node.Index); ok && idx >= v.length {
+				report(node, id.Name, fmt.Sprintf(
+					"index %d is out of range for '%s' (length %d), which panics",
+					idx, id.Name, v.length))
+			}
+		}
+		return true
+	})
+	return effects
+}
+
+// knownVars returns the local variables declared in body whose
+// initial value is a nil map or a slice of constant length, and that
+// nothing in body can change afterwards.
+func knownVars(info *types.Info, body *ast.BlockStmt) map[types.Object]knownVar {
+	known := make(map[types.Object]knownVar)
+	declare := func(id *ast.Ident, init ast.Expr) {
+		obj, ok := info.Defs[id].(*types.Var)
+		if !ok {
+			return
+		}
+		v := knownVar{decl: id.Pos(), length: -1}
+		switch obj.Type().Underlying().(type) {
+		case *types.Map:
+			v.nilMap = init == nil
+		case *types.Slice:
+			if init == nil {
+				v.length = 0
+			} else if n, ok := sliceLen(info, init); ok {
+				v.length = n
+			}
+		}
+		if v.nilMap || v.length >= 0 {
+			known[obj] = v
+		}
+	}
+
+	// Pass 1: candidate declarations.
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.ValueSpec:
+			for i, id := range node.Names {
+				switch {
+				case len(node.Values) == 0:
+					declare(id, nil)
+				case len(node.Values) == len(node.Names):
+					declare(id, node.Values[i])
+				}
+			}
+		case *ast.AssignStmt:
+			if node.Tok == token.DEFINE && len(node.Lhs) == len(node.Rhs) {
+				for i, lhs := range node.Lhs {
+					if id, ok := lhs.(*ast.Ident); ok {
+						declare(id, node.Rhs[i])
+					}
+				}
+			}
+		}
+		return true
+	})
+	if len(known) == 0 {
+		return nil
+	}
+
+	// Pass 2: drop every candidate that may change or escape.
+	forget := func(e ast.Expr) {
+		if id, ok := ast.Unparen(e).(*ast.Ident); ok {
+			if obj := info.Uses[id]; obj != nil {
+				delete(known, obj)
+			}
+		}
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range node.Lhs {
+				forget(lhs)
+			}
+		case *ast.RangeStmt:
+			if node.Tok == token.ASSIGN {
+				if node.Key != nil {
+					forget(node.Key)
+				}
+				if node.Value != nil {
+					forget(node.Value)
+				}
+			}
+		case *ast.UnaryExpr:
+			if node.Op == token.AND {
+				forget(node.X)
+			}
+		case *ast.SelectorExpr:
+			// A pointer-receiver method on a named map or slice
+			// type can replace the variable through its implicit
+			// address.
+			forget(node.X)
+		}
+		return true
+	})
+	return known
+}
+
+// sliceLen returns the length of the slice init evaluates to when it
+// is a constant: make([]T, n) with a constant n, or a slice literal.
+func sliceLen(info *types.Info, init ast.Expr) (int64, bool) {
+	switch e := ast.Unparen(init).(type) {
+	case *ast.CallExpr:
+		id, ok := ast.Unparen(e.Fun).(*ast.Ident)
+		if !ok || len(e.Args) < 2 {
+			return 0, false
+		}
+		if _, ok := info.Uses[id].(*types.Builtin); !ok || id.Name != "make" {
+			return 0, false
+		}
+		return constInt(info, e.Args[1])
+	case *ast.CompositeLit:
+		if _, ok := info.TypeOf(e).Underlying().(*types.Slice); !ok {
+			return 0, false
+		}
+		// The length is one past the highest index, which keyed
+		// elements can move.
+		var next, length int64
+		for _, elt := range e.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				k, ok := constInt(info, kv.Key)
+				if !ok {
+					return 0, false
+				}
+				next = k
+			}
+			next++
+			length = max(length, next)
+		}
+		return length, true
+	}
+	return 0, false
+}
+
+// constInt returns the value of e when it is an integer constant
+// that fits in an int64.
+func constInt(info *types.Info, e ast.Expr) (int64, bool) {
+	tv, ok := info.Types[e]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.Int {
+		return 0, false
+	}
+	return constant.Int64Val(tv.Value)
+}
@@ -0,0 +1,346 @@
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// detectLocalPointerEscapes reports return statements in fd that hand
+// the caller a pointer to a value allocated in the function: &T{...},
+// new(T), the address of a local variable (or of a field or array
+// element of one), or a local pointer variable assigned one of those.
+// The caller then holds a mutable reference to what was the
+// function's own state, which is part of its contract.
+//
+// Pointers the function did not allocate, such as a pointer
+// parameter passed through or the address of a receiver field, are
+// not reported, and neither are results of interface type, which
+// the caller cannot mutate without a type assertion. Returns inside
+// func literals belong to the literal and are skipped.
+func detectLocalPointerEscapes(
+	fset *token.FileSet,
+	info *types.Info,
+	fd *ast.FuncDecl,
+	pkg string,
+	funcName string,
+	seen map[string]bool,
+) []taxonomy.SideEffect {
+	if fd.Body == nil || info == nil {
+		return nil
+	}
+	sig, ok := info.Defs[fd.Name].Type().(*types.Signature)
+	if !ok {
+		return nil
+	}
+
+	// Pass 1: pointer variables assigned a local allocation.
+	allocated := make(map[types.Object]bool)
+	record := func(lhs, rhs ast.Expr) {
+		id, ok := ast.Unparen(lhs).(*ast.Ident)
+		if !ok || !isLocalAllocation(rhs, info, fd) {
+			return
+		}
+		if obj := info.ObjectOf(id); isLocalVar(obj, fd) {
+			allocated[obj] = true
+		}
+	}
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			if len(node.Lhs) == len(node.Rhs) {
+				for i := range node.Lhs {
+					record(node.Lhs[i], node.Rhs[i])
+				}
+			}
+		case *ast.ValueSpec:
+			if len(node.Names) == len(node.Values) {
+				for i := range node.Names {
+					record(node.Names[i], node.Values[i])
+				}
+			}
+		}
+		return true
+	})
+
+	// Pass 2: returns of those pointers or of allocations directly.
+	var effects []taxonomy.SideEffect
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(node.Results) != sig.Results().Len() {
+				return true
+			}
+			for i, r := range node.Results {
+				if _, isPtr := sig.Results().At(i).Type().Underlying().(*types.Pointer); !isPtr {
+					continue
+				}
+				if !isLocalAllocation(r, info, fd) {
+					id, ok := ast.Unparen(r).(*ast.Ident)
+					if !ok || !allocated[info.Uses[id]] {
+						continue
+					}
+				}
+				if e, ok := localPointerEffect(fset, info, r, pkg, funcName, seen); ok {
+					effects = append(effects, e)
+				}
+			}
+		}
+		return true
+	})
+	return effects
+}
+
+// localPointerEffect builds the LocalPointerEscape effect for the
+// returned expression r, or reports false if it was already seen.
+func localPointerEffect(
+	fset *token.FileSet,
+	info *types.Info,
+	r ast.Expr,
+	pkg string,
+	funcName string,
+	seen map[string]bool,
+) (taxonomy.SideEffect, bool) {
+	target := types.ExprString(r)
+	key := fmt.Sprintf("localpointer:%s:%d", target, fset.Position(r.Pos()).Line)
+	if seen[key] {
+		return taxonomy.SideEffect{}, false
+	}
+	seen[key] = true
+
+	ptrType := info.TypeOf(r)
+	pointee := "value"
+	if ptr, ok := ptrType.Underlying().(*types.Pointer); ok {
+		pointee = targetTypeString(ptr.Elem(), pkg)
+	}
+	return taxonomy.SideEffect{
+		ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.LocalPointerEscape), key),
+		Type:        taxonomy.LocalPointerEscape,
+		Tier:        taxonomy.TierP2,
+		Location:    fset.Position(r.Pos()).String(),
+		EndLocation: fset.Position(r.End()).String(),
+		Description: fmt.Sprintf("returns %s, a pointer to a %s allocated in the function; the caller receives a mutable reference", target, pointee),
+		Target:      target,
+		TargetType:  targetTypeString(ptrType, pkg),
+	}, true
+}
+
+// isLocalAllocation reports whether e yields a pointer to memory
+// allocated in fd: &T{...}, new(T), or the address of a local
+// variable or of a field or array element reached from one without
+// dereferencing a pointer.
+func isLocalAllocation(e ast.Expr, info *types.Info, fd *ast.FuncDecl) bool {
+	switch x := ast.Unparen(e).(type) {
+	case *ast.UnaryExpr:
+		if x.Op != token.AND {
+			return false
+		}
+		return isLocallyOwned(x.X, info, fd)
+	case *ast.CallExpr:
+		id, ok := ast.Unparen(x.Fun).(*ast.Ident)
+		if !ok {
+			return false
+		}
+		b, ok := info.Uses[id].(*types.Builtin)
+		return ok && b.Name() == "new"
+	}
+	return false
+}
+
+// isLocallyOwned reports whether the addressable expression e is
+// storage owned by fd: a composite literal, a local variable, or a
+// field or array element of one.
+func isLocallyOwned(e ast.Expr, info *types.Info, fd *ast.FuncDecl) bool {
+	switch x := ast.Unparen(e).(type) {
+	case *ast.CompositeLit:
+		return true
+	case *ast.Ident:
+		return isLocalVar(info.Uses[x], fd)
+	case *ast.SelectorExpr:
+		if _, isPtr := info.TypeOf(x.X).Underlying().(*types.Pointer); isPtr {
+			return false
+		}
+		return isLocallyOwned(x.X, info, fd)
+	case *ast.IndexExpr:
+		if _, isArray := info.TypeOf(x.X).Underlying().(*types.Array); !isArray {
+			return false
+		}
+		return isLocallyOwned(x.X, info, fd)
+	}
+	return false
+}
+
+// isLocalVar reports whether obj is a variable declared in the body
+// of fd, as opposed to a parameter, result, or package variable.
+func isLocalVar(obj types.Object, fd *ast.FuncDecl) bool {
+	v, ok := obj.(*types.Var)
+	return ok && !v.IsField() && v.Pos() >= fd.Body.Pos() && v.Pos() < fd.Body.End()
+}
+
+// detectInternalPointerEscapes reports return statements in method fd
+// that hand the caller a pointer into the receiver's unexported
+// state: a pointer-typed field (return s.cur) or the address of a
+// field reached through a pointer receiver (return &s.entry). The
+// caller can then mutate the receiver outside its methods, as in
+// s.Current().Name = "x", which bypasses any invariant the type
+// maintains. The selector chain must pass through at least one
+// unexported field; exported fields are already open to callers.
+//
+// The address of a field of a value receiver points into the
+// method's copy and is not reported, and neither are results of
+// interface type. Returns inside func literals are skipped.
+func detectInternalPointerEscapes(
+	fset *token.FileSet,
+	info *types.Info,
+	fd *ast.FuncDecl,
+	pkg string,
+	funcName string,
+	seen map[string]bool,
+) []taxonomy.SideEffect {
+	if fd.Body == nil || fd.Recv == nil || len(fd.Recv.List) == 0 ||
+		len(fd.Recv.List[0].Names) == 0 || info == nil {
+		return nil
+	}
+	recv := info.Defs[fd.Recv.List[0].Names[0]]
+	if recv == nil {
+		return nil
+	}
+	sig, ok := info.Defs[fd.Name].Type().(*types.Signature)
+	if !ok {
+		return nil
+	}
+
+	var effects []taxonomy.SideEffect
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(node.Results) != sig.Results().Len() {
+				return true
+			}
+			for i, r := range node.Results {
+				if !isPointerType(sig.Results().At(i).Type()) {
+					continue
+				}
+				field, ok := internalPointerField(r, recv, info)
+				if !ok {
+					continue
+				}
+				if e, ok := internalPointerEffect(fset, info, r, recv, field, pkg, funcName, seen); ok {
+					effects = append(effects, e)
+				}
+			}
+		}
+		return true
+	})
+	return effects
+}
+
+// internalPointerField reports whether r is a pointer into recv's
+// unexported state, as detectInternalPointerEscapes defines it, and
+// returns the name of the unexported field nearest the receiver.
+func internalPointerField(r ast.Expr, recv types.Object, info *types.Info) (string, bool) {
+	e := ast.Unparen(r)
+	addr := false
+	if u, ok := e.(*ast.UnaryExpr); ok && u.Op == token.AND {
+		addr, e = true, ast.Unparen(u.X)
+	}
+	switch e.(type) {
+	case *ast.SelectorExpr:
+		if !addr && !isPointerType(info.TypeOf(e)) {
+			return "", false
+		}
+	case *ast.IndexExpr:
+		if !addr {
+			return "", false
+		}
+	default:
+		return "", false
+	}
+
+	// Walk the selector chain back to the receiver, noting the
+	// unexported field nearest it and whether a pointer was
+	// dereferenced on the way.
+	var unexported string
+	throughPointer := false
+	for {
+		switch x := e.(type) {
+		case *ast.SelectorExpr:
+			sel, ok := info.Selections[x]
+			if !ok || sel.Kind() != types.FieldVal {
+				return "", false
+			}
+			if !x.Sel.IsExported() {
+				unexported = x.Sel.Name
+			}
+			if sel.Indirect() || isPointerType(info.TypeOf(x.X)) {
+				throughPointer = true
+			}
+			e = ast.Unparen(x.X)
+		case *ast.IndexExpr:
+			if _, isArray := info.TypeOf(x.X).Underlying().(*types.Array); !isArray {
+				// A slice element lives in a shared backing array.
+				throughPointer = true
+			}
+			e = ast.Unparen(x.X)
+		case *ast.Ident:
+			if info.Uses[x] != recv || unexported == "" || (addr && !throughPointer) {
+				return "", false
+			}
+			return unexported, true
+		default:
+			return "", false
+		}
+	}
+}
+
+// internalPointerEffect builds the InternalPointerEscape effect for
+// the returned expression r, or reports false if it was already seen.
+func internalPointerEffect(
+	fset *token.FileSet,
+	info *types.Info,
+	r ast.Expr,
+	recv types.Object,
+	field string,
+	pkg string,
+	funcName string,
+	seen map[string]bool,
+) (taxonomy.SideEffect, bool) {
+	target := types.ExprString(r)
+	key := fmt.Sprintf("internalpointer:%s:%d", target, fset.Position(r.Pos()).Line)
+	if seen[key] {
+		return taxonomy.SideEffect{}, false
+	}
+	seen[key] = true
+
+	ptrType := info.TypeOf(r)
+	owner := targetTypeString(recv.Type(), pkg)
+	if ptr, ok := recv.Type().(*types.Pointer); ok {
+		owner = targetTypeString(ptr.Elem(), pkg)
+	}
+	return taxonomy.SideEffect{
+		ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.InternalPointerEscape), key),
+		Type:        taxonomy.InternalPointerEscape,
+		Tier:        taxonomy.TierP2,
+		Location:    fset.Position(r.Pos()).String(),
+		EndLocation: fset.Position(r.End()).String(),
+		Description: fmt.Sprintf("returns %s, a pointer into %s's unexported field '%s'; callers can mutate its internal state outside its methods", target, owner, field),
+		Target:      target,
+		TargetType:  targetTypeString(ptrType, pkg),
+	}, true
+}
+
+// isPointerType reports whether t is a pointer type.
+func isPointerType(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	_, ok := t.Underlying().(*types.Pointer)
+	return ok
+}
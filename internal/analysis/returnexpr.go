@@ -0,0 +1,243 @@
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// maxReturnSources caps how many distinct return expressions are
+// listed for one return position before the rest are summarized.
+const maxReturnSources = 3
+
+// returnSources describes, for each result position of fd, what the
+// function's return statements return there: a parameter, a
+// receiver field, a literal, a call, and so on. Descriptions are
+// deduplicated and kept in source order. Return statements inside
+// function literals are ignored, as they belong to the literal.
+func returnSources(info *types.Info, fd *ast.FuncDecl, count int) [][]string {
+	if fd.Body == nil || count == 0 {
+		return nil
+	}
+	d := newReturnDescriber(info, fd)
+
+	sources := make([][]string, count)
+	seen := make([]map[string]bool, count)
+	for i := range seen {
+		seen[i] = make(map[string]bool)
+	}
+	add := func(pos int, desc string) {
+		if !seen[pos][desc] {
+			seen[pos][desc] = true
+			sources[pos] = append(sources[pos], desc)
+		}
+	}
+
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			switch {
+			case len(n.Results) == count:
+				for i, expr := range n.Results {
+					add(i, d.describe(expr))
+				}
+			case len(n.Results) == 0:
+				// Bare return of named results.
+				for i, name := range d.resultNames {
+					if i < count {
+						add(i, fmt.Sprintf("named return '%s'", name))
+					}
+				}
+			case len(n.Results) == 1:
+				// return f() forwarding a multi-value call.
+				desc := "result of " + d.describe(n.Results[0])
+				for i := 0; i < count; i++ {
+					add(i, desc)
+				}
+			}
+		}
+		return true
+	})
+	return sources
+}
+
+// formatReturnSources joins the sources of one return position into
+// a phrase such as "receiver field 'count' or literal 0".
+func formatReturnSources(sources []string) string {
+	if len(sources) <= maxReturnSources {
+		return strings.Join(sources, " or ")
+	}
+	return fmt.Sprintf("%s or %d more",
+		strings.Join(sources[:maxReturnSources], " or "),
+		len(sources)-maxReturnSources)
+}
+
+// returnDescriber classifies return expressions relative to the
+// receiver, parameters, and named results of one function.
+type returnDescriber struct {
+	info        *types.Info
+	recv        types.Object
+	params      map[types.Object]bool
+	results     map[types.Object]bool
+	resultNames []string
+}
+
+func newReturnDescriber(info *types.Info, fd *ast.FuncDecl) *returnDescriber {
+	d := &returnDescriber{
+		info:    info,
+		params:  make(map[types.Object]bool),
+		results: make(map[types.Object]bool),
+	}
+	if fd.Recv != nil && len(fd.Recv.List) > 0 && len(fd.Recv.List[0].Names) > 0 {
+		d.recv = d.def(fd.Recv.List[0].Names[0])
+	}
+	for _, field := range fd.Type.Params.List {
+		for _, n := range field.Names {
+			if obj := d.def(n); obj != nil {
+				d.params[obj] = true
+			}
+		}
+	}
+	if fd.Type.Results != nil {
+		for _, field := range fd.Type.Results.List {
+			for _, n := range field.Names {
+				d.resultNames = append(d.resultNames, n.Name)
+				if obj := d.def(n); obj != nil {
+					d.results[obj] = true
+				}
+			}
+		}
+	}
+	return d
+}
+
+// def returns the object ident declares, or nil without type info.
+func (d *returnDescriber) def(ident *ast.Ident) types.Object {
+	if d.info == nil {
+		return nil
+	}
+	return d.info.Defs[ident]
+}
+
+// use returns the object ident refers to, or nil without type info.
+func (d *returnDescriber) use(ident *ast.Ident) types.Object {
+	if d.info == nil {
+		return nil
+	}
+	return d.info.Uses[ident]
+}
+
+// describe returns a short description of a return expression.
+func (d *returnDescriber) describe(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return d.describe(e.X)
+	case *ast.BasicLit:
+		if len(e.Value) > 20 {
+			return "literal " + e.Value[:17] + "..."
+		}
+		return "literal " + e.Value
+	case *ast.Ident:
+		return d.describeIdent(e)
+	case *ast.SelectorExpr:
+		return d.describeSelector(e)
+	case *ast.CompositeLit:
+		if e.Type == nil {
+			return "composite literal"
+		}
+		return "composite literal " + types.ExprString(e.Type)
+	case *ast.UnaryExpr:
+		if lit, ok := e.X.(*ast.CompositeLit); ok && e.Op == token.AND && lit.Type != nil {
+			return "new " + types.ExprString(lit.Type)
+		}
+	case *ast.FuncLit:
+		return "function literal"
+	case *ast.CallExpr:
+		return d.describeCall(e)
+	}
+	return "computed expression"
+}
+
+// describeIdent classifies a bare identifier.
+func (d *returnDescriber) describeIdent(ident *ast.Ident) string {
+	obj := d.use(ident)
+	switch o := obj.(type) {
+	case nil:
+		if ident.Name == "nil" || ident.Name == "true" || ident.Name == "false" {
+			return "literal " + ident.Name
+		}
+		return fmt.Sprintf("'%s'", ident.Name)
+	case *types.Nil:
+		return "literal nil"
+	case *types.Const:
+		if o.Parent() == types.Universe {
+			return "literal " + ident.Name
+		}
+		return fmt.Sprintf("constant '%s'", ident.Name)
+	case *types.Func:
+		return fmt.Sprintf("function '%s'", ident.Name)
+	case *types.Var:
+		switch {
+		case obj == d.recv:
+			return "receiver"
+		case d.params[obj]:
+			return fmt.Sprintf("parameter '%s'", ident.Name)
+		case d.results[obj]:
+			return fmt.Sprintf("named return '%s'", ident.Name)
+		case o.Pkg() != nil && o.Parent() == o.Pkg().Scope():
+			return fmt.Sprintf("package variable '%s'", ident.Name)
+		}
+		return fmt.Sprintf("local variable '%s'", ident.Name)
+	}
+	return fmt.Sprintf("'%s'", ident.Name)
+}
+
+// describeSelector classifies a field access or qualified
+// identifier, naming the receiver or parameter it is read from.
+func (d *returnDescriber) describeSelector(sel *ast.SelectorExpr) string {
+	root := exprRootIdent(sel)
+	if root == nil {
+		return "computed expression"
+	}
+	obj := d.use(root)
+	if _, ok := obj.(*types.PkgName); ok && root == sel.X {
+		switch d.use(sel.Sel).(type) {
+		case *types.Const:
+			return fmt.Sprintf("constant '%s'", types.ExprString(sel))
+		case *types.Var:
+			return fmt.Sprintf("package variable '%s'", types.ExprString(sel))
+		case *types.Func:
+			return fmt.Sprintf("function '%s'", types.ExprString(sel))
+		}
+		return fmt.Sprintf("'%s'", types.ExprString(sel))
+	}
+
+	path := strings.TrimPrefix(types.ExprString(sel), root.Name+".")
+	switch {
+	case obj != nil && obj == d.recv:
+		return fmt.Sprintf("receiver field '%s'", path)
+	case d.params[obj]:
+		return fmt.Sprintf("field '%s' of parameter '%s'", path, root.Name)
+	}
+	return fmt.Sprintf("field '%s'", types.ExprString(sel))
+}
+
+// describeCall classifies a call, unwrapping type conversions.
+func (d *returnDescriber) describeCall(call *ast.CallExpr) string {
+	if d.info != nil && len(call.Args) == 1 {
+		if tv, ok := d.info.Types[call.Fun]; ok && tv.IsType() {
+			return d.describe(call.Args[0])
+		}
+	}
+	switch fun := call.Fun.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+		return "call to " + types.ExprString(fun)
+	case *ast.FuncLit:
+		return "call to function literal"
+	}
+	return "call"
+}
@@ -14,7 +14,9 @@ import (
 // AnalyzeReturns detects return value and error return side effects
 // for a function declaration. It inspects the function signature's
 // result list to find:
-//   - ReturnValue for each non-error return position
+//   - ReturnValue for each non-error return position, described
+//     with the expressions returned there (parameter, receiver
+//     field, literal, call, ...)
 //   - ErrorReturn for each error-typed return position
 //   - DeferredReturnMutation for named returns modified in defer
 func AnalyzeReturns(
@@ -30,8 +32,9 @@ func AnalyzeReturns(
 
 	var effects []taxonomy.SideEffect
 
-	// Collect named return variable names for defer analysis.
-	var namedReturns []string
+	// Describe what each result position is returned from, so that
+	// test authors can see which inputs drive each output.
+	sources := returnSources(info, fd, fd.Type.Results.NumFields())
 
 	pos := 0
 	for _, field := range fd.Type.Results.List {
@@ -48,11 +51,9 @@ func AnalyzeReturns(
 
 		for i := 0; i < count; i++ {
 			name := names[i]
-			if name != "" {
-				namedReturns = append(namedReturns, name)
-			}
 
 			loc := fset.Position(field.Pos()).String()
+			end := fset.Position(field.End()).String()
 			desc := formatReturnDesc(typeStr, pos, name)
 
 			if isError {
@@ -61,15 +62,20 @@ func AnalyzeReturns(
 					Type:        taxonomy.ErrorReturn,
 					Tier:        taxonomy.TierP0,
 					Location:    loc,
+					EndLocation: end,
 					Description: desc,
 					Target:      typeStr,
 				})
 			} else {
+				if pos < len(sources) && len(sources[pos]) > 0 {
+					desc += " from " + formatReturnSources(sources[pos])
+				}
 				effects = append(effects, taxonomy.SideEffect{
 					ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.ReturnValue), loc),
 					Type:        taxonomy.ReturnValue,
 					Tier:        taxonomy.TierP0,
 					Location:    loc,
+					EndLocation: end,
 					Description: desc,
 					Target:      typeStr,
 				})
@@ -79,17 +85,20 @@ func AnalyzeReturns(
 	}
 
 	// Check for named returns modified in deferred functions.
-	if len(namedReturns) > 0 && fd.Body != nil {
-		deferred := findDeferredReturnMutations(fd.Body, namedReturns)
-		for _, name := range deferred {
-			loc := fset.Position(fd.Pos()).String()
+	if fd.Body != nil {
+		deferred := findDeferredReturnMutations(info, fd.Type.Results, fd.Body)
+		for _, d := range deferred {
+			loc := fset.Position(d.stmt.Pos()).String()
+			end := fset.Position(d.stmt.End()).String()
 			effects = append(effects, taxonomy.SideEffect{
-				ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.DeferredReturnMutation), name),
+				ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.DeferredReturnMutation), d.name),
 				Type:        taxonomy.DeferredReturnMutation,
 				Tier:        taxonomy.TierP1,
 				Location:    loc,
-				Description: fmt.Sprintf("named return '%s' modified in defer", name),
-				Target:      name,
+				EndLocation: end,
+				Description: fmt.Sprintf("named return '%s' modified in %s, after the body's apparent return",
+					d.name, deferString(d.stmt)),
+				Target: d.name,
 			})
 		}
 	}
@@ -132,36 +141,75 @@ func formatReturnDesc(typeStr string, pos int, name string) string {
 	return fmt.Sprintf("returns %s at position %d", typeStr, pos)
 }
 
+// deferredMutation is a named return modified by a defer statement.
+type deferredMutation struct {
+	name string
+	stmt *ast.DeferStmt
+}
+
 // findDeferredReturnMutations walks a function body looking for
-// defer statements that assign to any of the named return variables.
-// Returns the list of named returns that are modified.
-func findDeferredReturnMutations(body *ast.BlockStmt, namedReturns []string) []string {
-	nameSet := make(map[string]bool, len(namedReturns))
-	for _, n := range namedReturns {
-		nameSet[n] = true
+// defer statements that modify any of the named return variables,
+// whatever their type: plain and compound assignments, ++/--, and
+// writes to a field or element of the return value. Returns the
+// named returns that are modified, each with the first defer that
+// modifies it, in order of first modification.
+//
+// With type information, identifiers are matched by object so that
+// a local variable shadowing a named return inside the deferred
+// closure is not mistaken for it; without it, names are compared.
+func findDeferredReturnMutations(info *types.Info, results *ast.FieldList, body *ast.BlockStmt) []deferredMutation {
+	nameSet := make(map[string]bool)
+	objs := make(map[types.Object]bool)
+	for _, field := range results.List {
+		for _, n := range field.Names {
+			if n.Name == "_" {
+				continue
+			}
+			nameSet[n.Name] = true
+			if info != nil {
+				if obj := info.Defs[n]; obj != nil {
+					objs[obj] = true
+				}
+			}
+		}
+	}
+	if len(nameSet) == 0 {
+		return nil
+	}
+
+	// isNamedReturn reports whether ident refers to a named return.
+	isNamedReturn := func(ident *ast.Ident) bool {
+		if len(objs) > 0 {
+			return objs[info.Uses[ident]]
+		}
+		return nameSet[ident.Name]
 	}
 
-	var modified []string
+	var modified []deferredMutation
 	seen := make(map[string]bool)
+	record := func(ds *ast.DeferStmt, lhs ast.Expr) {
+		ident := exprRootIdent(lhs)
+		if ident == nil || seen[ident.Name] || !isNamedReturn(ident) {
+			return
+		}
+		modified = append(modified, deferredMutation{name: ident.Name, stmt: ds})
+		seen[ident.Name] = true
+	}
 
 	ast.Inspect(body, func(n ast.Node) bool {
 		ds, ok := n.(*ast.DeferStmt)
 		if !ok {
 			return true
 		}
-		// Walk the deferred call for assignments to named returns.
+		// Walk the deferred call for writes to named returns.
 		ast.Inspect(ds, func(inner ast.Node) bool {
-			assign, ok := inner.(*ast.AssignStmt)
-			if !ok {
-				return true
-			}
-			for _, lhs := range assign.Lhs {
-				if ident, ok := lhs.(*ast.Ident); ok {
-					if nameSet[ident.Name] && !seen[ident.Name] {
-						modified = append(modified, ident.Name)
-						seen[ident.Name] = true
-					}
+			switch stmt := inner.(type) {
+			case *ast.AssignStmt:
+				for _, lhs := range stmt.Lhs {
+					record(ds, lhs)
 				}
+			case *ast.IncDecStmt:
+				record(ds, stmt.X)
 			}
 			return true
 		})
@@ -171,6 +219,22 @@ func findDeferredReturnMutations(body *ast.BlockStmt, namedReturns []string) []s
 	return modified
 }
 
+// deferString renders a defer statement compactly for descriptions,
+// eliding the body of a deferred function literal: "defer func()
+// {...}()" or "defer recoverInto(&err)".
+func deferString(ds *ast.DeferStmt) string {
+	call := ds.Call
+	if lit, ok := call.Fun.(*ast.FuncLit); ok {
+		args := make([]string, len(call.Args))
+		for i, a := range call.Args {
+			args[i] = types.ExprString(a)
+		}
+		return fmt.Sprintf("defer %s {...}(%s)",
+			types.ExprString(lit.Type), strings.Join(args, ", "))
+	}
+	return "defer " + types.ExprString(call)
+}
+
 // receiverName extracts the receiver type name from a FuncDecl.
 // Returns empty string for non-method functions.
 func receiverName(fd *ast.FuncDecl) string {
@@ -196,6 +260,22 @@ func typeExprString(expr ast.Expr) string {
 	}
 }
 
+// targetTypeString formats t for SideEffect.TargetType. Types from
+// the package pkgPath are left unqualified and others are qualified
+// by package name, e.g. "map[string]Item" or "*sync.Mutex". It
+// returns "" for a nil type.
+func targetTypeString(t types.Type, pkgPath string) string {
+	if t == nil {
+		return ""
+	}
+	return types.TypeString(t, func(p *types.Package) string {
+		if p.Path() == pkgPath {
+			return ""
+		}
+		return p.Name()
+	})
+}
+
 // funcSignature returns a readable signature string for a FuncDecl.
 func funcSignature(_ *token.FileSet, fd *ast.FuncDecl) string {
 	var b strings.Builder
@@ -1,6 +1,7 @@
 package analysis_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/unbound-force/gaze/internal/analysis"
@@ -140,6 +141,108 @@ func TestAnalyzeReturns_Direct_NamedReturnModifiedInDefer(t *testing.T) {
 	}
 }
 
+// TestAnalyzeReturns_Direct_DeferredNonErrorReturns verifies that
+// DeferredReturnMutation is reported for named returns of any type
+// modified in a defer, and not for a shadowing local variable.
+func TestAnalyzeReturns_Direct_DeferredNonErrorReturns(t *testing.T) {
+	tests := []struct {
+		funcName string
+		target   string // expected mutated return; "" for none
+	}{
+		{"NonErrorNamedReturnInDefer", "result"},
+		{"NamedReturnIncrementedInDefer", "n"},
+		{"NamedReturnFieldSetInDefer", "cfg"},
+		{"ShadowedNamedReturnInDefer", ""},
+	}
+
+	pkg := loadTestPackage(t, "returns")
+	for _, tt := range tests {
+		t.Run(tt.funcName, func(t *testing.T) {
+			fd := analysis.FindFuncDecl(pkg, tt.funcName)
+			if fd == nil {
+				t.Fatalf("%s not found in returns package", tt.funcName)
+			}
+
+			effects := analysis.AnalyzeReturns(pkg.Fset, pkg.TypesInfo, fd, pkg.PkgPath, tt.funcName)
+
+			var deferred []taxonomy.SideEffect
+			for _, e := range effects {
+				if e.Type == taxonomy.DeferredReturnMutation {
+					deferred = append(deferred, e)
+				}
+			}
+			if tt.target == "" {
+				if len(deferred) != 0 {
+					t.Errorf("expected no DeferredReturnMutation, got %v", deferred)
+				}
+				return
+			}
+			if len(deferred) != 1 {
+				t.Fatalf("expected 1 DeferredReturnMutation, got %d", len(deferred))
+			}
+			e := deferred[0]
+			if e.Target != tt.target {
+				t.Errorf("Target = %q, want %q", e.Target, tt.target)
+			}
+			if !strings.Contains(e.Description, "'"+tt.target+"'") ||
+				!strings.Contains(e.Description, "apparent return") {
+				t.Errorf("unexpected description %q", e.Description)
+			}
+			if !strings.Contains(e.Description, "defer func() {...}()") {
+				t.Errorf("description %q does not name the deferred call", e.Description)
+			}
+			// The effect is located at the defer statement, not the
+			// function declaration.
+			if got, decl := e.Location, pkg.Fset.Position(fd.Pos()).String(); got == decl {
+				t.Errorf("Location = %s, want the defer statement", got)
+			}
+		})
+	}
+}
+
+// TestAnalyzeReturns_Direct_ReturnSources verifies that ReturnValue
+// descriptions list the expressions returned at each position across
+// all return statements, ignoring returns inside function literals.
+func TestAnalyzeReturns_Direct_ReturnSources(t *testing.T) {
+	tests := []struct {
+		funcName string
+		want     []string // ReturnValue descriptions in position order
+	}{
+		{"Count", []string{
+			"returns int at position 0 from literal 0 or receiver field 'count'",
+		}},
+		{"ReturnSources", []string{
+			"returns int at position 0 from parameter 'n' or constant 'Limit' or computed expression",
+			"returns string at position 1 from parameter 's' or call to strings.ToUpper or computed expression",
+		}},
+		{"NamedReturns", []string{
+			"returns []byte at position 0 (named: data) from literal nil",
+		}},
+	}
+
+	pkg := loadTestPackage(t, "returns")
+	for _, tt := range tests {
+		t.Run(tt.funcName, func(t *testing.T) {
+			fd := analysis.FindFuncDecl(pkg, tt.funcName)
+			if fd == nil {
+				t.Fatalf("%s not found in returns package", tt.funcName)
+			}
+
+			effects := analysis.AnalyzeReturns(pkg.Fset, pkg.TypesInfo, fd, pkg.PkgPath, tt.funcName)
+
+			var got []string
+			for _, e := range effects {
+				if e.Type == taxonomy.ReturnValue {
+					got = append(got, e.Description)
+				}
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("descriptions:\ngot  %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
 // TestAnalyzeReturns_Direct_PureFunction verifies that AnalyzeReturns
 // returns an empty slice for a function with no return values.
 func TestAnalyzeReturns_Direct_PureFunction(t *testing.T) {
@@ -2,19 +2,33 @@
 package analysis
 
 import (
+	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/token"
+	"go/types"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/unbound-force/gaze/internal/taxonomy"
 )
 
-// AnalyzeSentinels detects package-level sentinel error variables.
-// A sentinel error is a package-level var whose name starts with
-// "Err" (or "err" for unexported) and is initialized with
-// errors.New(...) or fmt.Errorf("...%w...").
+// AnalyzeSentinels detects package-level sentinel errors. A
+// sentinel error is a package-level var whose name starts with
+// "Err" (or "err" for unexported) and that is either initialized
+// with errors.New(...) or fmt.Errorf("...%w...") or, when type
+// information is available, has a type implementing error (e.g.
+// "var ErrTimeout error = newTimeout()" or
+// "var ErrClosed = &ClosedError{}"). Exported struct types
+// implementing error (e.g. NotFoundError) are reported as
+// sentinel-like too, since callers match them with errors.As.
+//
+// info may be nil, in which case only errors.New and fmt.Errorf
+// initializers are recognized.
 func AnalyzeSentinels(
 	fset *token.FileSet,
+	info *types.Info,
 	file *ast.File,
 	pkg string,
 ) []taxonomy.SideEffect {
@@ -22,65 +36,243 @@ func AnalyzeSentinels(
 
 	for _, decl := range file.Decls {
 		gd, ok := decl.(*ast.GenDecl)
-		if !ok || gd.Tok != token.VAR {
+		if !ok {
 			continue
 		}
 
-		for _, spec := range gd.Specs {
-			vs, ok := spec.(*ast.ValueSpec)
-			if !ok {
+		switch gd.Tok {
+		case token.VAR:
+			effects = append(effects, sentinelVars(fset, info, gd, pkg)...)
+		case token.TYPE:
+			effects = append(effects, errorTypes(fset, info, gd, pkg)...)
+		}
+	}
+
+	return effects
+}
+
+// sentinelVars returns the sentinel error variables declared by a
+// var declaration.
+func sentinelVars(
+	fset *token.FileSet,
+	info *types.Info,
+	gd *ast.GenDecl,
+	pkg string,
+) []taxonomy.SideEffect {
+	var effects []taxonomy.SideEffect
+
+	for _, spec := range gd.Specs {
+		vs, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+
+		for i, name := range vs.Names {
+			if !isSentinelName(name.Name) {
 				continue
 			}
 
-			for i, name := range vs.Names {
-				if !isSentinelName(name.Name) {
-					continue
-				}
-				if i >= len(vs.Values) {
-					continue
-				}
+			var call *ast.CallExpr
+			if i < len(vs.Values) {
+				call, _ = vs.Values[i].(*ast.CallExpr)
+			}
 
-				call, ok := vs.Values[i].(*ast.CallExpr)
-				if !ok {
-					continue
+			var suffix string
+			switch {
+			case call != nil && isErrorsNewCall(call):
+			case call != nil && isFmtErrorfCall(call):
+				if hasWrapVerb(call) {
+					suffix = taxonomy.SentinelWrapSuffix
 				}
+			case isErrorTypedVar(info, name):
+				suffix = " (error-typed var)"
+			default:
+				continue
+			}
 
-				wraps := false
-				isSentinel := false
+			loc := fset.Position(name.Pos()).String()
+			effects = append(effects, taxonomy.SideEffect{
+				ID:          taxonomy.GenerateID(pkg, "", string(taxonomy.SentinelError), loc),
+				Type:        taxonomy.SentinelError,
+				Tier:        taxonomy.TierP0,
+				Location:    loc,
+				EndLocation: fset.Position(vs.End()).String(),
+				Description: "package-level sentinel error '" + name.Name + "'" + suffix,
+				Target:      name.Name,
+			})
+		}
+	}
 
-				switch {
-				case isErrorsNewCall(call):
-					isSentinel = true
-				case isFmtErrorfCall(call):
-					isSentinel = true
-					wraps = hasWrapVerb(call)
-				}
+	return effects
+}
 
-				if !isSentinel {
+// duplicateSentinelWarnings finds sentinel error variables in files
+// initialized with the same constant message, as in
+// errors.New("closed") twice. Errors wrapping either one read
+// identically in logs and to callers matching on err.Error(), so
+// which sentinel caused a failure cannot be told from its text. The
+// result maps the file name of every colliding declaration to one
+// warning per collision naming all of the sentinels involved.
+func duplicateSentinelWarnings(
+	fset *token.FileSet,
+	info *types.Info,
+	files []*ast.File,
+) map[string][]string {
+	type decl struct{ name, file string }
+	byMessage := make(map[string][]decl)
+	var messages []string
+
+	for _, file := range files {
+		for _, d := range file.Decls {
+			gd, ok := d.(*ast.GenDecl)
+			if !ok || gd.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				vs, ok := spec.(*ast.ValueSpec)
+				if !ok {
 					continue
 				}
-
-				loc := fset.Position(name.Pos()).String()
-				desc := "package-level sentinel error '" + name.Name + "'"
-				if wraps {
-					desc += " (wraps via %w)"
+				for i, name := range vs.Names {
+					if !isSentinelName(name.Name) || i >= len(vs.Values) {
+						continue
+					}
+					call, ok := vs.Values[i].(*ast.CallExpr)
+					if !ok {
+						continue
+					}
+					msg, ok := sentinelMessage(info, call)
+					if !ok {
+						continue
+					}
+					if _, seen := byMessage[msg]; !seen {
+						messages = append(messages, msg)
+					}
+					byMessage[msg] = append(byMessage[msg], decl{
+						name: name.Name,
+						file: fset.Position(name.Pos()).Filename,
+					})
 				}
+			}
+		}
+	}
 
-				effects = append(effects, taxonomy.SideEffect{
-					ID:          taxonomy.GenerateID(pkg, "", string(taxonomy.SentinelError), loc),
-					Type:        taxonomy.SentinelError,
-					Tier:        taxonomy.TierP0,
-					Location:    loc,
-					Description: desc,
-					Target:      name.Name,
-				})
+	var warnings map[string][]string
+	for _, msg := range messages {
+		decls := byMessage[msg]
+		if len(decls) < 2 {
+			continue
+		}
+		names := make([]string, len(decls))
+		for i, d := range decls {
+			names[i] = "'" + d.name + "'"
+		}
+		warning := fmt.Sprintf("sentinel: %s share the message %q; "+
+			"errors wrapping them cannot be told apart by their text",
+			strings.Join(names, ", "), msg)
+		if warnings == nil {
+			warnings = make(map[string][]string)
+		}
+		for _, d := range decls {
+			if !slices.Contains(warnings[d.file], warning) {
+				warnings[d.file] = append(warnings[d.file], warning)
 			}
 		}
 	}
+	return warnings
+}
+
+// sentinelMessage returns the message of an errors.New or
+// fmt.Errorf call whose only argument is a constant string, or false
+// if the message is computed or formatted.
+func sentinelMessage(info *types.Info, call *ast.CallExpr) (string, bool) {
+	if len(call.Args) != 1 || (!isErrorsNewCall(call) && !isFmtErrorfCall(call)) {
+		return "", false
+	}
+	arg := call.Args[0]
+	if info != nil {
+		if tv, ok := info.Types[arg]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+			return constant.StringVal(tv.Value), true
+		}
+	}
+	lit, ok := arg.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	msg, err := strconv.Unquote(lit.Value)
+	return msg, err == nil
+}
+
+// errorTypes returns sentinel-like effects for the exported struct
+// types declared by a type declaration whose value or pointer type
+// implements error.
+func errorTypes(
+	fset *token.FileSet,
+	info *types.Info,
+	gd *ast.GenDecl,
+	pkg string,
+) []taxonomy.SideEffect {
+	if info == nil {
+		return nil
+	}
+
+	var effects []taxonomy.SideEffect
+
+	for _, spec := range gd.Specs {
+		ts, ok := spec.(*ast.TypeSpec)
+		if !ok || !ts.Name.IsExported() {
+			continue
+		}
+		if _, ok := ts.Type.(*ast.StructType); !ok {
+			continue
+		}
+		obj := info.Defs[ts.Name]
+		if obj == nil || !implementsError(obj.Type()) {
+			continue
+		}
+
+		loc := fset.Position(ts.Name.Pos()).String()
+		effects = append(effects, taxonomy.SideEffect{
+			ID:          taxonomy.GenerateID(pkg, "", string(taxonomy.SentinelError), loc),
+			Type:        taxonomy.SentinelError,
+			Tier:        taxonomy.TierP0,
+			Location:    loc,
+			EndLocation: fset.Position(ts.End()).String(),
+			Description: "exported error type '" + ts.Name.Name + "' (matchable with errors.As)",
+			Target:      ts.Name.Name,
+		})
+	}
 
 	return effects
 }
 
+// isErrorTypedVar reports whether the package-level variable
+// declared by name has a type implementing error.
+func isErrorTypedVar(info *types.Info, name *ast.Ident) bool {
+	if info == nil {
+		return false
+	}
+	obj := info.Defs[name]
+	if obj == nil {
+		return false
+	}
+	return implementsError(obj.Type())
+}
+
+// errorType is the universe "error" interface.
+var errorType = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+// implementsError reports whether t or *t implements error.
+func implementsError(t types.Type) bool {
+	if types.Implements(t, errorType) {
+		return true
+	}
+	if _, isPtr := t.(*types.Pointer); isPtr {
+		return false
+	}
+	return types.Implements(types.NewPointer(t), errorType)
+}
+
 // isSentinelName returns true if the name follows Go sentinel error
 // naming conventions: starts with "Err" (exported) or "err" (unexported).
 func isSentinelName(name string) bool {
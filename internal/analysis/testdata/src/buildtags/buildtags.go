@@ -0,0 +1,8 @@
+// Package buildtags provides test fixtures for loading packages
+// under build tags and a target platform.
+package buildtags
+
+// Always is built on every platform and with any tags.
+func Always() int {
+	return 1
+}
@@ -0,0 +1,8 @@
+//go:build plan9
+
+package buildtags
+
+// OnPlan9 is built only for GOOS=plan9.
+func OnPlan9() error {
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build gazetag
+
+package buildtags
+
+var calls int
+
+// Tagged is built only with the gazetag build tag.
+func Tagged() {
+	calls++
+}
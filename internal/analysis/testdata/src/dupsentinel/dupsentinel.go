@@ -0,0 +1,17 @@
+// Package dupsentinel is a test fixture for sentinel errors that
+// share a message.
+package dupsentinel
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrClosed shares its message with ErrShutdown in other.go.
+var ErrClosed = errors.New("closed")
+
+// ErrMissing and ErrGone share a message within this file.
+var (
+	ErrMissing = errors.New("missing")
+	ErrGone    = fmt.Errorf("missing")
+)
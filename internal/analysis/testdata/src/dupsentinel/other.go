@@ -0,0 +1,18 @@
+package dupsentinel
+
+import (
+	"errors"
+	"fmt"
+)
+
+const closedMsg = "closed"
+
+// ErrShutdown is declared with a constant equal to ErrClosed's
+// message.
+var ErrShutdown = errors.New(closedMsg)
+
+// ErrUnique has a message of its own — should NOT be reported.
+var ErrUnique = errors.New("unique")
+
+// ErrClosedWrapped formats its message — should NOT be reported.
+var ErrClosedWrapped = fmt.Errorf("closed: %w", ErrClosed)
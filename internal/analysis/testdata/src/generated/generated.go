@@ -0,0 +1,10 @@
+// Package generated contains test fixtures for skipping generated
+// and excluded files.
+package generated
+
+var count int
+
+// Handwritten mutates a global.
+func Handwritten() {
+	count++
+}
@@ -0,0 +1,6 @@
+package generated
+
+// Mocked mutates a global.
+func Mocked() {
+	count++
+}
@@ -0,0 +1,51 @@
+// Package ignore contains test fixtures for //gaze:ignore directives.
+package ignore
+
+import "fmt"
+
+var counter int
+
+// Unsuppressed mutates a global and returns an error.
+func Unsuppressed() error {
+	counter++
+	return fmt.Errorf("count %d", counter)
+}
+
+// All suppresses every effect.
+//
+//gaze:ignore
+func All() error {
+	counter++
+	return fmt.Errorf("count %d", counter)
+}
+
+// Global suppresses only the global mutation.
+//
+//gaze:ignore GlobalMutation
+func Global() error {
+	counter++
+	return fmt.Errorf("count %d", counter)
+}
+
+// CommaList suppresses two types listed with a comma.
+//
+//gaze:ignore GlobalMutation,ErrorReturn
+func CommaList() error {
+	counter++
+	return fmt.Errorf("count %d", counter)
+}
+
+// Unknown names a type that does not exist, so nothing is suppressed.
+//
+//gaze:ignore NoSuchEffect
+func Unknown() error {
+	counter++
+	return fmt.Errorf("count %d", counter)
+}
+
+// NotADirective only mentions gaze:ignore in prose.
+// //gaze:ignored is not the directive either.
+func NotADirective() error {
+	counter++
+	return fmt.Errorf("count %d", counter)
+}
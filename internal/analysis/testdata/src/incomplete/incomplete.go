@@ -0,0 +1,33 @@
+// Package incomplete contains test fixtures for constructs the
+// analyzers cannot fully reason about: assembly-backed functions,
+// reflection, and dot-imports.
+package incomplete
+
+import (
+	. "os"
+	"reflect"
+)
+
+// AsmAdd is implemented in assembly (incomplete_amd64.s); its
+// declaration has no Go body.
+func AsmAdd(a, b int) int
+
+// ReflectSet writes v into *dst through reflection.
+func ReflectSet(dst any, v int) {
+	reflect.ValueOf(dst).Elem().SetInt(int64(v))
+}
+
+// ReflectInspect only reads through reflection.
+func ReflectInspect(v any) string {
+	return reflect.TypeOf(v).String()
+}
+
+// DotExit exits through a dot-imported os.Exit.
+func DotExit() {
+	Exit(1)
+}
+
+// Plain has no constructs that limit the analysis.
+func Plain(a, b int) int {
+	return a + b
+}
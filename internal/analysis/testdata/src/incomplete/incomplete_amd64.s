@@ -0,0 +1,8 @@
+#include "textflag.h"
+
+// func AsmAdd(a, b int) int
+TEXT ·AsmAdd(SB), NOSPLIT, $0-24
+	MOVQ a+0(FP), AX
+	ADDQ b+8(FP), AX
+	MOVQ AX, ret+16(FP)
+	RET
@@ -0,0 +1,70 @@
+// Package interproc contains test fixtures for interprocedural
+// effect propagation.
+package interproc
+
+import "github.com/unbound-force/gaze/internal/analysis/testdata/src/interproc/store"
+
+// Do delegates all its work to Store.Save.
+func Do(s *store.Store) {
+	s.Save()
+}
+
+// Service wraps a store.
+type Service struct {
+	st *store.Store
+}
+
+// Flush saves the service's store.
+func (v *Service) Flush() {
+	v.st.Save()
+}
+
+// Clear resets the service's store through a function argument.
+func (v *Service) Clear() {
+	store.Reset(v.st)
+}
+
+// Outer reaches Store.Save two calls deep.
+func Outer(s *store.Store) {
+	Do(s)
+}
+
+// Scratch saves a store it created itself, so only the print is
+// visible to callers.
+func Scratch() {
+	var s store.Store
+	s.Save()
+}
+
+// Ping calls Pong, which calls Ping back.
+func Ping(n int) {
+	if n > 0 {
+		Pong(n - 1)
+	}
+}
+
+// Pong calls Ping.
+func Pong(n int) {
+	Ping(n)
+}
+
+// ticks counts calls of Tick.
+var ticks int
+
+// Tick increments ticks and calls Tock, which calls Tick back.
+func Tick(n int) {
+	ticks++
+	if n > 0 {
+		Tock(n - 1)
+	}
+}
+
+// Tock calls Tick.
+func Tock(n int) {
+	Tick(n)
+}
+
+// Tack reaches Tick's effect through Tock.
+func Tack(n int) {
+	Tock(n)
+}
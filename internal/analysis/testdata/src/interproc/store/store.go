@@ -0,0 +1,21 @@
+// Package store is a dependency of the interproc fixture, so that
+// effects are propagated across packages.
+package store
+
+import "fmt"
+
+// Store holds saved records.
+type Store struct {
+	count int
+}
+
+// Save mutates the store and prints a message.
+func (s *Store) Save() {
+	s.count++
+	fmt.Println("saved")
+}
+
+// Reset zeroes the count of the store passed in.
+func Reset(s *Store) {
+	s.count = 0
+}
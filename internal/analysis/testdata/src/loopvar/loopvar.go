@@ -0,0 +1,58 @@
+// Package loopvar is a fixture for goroutines that capture loop
+// variables in a module declaring go 1.21.
+package loopvar
+
+import "sync"
+
+// RangeCapture starts goroutines that read the shared range variable.
+func RangeCapture(xs []int) {
+	var wg sync.WaitGroup
+	for _, v := range xs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			use(v)
+		}()
+	}
+	wg.Wait()
+}
+
+// ForCapture starts goroutines that read the shared for variable.
+func ForCapture(n int) {
+	for i := 0; i < n; i++ {
+		go func() { use(i) }()
+	}
+}
+
+// NestedCapture captures the outer range key from an inner loop.
+func NestedCapture(xss [][]int) {
+	for k, xs := range xss {
+		for range xs {
+			go func() { use(k) }()
+		}
+	}
+}
+
+// PassedAsArgument gives each goroutine its own copy.
+func PassedAsArgument(xs []int) {
+	for _, v := range xs {
+		go func(v int) { use(v) }(v)
+	}
+}
+
+// Shadowed copies the variable before capturing it.
+func Shadowed(xs []int) {
+	for _, v := range xs {
+		v := v
+		go func() { use(v) }()
+	}
+}
+
+// NoGoroutine captures the variable in a closure run synchronously.
+func NoGoroutine(xs []int) {
+	for _, v := range xs {
+		func() { use(v) }()
+	}
+}
+
+func use(int) {}
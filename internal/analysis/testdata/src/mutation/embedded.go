@@ -0,0 +1,61 @@
+package mutation
+
+// Deep is embedded by pointer in Inner.
+type Deep struct {
+	Level int
+}
+
+// Inner is embedded by pointer in Outer.
+type Inner struct {
+	Field int
+	items []int
+	*Deep
+}
+
+// Outer demonstrates writes through promoted fields of an embedded
+// pointer, which land in the separately allocated Inner.
+type Outer struct {
+	*Inner
+	Name string
+}
+
+// Set writes the field promoted from *Inner.
+func (o *Outer) Set() {
+	o.Field = 1
+}
+
+// SetExplicit writes the same field through the embedded name.
+func (o *Outer) SetExplicit() {
+	o.Inner.Field = 2
+}
+
+// AddItem grows a slice field promoted from *Inner.
+func (o *Outer) AddItem(x int) {
+	o.items = append(o.items, x)
+}
+
+// SetDeep writes a field promoted through two embedded pointers.
+func (o *Outer) SetDeep() {
+	o.Level = 3
+}
+
+// SetInner replaces the embedded pointer itself.
+func (o *Outer) SetInner(in *Inner) {
+	o.Inner = in
+}
+
+// Base is embedded by value in Wrapper.
+type Base struct {
+	ID int
+}
+
+// Wrapper embeds Base by value, so its promoted fields are part of
+// Wrapper itself.
+type Wrapper struct {
+	Base
+}
+
+// SetID writes a field promoted from the value-embedded Base.
+func (w *Wrapper) SetID() {
+	w.ID = 1
+}
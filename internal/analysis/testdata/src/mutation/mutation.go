@@ -68,3 +68,61 @@ func (c *Config) UpdateConfig(timeout int) {
 func (c *Config) UpdateNested(v string) {
 	c.Nested.Value = v
 }
+
+// Store demonstrates mutation of slice and map fields.
+type Store struct {
+	items []int
+	cache map[string]int
+	inner struct {
+		log []string
+	}
+}
+
+// Add grows the receiver slice field 'items'.
+func (s *Store) Add(x int) {
+	s.items = append(s.items, x)
+}
+
+// Put writes into the receiver map field 'cache'.
+func (s *Store) Put(k string, v int) {
+	s.cache[k] = v
+}
+
+// Record grows a nested slice, reported as the top-level field 'inner'.
+func (s *Store) Record(msg string) {
+	s.inner.log = append(s.inner.log, msg)
+}
+
+// Replace assigns a new slice to 'items' rather than growing it.
+func (s *Store) Replace(xs []int) {
+	s.items = append(xs, 0)
+}
+
+// Pair demonstrates tuple assignment into fields.
+type Pair struct {
+	a, b int
+}
+
+func split() (int, int) { return 1, 2 }
+
+// SetFromCall assigns both fields from a tuple-returning call.
+func (p *Pair) SetFromCall() {
+	p.a, p.b = split()
+}
+
+// SetMixed assigns a local and a field in one statement.
+func (p *Pair) SetMixed() {
+	x := 0
+	x, p.b = 1, 2
+	_ = x
+}
+
+// SetWithBlank discards one result and assigns the other to a field.
+func (p *Pair) SetWithBlank() {
+	_, p.b = split()
+}
+
+// SwapInto writes a field of each pointer argument in one statement.
+func SwapInto(dst, src *Pair) {
+	dst.a, src.b = src.b, dst.a
+}
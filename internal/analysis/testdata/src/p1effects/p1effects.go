@@ -92,6 +92,19 @@ func ReadFromSlice(s []int) int {
 	return s[0]
 }
 
+// AppendToParam returns an append to its slice parameter, which
+// writes into the caller's backing array when s has spare capacity.
+func AppendToParam(s []int, x int) []int {
+	return append(s, x)
+}
+
+// AppendToLocal appends to a slice it allocated itself — should NOT
+// produce SliceMutation.
+func AppendToLocal(x int) []int {
+	s := make([]int, 0, 4)
+	return append(s, x)
+}
+
 // --- Pure function (no P1 effects) ---
 
 // PureP1 has no P1 side effects.
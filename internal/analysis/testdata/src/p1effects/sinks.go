@@ -0,0 +1,35 @@
+package p1effects
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// --- Output Sinks ---
+
+// RenderToBuffer builds output in a buffer passed in by the caller.
+func RenderToBuffer(buf *bytes.Buffer, name string) {
+	buf.WriteString("hello, ")
+	buf.WriteString(name)
+}
+
+// RenderToBuilder writes to a strings.Builder with fmt.Fprintf.
+func RenderToBuilder(sb *strings.Builder, n int) {
+	fmt.Fprintf(sb, "%d items", n)
+}
+
+// PrintToWriter writes to an io.Writer parameter with fmt.Fprintln.
+func PrintToWriter(w io.Writer, msg string) {
+	fmt.Fprintln(w, msg)
+}
+
+// LocalBuffer builds a string in its own buffer — should NOT
+// produce WriterOutput.
+func LocalBuffer(name string) string {
+	var sb strings.Builder
+	sb.WriteString("hello, ")
+	fmt.Fprintf(&sb, "%s", name)
+	return sb.String()
+}
@@ -0,0 +1,44 @@
+package p2effects
+
+import (
+	"context"
+	"net/http"
+)
+
+// --- Context Value ---
+
+type ctxKey int
+
+const userKey ctxKey = 0
+
+// WithUser returns a context carrying the user.
+func WithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userKey, user)
+}
+
+// UserMiddleware injects the user into the request context for next.
+func UserMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), userKey, r.Header.Get("X-User"))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// WithUserNamed assigns the derived context to a named result.
+func WithUserNamed(parent context.Context, user string) (ctx context.Context) {
+	ctx = context.WithValue(parent, userKey, user)
+	return
+}
+
+// LocalContextValue derives a context it never hands to anyone.
+func LocalContextValue(ctx context.Context) bool {
+	local := context.WithValue(ctx, userKey, "nobody")
+	return local.Value(userKey) != nil
+}
+
+// PassedBeforeValue passes ctx on before replacing it locally.
+func PassedBeforeValue(ctx context.Context, next func(context.Context)) {
+	next(ctx)
+	ctx = context.WithValue(ctx, userKey, "late")
+	_ = ctx.Err()
+}
@@ -158,6 +158,189 @@ func NoCallback(x int) int {
 	return x + 1
 }
 
+// --- Method Value Escape ---
+
+// Counter is a receiver whose methods are handed out as callbacks.
+type Counter struct{ n int }
+
+// Inc mutates the counter through a pointer receiver.
+func (c *Counter) Inc() { c.n++ }
+
+// Value reads the counter through a value receiver.
+func (c Counter) Value() int { return c.n }
+
+// Incrementer is satisfied by *Counter.
+type Incrementer interface{ Inc() }
+
+// Subscribe accepts a callback to invoke later.
+func Subscribe(fn func()) {}
+
+// RegisterMethodValue passes a pointer-receiver method value as a
+// callback, so Subscribe may mutate c later.
+func RegisterMethodValue(c *Counter) {
+	Subscribe(c.Inc)
+}
+
+// ReturnMethodValue returns a pointer-receiver method value.
+func ReturnMethodValue(c *Counter) func() {
+	return (c.Inc)
+}
+
+// RegisterMethodExpr passes a pointer-receiver method expression.
+func RegisterMethodExpr(apply func(func(*Counter))) {
+	apply((*Counter).Inc)
+}
+
+// RegisterValueMethod passes a value-receiver method value (should
+// NOT trigger MethodValueEscape).
+func RegisterValueMethod(c Counter, report func(func() int)) {
+	report(c.Value)
+}
+
+// RegisterInterfaceMethod passes an interface method value (should
+// NOT trigger MethodValueEscape).
+func RegisterInterfaceMethod(i Incrementer) {
+	Subscribe(i.Inc)
+}
+
+// CallMethodDirectly calls the method rather than passing it (should
+// NOT trigger MethodValueEscape).
+func CallMethodDirectly(c *Counter) {
+	c.Inc()
+}
+
+// --- Local Pointer Escape ---
+
+// Settings is a struct handed out by pointer.
+type Settings struct {
+	Name  string
+	Limit int
+	slots [4]int
+}
+
+// NewSettings returns the address of a composite literal.
+func NewSettings() *Settings {
+	return &Settings{}
+}
+
+// AllocSettings returns a pointer from new.
+func AllocSettings() *Settings {
+	return new(Settings)
+}
+
+// LocalSettings returns the address of a local variable.
+func LocalSettings(name string) (*Settings, error) {
+	s := Settings{}
+	s.Name = name
+	return &s, nil
+}
+
+// BuiltSettings returns a local pointer variable assigned an
+// allocation.
+func BuiltSettings() *Settings {
+	s := &Settings{}
+	s.Limit = 5
+	return s
+}
+
+// LocalSlot returns the address of an array element of a local.
+func LocalSlot() *int {
+	var s Settings
+	return &s.slots[0]
+}
+
+// PassThrough returns its pointer parameter (should NOT trigger
+// LocalPointerEscape).
+func PassThrough(s *Settings) *Settings {
+	return s
+}
+
+// FieldOfParam returns the address of a field behind a pointer
+// parameter (should NOT trigger LocalPointerEscape).
+func FieldOfParam(s *Settings) *int {
+	return &s.Limit
+}
+
+// SettingsAsAny returns an allocation as an interface (should NOT
+// trigger LocalPointerEscape).
+func SettingsAsAny() any {
+	return &Settings{}
+}
+
+// SettingsFactory returns a closure that allocates; the closure's
+// return is not the function's (should NOT trigger
+// LocalPointerEscape).
+func SettingsFactory() func() *Settings {
+	return func() *Settings { return &Settings{} }
+}
+
+// --- Internal Pointer Escape ---
+
+// Entry is an element of a Store.
+type Entry struct {
+	Name string
+}
+
+// Store keeps its entries private.
+type Store struct {
+	cur     *Entry
+	entry   Entry
+	entries []Entry
+	meta    struct{ owner *Entry }
+	Public  *Entry
+}
+
+// Current returns a pointer-typed unexported field.
+func (s *Store) Current() *Entry {
+	return s.cur
+}
+
+// EntryRef returns the address of an unexported field.
+func (s *Store) EntryRef() *Entry {
+	return &s.entry
+}
+
+// At returns the address of an element of an unexported slice.
+func (s Store) At(i int) *Entry {
+	return &s.entries[i]
+}
+
+// Owner returns a pointer field nested in an unexported field.
+func (s Store) Owner() *Entry {
+	return s.meta.owner
+}
+
+// CurrentByValue returns a pointer field from a value receiver,
+// which still points at the shared entry.
+func (s Store) CurrentByValue() *Entry {
+	return s.cur
+}
+
+// CopyRef returns the address of a field of a value receiver, which
+// points into the method's copy (should NOT trigger
+// InternalPointerEscape).
+func (s Store) CopyRef() *Entry {
+	return &s.entry
+}
+
+// PublicEntry returns an exported field (should NOT trigger
+// InternalPointerEscape).
+func (s *Store) PublicEntry() *Entry {
+	return s.Public
+}
+
+// CurrentAsAny returns the field as an interface (should NOT trigger
+// InternalPointerEscape).
+func (s *Store) CurrentAsAny() any {
+	return s.cur
+}
+
+// CurrentOf returns a field of a parameter, not the receiver (should
+// NOT trigger InternalPointerEscape).
+func CurrentOf(s *Store) *Entry {
+	return s.cur
+}
+
 // --- Database Write ---
 
 // DBExec executes a database write.
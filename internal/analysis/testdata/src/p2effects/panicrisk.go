@@ -0,0 +1,103 @@
+package p2effects
+
+// --- Panic: statically certain runtime panics ---
+
+// NilMapWrite writes to a map that is declared but never made.
+func NilMapWrite(k string) {
+	var m map[string]int
+	m[k] = 1
+}
+
+// NilMapIncrement increments an entry of a never-made map.
+func NilMapIncrement(k string) int {
+	var counts map[string]int
+	counts[k]++
+	return len(counts)
+}
+
+// SliceIndexPastLen indexes a constant-length slice past its end.
+func SliceIndexPastLen() int {
+	buf := make([]int, 3)
+	return buf[5]
+}
+
+// SliceLiteralIndexPastLen writes past the end of a slice literal.
+func SliceLiteralIndexPastLen() []string {
+	names := []string{"a", "b"}
+	names[2] = "c"
+	return names
+}
+
+// NilSliceIndex reads from a slice that is declared but never set.
+func NilSliceIndex() byte {
+	var b []byte
+	return b[0]
+}
+
+// --- Not certain: no Panic ---
+
+// MadeMapWrite assigns the map before writing to it.
+func MadeMapWrite(k string) map[string]int {
+	var m map[string]int
+	m = make(map[string]int)
+	m[k] = 1
+	return m
+}
+
+// MapMadeInClosure assigns the map in a closure.
+func MapMadeInClosure(k string) {
+	var m map[string]int
+	init := func() { m = map[string]int{} }
+	init()
+	m[k] = 1
+}
+
+// MapAddressTaken lets another function initialize the map.
+func MapAddressTaken(k string) {
+	var m map[string]int
+	initMap(&m)
+	m[k] = 1
+}
+
+func initMap(m *map[string]int) { *m = map[string]int{} }
+
+// NilMapRead only reads, which is safe on a nil map.
+func NilMapRead(k string) int {
+	var m map[string]int
+	return m[k]
+}
+
+// SliceIndexInBounds stays within the slice.
+func SliceIndexInBounds() int {
+	buf := make([]int, 3)
+	return buf[2]
+}
+
+// SliceAppended grows the slice before indexing.
+func SliceAppended() int {
+	buf := make([]int, 3)
+	buf = append(buf, 4, 5, 6)
+	return buf[5]
+}
+
+// SliceVariableIndex uses an index that is not a constant.
+func SliceVariableIndex(i int) int {
+	buf := make([]int, 3)
+	return buf[i]
+}
+
+// SliceVariableLen has a length that is not a constant.
+func SliceVariableLen(n int) int {
+	buf := make([]int, n)
+	return buf[5]
+}
+
+// ShadowedMap writes to an inner map that is made, not the nil one.
+func ShadowedMap(k string) {
+	var m map[string]int
+	_ = len(m)
+	{
+		m := make(map[string]int)
+		m[k] = 1
+	}
+}
@@ -0,0 +1,256 @@
+// Package p3effects provides test fixtures for P3-tier side effect
+// detection.
+package p3effects
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	myatomic "sync/atomic"
+	"time"
+)
+
+// --- AtomicOp: free functions ---
+
+var hits int64
+
+// AddHit increments a counter with atomic.AddInt64.
+func AddHit() {
+	atomic.AddInt64(&hits, 1)
+}
+
+// ResetHits stores through an aliased import.
+func ResetHits() {
+	myatomic.StoreInt64(&hits, 0)
+}
+
+// ReadHits only loads; no AtomicOp.
+func ReadHits() int64 {
+	return atomic.LoadInt64(&hits)
+}
+
+// --- AtomicOp: typed atomics ---
+
+// Counters holds typed atomics.
+type Counters struct {
+	n       atomic.Int64
+	ready   atomic.Bool
+	current atomic.Pointer[string]
+	value   atomic.Value
+}
+
+// Inc adds to a typed atomic.
+func (c *Counters) Inc() {
+	c.n.Add(1)
+}
+
+// Publish stores, swaps, and compares-and-swaps typed atomics.
+func (c *Counters) Publish(s *string) {
+	c.ready.Store(true)
+	old := c.current.Swap(s)
+	c.current.CompareAndSwap(old, s)
+	c.value.Store(s)
+}
+
+// Count only loads; no AtomicOp.
+func (c *Counters) Count() int64 {
+	if c.ready.Load() {
+		return c.n.Load()
+	}
+	return 0
+}
+
+// Embedded promotes the methods of an embedded typed atomic.
+type Embedded struct {
+	atomic.Uint32
+}
+
+// Bump calls a promoted typed atomic method.
+func (e *Embedded) Bump() {
+	e.Add(1)
+}
+
+// --- Not atomic ---
+
+// Tally has methods named like atomic operations.
+type Tally struct{ n int }
+
+// Add is not a sync/atomic operation.
+func (t *Tally) Add(d int) { t.n += d }
+
+// AddToTally calls a user-defined Add; no AtomicOp.
+func AddToTally(t *Tally) {
+	t.Add(1)
+}
+
+// --- DeferredCleanup ---
+
+// Resource is a closable resource.
+type Resource struct{}
+
+// Close releases the resource.
+func (r *Resource) Close() error { return nil }
+
+// Tx is a transaction that can be rolled back.
+type Tx struct{}
+
+// Rollback aborts the transaction.
+func (tx *Tx) Rollback() error { return nil }
+
+// UseResource defers a Close.
+func UseResource(r *Resource) {
+	defer r.Close()
+}
+
+// Guarded defers an Unlock and a RUnlock.
+func Guarded(mu *sync.Mutex, rw *sync.RWMutex) {
+	mu.Lock()
+	defer mu.Unlock()
+	rw.RLock()
+	defer rw.RUnlock()
+}
+
+// Worker defers wg.Done.
+func Worker(wg *sync.WaitGroup) {
+	defer wg.Done()
+}
+
+// WithTimeout defers the cancel func.
+func WithTimeout(parent context.Context) {
+	ctx, cancel := context.WithTimeout(parent, time.Second)
+	defer cancel()
+	_ = ctx
+}
+
+// Transact defers a Rollback inside a closure.
+func Transact(tx *Tx) {
+	defer func() {
+		_ = tx.Rollback()
+	}()
+}
+
+// DeferLog defers a call that is not cleanup; no DeferredCleanup.
+func DeferLog() {
+	defer fmt.Println("done")
+}
+
+// NestedDefer defers inside a goroutine's func literal, which runs
+// on the literal's return; no DeferredCleanup.
+func NestedDefer(r *Resource) {
+	go func() {
+		defer r.Close()
+	}()
+}
+
+// pollInterval is the delay between Poll attempts.
+const pollInterval = 250 * time.Millisecond
+
+// Backoff sleeps for a constant duration; TimeDependency (blocking
+// sleep of 2s).
+func Backoff() {
+	time.Sleep(2 * time.Second)
+}
+
+// Poll sleeps for a named constant between attempts; TimeDependency
+// (blocking sleep of 250ms).
+func Poll(ready func() bool) {
+	for !ready() {
+		time.Sleep(pollInterval)
+	}
+}
+
+// Delay sleeps for a caller-supplied duration; TimeDependency
+// without a fixed duration.
+func Delay(d time.Duration) {
+	time.Sleep(d)
+}
+
+// Stamp reads the clock but does not block; no TimeDependency sleep.
+func Stamp() time.Time {
+	return time.Now()
+}
+
+// Clock is a fake-able clock whose Sleep method is not time.Sleep.
+type Clock struct{}
+
+// Sleep does nothing.
+func (Clock) Sleep(time.Duration) {}
+
+// WaitOn sleeps through an injected clock; no TimeDependency.
+func WaitOn(c Clock) {
+	c.Sleep(time.Second)
+}
+
+// Swallow discards any panic from f; RecoverBehavior (always
+// swallows).
+func Swallow(f func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Println("recovered:", r)
+		}
+	}()
+	f()
+}
+
+// ToError converts a panic from f into its error result;
+// RecoverBehavior (converts to 'err').
+func ToError(f func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	f()
+	return nil
+}
+
+// Filter converts error panics and re-panics with anything else;
+// RecoverBehavior (conditionally re-panics).
+func Filter(f func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok {
+				err = e
+				return
+			}
+			panic(r)
+		}
+	}()
+	f()
+	return nil
+}
+
+// Rethrow logs and propagates every panic; RecoverBehavior (always
+// re-panics).
+func Rethrow(f func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Println("panicking:", r)
+			panic(r)
+		}
+	}()
+	f()
+}
+
+// SwitchOnPanic re-panics unless the value is a string;
+// RecoverBehavior (conditionally re-panics).
+func SwitchOnPanic(f func()) {
+	defer func() {
+		switch r := recover().(type) {
+		case nil, string:
+		default:
+			panic(r)
+		}
+	}()
+	f()
+}
+
+// NestedRecover calls recover in a closure inside the deferred
+// function, where it does not stop the panic; no RecoverBehavior.
+func NestedRecover(f func()) {
+	defer func() {
+		func() { _ = recover() }()
+	}()
+	f()
+}
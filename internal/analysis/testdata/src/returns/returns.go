@@ -4,6 +4,7 @@ package returns
 import (
 	"errors"
 	"io"
+	"strings"
 )
 
 // PureFunction has no return values — no side effects expected.
@@ -50,6 +51,82 @@ func NamedReturnModifiedInDefer() (err error) {
 	return nil
 }
 
+// Config is a struct returned by value.
+type Config struct {
+	Name string
+}
+
+// NonErrorNamedReturnInDefer replaces a non-error named return with
+// a fallback in a deferred function.
+func NonErrorNamedReturnInDefer() (result []byte) {
+	defer func() {
+		if result == nil {
+			result = []byte("fallback")
+		}
+	}()
+	return nil
+}
+
+// NamedReturnIncrementedInDefer increments a named return in a
+// deferred function.
+func NamedReturnIncrementedInDefer() (n int) {
+	defer func() { n++ }()
+	return 1
+}
+
+// NamedReturnFieldSetInDefer sets a field of a named struct return in
+// a deferred function.
+func NamedReturnFieldSetInDefer() (cfg Config) {
+	defer func() { cfg.Name = "default" }()
+	return Config{}
+}
+
+// ShadowedNamedReturnInDefer assigns only a local variable that
+// shadows the named return, so the return value is not modified.
+func ShadowedNamedReturnInDefer() (result int) {
+	defer func() {
+		result := 2
+		result++
+		_ = result
+	}()
+	return 1
+}
+
+// Limit is a package-level constant returned by ReturnSources.
+const Limit = 10
+
+// Counter is a receiver for return source fixtures.
+type Counter struct {
+	count int
+}
+
+// Count returns a receiver field, or a literal for a nil receiver.
+func (c *Counter) Count() int {
+	if c == nil {
+		return 0
+	}
+	return c.count
+}
+
+// ReturnSources returns a parameter, a constant, a call, or a
+// computation, and forwards a nested function's return untouched.
+func ReturnSources(n int, s string) (int, string) {
+	inner := func() int { return 99 }
+	switch {
+	case n < 0:
+		return n, s
+	case n > Limit:
+		return Limit, strings.ToUpper(s)
+	}
+	return n * inner(), s + "!"
+}
+
+// Bounds declares two results in one field, so both share a type
+// and a position.
+func Bounds(xs []int) (lo, hi int) {
+	return xs[0], xs[len(xs)-1]
+}
+
 // InterfaceReturn returns an interface type.
 func InterfaceReturn() io.Reader {
 	return nil
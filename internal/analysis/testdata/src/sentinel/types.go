@@ -0,0 +1,32 @@
+package sentinel
+
+// ErrTimeout is declared with an explicit error type.
+var ErrTimeout error = timeoutError("timed out")
+
+// ErrClosed points to a custom error type.
+var ErrClosed = &NotFoundError{Key: "closed"}
+
+// ErrRetries has the sentinel prefix but is not an error — should
+// NOT be detected.
+var ErrRetries = 3
+
+// NotFoundError is an exported error type matched with errors.As.
+type NotFoundError struct {
+	Key string
+}
+
+// Error implements error.
+func (e *NotFoundError) Error() string { return e.Key + " not found" }
+
+// timeoutError is unexported — should NOT be reported as an error
+// type.
+type timeoutError string
+
+// Error implements error.
+func (e timeoutError) Error() string { return string(e) }
+
+// Options is an exported struct that does not implement error —
+// should NOT be detected.
+type Options struct {
+	Verbose bool
+}
@@ -0,0 +1,9 @@
+package testfiles_test
+
+import "github.com/unbound-force/gaze/internal/analysis/testdata/src/testfiles"
+
+// ResetLimit restores the default limit from an external test
+// package.
+func ResetLimit() {
+	testfiles.Limit = 10
+}
@@ -0,0 +1,6 @@
+package testfiles
+
+// SetupLimit is a shared test helper that mutates package state.
+func SetupLimit(n int) {
+	Limit = n
+}
@@ -0,0 +1,14 @@
+// Package testfiles provides test fixtures for analyzing functions
+// declared in _test.go files.
+package testfiles
+
+// Limit caps the values Clamp returns.
+var Limit = 10
+
+// Clamp returns v capped at Limit.
+func Clamp(v int) int {
+	if v > Limit {
+		return Limit
+	}
+	return v
+}
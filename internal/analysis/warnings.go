@@ -0,0 +1,107 @@
+package analysis
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+// Warnings attached to results whose side effects may be incomplete.
+// They tell users when a short or empty effect list should not be
+// trusted as "no side effects".
+const (
+	warnNoBody = "analysis: function has no Go body (assembly or linkname); " +
+		"only effects visible in the signature are reported"
+	warnCgo = "analysis: calls C code via cgo; " +
+		"effects inside C functions are not detected"
+	warnReflect = "analysis: mutates or calls through reflect.Value; " +
+		"effects performed via reflection are not detected"
+	warnDotImport = "analysis: uses dot-imported identifiers; " +
+		"effects of unqualified calls into other packages may be missed"
+)
+
+// analysisWarnings reports the constructs in fd that the analyzers
+// cannot fully reason about. Each warning appears at most once, in
+// a fixed order. Returns nil when the analysis is complete.
+func analysisWarnings(info *types.Info, self *types.Package, fd *ast.FuncDecl) []string {
+	if fd.Body == nil {
+		return []string{warnNoBody}
+	}
+
+	var cgo, reflection, dotImport bool
+	// Selected names are qualified and must not be mistaken for
+	// dot-imported identifiers. Inspect visits a selector before its
+	// Sel, so recording them here is enough.
+	qualified := make(map[*ast.Ident]bool)
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SelectorExpr:
+			qualified[node.Sel] = true
+			if isCgoRef(node.X, info) {
+				cgo = true
+			}
+			if isReflectMutation(node, info) {
+				reflection = true
+			}
+		case *ast.Ident:
+			if strings.HasPrefix(node.Name, "_Cfunc_") {
+				cgo = true
+			}
+			if !qualified[node] && isDotImported(node, info, self) {
+				dotImport = true
+			}
+		}
+		return true
+	})
+
+	var warnings []string
+	if cgo {
+		warnings = append(warnings, warnCgo)
+	}
+	if reflection {
+		warnings = append(warnings, warnReflect)
+	}
+	if dotImport {
+		warnings = append(warnings, warnDotImport)
+	}
+	return warnings
+}
+
+// isCgoRef reports whether x is the pseudo-package C, as in C.free.
+func isCgoRef(x ast.Expr, info *types.Info) bool {
+	ident, ok := x.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	if pkgName, ok := info.Uses[ident].(*types.PkgName); ok {
+		return pkgName.Imported().Path() == "C"
+	}
+	return false
+}
+
+// isReflectMutation reports whether sel selects a reflect.Value
+// method that writes through or invokes the underlying value
+// (Set*, Call, CallSlice).
+func isReflectMutation(sel *ast.SelectorExpr, info *types.Info) bool {
+	s, ok := info.Selections[sel]
+	if !ok || s.Kind() != types.MethodVal {
+		return false
+	}
+	fn := s.Obj()
+	if fn.Pkg() == nil || fn.Pkg().Path() != "reflect" {
+		return false
+	}
+	name := fn.Name()
+	return strings.HasPrefix(name, "Set") || name == "Call" || name == "CallSlice"
+}
+
+// isDotImported reports whether ident refers to a package-level
+// object of another package without a qualifier, which is only
+// possible through a dot-import.
+func isDotImported(ident *ast.Ident, info *types.Info, self *types.Package) bool {
+	obj := info.Uses[ident]
+	if obj == nil || obj.Pkg() == nil || obj.Pkg() == self {
+		return false
+	}
+	return obj.Parent() == obj.Pkg().Scope()
+}
@@ -0,0 +1,164 @@
+// Package cache provides a content-addressed on-disk cache of
+// analysis results, keyed by a hash of a package's source files, so
+// that unchanged packages can skip side effect analysis.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// Cache stores serialized []taxonomy.AnalysisResult values in a
+// directory, one JSON file per key.
+type Cache struct {
+	dir string
+}
+
+// New returns a Cache rooted at dir, creating the directory if it
+// does not exist.
+func New(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating cache dir %q: %w", dir, err)
+	}
+	return &Cache{dir: dir}, nil
+}
+
+// Load returns the results stored under key and true, or nil and
+// false on a miss. An unreadable or corrupt entry is treated as a
+// miss so that a damaged cache never blocks analysis.
+func (c *Cache) Load(key string) ([]taxonomy.AnalysisResult, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var results []taxonomy.AnalysisResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, false
+	}
+	return results, true
+}
+
+// Store writes results under key. The entry is written to a
+// temporary file and renamed into place so that concurrent readers
+// never observe a partial write.
+func (c *Cache) Store(key string, results []taxonomy.AnalysisResult) error {
+	data, err := json.Marshal(results)
+	if err != nil {
+		return fmt.Errorf("encoding cache entry: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating cache entry: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		return fmt.Errorf("committing cache entry: %w", err)
+	}
+	return nil
+}
+
+// path returns the file path for key.
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// Key computes a content hash for pkg. The hash covers the package
+// path, the path and contents of every compiled Go file in the
+// package and in each transitively imported package from the same
+// module, the path and module version of imports from other
+// modules, and any caller-supplied salt (e.g. tool version and
+// analysis options). Any change to these inputs yields a new key.
+func Key(pkg *packages.Package, salt ...string) (string, error) {
+	h := sha256.New()
+	for _, s := range salt {
+		writeField(h, s)
+	}
+	writeField(h, pkg.PkgPath)
+	if err := hashFiles(h, pkg); err != nil {
+		return "", err
+	}
+
+	modPath := ""
+	if pkg.Module != nil {
+		modPath = pkg.Module.Path
+	}
+
+	deps := make(map[string]*packages.Package)
+	collectImports(pkg, deps)
+	paths := make([]string, 0, len(deps))
+	for p := range deps {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		dep := deps[p]
+		writeField(h, dep.PkgPath)
+		if modPath != "" && dep.Module != nil && dep.Module.Path == modPath {
+			if err := hashFiles(h, dep); err != nil {
+				return "", err
+			}
+			continue
+		}
+		if dep.Module != nil {
+			writeField(h, dep.Module.Path+"@"+dep.Module.Version)
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashFiles writes the path and contents of each compiled Go file
+// in pkg to h.
+func hashFiles(h io.Writer, pkg *packages.Package) error {
+	files := pkg.CompiledGoFiles
+	if len(files) == 0 {
+		files = pkg.GoFiles
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return fmt.Errorf("hashing %q: %w", f, err)
+		}
+		writeField(h, f)
+		writeField(h, string(data))
+	}
+	return nil
+}
+
+// collectImports adds every package transitively imported by pkg
+// to deps, keyed by package path.
+func collectImports(pkg *packages.Package, deps map[string]*packages.Package) {
+	for path, imp := range pkg.Imports {
+		if _, ok := deps[path]; ok {
+			continue
+		}
+		deps[path] = imp
+		collectImports(imp, deps)
+	}
+}
+
+// writeField writes s to h prefixed with its length so that field
+// boundaries are unambiguous.
+func writeField(h io.Writer, s string) {
+	_, _ = fmt.Fprintf(h, "%d:%s", len(s), s)
+}
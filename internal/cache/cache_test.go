@@ -0,0 +1,160 @@
+package cache_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/unbound-force/gaze/internal/cache"
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+func sampleResults() []taxonomy.AnalysisResult {
+	return []taxonomy.AnalysisResult{{
+		Target: taxonomy.FunctionTarget{
+			Package:   "example.com/p",
+			Function:  "Get",
+			Signature: "func Get() int",
+			Location:  "p.go:3:1",
+		},
+		SideEffects: []taxonomy.SideEffect{{
+			ID:          "se-00000001",
+			Type:        taxonomy.ReturnValue,
+			Tier:        taxonomy.TierP0,
+			Location:    "p.go:3:1",
+			Description: "returns int value",
+			Target:      "int",
+		}},
+	}}
+}
+
+func TestStoreLoad_RoundTrip(t *testing.T) {
+	c, err := cache.New(filepath.Join(t.TempDir(), "nested", "cache"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	want := sampleResults()
+	if err := c.Store("k1", want); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	got, ok := c.Load("k1")
+	if !ok {
+		t.Fatal("Load: expected hit")
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoad_Miss(t *testing.T) {
+	c, err := cache.New(t.TempDir())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, ok := c.Load("absent"); ok {
+		t.Error("Load(absent): expected miss")
+	}
+}
+
+func TestLoad_CorruptEntryIsMiss(t *testing.T) {
+	dir := t.TempDir()
+	c, err := cache.New(dir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "bad.json"), []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Load("bad"); ok {
+		t.Error("Load(bad): expected miss for corrupt entry")
+	}
+}
+
+// writeModule creates a minimal module with one package and returns
+// the module directory.
+func writeModule(t *testing.T, src string) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":   "module example.com/m\n\ngo 1.24\n",
+		"p/p.go":   src,
+		"q/q.go":   "package q\n\nfunc Q() int { return 1 }\n",
+		"p/gen.go": "package p\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func loadPkg(t *testing.T, dir string) *packages.Package {
+	t.Helper()
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles |
+			packages.NeedImports | packages.NeedDeps | packages.NeedModule,
+		Dir: dir,
+	}
+	pkgs, err := packages.Load(cfg, "./p")
+	if err != nil || len(pkgs) != 1 {
+		t.Fatalf("loading package: %v (%d pkgs)", err, len(pkgs))
+	}
+	return pkgs[0]
+}
+
+func TestKey_ChangesWithContent(t *testing.T) {
+	src := "package p\n\nimport \"example.com/m/q\"\n\nfunc P() int { return q.Q() }\n"
+	dir := writeModule(t, src)
+
+	k1, err := cache.Key(loadPkg(t, dir), "v1")
+	if err != nil {
+		t.Fatalf("Key: %v", err)
+	}
+	k2, err := cache.Key(loadPkg(t, dir), "v1")
+	if err != nil {
+		t.Fatalf("Key: %v", err)
+	}
+	if k1 != k2 {
+		t.Errorf("Key not stable for unchanged package: %s vs %s", k1, k2)
+	}
+
+	if k, _ := cache.Key(loadPkg(t, dir), "v2"); k == k1 {
+		t.Error("Key should change with salt")
+	}
+
+	// Editing a same-module dependency invalidates the key.
+	if err := os.WriteFile(filepath.Join(dir, "q", "q.go"),
+		[]byte("package q\n\nfunc Q() int { return 2 }\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	k3, err := cache.Key(loadPkg(t, dir), "v1")
+	if err != nil {
+		t.Fatalf("Key: %v", err)
+	}
+	if k3 == k1 {
+		t.Error("Key should change when a same-module dependency changes")
+	}
+
+	// Editing the package itself invalidates the key.
+	if err := os.WriteFile(filepath.Join(dir, "p", "p.go"),
+		[]byte(src+"\nfunc Extra() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	k4, err := cache.Key(loadPkg(t, dir), "v1")
+	if err != nil {
+		t.Fatalf("Key: %v", err)
+	}
+	if k4 == k3 {
+		t.Error("Key should change when the package source changes")
+	}
+}
@@ -0,0 +1,169 @@
+// Package classify implements the contractual classification engine.
+package classify
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/unbound-force/gaze/internal/config"
+	"github.com/unbound-force/gaze/internal/docscan"
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// AnalyzeArchitectureDocSignal searches the given architecture
+// documents for a whole-word mention of funcName inside a section
+// whose heading (at any enclosing level) matches one of the
+// configured ArchitectureDocs heading patterns, such as "Public API"
+// or "Contracts". It returns a positive "architecture_doc" signal
+// for the first such mention. A function a team has listed as part
+// of its declared contract is stronger evidence than a passing
+// README mention, so the default weight is higher than "readme".
+// The documents are expected to come from loadArchitectureDocs.
+func AnalyzeArchitectureDocSignal(
+	funcName string,
+	_ taxonomy.SideEffectType,
+	docs []docscan.DocumentFile,
+	cfg *config.GazeConfig,
+) taxonomy.Signal {
+	if funcName == "" || funcName == "<package>" || len(docs) == 0 {
+		return taxonomy.Signal{}
+	}
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	patterns := cfg.Classification.ArchitectureDocs.ResolvedHeadings()
+
+	for _, doc := range docs {
+		heading, line, ok := findContractMention(doc.Content, funcName, patterns)
+		if !ok {
+			continue
+		}
+		w := weightFor(cfg, "architecture_doc")
+		return taxonomy.Signal{
+			Source:     "architecture_doc",
+			Weight:     min(w.Base, w.Max),
+			SourceFile: doc.Path,
+			Excerpt:    truncateExcerpt(line),
+			Reasoning:  "function \"" + funcName + "\" is listed under heading \"" + heading + "\" in " + doc.Path,
+		}
+	}
+
+	return taxonomy.Signal{}
+}
+
+// mdHeading is one entry of the open Markdown heading stack.
+type mdHeading struct {
+	level int
+	text  string
+}
+
+// findContractMention scans Markdown content line by line, tracking
+// the stack of enclosing ATX headings, and returns the innermost
+// heading matching one of patterns together with the trimmed line
+// of the first mention of name beneath it. Mentions follow
+// findDocMention's rules, so a single-word name such as Close must be
+// qualified or inside code. Lines inside fenced code blocks are
+// searched for mentions but never treated as headings.
+func findContractMention(content, name string, patterns []string) (heading, line string, ok bool) {
+	var stack []mdHeading
+	inFence := false
+	for _, raw := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(raw)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if !inFence {
+			if level, text, isHeading := parseATXHeading(trimmed); isHeading {
+				for len(stack) > 0 && stack[len(stack)-1].level >= level {
+					stack = stack[:len(stack)-1]
+				}
+				stack = append(stack, mdHeading{level: level, text: text})
+			}
+		}
+		match := matchingHeading(stack, patterns)
+		if match == "" {
+			continue
+		}
+		if lineMentions(raw, name, inFence) {
+			return match, trimmed, true
+		}
+	}
+	return "", "", false
+}
+
+// parseATXHeading parses a trimmed line as a Markdown ATX heading
+// ("## Public API") and returns its level and text.
+func parseATXHeading(line string) (int, string, bool) {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 {
+		return 0, "", false
+	}
+	rest := line[level:]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return 0, "", false
+	}
+	text := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(rest), "#"))
+	return level, text, true
+}
+
+// matchingHeading returns the text of the innermost heading in
+// stack that contains one of patterns, compared case-insensitively,
+// or "" if none does.
+func matchingHeading(stack []mdHeading, patterns []string) string {
+	for i := len(stack) - 1; i >= 0; i-- {
+		text := strings.ToLower(stack[i].text)
+		for _, p := range patterns {
+			if p != "" && strings.Contains(text, strings.ToLower(p)) {
+				return stack[i].text
+			}
+		}
+	}
+	return ""
+}
+
+// loadArchitectureDocs reads the documents named by the configured
+// ArchitectureDocs paths, resolved against opts.ModuleRoot. Glob
+// patterns are expanded in sorted order and each file is read once.
+// Missing or unreadable files are skipped so that classification
+// degrades to the remaining signals.
+func loadArchitectureDocs(opts Options) []docscan.DocumentFile {
+	paths := opts.Config.Classification.ArchitectureDocs.Paths
+	if opts.ModuleRoot == "" || len(paths) == 0 {
+		return nil
+	}
+
+	var docs []docscan.DocumentFile
+	seen := make(map[string]bool)
+	for _, p := range paths {
+		matches, err := filepath.Glob(filepath.Join(opts.ModuleRoot, filepath.FromSlash(p)))
+		if err != nil {
+			continue
+		}
+		sort.Strings(matches)
+		for _, m := range matches {
+			if seen[m] {
+				continue
+			}
+			seen[m] = true
+			content, err := os.ReadFile(m)
+			if err != nil {
+				continue
+			}
+			rel, err := filepath.Rel(opts.ModuleRoot, m)
+			if err != nil {
+				rel = m
+			}
+			docs = append(docs, docscan.DocumentFile{
+				Path:    filepath.ToSlash(rel),
+				Content: string(content),
+			})
+		}
+	}
+	return docs
+}
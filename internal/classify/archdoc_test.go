@@ -0,0 +1,169 @@
+package classify_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/unbound-force/gaze/internal/analysis"
+	"github.com/unbound-force/gaze/internal/classify"
+	"github.com/unbound-force/gaze/internal/config"
+	"github.com/unbound-force/gaze/internal/docscan"
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// archDoc is a sample architecture document used across the
+// architecture_doc tests.
+const archDoc = `# Architecture
+
+Load is an internal helper.
+
+## Public API
+
+### Storage
+
+- ` + "`Save`" + ` persists a record.
+
+Close the store when you are done with it.
+
+` + "```go" + `
+# not a heading
+` + "```" + `
+
+## Internals
+
+` + "`Delete`" + ` is not part of the contract.
+`
+
+// TestAnalyzeArchitectureDocSignal verifies that only mentions under
+// a matching heading produce a signal and that the reasoning names
+// the matched heading.
+func TestAnalyzeArchitectureDocSignal(t *testing.T) {
+	docs := []docscan.DocumentFile{{Path: "docs/ARCHITECTURE.md", Content: archDoc}}
+
+	tests := []struct {
+		name    string
+		fn      string
+		heading string
+	}{
+		{"nested under matching heading", "Save", "Public API"},
+		{"before any matching heading", "Load", ""},
+		{"under sibling heading", "Delete", ""},
+		{"absent", "Fetch", ""},
+		{"single word in prose", "Close", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig := classify.AnalyzeArchitectureDocSignal(tt.fn, taxonomy.ReceiverMutation, docs, nil)
+			if tt.heading == "" {
+				if sig.Source != "" {
+					t.Errorf("got %+v, want no signal", sig)
+				}
+				return
+			}
+			if sig.Source != "architecture_doc" || sig.Weight != 20 {
+				t.Errorf("got source %q weight %d, want architecture_doc/20", sig.Source, sig.Weight)
+			}
+			if !strings.Contains(sig.Reasoning, `"`+tt.heading+`"`) {
+				t.Errorf("reasoning %q does not name heading %q", sig.Reasoning, tt.heading)
+			}
+			if sig.SourceFile != "docs/ARCHITECTURE.md" || sig.Excerpt != "- `Save` persists a record." {
+				t.Errorf("SourceFile = %q, Excerpt = %q", sig.SourceFile, sig.Excerpt)
+			}
+		})
+	}
+}
+
+// TestAnalyzeArchitectureDocSignal_QualifiedMention verifies that a
+// single-word name counts when qualified or in code, but not as a
+// bare word in prose.
+func TestAnalyzeArchitectureDocSignal_QualifiedMention(t *testing.T) {
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{"## Contracts\n\nGet the record and Close the store.\n", false},
+		{"## Contracts\n\n- Store.Close releases the file.\n", true},
+		{"## Contracts\n\n- `Close` releases the file.\n", true},
+		{"## Contracts\n\n```go\ns.Close()\n```\n", true},
+	}
+	for _, tt := range tests {
+		docs := []docscan.DocumentFile{{Path: "ARCHITECTURE.md", Content: tt.content}}
+		sig := classify.AnalyzeArchitectureDocSignal("Close", taxonomy.ReceiverMutation, docs, nil)
+		if got := sig.Source == "architecture_doc"; got != tt.want {
+			t.Errorf("%q: signal = %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+// TestAnalyzeArchitectureDocSignal_Config verifies that heading
+// patterns and the weight come from the configuration.
+func TestAnalyzeArchitectureDocSignal_Config(t *testing.T) {
+	docs := []docscan.DocumentFile{{Path: "ADR.md", Content: archDoc}}
+
+	cfg := config.DefaultConfig()
+	cfg.Classification.ArchitectureDocs.Headings = []string{"internals"}
+	cfg.Classification.Weights["architecture_doc"] = config.SignalWeight{Base: 25, Max: 25}
+
+	if sig := classify.AnalyzeArchitectureDocSignal("Save", taxonomy.ReturnValue, docs, cfg); sig.Source != "" {
+		t.Errorf("Save: got %+v, want no signal", sig)
+	}
+	sig := classify.AnalyzeArchitectureDocSignal("Delete", taxonomy.ReturnValue, docs, cfg)
+	if sig.Weight != 25 || !strings.Contains(sig.Reasoning, `"Internals"`) {
+		t.Errorf("Delete: got %+v, want weight 25 under Internals", sig)
+	}
+}
+
+// TestClassify_ArchitectureDocSignal verifies that Classify reads the
+// configured architecture documents relative to ModuleRoot.
+func TestClassify_ArchitectureDocSignal(t *testing.T) {
+	allPkgs := loadTestPackages(t)
+	contractsPkg := findPackage(allPkgs, "contracts")
+	if contractsPkg == nil {
+		t.Fatal("contracts package not found")
+	}
+
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "docs", "adr"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "docs", "adr", "0001-api.md"),
+		[]byte("## Contracts\n\n- GetVersion reports the version.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	hasArchDoc := func(cfg *config.GazeConfig) bool {
+		results, err := analysis.Analyze(contractsPkg, analysis.Options{
+			FunctionFilter: "GetVersion",
+		})
+		if err != nil {
+			t.Fatalf("analysis failed: %v", err)
+		}
+		classified := classify.Classify(results, classify.Options{
+			Config:     cfg,
+			TargetPkg:  contractsPkg,
+			ModuleRoot: root,
+		})
+		for _, r := range classified {
+			for _, se := range r.SideEffects {
+				for _, s := range se.Classification.Signals {
+					if s.Source == "architecture_doc" {
+						return true
+					}
+				}
+			}
+		}
+		return false
+	}
+
+	if hasArchDoc(config.DefaultConfig()) {
+		t.Error("expected no architecture_doc signal without configured paths")
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Classification.ArchitectureDocs.Paths = []string{"docs/adr/*.md"}
+	if !hasArchDoc(cfg) {
+		t.Error("expected architecture_doc signal for configured paths")
+	}
+}
@@ -7,21 +7,20 @@ import (
 
 	"golang.org/x/tools/go/packages"
 
+	"github.com/unbound-force/gaze/internal/config"
 	"github.com/unbound-force/gaze/internal/taxonomy"
 )
 
-// maxCallerWeight is the maximum weight for caller dependency
-// signals.
-const maxCallerWeight = 15
-
 // AnalyzeCallerSignal scans TypesInfo.Uses across module packages
 // to find call sites of the target function and computes a weight
 // proportional to the ratio of callers that use/depend on the
-// side effect.
+// side effect. Weights come from the "caller" entry of cfg's weight
+// table (defaults when cfg is nil).
 func AnalyzeCallerSignal(
 	funcObj types.Object,
 	_ taxonomy.SideEffectType,
 	modulePkgs []*packages.Package,
+	cfg *config.GazeConfig,
 ) taxonomy.Signal {
 	if funcObj == nil {
 		return taxonomy.Signal{}
@@ -33,13 +32,9 @@ func AnalyzeCallerSignal(
 	}
 
 	// Weight is proportional to caller count, capped at max.
-	// 1 caller = 5, 2-3 callers = 10, 4+ callers = 15.
-	weight := 5
-	if callerCount >= 4 {
-		weight = maxCallerWeight
-	} else if callerCount >= 2 {
-		weight = 10
-	}
+	// With default weights: 1 caller = 5, 2-3 callers = 10,
+	// 4+ callers = 15.
+	weight := countTierWeight(callerCount, weightFor(cfg, "caller"))
 
 	return taxonomy.Signal{
 		Source: "caller",
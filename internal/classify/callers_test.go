@@ -26,7 +26,7 @@ func TestAnalyzeCallerSignal_SingleCaller(t *testing.T) {
 		t.Fatal("GetData types.Object not found")
 	}
 
-	sig := classify.AnalyzeCallerSignal(obj, taxonomy.ReturnValue, pkgs)
+	sig := classify.AnalyzeCallerSignal(obj, taxonomy.ReturnValue, pkgs, nil)
 
 	if sig.Weight != 5 {
 		t.Errorf("GetData: weight = %d, want 5 (1 caller)", sig.Weight)
@@ -78,7 +78,7 @@ func TestAnalyzeCallerSignal_WeightTiers(t *testing.T) {
 				t.Fatalf("%s types.Object not found", tt.funcName)
 			}
 
-			sig := classify.AnalyzeCallerSignal(obj, taxonomy.ReturnValue, pkgs)
+			sig := classify.AnalyzeCallerSignal(obj, taxonomy.ReturnValue, pkgs, nil)
 
 			if sig.Weight != tt.wantWeight {
 				t.Errorf("weight = %d, want %d", sig.Weight, tt.wantWeight)
@@ -125,7 +125,7 @@ func TestAnalyzeCallerSignal_ZeroCrossPackageCallers(t *testing.T) {
 				t.Fatalf("%s types.Object not found", tt.funcName)
 			}
 
-			sig := classify.AnalyzeCallerSignal(obj, taxonomy.ReturnValue, pkgs)
+			sig := classify.AnalyzeCallerSignal(obj, taxonomy.ReturnValue, pkgs, nil)
 
 			if sig.Weight != 0 {
 				t.Errorf("weight = %d, want 0 (no cross-package callers)", sig.Weight)
@@ -155,7 +155,7 @@ func TestAnalyzeCallerSignal_SamePackageExcluded(t *testing.T) {
 		t.Fatal("ProcessItem types.Object not found")
 	}
 
-	sig := classify.AnalyzeCallerSignal(obj, taxonomy.ReturnValue, pkgs)
+	sig := classify.AnalyzeCallerSignal(obj, taxonomy.ReturnValue, pkgs, nil)
 
 	// ProcessItem has 1 cross-package caller (callers.UseProcessItem).
 	// Same-package callers are excluded, so weight = 5.
@@ -185,7 +185,7 @@ func TestAnalyzeCallerSignal_SamePackageExcluded(t *testing.T) {
 		t.Fatal("debugTrace types.Object not found")
 	}
 
-	debugSig := classify.AnalyzeCallerSignal(debugObj, taxonomy.LogWrite, pkgs)
+	debugSig := classify.AnalyzeCallerSignal(debugObj, taxonomy.LogWrite, pkgs, nil)
 	if debugSig.Weight != 0 {
 		t.Errorf("debugTrace: weight = %d, want 0 (no cross-package callers)",
 			debugSig.Weight)
@@ -197,7 +197,7 @@ func TestAnalyzeCallerSignal_SamePackageExcluded(t *testing.T) {
 func TestAnalyzeCallerSignal_NilFuncObj(t *testing.T) {
 	pkgs := loadTestPackages(t)
 
-	sig := classify.AnalyzeCallerSignal(nil, taxonomy.ReturnValue, pkgs)
+	sig := classify.AnalyzeCallerSignal(nil, taxonomy.ReturnValue, pkgs, nil)
 
 	if sig.Weight != 0 {
 		t.Errorf("nil funcObj: weight = %d, want 0", sig.Weight)
@@ -221,15 +221,14 @@ func TestAnalyzeCallerSignal_EmptyPackageList(t *testing.T) {
 		t.Fatal("GetData types.Object not found")
 	}
 
-	sig := classify.AnalyzeCallerSignal(obj, taxonomy.ReturnValue, nil)
+	sig := classify.AnalyzeCallerSignal(obj, taxonomy.ReturnValue, nil, nil)
 
 	if sig.Weight != 0 {
 		t.Errorf("nil pkgs: weight = %d, want 0", sig.Weight)
 	}
 
 	sig2 := classify.AnalyzeCallerSignal(
-		obj, taxonomy.ReturnValue, []*packages.Package{},
-	)
+		obj, taxonomy.ReturnValue, []*packages.Package{}, nil)
 
 	if sig2.Weight != 0 {
 		t.Errorf("empty pkgs: weight = %d, want 0", sig2.Weight)
@@ -268,7 +267,7 @@ func TestAnalyzeCallerSignal_MethodCallers(t *testing.T) {
 		t.Fatal("Save method not found on *FileStore")
 	}
 
-	sig := classify.AnalyzeCallerSignal(saveObj, taxonomy.ReceiverMutation, pkgs)
+	sig := classify.AnalyzeCallerSignal(saveObj, taxonomy.ReceiverMutation, pkgs, nil)
 
 	// UseStore calls s.Save() via the Store interface, but the
 	// TypesInfo.Uses for that call site references Store.Save, not
@@ -295,8 +294,8 @@ func TestAnalyzeCallerSignal_Determinism(t *testing.T) {
 		t.Fatal("GetData types.Object not found")
 	}
 
-	sig1 := classify.AnalyzeCallerSignal(obj, taxonomy.ReturnValue, pkgs)
-	sig2 := classify.AnalyzeCallerSignal(obj, taxonomy.ReturnValue, pkgs)
+	sig1 := classify.AnalyzeCallerSignal(obj, taxonomy.ReturnValue, pkgs, nil)
+	sig2 := classify.AnalyzeCallerSignal(obj, taxonomy.ReturnValue, pkgs, nil)
 
 	if sig1.Weight != sig2.Weight {
 		t.Errorf("determinism: weights differ: %d vs %d",
@@ -6,25 +6,48 @@ package classify
 import (
 	"go/ast"
 	"go/types"
+	"path/filepath"
+	"slices"
 
 	"golang.org/x/tools/go/packages"
 
 	"github.com/unbound-force/gaze/internal/config"
+	"github.com/unbound-force/gaze/internal/docscan"
 	"github.com/unbound-force/gaze/internal/taxonomy"
 )
 
+// warnNoModule is attached to every result classified without
+// module packages, whose labels then rest on the target-local
+// signals alone.
+const warnNoModule = "classification: module packages not loaded; " +
+	"interface and caller signals were skipped"
+
 // Options configures the classification engine.
 type Options struct {
 	// Config is the Gaze configuration. If nil, defaults are used.
 	Config *config.GazeConfig
 
 	// ModulePackages is the list of all packages in the module,
-	// used for interface satisfaction and caller analysis.
+	// used for interface satisfaction and caller analysis. When
+	// empty, those two signals are skipped, each result carries a
+	// metadata warning, and labels come from the remaining signals.
 	ModulePackages []*packages.Package
 
+	// ModuleTestPackages is the list of module packages loaded
+	// with test files (Tests: true), used for the optional test
+	// caller signal. When empty, the test caller signal is skipped.
+	ModuleTestPackages []*packages.Package
+
 	// TargetPkg is the loaded target package (for AST access).
 	TargetPkg *packages.Package
 
+	// ModuleRoot is the repository root scanned for Markdown
+	// documentation that names the analyzed functions. The scan
+	// honors Config.Classification.DocScan. It is also the base for
+	// Config.Classification.ArchitectureDocs paths. When empty, the
+	// doc and architecture_doc signals are skipped.
+	ModuleRoot string
+
 	// Verbose controls whether signal detail fields (SourceFile,
 	// Excerpt, Reasoning) are populated.
 	Verbose bool
@@ -33,7 +56,10 @@ type Options struct {
 // Classify classifies each side effect in the given analysis
 // results using mechanical signal analyzers. It attaches a
 // Classification to each SideEffect and returns the modified
-// results.
+// results. A configured override matching the function replaces
+// the scored label and confidence. Without ModulePackages the
+// interface and caller signals are skipped rather than scored as
+// absent, and each result records a warning saying so.
 func Classify(results []taxonomy.AnalysisResult, opts Options) []taxonomy.AnalysisResult {
 	if opts.Config == nil {
 		opts.Config = config.DefaultConfig()
@@ -47,6 +73,10 @@ func Classify(results []taxonomy.AnalysisResult, opts Options) []taxonomy.Analys
 	// Pre-compute interfaces once to avoid O(n²) collection.
 	ifaces := collectInterfaces(opts.ModulePackages)
 
+	// Scan documentation once for the doc signal.
+	docs := scanDocs(opts)
+	archDocs := loadArchitectureDocs(opts)
+
 	for i := range results {
 		result := &results[i]
 		funcName := result.Target.Function
@@ -56,6 +86,12 @@ func Classify(results []taxonomy.AnalysisResult, opts Options) []taxonomy.Analys
 		// between methods with the same name on different types.
 		funcObj := lookupFuncObj(funcObjs, result.Target.Receiver, funcName)
 
+		override := matchOverride(opts.Config.Classification.Overrides, result.Target)
+
+		if len(opts.ModulePackages) == 0 && !slices.Contains(result.Metadata.Warnings, warnNoModule) {
+			result.Metadata.Warnings = append(result.Metadata.Warnings, warnNoModule)
+		}
+
 		// Determine receiver type if this is a method.
 		var receiverType types.Type
 		if funcObj != nil {
@@ -82,10 +118,13 @@ func Classify(results []taxonomy.AnalysisResult, opts Options) []taxonomy.Analys
 			signals := classifySideEffect(
 				funcName, funcDecl, funcObj,
 				receiverType, se.Type,
-				namingName, ifaces, opts,
+				namingName, ifaces, docs, archDocs, opts,
 			)
 
 			classification := ComputeScore(se.Type, signals, opts.Config)
+			if override != nil {
+				applyOverride(&classification, override)
+			}
 
 			// Strip detail fields if not verbose.
 			if !opts.Verbose {
@@ -103,12 +142,14 @@ func Classify(results []taxonomy.AnalysisResult, opts Options) []taxonomy.Analys
 	return results
 }
 
-// classifySideEffect runs all five mechanical signal analyzers
+// classifySideEffect runs all mechanical signal analyzers
 // for a single side effect and returns the collected signals.
 // ifaces is the pre-computed interface list from collectInterfaces.
 // namingName is the name used for naming-convention analysis; for
 // sentinel errors it is the variable name (se.Target) rather than
-// the enclosing funcName ("<package>").
+// the enclosing funcName ("<package>"). docs is the pre-scanned
+// documentation list from scanDocs, and archDocs the architecture
+// documents from loadArchitectureDocs.
 func classifySideEffect(
 	funcName string,
 	funcDecl *ast.FuncDecl,
@@ -117,38 +158,110 @@ func classifySideEffect(
 	effectType taxonomy.SideEffectType,
 	namingName string,
 	ifaces []namedInterface,
+	docs []docscan.DocumentFile,
+	archDocs []docscan.DocumentFile,
 	opts Options,
 ) []taxonomy.Signal {
 	var signals []taxonomy.Signal
+	moduleLoaded := len(opts.ModulePackages) > 0
 
-	// 1. Interface satisfaction.
-	if s := analyzeInterfaceSignal(funcName, receiverType, effectType, ifaces); s.Source != "" {
-		signals = append(signals, s)
+	// 1. Interface satisfaction (only when module packages were
+	// loaded).
+	if moduleLoaded {
+		if s := analyzeInterfaceSignal(funcName, receiverType, effectType, ifaces, opts.Config); s.Source != "" {
+			signals = append(signals, s)
+		}
 	}
 
 	// 2. API surface visibility.
-	if s := AnalyzeVisibilitySignal(funcDecl, funcObj, effectType); s.Source != "" {
+	if s := AnalyzeVisibilitySignal(funcDecl, funcObj, effectType, opts.Config); s.Source != "" {
 		signals = append(signals, s)
 	}
 
-	// 3. Caller dependency.
-	if s := AnalyzeCallerSignal(funcObj, effectType, opts.ModulePackages); s.Source != "" {
-		signals = append(signals, s)
+	// 3. Caller dependency (only when module packages were loaded).
+	if moduleLoaded {
+		if s := AnalyzeCallerSignal(funcObj, effectType, opts.ModulePackages, opts.Config); s.Source != "" {
+			signals = append(signals, s)
+		}
 	}
 
 	// 4. Naming convention (use namingName to handle sentinel vars).
-	if s := AnalyzeNamingSignal(namingName, effectType); s.Source != "" {
+	if s := AnalyzeNamingSignal(namingName, effectType, opts.Config); s.Source != "" {
 		signals = append(signals, s)
 	}
 
 	// 5. Godoc comment.
-	if s := AnalyzeGodocSignal(funcDecl, effectType); s.Source != "" {
+	if s := AnalyzeGodocSignal(funcDecl, effectType, opts.Config); s.Source != "" {
+		signals = append(signals, s)
+	}
+
+	// 6. Test callers (only when test packages were loaded).
+	if s := AnalyzeTestCallerSignal(funcObj, effectType, opts.ModuleTestPackages, opts.Config); s.Source != "" {
+		signals = append(signals, s)
+	}
+
+	// 7. Repository documentation (only when a module root is set).
+	if s := AnalyzeDocSignal(namingName, effectType, docs, opts.Config); s.Source != "" {
+		signals = append(signals, s)
+	}
+
+	// 8. Architecture documents (only when paths are configured).
+	if s := AnalyzeArchitectureDocSignal(namingName, effectType, archDocs, opts.Config); s.Source != "" {
 		signals = append(signals, s)
 	}
 
 	return signals
 }
 
+// scanDocs scans opts.ModuleRoot for Markdown documentation using
+// the configured DocScan filters. A scan failure (including a
+// timeout) yields no documents so that classification degrades to
+// the remaining signals instead of failing.
+func scanDocs(opts Options) []docscan.DocumentFile {
+	if opts.ModuleRoot == "" {
+		return nil
+	}
+	docs, err := docscan.Scan(opts.ModuleRoot, docscan.ScanOptions{
+		Config:     opts.Config,
+		PackageDir: packageDir(opts.TargetPkg),
+	})
+	if err != nil {
+		return nil
+	}
+	return docs
+}
+
+// packageDir returns the directory containing the target package's
+// source files, or "" if it cannot be determined.
+func packageDir(pkg *packages.Package) string {
+	if pkg == nil || len(pkg.GoFiles) == 0 {
+		return ""
+	}
+	return filepath.Dir(pkg.GoFiles[0])
+}
+
+// weightFor resolves the configured weight for a signal source,
+// falling back to the built-in defaults when cfg is nil.
+func weightFor(cfg *config.GazeConfig, source string) config.SignalWeight {
+	if cfg == nil {
+		return config.ClassificationConfig{}.Weight(source)
+	}
+	return cfg.Classification.Weight(source)
+}
+
+// countTierWeight maps an evidence count onto the tiered weight
+// scale shared by the caller analyzers: 1 = Base, 2-3 = 2×Base,
+// 4+ = 3×Base, capped at Max.
+func countTierWeight(count int, w config.SignalWeight) int {
+	units := 1
+	if count >= 4 {
+		units = 3
+	} else if count >= 2 {
+		units = 2
+	}
+	return min(units*w.Base, w.Max)
+}
+
 // buildFuncDeclMap creates a lookup from function/method name to
 // its AST declaration in the given package.
 func buildFuncDeclMap(pkg *packages.Package) map[string]*ast.FuncDecl {
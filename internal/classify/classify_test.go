@@ -95,11 +95,21 @@ func TestNamingSignal_ContractualPrefixes(t *testing.T) {
 		{"New_ExactMatch", "New", taxonomy.ReturnValue, 10},
 		{"New_ErrorReturn", "NewStore", taxonomy.ErrorReturn, 10},
 		{"New_NoMatchMutation", "NewClient", taxonomy.ReceiverMutation, 0},
+		// Make/MakeXxx constructor prefix — same implied effects as New.
+		{"Make_ReturnValue", "MakeBuffer", taxonomy.ReturnValue, 10},
+		{"Make_ErrorReturn", "MakeConn", taxonomy.ErrorReturn, 10},
+		{"Make_NoMatchPanic", "MakeBuffer", taxonomy.Panic, 0},
+		// Must/MustXxx helpers — the panic is the contract, with the
+		// stronger naming_must weight.
+		{"Must_Panic", "MustParse", taxonomy.Panic, 25},
+		{"Must_ExactMatch", "Must", taxonomy.Panic, 25},
+		{"Must_NoMatchReturn", "MustParse", taxonomy.ReturnValue, 0},
+		{"Must_Unexported", "mustParse", taxonomy.Panic, 0},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := classify.AnalyzeNamingSignal(tt.funcName, tt.effectType)
+			s := classify.AnalyzeNamingSignal(tt.funcName, tt.effectType, nil)
 			if s.Weight != tt.wantWeight {
 				t.Errorf("AnalyzeNamingSignal(%q, %s) weight = %d, want %d",
 					tt.funcName, tt.effectType, s.Weight, tt.wantWeight)
@@ -124,7 +134,7 @@ func TestNamingSignal_IncidentalPrefixes(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.funcName, func(t *testing.T) {
-			s := classify.AnalyzeNamingSignal(tt.funcName, taxonomy.ReturnValue)
+			s := classify.AnalyzeNamingSignal(tt.funcName, taxonomy.ReturnValue, nil)
 			if s.Weight >= 0 {
 				t.Errorf("AnalyzeNamingSignal(%q) weight = %d, want negative",
 					tt.funcName, s.Weight)
@@ -133,10 +143,75 @@ func TestNamingSignal_IncidentalPrefixes(t *testing.T) {
 	}
 }
 
+// TestNamingSignal_SentinelNames tests the sentinel naming rules
+// for Err* variables and exported *Error types.
+func TestNamingSignal_SentinelNames(t *testing.T) {
+	tests := []struct {
+		name       string
+		effectType taxonomy.SideEffectType
+		wantWeight int
+	}{
+		{"ErrNotFound", taxonomy.SentinelError, 30},
+		{"NotFoundError", taxonomy.SentinelError, 30},
+		{"notFoundError", taxonomy.SentinelError, 0},
+		{"NotFoundError", taxonomy.ReturnValue, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"/"+string(tt.effectType), func(t *testing.T) {
+			s := classify.AnalyzeNamingSignal(tt.name, tt.effectType, nil)
+			if s.Weight != tt.wantWeight {
+				t.Errorf("AnalyzeNamingSignal(%q, %s) weight = %d, want %d",
+					tt.name, tt.effectType, s.Weight, tt.wantWeight)
+			}
+		})
+	}
+}
+
+// TestNamingSignal_ConfiguredPrefixes verifies that the naming
+// signal consults the prefix lists from the config.
+func TestNamingSignal_ConfiguredPrefixes(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Classification.Naming = config.Naming{
+		ContractualPrefixes: []string{"Get", "must", "Must", "ensure"},
+		IncidentalPrefixes:  []string{"audit", "emit"},
+	}
+
+	tests := []struct {
+		funcName   string
+		effectType taxonomy.SideEffectType
+		want       int
+	}{
+		{"auditLogin", taxonomy.ReturnValue, -10},
+		{"emitMetric", taxonomy.ChannelSend, -10},
+		{"mustParse", taxonomy.ErrorReturn, 10},
+		{"ensureDir", taxonomy.ReceiverMutation, 10},
+		// A configured Must prefix applies to every effect type, but
+		// panics keep the stronger naming_must weight.
+		{"MustParse", taxonomy.ReturnValue, 10},
+		{"MustParse", taxonomy.Panic, 25},
+		// Built-in prefixes keep their implied effect types.
+		{"GetData", taxonomy.ReturnValue, 10},
+		{"GetData", taxonomy.ErrorReturn, 0},
+		// Prefixes absent from the configured lists are ignored.
+		{"logError", taxonomy.LogWrite, 0},
+		{"FetchUser", taxonomy.ReturnValue, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.funcName+"/"+string(tt.effectType), func(t *testing.T) {
+			s := classify.AnalyzeNamingSignal(tt.funcName, tt.effectType, cfg)
+			if s.Weight != tt.want {
+				t.Errorf("AnalyzeNamingSignal(%q, %s) weight = %d, want %d",
+					tt.funcName, tt.effectType, s.Weight, tt.want)
+			}
+		})
+	}
+}
+
 // TestNamingSignal_NoMatch tests that unknown names produce zero
 // signal.
 func TestNamingSignal_NoMatch(t *testing.T) {
-	s := classify.AnalyzeNamingSignal("computeHash", taxonomy.ReturnValue)
+	s := classify.AnalyzeNamingSignal("computeHash", taxonomy.ReturnValue, nil)
 	if s.Source != "" {
 		t.Errorf("expected zero signal for %q, got source=%q weight=%d",
 			"computeHash", s.Source, s.Weight)
@@ -234,7 +309,7 @@ func TestScoreComputation_Incidental(t *testing.T) {
 }
 
 // TestScoreComputation_Contradiction tests that contradicting
-// signals apply a penalty.
+// signals apply a penalty that scales with the weaker side.
 func TestScoreComputation_Contradiction(t *testing.T) {
 	signals := []taxonomy.Signal{
 		{Source: "interface", Weight: 30},
@@ -242,15 +317,63 @@ func TestScoreComputation_Contradiction(t *testing.T) {
 	}
 
 	c := classify.ComputeScore("", signals, nil)
-	// 50 + 30 - 10 - 20 (contradiction) = 50.
-	if c.Confidence != 50 {
-		t.Errorf("contradiction: confidence = %d, want 50", c.Confidence)
+	// 50 + 30 - 10 - 10 (contradiction: 1 × weaker side 10) = 60.
+	if c.Confidence != 60 {
+		t.Errorf("contradiction: confidence = %d, want 60", c.Confidence)
 	}
 	if c.Label != taxonomy.Ambiguous {
 		t.Errorf("contradiction: label = %q, want %q", c.Label, taxonomy.Ambiguous)
 	}
 }
 
+// TestScoreComputation_ContradictionCurve checks the penalty for
+// opposing sides of different strengths and factors.
+func TestScoreComputation_ContradictionCurve(t *testing.T) {
+	cases := []struct {
+		name        string
+		factor      float64
+		signals     []taxonomy.Signal
+		wantPenalty int
+	}{
+		{"weak negative", 0, []taxonomy.Signal{{Source: "interface", Weight: 30}, {Source: "naming", Weight: -10}}, 10},
+		{"weak positive", 0, []taxonomy.Signal{{Source: "naming", Weight: 10}, {Source: "godoc", Weight: -15}}, 10},
+		{"sides summed", 0, []taxonomy.Signal{{Source: "interface", Weight: 30}, {Source: "naming", Weight: -10}, {Source: "godoc", Weight: -5}}, 15},
+		{"strong sides capped", 0, []taxonomy.Signal{{Source: "interface", Weight: 30}, {Source: "godoc", Weight: -30}}, 20},
+		{"half factor", 0.5, []taxonomy.Signal{{Source: "interface", Weight: 30}, {Source: "naming", Weight: -10}}, 5},
+		{"rounded half up", 0.25, []taxonomy.Signal{{Source: "interface", Weight: 30}, {Source: "naming", Weight: -10}}, 3},
+		{"rounds to zero", 0.01, []taxonomy.Signal{{Source: "interface", Weight: 30}, {Source: "naming", Weight: -10}}, 0},
+		{"large factor capped", 3, []taxonomy.Signal{{Source: "interface", Weight: 30}, {Source: "naming", Weight: -10}}, 20},
+		{"one side only", 0, []taxonomy.Signal{{Source: "interface", Weight: 30}, {Source: "visibility", Weight: 8}}, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			if tc.factor != 0 {
+				cfg.Classification.ContradictionFactor = tc.factor
+			}
+			c := classify.ComputeScore(taxonomy.LogWrite, tc.signals, cfg)
+
+			sum := 0
+			for _, s := range tc.signals {
+				sum += s.Weight
+			}
+			if want := 50 + sum - tc.wantPenalty; c.Confidence != want {
+				t.Errorf("confidence = %d, want %d", c.Confidence, want)
+			}
+
+			var got int
+			for _, s := range c.Signals {
+				if s.Source == "contradiction" {
+					got = -s.Weight
+				}
+			}
+			if got != tc.wantPenalty {
+				t.Errorf("contradiction penalty = %d, want %d", got, tc.wantPenalty)
+			}
+		})
+	}
+}
+
 // TestScoreComputation_ClampToZero tests that very negative scores
 // clamp to 0.
 func TestScoreComputation_ClampToZero(t *testing.T) {
@@ -346,6 +469,98 @@ func TestScoreComputation_ReasoningContainsThreshold(t *testing.T) {
 	}
 }
 
+// TestAnalyzers_ConfiguredWeights verifies that signal analyzers
+// read their weights from the config weight table rather than
+// fixed constants.
+func TestAnalyzers_ConfiguredWeights(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Classification.Weights["naming"] = config.SignalWeight{Base: 20, Max: 20}
+	cfg.Classification.Weights["godoc"] = config.SignalWeight{Base: 7, Max: 7}
+
+	if s := classify.AnalyzeNamingSignal("GetData", taxonomy.ReturnValue, cfg); s.Weight != 20 {
+		t.Errorf("naming contractual weight = %d, want 20", s.Weight)
+	}
+	if s := classify.AnalyzeNamingSignal("logError", taxonomy.LogWrite, cfg); s.Weight != -20 {
+		t.Errorf("naming incidental weight = %d, want -20", s.Weight)
+	}
+
+	fd := makeFuncDeclWithDoc("GetVersion", "GetVersion returns the version.")
+	if s := classify.AnalyzeGodocSignal(fd, taxonomy.ReturnValue, cfg); s.Weight != 7 {
+		t.Errorf("godoc weight = %d, want 7", s.Weight)
+	}
+
+	// Invalid entries fall back to the default weight.
+	cfg.Classification.Weights["naming"] = config.SignalWeight{Base: 40, Max: 5}
+	if s := classify.AnalyzeNamingSignal("GetData", taxonomy.ReturnValue, cfg); s.Weight != 10 {
+		t.Errorf("invalid naming entry weight = %d, want default 10", s.Weight)
+	}
+}
+
+// TestScoreComputation_ReasoningMargin verifies that the Reasoning
+// reports the distance to the nearest threshold and names the
+// dominant signal sources in a deterministic order.
+func TestScoreComputation_ReasoningMargin(t *testing.T) {
+	tests := []struct {
+		name    string
+		signals []taxonomy.Signal
+		want    []string
+	}{
+		{
+			// 50 + 28 = 78: 2 below 80, 28 above 50.
+			name:    "ambiguous near contractual",
+			signals: []taxonomy.Signal{{Source: "interface", Weight: 28}},
+			want:    []string{"confidence 78", "2 below contractual threshold 80", "interface (+28)"},
+		},
+		{
+			// 50 + 3 = 53: 3 above 50, 27 below 80.
+			name:    "ambiguous near incidental",
+			signals: []taxonomy.Signal{{Source: "naming", Weight: 3}},
+			want:    []string{"3 above incidental threshold 50"},
+		},
+		{
+			// 50 + 30 + 10 = 90.
+			name: "contractual",
+			signals: []taxonomy.Signal{
+				{Source: "visibility", Weight: 10},
+				{Source: "interface", Weight: 30},
+			},
+			want: []string{"10 above", "dominant signals: interface (+30), visibility (+10)"},
+		},
+		{
+			// 50 - 10 = 40.
+			name:    "incidental",
+			signals: []taxonomy.Signal{{Source: "naming", Weight: -10}},
+			want:    []string{"10 below", "naming (-10)"},
+		},
+		{
+			// Equal magnitudes sort by source name; only three listed.
+			name: "ties and cap",
+			signals: []taxonomy.Signal{
+				{Source: "naming", Weight: 5},
+				{Source: "caller", Weight: 5},
+				{Source: "godoc", Weight: 5},
+				{Source: "visibility", Weight: 5},
+			},
+			want: []string{"dominant signals: caller (+5), godoc (+5), naming (+5)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := classify.ComputeScore("", tt.signals, nil)
+			for _, w := range tt.want {
+				if !strings.Contains(c.Reasoning, w) {
+					t.Errorf("Reasoning %q missing %q", c.Reasoning, w)
+				}
+			}
+			again := classify.ComputeScore("", tt.signals, nil)
+			if again.Reasoning != c.Reasoning {
+				t.Errorf("Reasoning not deterministic: %q vs %q", c.Reasoning, again.Reasoning)
+			}
+		})
+	}
+}
+
 // TestScoreComputation_SignalsInResult verifies that the input
 // signals are returned in the Classification.Signals field.
 func TestScoreComputation_SignalsInResult(t *testing.T) {
@@ -390,8 +605,8 @@ func TestScoreComputation_ContradictionSignalAdded(t *testing.T) {
 	for _, s := range c.Signals {
 		if s.Source == "contradiction" {
 			contradictionFound = true
-			if s.Weight != -20 {
-				t.Errorf("contradiction signal weight = %d, want -20", s.Weight)
+			if s.Weight != -10 {
+				t.Errorf("contradiction signal weight = %d, want -10", s.Weight)
 			}
 			if s.Reasoning == "" {
 				t.Error("contradiction signal: expected non-empty Reasoning")
@@ -506,6 +721,140 @@ func TestClassify_ContractsPackage(t *testing.T) {
 	}
 }
 
+// TestClassify_WithoutModulePackages verifies that classification
+// without module packages skips the interface and caller signals,
+// records a single metadata warning per result, and still labels
+// every effect from the remaining signals.
+func TestClassify_WithoutModulePackages(t *testing.T) {
+	allPkgs := loadTestPackages(t)
+	contractsPkg := findPackage(allPkgs, "contracts")
+	if contractsPkg == nil {
+		t.Fatal("contracts package not found")
+	}
+
+	results, err := analysis.Analyze(contractsPkg, analysis.Options{})
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+
+	classifyOpts := classify.Options{
+		Config:    config.DefaultConfig(),
+		TargetPkg: contractsPkg,
+		Verbose:   true,
+	}
+	classified := classify.Classify(results, classifyOpts)
+	// A second pass must not repeat the warning.
+	classified = classify.Classify(classified, classifyOpts)
+
+	contractual := 0
+	for _, result := range classified {
+		warnings := 0
+		for _, w := range result.Metadata.Warnings {
+			if strings.Contains(w, "interface and caller signals were skipped") {
+				warnings++
+			}
+		}
+		if warnings != 1 {
+			t.Errorf("%s: got %d module warning(s), want 1: %v",
+				result.Target.Function, warnings, result.Metadata.Warnings)
+		}
+		for _, se := range result.SideEffects {
+			if se.Classification == nil {
+				t.Errorf("%s, effect %s: no classification", result.Target.Function, se.Type)
+				continue
+			}
+			if se.Classification.Label == taxonomy.Contractual {
+				contractual++
+			}
+			for _, sig := range se.Classification.Signals {
+				if sig.Source == "interface" || sig.Source == "caller" {
+					t.Errorf("%s, effect %s: unexpected %s signal without module packages",
+						result.Target.Function, se.Type, sig.Source)
+				}
+			}
+		}
+	}
+	if contractual == 0 {
+		t.Error("expected some contractual labels from naming, visibility, and godoc")
+	}
+}
+
+// TestClassify_Overrides verifies that a configured override
+// replaces the scored label and confidence of every effect of the
+// matching functions, records an "override" signal, and leaves
+// other functions alone.
+func TestClassify_Overrides(t *testing.T) {
+	allPkgs := loadTestPackages(t)
+	contractsPkg := findPackage(allPkgs, "contracts")
+	if contractsPkg == nil {
+		t.Fatal("contracts package not found")
+	}
+	results, err := analysis.Analyze(contractsPkg, analysis.Options{})
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Classification.Overrides = []config.Override{
+		{Function: "FileStore.Save", Label: "incidental", Confidence: 5},
+		{Function: "(*MemoryCache).*", Label: "ambiguous", Confidence: 60},
+		{Function: "Fetch*", Label: "contractual", Confidence: 99},
+		{Function: "(*MemoryCache).Load", Label: "contractual", Confidence: 90}, // shadowed by the entry above
+	}
+	classified := classify.Classify(results, classify.Options{
+		Config:         cfg,
+		ModulePackages: allPkgs,
+		TargetPkg:      contractsPkg,
+		Verbose:        true,
+	})
+
+	want := map[string]struct {
+		label      taxonomy.ClassificationLabel
+		confidence int
+	}{
+		"(*FileStore).Save":      {taxonomy.Incidental, 5},
+		"(*MemoryCache).Load":    {taxonomy.Ambiguous, 60},
+		"(*MemoryCache).Update":  {taxonomy.Ambiguous, 60},
+		"(*RemoteFetcher).Fetch": {taxonomy.Contractual, 99},
+		"FetchConfig":            {taxonomy.Contractual, 99},
+	}
+	seen := make(map[string]bool)
+	for _, result := range classified {
+		name := result.Target.QualifiedName()
+		w, overridden := want[name]
+		for _, se := range result.SideEffects {
+			c := se.Classification
+			if c == nil {
+				t.Fatalf("%s %s: no classification", name, se.Type)
+			}
+			var signal *taxonomy.Signal
+			for k := range c.Signals {
+				if c.Signals[k].Source == "override" {
+					signal = &c.Signals[k]
+				}
+			}
+			if !overridden {
+				if signal != nil {
+					t.Errorf("%s %s: unexpected override signal", name, se.Type)
+				}
+				continue
+			}
+			seen[name] = true
+			if c.Label != w.label || c.Confidence != w.confidence {
+				t.Errorf("%s %s: got %s/%d, want %s/%d", name, se.Type, c.Label, c.Confidence, w.label, w.confidence)
+			}
+			if signal == nil || signal.Reasoning == "" {
+				t.Errorf("%s %s: missing override signal with reasoning: %+v", name, se.Type, c.Signals)
+			}
+		}
+	}
+	for name := range want {
+		if !seen[name] {
+			t.Errorf("no side effects classified for %s", name)
+		}
+	}
+}
+
 // TestClassify_IncidentalPackage tests that incidental effects
 // are classified with low confidence.
 func TestClassify_IncidentalPackage(t *testing.T) {
@@ -954,3 +1303,113 @@ func TestClassify_Determinism(t *testing.T) {
 		}
 	}
 }
+
+// TestClassify_EmbeddedInterfaceSignal verifies that a method
+// satisfying an interface embedded in the receiver struct (here
+// io.Closer, which is outside the module) receives the interface
+// signal.
+func TestClassify_EmbeddedInterfaceSignal(t *testing.T) {
+	allPkgs := loadTestPackages(t)
+	contractsPkg := findPackage(allPkgs, "contracts")
+	if contractsPkg == nil {
+		t.Fatal("contracts package not found")
+	}
+
+	results, err := analysis.Analyze(contractsPkg, analysis.Options{FunctionFilter: "Close"})
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+
+	classified := classify.Classify(results, classify.Options{
+		Config:         config.DefaultConfig(),
+		ModulePackages: allPkgs,
+		TargetPkg:      contractsPkg,
+		Verbose:        true,
+	})
+
+	found := false
+	for _, r := range classified {
+		if r.Target.Receiver != "*TrackedCloser" {
+			continue
+		}
+		for _, se := range r.SideEffects {
+			for _, s := range se.Classification.Signals {
+				if s.Source != "interface" {
+					continue
+				}
+				found = true
+				if s.Weight != 30 {
+					t.Errorf("interface weight = %d, want 30", s.Weight)
+				}
+				if !strings.Contains(s.Reasoning, "io.Closer.Close (io)") {
+					t.Errorf("reasoning %q should name io.Closer.Close and its package", s.Reasoning)
+				}
+				if s.Excerpt != "io.Closer.Close() error" {
+					t.Errorf("excerpt = %q, want the interface method signature", s.Excerpt)
+				}
+			}
+		}
+	}
+	if !found {
+		t.Error("expected interface signal for TrackedCloser.Close via embedded io.Closer")
+	}
+
+	// ForcedCloser.Close(bool) shadows the embedded io.Closer.Close
+	// with a different signature, so it earns no interface signal.
+	for _, r := range classified {
+		if r.Target.Receiver != "*ForcedCloser" {
+			continue
+		}
+		for _, se := range r.SideEffects {
+			for _, s := range se.Classification.Signals {
+				if s.Source == "interface" {
+					t.Errorf("unexpected interface signal for ForcedCloser.Close: %s", s.Reasoning)
+				}
+			}
+		}
+	}
+}
+
+// TestClassify_InterfaceSignalNamesInterface verifies that the
+// interface signal names the satisfied interface method and its
+// package in the reasoning, and its signature in the excerpt.
+func TestClassify_InterfaceSignalNamesInterface(t *testing.T) {
+	allPkgs := loadTestPackages(t)
+	contractsPkg := findPackage(allPkgs, "contracts")
+	if contractsPkg == nil {
+		t.Fatal("contracts package not found")
+	}
+
+	results, err := analysis.Analyze(contractsPkg, analysis.Options{FunctionFilter: "FileStore.Save"})
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+	classified := classify.Classify(results, classify.Options{
+		Config:         config.DefaultConfig(),
+		ModulePackages: allPkgs,
+		TargetPkg:      contractsPkg,
+		Verbose:        true,
+	})
+	if len(classified) != 1 || len(classified[0].SideEffects) == 0 {
+		t.Fatalf("expected side effects for (*FileStore).Save, got %+v", classified)
+	}
+
+	wantReason := "method Save satisfies contracts.Store.Save (" + contractsPkg.PkgPath + ")"
+	for _, se := range classified[0].SideEffects {
+		var sig *taxonomy.Signal
+		for k := range se.Classification.Signals {
+			if se.Classification.Signals[k].Source == "interface" {
+				sig = &se.Classification.Signals[k]
+			}
+		}
+		if sig == nil {
+			t.Fatalf("%s: no interface signal", se.Type)
+		}
+		if sig.Reasoning != wantReason {
+			t.Errorf("%s: reasoning = %q, want %q", se.Type, sig.Reasoning, wantReason)
+		}
+		if sig.Excerpt != "contracts.Store.Save(data []byte) error" {
+			t.Errorf("%s: excerpt = %q", se.Type, sig.Excerpt)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Package classify implements the contractual classification engine.
+package classify
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/unbound-force/gaze/internal/config"
+	"github.com/unbound-force/gaze/internal/docscan"
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// maxExcerptLen bounds the length of the excerpt attached to a doc
+// signal so verbose output stays readable.
+const maxExcerptLen = 200
+
+// AnalyzeDocSignal searches the given Markdown documents for a
+// whole-word mention of funcName and returns a positive "readme"
+// signal for the first document (in priority order) that names it.
+// A single-word name such as Close or Load counts only when the
+// mention is qualified or in code (see findDocMention).
+// The documents are expected to come from docscan.Scan, which
+// honors the configured DocScan include/exclude globs and timeout.
+// The weight is the "readme" entry of cfg's weight table; a function
+// named in the README is strong contractual evidence.
+func AnalyzeDocSignal(
+	funcName string,
+	_ taxonomy.SideEffectType,
+	docs []docscan.DocumentFile,
+	cfg *config.GazeConfig,
+) taxonomy.Signal {
+	if funcName == "" || funcName == "<package>" || len(docs) == 0 {
+		return taxonomy.Signal{}
+	}
+
+	for _, doc := range docs {
+		line, ok := findDocMention(doc.Content, funcName)
+		if !ok {
+			continue
+		}
+		w := weightFor(cfg, "readme")
+		return taxonomy.Signal{
+			Source:     "readme",
+			Weight:     min(w.Base, w.Max),
+			SourceFile: doc.Path,
+			Excerpt:    truncateExcerpt(line),
+			Reasoning:  "function \"" + funcName + "\" is named in " + doc.Path,
+		}
+	}
+
+	return taxonomy.Signal{}
+}
+
+// findIdentMention returns the trimmed line containing the first
+// occurrence of name that is not part of a longer identifier, and
+// whether such an occurrence exists. Matching is case-sensitive
+// because Go identifiers are.
+func findIdentMention(content, name string) (string, bool) {
+	offset := 0
+	for {
+		idx := strings.Index(content[offset:], name)
+		if idx < 0 {
+			return "", false
+		}
+		start := offset + idx
+		end := start + len(name)
+		if isIdentBoundary(content, start, end) {
+			lineStart := strings.LastIndexByte(content[:start], '\n') + 1
+			lineEnd := strings.IndexByte(content[end:], '\n')
+			if lineEnd < 0 {
+				lineEnd = len(content)
+			} else {
+				lineEnd += end
+			}
+			return strings.TrimSpace(content[lineStart:lineEnd]), true
+		}
+		offset = end
+	}
+}
+
+// findDocMention is like findIdentMention, but when name is a
+// single word (see isSingleWord) it accepts only mentions that are
+// qualified (Store.Close, pkg.Load) or inside code: a code span or a
+// fenced block. Prose uses words such as Close and String on their
+// own, which says nothing about the function.
+func findDocMention(content, name string) (string, bool) {
+	if !isSingleWord(name) {
+		return findIdentMention(content, name)
+	}
+	inFence := false
+	for _, raw := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(raw)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if lineMentions(raw, name, inFence) {
+			return trimmed, true
+		}
+	}
+	return "", false
+}
+
+// lineMentions reports whether line mentions name the way
+// findDocMention requires: anywhere as a whole identifier, or for a
+// single-word name only where qualified or inside code. inFence
+// reports whether line is inside a fenced code block.
+func lineMentions(line, name string, inFence bool) bool {
+	qualify := isSingleWord(name)
+	offset := 0
+	for {
+		idx := strings.Index(line[offset:], name)
+		if idx < 0 {
+			return false
+		}
+		start := offset + idx
+		end := start + len(name)
+		if isIdentBoundary(line, start, end) &&
+			(!qualify || inFence || isQualified(line, start) || inCodeSpan(line, start)) {
+			return true
+		}
+		offset = end
+	}
+}
+
+// isSingleWord reports whether name is one word, with no inner
+// capitals, digits, or underscores: Close or load, but not
+// GetVersion, ParseURL, or Read2.
+func isSingleWord(name string) bool {
+	for i, r := range name {
+		if (i > 0 && unicode.IsUpper(r)) || r == '_' || unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isQualified reports whether the identifier at line[start:] is
+// preceded by a selector, as in Store.Close or config.Load.
+func isQualified(line string, start int) bool {
+	if start < 2 || line[start-1] != '.' {
+		return false
+	}
+	r, _ := utf8.DecodeLastRuneInString(line[:start-1])
+	return isIdentRune(r)
+}
+
+// inCodeSpan reports whether line[start:] falls inside a Markdown
+// code span, i.e. after an odd number of backticks on the line.
+func inCodeSpan(line string, start int) bool {
+	return strings.Count(line[:start], "`")%2 == 1
+}
+
+// isIdentBoundary reports whether content[start:end] is delimited
+// on both sides by non-identifier characters.
+func isIdentBoundary(content string, start, end int) bool {
+	if start > 0 {
+		r, _ := utf8.DecodeLastRuneInString(content[:start])
+		if isIdentRune(r) {
+			return false
+		}
+	}
+	if end < len(content) {
+		r, _ := utf8.DecodeRuneInString(content[end:])
+		if isIdentRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isIdentRune reports whether r may appear in a Go identifier.
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// truncateExcerpt shortens s to maxExcerptLen bytes on a rune
+// boundary, appending an ellipsis when truncated.
+func truncateExcerpt(s string) string {
+	if len(s) <= maxExcerptLen {
+		return s
+	}
+	cut := maxExcerptLen
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "..."
+}
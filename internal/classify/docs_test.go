@@ -0,0 +1,173 @@
+package classify_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/unbound-force/gaze/internal/analysis"
+	"github.com/unbound-force/gaze/internal/classify"
+	"github.com/unbound-force/gaze/internal/config"
+	"github.com/unbound-force/gaze/internal/docscan"
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// TestAnalyzeDocSignal_WholeWordMatch verifies that only whole-word
+// mentions of the function name produce a readme signal.
+func TestAnalyzeDocSignal_WholeWordMatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		wantWeight int
+	}{
+		{"plain mention", "Call GetVersion to read the version.", 15},
+		{"code span", "Use `GetVersion()` in scripts.", 15},
+		{"qualified", "See contracts.GetVersion for details.", 15},
+		{"prefix of longer ident", "GetVersionString is separate.", 0},
+		{"suffix of longer ident", "MustGetVersion panics.", 0},
+		{"different case", "getversion is not the same.", 0},
+		{"absent", "Nothing relevant here.", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs := []docscan.DocumentFile{{Path: "README.md", Content: tt.content}}
+			sig := classify.AnalyzeDocSignal("GetVersion", taxonomy.ReturnValue, docs, nil)
+			if sig.Weight != tt.wantWeight {
+				t.Errorf("weight = %d, want %d", sig.Weight, tt.wantWeight)
+			}
+			if tt.wantWeight != 0 && sig.Source != "readme" {
+				t.Errorf("source = %q, want %q", sig.Source, "readme")
+			}
+		})
+	}
+}
+
+// TestAnalyzeDocSignal_SingleWordNeedsQualifier verifies that a
+// single-word name counts only when qualified or in code, since
+// prose uses words like Close on their own.
+func TestAnalyzeDocSignal_SingleWordNeedsQualifier(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		wantWeight int
+	}{
+		{"bare prose", "Close the file when done.", 0},
+		{"code span", "Call `Close` when done.", 15},
+		{"code span with call", "Always `defer f.Close()`.", 15},
+		{"type qualified", "Store.Close releases the handle.", 15},
+		{"package qualified", "Use store.Close to release it.", 15},
+		{"fenced block", "Example:\n\n```go\ndefer Close()\n```\n", 15},
+		{"after fenced block", "```\nx\n```\nClose the file.", 0},
+		{"sentence dot", "Done. Close the file.", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs := []docscan.DocumentFile{{Path: "README.md", Content: tt.content}}
+			sig := classify.AnalyzeDocSignal("Close", taxonomy.ReturnValue, docs, nil)
+			if sig.Weight != tt.wantWeight {
+				t.Errorf("weight = %d, want %d", sig.Weight, tt.wantWeight)
+			}
+		})
+	}
+}
+
+// TestAnalyzeDocSignal_FirstDocWins verifies that the signal cites
+// the first matching document and carries the matching line as
+// its excerpt.
+func TestAnalyzeDocSignal_FirstDocWins(t *testing.T) {
+	docs := []docscan.DocumentFile{
+		{Path: "pkg/README.md", Content: "# pkg\n\nNo mention."},
+		{Path: "README.md", Content: "# Project\n\n- `Save` persists a record.\n- other"},
+		{Path: "docs/api.md", Content: "Save is documented here too."},
+	}
+
+	sig := classify.AnalyzeDocSignal("Save", taxonomy.ReceiverMutation, docs, nil)
+
+	if sig.SourceFile != "README.md" {
+		t.Errorf("SourceFile = %q, want %q", sig.SourceFile, "README.md")
+	}
+	if sig.Excerpt != "- `Save` persists a record." {
+		t.Errorf("Excerpt = %q", sig.Excerpt)
+	}
+}
+
+// TestAnalyzeDocSignal_EmptyInputs verifies that missing names,
+// the package pseudo-function, and empty doc lists yield no signal.
+func TestAnalyzeDocSignal_EmptyInputs(t *testing.T) {
+	docs := []docscan.DocumentFile{{Path: "README.md", Content: "<package> Foo"}}
+	for _, name := range []string{"", "<package>"} {
+		if sig := classify.AnalyzeDocSignal(name, taxonomy.ReturnValue, docs, nil); sig.Source != "" {
+			t.Errorf("AnalyzeDocSignal(%q) = %+v, want zero", name, sig)
+		}
+	}
+	if sig := classify.AnalyzeDocSignal("Foo", taxonomy.ReturnValue, nil, nil); sig.Source != "" {
+		t.Errorf("nil docs: got %+v, want zero", sig)
+	}
+}
+
+// TestAnalyzeDocSignal_LongExcerptTruncated verifies that very long
+// lines are truncated in the excerpt.
+func TestAnalyzeDocSignal_LongExcerptTruncated(t *testing.T) {
+	line := "`Foo` " + strings.Repeat("x", 500)
+	docs := []docscan.DocumentFile{{Path: "README.md", Content: line}}
+
+	sig := classify.AnalyzeDocSignal("Foo", taxonomy.ReturnValue, docs, nil)
+	if len(sig.Excerpt) > 210 || !strings.HasSuffix(sig.Excerpt, "...") {
+		t.Errorf("excerpt not truncated: len=%d", len(sig.Excerpt))
+	}
+}
+
+// TestClassify_DocSignalHonorsDocScanConfig verifies that Classify
+// scans ModuleRoot for docs, attaches a readme signal for named
+// functions, and skips documents excluded by the DocScan globs.
+func TestClassify_DocSignalHonorsDocScanConfig(t *testing.T) {
+	allPkgs := loadTestPackages(t)
+	contractsPkg := findPackage(allPkgs, "contracts")
+	if contractsPkg == nil {
+		t.Fatal("contracts package not found")
+	}
+
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "README.md"),
+		[]byte("Call GetVersion to read the version.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	hasReadme := func(cfg *config.GazeConfig) bool {
+		results, err := analysis.Analyze(contractsPkg, analysis.Options{
+			FunctionFilter: "GetVersion",
+		})
+		if err != nil {
+			t.Fatalf("analysis failed: %v", err)
+		}
+		classified := classify.Classify(results, classify.Options{
+			Config:     cfg,
+			TargetPkg:  contractsPkg,
+			ModuleRoot: root,
+		})
+		for _, r := range classified {
+			for _, se := range r.SideEffects {
+				for _, s := range se.Classification.Signals {
+					if s.Source == "readme" {
+						return true
+					}
+				}
+			}
+		}
+		return false
+	}
+
+	if !hasReadme(config.DefaultConfig()) {
+		t.Error("expected readme signal with default config")
+	}
+
+	excluded := config.DefaultConfig()
+	excluded.Classification.DocScan.Exclude = append(
+		excluded.Classification.DocScan.Exclude, "README.md")
+	if hasReadme(excluded) {
+		t.Error("expected no readme signal when README.md is excluded")
+	}
+}
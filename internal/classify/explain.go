@@ -0,0 +1,165 @@
+package classify
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/unbound-force/gaze/internal/config"
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// contradictionSource is the signal source ComputeScore records when
+// it applies the contradiction penalty.
+const contradictionSource = "contradiction"
+
+// Explanation is the step-by-step arithmetic behind a
+// classification: the base confidence, the tier boost, each signal
+// weight, the contradiction penalty, the clamp to 0-100, and the
+// threshold comparisons that chose the label. When an override
+// replaced the scored result, Override records it and FinalLabel
+// and FinalConfidence hold the forced values.
+type Explanation struct {
+	EffectType taxonomy.SideEffectType
+
+	// Base and TierBoost are the starting score, before signals.
+	Base      int
+	TierBoost int
+
+	// Signals are the weighted signals, in the order they were
+	// summed.
+	Signals []taxonomy.Signal
+
+	// Contradiction is the penalty subtracted because positive and
+	// negative signals were both present, or 0. It is
+	// ContradictionFactor times Weaker, the total weight of the
+	// weaker side, capped at 20.
+	Contradiction       int
+	ContradictionFactor float64
+	Weaker              int
+
+	// Unclamped is the score before clamping; Confidence after.
+	Unclamped  int
+	Confidence int
+
+	ContractualThreshold int
+	IncidentalThreshold  int
+
+	// Label is the scored label and Comparison the threshold
+	// comparison that produced it.
+	Label      taxonomy.ClassificationLabel
+	Comparison string
+
+	// Override is the override signal applied after scoring, or nil.
+	Override *taxonomy.Signal
+
+	FinalLabel      taxonomy.ClassificationLabel
+	FinalConfidence int
+}
+
+// Explain traces the computation ComputeScore performs for an
+// effect of effectType with the given signals.
+func Explain(effectType taxonomy.SideEffectType, signals []taxonomy.Signal, cfg *config.GazeConfig) Explanation {
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+
+	e := Explanation{
+		EffectType:           effectType,
+		Base:                 baseConfidence,
+		TierBoost:            tierBoost(effectType),
+		ContractualThreshold: cfg.Classification.Thresholds.Contractual,
+		IncidentalThreshold:  cfg.Classification.Thresholds.Incidental,
+	}
+	score, positive, negative := accumulateSignals(effectType, signals)
+	for _, s := range signals {
+		if s.Weight == 0 && s.Source == "" {
+			continue
+		}
+		e.Signals = append(e.Signals, s)
+	}
+	e.ContradictionFactor = cfg.Classification.ContradictionScale()
+	e.Contradiction = contradictionPenalty(positive, negative, e.ContradictionFactor)
+	e.Weaker = min(positive, negative)
+	score -= e.Contradiction
+	e.Unclamped = score
+	e.Confidence = clampConfidence(score)
+	e.Label, e.Comparison = classifyLabel(e.Confidence, e.ContractualThreshold, e.IncidentalThreshold)
+	e.FinalLabel, e.FinalConfidence = e.Label, e.Confidence
+	return e
+}
+
+// ExplainClassification traces how c, a classification of an effect
+// of effectType produced by Classify, was computed. The signals
+// recorded in c are re-scored with cfg; an override among them is
+// reported as the final step.
+func ExplainClassification(effectType taxonomy.SideEffectType, c taxonomy.Classification, cfg *config.GazeConfig) Explanation {
+	var signals []taxonomy.Signal
+	var override *taxonomy.Signal
+	for i, s := range c.Signals {
+		switch s.Source {
+		case contradictionSource:
+		case overrideSource:
+			override = &c.Signals[i]
+		default:
+			signals = append(signals, s)
+		}
+	}
+	e := Explain(effectType, signals, cfg)
+	if override != nil {
+		e.Override = override
+		e.FinalLabel, e.FinalConfidence = c.Label, c.Confidence
+	}
+	return e
+}
+
+// WriteText writes the explanation as a running total, one step
+// per line, followed by the threshold comparisons and the label.
+func (e Explanation) WriteText(w io.Writer) error {
+	var b strings.Builder
+	total := e.Base
+	step := func(name string, delta int, note string) {
+		total += delta
+		fmt.Fprintf(&b, "  %-28s %+5d = %3d", name, delta, total)
+		if note != "" {
+			fmt.Fprintf(&b, "  (%s)", note)
+		}
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "  %-28s %5d = %3d\n", "base confidence", e.Base, total)
+	step(fmt.Sprintf("tier boost (%s)", taxonomy.TierOf(e.EffectType)), e.TierBoost, "")
+	for _, s := range e.Signals {
+		step("signal "+s.Source, s.Weight, s.Reasoning)
+	}
+	if e.Contradiction != 0 {
+		step("contradiction penalty", -e.Contradiction,
+			fmt.Sprintf("factor %g × weaker side %d, capped at %d", e.ContradictionFactor, e.Weaker, maxContradictionPenalty))
+	}
+	if e.Confidence != e.Unclamped {
+		fmt.Fprintf(&b, "  %-28s       = %3d  (from %d)\n", "clamp to [0, 100]", e.Confidence, e.Unclamped)
+	}
+
+	fmt.Fprintf(&b, "  contractual: %d >= %d? %s\n",
+		e.Confidence, e.ContractualThreshold, yesNo(e.Confidence >= e.ContractualThreshold))
+	if e.Confidence < e.ContractualThreshold {
+		fmt.Fprintf(&b, "  incidental:  %d < %d? %s\n",
+			e.Confidence, e.IncidentalThreshold, yesNo(e.Confidence < e.IncidentalThreshold))
+	}
+	fmt.Fprintf(&b, "  label: %s (%s)\n", e.Label, e.Comparison)
+	if e.Override != nil {
+		fmt.Fprintf(&b, "  override: %s (%s); final label %s, confidence %d\n",
+			e.Override.Source, e.Override.Reasoning, e.FinalLabel, e.FinalConfidence)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// yesNo renders a comparison result for WriteText.
+func yesNo(ok bool) string {
+	if ok {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,88 @@
+package classify_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/unbound-force/gaze/internal/classify"
+	"github.com/unbound-force/gaze/internal/config"
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// TestExplain_MatchesComputeScore checks that the traced arithmetic
+// ends where ComputeScore does.
+func TestExplain_MatchesComputeScore(t *testing.T) {
+	cases := []struct {
+		name       string
+		effectType taxonomy.SideEffectType
+		signals    []taxonomy.Signal
+	}{
+		{"no signals P0", taxonomy.ReturnValue, nil},
+		{"contractual", "", []taxonomy.Signal{{Source: "interface", Weight: 30}, {Source: "visibility", Weight: 10}}},
+		{"contradiction", taxonomy.GlobalMutation, []taxonomy.Signal{{Source: "interface", Weight: 30}, {Source: "naming", Weight: -10}}},
+		{"clamped high", taxonomy.ReturnValue, []taxonomy.Signal{{Source: "interface", Weight: 30}, {Source: "caller", Weight: 15}}},
+		{"clamped low", taxonomy.LogWrite, []taxonomy.Signal{{Source: "naming", Weight: -30}, {Source: "godoc", Weight: -30}}},
+		{"empty signal skipped", "", []taxonomy.Signal{{}, {Source: "naming", Weight: -10}}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := classify.ComputeScore(tc.effectType, tc.signals, nil)
+			e := classify.Explain(tc.effectType, tc.signals, nil)
+			if e.Confidence != c.Confidence || e.Label != c.Label {
+				t.Errorf("Explain = %s %d, ComputeScore = %s %d", e.Label, e.Confidence, c.Label, c.Confidence)
+			}
+			total := e.Base + e.TierBoost - e.Contradiction
+			for _, s := range e.Signals {
+				total += s.Weight
+			}
+			if total != e.Unclamped {
+				t.Errorf("steps sum to %d, Unclamped = %d", total, e.Unclamped)
+			}
+
+			// Re-explaining the classification ComputeScore returned,
+			// with its contradiction signal, gives the same trace.
+			again := classify.ExplainClassification(tc.effectType, c, nil)
+			if again.Unclamped != e.Unclamped || again.FinalConfidence != c.Confidence || again.Override != nil {
+				t.Errorf("ExplainClassification = %+v, want %+v", again, e)
+			}
+		})
+	}
+}
+
+func TestExplanation_WriteText(t *testing.T) {
+	cfg := config.DefaultConfig()
+	c := taxonomy.Classification{
+		Label:      taxonomy.Incidental,
+		Confidence: 10,
+		Signals: []taxonomy.Signal{
+			{Source: "interface", Weight: 30, Reasoning: "implements io.Writer"},
+			{Source: "naming", Weight: -10},
+			{Source: "contradiction", Weight: -20},
+			{Source: "override", Weight: -70, Reasoning: `function matches override "Debug*" in .gaze.yaml`},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := classify.ExplainClassification(taxonomy.ReceiverMutation, c, cfg).WriteText(&buf); err != nil {
+		t.Fatalf("WriteText: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"base confidence                 50 =  50",
+		"tier boost (P0)                +25 =  75",
+		"signal interface               +30 = 105  (implements io.Writer)",
+		"signal naming                  -10 =  95",
+		"contradiction penalty          -10 =  85  (factor 1 × weaker side 10, capped at 20)",
+		"contractual: 85 >= 80? yes",
+		"label: contractual (confidence 85 >= 80 (contractual threshold), 5 above)",
+		"override: override (function matches override \"Debug*\" in .gaze.yaml); final label incidental, confidence 10",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "clamp") {
+		t.Errorf("unexpected clamp step for an in-range score:\n%s", out)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"go/ast"
 	"strings"
 
+	"github.com/unbound-force/gaze/internal/config"
 	"github.com/unbound-force/gaze/internal/taxonomy"
 )
 
@@ -31,24 +32,70 @@ var incidentalKeywords = []string{
 	"logs", "prints", "traces", "debugs",
 }
 
-// maxGodocWeight is the maximum weight for godoc comment signals.
-const maxGodocWeight = 15
-
-// reducedGodocWeight is the signal weight when a contractual keyword is
-// found but the effect type does not match the keyword's impliesFor list.
-// This provides a positive signal for well-documented functions without
-// inflating scores as much as a direct type match.
-const reducedGodocWeight = 5
+// deprecatedMarker is the conventional Go doc prefix that marks an
+// identifier as deprecated (see go.dev/wiki/Deprecated).
+const deprecatedMarker = "Deprecated:"
 
 // AnalyzeGodocSignal parses the function's doc comment for
 // behavioral declarations and returns a signal indicating whether
 // the side effect is likely contractual or incidental.
-func AnalyzeGodocSignal(funcDecl *ast.FuncDecl, effectType taxonomy.SideEffectType) taxonomy.Signal {
+//
+// Weights come from cfg's weight table: "godoc" for a keyword whose
+// implied effect types include effectType (±15 by default), and
+// "godoc_keyword_indirect" when a contractual keyword is found but
+// the effect type is not directly implied (5 by default) — a
+// positive signal for well-documented functions that does not
+// inflate scores as much as a direct match. A "Deprecated:" marker
+// lowers the result by the "godoc_deprecated" weight (5 by
+// default). That penalty is deliberately small: a deprecated
+// function's effects are less likely to be part of the contract
+// under active test, but the marker alone should only nudge
+// ambiguous cases, never override interface or caller evidence.
+func AnalyzeGodocSignal(funcDecl *ast.FuncDecl, effectType taxonomy.SideEffectType, cfg *config.GazeConfig) taxonomy.Signal {
 	if funcDecl == nil || funcDecl.Doc == nil {
 		return taxonomy.Signal{}
 	}
 
-	docText := strings.ToLower(funcDecl.Doc.Text())
+	rawText := funcDecl.Doc.Text()
+	sig := keywordGodocSignal(strings.ToLower(rawText), effectType, cfg)
+	if !isDeprecated(rawText) {
+		return sig
+	}
+
+	dw := weightFor(cfg, "godoc_deprecated")
+	deprecatedGodocWeight := min(dw.Base, dw.Max)
+
+	if sig.Source == "" {
+		return taxonomy.Signal{
+			Source:    "godoc",
+			Weight:    -deprecatedGodocWeight,
+			Reasoning: "godoc marks the function as deprecated",
+		}
+	}
+	sig.Weight -= deprecatedGodocWeight
+	sig.Reasoning += "; function is marked deprecated"
+	return sig
+}
+
+// isDeprecated reports whether the doc text contains a line that
+// begins with the "Deprecated:" marker. Matching is case-sensitive
+// to follow the convention recognized by go vet and pkg.go.dev.
+func isDeprecated(docText string) bool {
+	for _, line := range strings.Split(docText, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), deprecatedMarker) {
+			return true
+		}
+	}
+	return false
+}
+
+// keywordGodocSignal matches the lowercased doc text against the
+// incidental and contractual keyword tables.
+func keywordGodocSignal(docText string, effectType taxonomy.SideEffectType, cfg *config.GazeConfig) taxonomy.Signal {
+	gw := weightFor(cfg, "godoc")
+	maxGodocWeight := min(gw.Base, gw.Max)
+	rw := weightFor(cfg, "godoc_keyword_indirect")
+	reducedGodocWeight := min(rw.Base, rw.Max)
 
 	// Check incidental keywords first.
 	for _, kw := range incidentalKeywords {
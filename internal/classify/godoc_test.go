@@ -192,7 +192,7 @@ func TestAnalyzeGodocSignal_ContractualKeywords(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			fd := makeFuncDeclWithDoc("TestFunc", tt.doc)
-			sig := classify.AnalyzeGodocSignal(fd, tt.effectType)
+			sig := classify.AnalyzeGodocSignal(fd, tt.effectType, nil)
 
 			if sig.Weight != tt.wantWeight {
 				t.Errorf("weight = %d, want %d", sig.Weight, tt.wantWeight)
@@ -241,7 +241,7 @@ func TestAnalyzeGodocSignal_IncidentalPriority(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			fd := makeFuncDeclWithDoc("TestFunc", tt.doc)
-			sig := classify.AnalyzeGodocSignal(fd, taxonomy.ReturnValue)
+			sig := classify.AnalyzeGodocSignal(fd, taxonomy.ReturnValue, nil)
 
 			if sig.Weight != -15 {
 				t.Errorf("weight = %d, want -15 (incidental wins)", sig.Weight)
@@ -257,7 +257,7 @@ func TestAnalyzeGodocSignal_IncidentalPriority(t *testing.T) {
 // keyword alone produces -15 weight.
 func TestAnalyzeGodocSignal_IncidentalOnly(t *testing.T) {
 	fd := makeFuncDeclWithDoc("LogError", "LogError logs the error to stderr.")
-	sig := classify.AnalyzeGodocSignal(fd, taxonomy.ReturnValue)
+	sig := classify.AnalyzeGodocSignal(fd, taxonomy.ReturnValue, nil)
 
 	if sig.Weight != -15 {
 		t.Errorf("weight = %d, want -15", sig.Weight)
@@ -270,7 +270,7 @@ func TestAnalyzeGodocSignal_IncidentalOnly(t *testing.T) {
 // TestAnalyzeGodocSignal_NilFuncDecl verifies that nil funcDecl
 // returns a zero signal (FR-006).
 func TestAnalyzeGodocSignal_NilFuncDecl(t *testing.T) {
-	sig := classify.AnalyzeGodocSignal(nil, taxonomy.ReturnValue)
+	sig := classify.AnalyzeGodocSignal(nil, taxonomy.ReturnValue, nil)
 
 	if sig.Weight != 0 {
 		t.Errorf("nil funcDecl: weight = %d, want 0", sig.Weight)
@@ -288,7 +288,7 @@ func TestAnalyzeGodocSignal_NilDocComment(t *testing.T) {
 		Type: &ast.FuncType{},
 	}
 
-	sig := classify.AnalyzeGodocSignal(fd, taxonomy.ReturnValue)
+	sig := classify.AnalyzeGodocSignal(fd, taxonomy.ReturnValue, nil)
 
 	if sig.Weight != 0 {
 		t.Errorf("nil Doc: weight = %d, want 0", sig.Weight)
@@ -302,7 +302,7 @@ func TestAnalyzeGodocSignal_NilDocComment(t *testing.T) {
 // matching is case-insensitive.
 func TestAnalyzeGodocSignal_CaseInsensitive(t *testing.T) {
 	fd := makeFuncDeclWithDoc("GetVersion", "GetVersion RETURNS the version string.")
-	sig := classify.AnalyzeGodocSignal(fd, taxonomy.ReturnValue)
+	sig := classify.AnalyzeGodocSignal(fd, taxonomy.ReturnValue, nil)
 
 	if sig.Weight != 15 {
 		t.Errorf("case-insensitive: weight = %d, want 15", sig.Weight)
@@ -314,7 +314,7 @@ func TestAnalyzeGodocSignal_CaseInsensitive(t *testing.T) {
 func TestAnalyzeGodocSignal_NoKeyword(t *testing.T) {
 	fd := makeFuncDeclWithDoc("ComputeHash",
 		"ComputeHash computes a SHA-256 hash of the input.")
-	sig := classify.AnalyzeGodocSignal(fd, taxonomy.ReturnValue)
+	sig := classify.AnalyzeGodocSignal(fd, taxonomy.ReturnValue, nil)
 
 	if sig.Weight != 0 {
 		t.Errorf("no keyword: weight = %d, want 0", sig.Weight)
@@ -329,7 +329,7 @@ func TestAnalyzeGodocSignal_NoKeyword(t *testing.T) {
 func TestAnalyzeGodocSignal_ReasoningContent(t *testing.T) {
 	fd := makeFuncDeclWithDoc("GetVersion",
 		"GetVersion returns the current version.")
-	sig := classify.AnalyzeGodocSignal(fd, taxonomy.ReturnValue)
+	sig := classify.AnalyzeGodocSignal(fd, taxonomy.ReturnValue, nil)
 
 	if sig.Reasoning == "" {
 		t.Fatal("expected non-empty reasoning")
@@ -389,7 +389,7 @@ func TestAnalyzeGodocSignal_RealFixture(t *testing.T) {
 				t.Fatalf("%s func decl not found", tt.funcName)
 			}
 
-			sig := classify.AnalyzeGodocSignal(funcDecl, tt.effectType)
+			sig := classify.AnalyzeGodocSignal(funcDecl, tt.effectType, nil)
 
 			if sig.Weight != tt.wantWeight {
 				t.Errorf("weight = %d, want %d", sig.Weight, tt.wantWeight)
@@ -397,3 +397,76 @@ func TestAnalyzeGodocSignal_RealFixture(t *testing.T) {
 		})
 	}
 }
+
+// makeFuncDeclWithDocLines constructs a minimal *ast.FuncDecl whose
+// doc comment spans the given lines.
+func makeFuncDeclWithDocLines(name string, lines ...string) *ast.FuncDecl {
+	comments := make([]*ast.Comment, 0, len(lines))
+	for _, l := range lines {
+		comments = append(comments, &ast.Comment{Text: "// " + l})
+	}
+	return &ast.FuncDecl{
+		Name: ast.NewIdent(name),
+		Doc:  &ast.CommentGroup{List: comments},
+		Type: &ast.FuncType{},
+	}
+}
+
+// TestAnalyzeGodocSignal_Deprecated verifies that a "Deprecated:"
+// paragraph produces a small negative godoc signal on its own and
+// reduces the weight of any keyword signal it accompanies.
+func TestAnalyzeGodocSignal_Deprecated(t *testing.T) {
+	tests := []struct {
+		name       string
+		lines      []string
+		effectType taxonomy.SideEffectType
+		wantSource string
+		wantWeight int
+	}{
+		{
+			name:       "marker only",
+			lines:      []string{"OldHash computes a hash.", "", "Deprecated: use NewHash instead."},
+			effectType: taxonomy.ReturnValue,
+			wantSource: "godoc",
+			wantWeight: -5,
+		},
+		{
+			name:       "marker with matching keyword",
+			lines:      []string{"GetVersion returns the version.", "", "Deprecated: use Version."},
+			effectType: taxonomy.ReturnValue,
+			wantSource: "godoc",
+			wantWeight: 10,
+		},
+		{
+			name:       "marker with incidental keyword",
+			lines:      []string{"Dump prints the state.", "", "Deprecated: use Write."},
+			effectType: taxonomy.StdoutWrite,
+			wantSource: "godoc",
+			wantWeight: -20,
+		},
+		{
+			name:       "lowercase mention is not a marker",
+			lines:      []string{"Compute is not deprecated: it computes a value."},
+			effectType: taxonomy.ReturnValue,
+			wantSource: "",
+			wantWeight: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fd := makeFuncDeclWithDocLines("Fn", tt.lines...)
+			sig := classify.AnalyzeGodocSignal(fd, tt.effectType, nil)
+
+			if sig.Source != tt.wantSource {
+				t.Errorf("source = %q, want %q", sig.Source, tt.wantSource)
+			}
+			if sig.Weight != tt.wantWeight {
+				t.Errorf("weight = %d, want %d", sig.Weight, tt.wantWeight)
+			}
+			if tt.wantSource != "" && !strings.Contains(sig.Reasoning, "deprecated") {
+				t.Errorf("reasoning %q should mention deprecation", sig.Reasoning)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package classify
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// CallGraph is the reference graph around one function: the module
+// functions that reference it, transitively, and optionally the
+// module functions it references. References are found the same way
+// as for the caller signal, through TypesInfo.Uses, so a function
+// passed as a value counts as well as a direct call.
+type CallGraph struct {
+	// Root is the key of the function the graph is built around.
+	Root string
+
+	// Nodes maps each function key in the graph to its display
+	// name, such as "store.Store.Save".
+	Nodes map[string]string
+
+	// Edges are the caller -> callee references, sorted.
+	Edges []CallEdge
+}
+
+// CallEdge is one reference from a caller to a callee, both given
+// as function keys.
+type CallEdge struct {
+	Caller string
+	Callee string
+}
+
+// BuildCallGraph builds the CallGraph of root across pkgs. Callers
+// are followed up to depth levels (1 means direct callers only);
+// when callees is true, the module functions root references are
+// followed the same number of levels. Functions outside pkgs (the
+// standard library, dependencies) are not included as callees.
+func BuildCallGraph(root types.Object, pkgs []*packages.Package, depth int, callees bool) *CallGraph {
+	g := &CallGraph{
+		Root:  funcKey(root),
+		Nodes: make(map[string]string),
+	}
+	if g.Root == "" {
+		return g
+	}
+	g.Nodes[g.Root] = displayName(root)
+
+	refs, names := referenceIndex(pkgs)
+	callersOf := make(map[string][]string)
+	for caller, set := range refs {
+		for callee := range set {
+			callersOf[callee] = append(callersOf[callee], caller)
+		}
+	}
+
+	edges := make(map[CallEdge]bool)
+	walk := func(next func(string) []string, edge func(from, to string) CallEdge) {
+		seen := map[string]bool{g.Root: true}
+		frontier := []string{g.Root}
+		for level := 0; level < depth && len(frontier) > 0; level++ {
+			var following []string
+			for _, from := range frontier {
+				for _, to := range next(from) {
+					edges[edge(from, to)] = true
+					g.Nodes[to] = names[to]
+					if !seen[to] {
+						seen[to] = true
+						following = append(following, to)
+					}
+				}
+			}
+			frontier = following
+		}
+	}
+
+	walk(func(k string) []string { return callersOf[k] },
+		func(from, to string) CallEdge { return CallEdge{Caller: to, Callee: from} })
+	if callees {
+		walk(func(k string) []string {
+			var out []string
+			for callee := range refs[k] {
+				// Only functions declared in pkgs have an entry.
+				if _, ok := refs[callee]; ok {
+					out = append(out, callee)
+				}
+			}
+			return out
+		}, func(from, to string) CallEdge { return CallEdge{Caller: from, Callee: to} })
+	}
+
+	for e := range edges {
+		g.Edges = append(g.Edges, e)
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].Caller != g.Edges[j].Caller {
+			return g.Edges[i].Caller < g.Edges[j].Caller
+		}
+		return g.Edges[i].Callee < g.Edges[j].Callee
+	})
+	return g
+}
+
+// referenceIndex maps the key of every function declared in pkgs to
+// the set of function keys its body references, and every key seen
+// to a display name. References from function literals count toward
+// the enclosing declaration; self-references are omitted.
+func referenceIndex(pkgs []*packages.Package) (map[string]map[string]bool, map[string]string) {
+	refs := make(map[string]map[string]bool)
+	names := make(map[string]string)
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		info := pkg.TypesInfo
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				fd, ok := decl.(*ast.FuncDecl)
+				if !ok || fd.Body == nil {
+					continue
+				}
+				obj := info.Defs[fd.Name]
+				caller := funcKey(obj)
+				if caller == "" {
+					continue
+				}
+				names[caller] = displayName(obj)
+				set := refs[caller]
+				if set == nil {
+					set = make(map[string]bool)
+					refs[caller] = set
+				}
+				ast.Inspect(fd.Body, func(n ast.Node) bool {
+					ident, ok := n.(*ast.Ident)
+					if !ok {
+						return true
+					}
+					fn, ok := info.Uses[ident].(*types.Func)
+					if !ok {
+						return true
+					}
+					if callee := funcKey(fn); callee != "" && callee != caller {
+						set[callee] = true
+						if _, ok := names[callee]; !ok {
+							names[callee] = displayName(fn)
+						}
+					}
+					return true
+				})
+			}
+		}
+	}
+	return refs, names
+}
+
+// displayName shortens a function key by replacing the package path
+// with the package name, e.g. "store.Store.Save".
+func displayName(obj types.Object) string {
+	key := funcKey(obj)
+	if key == "" {
+		return ""
+	}
+	return obj.Pkg().Name() + "." + strings.TrimPrefix(key, obj.Pkg().Path()+".")
+}
+
+// WriteDOT writes the graph in Graphviz DOT format. The root
+// function is drawn in bold; edges point from caller to callee.
+func (g *CallGraph) WriteDOT(w io.Writer) error {
+	keys := make([]string, 0, len(g.Nodes))
+	for k := range g.Nodes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", strconv.Quote(g.Nodes[g.Root]))
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=box];\n")
+	for _, k := range keys {
+		attrs := "label=" + strconv.Quote(g.Nodes[k])
+		if k == g.Root {
+			attrs += ", style=bold"
+		}
+		fmt.Fprintf(&b, "\t%s [%s];\n", strconv.Quote(k), attrs)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "\t%s -> %s;\n", strconv.Quote(e.Caller), strconv.Quote(e.Callee))
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// LookupFunction finds a package-level function ("Name") or a
+// method ("Type.Name") in pkg. It returns nil if there is none.
+func LookupFunction(pkg *types.Package, name string) types.Object {
+	if pkg == nil {
+		return nil
+	}
+	typeName, method, isMethod := strings.Cut(name, ".")
+	if !isMethod {
+		fn, _ := pkg.Scope().Lookup(name).(*types.Func)
+		if fn == nil {
+			return nil
+		}
+		return fn
+	}
+	tn, ok := pkg.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(tn.Type()), true, pkg, method)
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return nil
+	}
+	return fn
+}
@@ -0,0 +1,105 @@
+package classify_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/unbound-force/gaze/internal/classify"
+)
+
+func TestBuildCallGraph_Callers(t *testing.T) {
+	pkgs := loadTestPackages(t)
+	contractsPkg := findPackage(pkgs, "contracts")
+	if contractsPkg == nil {
+		t.Fatal("contracts package not found")
+	}
+	obj := classify.LookupFunction(contractsPkg.Types, "GetData")
+	if obj == nil {
+		t.Fatal("GetData not found")
+	}
+
+	g := classify.BuildCallGraph(obj, pkgs, 1, false)
+
+	if got := g.Nodes[g.Root]; got != "contracts.GetData" {
+		t.Errorf("root name = %q, want %q", got, "contracts.GetData")
+	}
+	if len(g.Edges) != 1 {
+		t.Fatalf("expected 1 edge, got %+v", g.Edges)
+	}
+	if caller := g.Nodes[g.Edges[0].Caller]; caller != "callers.UseGetData" {
+		t.Errorf("caller = %q, want %q", caller, "callers.UseGetData")
+	}
+	if g.Edges[0].Callee != g.Root {
+		t.Errorf("callee = %q, want root %q", g.Edges[0].Callee, g.Root)
+	}
+}
+
+func TestBuildCallGraph_CalleesStayInModule(t *testing.T) {
+	pkgs := loadTestPackages(t)
+	callersPkg := findPackage(pkgs, "callers")
+	if callersPkg == nil {
+		t.Fatal("callers package not found")
+	}
+	obj := classify.LookupFunction(callersPkg.Types, "UseFetchConfig")
+	if obj == nil {
+		t.Fatal("UseFetchConfig not found")
+	}
+
+	g := classify.BuildCallGraph(obj, pkgs, 2, true)
+
+	var callees []string
+	for _, e := range g.Edges {
+		if e.Caller == g.Root {
+			callees = append(callees, g.Nodes[e.Callee])
+		}
+	}
+	if strings.Join(callees, ",") != "contracts.FetchConfig" {
+		t.Errorf("callees = %v, want [contracts.FetchConfig]", callees)
+	}
+}
+
+func TestLookupFunction_Method(t *testing.T) {
+	pkgs := loadTestPackages(t)
+	contractsPkg := findPackage(pkgs, "contracts")
+	if contractsPkg == nil {
+		t.Fatal("contracts package not found")
+	}
+	if obj := classify.LookupFunction(contractsPkg.Types, "FileStore.Save"); obj == nil {
+		t.Error("FileStore.Save not found")
+	}
+	for _, name := range []string{"Missing", "FileStore.Missing", "FileStore.Path"} {
+		if obj := classify.LookupFunction(contractsPkg.Types, name); obj != nil {
+			t.Errorf("LookupFunction(%q) = %v, want nil", name, obj)
+		}
+	}
+}
+
+func TestCallGraph_WriteDOT(t *testing.T) {
+	g := &classify.CallGraph{
+		Root: "example.com/store.Save",
+		Nodes: map[string]string{
+			"example.com/store.Save": "store.Save",
+			"example.com/api.Handle": "api.Handle",
+		},
+		Edges: []classify.CallEdge{
+			{Caller: "example.com/api.Handle", Callee: "example.com/store.Save"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := g.WriteDOT(&buf); err != nil {
+		t.Fatalf("WriteDOT: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`digraph "store.Save" {`,
+		`"example.com/store.Save" [label="store.Save", style=bold];`,
+		`"example.com/api.Handle" [label="api.Handle"];`,
+		`"example.com/api.Handle" -> "example.com/store.Save";`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DOT output missing %q:\n%s", want, out)
+		}
+	}
+}
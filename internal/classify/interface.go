@@ -4,21 +4,27 @@ package classify
 import (
 	"fmt"
 	"go/types"
+	"strings"
 
 	"golang.org/x/tools/go/packages"
 
+	"github.com/unbound-force/gaze/internal/config"
 	"github.com/unbound-force/gaze/internal/taxonomy"
 )
 
-// maxInterfaceWeight is the maximum weight for interface
-// satisfaction signals.
-const maxInterfaceWeight = 30
-
 // analyzeInterfaceSignal checks if the function's receiver type
-// satisfies any interface defined in the module. When a method's
-// side effect matches the interface's method signature, it is
-// strong contractual evidence. Returns a zero signal for
-// non-method functions.
+// satisfies any interface defined in the module, or any interface
+// embedded as a field of the receiver struct (e.g. http.Handler in
+// `type Server struct{ http.Handler }`), including interfaces from
+// outside the module. When a method's side effect matches the
+// interface's method signature, it is strong contractual evidence.
+// Returns a zero signal for non-method functions.
+//
+// The reasoning names every satisfying interface that declares the
+// method, qualified by package name and followed by its import path
+// (e.g. "method Save satisfies contracts.Store.Save (example.com/
+// contracts)"), and the excerpt carries their method signatures, so
+// reviewers can check which contract drove the boost.
 //
 // ifaces is a pre-computed slice from collectInterfaces; callers
 // should compute this once per Classify invocation to avoid O(n²)
@@ -27,11 +33,15 @@ const maxInterfaceWeight = 30
 // This function is intentionally unexported because its ifaces
 // parameter uses the unexported namedInterface type. All callers
 // go through Classify() which pre-computes the interface list.
+//
+// The weight is the configured "interface" base weight, capped at
+// its max.
 func analyzeInterfaceSignal(
 	funcName string,
 	receiverType types.Type,
 	_ taxonomy.SideEffectType,
 	ifaces []namedInterface,
+	cfg *config.GazeConfig,
 ) taxonomy.Signal {
 	if receiverType == nil {
 		return taxonomy.Signal{}
@@ -41,40 +51,149 @@ func analyzeInterfaceSignal(
 		return taxonomy.Signal{}
 	}
 
-	// Check if the receiver type (or pointer to it) satisfies any
-	// interface that declares a method with the same name.
+	w := weightFor(cfg, "interface")
+
+	// Collect every interface the receiver type (or pointer to it)
+	// satisfies that declares a method with the same name.
+	var names, decls []string
 	for _, iface := range ifaces {
 		if !satisfies(receiverType, iface.iface) {
 			continue
 		}
+		if method := interfaceMethod(iface.iface, funcName); method != nil {
+			names = append(names, fmt.Sprintf("%s.%s (%s)", iface.short, funcName, iface.pkgPath))
+			decls = append(decls, methodDecl(iface.short, method))
+		}
+	}
+	if len(names) > 0 {
+		// The method is declared by a satisfied interface — this
+		// side effect is contractual.
+		return taxonomy.Signal{
+			Source:    "interface",
+			Weight:    min(w.Base, w.Max),
+			Excerpt:   truncateExcerpt(strings.Join(decls, "; ")),
+			Reasoning: fmt.Sprintf("method %s satisfies %s", funcName, strings.Join(names, ", ")),
+		}
+	}
 
-		// The type satisfies this interface. Check if the method
-		// is declared in the interface.
-		for i := 0; i < iface.iface.NumMethods(); i++ {
-			method := iface.iface.Method(i)
-			if method.Name() != funcName {
-				continue
-			}
+	if iface, method := embeddedInterfaceFor(receiverType, funcName); iface != nil {
+		short, pkgPath := interfaceNames(iface)
+		return taxonomy.Signal{
+			Source:  "interface",
+			Weight:  min(w.Base, w.Max),
+			Excerpt: methodDecl(short, method),
+			Reasoning: fmt.Sprintf(
+				"method %s satisfies embedded interface %s.%s (%s)",
+				funcName, short, funcName, pkgPath,
+			),
+		}
+	}
+
+	return taxonomy.Signal{}
+}
+
+// methodDecl renders an interface method with its signature,
+// qualified by the interface's short name: "contracts.Store.Save(data
+// []byte) error". Types in the signature are qualified by package
+// name.
+func methodDecl(ifaceName string, method *types.Func) string {
+	sig := types.TypeString(method.Type(), (*types.Package).Name)
+	return ifaceName + "." + method.Name() + strings.TrimPrefix(sig, "func")
+}
 
-			// The method is in the interface — this side effect
-			// is contractual.
-			return taxonomy.Signal{
-				Source: "interface",
-				Weight: maxInterfaceWeight,
-				Reasoning: fmt.Sprintf(
-					"method %s satisfies interface %s",
-					funcName, iface.name,
-				),
+// interfaceNames returns the package-qualified short name ("io.Closer")
+// and the import path ("io") of an interface type. Unnamed interfaces
+// have no import path.
+func interfaceNames(typ types.Type) (short, pkgPath string) {
+	short = types.TypeString(typ, (*types.Package).Name)
+	if named, ok := typ.(*types.Named); ok && named.Obj().Pkg() != nil {
+		pkgPath = named.Obj().Pkg().Path()
+	}
+	return short, pkgPath
+}
+
+// embeddedInterfaceFor returns an interface type embedded (directly
+// or through embedded structs) in the receiver struct that declares
+// a method named funcName, together with that method, provided the
+// method named funcName in the method set of *receiverType has the
+// same signature. Returns nils when no such interface exists.
+func embeddedInterfaceFor(receiverType types.Type, funcName string) (types.Type, *types.Func) {
+	fn := methodOf(types.NewPointer(receiverType), funcName)
+	if fn == nil {
+		return nil, nil
+	}
+	return findEmbeddedInterface(receiverType, fn, make(map[types.Type]bool))
+}
+
+// methodOf returns the method named funcName in the method set of
+// typ, including methods promoted through embedded fields, or nil.
+func methodOf(typ types.Type, funcName string) *types.Func {
+	ms := types.NewMethodSet(typ)
+	for i := 0; i < ms.Len(); i++ {
+		if obj := ms.At(i).Obj(); obj.Name() == funcName {
+			fn, _ := obj.(*types.Func)
+			return fn
+		}
+	}
+	return nil
+}
+
+// findEmbeddedInterface walks the embedded fields of typ depth-first
+// and returns the first embedded interface declaring a method with
+// fn's name and signature, with that method. seen guards against
+// recursive embedding.
+func findEmbeddedInterface(typ types.Type, fn *types.Func, seen map[types.Type]bool) (types.Type, *types.Func) {
+	if seen[typ] {
+		return nil, nil
+	}
+	seen[typ] = true
+
+	st, ok := typ.Underlying().(*types.Struct)
+	if !ok {
+		return nil, nil
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if !field.Embedded() {
+			continue
+		}
+		ft := field.Type()
+		if ptr, ok := ft.(*types.Pointer); ok {
+			ft = ptr.Elem()
+		}
+		if iface, ok := ft.Underlying().(*types.Interface); ok {
+			// Receivers are ignored when comparing signatures.
+			if method := interfaceMethod(iface, fn.Name()); method != nil && types.Identical(method.Type(), fn.Type()) {
+				return ft, method
 			}
+			continue
+		}
+		if iface, method := findEmbeddedInterface(ft, fn, seen); iface != nil {
+			return iface, method
 		}
 	}
+	return nil, nil
+}
 
-	return taxonomy.Signal{}
+// interfaceMethod returns the method of iface named funcName,
+// including methods of embedded interfaces, or nil.
+func interfaceMethod(iface *types.Interface, funcName string) *types.Func {
+	for i := 0; i < iface.NumMethods(); i++ {
+		if m := iface.Method(i); m.Name() == funcName {
+			return m
+		}
+	}
+	return nil
 }
 
-// namedInterface pairs an interface type with its qualified name.
+// namedInterface pairs an interface type with its names.
 type namedInterface struct {
-	name  string
+	// short is the package-qualified name, e.g. "contracts.Store".
+	short string
+
+	// pkgPath is the import path of the declaring package.
+	pkgPath string
+
 	iface *types.Interface
 }
 
@@ -104,8 +223,9 @@ func collectInterfaces(pkgs []*packages.Package) []namedInterface {
 			}
 			seen[iface] = true
 			result = append(result, namedInterface{
-				name:  pkg.PkgPath + "." + name,
-				iface: iface,
+				short:   pkg.Types.Name() + "." + name,
+				pkgPath: pkg.PkgPath,
+				iface:   iface,
 			})
 		}
 	}
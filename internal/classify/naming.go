@@ -2,97 +2,119 @@
 package classify
 
 import (
+	"go/ast"
 	"strings"
 
+	"github.com/unbound-force/gaze/internal/config"
 	"github.com/unbound-force/gaze/internal/taxonomy"
 )
 
-// contractualPrefixes maps function name prefixes to the side effect
-// types they imply as contractual.
-var contractualPrefixes = []struct {
-	prefix     string
-	impliesFor []taxonomy.SideEffectType
-}{
-	{"Get", []taxonomy.SideEffectType{taxonomy.ReturnValue}},
-	{"Fetch", []taxonomy.SideEffectType{taxonomy.ReturnValue, taxonomy.ErrorReturn}},
-	{"Load", []taxonomy.SideEffectType{taxonomy.ReturnValue, taxonomy.ErrorReturn}},
-	{"Read", []taxonomy.SideEffectType{taxonomy.ReturnValue, taxonomy.ErrorReturn}},
-	{"Save", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.PointerArgMutation, taxonomy.ErrorReturn}},
-	{"Write", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.PointerArgMutation, taxonomy.ErrorReturn}},
-	{"Update", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.PointerArgMutation, taxonomy.ErrorReturn}},
-	{"Set", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.PointerArgMutation}},
-	{"Delete", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.ErrorReturn}},
-	{"Remove", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.ErrorReturn}},
-	{"Handle", nil},  // nil means all side effects
-	{"Process", nil}, // nil means all side effects
+// prefixEffects maps the built-in contractual name prefixes to the
+// side effect types they imply. A nil entry means all side effects.
+// Configured prefixes missing from this table also imply all side
+// effects.
+var prefixEffects = map[string][]taxonomy.SideEffectType{
+	"Get":     {taxonomy.ReturnValue},
+	"Fetch":   {taxonomy.ReturnValue, taxonomy.ErrorReturn},
+	"Load":    {taxonomy.ReturnValue, taxonomy.ErrorReturn},
+	"Read":    {taxonomy.ReturnValue, taxonomy.ErrorReturn},
+	"Save":    {taxonomy.ReceiverMutation, taxonomy.PointerArgMutation, taxonomy.ErrorReturn},
+	"Write":   {taxonomy.ReceiverMutation, taxonomy.PointerArgMutation, taxonomy.ErrorReturn},
+	"Update":  {taxonomy.ReceiverMutation, taxonomy.PointerArgMutation, taxonomy.ErrorReturn},
+	"Set":     {taxonomy.ReceiverMutation, taxonomy.PointerArgMutation},
+	"Delete":  {taxonomy.ReceiverMutation, taxonomy.ErrorReturn},
+	"Remove":  {taxonomy.ReceiverMutation, taxonomy.ErrorReturn},
+	"Handle":  nil,
+	"Process": nil,
 	// Computational / analytical functions: return value is the primary
 	// contract by Go convention (e.g., ComputeX, AnalyzeX, ParseX).
-	{"Compute", []taxonomy.SideEffectType{taxonomy.ReturnValue, taxonomy.ErrorReturn}},
-	{"Analyze", []taxonomy.SideEffectType{taxonomy.ReturnValue, taxonomy.ErrorReturn}},
-	{"Classify", []taxonomy.SideEffectType{taxonomy.ReturnValue, taxonomy.ErrorReturn}},
-	{"Parse", []taxonomy.SideEffectType{taxonomy.ReturnValue, taxonomy.ErrorReturn}},
-	{"Build", []taxonomy.SideEffectType{taxonomy.ReturnValue, taxonomy.ErrorReturn}},
-	{"New", []taxonomy.SideEffectType{taxonomy.ReturnValue, taxonomy.ErrorReturn}},
+	"Compute":  {taxonomy.ReturnValue, taxonomy.ErrorReturn},
+	"Analyze":  {taxonomy.ReturnValue, taxonomy.ErrorReturn},
+	"Classify": {taxonomy.ReturnValue, taxonomy.ErrorReturn},
+	"Parse":    {taxonomy.ReturnValue, taxonomy.ErrorReturn},
+	"Build":    {taxonomy.ReturnValue, taxonomy.ErrorReturn},
+	"New":      {taxonomy.ReturnValue, taxonomy.ErrorReturn},
+	"Make":     {taxonomy.ReturnValue, taxonomy.ErrorReturn},
 }
 
-// incidentalPrefixes are function name prefixes that signal
-// incidental behavior.
-var incidentalPrefixes = []string{
-	"log", "Log",
-	"debug", "Debug",
-	"trace", "Trace",
-	"print", "Print",
+// namingFor resolves the naming prefix lists, tolerating a nil
+// config.
+func namingFor(cfg *config.GazeConfig) config.Naming {
+	if cfg == nil {
+		return config.ClassificationConfig{}.Prefixes()
+	}
+	return cfg.Classification.Prefixes()
 }
 
-// maxNamingWeight is the maximum weight for naming convention signals.
-const maxNamingWeight = 10
-
-// sentinelNamingWeight is the weight for Err* sentinel variable naming.
-// Sentinel errors are unambiguously contractual by convention — they are
-// exported, named with the Err prefix, and exist solely to be matched by
-// callers. The weight is set so that a sentinel with no other signals
-// (base 50 + 30 = 80) reaches the default contractual threshold.
-//
-// Note: this intentionally exceeds maxNamingWeight (10). Sentinels are a
-// special sub-case: unlike regular functions, package-level var declarations
-// cannot receive interface, visibility, or godoc signals, so a stronger
-// naming weight is the only way to reach the contractual threshold.
-const sentinelNamingWeight = 30
-
 // AnalyzeNamingSignal checks the function name against Go community
 // naming conventions and returns a signal indicating whether the
 // side effect is likely contractual or incidental based on the name.
-func AnalyzeNamingSignal(funcName string, effectType taxonomy.SideEffectType) taxonomy.Signal {
+// The prefixes come from cfg.Classification.Naming, defaulting to the
+// built-in sets.
+//
+// Prefix matches use the "naming" weight (±10 by default). A Panic
+// in a Must* function uses the separate "naming_must" weight (25 by
+// default): by convention MustX is X that panics instead of
+// returning an error, so the panic is its documented failure mode.
+// Err* sentinel variables use the separate "naming_sentinel" weight
+// (30 by default). Sentinel errors are unambiguously contractual by
+// convention — they are exported, named with the Err prefix, and
+// exist solely to be matched by callers. The default is set so that
+// a sentinel with no other signals (base 50 + 30 = 80) reaches the
+// default contractual threshold. It intentionally exceeds the
+// naming weight: unlike regular functions, package-level var
+// declarations cannot receive interface, visibility, or godoc
+// signals, so a stronger naming weight is the only way to reach
+// the contractual threshold.
+func AnalyzeNamingSignal(funcName string, effectType taxonomy.SideEffectType, cfg *config.GazeConfig) taxonomy.Signal {
+	nw := weightFor(cfg, "naming")
+	namingWeight := min(nw.Base, nw.Max)
+
+	naming := namingFor(cfg)
+
 	// Check incidental prefixes first.
-	for _, prefix := range incidentalPrefixes {
-		if strings.HasPrefix(funcName, prefix) {
+	for _, prefix := range naming.IncidentalPrefixes {
+		if prefix != "" && strings.HasPrefix(funcName, prefix) {
 			return taxonomy.Signal{
 				Source:    "naming",
-				Weight:    -maxNamingWeight,
+				Weight:    -namingWeight,
 				Reasoning: "function name prefix " + prefix + "* suggests incidental behavior",
 			}
 		}
 	}
 
+	// Must* helpers panic by contract. This is checked before the
+	// configured prefixes so a project listing "Must" among them
+	// keeps the stronger weight for panics.
+	if strings.HasPrefix(funcName, "Must") && effectType == taxonomy.Panic {
+		mw := weightFor(cfg, "naming_must")
+		return taxonomy.Signal{
+			Source:    "naming",
+			Weight:    min(mw.Base, mw.Max),
+			Reasoning: "Must* function name implies the panic is contractual",
+		}
+	}
+
 	// Check contractual prefixes.
-	for _, cp := range contractualPrefixes {
-		if !strings.HasPrefix(funcName, cp.prefix) {
+	for _, prefix := range naming.ContractualPrefixes {
+		if prefix == "" || !strings.HasPrefix(funcName, prefix) {
 			continue
 		}
-		// nil impliesFor means all effect types match.
-		if cp.impliesFor == nil {
+		// nil implied effects means all effect types match.
+		implies := prefixEffects[prefix]
+		if implies == nil {
 			return taxonomy.Signal{
 				Source:    "naming",
-				Weight:    maxNamingWeight,
-				Reasoning: "function name prefix " + cp.prefix + "* suggests contractual behavior",
+				Weight:    namingWeight,
+				Reasoning: "function name prefix " + prefix + "* suggests contractual behavior",
 			}
 		}
-		for _, implied := range cp.impliesFor {
+		for _, implied := range implies {
 			if implied == effectType {
 				return taxonomy.Signal{
 					Source:    "naming",
-					Weight:    maxNamingWeight,
-					Reasoning: "function name prefix " + cp.prefix + "* implies " + string(effectType) + " is contractual",
+					Weight:    namingWeight,
+					Reasoning: "function name prefix " + prefix + "* implies " + string(effectType) + " is contractual",
 				}
 			}
 		}
@@ -103,13 +125,26 @@ func AnalyzeNamingSignal(funcName string, effectType taxonomy.SideEffectType) ta
 	// and exist solely to be matched by callers. Use a stronger weight so
 	// sentinels with no other signals reach the contractual threshold.
 	if strings.HasPrefix(funcName, "Err") && effectType == taxonomy.SentinelError {
+		sw := weightFor(cfg, "naming_sentinel")
 		return taxonomy.Signal{
 			Source:    "naming",
-			Weight:    sentinelNamingWeight,
+			Weight:    min(sw.Base, sw.Max),
 			Reasoning: "Err* sentinel variable name implies contractual error",
 		}
 	}
 
+	// Exported *Error types reported as sentinel-like exist to be
+	// matched with errors.As and carry the same contract.
+	if strings.HasSuffix(funcName, "Error") && ast.IsExported(funcName) &&
+		effectType == taxonomy.SentinelError {
+		sw := weightFor(cfg, "naming_sentinel")
+		return taxonomy.Signal{
+			Source:    "naming",
+			Weight:    min(sw.Base, sw.Max),
+			Reasoning: "exported *Error type name implies contractual error",
+		}
+	}
+
 	// No naming signal detected.
 	return taxonomy.Signal{}
 }
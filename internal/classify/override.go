@@ -0,0 +1,55 @@
+package classify
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/unbound-force/gaze/internal/config"
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// overrideSource is the signal source recorded when a configured
+// override forces a classification.
+const overrideSource = "override"
+
+// matchOverride returns the first override whose Function pattern
+// matches the function, or nil. A method is matched by its bare
+// name, by "Type.Method", and by its full receiver form such as
+// "(*Type).Method"; a package-level function only by its name.
+func matchOverride(overrides []config.Override, target taxonomy.FunctionTarget) *config.Override {
+	if len(overrides) == 0 || target.Function == "<package>" {
+		return nil
+	}
+	names := []string{target.Function}
+	if target.Receiver != "" {
+		typeName := strings.TrimPrefix(target.Receiver, "*")
+		if i := strings.IndexByte(typeName, '['); i >= 0 {
+			typeName = typeName[:i]
+		}
+		names = append(names, typeName+"."+target.Function, target.QualifiedName())
+	}
+	for i := range overrides {
+		for _, name := range names {
+			if ok, _ := filepath.Match(overrides[i].Function, name); ok {
+				return &overrides[i]
+			}
+		}
+	}
+	return nil
+}
+
+// applyOverride forces c to the override's label and confidence.
+// The change is recorded as an "override" signal whose weight is
+// the confidence adjustment, so the signal list still explains the
+// final score.
+func applyOverride(c *taxonomy.Classification, o *config.Override) {
+	c.Signals = append(c.Signals, taxonomy.Signal{
+		Source:    overrideSource,
+		Weight:    o.Confidence - c.Confidence,
+		Reasoning: fmt.Sprintf("function matches override %q in .gaze.yaml", o.Function),
+	})
+	c.Label = taxonomy.ClassificationLabel(o.Label)
+	c.Confidence = o.Confidence
+	c.Reasoning = fmt.Sprintf("forced %s by override %q (confidence %d)", o.Label, o.Function, o.Confidence)
+}
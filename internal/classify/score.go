@@ -3,6 +3,9 @@ package classify
 
 import (
 	"fmt"
+	"math"
+	"sort"
+	"strings"
 
 	"github.com/unbound-force/gaze/internal/config"
 	"github.com/unbound-force/gaze/internal/taxonomy"
@@ -14,7 +17,8 @@ import (
 const baseConfidence = 50
 
 // maxContradictionPenalty is the maximum penalty for contradicting
-// signals (FR-007).
+// signals (FR-007). The penalty scales with the weaker side up to
+// this cap; see contradictionPenalty.
 const maxContradictionPenalty = 20
 
 // tierBoost returns the confidence boost for a side effect based on
@@ -38,8 +42,9 @@ func tierBoost(effectType taxonomy.SideEffectType) int {
 // baseConfidence + tierBoost(effectType), skipping zero-weight/
 // empty-source signals. The effective starting score is 75 for P0,
 // 60 for P1, and 50 for P2-P4. Returns the accumulated score and
-// whether positive and negative signals were both present.
-func accumulateSignals(effectType taxonomy.SideEffectType, signals []taxonomy.Signal) (score int, hasPositive, hasNegative bool) {
+// the total weight of the positive and the negative signals, the
+// latter as a magnitude.
+func accumulateSignals(effectType taxonomy.SideEffectType, signals []taxonomy.Signal) (score, positive, negative int) {
 	score = baseConfidence + tierBoost(effectType)
 	for _, s := range signals {
 		if s.Weight == 0 && s.Source == "" {
@@ -47,69 +52,140 @@ func accumulateSignals(effectType taxonomy.SideEffectType, signals []taxonomy.Si
 		}
 		score += s.Weight
 		if s.Weight > 0 {
-			hasPositive = true
+			positive += s.Weight
 		}
 		if s.Weight < 0 {
-			hasNegative = true
+			negative -= s.Weight
 		}
 	}
-	return score, hasPositive, hasNegative
+	return score, positive, negative
 }
 
+// contradictionPenalty returns the penalty for signals whose
+// positive and negative weights total positive and negative: factor
+// times the weaker side, rounded to the nearest integer and capped
+// at maxContradictionPenalty. A +30 interface signal against a -10
+// naming signal costs 10 at the default factor of 1, while two
+// strong opposing sides cost the full 20. Returns 0 unless both
+// sides are present.
+func contradictionPenalty(positive, negative int, factor float64) int {
+	weaker := min(positive, negative)
+	if weaker <= 0 {
+		return 0
+	}
+	return min(int(math.Round(factor*float64(weaker))), maxContradictionPenalty)
+}
+
+// maxDominantSignals is the number of strongest signals named in
+// the classification reasoning.
+const maxDominantSignals = 3
+
 // classifyLabel determines the classification label and reasoning
-// string based on the score and configured thresholds.
+// string based on the score and configured thresholds. The
+// reasoning includes the margin to the nearest threshold so that
+// verbose output shows how close a classification came to flipping.
 func classifyLabel(score, contractualThreshold, incidentalThreshold int) (taxonomy.ClassificationLabel, string) {
 	switch {
 	case score >= contractualThreshold:
 		return taxonomy.Contractual, fmt.Sprintf(
-			"confidence %d >= %d (contractual threshold)",
-			score, contractualThreshold,
+			"confidence %d >= %d (contractual threshold), %d above",
+			score, contractualThreshold, score-contractualThreshold,
 		)
 	case score < incidentalThreshold:
 		return taxonomy.Incidental, fmt.Sprintf(
-			"confidence %d < %d (incidental threshold)",
-			score, incidentalThreshold,
+			"confidence %d < %d (incidental threshold), %d below",
+			score, incidentalThreshold, incidentalThreshold-score,
 		)
 	default:
+		toContractual := contractualThreshold - score
+		toIncidental := score - incidentalThreshold
+		margin := fmt.Sprintf("%d below contractual threshold %d",
+			toContractual, contractualThreshold)
+		if toIncidental < toContractual {
+			margin = fmt.Sprintf("%d above incidental threshold %d",
+				toIncidental, incidentalThreshold)
+		}
 		return taxonomy.Ambiguous, fmt.Sprintf(
-			"confidence %d in ambiguous range [%d, %d)",
-			score, incidentalThreshold, contractualThreshold,
+			"confidence %d in ambiguous range [%d, %d), %s",
+			score, incidentalThreshold, contractualThreshold, margin,
 		)
 	}
 }
 
+// dominantSignals returns a description of the strongest signals
+// by absolute weight, e.g. "interface (+30), naming (-10)". Ties
+// are broken by source name so the output is deterministic.
+// Returns "" when no signal carries weight.
+func dominantSignals(signals []taxonomy.Signal) string {
+	ranked := make([]taxonomy.Signal, 0, len(signals))
+	for _, s := range signals {
+		if s.Source != "" && s.Weight != 0 {
+			ranked = append(ranked, s)
+		}
+	}
+	if len(ranked) == 0 {
+		return ""
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		ai, aj := abs(ranked[i].Weight), abs(ranked[j].Weight)
+		if ai != aj {
+			return ai > aj
+		}
+		return ranked[i].Source < ranked[j].Source
+	})
+	if len(ranked) > maxDominantSignals {
+		ranked = ranked[:maxDominantSignals]
+	}
+
+	parts := make([]string, len(ranked))
+	for i, s := range ranked {
+		parts[i] = fmt.Sprintf("%s (%+d)", s.Source, s.Weight)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// clampConfidence limits score to the 0-100 confidence range.
+func clampConfidence(score int) int {
+	return min(max(score, 0), 100)
+}
+
+// abs returns the absolute value of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
 // ComputeScore computes the confidence score from a set of signals,
 // applies a tier-based boost, contradiction detection and penalty,
 // clamps to 0-100, and returns a Classification based on the
 // configured thresholds. The effectType determines the tier boost:
-// P0 effects start at 75, P1 at 60, P2-P4 at 50.
+// P0 effects start at 75, P1 at 60, P2-P4 at 50. The Reasoning
+// reports the margin to the nearest threshold and names the
+// dominant signal sources.
 func ComputeScore(effectType taxonomy.SideEffectType, signals []taxonomy.Signal, cfg *config.GazeConfig) taxonomy.Classification {
 	if cfg == nil {
 		cfg = config.DefaultConfig()
 	}
 
-	score, hasPositive, hasNegative := accumulateSignals(effectType, signals)
+	score, positive, negative := accumulateSignals(effectType, signals)
+	dominant := dominantSignals(signals)
 
 	// Apply contradiction penalty if both positive and negative
-	// signals exist.
-	contradictionApplied := false
-	if hasPositive && hasNegative {
-		score -= maxContradictionPenalty
-		contradictionApplied = true
+	// signals exist, scaled by the weaker side.
+	penalty := contradictionPenalty(positive, negative, cfg.Classification.ContradictionScale())
+	if penalty > 0 {
+		score -= penalty
 		signals = append(signals, taxonomy.Signal{
-			Source:    "contradiction",
-			Weight:    -maxContradictionPenalty,
-			Reasoning: "contradicting signals detected — positive and negative evidence both present",
+			Source:    contradictionSource,
+			Weight:    -penalty,
+			Reasoning: fmt.Sprintf("contradicting signals detected — positive (+%d) and negative (-%d) evidence both present", positive, negative),
 		})
 	}
 
-	// Clamp to 0-100.
-	if score < 0 {
-		score = 0
-	}
-	if score > 100 {
-		score = 100
-	}
+	score = clampConfidence(score)
 
 	label, reasoning := classifyLabel(
 		score,
@@ -117,7 +193,10 @@ func ComputeScore(effectType taxonomy.SideEffectType, signals []taxonomy.Signal,
 		cfg.Classification.Thresholds.Incidental,
 	)
 
-	if contradictionApplied {
+	if dominant != "" {
+		reasoning += "; dominant signals: " + dominant
+	}
+	if penalty > 0 {
 		reasoning += "; contradiction penalty applied"
 	}
 
@@ -0,0 +1,113 @@
+// Package classify implements the contractual classification engine.
+package classify
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/unbound-force/gaze/internal/config"
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// AnalyzeTestCallerSignal counts the test functions (TestXxx in
+// _test.go files) that reference the target function and computes
+// a weight proportional to that count. A side effect that existing
+// tests already exercise is very likely contractual.
+//
+// testPkgs must be loaded with Tests: true so that test files are
+// present in each package's Syntax. When testPkgs is empty the
+// signal is zero, leaving non-test runs unaffected. Weights come
+// from the "test_caller" entry of cfg's weight table.
+func AnalyzeTestCallerSignal(
+	funcObj types.Object,
+	_ taxonomy.SideEffectType,
+	testPkgs []*packages.Package,
+	cfg *config.GazeConfig,
+) taxonomy.Signal {
+	if funcObj == nil || len(testPkgs) == 0 {
+		return taxonomy.Signal{}
+	}
+
+	testCount := countTestCallers(funcObj, testPkgs)
+	if testCount == 0 {
+		return taxonomy.Signal{}
+	}
+
+	// With default weights: 1 test = 5, 2-3 tests = 10, 4+ = 15.
+	weight := countTierWeight(testCount, weightFor(cfg, "test_caller"))
+
+	return taxonomy.Signal{
+		Source: "test_caller",
+		Weight: weight,
+		Reasoning: fmt.Sprintf(
+			"%d test function(s) reference this function",
+			testCount,
+		),
+	}
+}
+
+// countTestCallers counts the distinct test functions across the
+// given packages whose bodies reference funcObj. Identity is
+// compared with funcKey so that objects from separate
+// packages.Load calls match. Test functions are deduplicated by
+// file position because a _test.go file may appear in more than
+// one package variant.
+func countTestCallers(funcObj types.Object, pkgs []*packages.Package) int {
+	key := funcKey(funcObj)
+	if key == "" {
+		return 0
+	}
+
+	seen := make(map[string]bool)
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil || pkg.Fset == nil {
+			continue
+		}
+		for _, file := range pkg.Syntax {
+			filename := pkg.Fset.Position(file.Pos()).Filename
+			if !strings.HasSuffix(filename, "_test.go") {
+				continue
+			}
+			for _, decl := range file.Decls {
+				fd, ok := decl.(*ast.FuncDecl)
+				if !ok || fd.Recv != nil || fd.Body == nil ||
+					!strings.HasPrefix(fd.Name.Name, "Test") {
+					continue
+				}
+				pos := pkg.Fset.Position(fd.Pos()).String()
+				if seen[pos] {
+					continue
+				}
+				if referencesKey(fd.Body, pkg.TypesInfo, key) {
+					seen[pos] = true
+				}
+			}
+		}
+	}
+
+	return len(seen)
+}
+
+// referencesKey reports whether any identifier within node resolves
+// (via info.Uses) to an object whose funcKey equals key.
+func referencesKey(node ast.Node, info *types.Info, key string) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		id, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		if obj := info.Uses[id]; obj != nil && funcKey(obj) == key {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
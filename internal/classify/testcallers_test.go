@@ -0,0 +1,95 @@
+package classify_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/unbound-force/gaze/internal/classify"
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// loadTestPackagesWithTests loads the classify testdata packages
+// with Tests: true so that _test.go files are available.
+func loadTestPackagesWithTests(t *testing.T) []*packages.Package {
+	t.Helper()
+	cfg := &packages.Config{
+		Mode: packages.NeedName |
+			packages.NeedFiles |
+			packages.NeedCompiledGoFiles |
+			packages.NeedImports |
+			packages.NeedDeps |
+			packages.NeedTypes |
+			packages.NeedSyntax |
+			packages.NeedTypesInfo |
+			packages.NeedTypesSizes,
+		Dir:   testdataDir(),
+		Tests: true,
+	}
+	pkgs, err := packages.Load(cfg, "./...")
+	if err != nil {
+		t.Fatalf("loading test packages with tests: %v", err)
+	}
+	return pkgs
+}
+
+// TestAnalyzeTestCallerSignal_CountsTestFunctions verifies that the
+// signal weight reflects the number of TestXxx functions that
+// reference the target, ignoring non-test helpers.
+func TestAnalyzeTestCallerSignal_CountsTestFunctions(t *testing.T) {
+	pkgs := loadTestPackages(t)
+	testPkgs := loadTestPackagesWithTests(t)
+	contractsPkg := findPackage(pkgs, "contracts")
+	if contractsPkg == nil {
+		t.Fatal("contracts package not found")
+	}
+
+	tests := []struct {
+		name       string
+		funcName   string
+		wantWeight int
+	}{
+		{"GetVersion 2 tests", "GetVersion", 10},
+		{"GetData only indirect", "GetData", 0},
+		{"FetchConfig no tests", "FetchConfig", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := contractsPkg.Types.Scope().Lookup(tt.funcName)
+			if obj == nil {
+				t.Fatalf("%s types.Object not found", tt.funcName)
+			}
+			sig := classify.AnalyzeTestCallerSignal(obj, taxonomy.ReturnValue, testPkgs, nil)
+			if sig.Weight != tt.wantWeight {
+				t.Errorf("weight = %d, want %d (reasoning: %q)",
+					sig.Weight, tt.wantWeight, sig.Reasoning)
+			}
+			if tt.wantWeight != 0 && sig.Source != "test_caller" {
+				t.Errorf("source = %q, want %q", sig.Source, "test_caller")
+			}
+		})
+	}
+}
+
+// TestAnalyzeTestCallerSignal_NoTestPackages verifies that the
+// signal is zero when no test packages are supplied, so runs
+// without test loading are unaffected.
+func TestAnalyzeTestCallerSignal_NoTestPackages(t *testing.T) {
+	pkgs := loadTestPackages(t)
+	contractsPkg := findPackage(pkgs, "contracts")
+	if contractsPkg == nil {
+		t.Fatal("contracts package not found")
+	}
+	obj := contractsPkg.Types.Scope().Lookup("GetVersion")
+
+	sig := classify.AnalyzeTestCallerSignal(obj, taxonomy.ReturnValue, nil, nil)
+	if sig.Source != "" || sig.Weight != 0 {
+		t.Errorf("expected zero signal, got %+v", sig)
+	}
+
+	sig = classify.AnalyzeTestCallerSignal(nil, taxonomy.ReturnValue, pkgs, nil)
+	if sig.Source != "" || sig.Weight != 0 {
+		t.Errorf("nil funcObj: expected zero signal, got %+v", sig)
+	}
+}
@@ -0,0 +1,34 @@
+package callers
+
+import (
+	"testing"
+
+	"github.com/unbound-force/gaze/internal/classify/testdata/src/contracts"
+)
+
+// TestGetVersion and TestGetVersionStable both reference
+// contracts.GetVersion, giving it 2 test callers.
+func TestGetVersion(t *testing.T) {
+	if contracts.GetVersion() == "" {
+		t.Error("empty version")
+	}
+}
+
+func TestGetVersionStable(t *testing.T) {
+	if contracts.GetVersion() != contracts.GetVersion() {
+		t.Error("version changed between calls")
+	}
+}
+
+// TestUseGetData references contracts.GetData only indirectly via
+// UseGetData, so it counts toward UseGetData, not GetData.
+func TestUseGetData(t *testing.T) {
+	if UseGetData() == nil {
+		t.Error("nil data")
+	}
+}
+
+// helperGetVersion is not a Test function and must not be counted.
+func helperGetVersion() string {
+	return contracts.GetVersion()
+}
@@ -110,6 +110,36 @@ func (rf *RemoteFetcher) Fetch(key string) ([]byte, error) {
 	return []byte(rf.BaseURL + "/" + key), nil
 }
 
+// ---- TrackedCloser (embeds io.Closer) -------------------------------------
+
+// TrackedCloser wraps an io.Closer and records whether it was
+// closed. It satisfies io.Closer only through the embedded field's
+// promoted method set; no module interface declares Close.
+type TrackedCloser struct {
+	io.Closer
+	closed bool
+}
+
+// Close marks the closer as closed and closes the wrapped value.
+// Contractual via the embedded io.Closer.
+func (tc *TrackedCloser) Close() error {
+	tc.closed = true
+	return tc.Closer.Close()
+}
+
+// ForcedCloser embeds io.Closer but declares its own Close with a
+// different signature, which shadows the promoted io.Closer.Close.
+type ForcedCloser struct {
+	io.Closer
+	forced bool
+}
+
+// Close records whether the close was forced. It does not implement
+// io.Closer.Close, so the embedded interface is no evidence.
+func (fc *ForcedCloser) Close(force bool) {
+	fc.forced = force
+}
+
 // ---- Naming-signal contractual functions ---------------------------------
 
 // GetData returns the stored data. The return value is contractual
@@ -4,36 +4,40 @@ package classify
 import (
 	"go/ast"
 	"go/types"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 
+	"github.com/unbound-force/gaze/internal/config"
 	"github.com/unbound-force/gaze/internal/taxonomy"
 )
 
-// maxVisibilityWeight is the maximum total weight for API surface
-// visibility signals.
-const maxVisibilityWeight = 20
-
-// visibilityDimensions defines the weight per visibility dimension.
-const (
-	exportedFunctionWeight = 8
-	exportedReturnWeight   = 6
-	exportedReceiverWeight = 6
-)
-
 // AnalyzeVisibilitySignal checks if the side effect is observable
 // through the exported API surface. The score is graduated: exported
 // function, exported return types, and exported receiver types each
-// contribute independently up to maxVisibilityWeight total.
+// contribute independently up to the "visibility" max weight. The
+// exported function contributes the base weight; exported return
+// and receiver types each contribute three quarters of it (8/6/6
+// with default weights).
+//
+// Exported names in a package under an internal/ directory can only
+// be used by the code that encloses that directory, so they are not
+// part of a public API. For those functions the weight is halved and
+// the reasoning says why.
 func AnalyzeVisibilitySignal(
 	funcDecl *ast.FuncDecl,
 	funcObj types.Object,
 	_ taxonomy.SideEffectType,
+	cfg *config.GazeConfig,
 ) taxonomy.Signal {
 	if funcDecl == nil || funcObj == nil {
 		return taxonomy.Signal{}
 	}
 
+	w := weightFor(cfg, "visibility")
+	exportedFunctionWeight := w.Base
+	exportedTypeWeight := w.Base * 3 / 4
+
 	weight := 0
 	var reasons []string
 
@@ -47,7 +51,7 @@ func AnalyzeVisibilitySignal(
 	if funcDecl.Type.Results != nil {
 		for _, result := range funcDecl.Type.Results.List {
 			if isExportedType(result.Type) {
-				weight += exportedReturnWeight
+				weight += exportedTypeWeight
 				reasons = append(reasons, "return type is exported")
 				break // Count once per dimension
 			}
@@ -58,14 +62,14 @@ func AnalyzeVisibilitySignal(
 	if funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 {
 		recvType := funcDecl.Recv.List[0].Type
 		if isExportedType(recvType) {
-			weight += exportedReceiverWeight
+			weight += exportedTypeWeight
 			reasons = append(reasons, "receiver type is exported")
 		}
 	}
 
 	// Clamp to max.
-	if weight > maxVisibilityWeight {
-		weight = maxVisibilityWeight
+	if weight > w.Max {
+		weight = w.Max
 	}
 
 	if weight == 0 {
@@ -77,6 +81,12 @@ func AnalyzeVisibilitySignal(
 		reasoning += " " + r + ";"
 	}
 
+	// Dimension 4: An internal package has no external consumers.
+	if funcObj.Pkg() != nil && isInternalPath(funcObj.Pkg().Path()) {
+		weight /= 2
+		reasoning += " halved because the package is internal and has no external consumers;"
+	}
+
 	return taxonomy.Signal{
 		Source:    "visibility",
 		Weight:    weight,
@@ -112,3 +122,15 @@ func isExported(name string) bool {
 	r, _ := utf8.DecodeRuneInString(name)
 	return unicode.IsUpper(r)
 }
+
+// isInternalPath reports whether the import path has an "internal"
+// element, which the go command only lets the tree rooted at its
+// parent import.
+func isInternalPath(path string) bool {
+	for _, elem := range strings.Split(path, "/") {
+		if elem == "internal" {
+			return true
+		}
+	}
+	return false
+}
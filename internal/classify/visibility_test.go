@@ -2,6 +2,7 @@ package classify_test
 
 import (
 	"go/ast"
+	"go/token"
 	"go/types"
 	"strings"
 	"testing"
@@ -63,11 +64,12 @@ func TestAnalyzeVisibilitySignal_ExportedFunction(t *testing.T) {
 		t.Fatal("GetData types.Object not found")
 	}
 
-	sig := classify.AnalyzeVisibilitySignal(funcDecl, funcObj, taxonomy.ReturnValue)
+	sig := classify.AnalyzeVisibilitySignal(funcDecl, funcObj, taxonomy.ReturnValue, nil)
 
-	// Exported function (+8), no exported return (+0), no receiver (+0) = 8.
-	if sig.Weight != 8 {
-		t.Errorf("GetData: weight = %d, want 8", sig.Weight)
+	// Exported function (+8), no exported return (+0), no receiver (+0)
+	// = 8, halved to 4 because the fixture lives under internal/.
+	if sig.Weight != 4 {
+		t.Errorf("GetData: weight = %d, want 4", sig.Weight)
 	}
 	if sig.Source != "visibility" {
 		t.Errorf("GetData: source = %q, want %q", sig.Source, "visibility")
@@ -88,7 +90,8 @@ func TestAnalyzeVisibilitySignal_ExportedReturnType(t *testing.T) {
 	}
 
 	// ComputeResult is exported, returns ExportedResult (exported type),
-	// no receiver. Weight = 8 (func) + 6 (return) = 14.
+	// no receiver. Weight = 8 (func) + 6 (return) = 14, halved to 7
+	// for the internal fixture package.
 	funcDecl := findFuncDeclInFiles(contractsPkg.Syntax, "ComputeResult", "")
 	if funcDecl == nil {
 		t.Fatal("ComputeResult func decl not found")
@@ -98,10 +101,10 @@ func TestAnalyzeVisibilitySignal_ExportedReturnType(t *testing.T) {
 		t.Fatal("ComputeResult types.Object not found")
 	}
 
-	sig := classify.AnalyzeVisibilitySignal(funcDecl, funcObj, taxonomy.ReturnValue)
+	sig := classify.AnalyzeVisibilitySignal(funcDecl, funcObj, taxonomy.ReturnValue, nil)
 
-	if sig.Weight != 14 {
-		t.Errorf("ComputeResult: weight = %d, want 14", sig.Weight)
+	if sig.Weight != 7 {
+		t.Errorf("ComputeResult: weight = %d, want 7", sig.Weight)
 	}
 	if !strings.Contains(sig.Reasoning, "return type is exported") {
 		t.Errorf("ComputeResult: reasoning %q does not contain %q",
@@ -121,7 +124,8 @@ func TestAnalyzeVisibilitySignal_ExportedReceiverType(t *testing.T) {
 
 	// (*FileStore).Save is exported, receiver FileStore is exported,
 	// returns error (builtin, not exported type).
-	// Weight = 8 (func) + 6 (receiver) = 14.
+	// Weight = 8 (func) + 6 (receiver) = 14, halved to 7 for the
+	// internal fixture package.
 	funcDecl := findFuncDeclInFiles(contractsPkg.Syntax, "Save", "FileStore")
 	if funcDecl == nil {
 		t.Fatal("FileStore.Save func decl not found")
@@ -150,10 +154,10 @@ func TestAnalyzeVisibilitySignal_ExportedReceiverType(t *testing.T) {
 		t.Fatal("Save method not found on *FileStore")
 	}
 
-	sig := classify.AnalyzeVisibilitySignal(funcDecl, saveObj, taxonomy.ReceiverMutation)
+	sig := classify.AnalyzeVisibilitySignal(funcDecl, saveObj, taxonomy.ReceiverMutation, nil)
 
-	if sig.Weight != 14 {
-		t.Errorf("FileStore.Save: weight = %d, want 14", sig.Weight)
+	if sig.Weight != 7 {
+		t.Errorf("FileStore.Save: weight = %d, want 7", sig.Weight)
 	}
 	if !strings.Contains(sig.Reasoning, "receiver type is exported") {
 		t.Errorf("FileStore.Save: reasoning %q does not contain %q",
@@ -205,12 +209,13 @@ func TestAnalyzeVisibilitySignal_WeightClamp(t *testing.T) {
 		t.Fatal("GetData types.Object not found")
 	}
 
-	sig := classify.AnalyzeVisibilitySignal(funcDecl, funcObj, taxonomy.ReturnValue)
+	sig := classify.AnalyzeVisibilitySignal(funcDecl, funcObj, taxonomy.ReturnValue, nil)
 
 	// 8 (exported func) + 6 (exported return) + 6 (exported receiver)
-	// = 20, clamped to 20.
-	if sig.Weight != 20 {
-		t.Errorf("clamped: weight = %d, want 20", sig.Weight)
+	// = 20, clamped to 20, then halved to 10 because GetData is in an
+	// internal fixture package.
+	if sig.Weight != 10 {
+		t.Errorf("clamped: weight = %d, want 10", sig.Weight)
 	}
 }
 
@@ -246,7 +251,7 @@ func TestAnalyzeVisibilitySignal_AllDimensionsInReasoning(t *testing.T) {
 		t.Fatal("GetData types.Object not found")
 	}
 
-	sig := classify.AnalyzeVisibilitySignal(funcDecl, funcObj, taxonomy.ReturnValue)
+	sig := classify.AnalyzeVisibilitySignal(funcDecl, funcObj, taxonomy.ReturnValue, nil)
 
 	expected := []string{
 		"function is exported",
@@ -291,7 +296,7 @@ func TestAnalyzeVisibilitySignal_UnexportedFunction(t *testing.T) {
 		t.Fatal("debugTrace types.Object not found")
 	}
 
-	sig := classify.AnalyzeVisibilitySignal(funcDecl, funcObj, taxonomy.LogWrite)
+	sig := classify.AnalyzeVisibilitySignal(funcDecl, funcObj, taxonomy.LogWrite, nil)
 
 	if sig.Weight != 0 {
 		t.Errorf("unexported debugTrace: weight = %d, want 0", sig.Weight)
@@ -315,7 +320,7 @@ func TestAnalyzeVisibilitySignal_NilFuncDecl(t *testing.T) {
 		t.Fatal("GetData types.Object not found")
 	}
 
-	sig := classify.AnalyzeVisibilitySignal(nil, funcObj, taxonomy.ReturnValue)
+	sig := classify.AnalyzeVisibilitySignal(nil, funcObj, taxonomy.ReturnValue, nil)
 
 	if sig.Weight != 0 {
 		t.Errorf("nil funcDecl: weight = %d, want 0", sig.Weight)
@@ -333,7 +338,7 @@ func TestAnalyzeVisibilitySignal_NilFuncObj(t *testing.T) {
 		Type: &ast.FuncType{},
 	}
 
-	sig := classify.AnalyzeVisibilitySignal(funcDecl, nil, taxonomy.ReturnValue)
+	sig := classify.AnalyzeVisibilitySignal(funcDecl, nil, taxonomy.ReturnValue, nil)
 
 	if sig.Weight != 0 {
 		t.Errorf("nil funcObj: weight = %d, want 0", sig.Weight)
@@ -353,6 +358,8 @@ func TestAnalyzeVisibilitySignal_TableDriven(t *testing.T) {
 		t.Fatal("contracts package not found")
 	}
 
+	// The fixture package is under internal/, so every weight is half
+	// the sum of its dimensions.
 	tests := []struct {
 		name            string
 		funcName        string
@@ -365,14 +372,14 @@ func TestAnalyzeVisibilitySignal_TableDriven(t *testing.T) {
 			name:            "exported func only (GetData)",
 			funcName:        "GetData",
 			effectType:      taxonomy.ReturnValue,
-			wantWeight:      8,
+			wantWeight:      4,
 			wantInReasoning: []string{"function is exported"},
 		},
 		{
 			name:       "exported func + exported return (ComputeResult)",
 			funcName:   "ComputeResult",
 			effectType: taxonomy.ReturnValue,
-			wantWeight: 14,
+			wantWeight: 7,
 			wantInReasoning: []string{
 				"function is exported",
 				"return type is exported",
@@ -382,7 +389,7 @@ func TestAnalyzeVisibilitySignal_TableDriven(t *testing.T) {
 			name:       "exported func + exported return with error (ApplyTransform)",
 			funcName:   "ApplyTransform",
 			effectType: taxonomy.ReturnValue,
-			wantWeight: 14,
+			wantWeight: 7,
 			wantInReasoning: []string{
 				"function is exported",
 				"return type is exported",
@@ -393,7 +400,7 @@ func TestAnalyzeVisibilitySignal_TableDriven(t *testing.T) {
 			funcName:   "Save",
 			recvType:   "FileStore",
 			effectType: taxonomy.ReceiverMutation,
-			wantWeight: 14,
+			wantWeight: 7,
 			wantInReasoning: []string{
 				"function is exported",
 				"receiver type is exported",
@@ -404,7 +411,7 @@ func TestAnalyzeVisibilitySignal_TableDriven(t *testing.T) {
 			funcName:   "Write",
 			recvType:   "FileStore",
 			effectType: taxonomy.ReceiverMutation,
-			wantWeight: 14,
+			wantWeight: 7,
 			wantInReasoning: []string{
 				"function is exported",
 				"receiver type is exported",
@@ -444,7 +451,7 @@ func TestAnalyzeVisibilitySignal_TableDriven(t *testing.T) {
 				t.Fatalf("%s types.Object not found", tt.funcName)
 			}
 
-			sig := classify.AnalyzeVisibilitySignal(funcDecl, funcObj, tt.effectType)
+			sig := classify.AnalyzeVisibilitySignal(funcDecl, funcObj, tt.effectType, nil)
 
 			if sig.Weight != tt.wantWeight {
 				t.Errorf("weight = %d, want %d", sig.Weight, tt.wantWeight)
@@ -458,3 +465,40 @@ func TestAnalyzeVisibilitySignal_TableDriven(t *testing.T) {
 		})
 	}
 }
+
+// TestAnalyzeVisibilitySignal_InternalPackage verifies that exported
+// functions in a package under internal/ get half the visibility
+// weight, and that the reasoning says why.
+func TestAnalyzeVisibilitySignal_InternalPackage(t *testing.T) {
+	funcDecl := &ast.FuncDecl{
+		Name: ast.NewIdent("Open"),
+		Type: &ast.FuncType{},
+	}
+	tests := []struct {
+		pkgPath      string
+		wantWeight   int
+		wantInternal bool
+	}{
+		{"example.com/lib", 8, false},
+		{"example.com/lib/internal/store", 4, true},
+		{"example.com/lib/internal", 4, true},
+		{"internal/store", 4, true},
+		{"example.com/lib/internalstore", 8, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pkgPath, func(t *testing.T) {
+			pkg := types.NewPackage(tt.pkgPath, "store")
+			funcObj := types.NewFunc(token.NoPos, pkg, "Open", types.NewSignatureType(nil, nil, nil, nil, nil, false))
+
+			sig := classify.AnalyzeVisibilitySignal(funcDecl, funcObj, taxonomy.ReturnValue, nil)
+
+			if sig.Weight != tt.wantWeight {
+				t.Errorf("weight = %d, want %d", sig.Weight, tt.wantWeight)
+			}
+			if got := strings.Contains(sig.Reasoning, "package is internal"); got != tt.wantInternal {
+				t.Errorf("reasoning %q mentions internal package = %v, want %v",
+					sig.Reasoning, got, tt.wantInternal)
+			}
+		})
+	}
+}
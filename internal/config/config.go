@@ -3,8 +3,11 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -15,44 +18,303 @@ import (
 type Thresholds struct {
 	// Contractual is the minimum confidence for the contractual
 	// label. Scores >= this value are classified as contractual.
-	Contractual int `yaml:"contractual"`
+	Contractual int `yaml:"contractual" json:"contractual"`
 
 	// Incidental is the upper bound for the incidental label.
 	// Scores < this value are classified as incidental.
-	Incidental int `yaml:"incidental"`
+	Incidental int `yaml:"incidental" json:"incidental"`
 }
 
 // DocScan defines document scanning configuration.
 type DocScan struct {
 	// Exclude is a list of glob patterns for files to exclude
 	// from document scanning.
-	Exclude []string `yaml:"exclude"`
+	Exclude []string `yaml:"exclude" json:"exclude"`
 
 	// Include is a list of glob patterns for files to include.
 	// If set, only matching files are processed, overriding the
 	// default full-repo scan.
-	Include []string `yaml:"include"`
+	Include []string `yaml:"include" json:"include"`
 
-	// Timeout is the maximum duration for document scanning.
-	Timeout time.Duration `yaml:"-"`
+	// Timeout is the maximum duration for document scanning. In
+	// YAML and JSON it is a Go duration string such as "30s".
+	Timeout time.Duration `yaml:"timeout" json:"timeout"`
+}
+
+// docScanFile is the serialized form of DocScan, with Timeout as a
+// duration string.
+type docScanFile struct {
+	Exclude []string `yaml:"exclude" json:"exclude"`
+	Include []string `yaml:"include" json:"include"`
+	Timeout string   `yaml:"timeout" json:"timeout"`
+}
+
+// MarshalYAML encodes d with Timeout as a duration string, so a
+// config read with Load can be written back unchanged.
+func (d DocScan) MarshalYAML() (any, error) {
+	return docScanFile{Exclude: d.Exclude, Include: d.Include, Timeout: d.Timeout.String()}, nil
+}
+
+// UnmarshalYAML decodes a doc_scan mapping, parsing timeout with
+// time.ParseDuration. Keys absent from the mapping keep the values
+// d already holds, so defaults survive a partial file.
+func (d *DocScan) UnmarshalYAML(node *yaml.Node) error {
+	file := docScanFile{Exclude: d.Exclude, Include: d.Include}
+	if err := node.Decode(&file); err != nil {
+		return err
+	}
+	d.Exclude, d.Include = file.Exclude, file.Include
+	if file.Timeout != "" {
+		t, err := time.ParseDuration(file.Timeout)
+		if err != nil {
+			return fmt.Errorf("doc_scan.timeout %q: %w", file.Timeout, err)
+		}
+		d.Timeout = t
+	}
+	return nil
+}
+
+// MarshalJSON encodes d like MarshalYAML, with Timeout as a
+// duration string under "timeout".
+func (d DocScan) MarshalJSON() ([]byte, error) {
+	return json.Marshal(docScanFile{Exclude: d.Exclude, Include: d.Include, Timeout: d.Timeout.String()})
+}
+
+// SignalWeight configures the weight contributed by one
+// classification signal source.
+type SignalWeight struct {
+	// Base is the weight of a single unit of evidence (e.g. one
+	// caller, one matching keyword). Must be positive.
+	Base int `yaml:"base" json:"base"`
 
-	// TimeoutStr is the string representation for YAML parsing.
-	TimeoutStr string `yaml:"timeout"`
+	// Max caps the absolute weight of the signal. Must be
+	// positive and at least Base.
+	Max int `yaml:"max" json:"max"`
 }
 
 // ClassificationConfig groups all classification-related settings.
 type ClassificationConfig struct {
 	// Thresholds defines the confidence score boundaries.
-	Thresholds Thresholds `yaml:"thresholds"`
+	Thresholds Thresholds `yaml:"thresholds" json:"thresholds"`
 
 	// DocScan defines document scanning configuration.
-	DocScan DocScan `yaml:"doc_scan"`
+	DocScan DocScan `yaml:"doc_scan" json:"doc_scan"`
+
+	// Weights maps signal source names (e.g. "caller", "naming")
+	// to their base and maximum weights. Missing or invalid entries
+	// fall back to DefaultWeights; use Weight to resolve an entry.
+	Weights map[string]SignalWeight `yaml:"weights" json:"weights"`
+
+	// Naming lists the function name prefixes consulted by the
+	// naming signal. Use Prefixes to resolve the effective lists.
+	Naming Naming `yaml:"naming" json:"naming"`
+
+	// ArchitectureDocs configures the architecture_doc signal, which
+	// treats functions named under contract headings of designated
+	// architecture documents as strongly contractual.
+	ArchitectureDocs ArchitectureDocs `yaml:"architecture_docs" json:"architecture_docs"`
+
+	// Overrides force the classification of every side effect of
+	// the matching functions, after signal scoring. The first
+	// matching entry wins.
+	Overrides []Override `yaml:"overrides" json:"overrides"`
+
+	// ContradictionFactor scales the contradiction penalty applied
+	// when positive and negative signals are both present: the
+	// penalty is the factor times the total weight of the weaker
+	// side. Use ContradictionScale to resolve the effective value.
+	ContradictionFactor float64 `yaml:"contradiction_factor" json:"contradiction_factor"`
+}
+
+// DefaultContradictionFactor is the built-in ContradictionFactor.
+const DefaultContradictionFactor = 1.0
+
+// ContradictionScale resolves the effective contradiction factor,
+// substituting DefaultContradictionFactor when ContradictionFactor
+// is not positive.
+func (c ClassificationConfig) ContradictionScale() float64 {
+	if c.ContradictionFactor <= 0 {
+		return DefaultContradictionFactor
+	}
+	return c.ContradictionFactor
+}
+
+// ArchitectureDocs configures the architecture_doc signal.
+type ArchitectureDocs struct {
+	// Paths are the architecture or ADR documents to consult,
+	// relative to the module root. Entries may be filepath.Match
+	// globs such as "docs/adr/*.md". The signal is disabled when
+	// no paths are configured.
+	Paths []string `yaml:"paths" json:"paths"`
+
+	// Headings are matched case-insensitively as substrings of the
+	// Markdown headings in each document. A function named in a
+	// section under a matching heading (at any level) earns the
+	// signal. A nil list falls back to DefaultArchitectureHeadings.
+	Headings []string `yaml:"headings" json:"headings"`
+}
+
+// DefaultArchitectureHeadings returns the built-in heading patterns
+// for the architecture_doc signal.
+func DefaultArchitectureHeadings() []string {
+	return []string{"Public API", "Contract"}
+}
+
+// ResolvedHeadings returns the effective heading patterns,
+// substituting the default when Headings is nil.
+func (a ArchitectureDocs) ResolvedHeadings() []string {
+	if a.Headings == nil {
+		return DefaultArchitectureHeadings()
+	}
+	return a.Headings
+}
+
+// Override forces a classification for the side effects of the
+// functions whose names match Function, for domain knowledge the
+// mechanical signals cannot infer (e.g. "metrics calls are always
+// incidental").
+type Override struct {
+	// Function is a filepath.Match glob matched against a function's
+	// bare name ("Handle*"), its method name qualified by the
+	// receiver type ("Metrics.*", "*.ServeHTTP"), and its full
+	// receiver form ("(*Metrics).Inc"). A match on any form selects
+	// the function.
+	Function string `yaml:"function" json:"function"`
+
+	// Label is the forced label: contractual, incidental, or
+	// ambiguous.
+	Label string `yaml:"label" json:"label"`
+
+	// Confidence is the forced confidence score, in [1, 100].
+	Confidence int `yaml:"confidence" json:"confidence"`
+}
+
+// Naming configures the function name prefixes recognized by the
+// naming signal. Prefixes are matched case-sensitively, so list
+// both forms (e.g. "log" and "Log") to cover exported and
+// unexported names. A configured list replaces the built-in one;
+// a nil list falls back to the default and an empty list disables
+// that half of the signal.
+type Naming struct {
+	// ContractualPrefixes are name prefixes that signal contractual
+	// behavior. Built-in prefixes keep the effect types they imply
+	// (e.g. Get implies only ReturnValue); any other prefix applies
+	// to every effect type.
+	ContractualPrefixes []string `yaml:"contractual_prefixes" json:"contractual_prefixes"`
+
+	// IncidentalPrefixes are name prefixes that signal incidental
+	// behavior. They are checked before ContractualPrefixes.
+	IncidentalPrefixes []string `yaml:"incidental_prefixes" json:"incidental_prefixes"`
+}
+
+// DefaultNaming returns the built-in naming prefix lists.
+func DefaultNaming() Naming {
+	return Naming{
+		ContractualPrefixes: []string{
+			"Get", "Fetch", "Load", "Read",
+			"Save", "Write", "Update", "Set",
+			"Delete", "Remove", "Handle", "Process",
+			"Compute", "Analyze", "Classify", "Parse",
+			"Build", "New", "Make",
+		},
+		IncidentalPrefixes: []string{
+			"log", "Log",
+			"debug", "Debug",
+			"trace", "Trace",
+			"print", "Print",
+		},
+	}
+}
+
+// Prefixes resolves the effective naming prefix lists, substituting
+// the default for each list that is nil.
+func (c ClassificationConfig) Prefixes() Naming {
+	n := c.Naming
+	def := DefaultNaming()
+	if n.ContractualPrefixes == nil {
+		n.ContractualPrefixes = def.ContractualPrefixes
+	}
+	if n.IncidentalPrefixes == nil {
+		n.IncidentalPrefixes = def.IncidentalPrefixes
+	}
+	return n
+}
+
+// DefaultWeights returns the built-in signal weight table. Keys are
+// signal source names; "naming_sentinel", "naming_must", and
+// "godoc_deprecated" configure the Err* sentinel naming boost, the
+// Must* panic naming boost, and the Deprecated: godoc penalty
+// respectively.
+func DefaultWeights() map[string]SignalWeight {
+	return map[string]SignalWeight{
+		"interface":              {Base: 30, Max: 30},
+		"visibility":             {Base: 8, Max: 20},
+		"caller":                 {Base: 5, Max: 15},
+		"test_caller":            {Base: 5, Max: 15},
+		"naming":                 {Base: 10, Max: 10},
+		"naming_sentinel":        {Base: 30, Max: 30},
+		"naming_must":            {Base: 25, Max: 25},
+		"godoc":                  {Base: 15, Max: 15},
+		"godoc_keyword_indirect": {Base: 5, Max: 5},
+		"godoc_deprecated":       {Base: 5, Max: 5},
+		"readme":                 {Base: 15, Max: 15},
+		"architecture_doc":       {Base: 20, Max: 20},
+	}
+}
+
+// Weight resolves the weight for the given signal source. A
+// configured entry with a non-positive Base or Max inherits that
+// field from the default; an entry whose Max is below its Base is
+// invalid and the default is returned in its place. Unknown sources
+// without a configured entry return the zero SignalWeight.
+func (c ClassificationConfig) Weight(source string) SignalWeight {
+	def := DefaultWeights()[source]
+	w, ok := c.Weights[source]
+	if !ok {
+		return def
+	}
+	if w.Base <= 0 {
+		w.Base = def.Base
+	}
+	if w.Max <= 0 {
+		w.Max = def.Max
+	}
+	if w.Max < w.Base {
+		return def
+	}
+	return w
+}
+
+// AssertionHelper registers a function that test quality analysis
+// should treat as an assertion, such as an in-house wrapper around
+// t.Errorf kept in a shared test utility package.
+type AssertionHelper struct {
+	// Function is the helper's fully qualified name: its import path
+	// and name (e.g. "github.com/acme/testutil.MustEqual"), or for a
+	// method, its receiver in the form
+	// "(*github.com/acme/testutil.Checker).Equal".
+	Function string `yaml:"function" json:"function"`
+
+	// Actual is the zero-based index of the argument holding the
+	// value under test, which is the one mapped to a side effect.
+	Actual int `yaml:"actual" json:"actual"`
+}
+
+// QualityConfig groups test quality assessment settings.
+type QualityConfig struct {
+	// AssertionHelpers are recognized as assertions in addition to
+	// the built-in stdlib, testify, gotest.tools, and go-cmp
+	// patterns.
+	AssertionHelpers []AssertionHelper `yaml:"assertion_helpers" json:"assertion_helpers"`
 }
 
 // GazeConfig is the top-level configuration loaded from .gaze.yaml.
 type GazeConfig struct {
 	// Classification holds classification-related settings.
-	Classification ClassificationConfig `yaml:"classification"`
+	Classification ClassificationConfig `yaml:"classification" json:"classification"`
+
+	// Quality holds test quality assessment settings.
+	Quality QualityConfig `yaml:"quality" json:"quality"`
 }
 
 // DefaultConfig returns a GazeConfig with sensible defaults.
@@ -76,14 +338,85 @@ func DefaultConfig() *GazeConfig {
 					"LICENSE",
 					"LICENSE.md",
 				},
-				Include:    nil,
-				Timeout:    30 * time.Second,
-				TimeoutStr: "30s",
+				Include: nil,
+				Timeout: 30 * time.Second,
 			},
+			Weights: DefaultWeights(),
+			Naming:  DefaultNaming(),
+			ArchitectureDocs: ArchitectureDocs{
+				Headings: DefaultArchitectureHeadings(),
+			},
+			ContradictionFactor: DefaultContradictionFactor,
 		},
 	}
 }
 
+// Effective returns a copy of c with every fallback resolved, so it
+// shows the values classification actually uses: the weight of each
+// signal source after Weight's defaulting, the naming prefixes from
+// Prefixes, the architecture doc headings from ResolvedHeadings, and
+// the contradiction factor from ContradictionScale.
+func (c *GazeConfig) Effective() *GazeConfig {
+	out := *c
+	cls := &out.Classification
+
+	cls.Weights = make(map[string]SignalWeight)
+	for source := range DefaultWeights() {
+		cls.Weights[source] = c.Classification.Weight(source)
+	}
+	for source := range c.Classification.Weights {
+		cls.Weights[source] = c.Classification.Weight(source)
+	}
+	cls.Naming = c.Classification.Prefixes()
+	cls.ArchitectureDocs.Headings = c.Classification.ArchitectureDocs.ResolvedHeadings()
+	cls.ContradictionFactor = c.Classification.ContradictionScale()
+	return &out
+}
+
+// FileName is the name of the Gaze configuration file.
+const FileName = ".gaze.yaml"
+
+// FindFile walks up from startDir looking for a FileName file and
+// returns the path of the first one found. The walk stops after the
+// module root (the first directory containing go.mod) or the
+// filesystem root. Returns "" if no file is found.
+func FindFile(startDir string) string {
+	dir, err := filepath.Abs(startDir)
+	if err != nil {
+		return ""
+	}
+	for {
+		candidate := filepath.Join(dir, FileName)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate
+		}
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return ""
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// Discover locates the configuration for startDir with FindFile and
+// loads it. It returns the loaded config and its path, or
+// DefaultConfig and "" if no file was found. An error is returned
+// only if a file was found but could not be loaded.
+func Discover(startDir string) (*GazeConfig, string, error) {
+	path := FindFile(startDir)
+	if path == "" {
+		return DefaultConfig(), "", nil
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		return nil, path, err
+	}
+	return cfg, path, nil
+}
+
 // Load reads a .gaze.yaml configuration file from the given path.
 // If the file does not exist, it returns DefaultConfig without error.
 // If the file exists but is invalid, it returns an error.
@@ -101,14 +434,37 @@ func Load(path string) (*GazeConfig, error) {
 		return nil, fmt.Errorf("parsing config %q: %w", path, err)
 	}
 
-	// Parse timeout string if provided.
-	if cfg.Classification.DocScan.TimeoutStr != "" {
-		d, err := time.ParseDuration(cfg.Classification.DocScan.TimeoutStr)
-		if err != nil {
-			return nil, fmt.Errorf("parsing doc_scan.timeout %q: %w",
-				cfg.Classification.DocScan.TimeoutStr, err)
+	for i, h := range cfg.Quality.AssertionHelpers {
+		if !strings.Contains(h.Function, ".") {
+			return nil, fmt.Errorf("quality.assertion_helpers[%d].function %q: want an import path and name such as \"example.com/testutil.Equal\"",
+				i, h.Function)
+		}
+		if h.Actual < 0 {
+			return nil, fmt.Errorf("quality.assertion_helpers[%d].actual %d: must not be negative", i, h.Actual)
+		}
+	}
+
+	for i, p := range cfg.Classification.ArchitectureDocs.Paths {
+		if _, err := filepath.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("classification.architecture_docs.paths[%d] %q: %w", i, p, err)
+		}
+	}
+
+	for i, o := range cfg.Classification.Overrides {
+		if o.Function == "" {
+			return nil, fmt.Errorf("classification.overrides[%d].function: must not be empty", i)
+		}
+		if _, err := filepath.Match(o.Function, ""); err != nil {
+			return nil, fmt.Errorf("classification.overrides[%d].function %q: %w", i, o.Function, err)
+		}
+		switch o.Label {
+		case "contractual", "incidental", "ambiguous":
+		default:
+			return nil, fmt.Errorf("classification.overrides[%d].label %q: must be contractual, incidental, or ambiguous", i, o.Label)
+		}
+		if o.Confidence < 1 || o.Confidence > 100 {
+			return nil, fmt.Errorf("classification.overrides[%d].confidence %d: must be in [1, 100]", i, o.Confidence)
 		}
-		cfg.Classification.DocScan.Timeout = d
 	}
 
 	return cfg, nil
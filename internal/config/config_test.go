@@ -1,9 +1,15 @@
 package config
 
 import (
+	"encoding/json"
+	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestDefaultConfig_Thresholds(t *testing.T) {
@@ -133,3 +139,355 @@ func TestLoad_IncludeOverride(t *testing.T) {
 		t.Errorf("include[1] = %q, want %q", includes[1], "README.md")
 	}
 }
+
+func TestDefaultConfig_Weights(t *testing.T) {
+	cfg := DefaultConfig()
+
+	for source, want := range DefaultWeights() {
+		got := cfg.Classification.Weight(source)
+		if got != want {
+			t.Errorf("Weight(%q) = %+v, want %+v", source, got, want)
+		}
+	}
+}
+
+func TestLoad_Weights(t *testing.T) {
+	cfg, err := Load(filepath.Join("testdata", "weights.yaml"))
+	if err != nil {
+		t.Fatalf("Load(weights) error: %v", err)
+	}
+
+	tests := []struct {
+		source string
+		want   SignalWeight
+	}{
+		// Fully specified entry overrides the default.
+		{"naming", SignalWeight{Base: 20, Max: 20}},
+		// Missing base inherits the default base.
+		{"caller", SignalWeight{Base: 5, Max: 30}},
+		// Max below base is invalid and falls back to the default.
+		{"interface", SignalWeight{Base: 30, Max: 30}},
+		// Entries absent from the file keep their defaults.
+		{"godoc", SignalWeight{Base: 15, Max: 15}},
+		// Unknown sources resolve to the zero weight.
+		{"unknown", SignalWeight{}},
+	}
+
+	for _, tt := range tests {
+		if got := cfg.Classification.Weight(tt.source); got != tt.want {
+			t.Errorf("Weight(%q) = %+v, want %+v", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestWeight_NilMap(t *testing.T) {
+	var cc ClassificationConfig
+	if got := cc.Weight("caller"); got != (SignalWeight{Base: 5, Max: 15}) {
+		t.Errorf("Weight(caller) with nil map = %+v, want default", got)
+	}
+}
+
+func TestLoad_ContradictionFactor(t *testing.T) {
+	cfg, err := Load(filepath.Join("testdata", "contradiction.yaml"))
+	if err != nil {
+		t.Fatalf("Load(contradiction) error: %v", err)
+	}
+	if got := cfg.Classification.ContradictionScale(); got != 0.5 {
+		t.Errorf("ContradictionScale() = %v, want 0.5", got)
+	}
+
+	// A missing or non-positive factor falls back to the default.
+	for _, factor := range []float64{0, -1} {
+		cc := ClassificationConfig{ContradictionFactor: factor}
+		if got := cc.ContradictionScale(); got != DefaultContradictionFactor {
+			t.Errorf("ContradictionScale() with %v = %v, want %v", factor, got, DefaultContradictionFactor)
+		}
+	}
+}
+
+func TestLoad_Naming(t *testing.T) {
+	cfg, err := Load(filepath.Join("testdata", "naming.yaml"))
+	if err != nil {
+		t.Fatalf("Load(naming) error: %v", err)
+	}
+
+	got := cfg.Classification.Prefixes()
+	// A configured list replaces the default rather than extending it.
+	if want := []string{"Get", "must", "Must", "ensure"}; !reflect.DeepEqual(got.ContractualPrefixes, want) {
+		t.Errorf("ContractualPrefixes = %v, want %v", got.ContractualPrefixes, want)
+	}
+	// An explicit empty list disables incidental prefixes.
+	if got.IncidentalPrefixes == nil || len(got.IncidentalPrefixes) != 0 {
+		t.Errorf("IncidentalPrefixes = %#v, want empty non-nil", got.IncidentalPrefixes)
+	}
+}
+
+func TestLoad_AssertionHelpers(t *testing.T) {
+	cfg, err := Load(filepath.Join("testdata", "assertion_helpers.yaml"))
+	if err != nil {
+		t.Fatalf("Load(assertion_helpers) error: %v", err)
+	}
+	want := []AssertionHelper{
+		{Function: "github.com/acme/testutil.MustEqual", Actual: 1},
+		{Function: "(*github.com/acme/testutil.Checker).Equal", Actual: 0},
+	}
+	if !reflect.DeepEqual(cfg.Quality.AssertionHelpers, want) {
+		t.Errorf("AssertionHelpers = %+v, want %+v", cfg.Quality.AssertionHelpers, want)
+	}
+
+	if _, err := Load(filepath.Join("testdata", "assertion_helpers_invalid.yaml")); err == nil {
+		t.Error("expected error for helper without an import path")
+	}
+}
+
+func TestLoad_Overrides(t *testing.T) {
+	cfg, err := Load(filepath.Join("testdata", "overrides.yaml"))
+	if err != nil {
+		t.Fatalf("Load(overrides) error: %v", err)
+	}
+	want := []Override{
+		{Function: "*.ServeHTTP", Label: "contractual", Confidence: 95},
+		{Function: "Metrics.*", Label: "incidental", Confidence: 10},
+	}
+	if !reflect.DeepEqual(cfg.Classification.Overrides, want) {
+		t.Errorf("Overrides = %+v, want %+v", cfg.Classification.Overrides, want)
+	}
+
+	for _, tt := range []struct {
+		name, yaml, want string
+	}{
+		{"empty function", "{label: incidental, confidence: 10}", "function: must not be empty"},
+		{"bad pattern", "{function: \"[\", label: incidental, confidence: 10}", "syntax error in pattern"},
+		{"bad label", "{function: Log, label: maybe, confidence: 10}", `label "maybe"`},
+		{"no confidence", "{function: Log, label: incidental}", "confidence 0: must be in [1, 100]"},
+		{"confidence too high", "{function: Log, label: contractual, confidence: 101}", "confidence 101"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), FileName)
+			writeFile(t, path, "classification:\n  overrides:\n    - "+tt.yaml+"\n")
+			if _, err := Load(path); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestLoad_ArchitectureDocs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	writeFile(t, path, "classification:\n  architecture_docs:\n    paths: [\"docs/adr/*.md\"]\n")
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	ad := cfg.Classification.ArchitectureDocs
+	if !reflect.DeepEqual(ad.Paths, []string{"docs/adr/*.md"}) {
+		t.Errorf("Paths = %v", ad.Paths)
+	}
+	if !reflect.DeepEqual(ad.ResolvedHeadings(), DefaultArchitectureHeadings()) {
+		t.Errorf("ResolvedHeadings = %v, want defaults", ad.ResolvedHeadings())
+	}
+
+	writeFile(t, path, "classification:\n  architecture_docs:\n    paths: [\"[\"]\n")
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "architecture_docs.paths[0]") {
+		t.Errorf("expected invalid path pattern error, got %v", err)
+	}
+}
+
+func TestPrefixes_Defaults(t *testing.T) {
+	var cc ClassificationConfig
+	if got := cc.Prefixes(); !reflect.DeepEqual(got, DefaultNaming()) {
+		t.Errorf("Prefixes() with nil lists = %+v, want defaults", got)
+	}
+	if got := DefaultConfig().Classification.Prefixes(); !reflect.DeepEqual(got, DefaultNaming()) {
+		t.Errorf("DefaultConfig Prefixes() = %+v, want defaults", got)
+	}
+}
+
+func TestEffective(t *testing.T) {
+	cfg, err := Load(filepath.Join("testdata", "weights.yaml"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	eff := cfg.Effective()
+
+	// Configured entries are resolved the way Weight resolves them,
+	// and unconfigured sources show their defaults.
+	for source, want := range map[string]SignalWeight{
+		"naming":    {Base: 20, Max: 20},
+		"caller":    {Base: 5, Max: 30},
+		"interface": {Base: 30, Max: 30},
+		"godoc":     {Base: 15, Max: 15},
+	} {
+		if got := eff.Classification.Weights[source]; got != want {
+			t.Errorf("Weights[%q] = %+v, want %+v", source, got, want)
+		}
+	}
+	if !reflect.DeepEqual(eff.Classification.Naming, DefaultNaming()) {
+		t.Errorf("Naming = %+v, want the defaults", eff.Classification.Naming)
+	}
+	if !reflect.DeepEqual(eff.Classification.ArchitectureDocs.Headings, DefaultArchitectureHeadings()) {
+		t.Errorf("Headings = %v, want the defaults", eff.Classification.ArchitectureDocs.Headings)
+	}
+	// The original is left alone.
+	if w := cfg.Classification.Weights["interface"]; w != (SignalWeight{Base: 40, Max: 10}) {
+		t.Errorf("Effective modified the original weights: %+v", w)
+	}
+}
+
+func TestDocScan_MarshalJSON(t *testing.T) {
+	ds := DocScan{Exclude: []string{"vendor/**"}, Timeout: 90 * time.Second}
+	data, err := json.Marshal(ds)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	want := `{"exclude":["vendor/**"],"include":null,"timeout":"1m30s"}`
+	if string(data) != want {
+		t.Errorf("Marshal = %s, want %s", data, want)
+	}
+}
+
+func TestDocScan_YAMLRoundTrip(t *testing.T) {
+	cfg, err := Load(filepath.Join("testdata", "valid.yaml"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if !strings.Contains(string(data), "timeout: 15s") {
+		t.Errorf("marshaled config should carry the timeout as a duration string:\n%s", data)
+	}
+
+	path := filepath.Join(t.TempDir(), FileName)
+	writeFile(t, path, string(data))
+	again, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load of re-marshaled config: %v", err)
+	}
+	if !reflect.DeepEqual(again.Classification.DocScan, cfg.Classification.DocScan) {
+		t.Errorf("round trip changed doc_scan: %+v, want %+v",
+			again.Classification.DocScan, cfg.Classification.DocScan)
+	}
+}
+
+func TestDocScan_UnmarshalYAML(t *testing.T) {
+	ds := DefaultConfig().Classification.DocScan
+	if err := yaml.Unmarshal([]byte("timeout: 2m\n"), &ds); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if ds.Timeout != 2*time.Minute {
+		t.Errorf("timeout = %v, want 2m", ds.Timeout)
+	}
+	if !reflect.DeepEqual(ds.Exclude, DefaultConfig().Classification.DocScan.Exclude) {
+		t.Errorf("exclude = %v, want the defaults kept", ds.Exclude)
+	}
+
+	err := yaml.Unmarshal([]byte("timeout: soon\n"), &ds)
+	if err == nil || !strings.Contains(err.Error(), `doc_scan.timeout "soon"`) {
+		t.Errorf("expected an invalid timeout error, got %v", err)
+	}
+}
+
+// writeFile creates path (and its parent directories) with content.
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindFile(t *testing.T) {
+	// Layout:
+	//   root/.gaze.yaml          (outside the module; never found)
+	//   root/mod/go.mod
+	//   root/mod/.gaze.yaml      (module-level config)
+	//   root/mod/a/b/            (package dir, no config)
+	//   root/mod/c/.gaze.yaml    (package-level override)
+	//   root/bare/mod/go.mod     (module without config)
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, FileName), "")
+	writeFile(t, filepath.Join(root, "mod", "go.mod"), "module m\n")
+	writeFile(t, filepath.Join(root, "mod", FileName), "")
+	writeFile(t, filepath.Join(root, "mod", "c", FileName), "")
+	writeFile(t, filepath.Join(root, "bare", "mod", "go.mod"), "module bare\n")
+	if err := os.MkdirAll(filepath.Join(root, "mod", "a", "b"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "bare", "mod", "pkg"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		start string
+		want  string
+	}{
+		{"walks up to module root", "mod/a/b", "mod/" + FileName},
+		{"nearest file wins", "mod/c", "mod/c/" + FileName},
+		{"module root itself", "mod", "mod/" + FileName},
+		{"stops at module root", "bare/mod/pkg", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FindFile(filepath.Join(root, tt.start))
+			want := ""
+			if tt.want != "" {
+				want = filepath.Join(root, tt.want)
+			}
+			if got != want {
+				t.Errorf("FindFile(%s) = %q, want %q", tt.start, got, want)
+			}
+		})
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "go.mod"), "module m\n")
+	writeFile(t, filepath.Join(root, FileName), "classification:\n  thresholds:\n    contractual: 70\n")
+	pkgDir := filepath.Join(root, "internal", "pkg")
+	if err := os.MkdirAll(pkgDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, path, err := Discover(pkgDir)
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	if path != filepath.Join(root, FileName) {
+		t.Errorf("path = %q, want %q", path, filepath.Join(root, FileName))
+	}
+	if cfg.Classification.Thresholds.Contractual != 70 {
+		t.Errorf("contractual = %d, want 70", cfg.Classification.Thresholds.Contractual)
+	}
+}
+
+func TestDiscover_Defaults(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "go.mod"), "module m\n")
+
+	cfg, path, err := Discover(root)
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	if path != "" {
+		t.Errorf("path = %q, want empty", path)
+	}
+	if cfg.Classification.Thresholds.Contractual != 80 {
+		t.Errorf("contractual = %d, want default 80", cfg.Classification.Thresholds.Contractual)
+	}
+}
+
+func TestDiscover_InvalidFile(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "go.mod"), "module m\n")
+	writeFile(t, filepath.Join(root, FileName), "classification: [\n")
+
+	if _, path, err := Discover(root); err == nil || path == "" {
+		t.Errorf("expected error with path for invalid config, got path=%q err=%v", path, err)
+	}
+}
@@ -2,25 +2,36 @@
 package crap
 
 import (
-	"bufio"
 	"fmt"
+	"go/token"
 	"io"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
 
 	"github.com/fzipp/gocyclo"
+
+	"github.com/unbound-force/gaze/internal/gofiles"
+	"github.com/unbound-force/gaze/internal/taxonomy"
 )
 
 // Options configures CRAP analysis.
 type Options struct {
 	// CoverProfile is the path to a coverage profile file.
-	// If empty, Gaze will generate one automatically.
+	// If empty and CoverProfiles is empty, Gaze will generate one
+	// automatically.
 	CoverProfile string
 
+	// CoverProfiles lists additional coverage profile files. All
+	// profiles (CoverProfile first, if set) are merged before
+	// coverage is computed; blocks present in several profiles are
+	// combined rather than one replacing another.
+	CoverProfiles []string
+
 	// CRAPThreshold is the threshold for flagging a function as
 	// "crappy". Default: 15.
 	CRAPThreshold float64
@@ -29,12 +40,51 @@ type Options struct {
 	// Used only when contract coverage is available.
 	GazeCRAPThreshold float64
 
-	// IgnoreGenerated excludes functions in files with
-	// "// Code generated" headers. Default: true.
+	// ComplexityThreshold and CoverageThreshold set the quadrant
+	// boundary of one axis each, independently of the other. When
+	// ComplexityThreshold is positive, a function is high-risk on
+	// the CRAP axis (Q2, Q4) when its cyclomatic complexity is at or
+	// above it, instead of when its CRAP score reaches
+	// CRAPThreshold. When CoverageThreshold is positive, a function
+	// is high-risk on the GazeCRAP axis (Q3, Q4) when its contract
+	// coverage is below it, instead of when its GazeCRAP score
+	// reaches GazeCRAPThreshold. They do not affect CRAPload or
+	// GazeCRAPload.
+	ComplexityThreshold int
+	CoverageThreshold   float64
+
+	// IgnoreGenerated excludes functions in generated files, as
+	// detected by gofiles.IsGenerated. Default: true.
 	IgnoreGenerated bool
 
+	// CoverageMode selects the coverage figure fed into the CRAP
+	// formula: CoverageLine (the default when empty),
+	// CoverageBranch, or CoverageNone. In branch mode, functions
+	// the profile has no blocks for fall back to line coverage and
+	// the summary notes the fallback. CoverageNone runs no tests and cannot be combined
+	// with cover profiles.
+	CoverageMode CoverageMode
+
+	// ExplainComplexity populates Score.ComplexityDetail with the
+	// constructs contributing to each function's complexity.
+	// Default: false.
+	ExplainComplexity bool
+
+	// IncludeUnexported includes unexported functions, and methods
+	// on unexported types, in scores and CRAPload. Default: true.
+	// When false, only exported API is scored and the summary's
+	// Filter field records the restriction.
+	IncludeUnexported bool
+
+	// Workers bounds the number of files parsed concurrently while
+	// computing complexity. Zero or negative means
+	// runtime.GOMAXPROCS(0); 1 parses one file at a time. Scores are
+	// sorted afterwards, so the report does not depend on it.
+	Workers int
+
 	// Stderr receives warnings about files that could not be parsed
-	// during coverage analysis. If nil, warnings are suppressed.
+	// during complexity or coverage analysis. If nil, warnings are
+	// suppressed.
 	Stderr io.Writer
 
 	// ContractCoverageFunc is an optional function that returns
@@ -44,6 +94,23 @@ type Options struct {
 	// If nil, GazeCRAP fields remain unavailable (FR-015).
 	ContractCoverageFunc func(pkg, function string) (ContractCoverageInfo, bool)
 
+	// GazeTiers selects the tiers of contractual effects that count
+	// toward the contract coverage fed into GazeFormula. Default (nil):
+	// P0 and P1. Effects in other tiers are left out of both the
+	// asserted count and the denominator, so a function whose
+	// contractual effects all fall outside these tiers (say, only P4
+	// effects) has trivially complete contract coverage. Applies only
+	// when ContractCoverageInfo.Tiers is populated; otherwise the
+	// callback's Percentage is used as is.
+	GazeTiers []taxonomy.Tier
+
+	// AmbiguousPolicy selects how ambiguous effects count toward
+	// the contract coverage fed into GazeFormula: AmbiguousIgnore
+	// (the default when empty), AmbiguousContractual, or
+	// AmbiguousIncidental. AmbiguousContractual applies only when
+	// ContractCoverageInfo.Ambiguous is populated.
+	AmbiguousPolicy AmbiguousPolicy
+
 	// SSADegradedPackages lists package paths where SSA construction
 	// failed during quality analysis. Propagated to Summary so the
 	// CRAP JSON output indicates which packages have partial data.
@@ -73,15 +140,146 @@ type ContractCoverageInfo struct {
 	// MaxConfidence is the highest classification confidence across
 	// all side effects. Zero if no effects.
 	MaxConfidence int
+
+	// Tiers breaks the function's contractual effects down by tier,
+	// for restricting contract coverage to Options.GazeTiers. Nil
+	// when the breakdown is unavailable.
+	Tiers map[taxonomy.Tier]TierCoverage
+
+	// Ambiguous breaks the function's ambiguous effects down by
+	// tier, counting those asserted on anyway, for
+	// Options.AmbiguousPolicy. Nil when the breakdown is unavailable.
+	Ambiguous map[taxonomy.Tier]TierCoverage
+
+	// SideEffectCount is the number of side effects detected in the
+	// function, or nil if it was not analyzed. Unlike the other
+	// fields it is used even when ContractCoverageFunc reports no
+	// contract coverage, and populates Score.SideEffectCount.
+	SideEffectCount *int
+}
+
+// TierCoverage counts a function's contractual (or, in
+// ContractCoverageInfo.Ambiguous, ambiguous) effects in one tier.
+type TierCoverage struct {
+	// Asserted is the number of those effects asserted on by at
+	// least one test.
+	Asserted int
+
+	// Total is the number of contractual effects in the tier.
+	Total int
 }
 
+// percentage returns the contract coverage restricted to tiers,
+// counting ambiguous effects as policy directs, or Percentage when
+// info has no tier breakdown. Coverage is 100 when no counted effect
+// falls in tiers, or when policy is AmbiguousIncidental and every
+// effect is ambiguous.
+func (info ContractCoverageInfo) percentage(tiers []taxonomy.Tier, policy AmbiguousPolicy) float64 {
+	countAmbiguous := policy == AmbiguousContractual && info.Ambiguous != nil
+	if info.Tiers == nil && !countAmbiguous {
+		if policy == AmbiguousIncidental && info.Reason == reasonAllAmbiguous {
+			return 100
+		}
+		return info.Percentage
+	}
+	var asserted, total int
+	for _, t := range tiers {
+		asserted += info.Tiers[t].Asserted
+		total += info.Tiers[t].Total
+		if countAmbiguous {
+			asserted += info.Ambiguous[t].Asserted
+			total += info.Ambiguous[t].Total
+		}
+	}
+	if total == 0 {
+		return 100
+	}
+	return float64(asserted) * 100.0 / float64(total)
+}
+
+// reasonAllAmbiguous is the ContractCoverageInfo.Reason for a
+// function whose effects are all classified ambiguous.
+const reasonAllAmbiguous = "all_effects_ambiguous"
+
 // DefaultOptions returns options with sensible defaults.
 func DefaultOptions() Options {
 	return Options{
 		CRAPThreshold:     15,
 		GazeCRAPThreshold: 15,
 		IgnoreGenerated:   true,
+		IncludeUnexported: true,
+		GazeTiers:         []taxonomy.Tier{taxonomy.TierP0, taxonomy.TierP1},
+	}
+}
+
+// gazeTiers returns GazeTiers, or P0 and P1 when it is nil.
+func (o Options) gazeTiers() []taxonomy.Tier {
+	if o.GazeTiers == nil {
+		return []taxonomy.Tier{taxonomy.TierP0, taxonomy.TierP1}
+	}
+	return o.GazeTiers
+}
+
+// coverProfilePaths returns CoverProfile (if set) followed by
+// CoverProfiles, as a new slice.
+func (o Options) coverProfilePaths() []string {
+	var paths []string
+	if o.CoverProfile != "" {
+		paths = append(paths, o.CoverProfile)
+	}
+	return append(paths, o.CoverProfiles...)
+}
+
+// quadrant assigns a function to a quadrant. Each axis uses
+// ComplexityThreshold or CoverageThreshold when set, and the score
+// threshold otherwise.
+func (o Options) quadrant(complexity int, crapScore, contractCoverage, gazeCRAP float64) Quadrant {
+	highCRAP := crapScore >= o.CRAPThreshold
+	if o.ComplexityThreshold > 0 {
+		highCRAP = complexity >= o.ComplexityThreshold
+	}
+	highGazeCRAP := gazeCRAP >= o.GazeCRAPThreshold
+	if o.CoverageThreshold > 0 {
+		highGazeCRAP = contractCoverage < o.CoverageThreshold
 	}
+	return quadrantOf(highCRAP, highGazeCRAP)
+}
+
+// Validate reports whether the options are usable, returning an
+// error describing the first invalid field.
+func (o Options) Validate() error {
+	switch o.CoverageMode {
+	case "", CoverageLine, CoverageBranch:
+	case CoverageNone:
+		if len(o.coverProfilePaths()) > 0 {
+			return fmt.Errorf("coverage mode %q cannot be combined with a cover profile", CoverageNone)
+		}
+	default:
+		return fmt.Errorf("invalid coverage mode %q: must be %q, %q, or %q",
+			o.CoverageMode, CoverageLine, CoverageBranch, CoverageNone)
+	}
+	if o.ComplexityThreshold < 0 {
+		return fmt.Errorf("invalid complexity threshold %d: must not be negative",
+			o.ComplexityThreshold)
+	}
+	if o.CoverageThreshold < 0 || o.CoverageThreshold > 100 {
+		return fmt.Errorf("invalid coverage threshold %g: must be between 0 and 100",
+			o.CoverageThreshold)
+	}
+	switch o.AmbiguousPolicy {
+	case "", AmbiguousIgnore, AmbiguousContractual, AmbiguousIncidental:
+	default:
+		return fmt.Errorf("invalid ambiguous policy %q: must be %q, %q, or %q",
+			o.AmbiguousPolicy, AmbiguousContractual, AmbiguousIncidental, AmbiguousIgnore)
+	}
+	for _, t := range o.GazeTiers {
+		switch t {
+		case taxonomy.TierP0, taxonomy.TierP1, taxonomy.TierP2, taxonomy.TierP3, taxonomy.TierP4:
+		default:
+			return fmt.Errorf("invalid GazeCRAP tier %q: must be P0 through P4", t)
+		}
+	}
+	return nil
 }
 
 // Analyze computes CRAP scores for all functions in the given
@@ -92,25 +290,32 @@ func Analyze(patterns []string, moduleDir string, opts Options) (*Report, error)
 	if opts.CRAPThreshold <= 0 {
 		opts.CRAPThreshold = 15
 	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
 
-	// Step 1: Generate coverage profile if not provided.
-	coverProfile := opts.CoverProfile
-	if coverProfile == "" {
-		var err error
-		coverProfile, err = generateCoverProfile(moduleDir, patterns)
+	// Step 1: Generate coverage profile if not provided (and not in
+	// complexity-only mode, which runs no tests).
+	coverProfiles := opts.coverProfilePaths()
+	if len(coverProfiles) == 0 && opts.CoverageMode != CoverageNone {
+		coverProfile, err := generateCoverProfile(moduleDir, patterns)
 		if err != nil {
 			return nil, fmt.Errorf("generating coverage: %w", err)
 		}
 		defer func() { _ = os.Remove(coverProfile) }()
+		coverProfiles = []string{coverProfile}
 	} else {
-		// Validate user-supplied cover profile path.
-		coverProfile = filepath.Clean(coverProfile)
-		info, err := os.Stat(coverProfile)
-		if err != nil {
-			return nil, fmt.Errorf("cover profile %q: %w", coverProfile, err)
-		}
-		if info.IsDir() {
-			return nil, fmt.Errorf("cover profile %q is a directory, not a file", coverProfile)
+		// Validate user-supplied cover profile paths.
+		for i, coverProfile := range coverProfiles {
+			coverProfile = filepath.Clean(coverProfile)
+			info, err := os.Stat(coverProfile)
+			if err != nil {
+				return nil, fmt.Errorf("cover profile %q: %w", coverProfile, err)
+			}
+			if info.IsDir() {
+				return nil, fmt.Errorf("cover profile %q is a directory, not a file", coverProfile)
+			}
+			coverProfiles[i] = coverProfile
 		}
 	}
 
@@ -120,29 +325,90 @@ func Analyze(patterns []string, moduleDir string, opts Options) (*Report, error)
 		return nil, fmt.Errorf("resolving patterns: %w", err)
 	}
 
-	complexityStats := gocyclo.Analyze(absPaths, testFileRegexp)
+	complexityStats := gatherComplexity(absPaths, testFileRegexp, opts.Workers, opts.Stderr)
 
 	// Step 3: Parse coverage profile for per-function coverage.
-	funcCoverages, err := ParseCoverProfile(coverProfile, moduleDir, opts.Stderr)
-	if err != nil {
-		return nil, fmt.Errorf("parsing coverage profile: %w", err)
+	var funcCoverages []FuncCoverage
+	if opts.CoverageMode != CoverageNone {
+		funcCoverages, err = ParseCoverProfiles(coverProfiles, moduleDir, opts.Stderr)
+		if err != nil {
+			return nil, fmt.Errorf("parsing coverage profile: %w", err)
+		}
 	}
 
 	// Step 4: Build coverage lookup map (file:line → coverage).
 	coverMap := buildCoverMap(funcCoverages)
 
 	// Step 5: Join complexity with coverage and compute CRAP.
-	scores := computeScores(complexityStats, coverMap, opts)
+	scores := computeScores(complexityStats, coverMap, opts, importPathFunc(moduleDir))
+	sortScores(scores)
+	if opts.ExplainComplexity {
+		attachComplexityDetails(scores)
+	}
 
 	// Step 6: Build summary.
 	summary := buildSummary(scores, opts)
 
 	return &Report{
-		Scores:  scores,
-		Summary: summary,
+		Scores:           scores,
+		Summary:          summary,
+		PackageSummaries: buildPackageSummaries(scores, moduleDir, opts),
 	}, nil
 }
 
+// buildPackageSummaries groups scores by package directory and
+// summarizes each group. Returns nil when there are no scores.
+func buildPackageSummaries(scores []Score, moduleDir string, opts Options) map[string]Summary {
+	if len(scores) == 0 {
+		return nil
+	}
+	groups := make(map[string][]Score)
+	for _, s := range scores {
+		key := packageKey(s.File, moduleDir)
+		groups[key] = append(groups[key], s)
+	}
+	summaries := make(map[string]Summary, len(groups))
+	for key, group := range groups {
+		summary := buildSummary(group, opts)
+		summary.RecommendedActions = nil
+		summaries[key] = summary
+	}
+	return summaries
+}
+
+// packageKey returns the slash-separated directory of file relative
+// to moduleDir, or the absolute directory if file lies outside it.
+func packageKey(file, moduleDir string) string {
+	dir := filepath.Dir(file)
+	if rel, err := filepath.Rel(moduleDir, dir); err == nil && !strings.HasPrefix(rel, "..") {
+		dir = rel
+	}
+	return filepath.ToSlash(dir)
+}
+
+// importPathFunc returns a function mapping a source file under
+// moduleDir to its package import path: the module path from go.mod
+// joined with the file's directory relative to moduleDir. Files
+// outside the module, or a module without a readable go.mod, fall
+// back to the slash-separated directory, which is still unique per
+// package. Results are cached per directory.
+func importPathFunc(moduleDir string) func(file string) string {
+	modPath := readModulePath(moduleDir)
+	cache := make(map[string]string)
+	return func(file string) string {
+		dir := filepath.Dir(file)
+		if p, ok := cache[dir]; ok {
+			return p
+		}
+		p := filepath.ToSlash(dir)
+		if rel, err := filepath.Rel(moduleDir, dir); err == nil && modPath != "" && !strings.HasPrefix(rel, "..") {
+			p = path.Join(modPath, filepath.ToSlash(rel))
+		}
+		cache[dir] = p
+		return p
+	}
+}
+
 // generateCoverProfile runs go test to produce a coverage profile.
 // The profile is written to a temporary file to avoid clobbering
 // any existing cover.out in the user's working directory.
@@ -204,10 +470,15 @@ type coverKey struct {
 }
 
 // coverMaps holds both exact-path and basename-based coverage
-// lookup maps for O(1) access in both cases.
+// lookup maps for O(1) access in both cases. The branch maps hold
+// block-based coverage for functions the profile has blocks for;
+// other functions are absent.
 type coverMaps struct {
 	exact    map[coverKey]float64
 	basename map[coverKey]float64
+
+	branchExact    map[coverKey]float64
+	branchBasename map[coverKey]float64
 }
 
 // buildCoverMap creates lookup maps from (file, startLine) to
@@ -216,11 +487,24 @@ type coverMaps struct {
 func buildCoverMap(coverages []FuncCoverage) coverMaps {
 	exact := make(map[coverKey]float64, len(coverages))
 	base := make(map[coverKey]float64, len(coverages))
+	branchExact := make(map[coverKey]float64)
+	branchBase := make(map[coverKey]float64)
 	for _, fc := range coverages {
-		exact[coverKey{file: fc.File, line: fc.StartLine}] = fc.Percentage
-		base[coverKey{file: filepath.Base(fc.File), line: fc.StartLine}] = fc.Percentage
+		exactKey := coverKey{file: fc.File, line: fc.StartLine}
+		baseKey := coverKey{file: filepath.Base(fc.File), line: fc.StartLine}
+		exact[exactKey] = fc.Percentage
+		base[baseKey] = fc.Percentage
+		if fc.HasBranchData() {
+			branchExact[exactKey] = fc.BranchPercentage()
+			branchBase[baseKey] = fc.BranchPercentage()
+		}
+	}
+	return coverMaps{
+		exact:          exact,
+		basename:       base,
+		branchExact:    branchExact,
+		branchBasename: branchBase,
 	}
-	return coverMaps{exact: exact, basename: base}
 }
 
 // lookupCoverage finds the coverage for a gocyclo Stat by matching
@@ -242,14 +526,30 @@ func lookupCoverage(stat gocyclo.Stat, maps coverMaps) float64 {
 	return 0
 }
 
+// lookupBranchCoverage finds the block-based coverage for a gocyclo
+// Stat, matching like lookupCoverage. ok is false when the profile
+// had no blocks for the function.
+func lookupBranchCoverage(stat gocyclo.Stat, maps coverMaps) (pct float64, ok bool) {
+	key := coverKey{file: stat.Pos.Filename, line: stat.Pos.Line}
+	if pct, ok := maps.branchExact[key]; ok {
+		return pct, true
+	}
+	baseKey := coverKey{file: filepath.Base(stat.Pos.Filename), line: stat.Pos.Line}
+	pct, ok = maps.branchBasename[baseKey]
+	return pct, ok
+}
+
 // computeScores joins cyclomatic complexity stats with coverage data
 // and computes CRAP scores for each non-skipped function. Test files
 // and generated files (when opts.IgnoreGenerated is true) are
 // excluded. If opts.ContractCoverageFunc is set, GazeCRAP scores,
 // contract coverage percentages, and quadrant classifications are
 // computed for each function where the callback returns data.
-func computeScores(stats []gocyclo.Stat, coverMap coverMaps, opts Options) []Score {
+// importPath maps a source file to its package import path, from
+// which each score's ID is derived.
+func computeScores(stats []gocyclo.Stat, coverMap coverMaps, opts Options, importPath func(file string) string) []Score {
 	generatedCache := make(map[string]bool)
+	gazeTiers := opts.gazeTiers()
 	var scores []Score
 
 	for _, stat := range stats {
@@ -263,7 +563,7 @@ func computeScores(stats []gocyclo.Stat, coverMap coverMaps, opts Options) []Sco
 		if opts.IgnoreGenerated {
 			gen, ok := generatedCache[stat.Pos.Filename]
 			if !ok {
-				gen = isGeneratedFile(stat.Pos.Filename)
+				gen = gofiles.IsGenerated(stat.Pos.Filename)
 				generatedCache[stat.Pos.Filename] = gen
 			}
 			if gen {
@@ -271,10 +571,27 @@ func computeScores(stats []gocyclo.Stat, coverMap coverMaps, opts Options) []Sco
 			}
 		}
 
+		// Skip unexported functions when configured.
+		if !opts.IncludeUnexported && !isExportedFunc(stat.FuncName) {
+			continue
+		}
+
 		covPct := lookupCoverage(stat, coverMap)
-		crapScore := Formula(stat.Complexity, covPct)
+		crapCov := covPct
+		var branchCov *float64
+		if opts.CoverageMode == CoverageBranch {
+			if pct, ok := lookupBranchCoverage(stat, coverMap); ok {
+				branchCov = &pct
+				crapCov = pct
+			}
+		}
+		crapScore := Formula(stat.Complexity, crapCov)
+		if opts.CoverageMode == CoverageNone {
+			crapScore = float64(stat.Complexity)
+		}
 
 		score := Score{
+			ID:           GenerateFunctionID(importPath(stat.Pos.Filename), stat.FuncName),
 			Package:      stat.PkgName,
 			Function:     stat.FuncName,
 			File:         stat.Pos.Filename,
@@ -283,38 +600,89 @@ func computeScores(stats []gocyclo.Stat, coverMap coverMaps, opts Options) []Sco
 			LineCoverage: covPct,
 			CRAP:         crapScore,
 		}
+		score.BranchCoverage = branchCov
 
 		// Compute GazeCRAP if contract coverage is available.
 		if opts.ContractCoverageFunc != nil {
 			ccInfo, ok := opts.ContractCoverageFunc(stat.PkgName, stat.FuncName)
+			score.SideEffectCount = ccInfo.SideEffectCount
 			if ok {
-				gazeCRAP := Formula(stat.Complexity, ccInfo.Percentage)
-				quadrant := ClassifyQuadrant(
-					crapScore, gazeCRAP,
-					opts.CRAPThreshold, opts.GazeCRAPThreshold,
-				)
-				pct := ccInfo.Percentage
+				pct := ccInfo.percentage(gazeTiers, opts.AmbiguousPolicy)
+				gazeCRAP := GazeFormula(stat.Complexity, pct)
+				quadrant := opts.quadrant(stat.Complexity, crapScore, pct, gazeCRAP)
 				score.ContractCoverage = &pct
 				score.GazeCRAP = &gazeCRAP
 				score.Quadrant = &quadrant
 
+				// A policy other than ignore settles the ambiguous
+				// effects, so they no longer explain the coverage.
+				if ccInfo.Reason == reasonAllAmbiguous && opts.AmbiguousPolicy != "" && opts.AmbiguousPolicy != AmbiguousIgnore {
+					ccInfo.Reason = ""
+				}
 				if ccInfo.Reason != "" {
 					score.ContractCoverageReason = &ccInfo.Reason
 				}
-				if ccInfo.Reason == "all_effects_ambiguous" {
+				if ccInfo.Reason == reasonAllAmbiguous {
 					r := [2]int{ccInfo.MinConfidence, ccInfo.MaxConfidence}
 					score.EffectConfidenceRange = &r
 				}
 			}
 		}
 
-		score.FixStrategy = assignFixStrategy(score, opts.CRAPThreshold)
+		if opts.CoverageMode == CoverageNone {
+			// Coverage is unknown, so complexity is the only
+			// actionable signal.
+			if crapScore >= opts.CRAPThreshold {
+				fs := FixDecompose
+				score.FixStrategy = &fs
+			}
+		} else {
+			score.FixStrategy = assignFixStrategy(score, opts.CRAPThreshold)
+		}
 		scores = append(scores, score)
 	}
 
 	return scores
 }
 
+// sortScores orders scores by CRAP descending, breaking ties by
+// function name, then file and line, so that reports are identical
+// however complexity gathering was scheduled.
+func sortScores(scores []Score) {
+	sort.Slice(scores, func(i, j int) bool {
+		a, b := scores[i], scores[j]
+		if a.CRAP != b.CRAP {
+			return a.CRAP > b.CRAP
+		}
+		if a.Function != b.Function {
+			return a.Function < b.Function
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+}
+
+// isExportedFunc reports whether a gocyclo function name denotes
+// exported API: an exported function, or an exported method on an
+// exported receiver type. Method names have the form "(T).M" or
+// "(*T).M".
+func isExportedFunc(funcName string) bool {
+	if !strings.HasPrefix(funcName, "(") {
+		return token.IsExported(funcName)
+	}
+	end := strings.Index(funcName, ").")
+	if end < 0 {
+		return false
+	}
+	recv := strings.TrimPrefix(funcName[1:end], "*")
+	if i := strings.IndexByte(recv, '['); i >= 0 {
+		recv = recv[:i]
+	}
+	return token.IsExported(recv) && token.IsExported(funcName[end+2:])
+}
+
 // assignFixStrategy determines the recommended remediation action
 // for a function based on its CRAP score, complexity, coverage, and
 // quadrant. Returns nil for functions below the CRAP threshold.
@@ -350,41 +718,73 @@ func assignFixStrategy(s Score, crapThreshold float64) *FixStrategy {
 // testFileRegexp matches Go test files by suffix.
 var testFileRegexp = regexp.MustCompile(`_test\.go$`)
 
-// generatedRegexp matches the Go convention for generated file headers:
-// "^// Code generated .* DO NOT EDIT\.$"
-var generatedRegexp = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
-
-// isGeneratedFile checks whether a Go source file was auto-generated
-// by looking for a "// Code generated ... DO NOT EDIT." comment line
-// before the package clause, per the Go convention.
-func isGeneratedFile(path string) bool {
-	f, err := os.Open(path)
-	if err != nil {
-		return false
+// summaryFilter returns the Summary.Filter value describing which
+// functions opts admits, or "" when no filter applies.
+func summaryFilter(opts Options) string {
+	if !opts.IncludeUnexported {
+		return FilterExportedOnly
 	}
-	defer func() { _ = f.Close() }()
+	return ""
+}
 
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		line := scanner.Text()
-		trimmed := strings.TrimSpace(line)
-		// Stop scanning once we reach the package clause.
-		if strings.HasPrefix(trimmed, "package ") {
-			return false
-		}
-		if generatedRegexp.MatchString(trimmed) {
-			return true
+// setCoverageMode records the coverage figure CRAP was computed
+// from when branch or none mode was requested, with a note for
+// functions that fell back to line coverage or for skipped tests.
+// Line mode leaves the summary unchanged.
+func setCoverageMode(summary *Summary, scores []Score, opts Options) {
+	if opts.CoverageMode == CoverageNone {
+		summary.CoverageMode = CoverageNone
+		summary.CoverageNote = "tests not run; coverage unknown and CRAP shows complexity only"
+		return
+	}
+	if opts.CoverageMode != CoverageBranch {
+		return
+	}
+	fallback := 0
+	for _, s := range scores {
+		if s.BranchCoverage == nil {
+			fallback++
 		}
 	}
-	return false
+	switch {
+	case len(scores) > 0 && fallback == len(scores):
+		summary.CoverageMode = CoverageLine
+		summary.CoverageNote = "branch coverage requested but the coverage profile has no " +
+			"blocks for the analyzed functions; CRAP uses line coverage"
+	case fallback > 0:
+		summary.CoverageMode = CoverageBranch
+		summary.CoverageNote = fmt.Sprintf(
+			"%d function(s) have no coverage blocks and use line coverage", fallback)
+	default:
+		summary.CoverageMode = CoverageBranch
+	}
+}
+
+// summaryQuadrantThresholds records the boundaries quadrant counts
+// were computed with.
+func summaryQuadrantThresholds(opts Options) *QuadrantThresholds {
+	qt := &QuadrantThresholds{
+		Complexity: opts.ComplexityThreshold,
+		Coverage:   opts.CoverageThreshold,
+	}
+	if opts.ComplexityThreshold == 0 {
+		qt.CRAP = opts.CRAPThreshold
+	}
+	if opts.CoverageThreshold == 0 {
+		qt.GazeCRAP = opts.GazeCRAPThreshold
+	}
+	return qt
 }
 
 // buildSummary computes aggregate statistics from the scores.
 func buildSummary(scores []Score, opts Options) Summary {
 	if len(scores) == 0 {
-		return Summary{
+		summary := Summary{
 			CRAPThreshold: opts.CRAPThreshold,
+			Filter:        summaryFilter(opts),
 		}
+		setCoverageMode(&summary, scores, opts)
+		return summary
 	}
 
 	var totalComp, totalCov, totalCRAP float64
@@ -443,7 +843,9 @@ func buildSummary(scores []Score, opts Options) Summary {
 		CRAPload:        crapload,
 		CRAPThreshold:   opts.CRAPThreshold,
 		WorstCRAP:       worst,
+		Filter:          summaryFilter(opts),
 	}
+	setCoverageMode(&summary, scores, opts)
 
 	if len(fixStrategyCounts) > 0 {
 		summary.FixStrategyCounts = fixStrategyCounts
@@ -491,6 +893,7 @@ func buildSummary(scores []Score, opts Options) Summary {
 		summary.GazeCRAPload = &gazeCRAPload
 		summary.GazeCRAPThreshold = &opts.GazeCRAPThreshold
 		summary.QuadrantCounts = quadrantCounts
+		summary.QuadrantThresholds = summaryQuadrantThresholds(opts)
 
 		avgGazeCRAP := totalGazeCRAP / float64(gazeCRAPCount)
 		summary.AvgGazeCRAP = &avgGazeCRAP
@@ -5,9 +5,13 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/fzipp/gocyclo"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
 )
 
 // makeStat constructs a gocyclo.Stat for testing.
@@ -20,6 +24,10 @@ func makeStat(pkg, fn, file string, line, complexity int) gocyclo.Stat {
 	}
 }
 
+// testImportPath resolves fixture files under /src, which has no
+// go.mod, to their directory.
+var testImportPath = importPathFunc("/src")
+
 // makeCoverMap constructs a coverMaps for testing from file:line -> pct pairs.
 func makeCoverMap(entries map[coverKey]float64) coverMaps {
 	base := make(map[coverKey]float64, len(entries))
@@ -38,7 +46,7 @@ func TestComputeScores_BasicCRAP(t *testing.T) {
 	})
 	opts := DefaultOptions()
 
-	scores := computeScores(stats, cm, opts)
+	scores := computeScores(stats, cm, opts, testImportPath)
 
 	if len(scores) != 1 {
 		t.Fatalf("expected 1 score, got %d", len(scores))
@@ -62,6 +70,77 @@ func TestComputeScores_BasicCRAP(t *testing.T) {
 	}
 }
 
+func TestComputeScores_StableID(t *testing.T) {
+	// The ID must not change when the function moves.
+	cm := makeCoverMap(map[coverKey]float64{})
+	before := computeScores([]gocyclo.Stat{makeStat("pkg", "(*T).Foo", "/src/foo.go", 10, 3)}, cm, DefaultOptions(), testImportPath)
+	after := computeScores([]gocyclo.Stat{makeStat("pkg", "(*T).Foo", "/src/bar.go", 42, 3)}, cm, DefaultOptions(), testImportPath)
+
+	if before[0].ID == "" {
+		t.Fatal("expected non-empty ID")
+	}
+	if before[0].ID != after[0].ID {
+		t.Errorf("expected ID to survive moves, got %s and %s", before[0].ID, after[0].ID)
+	}
+	if before[0].ID != GenerateFunctionID("/src", "(*T).Foo") {
+		t.Errorf("expected ID from GenerateFunctionID, got %s", before[0].ID)
+	}
+}
+
+func TestComputeScores_IDDistinguishesSameNamedPackages(t *testing.T) {
+	// Two packages both named util must not share IDs.
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/m\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stats := []gocyclo.Stat{
+		makeStat("util", "Run", filepath.Join(dir, "a", "util", "util.go"), 3, 1),
+		makeStat("util", "Run", filepath.Join(dir, "b", "util", "util.go"), 3, 1),
+		makeStat("main", "run", filepath.Join(dir, "cmd", "x", "main.go"), 3, 1),
+		makeStat("main", "run", filepath.Join(dir, "cmd", "y", "main.go"), 3, 1),
+	}
+	opts := DefaultOptions()
+	opts.IncludeUnexported = true
+	scores := computeScores(stats, makeCoverMap(map[coverKey]float64{}), opts, importPathFunc(dir))
+
+	if len(scores) != 4 {
+		t.Fatalf("expected 4 scores, got %d", len(scores))
+	}
+	seen := make(map[string]string)
+	for _, s := range scores {
+		if prev, ok := seen[s.ID]; ok {
+			t.Errorf("ID %s shared by %s and %s", s.ID, prev, s.File)
+		}
+		seen[s.ID] = s.File
+	}
+	if want := GenerateFunctionID("example.com/m/a/util", "Run"); scores[0].ID != want {
+		t.Errorf("expected ID from import path example.com/m/a/util (%s), got %s", want, scores[0].ID)
+	}
+}
+
+func TestComputeScores_NoTestsMode(t *testing.T) {
+	stats := []gocyclo.Stat{
+		makeStat("pkg", "Simple", "/src/foo.go", 10, 3),
+		makeStat("pkg", "Complex", "/src/foo.go", 20, 16),
+	}
+	opts := DefaultOptions()
+	opts.CoverageMode = CoverageNone
+
+	scores := computeScores(stats, makeCoverMap(map[coverKey]float64{}), opts, testImportPath)
+
+	if scores[0].CRAP != 3 || scores[0].FixStrategy != nil {
+		t.Errorf("Simple: expected CRAP 3 with no fix strategy, got %.1f / %v",
+			scores[0].CRAP, scores[0].FixStrategy)
+	}
+	if scores[1].CRAP != 16 {
+		t.Errorf("Complex: expected CRAP 16, got %.1f", scores[1].CRAP)
+	}
+	// Coverage is unknown, so decompose rather than decompose_and_test.
+	if scores[1].FixStrategy == nil || *scores[1].FixStrategy != FixDecompose {
+		t.Errorf("Complex: expected %s, got %v", FixDecompose, scores[1].FixStrategy)
+	}
+}
+
 func TestComputeScores_SkipsTestFiles(t *testing.T) {
 	stats := []gocyclo.Stat{
 		makeStat("pkg", "Foo", "/src/foo.go", 10, 5),
@@ -72,7 +151,7 @@ func TestComputeScores_SkipsTestFiles(t *testing.T) {
 	})
 	opts := DefaultOptions()
 
-	scores := computeScores(stats, cm, opts)
+	scores := computeScores(stats, cm, opts, testImportPath)
 
 	if len(scores) != 1 {
 		t.Fatalf("expected 1 score (test file skipped), got %d", len(scores))
@@ -104,7 +183,7 @@ func TestComputeScores_SkipsGeneratedFiles(t *testing.T) {
 
 	// IgnoreGenerated = true (default): generated file skipped.
 	opts := DefaultOptions()
-	scores := computeScores(stats, cm, opts)
+	scores := computeScores(stats, cm, opts, testImportPath)
 	if len(scores) != 1 {
 		t.Fatalf("expected 1 score (generated skipped), got %d", len(scores))
 	}
@@ -114,7 +193,7 @@ func TestComputeScores_SkipsGeneratedFiles(t *testing.T) {
 
 	// IgnoreGenerated = false: generated file included.
 	opts.IgnoreGenerated = false
-	scores = computeScores(stats, cm, opts)
+	scores = computeScores(stats, cm, opts, testImportPath)
 	if len(scores) != 2 {
 		t.Fatalf("expected 2 scores (generated included), got %d", len(scores))
 	}
@@ -128,7 +207,7 @@ func TestComputeScores_ZeroCoverage(t *testing.T) {
 	cm := makeCoverMap(map[coverKey]float64{})
 	opts := DefaultOptions()
 
-	scores := computeScores(stats, cm, opts)
+	scores := computeScores(stats, cm, opts, testImportPath)
 
 	if len(scores) != 1 {
 		t.Fatalf("expected 1 score, got %d", len(scores))
@@ -158,7 +237,7 @@ func TestComputeScores_GazeCRAP(t *testing.T) {
 		return ContractCoverageInfo{}, false
 	}
 
-	scores := computeScores(stats, cm, opts)
+	scores := computeScores(stats, cm, opts, testImportPath)
 
 	if len(scores) != 1 {
 		t.Fatalf("expected 1 score, got %d", len(scores))
@@ -173,7 +252,7 @@ func TestComputeScores_GazeCRAP(t *testing.T) {
 	if s.GazeCRAP == nil {
 		t.Fatal("expected non-nil GazeCRAP")
 	}
-	expectedGazeCRAP := Formula(10, 75.0)
+	expectedGazeCRAP := GazeFormula(10, 75.0)
 	if math.Abs(*s.GazeCRAP-expectedGazeCRAP) > 0.001 {
 		t.Errorf("expected GazeCRAP %f, got %f", expectedGazeCRAP, *s.GazeCRAP)
 	}
@@ -182,6 +261,236 @@ func TestComputeScores_GazeCRAP(t *testing.T) {
 	}
 }
 
+func TestComputeScores_GazeCRAPUnassertedContract(t *testing.T) {
+	// Every line runs under test but no contractual effect is
+	// asserted: CRAP sees a safe function, GazeCRAP does not.
+	stats := []gocyclo.Stat{
+		makeStat("pkg", "Foo", "/src/foo.go", 10, 5),
+	}
+	cm := makeCoverMap(map[coverKey]float64{
+		{file: "/src/foo.go", line: 10}: 100.0,
+	})
+	opts := DefaultOptions()
+	opts.ContractCoverageFunc = func(pkg, fn string) (ContractCoverageInfo, bool) {
+		return ContractCoverageInfo{Percentage: 0}, true
+	}
+
+	scores := computeScores(stats, cm, opts, testImportPath)
+
+	if len(scores) != 1 {
+		t.Fatalf("expected 1 score, got %d", len(scores))
+	}
+	s := scores[0]
+	if s.CRAP != 5 {
+		t.Errorf("expected CRAP 5, got %f", s.CRAP)
+	}
+	if s.GazeCRAP == nil || *s.GazeCRAP != 30 {
+		t.Fatalf("expected GazeCRAP 30, got %v", s.GazeCRAP)
+	}
+	if *s.Quadrant != Q3SimpleButUnderspecified {
+		t.Errorf("expected %s, got %s", Q3SimpleButUnderspecified, *s.Quadrant)
+	}
+}
+
+func TestComputeScores_GazeTiers(t *testing.T) {
+	// One of two P1 effects and none of three P4 effects asserted:
+	// 20% overall, 50% over the default P0+P1.
+	stats := []gocyclo.Stat{
+		makeStat("pkg", "Foo", "/src/foo.go", 10, 4),
+		makeStat("pkg", "OnlyP4", "/src/foo.go", 20, 4),
+	}
+	cm := makeCoverMap(map[coverKey]float64{
+		{file: "/src/foo.go", line: 10}: 100.0,
+		{file: "/src/foo.go", line: 20}: 100.0,
+	})
+	opts := DefaultOptions()
+	opts.GazeTiers = nil
+	opts.ContractCoverageFunc = func(pkg, fn string) (ContractCoverageInfo, bool) {
+		if fn == "OnlyP4" {
+			return ContractCoverageInfo{
+				Percentage: 0,
+				Tiers:      map[taxonomy.Tier]TierCoverage{taxonomy.TierP4: {Total: 2}},
+			}, true
+		}
+		return ContractCoverageInfo{
+			Percentage: 20,
+			Tiers: map[taxonomy.Tier]TierCoverage{
+				taxonomy.TierP1: {Asserted: 1, Total: 2},
+				taxonomy.TierP4: {Total: 3},
+			},
+		}, true
+	}
+
+	for _, tt := range []struct {
+		tiers       []taxonomy.Tier
+		foo, onlyP4 float64
+	}{
+		{nil, 50, 100},
+		{[]taxonomy.Tier{taxonomy.TierP0, taxonomy.TierP1}, 50, 100},
+		{[]taxonomy.Tier{taxonomy.TierP1, taxonomy.TierP4}, 20, 0},
+		{[]taxonomy.Tier{taxonomy.TierP4}, 0, 0},
+	} {
+		opts.GazeTiers = tt.tiers
+		scores := computeScores(stats, cm, opts, testImportPath)
+		for i, want := range []float64{tt.foo, tt.onlyP4} {
+			s := scores[i]
+			if s.ContractCoverage == nil || *s.ContractCoverage != want {
+				t.Errorf("tiers %v: %s contract coverage = %v, want %g", tt.tiers, s.Function, s.ContractCoverage, want)
+				continue
+			}
+			if got, want := *s.GazeCRAP, GazeFormula(4, want); got != want {
+				t.Errorf("tiers %v: %s GazeCRAP = %g, want %g", tt.tiers, s.Function, got, want)
+			}
+		}
+	}
+
+	// Without a tier breakdown the callback's percentage is used.
+	opts.ContractCoverageFunc = func(pkg, fn string) (ContractCoverageInfo, bool) {
+		return ContractCoverageInfo{Percentage: 20}, true
+	}
+	if got := *computeScores(stats, cm, opts, testImportPath)[0].ContractCoverage; got != 20 {
+		t.Errorf("no tier breakdown: contract coverage = %g, want 20", got)
+	}
+}
+
+func TestComputeScores_AmbiguousPolicy(t *testing.T) {
+	// Foo asserts one of two contractual P0 effects and one of two
+	// ambiguous ones (plus an unasserted ambiguous P4 effect outside
+	// the default tiers). Vague has only ambiguous effects.
+	stats := []gocyclo.Stat{
+		makeStat("pkg", "Foo", "/src/foo.go", 10, 4),
+		makeStat("pkg", "Vague", "/src/foo.go", 20, 4),
+	}
+	cm := makeCoverMap(map[coverKey]float64{
+		{file: "/src/foo.go", line: 10}: 100.0,
+		{file: "/src/foo.go", line: 20}: 100.0,
+	})
+	opts := DefaultOptions()
+	opts.ContractCoverageFunc = func(pkg, fn string) (ContractCoverageInfo, bool) {
+		if fn == "Vague" {
+			return ContractCoverageInfo{
+				Reason:        reasonAllAmbiguous,
+				MinConfidence: 55,
+				MaxConfidence: 70,
+				Ambiguous:     map[taxonomy.Tier]TierCoverage{taxonomy.TierP1: {Total: 2}},
+			}, true
+		}
+		return ContractCoverageInfo{
+			Percentage: 50,
+			Tiers:      map[taxonomy.Tier]TierCoverage{taxonomy.TierP0: {Asserted: 1, Total: 2}},
+			Ambiguous: map[taxonomy.Tier]TierCoverage{
+				taxonomy.TierP0: {Asserted: 1, Total: 2},
+				taxonomy.TierP4: {Total: 1},
+			},
+		}, true
+	}
+
+	for _, tt := range []struct {
+		policy     AmbiguousPolicy
+		foo, vague float64
+		reason     bool
+	}{
+		{"", 50, 0, true},
+		{AmbiguousIgnore, 50, 0, true},
+		{AmbiguousContractual, 50, 0, false},
+		{AmbiguousIncidental, 50, 100, false},
+	} {
+		opts.AmbiguousPolicy = tt.policy
+		scores := computeScores(stats, cm, opts, testImportPath)
+		for i, want := range []float64{tt.foo, tt.vague} {
+			if got := *scores[i].ContractCoverage; got != want {
+				t.Errorf("policy %q: %s contract coverage = %g, want %g", tt.policy, scores[i].Function, got, want)
+			}
+		}
+		vague := scores[1]
+		if got := vague.ContractCoverageReason != nil; got != tt.reason {
+			t.Errorf("policy %q: Vague has reason = %v, want %v", tt.policy, got, tt.reason)
+		}
+		if got := vague.EffectConfidenceRange != nil; got != tt.reason {
+			t.Errorf("policy %q: Vague has confidence range = %v, want %v", tt.policy, got, tt.reason)
+		}
+	}
+
+	// Asserting one of Foo's ambiguous effects lifts it to 2 of 4
+	// under the contractual policy; one more would make it 3 of 4.
+	opts.AmbiguousPolicy = AmbiguousContractual
+	opts.ContractCoverageFunc = func(pkg, fn string) (ContractCoverageInfo, bool) {
+		return ContractCoverageInfo{
+			Tiers:     map[taxonomy.Tier]TierCoverage{taxonomy.TierP0: {Asserted: 1, Total: 2}},
+			Ambiguous: map[taxonomy.Tier]TierCoverage{taxonomy.TierP1: {Asserted: 2, Total: 2}},
+		}, true
+	}
+	if got := *computeScores(stats, cm, opts, testImportPath)[0].ContractCoverage; got != 75 {
+		t.Errorf("contractual policy: contract coverage = %g, want 75", got)
+	}
+}
+
+func TestAmbiguousCoverage(t *testing.T) {
+	ambiguous := &taxonomy.Classification{Label: taxonomy.Ambiguous}
+	result := taxonomy.AnalysisResult{SideEffects: []taxonomy.SideEffect{
+		{ID: "se-1", Tier: taxonomy.TierP0, Classification: ambiguous},
+		{ID: "se-2", Tier: taxonomy.TierP0, Classification: ambiguous},
+		{ID: "se-3", Tier: taxonomy.TierP2, Classification: ambiguous},
+		{ID: "se-4", Tier: taxonomy.TierP0, Classification: &taxonomy.Classification{Label: taxonomy.Contractual}},
+		{ID: "se-5", Tier: taxonomy.TierP0},
+	}}
+	got := ambiguousCoverage(result, map[string]bool{"se-2": true, "se-4": true})
+	want := map[taxonomy.Tier]TierCoverage{
+		taxonomy.TierP0: {Asserted: 1, Total: 2},
+		taxonomy.TierP2: {Total: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ambiguousCoverage = %v, want %v", got, want)
+	}
+
+	if got := ambiguousCoverage(taxonomy.AnalysisResult{SideEffects: result.SideEffects[3:]}, nil); got != nil {
+		t.Errorf("ambiguousCoverage without ambiguous effects = %v, want nil", got)
+	}
+}
+
+func TestComputeScores_CustomQuadrantThresholds(t *testing.T) {
+	// Complexity 6 at 100% line coverage: CRAP 6. Contract coverage
+	// 50%: GazeCRAP 10.5. With the default 15/15 thresholds this is
+	// Q1. Each custom threshold moves one axis on its own.
+	stats := []gocyclo.Stat{
+		makeStat("pkg", "Foo", "/src/foo.go", 10, 6),
+	}
+	cm := makeCoverMap(map[coverKey]float64{
+		{file: "/src/foo.go", line: 10}: 100.0,
+	})
+
+	tests := []struct {
+		name       string
+		complexity int
+		coverage   float64
+		want       Quadrant
+	}{
+		{"default thresholds", 0, 0, Q1Safe},
+		{"complexity only", 6, 0, Q2ComplexButTested},
+		{"complexity above", 7, 0, Q1Safe},
+		{"coverage only", 0, 60, Q3SimpleButUnderspecified},
+		{"coverage at contract coverage", 0, 50, Q1Safe},
+		{"both", 5, 60, Q4Dangerous},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.ContractCoverageFunc = func(pkg, fn string) (ContractCoverageInfo, bool) {
+				return ContractCoverageInfo{Percentage: 50}, true
+			}
+			opts.ComplexityThreshold = tt.complexity
+			opts.CoverageThreshold = tt.coverage
+			s := computeScores(stats, cm, opts, testImportPath)[0]
+			if *s.Quadrant != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, *s.Quadrant)
+			}
+			if s.FixStrategy != nil {
+				t.Errorf("expected CRAPload threshold unaffected, got fix strategy %s", *s.FixStrategy)
+			}
+		})
+	}
+}
+
 func TestComputeScores_NoGazeCRAP(t *testing.T) {
 	stats := []gocyclo.Stat{
 		makeStat("pkg", "Foo", "/src/foo.go", 10, 5),
@@ -192,7 +501,7 @@ func TestComputeScores_NoGazeCRAP(t *testing.T) {
 	opts := DefaultOptions()
 	// ContractCoverageFunc is nil (default).
 
-	scores := computeScores(stats, cm, opts)
+	scores := computeScores(stats, cm, opts, testImportPath)
 
 	if len(scores) != 1 {
 		t.Fatalf("expected 1 score, got %d", len(scores))
@@ -222,7 +531,7 @@ func TestComputeScores_GazeCRAPNotFound(t *testing.T) {
 		return ContractCoverageInfo{}, false
 	}
 
-	scores := computeScores(stats, cm, opts)
+	scores := computeScores(stats, cm, opts, testImportPath)
 
 	if len(scores) != 1 {
 		t.Fatalf("expected 1 score, got %d", len(scores))
@@ -239,8 +548,61 @@ func TestComputeScores_GazeCRAPNotFound(t *testing.T) {
 	}
 }
 
+func TestComputeScores_SideEffectCount(t *testing.T) {
+	stats := []gocyclo.Stat{
+		makeStat("pkg", "Tested", "/src/foo.go", 10, 5),
+		makeStat("pkg", "Untested", "/src/foo.go", 20, 5),
+		makeStat("pkg", "Unknown", "/src/foo.go", 30, 5),
+	}
+	cm := makeCoverMap(map[coverKey]float64{})
+	three, four := 3, 4
+	opts := DefaultOptions()
+	opts.ContractCoverageFunc = func(pkg, fn string) (ContractCoverageInfo, bool) {
+		switch fn {
+		case "Tested":
+			return ContractCoverageInfo{Percentage: 50, SideEffectCount: &three}, true
+		case "Untested":
+			return ContractCoverageInfo{Reason: "no_test_coverage", SideEffectCount: &four}, false
+		}
+		return ContractCoverageInfo{}, false
+	}
+
+	scores := computeScores(stats, cm, opts, testImportPath)
+
+	want := map[string]int{"Tested": 3, "Untested": 4}
+	for _, s := range scores {
+		n, ok := want[s.Function]
+		switch {
+		case !ok && s.SideEffectCount != nil:
+			t.Errorf("%s: expected nil SideEffectCount, got %d", s.Function, *s.SideEffectCount)
+		case ok && (s.SideEffectCount == nil || *s.SideEffectCount != n):
+			t.Errorf("%s: SideEffectCount = %v, want %d", s.Function, s.SideEffectCount, n)
+		}
+	}
+}
+
 // --- FixStrategy Tests ---
 
+func TestSortScores(t *testing.T) {
+	scores := []Score{
+		{Function: "B", File: "b.go", Line: 1, CRAP: 5},
+		{Function: "A", File: "b.go", Line: 9, CRAP: 5},
+		{Function: "Z", File: "z.go", Line: 1, CRAP: 30},
+		{Function: "A", File: "a.go", Line: 3, CRAP: 5},
+		{Function: "C", File: "c.go", Line: 1, CRAP: 1},
+	}
+	sortScores(scores)
+
+	var got []string
+	for _, s := range scores {
+		got = append(got, s.Function+"@"+s.File)
+	}
+	want := []string{"Z@z.go", "A@a.go", "A@b.go", "B@b.go", "C@c.go"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
+
 func TestAssignFixStrategy_Decompose(t *testing.T) {
 	// Complexity 20 >= threshold 15, coverage 80% > 0.
 	s := Score{Complexity: 20, LineCoverage: 80, CRAP: Formula(20, 80)}
@@ -320,7 +682,7 @@ func TestComputeScores_FixStrategyAssigned(t *testing.T) {
 	})
 	opts := DefaultOptions()
 
-	scores := computeScores(stats, cm, opts)
+	scores := computeScores(stats, cm, opts, testImportPath)
 	if len(scores) != 2 {
 		t.Fatalf("expected 2 scores, got %d", len(scores))
 	}
@@ -382,7 +744,7 @@ func TestComputeScores_CoverageReason_AllAmbiguous(t *testing.T) {
 		}, true
 	}
 
-	scores := computeScores(stats, cm, opts)
+	scores := computeScores(stats, cm, opts, testImportPath)
 	if len(scores) != 1 {
 		t.Fatalf("expected 1 score, got %d", len(scores))
 	}
@@ -414,7 +776,7 @@ func TestComputeScores_CoverageReason_Normal(t *testing.T) {
 		return ContractCoverageInfo{Percentage: 85.0}, true
 	}
 
-	scores := computeScores(stats, cm, opts)
+	scores := computeScores(stats, cm, opts, testImportPath)
 	if len(scores) != 1 {
 		t.Fatalf("expected 1 score, got %d", len(scores))
 	}
@@ -494,3 +856,31 @@ func TestBuildSummary_RecommendedActions_Truncated(t *testing.T) {
 		t.Errorf("expected 20 recommended actions (truncated), got %d", len(summary.RecommendedActions))
 	}
 }
+
+func TestComputeScores_ExportedOnly(t *testing.T) {
+	stats := []gocyclo.Stat{
+		makeStat("pkg", "Foo", "/src/foo.go", 10, 5),
+		makeStat("pkg", "helper", "/src/foo.go", 20, 5),
+		makeStat("pkg", "(*Store).Save", "/src/foo.go", 30, 5),
+		makeStat("pkg", "(*Store).flush", "/src/foo.go", 40, 5),
+		makeStat("pkg", "(cache).Get", "/src/foo.go", 50, 5),
+		makeStat("pkg", "(List[T]).Len", "/src/foo.go", 60, 5),
+	}
+	cm := makeCoverMap(map[coverKey]float64{})
+
+	opts := DefaultOptions()
+	if got := len(computeScores(stats, cm, opts, testImportPath)); got != len(stats) {
+		t.Fatalf("default options scored %d functions, want %d", got, len(stats))
+	}
+
+	opts.IncludeUnexported = false
+	scores := computeScores(stats, cm, opts, testImportPath)
+	var names []string
+	for _, s := range scores {
+		names = append(names, s.Function)
+	}
+	want := []string{"Foo", "(*Store).Save", "(List[T]).Len"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("exported-only scored %v, want %v", names, want)
+	}
+}
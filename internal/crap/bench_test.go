@@ -1,6 +1,10 @@
 package crap
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -50,10 +54,59 @@ func BenchmarkBuildCoverMap(b *testing.B) {
 	}
 }
 
-func BenchmarkIsGeneratedFile_NotGenerated(b *testing.B) {
-	// Use this test file itself as a non-generated file.
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		isGeneratedFile("bench_test.go")
+// writeSyntheticModule writes files Go files of funcsPerFile
+// functions each under a temporary directory and returns it.
+func writeSyntheticModule(b *testing.B, files, funcsPerFile int) string {
+	b.Helper()
+	dir := b.TempDir()
+	for f := 0; f < files; f++ {
+		var src strings.Builder
+		src.WriteString("package synth\n")
+		for fn := 0; fn < funcsPerFile; fn++ {
+			fmt.Fprintf(&src, `
+func F%d_%d(xs []int, k int) int {
+	total := 0
+	for _, x := range xs {
+		if x > k && x%%2 == 0 || x == 42 {
+			total += x
+		}
+	}
+	switch {
+	case k > 10:
+		total++
+	case k < 0:
+		total--
+	}
+	return total
+}
+`, f, fn)
+		}
+		path := filepath.Join(dir, fmt.Sprintf("f%d.go", f))
+		if err := os.WriteFile(path, []byte(src.String()), 0o644); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// BenchmarkGatherComplexity measures complexity gathering over a
+// synthetic set of 500 functions in 50 files, parsing one file at a
+// time and on GOMAXPROCS goroutines.
+func BenchmarkGatherComplexity(b *testing.B) {
+	dir := writeSyntheticModule(b, 50, 10)
+	for _, bc := range []struct {
+		name    string
+		workers int
+	}{
+		{"serial", 1},
+		{"parallel", 0},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if stats := gatherComplexity([]string{dir}, testFileRegexp, bc.workers, nil); len(stats) != 500 {
+					b.Fatalf("got %d stats, want 500", len(stats))
+				}
+			}
+		})
 	}
 }
@@ -0,0 +1,234 @@
+package crap
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/fzipp/gocyclo"
+)
+
+// ComplexityDetail counts the constructs that contribute to a
+// function's cyclomatic complexity, using the same rules as gocyclo:
+// complexity = 1 + If + For + Range + Case + Comm + And + Or.
+type ComplexityDetail struct {
+	If    int `json:"if"`
+	For   int `json:"for"`
+	Range int `json:"range"`
+
+	// Case counts non-default switch and type switch cases.
+	Case int `json:"case"`
+
+	// Comm counts non-default select cases.
+	Comm int `json:"comm"`
+
+	// And and Or count && and || operators.
+	And int `json:"and"`
+	Or  int `json:"or"`
+}
+
+// Total returns the cyclomatic complexity the detail accounts for.
+func (d ComplexityDetail) Total() int {
+	return 1 + d.If + d.For + d.Range + d.Case + d.Comm + d.And + d.Or
+}
+
+// String lists the non-zero contributors, e.g. "if 3, case 4, && 1".
+func (d ComplexityDetail) String() string {
+	var parts []string
+	for _, c := range []struct {
+		label string
+		n     int
+	}{
+		{"if", d.If}, {"for", d.For}, {"range", d.Range}, {"case", d.Case},
+		{"select case", d.Comm}, {"&&", d.And}, {"||", d.Or},
+	} {
+		if c.n > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", c.label, c.n))
+		}
+	}
+	if len(parts) == 0 {
+		return "straight-line code"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// complexityDetail walks fn and counts complexity contributors.
+func complexityDetail(fn ast.Node) ComplexityDetail {
+	var d ComplexityDetail
+	ast.Inspect(fn, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.IfStmt:
+			d.If++
+		case *ast.ForStmt:
+			d.For++
+		case *ast.RangeStmt:
+			d.Range++
+		case *ast.CaseClause:
+			if n.List != nil { // ignore default case
+				d.Case++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil { // ignore default case
+				d.Comm++
+			}
+		case *ast.BinaryExpr:
+			switch n.Op {
+			case token.LAND:
+				d.And++
+			case token.LOR:
+				d.Or++
+			}
+		}
+		return true
+	})
+	return d
+}
+
+// attachComplexityDetails sets ComplexityDetail on each score by
+// re-parsing its file and locating the function declared on the
+// score's line. Files that fail to parse are skipped, leaving the
+// detail nil.
+func attachComplexityDetails(scores []Score) {
+	byFile := make(map[string]map[int]*ast.FuncDecl)
+	for i := range scores {
+		s := &scores[i]
+		decls, ok := byFile[s.File]
+		if !ok {
+			decls = funcDeclsByLine(s.File)
+			byFile[s.File] = decls
+		}
+		if fn, ok := decls[s.Line]; ok {
+			d := complexityDetail(fn)
+			s.ComplexityDetail = &d
+		}
+	}
+}
+
+// funcDeclsByLine parses path and indexes its function declarations
+// by starting line. Returns nil if the file cannot be parsed.
+func funcDeclsByLine(path string) map[int]*ast.FuncDecl {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, nil, 0)
+	if err != nil {
+		return nil
+	}
+	decls := make(map[int]*ast.FuncDecl)
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			decls[fset.Position(fn.Pos()).Line] = fn
+		}
+	}
+	return decls
+}
+
+// gatherComplexity computes the cyclomatic complexity of every
+// function in the Go files under paths, like gocyclo.Analyze, but
+// parses and analyzes files on up to workers goroutines (GOMAXPROCS
+// when workers is not positive). Each file's stats are kept in the
+// file's slot and concatenated in walk order, so the result does not
+// depend on scheduling. Paths that cannot be read and files that fail
+// to parse are skipped with a warning to stderr, if non-nil.
+func gatherComplexity(paths []string, ignore *regexp.Regexp, workers int, stderr io.Writer) []gocyclo.Stat {
+	files := complexityFiles(paths, ignore, stderr)
+
+	perFile := make([][]gocyclo.Stat, len(files))
+	errs := make([]error, len(files))
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < complexityWorkers(workers, len(files)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				fset := token.NewFileSet()
+				f, err := parser.ParseFile(fset, files[i], nil, parser.ParseComments)
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				perFile[i] = gocyclo.AnalyzeASTFile(f, fset, nil)
+			}
+		}()
+	}
+	for i := range files {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+
+	var stats []gocyclo.Stat
+	for i, fileStats := range perFile {
+		if errs[i] != nil {
+			if stderr != nil {
+				_, _ = fmt.Fprintf(stderr, "warning: skipping %s: %v\n", files[i], errs[i])
+			}
+			continue
+		}
+		stats = append(stats, fileStats...)
+	}
+	return stats
+}
+
+// complexityFiles lists the Go files gocyclo.Analyze would visit for
+// paths: each file path as given, and every .go file under each
+// directory, skipping testdata, vendor, and hidden or underscore-
+// prefixed directories. Files matching ignore are left out.
+func complexityFiles(paths []string, ignore *regexp.Regexp, stderr io.Writer) []string {
+	var files []string
+	add := func(path string) {
+		if ignore == nil || !ignore.MatchString(path) {
+			files = append(files, path)
+		}
+	}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			if stderr != nil {
+				_, _ = fmt.Fprintf(stderr, "warning: skipping %s: %v\n", path, err)
+			}
+			continue
+		}
+		if !info.IsDir() {
+			add(path)
+			continue
+		}
+		_ = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				name := d.Name()
+				if p != path && (name == "testdata" || name == "vendor" ||
+					strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(d.Name(), ".go") {
+				add(p)
+			}
+			return nil
+		})
+	}
+	return files
+}
+
+// complexityWorkers returns the number of goroutines gatherComplexity
+// starts: requested if positive, otherwise GOMAXPROCS, and never more
+// than the number of files.
+func complexityWorkers(requested, files int) int {
+	n := requested
+	if n <= 0 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	return min(n, files)
+}
@@ -0,0 +1,172 @@
+package crap
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fzipp/gocyclo"
+)
+
+const complexitySrc = `package m
+
+func Busy(xs []int, ch chan int, k int) int {
+	total := 0
+	for _, x := range xs {
+		if x > 0 && x < 10 || x == 42 {
+			total += x
+		}
+	}
+	for i := 0; i < k; i++ {
+		switch {
+		case i%2 == 0:
+			total++
+		case i%3 == 0:
+			total--
+		default:
+		}
+	}
+	select {
+	case v := <-ch:
+		total += v
+	default:
+	}
+	f := func() bool { return k > 0 && total > 0 }
+	if f() {
+		total *= 2
+	}
+	return total
+}
+
+func Flat() int { return 1 }
+`
+
+func TestComplexityDetail_MatchesGocyclo(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "m.go", complexitySrc, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		want ComplexityDetail
+	}{
+		{"Busy", ComplexityDetail{If: 2, For: 1, Range: 1, Case: 2, Comm: 1, And: 2, Or: 1}},
+		{"Flat", ComplexityDetail{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fn *ast.FuncDecl
+			for _, d := range f.Decls {
+				if fd, ok := d.(*ast.FuncDecl); ok && fd.Name.Name == tt.name {
+					fn = fd
+				}
+			}
+			got := complexityDetail(fn)
+			if got != tt.want {
+				t.Errorf("complexityDetail = %+v, want %+v", got, tt.want)
+			}
+			if got.Total() != gocyclo.Complexity(fn) {
+				t.Errorf("Total() = %d, gocyclo = %d", got.Total(), gocyclo.Complexity(fn))
+			}
+		})
+	}
+}
+
+func TestComplexityDetail_String(t *testing.T) {
+	d := ComplexityDetail{If: 3, Case: 4, And: 1}
+	if got, want := d.String(), "if 3, case 4, && 1"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got, want := (ComplexityDetail{}).String(), "straight-line code"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestAnalyze_ExplainComplexity(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/m\n\ngo 1.24\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "m.go"), []byte(complexitySrc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := DefaultOptions()
+	opts.CoverageMode = CoverageNone
+	rpt, err := Analyze([]string{"./..."}, dir, opts)
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	for _, s := range rpt.Scores {
+		if s.ComplexityDetail != nil {
+			t.Errorf("%s: expected no detail by default", s.Function)
+		}
+	}
+
+	opts.ExplainComplexity = true
+	rpt, err = Analyze([]string{"./..."}, dir, opts)
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	for _, s := range rpt.Scores {
+		if s.ComplexityDetail == nil {
+			t.Errorf("%s: expected complexity detail", s.Function)
+			continue
+		}
+		if s.ComplexityDetail.Total() != s.Complexity {
+			t.Errorf("%s: detail total %d != complexity %d",
+				s.Function, s.ComplexityDetail.Total(), s.Complexity)
+		}
+	}
+}
+
+func TestGatherComplexity_MatchesGocyclo(t *testing.T) {
+	// The package's own sources, analyzed serially by gocyclo and
+	// concurrently by gatherComplexity, yield the same stats in the
+	// same order.
+	want := gocyclo.Analyze([]string{"."}, testFileRegexp)
+	for _, workers := range []int{1, 8} {
+		got := gatherComplexity([]string{"."}, testFileRegexp, workers, nil)
+		if !reflect.DeepEqual(got, []gocyclo.Stat(want)) {
+			t.Errorf("workers=%d: gatherComplexity differs from gocyclo.Analyze (%d vs %d stats)",
+				workers, len(got), len(want))
+		}
+	}
+}
+
+func TestGatherComplexity_SkipsUnparsable(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "ok.go"), complexitySrc)
+	writeFile(t, filepath.Join(dir, "broken.go"), "package m\nfunc {")
+	writeFile(t, filepath.Join(dir, "testdata", "skipped.go"), complexitySrc)
+
+	var stderr bytes.Buffer
+	stats := gatherComplexity([]string{dir, filepath.Join(dir, "missing.go")}, testFileRegexp, 0, &stderr)
+	if len(stats) != 2 {
+		t.Errorf("got %d stats, want 2 from ok.go", len(stats))
+	}
+	for _, want := range []string{"skipping " + filepath.Join(dir, "broken.go"), "skipping " + filepath.Join(dir, "missing.go")} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Errorf("stderr missing %q:\n%s", want, stderr.String())
+		}
+	}
+}
+
+// writeFile writes content to path, creating parent directories.
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
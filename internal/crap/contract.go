@@ -6,8 +6,6 @@ package crap
 import (
 	"fmt"
 	"io"
-	"os"
-	"path/filepath"
 	"strings"
 
 	"golang.org/x/tools/go/packages"
@@ -25,7 +23,14 @@ import (
 // for GazeCRAP scoring. This is best-effort: if the quality pipeline
 // fails for any package (no tests, config errors, etc.), those
 // packages are silently skipped. Returns nil if no coverage data
-// could be collected.
+// could be collected. gazeConfig supplies the classification
+// thresholds; nil means the defaults.
+//
+// A function's coverage is the share of its contractual effects
+// asserted on by any of its tests (see
+// quality.AggregateFunctionCoverage), as reported by gaze coverage.
+// Every analyzed function also gets its side effect count, even
+// when the callback reports no coverage for it.
 //
 // The returned degradedPkgs list contains package paths where SSA
 // construction failed during quality analysis.
@@ -36,6 +41,7 @@ import (
 func BuildContractCoverageFunc(
 	patterns []string,
 	moduleDir string,
+	gazeConfig *config.GazeConfig,
 	stderr io.Writer,
 	aiMapperFn ...quality.AIMapperFunc,
 ) (func(pkg, function string) (ContractCoverageInfo, bool), []string) {
@@ -49,16 +55,23 @@ func BuildContractCoverageFunc(
 		return nil, nil
 	}
 
-	// Load config once for all packages.
-	gazeConfig := loadGazeConfigBestEffort()
+	if gazeConfig == nil {
+		gazeConfig = config.DefaultConfig()
+	}
+
+	// Load the module once for all packages: every package is
+	// served from it, and classification reuses it for caller and
+	// interface analysis.
+	session := loader.NewSession(moduleDir)
 
 	// Build coverage map: "shortPkg:qualifiedName" -> coverage info.
 	coverageMap := make(map[string]ContractCoverageInfo)
-	// effectsSet tracks functions that have >0 detected side effects,
-	// regardless of whether they have test coverage. Used to
-	// distinguish "no_test_coverage" from "no_effects_detected" when
-	// a function is absent from the coverage map.
-	effectsSet := make(map[string]bool)
+	// effectCounts holds the number of detected side effects of
+	// every analyzed function, regardless of whether it has test
+	// coverage. A positive count distinguishes "no_test_coverage"
+	// from "no_effects_detected" when a function is absent from the
+	// coverage map.
+	effectCounts := make(map[string]int)
 	var degradedPkgs []string
 
 	for _, pkgPath := range pkgPaths {
@@ -68,18 +81,16 @@ func BuildContractCoverageFunc(
 		analysisOpts := analysis.Options{
 			IncludeUnexported: isMainPkg(pkgPath),
 		}
-		analysisResults, analysisErr := analysis.LoadAndAnalyze(pkgPath, analysisOpts)
+		analysisResults, analysisErr := analyzeWithSession(session, pkgPath, analysisOpts)
 		if analysisErr == nil {
 			for _, result := range analysisResults {
-				if len(result.SideEffects) > 0 {
-					shortPkg := extractShortPkgName(result.Target.Package)
-					key := shortPkg + ":" + result.Target.QualifiedName()
-					effectsSet[key] = true
-				}
+				shortPkg := extractShortPkgName(result.Target.Package)
+				key := shortPkg + ":" + result.Target.QualifiedName()
+				effectCounts[key] = len(result.SideEffects)
 			}
 		}
 
-		reports, degradedPkg := analyzePackageCoverage(pkgPath, gazeConfig, stderr, aiMapperFn...)
+		classified, reports, degradedPkg := analyzePackageCoverage(session, pkgPath, gazeConfig, stderr, aiMapperFn...)
 		if degradedPkg != "" {
 			degradedPkgs = append(degradedPkgs, degradedPkg)
 		}
@@ -113,7 +124,7 @@ func BuildContractCoverageFunc(
 					}
 				}
 				if effectCount > 0 {
-					info.Reason = "all_effects_ambiguous"
+					info.Reason = reasonAllAmbiguous
 					info.MinConfidence = minConf
 					info.MaxConfidence = maxConf
 				} else {
@@ -125,9 +136,50 @@ func BuildContractCoverageFunc(
 				coverageMap[key] = info
 			}
 		}
+
+		// A function's contract coverage is the union of what all of
+		// its tests assert, not the best single test. The tier
+		// breakdown lets scoring restrict it to Options.GazeTiers,
+		// and the ambiguous breakdown apply Options.AmbiguousPolicy.
+		if degradedPkg == "" {
+			coverage := quality.AggregateFunctionCoverage(classified, reports)
+			byTarget := make(map[taxonomy.FunctionTarget]taxonomy.AnalysisResult, len(classified))
+			for _, result := range classified {
+				byTarget[result.Target] = result
+			}
+			for _, fc := range coverage {
+				if len(fc.Tests) == 0 {
+					continue
+				}
+				shortPkg := extractShortPkgName(fc.Target.Package)
+				key := shortPkg + ":" + fc.Target.QualifiedName()
+				coverageMap[key] = ContractCoverageInfo{
+					Percentage: fc.Percentage,
+					Tiers:      tierCoverage(byTarget[fc.Target], fc),
+				}
+			}
+
+			assertedAmbiguous := make(map[string]bool)
+			for _, report := range reports {
+				for _, id := range report.ContractCoverage.AssertedAmbiguous {
+					assertedAmbiguous[id] = true
+				}
+			}
+			for _, result := range classified {
+				key := extractShortPkgName(result.Target.Package) + ":" + result.Target.QualifiedName()
+				info, ok := coverageMap[key]
+				if !ok {
+					continue
+				}
+				if ambiguous := ambiguousCoverage(result, assertedAmbiguous); ambiguous != nil {
+					info.Ambiguous = ambiguous
+					coverageMap[key] = info
+				}
+			}
+		}
 	}
 
-	if len(coverageMap) == 0 && len(effectsSet) == 0 {
+	if len(coverageMap) == 0 && len(effectCounts) == 0 {
 		return nil, degradedPkgs
 	}
 
@@ -135,8 +187,13 @@ func BuildContractCoverageFunc(
 
 	return func(pkg, function string) (ContractCoverageInfo, bool) {
 		key := pkg + ":" + function
+		var count *int
+		if n, analyzed := effectCounts[key]; analyzed {
+			count = &n
+		}
 		info, ok := coverageMap[key]
 		if ok {
+			info.SideEffectCount = count
 			return info, true
 		}
 		// Function not in coverage map — distinguish between
@@ -144,13 +201,54 @@ func BuildContractCoverageFunc(
 		// Return ok=false so the CRAP pipeline excludes these from
 		// GazeCRAP calculations (no test = no coverage data, not
 		// 0% coverage). The Reason is informational for display.
-		if effectsSet[key] {
-			return ContractCoverageInfo{Reason: "no_test_coverage"}, false
+		if count != nil && *count > 0 {
+			return ContractCoverageInfo{Reason: "no_test_coverage", SideEffectCount: count}, false
 		}
-		return ContractCoverageInfo{Reason: "no_effects_detected"}, false
+		return ContractCoverageInfo{Reason: "no_effects_detected", SideEffectCount: count}, false
 	}, degradedPkgs
 }
 
+// tierCoverage counts result's contractual effects by tier, and how
+// many of each fc does not list as unasserted.
+func tierCoverage(result taxonomy.AnalysisResult, fc taxonomy.FunctionCoverage) map[taxonomy.Tier]TierCoverage {
+	unasserted := make(map[string]bool, len(fc.Unasserted))
+	for _, e := range fc.Unasserted {
+		unasserted[e.ID] = true
+	}
+	tiers := make(map[taxonomy.Tier]TierCoverage)
+	for _, e := range quality.ContractualEffects(result.SideEffects) {
+		tc := tiers[e.Tier]
+		tc.Total++
+		if !unasserted[e.ID] {
+			tc.Asserted++
+		}
+		tiers[e.Tier] = tc
+	}
+	return tiers
+}
+
+// ambiguousCoverage counts result's ambiguous effects by tier, and
+// how many of each are in asserted. Returns nil when result has no
+// ambiguous effects.
+func ambiguousCoverage(result taxonomy.AnalysisResult, asserted map[string]bool) map[taxonomy.Tier]TierCoverage {
+	var tiers map[taxonomy.Tier]TierCoverage
+	for _, e := range result.SideEffects {
+		if e.Classification == nil || e.Classification.Label != taxonomy.Ambiguous {
+			continue
+		}
+		if tiers == nil {
+			tiers = make(map[taxonomy.Tier]TierCoverage)
+		}
+		tc := tiers[e.Tier]
+		tc.Total++
+		if asserted[e.ID] {
+			tc.Asserted++
+		}
+		tiers[e.Tier] = tc
+	}
+	return tiers
+}
+
 // resolvePackagePaths resolves package patterns to individual
 // package paths, filtering out test-variant packages (those with
 // a "_test" suffix). Returns the deduplicated list of package paths
@@ -182,89 +280,97 @@ func resolvePackagePaths(patterns []string, moduleDir string) ([]string, error)
 
 // analyzePackageCoverage runs the 4-step quality pipeline on a single
 // package (analysis -> classify -> test-load -> quality assess) and
-// returns the quality reports. The second return value is the degraded
-// package path (empty if SSA succeeded). Returns nil if any step fails.
+// returns the classified analysis results and the quality reports.
+// The third return value is the degraded package path (empty if SSA
+// succeeded). Returns nil results and reports if any step fails.
+// The package and the module packages used for classification come
+// from session.
 //
 // The optional aiMapperFn parameter enables AI-assisted assertion
 // mapping when non-nil. It is passed through to quality.Options.AIMapperFunc.
 func analyzePackageCoverage(
+	session *loader.Session,
 	pkgPath string,
 	gazeConfig *config.GazeConfig,
 	stderr io.Writer,
 	aiMapperFn ...quality.AIMapperFunc,
-) ([]taxonomy.QualityReport, string) {
+) ([]taxonomy.AnalysisResult, []taxonomy.QualityReport, string) {
 	analysisOpts := analysis.Options{
 		IncludeUnexported: isMainPkg(pkgPath),
 	}
 
 	// Step 1: Analyze (Spec 001).
-	results, err := analysis.LoadAndAnalyze(pkgPath, analysisOpts)
+	target, err := session.Load(pkgPath)
 	if err != nil {
-		return nil, ""
+		return nil, nil, ""
+	}
+	results, err := analysis.Analyze(target.Pkg, analysisOpts)
+	if err != nil {
+		return nil, nil, ""
 	}
 	if len(results) == 0 {
-		return nil, ""
+		return nil, nil, ""
 	}
 
 	// Step 2: Classify (Spec 002).
-	classified := classifyResults(results, pkgPath, gazeConfig)
-	if classified == nil {
-		return nil, ""
-	}
+	classified := classifyResults(results, target.Pkg, session, gazeConfig)
 
 	// Step 3: Load test package.
 	testPkg, err := loadTestPackage(pkgPath)
 	if err != nil {
-		return nil, ""
+		return nil, nil, ""
 	}
 
 	// Step 4: Assess quality (Spec 003).
 	qualOpts := quality.Options{
 		Stderr: stderr,
 	}
+	if gazeConfig != nil {
+		qualOpts.AssertionHelpers = gazeConfig.Quality.AssertionHelpers
+	}
 	if len(aiMapperFn) > 0 && aiMapperFn[0] != nil {
 		qualOpts.AIMapperFunc = aiMapperFn[0]
 	}
 	reports, summary, err := quality.Assess(classified, testPkg, qualOpts)
 	if err != nil {
-		return nil, ""
+		return nil, nil, ""
 	}
 	if summary != nil && summary.SSADegraded {
 		_, _ = fmt.Fprintf(stderr, "warning: SSA degraded for %s, contract coverage unavailable\n", pkgPath)
-		return reports, pkgPath
+		return classified, reports, pkgPath
+	}
+	return classified, reports, ""
+}
+
+// analyzeWithSession loads pkgPath through session and analyzes it.
+func analyzeWithSession(session *loader.Session, pkgPath string, opts analysis.Options) ([]taxonomy.AnalysisResult, error) {
+	target, err := session.Load(pkgPath)
+	if err != nil {
+		return nil, err
 	}
-	return reports, ""
+	return analysis.Analyze(target.Pkg, opts)
 }
 
 // classifyResults runs classification on analysis results for a single
 // package. This is a simplified version of the cmd/gaze runClassify
-// that doesn't require the package-main logger or verbose mode.
+// that doesn't require the package-main logger or verbose mode. If
+// the module cannot be loaded, caller and interface signals are
+// degraded rather than failing classification.
 func classifyResults(
 	results []taxonomy.AnalysisResult,
-	pkgPath string,
+	target *packages.Package,
+	session *loader.Session,
 	cfg *config.GazeConfig,
 ) []taxonomy.AnalysisResult {
-	// Load the target package for AST access.
-	targetResult, err := loader.Load(pkgPath)
-	if err != nil {
-		return nil
-	}
-
-	// Load the module for caller/interface analysis.
-	cwd, err := os.Getwd()
-	if err != nil {
-		cwd = ""
-	}
-	modResult, modErr := loader.LoadModule(cwd)
 	var modPkgs []*packages.Package
-	if modErr == nil {
+	if modResult, modErr := session.Module(); modErr == nil {
 		modPkgs = modResult.Packages
 	}
 
 	clOpts := classify.Options{
 		Config:         cfg,
 		ModulePackages: modPkgs,
-		TargetPkg:      targetResult.Pkg,
+		TargetPkg:      target,
 	}
 
 	return classify.Classify(results, clOpts)
@@ -321,24 +427,6 @@ func loadTestPackage(pkgPath string) (*packages.Package, error) {
 	return nil, fmt.Errorf("no test files found for %q", pkgPath)
 }
 
-// loadGazeConfigBestEffort loads the GazeConfig from cwd, falling
-// back to the default config on any error.
-//
-// NOTE: keep in sync with internal/aireport/runner_steps.go:loadGazeConfigBestEffort.
-// Consolidation deferred — see specs/022-report-gazecrap-pipeline/tasks.md.
-func loadGazeConfigBestEffort() *config.GazeConfig {
-	cwd, err := os.Getwd()
-	if err != nil {
-		return config.DefaultConfig()
-	}
-	cfgPath := filepath.Join(cwd, ".gaze.yaml")
-	cfg, err := config.Load(cfgPath)
-	if err != nil {
-		return config.DefaultConfig()
-	}
-	return cfg
-}
-
 // extractShortPkgName returns the short package name from a full
 // import path. For "github.com/unbound-force/gaze/internal/crap", it
 // returns "crap".
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/unbound-force/gaze/internal/config"
+	"github.com/unbound-force/gaze/internal/loader"
 )
 
 // ---------------------------------------------------------------------------
@@ -120,7 +121,8 @@ func TestAnalyzePackageCoverage_ValidPackage(t *testing.T) {
 	}
 	gazeConfig := config.DefaultConfig()
 	var stderr bytes.Buffer
-	reports, _ := analyzePackageCoverage(
+	_, reports, _ := analyzePackageCoverage(
+		loader.NewSession(""),
 		"github.com/unbound-force/gaze/internal/quality/testdata/src/welltested",
 		gazeConfig,
 		&stderr,
@@ -136,7 +138,8 @@ func TestAnalyzePackageCoverage_InvalidPackage(t *testing.T) {
 	}
 	gazeConfig := config.DefaultConfig()
 	var stderr bytes.Buffer
-	reports, _ := analyzePackageCoverage(
+	_, reports, _ := analyzePackageCoverage(
+		loader.NewSession(""),
 		"github.com/nonexistent/does/not/exist",
 		gazeConfig,
 		&stderr,
@@ -160,6 +163,7 @@ func TestBuildContractCoverageFunc_InvalidPattern(t *testing.T) {
 	fn, _ := BuildContractCoverageFunc(
 		[]string{"github.com/nonexistent/package/does/not/exist"},
 		t.TempDir(),
+		nil,
 		&buf,
 	)
 	if fn != nil {
@@ -182,7 +186,7 @@ func TestBuildContractCoverageFunc_WelltestedPackage(t *testing.T) {
 	pattern := "github.com/unbound-force/gaze/internal/quality/testdata/src/welltested"
 
 	var buf bytes.Buffer
-	fn, _ := BuildContractCoverageFunc([]string{pattern}, ".", &buf)
+	fn, _ := BuildContractCoverageFunc([]string{pattern}, ".", nil, &buf)
 
 	if fn == nil {
 		t.Fatal("BuildContractCoverageFunc returned nil; expected non-nil closure for well-tested package")
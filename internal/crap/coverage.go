@@ -9,6 +9,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"golang.org/x/tools/cover"
@@ -36,6 +37,36 @@ type FuncCoverage struct {
 
 	// Percentage is the coverage percentage (0-100).
 	Percentage float64 `json:"percentage"`
+
+	// CoveredBlocks is the number of coverage blocks in the
+	// function that executed at least once.
+	CoveredBlocks int64 `json:"covered_blocks"`
+
+	// TotalBlocks is the number of coverage blocks in the function.
+	TotalBlocks int64 `json:"total_blocks"`
+
+	// Mode is the cover mode of the profile the data came from
+	// ("set", "count", or "atomic").
+	Mode string `json:"mode"`
+}
+
+// HasBranchData reports whether fc carries the per-block data
+// BranchPercentage is derived from. Every cover mode, including the
+// default "set", records whether each block executed, which is all
+// BranchPercentage needs.
+func (fc FuncCoverage) HasBranchData() bool {
+	return fc.TotalBlocks > 0
+}
+
+// BranchPercentage is the share of the function's coverage blocks
+// that executed (0-100). Each block is a straight-line run of
+// statements entered by a branch, so unlike Percentage a small
+// untested branch weighs as much as a large tested one.
+func (fc FuncCoverage) BranchPercentage() float64 {
+	if fc.TotalBlocks == 0 {
+		return 0
+	}
+	return 100.0 * float64(fc.CoveredBlocks) / float64(fc.TotalBlocks)
 }
 
 // ParseCoverProfile reads a Go coverage profile and computes
@@ -48,7 +79,15 @@ type FuncCoverage struct {
 // Warnings about files that cannot be parsed are written to stderr.
 // If stderr is nil, warnings are suppressed.
 func ParseCoverProfile(profilePath string, moduleDir string, stderr io.Writer) ([]FuncCoverage, error) {
-	profiles, err := cover.ParseProfiles(profilePath)
+	return ParseCoverProfiles([]string{profilePath}, moduleDir, stderr)
+}
+
+// ParseCoverProfiles is like ParseCoverProfile but reads several
+// profiles and merges them before computing per-function coverage.
+// See mergeProfiles for how blocks from different profiles are
+// combined.
+func ParseCoverProfiles(profilePaths []string, moduleDir string, stderr io.Writer) ([]FuncCoverage, error) {
+	profiles, err := mergeProfiles(profilePaths)
 	if err != nil {
 		return nil, err
 	}
@@ -78,18 +117,22 @@ func ParseCoverProfile(profilePath string, moduleDir string, stderr io.Writer) (
 		// Compute coverage per function.
 		for _, fn := range funcs {
 			covered, total := funcCoverage(fn, profile)
+			coveredBlocks, totalBlocks := funcBlockCoverage(fn, profile)
 			pct := 0.0
 			if total > 0 {
 				pct = 100.0 * float64(covered) / float64(total)
 			}
 			results = append(results, FuncCoverage{
-				File:         filePath,
-				FuncName:     fn.name,
-				StartLine:    fn.startLine,
-				EndLine:      fn.endLine,
-				CoveredStmts: covered,
-				TotalStmts:   total,
-				Percentage:   pct,
+				File:          filePath,
+				FuncName:      fn.name,
+				StartLine:     fn.startLine,
+				EndLine:       fn.endLine,
+				CoveredStmts:  covered,
+				TotalStmts:    total,
+				Percentage:    pct,
+				CoveredBlocks: coveredBlocks,
+				TotalBlocks:   totalBlocks,
+				Mode:          profile.Mode,
 			})
 		}
 	}
@@ -97,6 +140,76 @@ func ParseCoverProfile(profilePath string, moduleDir string, stderr io.Writer) (
 	return results, nil
 }
 
+// blockExtent identifies a coverage block by its source range.
+type blockExtent struct {
+	startLine, startCol, endLine, endCol int
+}
+
+// mergeProfiles parses each profile path and merges the results
+// into one profile per source file. Blocks with the same extent in
+// several profiles are combined into a single block: counts are
+// summed, or in "set" mode the block is covered if any profile
+// covered it. This mirrors how cover.ParseProfiles merges duplicate
+// blocks within one profile, so a statement covered by any profile
+// counts as covered and no statement is counted twice. Blocks are
+// returned sorted by position, as funcCoverage requires.
+func mergeProfiles(profilePaths []string) ([]*cover.Profile, error) {
+	byFile := make(map[string]*cover.Profile)
+	blockIndex := make(map[string]map[blockExtent]int)
+
+	for _, path := range profilePaths {
+		profiles, err := cover.ParseProfiles(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for _, p := range profiles {
+			merged, ok := byFile[p.FileName]
+			if !ok {
+				merged = &cover.Profile{FileName: p.FileName, Mode: p.Mode}
+				byFile[p.FileName] = merged
+				blockIndex[p.FileName] = make(map[blockExtent]int)
+			}
+			index := blockIndex[p.FileName]
+			for _, b := range p.Blocks {
+				key := blockExtent{b.StartLine, b.StartCol, b.EndLine, b.EndCol}
+				i, seen := index[key]
+				if !seen {
+					index[key] = len(merged.Blocks)
+					merged.Blocks = append(merged.Blocks, b)
+					continue
+				}
+				if merged.Mode == "set" {
+					if b.Count > 0 {
+						merged.Blocks[i].Count = 1
+					}
+				} else {
+					merged.Blocks[i].Count += b.Count
+				}
+			}
+		}
+	}
+
+	files := make([]string, 0, len(byFile))
+	for name := range byFile {
+		files = append(files, name)
+	}
+	sort.Strings(files)
+
+	result := make([]*cover.Profile, 0, len(files))
+	for _, name := range files {
+		p := byFile[name]
+		sort.SliceStable(p.Blocks, func(i, j int) bool {
+			bi, bj := p.Blocks[i], p.Blocks[j]
+			if bi.StartLine != bj.StartLine {
+				return bi.StartLine < bj.StartLine
+			}
+			return bi.StartCol < bj.StartCol
+		})
+		result = append(result, p)
+	}
+	return result, nil
+}
+
 // funcExtent describes a function's source position.
 type funcExtent struct {
 	name      string
@@ -158,11 +271,35 @@ func recvTypeString(expr ast.Expr) string {
 
 // funcCoverage computes the covered and total statement counts for a
 // function within a coverage profile.
+func funcCoverage(fn funcExtent, profile *cover.Profile) (covered, total int64) {
+	for _, b := range funcBlocks(fn, profile) {
+		total += int64(b.NumStmt)
+		if b.Count > 0 {
+			covered += int64(b.NumStmt)
+		}
+	}
+	return
+}
+
+// funcBlockCoverage computes the executed and total coverage block
+// counts for a function within a coverage profile.
+func funcBlockCoverage(fn funcExtent, profile *cover.Profile) (covered, total int64) {
+	for _, b := range funcBlocks(fn, profile) {
+		total++
+		if b.Count > 0 {
+			covered++
+		}
+	}
+	return
+}
+
+// funcBlocks returns the profile blocks that overlap the function.
 //
 // Assumes profile.Blocks are sorted by StartLine, which is guaranteed
 // by cover.ParseProfiles (mirrors go tool cover behavior). The early
 // break optimization depends on this ordering.
-func funcCoverage(fn funcExtent, profile *cover.Profile) (covered, total int64) {
+func funcBlocks(fn funcExtent, profile *cover.Profile) []cover.ProfileBlock {
+	var blocks []cover.ProfileBlock
 	for _, b := range profile.Blocks {
 		// Block entirely after the function — stop.
 		if b.StartLine > fn.endLine {
@@ -178,13 +315,9 @@ func funcCoverage(fn funcExtent, profile *cover.Profile) (covered, total int64)
 		if b.EndLine == fn.startLine && b.EndCol <= fn.startCol {
 			continue
 		}
-		// Block overlaps the function.
-		total += int64(b.NumStmt)
-		if b.Count > 0 {
-			covered += int64(b.NumStmt)
-		}
+		blocks = append(blocks, b)
 	}
-	return
+	return blocks
 }
 
 // resolveFilePath maps a coverage profile filename (import path
@@ -8,8 +8,19 @@
 // a given threshold (default 15).
 package crap
 
+import (
+	"crypto/sha256"
+	"fmt"
+)
+
 // Score holds the CRAP score for a single function.
 type Score struct {
+	// ID is a stable identifier derived from the package import
+	// path and function name (see GenerateFunctionID). It excludes
+	// the file and line, so it survives edits above the function and
+	// reformatting, and differs between same-named packages.
+	ID string `json:"id"`
+
 	// Package is the Go package name.
 	Package string `json:"package"`
 
@@ -26,9 +37,20 @@ type Score struct {
 	// Complexity is the cyclomatic complexity.
 	Complexity int `json:"complexity"`
 
+	// ComplexityDetail breaks Complexity down by contributing
+	// construct. Populated only when Options.ExplainComplexity is
+	// set.
+	ComplexityDetail *ComplexityDetail `json:"complexity_detail,omitempty"`
+
 	// LineCoverage is the line coverage percentage (0-100).
 	LineCoverage float64 `json:"line_coverage"`
 
+	// BranchCoverage is the share of the function's coverage blocks
+	// that executed (0-100). Populated only in branch coverage mode
+	// when the profile has blocks for the function; CRAP is then
+	// computed from it instead of LineCoverage.
+	BranchCoverage *float64 `json:"branch_coverage,omitempty"`
+
 	// CRAP is the classic CRAP score.
 	CRAP float64 `json:"crap"`
 
@@ -53,6 +75,12 @@ type Score struct {
 	// reason is diagnostic (e.g., all effects are ambiguous).
 	ContractCoverageReason *string `json:"contract_coverage_reason,omitempty"`
 
+	// SideEffectCount is the number of side effects Gaze detected
+	// in the function. Nil when no analysis results were supplied
+	// through Options.ContractCoverageFunc or the function was not
+	// analyzed.
+	SideEffectCount *int `json:"side_effect_count,omitempty"`
+
 	// EffectConfidenceRange is [min, max] classification confidence
 	// across all side effects. Only populated when
 	// ContractCoverageReason is "all_effects_ambiguous".
@@ -114,6 +142,18 @@ type RecommendedAction struct {
 	Quadrant    *Quadrant   `json:"quadrant,omitempty"`
 }
 
+// QuadrantThresholds records the boundaries used to assign
+// quadrants. Each axis has one: the CRAP axis either CRAP (high-risk
+// at or above the score) or Complexity (at or above the cyclomatic
+// complexity), the GazeCRAP axis either GazeCRAP (at or above the
+// score) or Coverage (below the contract coverage percentage).
+type QuadrantThresholds struct {
+	CRAP       float64 `json:"crap,omitempty"`
+	Complexity int     `json:"complexity,omitempty"`
+	GazeCRAP   float64 `json:"gaze_crap,omitempty"`
+	Coverage   float64 `json:"coverage,omitempty"`
+}
+
 // Summary holds aggregate statistics for a CRAP report.
 type Summary struct {
 	TotalFunctions      int                 `json:"total_functions"`
@@ -127,6 +167,7 @@ type Summary struct {
 	AvgGazeCRAP         *float64            `json:"avg_gaze_crap,omitempty"`
 	AvgContractCoverage *float64            `json:"avg_contract_coverage,omitempty"`
 	QuadrantCounts      map[Quadrant]int    `json:"quadrant_counts,omitempty"`
+	QuadrantThresholds  *QuadrantThresholds `json:"quadrant_thresholds,omitempty"`
 	FixStrategyCounts   map[FixStrategy]int `json:"fix_strategy_counts,omitempty"`
 	WorstCRAP           []Score             `json:"worst_crap"`
 	WorstGazeCRAP       []Score             `json:"worst_gaze_crap,omitempty"`
@@ -137,12 +178,95 @@ type Summary struct {
 	// are based on a subset of analyzed functions. Consumers should
 	// caveat the metrics accordingly.
 	SSADegradedPackages []string `json:"ssa_degraded_packages,omitempty"`
+
+	// Filter names the function filter that produced the scored
+	// set (e.g. FilterExportedOnly), so consumers know what
+	// TotalFunctions and CRAPload count. Empty when every
+	// non-test, non-generated function was scored.
+	Filter string `json:"filter,omitempty"`
+
+	// CoverageMode is the coverage figure CRAP scores were computed
+	// from. Set only when branch or none mode was requested:
+	// CoverageBranch, CoverageLine if no function had execution
+	// counts, or CoverageNone, in which case line coverage is
+	// unknown and reported as 0.
+	CoverageMode CoverageMode `json:"coverage_mode,omitempty"`
+
+	// CoverageNote explains a fallback from branch to line coverage.
+	CoverageNote string `json:"coverage_note,omitempty"`
 }
 
+// CoverageMode selects which coverage figure feeds the CRAP formula.
+type CoverageMode string
+
+// Coverage modes.
+const (
+	// CoverageLine uses statement (line) coverage.
+	CoverageLine CoverageMode = "line"
+
+	// CoverageBranch uses the share of executed coverage blocks,
+	// which requires a count or atomic coverage profile.
+	CoverageBranch CoverageMode = "branch"
+
+	// CoverageNone skips coverage entirely: no tests are run and no
+	// profile is read. CRAP reduces to complexity (the score a fully
+	// covered function would get), giving a complexity-only ranking.
+	CoverageNone CoverageMode = "none"
+)
+
+// AmbiguousPolicy selects how GazeCRAP's contract coverage counts
+// side effects the classifier labeled ambiguous.
+type AmbiguousPolicy string
+
+// Ambiguous policies.
+const (
+	// AmbiguousIgnore leaves ambiguous effects out of contract
+	// coverage, and reports a function whose effects are all
+	// ambiguous with the all_effects_ambiguous reason.
+	AmbiguousIgnore AmbiguousPolicy = "ignore"
+
+	// AmbiguousContractual counts ambiguous effects as contractual:
+	// each one must be asserted on, forcing review of everything
+	// the classifier is unsure about.
+	AmbiguousContractual AmbiguousPolicy = "contractual"
+
+	// AmbiguousIncidental counts ambiguous effects as incidental, so
+	// a function whose effects are all ambiguous has trivially
+	// complete contract coverage.
+	AmbiguousIncidental AmbiguousPolicy = "incidental"
+)
+
+// FilterExportedOnly is the Summary.Filter value when only exported
+// functions and methods were scored (Options.IncludeUnexported is
+// false).
+const FilterExportedOnly = "exported_only"
+
 // Report is the complete CRAP analysis output.
 type Report struct {
-	Scores  []Score `json:"scores"`
+	// Scores are sorted by CRAP descending, then by function name.
+	Scores []Score `json:"scores"`
+
+	// PackageSummaries holds a summary per package, keyed by the
+	// package directory relative to the module root ("." for the
+	// root package). Per-package summaries omit recommended actions,
+	// which are listed once in Summary.
+	PackageSummaries map[string]Summary `json:"package_summaries,omitempty"`
+
 	Summary Summary `json:"summary"`
+
+	// Diff compares the scores against a baseline report. Set only
+	// when a baseline was supplied (gaze crap --baseline).
+	Diff *ReportDiff `json:"diff,omitempty"`
+}
+
+// GenerateFunctionID produces a stable, deterministic ID for a
+// function from its package import path and name (including any
+// receiver, e.g. "(*Store).Save"). Like taxonomy.GenerateID, it is a sha256 hash
+// truncated to 8 hex characters, prefixed with "fn-".
+func GenerateFunctionID(pkgPath, function string) string {
+	input := fmt.Sprintf("%s:%s", pkgPath, function)
+	hash := sha256.Sum256([]byte(input))
+	return fmt.Sprintf("fn-%x", hash[:4])
 }
 
 // Formula computes CRAP(m) = comp^2 * (1 - cov/100)^3 + comp.
@@ -155,14 +279,28 @@ func Formula(complexity int, coveragePct float64) float64 {
 	return comp*comp*uncov*uncov*uncov + comp
 }
 
+// GazeFormula computes GazeCRAP(m) = comp^2 * (1 - contract_cov/100)^3 + comp.
+// It is Formula with contract coverage — the percentage of a
+// function's contractual side effects asserted on by tests —
+// substituted for line coverage. A function whose lines all execute
+// under test but whose contractual effects are never asserted has
+// contractCoverage 0 and scores comp^2 + comp, the same as a
+// completely untested function.
+func GazeFormula(complexity int, contractCoverage float64) float64 {
+	return Formula(complexity, contractCoverage)
+}
+
 // ClassifyQuadrant determines the quadrant for a function based on
 // its CRAP and GazeCRAP scores relative to independent thresholds.
 // Returns the Quadrant constant (Q1Safe, Q2ComplexButTested,
 // Q3SimpleButUnderspecified, or Q4Dangerous).
 func ClassifyQuadrant(crap, gazeCRAP, crapThreshold, gazeCRAPThreshold float64) Quadrant {
-	highCRAP := crap >= crapThreshold
-	highGazeCRAP := gazeCRAP >= gazeCRAPThreshold
+	return quadrantOf(crap >= crapThreshold, gazeCRAP >= gazeCRAPThreshold)
+}
 
+// quadrantOf returns the quadrant for a function that is or is not
+// high-risk on each axis.
+func quadrantOf(highCRAP, highGazeCRAP bool) Quadrant {
 	switch {
 	case !highCRAP && !highGazeCRAP:
 		return Q1Safe
@@ -15,6 +15,10 @@ import (
 
 	"github.com/fzipp/gocyclo"
 	"golang.org/x/tools/cover"
+	"gopkg.in/yaml.v3"
+
+	"github.com/unbound-force/gaze/internal/report"
+	"github.com/unbound-force/gaze/internal/taxonomy"
 )
 
 func TestFormula_ZeroCoverage(t *testing.T) {
@@ -177,6 +181,68 @@ func TestClassifyQuadrant_IndependentThresholds(t *testing.T) {
 	}
 }
 
+func TestGazeFormula(t *testing.T) {
+	tests := []struct {
+		name       string
+		complexity int
+		contract   float64
+		want       float64
+	}{
+		{"no contract coverage", 5, 0, 30},
+		{"full contract coverage", 5, 100, 5},
+		{"half contract coverage", 10, 50, 22.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GazeFormula(tt.complexity, tt.contract)
+			if math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("GazeFormula(%d, %.0f) = %f, want %f",
+					tt.complexity, tt.contract, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildSummary_NoContractCoverage(t *testing.T) {
+	scores := []Score{
+		{Complexity: 5, LineCoverage: 100, CRAP: 5},
+		{Complexity: 4, LineCoverage: 0, CRAP: 20},
+	}
+	summary := buildSummary(scores, DefaultOptions())
+
+	if summary.GazeCRAPload != nil {
+		t.Errorf("expected nil GazeCRAPload, got %d", *summary.GazeCRAPload)
+	}
+	if summary.GazeCRAPThreshold != nil {
+		t.Errorf("expected nil GazeCRAPThreshold, got %f", *summary.GazeCRAPThreshold)
+	}
+	if summary.AvgGazeCRAP != nil {
+		t.Errorf("expected nil AvgGazeCRAP, got %f", *summary.AvgGazeCRAP)
+	}
+	if summary.QuadrantCounts != nil {
+		t.Errorf("expected nil QuadrantCounts, got %v", summary.QuadrantCounts)
+	}
+}
+
+func TestGenerateFunctionID(t *testing.T) {
+	id := GenerateFunctionID("crap", "(*Store).Save")
+	if !regexp.MustCompile(`^fn-[0-9a-f]{8}$`).MatchString(id) {
+		t.Errorf("unexpected ID format %q", id)
+	}
+	if again := GenerateFunctionID("crap", "(*Store).Save"); again != id {
+		t.Errorf("expected deterministic ID, got %q and %q", id, again)
+	}
+	for _, other := range []string{
+		GenerateFunctionID("crap", "(Store).Save"),
+		GenerateFunctionID("crap", "Save"),
+		GenerateFunctionID("report", "(*Store).Save"),
+	} {
+		if other == id {
+			t.Errorf("expected distinct IDs, got %q twice", id)
+		}
+	}
+}
+
 func TestBuildSummary_CRAPload(t *testing.T) {
 	scores := []Score{
 		{Complexity: 5, LineCoverage: 0, CRAP: 30},     // above 15
@@ -325,6 +391,39 @@ func TestBuildSummary_Empty(t *testing.T) {
 	}
 }
 
+func TestBuildSummary_Filter(t *testing.T) {
+	opts := DefaultOptions()
+	if got := buildSummary(nil, opts).Filter; got != "" {
+		t.Errorf("default Filter = %q, want empty", got)
+	}
+
+	opts.IncludeUnexported = false
+	if got := buildSummary(nil, opts).Filter; got != FilterExportedOnly {
+		t.Errorf("exported-only Filter = %q, want %q", got, FilterExportedOnly)
+	}
+	scores := []Score{{Function: "Foo", Complexity: 1, CRAP: 1}}
+	if got := buildSummary(scores, opts).Filter; got != FilterExportedOnly {
+		t.Errorf("exported-only Filter = %q, want %q", got, FilterExportedOnly)
+	}
+}
+
+func TestWriteText_ShowsFilter(t *testing.T) {
+	report := &Report{
+		Scores: []Score{
+			{Package: "pkg", Function: "Foo", File: "foo.go", Line: 10, Complexity: 1, CRAP: 1},
+		},
+		Summary: Summary{TotalFunctions: 1, CRAPThreshold: 15, Filter: FilterExportedOnly},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteText(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "exported functions only") {
+		t.Errorf("expected filter line in summary, got:\n%s", buf.String())
+	}
+}
+
 func TestWriteJSON_ValidOutput(t *testing.T) {
 	report := &Report{
 		Scores: []Score{
@@ -395,87 +494,69 @@ func TestWriteText_ContainsSummary(t *testing.T) {
 	}
 }
 
-func TestWriteText_MarksAboveThreshold(t *testing.T) {
+func TestWriteText_SideEffectCounts(t *testing.T) {
+	zero, two, twelve := 0, 2, 12
 	report := &Report{
 		Scores: []Score{
-			{Function: "Bad", CRAP: 30, Complexity: 5, LineCoverage: 0},
-			{Function: "Good", CRAP: 3, Complexity: 1, LineCoverage: 100},
-		},
-		Summary: Summary{
-			TotalFunctions: 2,
-			CRAPThreshold:  15,
-			CRAPload:       1,
+			{Function: "Pure", File: "a.go", Line: 1, Complexity: 1, CRAP: 1, SideEffectCount: &zero},
+			{Function: "Light", File: "a.go", Line: 5, Complexity: 2, CRAP: 2, SideEffectCount: &two},
+			{Function: "Heavy", File: "a.go", Line: 9, Complexity: 12, CRAP: 156, SideEffectCount: &twelve},
+			{Function: "Unanalyzed", File: "a.go", Line: 20, Complexity: 1, CRAP: 1},
 		},
+		Summary: Summary{TotalFunctions: 4, CRAPThreshold: 15},
 	}
 
 	var buf bytes.Buffer
 	if err := WriteText(&buf, report); err != nil {
 		t.Fatal(err)
 	}
-
 	output := buf.String()
-	// The * marker should appear for the function above threshold.
-	if !strings.Contains(output, "*") {
-		t.Error("expected * marker for function above threshold")
+	for _, want := range []string{
+		"EFFECTS",
+		"--- Side Effects per Function ---",
+		"10+",
+		"(1 at or above CRAP threshold)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
 	}
-}
-
-// --- isGeneratedFile tests ---
-
-func TestIsGeneratedFile_Generated(t *testing.T) {
-	dir := t.TempDir()
-	path := filepath.Join(dir, "gen.go")
-	content := `// Code generated by protoc-gen-go. DO NOT EDIT.
-
-package pb
 
-func Foo() {}
-`
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
-		t.Fatal(err)
-	}
-	if !isGeneratedFile(path) {
-		t.Error("expected file to be detected as generated")
+	// Without counts, neither the column nor the histogram appears.
+	for i := range report.Scores {
+		report.Scores[i].SideEffectCount = nil
 	}
-}
-
-func TestIsGeneratedFile_NotGenerated(t *testing.T) {
-	dir := t.TempDir()
-	path := filepath.Join(dir, "normal.go")
-	content := `// Package foo provides functionality.
-package foo
-
-func Bar() {}
-`
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+	buf.Reset()
+	if err := WriteText(&buf, report); err != nil {
 		t.Fatal(err)
 	}
-	if isGeneratedFile(path) {
-		t.Error("expected file NOT to be detected as generated")
+	if output := buf.String(); strings.Contains(output, "EFFECTS") || strings.Contains(output, "Side Effects per Function") {
+		t.Errorf("expected no side effect column or histogram without counts:\n%s", output)
 	}
 }
 
-func TestIsGeneratedFile_GeneratedAfterPackage(t *testing.T) {
-	// A "Code generated" comment AFTER the package clause should
-	// NOT count as generated (per Go convention).
-	dir := t.TempDir()
-	path := filepath.Join(dir, "late.go")
-	content := `package foo
+func TestWriteText_MarksAboveThreshold(t *testing.T) {
+	report := &Report{
+		Scores: []Score{
+			{Function: "Bad", CRAP: 30, Complexity: 5, LineCoverage: 0},
+			{Function: "Good", CRAP: 3, Complexity: 1, LineCoverage: 100},
+		},
+		Summary: Summary{
+			TotalFunctions: 2,
+			CRAPThreshold:  15,
+			CRAPload:       1,
+		},
+	}
 
-// Code generated by something. DO NOT EDIT.
-func Baz() {}
-`
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+	var buf bytes.Buffer
+	if err := WriteText(&buf, report); err != nil {
 		t.Fatal(err)
 	}
-	if isGeneratedFile(path) {
-		t.Error("comment after package clause should not be detected as generated")
-	}
-}
 
-func TestIsGeneratedFile_NonexistentFile(t *testing.T) {
-	if isGeneratedFile("/nonexistent/path/file.go") {
-		t.Error("nonexistent file should return false")
+	output := buf.String()
+	// The * marker should appear for the function above threshold.
+	if !strings.Contains(output, "*") {
+		t.Error("expected * marker for function above threshold")
 	}
 }
 
@@ -1126,12 +1207,22 @@ func TestAnalyze_WithPrebuiltProfile(t *testing.T) {
 	modRoot := moduleRoot(t)
 
 	// Build a minimal coverage profile that references crap.go.
-	// Formula (lines 152-156) and ClassifyQuadrant (lines 162-176)
-	// are marked as covered (Count=1); everything else is absent
-	// and defaults to 0% coverage.
-	profileContent := "mode: set\n" +
-		"github.com/unbound-force/gaze/internal/crap/crap.go:152.55,156.2 2 1\n" +
-		"github.com/unbound-force/gaze/internal/crap/crap.go:162.86,176.2 3 1\n"
+	// Formula and ClassifyQuadrant are marked as covered (Count=1);
+	// everything else is absent and defaults to 0% coverage. Block
+	// extents are taken from the source so edits to crap.go do not
+	// silently invalidate the profile.
+	funcs, err := findFunctions(filepath.Join(modRoot, "internal", "crap", "crap.go"))
+	if err != nil {
+		t.Fatalf("parsing crap.go: %v", err)
+	}
+	profileContent := "mode: set\n"
+	for _, fn := range funcs {
+		if fn.name == "Formula" || fn.name == "ClassifyQuadrant" {
+			profileContent += fmt.Sprintf(
+				"github.com/unbound-force/gaze/internal/crap/crap.go:%d.%d,%d.%d 2 1\n",
+				fn.startLine, fn.startCol, fn.endLine, fn.endCol)
+		}
+	}
 
 	profileFile := filepath.Join(t.TempDir(), "cover.out")
 	if err := os.WriteFile(profileFile, []byte(profileContent), 0o644); err != nil {
@@ -1189,6 +1280,19 @@ func TestAnalyze_WithPrebuiltProfile(t *testing.T) {
 	if len(report.Summary.WorstCRAP) == 0 {
 		t.Error("expected at least one WorstCRAP entry")
 	}
+
+	// A single package yields one per-package summary matching the
+	// global one.
+	pkgSummary, ok := report.PackageSummaries["internal/crap"]
+	if !ok || len(report.PackageSummaries) != 1 {
+		t.Fatalf("expected one package summary for internal/crap, got %v", report.PackageSummaries)
+	}
+	if pkgSummary.TotalFunctions != report.Summary.TotalFunctions ||
+		pkgSummary.CRAPload != report.Summary.CRAPload {
+		t.Errorf("package summary %d/%d does not match global %d/%d",
+			pkgSummary.TotalFunctions, pkgSummary.CRAPload,
+			report.Summary.TotalFunctions, report.Summary.CRAPload)
+	}
 }
 
 // TestAnalyze_ContractCoverageFunc verifies that Analyze populates
@@ -1494,3 +1598,558 @@ func TestWriteText_RemediationBreakdown(t *testing.T) {
 		t.Errorf("expected '[decompose]' label on worst offender, got:\n%s", out)
 	}
 }
+
+// writeMergeModule creates a module with one file holding two
+// functions, Alpha (lines 3-6) and Beta (lines 8-11), and returns
+// the module directory.
+func writeMergeModule(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	src := "package m\n" +
+		"\n" +
+		"func Alpha(x int) int {\n" +
+		"\ty := x + 1\n" +
+		"\treturn y\n" +
+		"}\n" +
+		"\n" +
+		"func Beta(x int) int {\n" +
+		"\ty := x * 2\n" +
+		"\treturn y\n" +
+		"}\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/m\n\ngo 1.24\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "m.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// writeProfile writes a coverage profile with the given block lines.
+func writeProfile(t *testing.T, mode string, blocks ...string) string {
+	t.Helper()
+	content := "mode: " + mode + "\n" + strings.Join(blocks, "\n") + "\n"
+	path := filepath.Join(t.TempDir(), "cover.out")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParseCoverProfiles_MergesDifferentFunctions(t *testing.T) {
+	dir := writeMergeModule(t)
+	alphaOnly := writeProfile(t, "count",
+		"example.com/m/m.go:3.24,6.2 2 3",
+		"example.com/m/m.go:8.23,11.2 2 0",
+	)
+	betaOnly := writeProfile(t, "count",
+		"example.com/m/m.go:3.24,6.2 2 0",
+		"example.com/m/m.go:8.23,11.2 2 5",
+	)
+
+	// Each profile alone covers only one function.
+	single, err := ParseCoverProfile(alphaOnly, dir, nil)
+	if err != nil {
+		t.Fatalf("ParseCoverProfile: %v", err)
+	}
+	for _, fc := range single {
+		if fc.FuncName == "Beta" && fc.Percentage != 0 {
+			t.Errorf("Beta coverage from alpha profile = %.1f, want 0", fc.Percentage)
+		}
+	}
+
+	merged, err := ParseCoverProfiles([]string{alphaOnly, betaOnly}, dir, nil)
+	if err != nil {
+		t.Fatalf("ParseCoverProfiles: %v", err)
+	}
+	if len(merged) != 2 {
+		t.Fatalf("got %d function coverages, want 2", len(merged))
+	}
+	for _, fc := range merged {
+		if fc.Percentage != 100 {
+			t.Errorf("%s coverage = %.1f, want 100", fc.FuncName, fc.Percentage)
+		}
+		// Shared blocks are combined, not double-counted.
+		if fc.TotalStmts != 2 {
+			t.Errorf("%s total statements = %d, want 2", fc.FuncName, fc.TotalStmts)
+		}
+	}
+}
+
+func TestMergeProfiles_CombinesOverlappingBlocks(t *testing.T) {
+	tests := []struct {
+		mode      string
+		a, b      int
+		wantCount int
+	}{
+		{"count", 3, 4, 7},
+		{"atomic", 0, 2, 2},
+		{"set", 1, 1, 1},
+		{"set", 0, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s_%d_%d", tt.mode, tt.a, tt.b), func(t *testing.T) {
+			p1 := writeProfile(t, tt.mode, fmt.Sprintf("example.com/m/m.go:3.24,6.2 2 %d", tt.a))
+			p2 := writeProfile(t, tt.mode, fmt.Sprintf("example.com/m/m.go:3.24,6.2 2 %d", tt.b))
+
+			profiles, err := mergeProfiles([]string{p1, p2})
+			if err != nil {
+				t.Fatalf("mergeProfiles: %v", err)
+			}
+			if len(profiles) != 1 || len(profiles[0].Blocks) != 1 {
+				t.Fatalf("expected 1 profile with 1 block, got %+v", profiles)
+			}
+			if got := profiles[0].Blocks[0].Count; got != tt.wantCount {
+				t.Errorf("merged count = %d, want %d", got, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestAnalyze_MultipleCoverProfiles(t *testing.T) {
+	dir := writeMergeModule(t)
+	opts := DefaultOptions()
+	opts.CoverProfile = writeProfile(t, "set", "example.com/m/m.go:3.24,6.2 2 1")
+	opts.CoverProfiles = []string{writeProfile(t, "set", "example.com/m/m.go:8.23,11.2 2 1")}
+
+	rpt, err := Analyze([]string{"./..."}, dir, opts)
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if len(rpt.Scores) != 2 {
+		t.Fatalf("got %d scores, want 2", len(rpt.Scores))
+	}
+	for _, s := range rpt.Scores {
+		if s.LineCoverage != 100 {
+			t.Errorf("%s line coverage = %.1f, want 100", s.Function, s.LineCoverage)
+		}
+	}
+}
+
+func TestParseCoverProfiles_BranchData(t *testing.T) {
+	dir := writeMergeModule(t)
+	// Alpha: one small executed block and one large unexecuted one.
+	blocks := []string{
+		"example.com/m/m.go:3.24,4.12 3 2",
+		"example.com/m/m.go:4.12,6.2 1 0",
+	}
+
+	tests := []struct {
+		mode       string
+		wantBranch bool
+	}{
+		{"count", true},
+		{"atomic", true},
+		{"set", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			covs, err := ParseCoverProfiles([]string{writeProfile(t, tt.mode, blocks...)}, dir, nil)
+			if err != nil {
+				t.Fatalf("ParseCoverProfiles: %v", err)
+			}
+			var alpha FuncCoverage
+			for _, fc := range covs {
+				if fc.FuncName == "Alpha" {
+					alpha = fc
+				}
+			}
+			if alpha.Percentage != 75 {
+				t.Errorf("line coverage = %.1f, want 75", alpha.Percentage)
+			}
+			if alpha.HasBranchData() != tt.wantBranch {
+				t.Fatalf("HasBranchData = %v, want %v", alpha.HasBranchData(), tt.wantBranch)
+			}
+			if alpha.BranchPercentage() != 50 {
+				t.Errorf("branch coverage = %.1f, want 50", alpha.BranchPercentage())
+			}
+		})
+	}
+}
+
+func TestComputeScores_BranchMode(t *testing.T) {
+	stats := []gocyclo.Stat{
+		makeStat("pkg", "Blocks", "/src/foo.go", 10, 4),
+		makeStat("pkg", "NoBlocks", "/src/bar.go", 10, 4),
+	}
+	cm := buildCoverMap([]FuncCoverage{
+		{File: "/src/foo.go", StartLine: 10, Percentage: 75, CoveredBlocks: 1, TotalBlocks: 2, Mode: "set"},
+		{File: "/src/bar.go", StartLine: 10, Percentage: 75, Mode: "set"},
+	})
+
+	opts := DefaultOptions()
+	line := computeScores(stats, cm, opts, testImportPath)
+	for _, s := range line {
+		if s.BranchCoverage != nil {
+			t.Errorf("%s: line mode should not set BranchCoverage", s.Function)
+		}
+		if want := Formula(4, 75); s.CRAP != want {
+			t.Errorf("%s: line mode CRAP = %f, want %f", s.Function, s.CRAP, want)
+		}
+	}
+
+	opts.CoverageMode = CoverageBranch
+	branch := computeScores(stats, cm, opts, testImportPath)
+	if branch[0].BranchCoverage == nil || *branch[0].BranchCoverage != 50 {
+		t.Fatalf("Blocks: BranchCoverage = %v, want 50", branch[0].BranchCoverage)
+	}
+	if want := Formula(4, 50); branch[0].CRAP != want {
+		t.Errorf("Blocks: branch mode CRAP = %f, want %f", branch[0].CRAP, want)
+	}
+	if branch[0].LineCoverage != 75 {
+		t.Errorf("Blocks: LineCoverage = %.1f, want 75", branch[0].LineCoverage)
+	}
+	if branch[1].BranchCoverage != nil {
+		t.Error("NoBlocks: expected fallback to line coverage")
+	}
+	if want := Formula(4, 75); branch[1].CRAP != want {
+		t.Errorf("NoBlocks: fallback CRAP = %f, want %f", branch[1].CRAP, want)
+	}
+
+	summary := buildSummary(branch, opts)
+	if summary.CoverageMode != CoverageBranch {
+		t.Errorf("CoverageMode = %q, want %q", summary.CoverageMode, CoverageBranch)
+	}
+	if !strings.Contains(summary.CoverageNote, "1 function(s)") {
+		t.Errorf("CoverageNote = %q, want partial fallback note", summary.CoverageNote)
+	}
+}
+
+func TestBuildSummary_BranchFallback(t *testing.T) {
+	opts := DefaultOptions()
+	scores := []Score{{Function: "Foo", Complexity: 1, CRAP: 1}}
+
+	if s := buildSummary(scores, opts); s.CoverageMode != "" || s.CoverageNote != "" {
+		t.Errorf("line mode should leave coverage fields empty, got %q / %q",
+			s.CoverageMode, s.CoverageNote)
+	}
+
+	opts.CoverageMode = CoverageBranch
+	s := buildSummary(scores, opts)
+	if s.CoverageMode != CoverageLine {
+		t.Errorf("CoverageMode = %q, want %q", s.CoverageMode, CoverageLine)
+	}
+	if !strings.Contains(s.CoverageNote, "no blocks") {
+		t.Errorf("CoverageNote = %q, want fallback note", s.CoverageNote)
+	}
+}
+
+func TestAnalyze_InvalidCoverageMode(t *testing.T) {
+	opts := DefaultOptions()
+	opts.CoverageMode = "path"
+	_, err := Analyze([]string{"./..."}, t.TempDir(), opts)
+	if err == nil || !strings.Contains(err.Error(), "invalid coverage mode") {
+		t.Errorf("expected invalid coverage mode error, got %v", err)
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*Options)
+		wantErr string
+	}{
+		{"defaults", func(*Options) {}, ""},
+		{"custom quadrant thresholds", func(o *Options) {
+			o.ComplexityThreshold = 10
+			o.CoverageThreshold = 80
+		}, ""},
+		{"coverage threshold 100", func(o *Options) { o.CoverageThreshold = 100 }, ""},
+		{"coverage threshold above 100", func(o *Options) { o.CoverageThreshold = 101 }, "invalid coverage threshold"},
+		{"negative coverage threshold", func(o *Options) { o.CoverageThreshold = -1 }, "invalid coverage threshold"},
+		{"negative complexity threshold", func(o *Options) { o.ComplexityThreshold = -2 }, "invalid complexity threshold"},
+		{"invalid coverage mode", func(o *Options) { o.CoverageMode = "path" }, "invalid coverage mode"},
+		{"no-tests mode", func(o *Options) { o.CoverageMode = CoverageNone }, ""},
+		{"no-tests mode with profile", func(o *Options) {
+			o.CoverageMode = CoverageNone
+			o.CoverProfiles = []string{"cover.out"}
+		}, "cannot be combined with a cover profile"},
+		{"all gaze tiers", func(o *Options) {
+			o.GazeTiers = []taxonomy.Tier{taxonomy.TierP0, taxonomy.TierP1, taxonomy.TierP2, taxonomy.TierP3, taxonomy.TierP4}
+		}, ""},
+		{"invalid gaze tier", func(o *Options) { o.GazeTiers = []taxonomy.Tier{"P5"} }, "invalid GazeCRAP tier"},
+		{"ambiguous contractual", func(o *Options) { o.AmbiguousPolicy = AmbiguousContractual }, ""},
+		{"invalid ambiguous policy", func(o *Options) { o.AmbiguousPolicy = "strict" }, "invalid ambiguous policy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			tt.mutate(&opts)
+			err := opts.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestBuildSummary_QuadrantThresholds(t *testing.T) {
+	scores := []Score{
+		{Complexity: 5, CRAP: 5, GazeCRAP: ptrFloat(30), ContractCoverage: ptrFloat(0),
+			Quadrant: ptrQuadrant(Q3SimpleButUnderspecified)},
+	}
+
+	summary := buildSummary(scores, DefaultOptions())
+	qt := summary.QuadrantThresholds
+	if qt == nil {
+		t.Fatal("expected non-nil QuadrantThresholds")
+	}
+	if *qt != (QuadrantThresholds{CRAP: 15, GazeCRAP: 15}) {
+		t.Errorf("expected default thresholds 15/15, got %+v", *qt)
+	}
+
+	opts := DefaultOptions()
+	opts.ComplexityThreshold = 10
+	qt = buildSummary(scores, opts).QuadrantThresholds
+	if *qt != (QuadrantThresholds{Complexity: 10, GazeCRAP: 15}) {
+		t.Errorf("expected complexity 10 and GazeCRAP 15, got %+v", *qt)
+	}
+
+	opts = DefaultOptions()
+	opts.CoverageThreshold = 50
+	qt = buildSummary(scores, opts).QuadrantThresholds
+	if *qt != (QuadrantThresholds{CRAP: 15, Coverage: 50}) {
+		t.Errorf("expected CRAP 15 and coverage 50, got %+v", *qt)
+	}
+
+	if buildSummary([]Score{{Complexity: 1, CRAP: 1}}, opts).QuadrantThresholds != nil {
+		t.Error("expected nil QuadrantThresholds without contract coverage")
+	}
+}
+
+func TestWriteText_QuadrantThresholds(t *testing.T) {
+	tests := []struct {
+		thresholds QuadrantThresholds
+		want       string
+	}{
+		{QuadrantThresholds{CRAP: 15, GazeCRAP: 12.5}, "Thresholds: CRAP >= 15.0, GazeCRAP >= 12.5"},
+		{QuadrantThresholds{Complexity: 10, Coverage: 80}, "Thresholds: complexity >= 10, contract coverage < 80%"},
+		{QuadrantThresholds{CRAP: 15, Coverage: 80}, "Thresholds: CRAP >= 15.0, contract coverage < 80%"},
+	}
+	for _, tt := range tests {
+		thresholds := tt.thresholds
+		rpt := &Report{
+			Scores: []Score{{Function: "F", Complexity: 1, CRAP: 1}},
+			Summary: Summary{
+				TotalFunctions:     1,
+				CRAPThreshold:      15,
+				QuadrantCounts:     map[Quadrant]int{Q1Safe: 1},
+				QuadrantThresholds: &thresholds,
+			},
+		}
+
+		var buf bytes.Buffer
+		if err := WriteText(&buf, rpt); err != nil {
+			t.Fatalf("WriteText failed: %v", err)
+		}
+		if !strings.Contains(buf.String(), tt.want) {
+			t.Errorf("expected %q in output:\n%s", tt.want, buf.String())
+		}
+	}
+}
+
+func TestBuildPackageSummaries(t *testing.T) {
+	scores := []Score{
+		{Package: "a", Function: "A1", File: "/mod/internal/a/a.go", Complexity: 5, CRAP: 30},
+		{Package: "a", Function: "A2", File: "/mod/internal/a/b.go", Complexity: 1, CRAP: 1},
+		{Package: "main", Function: "main", File: "/mod/main.go", Complexity: 2, CRAP: 2},
+		{Package: "main", Function: "run", File: "/mod/cmd/tool/main.go", Complexity: 4, CRAP: 20},
+	}
+	opts := DefaultOptions()
+
+	got := buildPackageSummaries(scores, "/mod", opts)
+
+	want := map[string]struct {
+		functions int
+		crapload  int
+		avgCRAP   float64
+	}{
+		"internal/a": {2, 1, 15.5},
+		".":          {1, 0, 2},
+		"cmd/tool":   {1, 1, 20},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d package summaries, got %d: %v", len(want), len(got), got)
+	}
+	for key, w := range want {
+		s, ok := got[key]
+		if !ok {
+			t.Errorf("missing package summary %q", key)
+			continue
+		}
+		if s.TotalFunctions != w.functions || s.CRAPload != w.crapload || s.AvgCRAP != w.avgCRAP {
+			t.Errorf("%s: got functions=%d crapload=%d avg=%.1f, want %d/%d/%.1f",
+				key, s.TotalFunctions, s.CRAPload, s.AvgCRAP, w.functions, w.crapload, w.avgCRAP)
+		}
+		if s.RecommendedActions != nil {
+			t.Errorf("%s: expected no recommended actions", key)
+		}
+	}
+
+	if buildPackageSummaries(nil, "/mod", opts) != nil {
+		t.Error("expected nil package summaries for no scores")
+	}
+}
+
+func TestWriteText_PackageBreakdown(t *testing.T) {
+	rpt := &Report{
+		Scores: []Score{
+			{Function: "A", File: "a.go", Complexity: 5, CRAP: 30},
+			{Function: "B", File: "b.go", Complexity: 1, CRAP: 1},
+		},
+		Summary: Summary{TotalFunctions: 2, CRAPThreshold: 15, CRAPload: 1},
+		PackageSummaries: map[string]Summary{
+			"internal/good": {TotalFunctions: 1, AvgCRAP: 1},
+			"internal/bad":  {TotalFunctions: 1, AvgCRAP: 30, CRAPload: 1},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteText(&buf, rpt); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+	out := buf.String()
+
+	pkgIdx := strings.Index(out, "--- Packages ---")
+	sumIdx := strings.Index(out, "--- Summary ---")
+	if pkgIdx < 0 || pkgIdx > sumIdx {
+		t.Fatalf("expected package breakdown before summary:\n%s", out)
+	}
+	bad := strings.Index(out, "internal/bad")
+	good := strings.Index(out, "internal/good")
+	if bad < 0 || good < 0 || bad > good {
+		t.Errorf("expected internal/bad listed before internal/good:\n%s", out)
+	}
+
+	// A single package repeats the global summary, so no breakdown.
+	rpt.PackageSummaries = map[string]Summary{"internal/bad": {TotalFunctions: 2}}
+	buf.Reset()
+	if err := WriteText(&buf, rpt); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "--- Packages ---") {
+		t.Error("expected no package breakdown for a single package")
+	}
+}
+
+func TestAnalyze_NoTestsMode(t *testing.T) {
+	// A module whose tests cannot even compile: complexity-only
+	// mode must not try to run them.
+	dir := writeMergeModule(t)
+	if err := os.WriteFile(filepath.Join(dir, "m_test.go"), []byte("package m\n\nbroken\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	opts := DefaultOptions()
+	opts.CoverageMode = CoverageNone
+
+	rpt, err := Analyze([]string{"./..."}, dir, opts)
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if len(rpt.Scores) != 2 {
+		t.Fatalf("got %d scores, want 2", len(rpt.Scores))
+	}
+	for _, s := range rpt.Scores {
+		if s.CRAP != float64(s.Complexity) {
+			t.Errorf("%s: CRAP = %.1f, want complexity %d", s.Function, s.CRAP, s.Complexity)
+		}
+	}
+	if rpt.Summary.CoverageMode != CoverageNone || rpt.Summary.CoverageNote == "" {
+		t.Errorf("expected none coverage mode with note, got %q / %q",
+			rpt.Summary.CoverageMode, rpt.Summary.CoverageNote)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteText(&buf, rpt); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "n/a") {
+		t.Errorf("expected n/a coverage in text output:\n%s", buf.String())
+	}
+}
+
+func TestWriteYAML_ValidOutput(t *testing.T) {
+	gazeCRAP := 12.5
+	report := &Report{
+		Scores: []Score{
+			{
+				Package:      "pkg",
+				Function:     "Foo",
+				File:         "foo.go",
+				Line:         10,
+				Complexity:   5,
+				LineCoverage: 80,
+				CRAP:         5.8,
+				GazeCRAP:     &gazeCRAP,
+			},
+		},
+		Summary: Summary{
+			TotalFunctions: 1,
+			CRAPThreshold:  15,
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteYAML(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc map[string]any
+	if err := yaml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid YAML: %v", err)
+	}
+	scores, ok := doc["scores"].([]any)
+	if !ok || len(scores) != 1 {
+		t.Fatalf("scores = %v, want one entry:\n%s", doc["scores"], buf.String())
+	}
+	score := scores[0].(map[string]any)
+	if score["function"] != "Foo" || score["gaze_crap"] != 12.5 || score["line_coverage"] != 80 {
+		t.Errorf("score = %v, want JSON field names and values", score)
+	}
+	if !strings.HasPrefix(buf.String(), "scores:\n") {
+		t.Errorf("expected scores first, as in the JSON report:\n%s", buf.String())
+	}
+}
+
+func TestWriteText_NoColor(t *testing.T) {
+	rpt := &Report{
+		Scores: []Score{
+			{Package: "pkg", Function: "Foo", File: "foo.go", Line: 10, Complexity: 5, LineCoverage: 0, CRAP: 30},
+		},
+		Summary: Summary{TotalFunctions: 1, CRAPload: 1, CRAPThreshold: 15},
+	}
+
+	// CLICOLOR_FORCE would color the buffer; NO_COLOR overrides it.
+	t.Setenv("CLICOLOR_FORCE", "1")
+	t.Setenv("NO_COLOR", "1")
+	var buf bytes.Buffer
+	if err := WriteText(&buf, rpt); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("expected no escape codes with NO_COLOR set:\n%q", buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteTextOptions(&buf, rpt, TextOptions{Color: report.ColorAlways}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("expected escape codes with --color=always:\n%q", buf.String())
+	}
+
+	t.Setenv("NO_COLOR", "")
+	buf.Reset()
+	if err := WriteTextOptions(&buf, rpt, TextOptions{Color: report.ColorNever}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("expected no escape codes with --color=never:\n%q", buf.String())
+	}
+}
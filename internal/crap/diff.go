@@ -0,0 +1,200 @@
+package crap
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// diffEpsilon absorbs floating-point noise when comparing CRAP
+// scores across runs.
+const diffEpsilon = 1e-6
+
+// FunctionDelta describes how one function's CRAP score changed
+// between a baseline report and the current one.
+type FunctionDelta struct {
+	// ID is the stable function identifier (see FunctionID).
+	ID       string `json:"id"`
+	Package  string `json:"package"`
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+
+	// OldCRAP is the baseline score; nil for functions that are not
+	// in the baseline.
+	OldCRAP *float64 `json:"old_crap,omitempty"`
+	NewCRAP float64  `json:"new_crap"`
+	Delta   float64  `json:"delta"`
+}
+
+// ReportDiff is the result of comparing a CRAP report against a
+// baseline. Functions whose score is unchanged, and functions that
+// no longer exist, are not listed.
+type ReportDiff struct {
+	// Threshold is the CRAP threshold of the current report. Added
+	// functions at or above it count as regressions.
+	Threshold float64 `json:"threshold"`
+
+	// Added lists functions absent from the baseline.
+	Added []FunctionDelta `json:"added"`
+
+	// Worsened lists functions whose CRAP score increased.
+	Worsened []FunctionDelta `json:"worsened"`
+
+	// Improved lists functions whose CRAP score decreased.
+	Improved []FunctionDelta `json:"improved"`
+}
+
+// FunctionID returns the identifier used to match a function across
+// reports: s.ID, or for scores without one an ID derived from the
+// directory of s.File and the function name, the same fallback
+// Analyze uses for files outside the module. Reports written before
+// IDs were added should go through AssignIDs first, which derives
+// the import path the way Analyze does.
+func FunctionID(s Score) string {
+	if s.ID != "" {
+		return s.ID
+	}
+	return GenerateFunctionID(filepath.ToSlash(filepath.Dir(s.File)), s.Function)
+}
+
+// AssignIDs gives every score in rpt that has no ID the one Analyze
+// would assign it, hashing the import path of the score's file
+// within the module at moduleDir. It migrates baselines written
+// before IDs were added, whose scores only carry the short package
+// name. Relative file paths are resolved against moduleDir.
+func AssignIDs(rpt *Report, moduleDir string) {
+	importPath := importPathFunc(moduleDir)
+	for i := range rpt.Scores {
+		s := &rpt.Scores[i]
+		if s.ID != "" {
+			continue
+		}
+		file := s.File
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(moduleDir, file)
+		}
+		s.ID = GenerateFunctionID(importPath(file), s.Function)
+	}
+}
+
+// Diff compares the current report against a baseline and returns
+// the functions that were added, worsened, or improved. Each list is
+// sorted by largest change first, then by ID. It returns an error
+// if either report lists two functions with the same ID, since one
+// would otherwise shadow the other.
+func Diff(old, current *Report) (*ReportDiff, error) {
+	baseline, err := indexScores(old.Scores)
+	if err != nil {
+		return nil, fmt.Errorf("baseline: %w", err)
+	}
+	if _, err := indexScores(current.Scores); err != nil {
+		return nil, fmt.Errorf("current report: %w", err)
+	}
+
+	d := &ReportDiff{
+		Threshold: current.Summary.CRAPThreshold,
+		Added:     []FunctionDelta{},
+		Worsened:  []FunctionDelta{},
+		Improved:  []FunctionDelta{},
+	}
+	for _, s := range current.Scores {
+		fd := FunctionDelta{
+			ID:       FunctionID(s),
+			Package:  s.Package,
+			Function: s.Function,
+			File:     s.File,
+			Line:     s.Line,
+			NewCRAP:  s.CRAP,
+		}
+		prev, ok := baseline[fd.ID]
+		if !ok {
+			fd.Delta = s.CRAP
+			d.Added = append(d.Added, fd)
+			continue
+		}
+		oldCRAP := prev.CRAP
+		fd.OldCRAP = &oldCRAP
+		fd.Delta = s.CRAP - oldCRAP
+		switch {
+		case fd.Delta > diffEpsilon:
+			d.Worsened = append(d.Worsened, fd)
+		case fd.Delta < -diffEpsilon:
+			d.Improved = append(d.Improved, fd)
+		}
+	}
+
+	sortDeltas(d.Added, false)
+	sortDeltas(d.Worsened, false)
+	sortDeltas(d.Improved, true)
+	return d, nil
+}
+
+// indexScores maps each score's FunctionID to the score. It returns
+// an error naming both functions when two scores share an ID.
+func indexScores(scores []Score) (map[string]Score, error) {
+	byID := make(map[string]Score, len(scores))
+	for _, s := range scores {
+		id := FunctionID(s)
+		if prev, ok := byID[id]; ok {
+			return nil, fmt.Errorf("duplicate function ID %s: %s.%s (%s) and %s.%s (%s)",
+				id, prev.Package, prev.Function, prev.File, s.Package, s.Function, s.File)
+		}
+		byID[id] = s
+	}
+	return byID, nil
+}
+
+// sortDeltas orders deltas by largest change first (most negative
+// first when ascending is true), breaking ties by ID.
+func sortDeltas(deltas []FunctionDelta, ascending bool) {
+	sort.Slice(deltas, func(i, j int) bool {
+		if deltas[i].Delta != deltas[j].Delta {
+			if ascending {
+				return deltas[i].Delta < deltas[j].Delta
+			}
+			return deltas[i].Delta > deltas[j].Delta
+		}
+		return deltas[i].ID < deltas[j].ID
+	})
+}
+
+// Regressions returns the functions that make the comparison fail:
+// every worsened function, and added functions at or above the
+// threshold.
+func (d *ReportDiff) Regressions() []FunctionDelta {
+	var out []FunctionDelta
+	for _, fd := range d.Added {
+		if fd.NewCRAP >= d.Threshold {
+			out = append(out, fd)
+		}
+	}
+	return append(out, d.Worsened...)
+}
+
+// ReadJSON decodes a CRAP report previously written by WriteJSON.
+func ReadJSON(r io.Reader) (*Report, error) {
+	var rpt Report
+	if err := json.NewDecoder(r).Decode(&rpt); err != nil {
+		return nil, err
+	}
+	return &rpt, nil
+}
+
+// LoadReport reads a CRAP JSON report from path.
+func LoadReport(path string) (*Report, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	rpt, err := ReadJSON(f)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return rpt, nil
+}
@@ -0,0 +1,197 @@
+package crap
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func diffReport(threshold float64, scores ...Score) *Report {
+	return &Report{
+		Scores:  scores,
+		Summary: Summary{TotalFunctions: len(scores), CRAPThreshold: threshold},
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := diffReport(15,
+		Score{Package: "a", File: "a/a.go", Function: "Same", CRAP: 4},
+		Score{Package: "a", File: "a/a.go", Function: "Worse", CRAP: 6},
+		Score{Package: "a", File: "a/a.go", Function: "Better", CRAP: 40},
+		Score{Package: "a", File: "a/a.go", Function: "Gone", CRAP: 50},
+		Score{Package: "b", File: "b/b.go", Function: "Same", CRAP: 2},
+	)
+	current := diffReport(15,
+		Score{Package: "a", File: "a/a.go", Function: "Same", CRAP: 4, Line: 99},
+		Score{Package: "a", File: "a/a.go", Function: "Worse", CRAP: 12},
+		Score{Package: "a", File: "a/a.go", Function: "Better", CRAP: 10},
+		Score{Package: "a", File: "a/a.go", Function: "NewLow", CRAP: 3},
+		Score{Package: "a", File: "a/a.go", Function: "NewHigh", CRAP: 30},
+		Score{Package: "b", File: "b/b.go", Function: "Same", CRAP: 20},
+	)
+
+	d, err := Diff(old, current)
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+
+	ids := func(deltas []FunctionDelta) []string {
+		out := []string{}
+		for _, fd := range deltas {
+			out = append(out, fd.Package+"."+fd.Function)
+		}
+		return out
+	}
+	if got, want := ids(d.Added), []string{"a.NewHigh", "a.NewLow"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Added = %v, want %v", got, want)
+	}
+	if got, want := ids(d.Worsened), []string{"b.Same", "a.Worse"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Worsened = %v, want %v", got, want)
+	}
+	if got, want := ids(d.Improved), []string{"a.Better"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Improved = %v, want %v", got, want)
+	}
+	if d.Added[0].OldCRAP != nil {
+		t.Error("expected nil OldCRAP for an added function")
+	}
+	if d.Improved[0].Delta != -30 {
+		t.Errorf("expected delta -30, got %f", d.Improved[0].Delta)
+	}
+
+	if got, want := ids(d.Regressions()), []string{"a.NewHigh", "b.Same", "a.Worse"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Regressions = %v, want %v", got, want)
+	}
+}
+
+func TestDiff_BaselineWithoutIDs(t *testing.T) {
+	// Baselines written before Score.ID existed get the IDs Analyze
+	// assigns, derived from the import path of each score's file.
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/m\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "a", "a.go")
+	old := diffReport(15,
+		Score{Package: "a", Function: "F", File: file, CRAP: 5},
+		Score{Package: "a", Function: "G", File: filepath.Join("a", "a.go"), CRAP: 9},
+	)
+	current := diffReport(15,
+		Score{ID: GenerateFunctionID("example.com/m/a", "F"), Package: "a", Function: "F", File: file, Line: 40, CRAP: 8},
+		Score{ID: GenerateFunctionID("example.com/m/a", "G"), Package: "a", Function: "G", File: file, Line: 60, CRAP: 3},
+	)
+
+	AssignIDs(old, dir)
+	d, err := Diff(old, current)
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	if len(d.Added) != 0 || len(d.Worsened) != 1 || len(d.Improved) != 1 {
+		t.Fatalf("expected one worsened and one improved function, got %+v", d)
+	}
+	if d.Worsened[0].ID != current.Scores[0].ID {
+		t.Errorf("expected ID %s, got %s", current.Scores[0].ID, d.Worsened[0].ID)
+	}
+	if d.Improved[0].ID != current.Scores[1].ID {
+		t.Errorf("expected ID %s, got %s", current.Scores[1].ID, d.Improved[0].ID)
+	}
+}
+
+func TestDiff_DuplicateIDs(t *testing.T) {
+	// Two functions with one ID must fail rather than shadow each
+	// other, in either report.
+	dup := diffReport(15,
+		Score{ID: "fn-00000001", Package: "util", Function: "Run", File: "a/util/util.go", CRAP: 5},
+		Score{ID: "fn-00000001", Package: "util", Function: "Run", File: "b/util/util.go", CRAP: 30},
+	)
+	clean := diffReport(15, Score{ID: "fn-00000001", Package: "util", Function: "Run", CRAP: 5})
+
+	for name, tc := range map[string]struct{ old, current *Report }{
+		"baseline": {dup, clean},
+		"current":  {clean, dup},
+	} {
+		_, err := Diff(tc.old, tc.current)
+		if err == nil || !strings.Contains(err.Error(), "duplicate function ID fn-00000001") {
+			t.Errorf("%s: expected duplicate ID error, got %v", name, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), "a/util/util.go") || !strings.Contains(err.Error(), "b/util/util.go") {
+			t.Errorf("%s: expected both files in error, got %v", name, err)
+		}
+	}
+}
+
+func TestDiff_NoChanges(t *testing.T) {
+	rpt := diffReport(15, Score{Package: "a", Function: "F", CRAP: 20})
+	d, err := Diff(rpt, rpt)
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	if len(d.Added)+len(d.Worsened)+len(d.Improved) != 0 {
+		t.Errorf("expected empty diff, got %+v", d)
+	}
+	if len(d.Regressions()) != 0 {
+		t.Error("expected no regressions")
+	}
+}
+
+func TestLoadReport_RoundTrip(t *testing.T) {
+	rpt := diffReport(15, Score{Package: "a", Function: "F", File: "f.go", Line: 3, CRAP: 7.5})
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, rpt); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadReport(path)
+	if err != nil {
+		t.Fatalf("LoadReport: %v", err)
+	}
+	if !reflect.DeepEqual(got.Scores, rpt.Scores) {
+		t.Errorf("scores = %+v, want %+v", got.Scores, rpt.Scores)
+	}
+}
+
+func TestLoadReport_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadReport(path); err == nil || !strings.Contains(err.Error(), "parsing") {
+		t.Errorf("expected parse error, got %v", err)
+	}
+}
+
+func TestWriteText_BaselineDiff(t *testing.T) {
+	old := diffReport(15, Score{Package: "a", Function: "Worse", File: "w.go", CRAP: 6})
+	rpt := diffReport(15,
+		Score{Package: "a", Function: "Worse", File: "w.go", CRAP: 12},
+		Score{Package: "a", Function: "NewHigh", File: "n.go", CRAP: 30},
+	)
+	d, err := Diff(old, rpt)
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	rpt.Diff = d
+
+	var buf bytes.Buffer
+	if err := WriteText(&buf, rpt); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"--- Baseline Diff ---",
+		"1 worsened, 1 added, 0 improved",
+		"Worse  6.0 -> 12.0",
+		"NewHigh  30.0",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
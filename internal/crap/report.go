@@ -22,9 +22,18 @@ func WriteJSON(w io.Writer, report *Report) error {
 	return enc.Encode(report)
 }
 
+// WriteYAML writes the CRAP report as YAML to w, with the same
+// structure and field names as WriteJSON.
+func WriteYAML(w io.Writer, rpt *Report) error {
+	return report.EncodeYAML(w, rpt)
+}
+
 // writeScoreTable builds and writes the CRAP score table with
-// threshold markers and color styling.
-func writeScoreTable(w io.Writer, sorted []Score, threshold float64, styles report.Styles) {
+// threshold markers and color styling. When noCoverage is set the
+// coverage column shows "n/a". An EFFECTS column is added when any
+// score carries a side effect count.
+func writeScoreTable(w io.Writer, sorted []Score, threshold float64, noCoverage bool, styles report.Styles) {
+	withEffects := hasSideEffectCounts(sorted)
 	rows := make([][]string, 0, len(sorted))
 	for _, s := range sorted {
 		marker := ""
@@ -32,15 +41,31 @@ func writeScoreTable(w io.Writer, sorted []Score, threshold float64, styles repo
 			marker = " *"
 		}
 		file := shortenPath(s.File)
-		rows = append(rows, []string{
+		coverage := fmt.Sprintf("%.1f%%", s.LineCoverage)
+		if noCoverage {
+			coverage = "n/a"
+		}
+		row := []string{
 			fmt.Sprintf("%.1f%s", s.CRAP, marker),
 			fmt.Sprintf("%d", s.Complexity),
-			fmt.Sprintf("%.1f%%", s.LineCoverage),
-			s.Function,
-			fmt.Sprintf("%s:%d", file, s.Line),
-		})
+			coverage,
+		}
+		if withEffects {
+			effects := "-"
+			if s.SideEffectCount != nil {
+				effects = fmt.Sprintf("%d", *s.SideEffectCount)
+			}
+			row = append(row, effects)
+		}
+		rows = append(rows, append(row, s.Function, fmt.Sprintf("%s:%d", file, s.Line)))
 	}
 
+	headers := []string{"CRAP", "COMPLEXITY", "COVERAGE"}
+	if withEffects {
+		headers = append(headers, "EFFECTS")
+	}
+	headers = append(headers, "FUNCTION", "FILE")
+
 	t := table.New().
 		Border(lipgloss.NormalBorder()).
 		BorderStyle(styles.Border).
@@ -56,20 +81,156 @@ func writeScoreTable(w io.Writer, sorted []Score, threshold float64, styles repo
 			}
 			return lipgloss.NewStyle()
 		}).
-		Headers("CRAP", "COMPLEXITY", "COVERAGE", "FUNCTION", "FILE").
+		Headers(headers...).
 		Rows(rows...)
 
 	_, _ = fmt.Fprintln(w, t)
 }
 
+// hasSideEffectCounts reports whether any score carries a side
+// effect count.
+func hasSideEffectCounts(scores []Score) bool {
+	for _, s := range scores {
+		if s.SideEffectCount != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// effectBuckets are the side effect count ranges of the histogram,
+// as inclusive lower bounds.
+var effectBuckets = []struct {
+	label string
+	min   int
+}{
+	{"0", 0},
+	{"1-2", 1},
+	{"3-5", 3},
+	{"6-9", 6},
+	{"10+", 10},
+}
+
+// writeEffectHistogram writes how many functions have each range of
+// side effect counts, and how many of the functions in each range
+// are at or above the CRAP threshold: complex, poorly tested
+// functions with many effects are the riskiest to change. It is
+// omitted when no score carries a count.
+func writeEffectHistogram(w io.Writer, scores []Score, threshold float64, styles report.Styles) {
+	if !hasSideEffectCounts(scores) {
+		return
+	}
+	counts := make([]int, len(effectBuckets))
+	crappy := make([]int, len(effectBuckets))
+	maxCount := 0
+	for _, s := range scores {
+		if s.SideEffectCount == nil {
+			continue
+		}
+		b := len(effectBuckets) - 1
+		for b > 0 && *s.SideEffectCount < effectBuckets[b].min {
+			b--
+		}
+		counts[b]++
+		if s.CRAP >= threshold {
+			crappy[b]++
+		}
+		maxCount = max(maxCount, counts[b])
+	}
+
+	const width = 30
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, styles.Header.Render("--- Side Effects per Function ---"))
+	for i, b := range effectBuckets {
+		bar := ""
+		if counts[i] > 0 {
+			bar = strings.Repeat("#", max(1, counts[i]*width/maxCount))
+		}
+		line := fmt.Sprintf("  %-4s  %-*s  %d", b.label, width, bar, counts[i])
+		if crappy[i] > 0 {
+			line += styles.CRAPBad.Render(fmt.Sprintf(" (%d at or above CRAP threshold)", crappy[i]))
+		}
+		_, _ = fmt.Fprintln(w, line)
+	}
+}
+
+// writePackageSection writes a per-package breakdown table, worst
+// CRAPload first. It is omitted for single-package reports, where
+// it would repeat the global summary.
+func writePackageSection(w io.Writer, summaries map[string]Summary, styles report.Styles) {
+	if len(summaries) < 2 {
+		return
+	}
+	keys := make([]string, 0, len(summaries))
+	for k := range summaries {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := summaries[keys[i]], summaries[keys[j]]
+		if a.CRAPload != b.CRAPload {
+			return a.CRAPload > b.CRAPload
+		}
+		if a.AvgCRAP != b.AvgCRAP {
+			return a.AvgCRAP > b.AvgCRAP
+		}
+		return keys[i] < keys[j]
+	})
+
+	rows := make([][]string, 0, len(keys))
+	for _, k := range keys {
+		s := summaries[k]
+		rows = append(rows, []string{
+			k,
+			fmt.Sprintf("%d", s.TotalFunctions),
+			fmt.Sprintf("%.1f", s.AvgCRAP),
+			fmt.Sprintf("%d", s.CRAPload),
+		})
+	}
+
+	t := table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(styles.Border).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			if row == table.HeaderRow {
+				return styles.Header
+			}
+			if col == 3 && row >= 0 && row < len(keys) {
+				if summaries[keys[row]].CRAPload > 0 {
+					return styles.CRAPBad
+				}
+				return styles.CRAPGood
+			}
+			return lipgloss.NewStyle()
+		}).
+		Headers("PACKAGE", "FUNCTIONS", "AVG CRAP", "CRAPLOAD").
+		Rows(rows...)
+
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, styles.Header.Render("--- Packages ---"))
+	_, _ = fmt.Fprintln(w, t)
+}
+
 // writeSummarySection writes the summary statistics section including
 // CRAPload, GazeCRAP threshold, and GazeCRAPload (when available).
 func writeSummarySection(w io.Writer, summary Summary, styles report.Styles) {
 	_, _ = fmt.Fprintln(w)
 	_, _ = fmt.Fprintln(w, styles.Header.Render("--- Summary ---"))
 	_, _ = fmt.Fprintf(w, "%s  %d\n", styles.SummaryLabel.Render("Functions analyzed:"), summary.TotalFunctions)
+	if summary.Filter == FilterExportedOnly {
+		_, _ = fmt.Fprintf(w, "%s  %s\n", styles.SummaryLabel.Render("Filter:"), "exported functions only")
+	}
+	if summary.CoverageMode != "" {
+		_, _ = fmt.Fprintf(w, "%s  %s\n", styles.SummaryLabel.Render("Coverage mode:"), summary.CoverageMode)
+	}
+	if summary.CoverageNote != "" {
+		_, _ = fmt.Fprintf(w, "%s  %s\n", styles.SummaryLabel.Render("Coverage note:"), summary.CoverageNote)
+	}
 	_, _ = fmt.Fprintf(w, "%s  %.1f\n", styles.SummaryLabel.Render("Avg complexity:"), summary.AvgComplexity)
-	_, _ = fmt.Fprintf(w, "%s  %.1f%%\n", styles.SummaryLabel.Render("Avg line coverage:"), summary.AvgLineCoverage)
+	if summary.CoverageMode == CoverageNone {
+		_, _ = fmt.Fprintf(w, "%s  %s\n", styles.SummaryLabel.Render("Avg line coverage:"), "n/a")
+	} else {
+		_, _ = fmt.Fprintf(w, "%s  %.1f%%\n", styles.SummaryLabel.Render("Avg line coverage:"), summary.AvgLineCoverage)
+	}
 	_, _ = fmt.Fprintf(w, "%s  %.1f\n", styles.SummaryLabel.Render("Avg CRAP score:"), summary.AvgCRAP)
 	_, _ = fmt.Fprintf(w, "%s  %.0f\n", styles.SummaryLabel.Render("CRAP threshold:"), summary.CRAPThreshold)
 
@@ -89,13 +250,25 @@ func writeSummarySection(w io.Writer, summary Summary, styles report.Styles) {
 	}
 }
 
-// writeQuadrantSection writes the quadrant breakdown section.
-func writeQuadrantSection(w io.Writer, counts map[Quadrant]int, styles report.Styles) {
+// writeQuadrantSection writes the quadrant breakdown section,
+// including the thresholds the quadrants were computed with.
+func writeQuadrantSection(w io.Writer, counts map[Quadrant]int, thresholds *QuadrantThresholds, styles report.Styles) {
 	if len(counts) == 0 {
 		return
 	}
 	_, _ = fmt.Fprintln(w)
 	_, _ = fmt.Fprintln(w, styles.Header.Render("--- Quadrant Breakdown ---"))
+	if thresholds != nil {
+		crapAxis := fmt.Sprintf("CRAP >= %.1f", thresholds.CRAP)
+		if thresholds.Complexity > 0 {
+			crapAxis = fmt.Sprintf("complexity >= %d", thresholds.Complexity)
+		}
+		gazeAxis := fmt.Sprintf("GazeCRAP >= %.1f", thresholds.GazeCRAP)
+		if thresholds.Coverage > 0 {
+			gazeAxis = fmt.Sprintf("contract coverage < %.0f%%", thresholds.Coverage)
+		}
+		_, _ = fmt.Fprintln(w, styles.Muted.Render("  Thresholds: "+crapAxis+", "+gazeAxis))
+	}
 	for _, q := range []Quadrant{Q1Safe, Q2ComplexButTested, Q3SimpleButUnderspecified, Q4Dangerous} {
 		count := counts[q]
 		_, _ = fmt.Fprintf(w, "  %-30s  %d\n", string(q), count)
@@ -164,16 +337,70 @@ func writeWorstSection(w io.Writer, worst []Score, threshold float64, styles rep
 		_, _ = fmt.Fprintf(w, "  %d. %s  %s%s%s  %s\n",
 			i+1, score, s.Function, strategyLabel, reasonLabel,
 			styles.Muted.Render(fmt.Sprintf("(%s:%d)", shortenPath(s.File), s.Line)))
+		if s.ComplexityDetail != nil {
+			_, _ = fmt.Fprintln(w, styles.Muted.Render(
+				fmt.Sprintf("     complexity %d: %s", s.Complexity, s.ComplexityDetail)))
+		}
+	}
+}
+
+// writeDiffSection writes the comparison against a baseline report:
+// regressions first, then improvements.
+func writeDiffSection(w io.Writer, d *ReportDiff, styles report.Styles) {
+	if d == nil {
+		return
+	}
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, styles.Header.Render("--- Baseline Diff ---"))
+	_, _ = fmt.Fprintf(w, "  %d worsened, %d added, %d improved\n",
+		len(d.Worsened), len(d.Added), len(d.Improved))
+
+	for _, fd := range d.Worsened {
+		_, _ = fmt.Fprintf(w, "  %s %s  %.1f -> %.1f  %s\n",
+			styles.CRAPBad.Render("worsened"), fd.Function, *fd.OldCRAP, fd.NewCRAP,
+			styles.Muted.Render(fmt.Sprintf("(%s:%d)", shortenPath(fd.File), fd.Line)))
+	}
+	for _, fd := range d.Added {
+		label := styles.Muted.Render("added")
+		if fd.NewCRAP >= d.Threshold {
+			label = styles.CRAPBad.Render("added")
+		}
+		_, _ = fmt.Fprintf(w, "  %s %s  %.1f  %s\n",
+			label, fd.Function, fd.NewCRAP,
+			styles.Muted.Render(fmt.Sprintf("(%s:%d)", shortenPath(fd.File), fd.Line)))
+	}
+	for _, fd := range d.Improved {
+		_, _ = fmt.Fprintf(w, "  %s %s  %.1f -> %.1f  %s\n",
+			styles.CRAPGood.Render("improved"), fd.Function, *fd.OldCRAP, fd.NewCRAP,
+			styles.Muted.Render(fmt.Sprintf("(%s:%d)", shortenPath(fd.File), fd.Line)))
 	}
 }
 
+// TextOptions controls the text output format.
+type TextOptions struct {
+	// Color selects when ANSI colors are used. The zero value, like
+	// report.ColorAuto, colors output only when w is a terminal and
+	// NO_COLOR is unset.
+	Color report.ColorMode
+}
+
 // WriteText writes the CRAP report as human-readable styled text to w.
 // Returns nil on success, or an error if writing to w fails.
 func WriteText(w io.Writer, rpt *Report) error {
-	styles := report.DefaultStyles()
+	return WriteTextOptions(w, rpt, TextOptions{})
+}
+
+// WriteTextOptions writes the CRAP report as human-readable styled
+// text to w using the given options.
+func WriteTextOptions(w io.Writer, rpt *Report, opts TextOptions) error {
+	styles := report.NewStyles(opts.Color.Renderer(w))
 
 	if len(rpt.Scores) == 0 {
-		_, _ = fmt.Fprintln(w, styles.Muted.Render("No functions analyzed."))
+		msg := "No functions analyzed."
+		if rpt.Summary.Filter == FilterExportedOnly {
+			msg = "No exported functions analyzed."
+		}
+		_, _ = fmt.Fprintln(w, styles.Muted.Render(msg))
 		return nil
 	}
 
@@ -184,12 +411,15 @@ func WriteText(w io.Writer, rpt *Report) error {
 	})
 
 	threshold := rpt.Summary.CRAPThreshold
-	writeScoreTable(w, sorted, threshold, styles)
+	writeScoreTable(w, sorted, threshold, rpt.Summary.CoverageMode == CoverageNone, styles)
+	writePackageSection(w, rpt.PackageSummaries, styles)
 	writeSummarySection(w, rpt.Summary, styles)
+	writeEffectHistogram(w, rpt.Scores, threshold, styles)
 	writeSSADiagnostics(w, rpt.Summary.SSADegradedPackages, styles)
-	writeQuadrantSection(w, rpt.Summary.QuadrantCounts, styles)
+	writeQuadrantSection(w, rpt.Summary.QuadrantCounts, rpt.Summary.QuadrantThresholds, styles)
 	writeRemediationSection(w, rpt.Summary.FixStrategyCounts, styles)
 	writeWorstSection(w, rpt.Summary.WorstCRAP, threshold, styles)
+	writeDiffSection(w, rpt.Diff, styles)
 
 	return nil
 }
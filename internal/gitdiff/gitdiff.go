@@ -0,0 +1,176 @@
+// Package gitdiff reports which source lines changed relative to a
+// git revision, so analysis can be limited to the functions a branch
+// touches, and checks out revisions so they can be analyzed side by
+// side with the working tree.
+package gitdiff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ChangedLines maps absolute file paths to the set of line numbers
+// (1-based, in the working tree version of the file) that differ
+// from a revision.
+type ChangedLines map[string]map[int]bool
+
+// Changed runs "git diff" in dir against ref and returns the changed
+// lines of every modified or added .go file, including uncommitted
+// changes. Deleted files are omitted. A deletion inside a file marks
+// the line preceding it, so the function it was removed from still
+// counts as changed.
+func Changed(dir, ref string) (ChangedLines, error) {
+	root, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	// --end-of-options keeps a ref such as "--output=file" from
+	// being read as an option.
+	diff, err := git(dir, "diff", "--unified=0", "--no-color", "--no-ext-diff", "--end-of-options", ref, "--", "*.go")
+	if err != nil {
+		return nil, err
+	}
+	return Parse(strings.NewReader(diff), strings.TrimSpace(root))
+}
+
+// Worktree is a temporary checkout of a revision, created by
+// Checkout.
+type Worktree struct {
+	// Root is the top-level directory of the checkout.
+	Root string
+
+	// RepoRoot is the top-level directory of the repository the
+	// checkout was made from.
+	RepoRoot string
+}
+
+// Checkout checks out ref in a new, detached git worktree of the
+// repository containing dir, in a temporary directory. The caller
+// must call Remove when done with it.
+func Checkout(dir, ref string) (*Worktree, error) {
+	root, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	// Resolve ref to a commit first: git worktree add accepts options
+	// after its positional arguments, so a ref such as "--lock" would
+	// otherwise be read as one.
+	commit, err := git(dir, "rev-parse", "--verify", "--end-of-options", ref+"^{commit}")
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := os.MkdirTemp("", "gaze-worktree-")
+	if err != nil {
+		return nil, err
+	}
+	// Resolve symlinks (e.g. /tmp on macOS) so paths reported by
+	// the go command have Root as their prefix.
+	if resolved, err := filepath.EvalSymlinks(tmp); err == nil {
+		tmp = resolved
+	}
+	if _, err := git(dir, "worktree", "add", "--detach", "--quiet", tmp, strings.TrimSpace(commit)); err != nil {
+		_ = os.RemoveAll(tmp)
+		return nil, err
+	}
+	return &Worktree{Root: tmp, RepoRoot: strings.TrimSpace(root)}, nil
+}
+
+// Path returns the path in the checkout that corresponds to path, a
+// file or directory in the repository. Paths outside the repository
+// are returned unchanged.
+func (w *Worktree) Path(path string) string {
+	rel, err := filepath.Rel(w.RepoRoot, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return filepath.Join(w.Root, rel)
+}
+
+// Remove deletes the checkout and unregisters it from the
+// repository.
+func (w *Worktree) Remove() error {
+	_, err := git(w.RepoRoot, "worktree", "remove", "--force", w.Root)
+	if rmErr := os.RemoveAll(w.Root); err == nil {
+		err = rmErr
+	}
+	return err
+}
+
+// git runs a git subcommand in dir and returns its stdout.
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok && len(ee.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(ee.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return string(out), nil
+}
+
+// hunkHeader matches the new-file range of a unified diff hunk,
+// e.g. "@@ -10,2 +12,3 @@". A missing count means 1.
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// Parse reads a unified diff (as produced by "git diff --unified=0")
+// and returns the changed lines of each file in the new version,
+// keyed by root joined with the file's repository-relative path.
+func Parse(r io.Reader, root string) (ChangedLines, error) {
+	changed := make(ChangedLines)
+	var lines map[int]bool
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		text := sc.Text()
+		switch {
+		case strings.HasPrefix(text, "+++ "):
+			name := strings.TrimPrefix(text, "+++ ")
+			if name == "/dev/null" {
+				lines = nil // deleted file
+				continue
+			}
+			name = strings.TrimPrefix(name, "b/")
+			path := filepath.Join(root, filepath.FromSlash(name))
+			lines = changed[path]
+			if lines == nil {
+				lines = make(map[int]bool)
+				changed[path] = lines
+			}
+		case strings.HasPrefix(text, "@@ "):
+			if lines == nil {
+				continue
+			}
+			m := hunkHeader.FindStringSubmatch(text)
+			if m == nil {
+				return nil, fmt.Errorf("malformed hunk header %q", text)
+			}
+			start, _ := strconv.Atoi(m[1])
+			count := 1
+			if m[2] != "" {
+				count, _ = strconv.Atoi(m[2])
+			}
+			if count == 0 {
+				// Pure deletion after line start.
+				lines[max(start, 1)] = true
+				continue
+			}
+			for l := start; l < start+count; l++ {
+				lines[l] = true
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
@@ -0,0 +1,177 @@
+package gitdiff
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleDiff = `diff --git a/pkg/a.go b/pkg/a.go
+index 1111111..2222222 100644
+--- a/pkg/a.go
++++ b/pkg/a.go
+@@ -3,0 +4,2 @@ func A() {
++	x := 1
++	_ = x
+@@ -10 +12 @@ func B() {
+-	return 1
++	return 2
+@@ -20,3 +21,0 @@ func C() {
+-	a()
+-	b()
+-	c()
+diff --git a/new.go b/new.go
+new file mode 100644
+--- /dev/null
++++ b/new.go
+@@ -0,0 +1,3 @@
++package m
++
++func New() {}
+diff --git a/gone.go b/gone.go
+deleted file mode 100644
+--- a/gone.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-package m
+-func Gone() {}
+`
+
+func TestParse(t *testing.T) {
+	got, err := Parse(strings.NewReader(sampleDiff), "/repo")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := ChangedLines{
+		filepath.Join("/repo", "pkg", "a.go"): {4: true, 5: true, 12: true, 21: true},
+		filepath.Join("/repo", "new.go"):      {1: true, 2: true, 3: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse = %v, want %v", got, want)
+	}
+}
+
+func TestParse_MalformedHunk(t *testing.T) {
+	diff := "+++ b/a.go\n@@ garbage @@\n"
+	if _, err := Parse(strings.NewReader(diff), "/repo"); err == nil {
+		t.Error("expected error for malformed hunk header")
+	}
+}
+
+// testRepo creates an empty git repository in a temporary directory
+// and returns it with helpers to run git and write files in it. The
+// test is skipped if git is not installed.
+func testRepo(t *testing.T) (dir string, run func(args ...string), write func(name, content string)) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir = t.TempDir()
+	run = func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com",
+			"GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write = func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("init", "-q")
+	return dir, run, write
+}
+
+func TestChanged(t *testing.T) {
+	dir, run, write := testRepo(t)
+	write("a.go", "package m\n\nfunc A() int {\n\treturn 1\n}\n")
+	write("notes.md", "hello\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "init")
+
+	// One committed-after change and one uncommitted change.
+	write("a.go", "package m\n\nfunc A() int {\n\treturn 2\n}\n")
+	write("notes.md", "changed\n")
+
+	got, err := Changed(dir, "HEAD")
+	if err != nil {
+		t.Fatalf("Changed: %v", err)
+	}
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ChangedLines{filepath.Join(root, "a.go"): {4: true}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Changed = %v, want %v", got, want)
+	}
+
+	if _, err := Changed(dir, "no-such-ref"); err == nil {
+		t.Error("expected error for unknown ref")
+	}
+
+	out := filepath.Join(t.TempDir(), "out")
+	if _, err := Changed(dir, "--output="+out); err == nil {
+		t.Error("expected error for a ref that looks like an option")
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Errorf("ref was parsed as an option: %v", err)
+	}
+}
+
+func TestCheckout(t *testing.T) {
+	dir, run, write := testRepo(t)
+	write("a.go", "package m\n\nfunc A() int { return 1 }\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "init")
+	write("a.go", "package m\n\nfunc A() int { return 2 }\n")
+
+	wt, err := Checkout(dir, "HEAD")
+	if err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wt.RepoRoot != root {
+		t.Errorf("RepoRoot = %q, want %q", wt.RepoRoot, root)
+	}
+	path := wt.Path(filepath.Join(root, "a.go"))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading checked out file: %v", err)
+	}
+	if !strings.Contains(string(data), "return 1") {
+		t.Errorf("checkout has working tree content:\n%s", data)
+	}
+	if got := wt.Path("/elsewhere/b.go"); got != "/elsewhere/b.go" {
+		t.Errorf("Path outside repository = %q, want it unchanged", got)
+	}
+
+	if err := wt.Remove(); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if _, err := os.Stat(wt.Root); !os.IsNotExist(err) {
+		t.Errorf("checkout still exists after Remove: %v", err)
+	}
+
+	if _, err := Checkout(dir, "no-such-ref"); err == nil {
+		t.Error("expected error for unknown ref")
+	}
+
+	// Read as an option, "--lock" would check out HEAD into a locked
+	// worktree instead of failing.
+	if wt, err := Checkout(dir, "--lock"); err == nil {
+		t.Errorf("expected error for a ref that looks like an option, got checkout %s", wt.Root)
+	}
+}
@@ -0,0 +1,80 @@
+// Package gofiles classifies Go source files, so that commands agree
+// on which files (such as generated code) to leave out of analysis.
+package gofiles
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// generatedRegexp matches the Go convention for generated file headers:
+// "^// Code generated .* DO NOT EDIT\.$"
+var generatedRegexp = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+// headerLines bounds how far into a file IsGenerated looks for the
+// generated-code header. Generators put it at the top, at most after
+// a license block.
+const headerLines = 30
+
+// generatedSuffixes are file name endings used by common generators
+// (protoc, grpc-gateway, Kubernetes deepcopy-gen, go:generate tools
+// by convention) for files that may lack the standard header.
+var generatedSuffixes = []string{
+	".pb.go",
+	".pb.gw.go",
+	"_gen.go",
+	"_generated.go",
+}
+
+// IsGenerated reports whether the Go source file at path was
+// generated. A file is generated if its name matches a common
+// generator convention (such as "api.pb.go" or
+// "zz_generated.deepcopy.go"), or if a
+// "// Code generated ... DO NOT EDIT." line appears before the
+// package clause within the first lines of the file, per the Go
+// convention. Unreadable files are reported as not generated unless
+// their name matches.
+func IsGenerated(path string) bool {
+	return hasGeneratedName(path) || hasGeneratedHeader(path)
+}
+
+// hasGeneratedName reports whether the base name of path follows a
+// generator naming convention.
+func hasGeneratedName(path string) bool {
+	base := filepath.Base(path)
+	if strings.HasPrefix(base, "zz_generated") {
+		return true
+	}
+	for _, suffix := range generatedSuffixes {
+		if strings.HasSuffix(base, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasGeneratedHeader reports whether the file at path carries the
+// generated-code header before its package clause.
+func hasGeneratedHeader(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for n := 0; n < headerLines && scanner.Scan(); n++ {
+		trimmed := strings.TrimSpace(scanner.Text())
+		// Stop scanning once we reach the package clause.
+		if strings.HasPrefix(trimmed, "package ") {
+			return false
+		}
+		if generatedRegexp.MatchString(trimmed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package gofiles
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFile writes content to name in a temporary directory and
+// returns its path.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestIsGenerated_Header(t *testing.T) {
+	path := writeFile(t, "gen.go", `// Code generated by protoc-gen-go. DO NOT EDIT.
+
+package pb
+
+func Foo() {}
+`)
+	if !IsGenerated(path) {
+		t.Error("expected file to be detected as generated")
+	}
+}
+
+func TestIsGenerated_HeaderAfterLicense(t *testing.T) {
+	path := writeFile(t, "gen.go", `// Copyright 2024 The Authors.
+// Licensed under the Apache License, Version 2.0.
+
+// Code generated by mockgen. DO NOT EDIT.
+
+package mocks
+`)
+	if !IsGenerated(path) {
+		t.Error("expected header after a license block to be detected")
+	}
+}
+
+func TestIsGenerated_NotGenerated(t *testing.T) {
+	path := writeFile(t, "normal.go", `// Package foo provides functionality.
+package foo
+
+func Bar() {}
+`)
+	if IsGenerated(path) {
+		t.Error("expected file NOT to be detected as generated")
+	}
+}
+
+func TestIsGenerated_HeaderAfterPackage(t *testing.T) {
+	// A "Code generated" comment AFTER the package clause should
+	// NOT count as generated (per Go convention).
+	path := writeFile(t, "late.go", `package foo
+
+// Code generated by something. DO NOT EDIT.
+func Baz() {}
+`)
+	if IsGenerated(path) {
+		t.Error("comment after package clause should not be detected as generated")
+	}
+}
+
+func TestIsGenerated_HeaderBeyondFirstLines(t *testing.T) {
+	content := strings.Repeat("//\n", headerLines) +
+		"// Code generated by something. DO NOT EDIT.\n\npackage foo\n"
+	if IsGenerated(writeFile(t, "deep.go", content)) {
+		t.Errorf("header after the first %d lines should not be detected", headerLines)
+	}
+}
+
+func TestIsGenerated_FileName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"api.pb.go", true},
+		{"api.pb.gw.go", true},
+		{"zz_generated.deepcopy.go", true},
+		{"types_gen.go", true},
+		{"client_generated.go", true},
+		{"generator.go", false},
+		{"pb.go", false},
+	}
+	for _, tt := range tests {
+		path := writeFile(t, tt.name, "package foo\n")
+		if got := IsGenerated(path); got != tt.want {
+			t.Errorf("IsGenerated(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIsGenerated_NonexistentFile(t *testing.T) {
+	if IsGenerated("/nonexistent/path/file.go") {
+		t.Error("nonexistent file should return false")
+	}
+	if !IsGenerated("/nonexistent/path/file.pb.go") {
+		t.Error("generated file name should be detected without reading the file")
+	}
+}
+
+func BenchmarkIsGenerated_NotGenerated(b *testing.B) {
+	// Use this test file itself as a non-generated file.
+	for i := 0; i < b.N; i++ {
+		IsGenerated("gofiles_test.go")
+	}
+}
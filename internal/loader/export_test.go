@@ -0,0 +1,12 @@
+package loader
+
+import "golang.org/x/tools/go/packages"
+
+// SetLoadPackages replaces the function used to call packages.Load
+// and returns a func that restores it. For testing the retry path
+// without real go command failures.
+func SetLoadPackages(f func(*packages.Config, ...string) ([]*packages.Package, error)) (restore func()) {
+	prev := loadPackages
+	loadPackages = f
+	return func() { loadPackages = prev }
+}
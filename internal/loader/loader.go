@@ -3,11 +3,23 @@
 package loader
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"go/token"
+	"go/types"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
 )
 
 // LoadMode is the minimum set of flags needed for SSA-ready analysis.
@@ -19,7 +31,8 @@ const LoadMode = packages.NeedName |
 	packages.NeedTypes |
 	packages.NeedSyntax |
 	packages.NeedTypesInfo |
-	packages.NeedTypesSizes
+	packages.NeedTypesSizes |
+	packages.NeedModule
 
 // Result holds the loaded package along with convenience accessors.
 type Result struct {
@@ -28,18 +41,219 @@ type Result struct {
 
 	// Fset is the shared file set for position information.
 	Fset *token.FileSet
+
+	// SSA is the SSA form of Pkg, built only when requested with
+	// Options.SSA. It is nil otherwise, or if SSA construction
+	// failed.
+	SSA *ssa.Package
+
+	// XTest is the external test package ("pkg_test") of Pkg, when
+	// loaded with Options.Tests and the package has one. It is nil
+	// otherwise.
+	XTest *packages.Package
 }
 
-// Load loads a Go package at the given import path or file pattern.
-// It returns the loaded package result or an error if loading or
-// type-checking fails.
-func Load(pattern string) (*Result, error) {
+// Options configures LoadWithOptions.
+type Options struct {
+	// SSA additionally builds the SSA form of the loaded package,
+	// for analyzers that need data flow rather than syntax. Function
+	// bodies are built for the loaded package only; its dependencies
+	// are represented by their types. SSA construction adds
+	// noticeably to load time, so it is off by default.
+	SSA bool
+
+	// Context, when non-nil, bounds package loading: if it is
+	// canceled or its deadline passes, the underlying go list
+	// invocation is stopped and loading fails.
+	Context context.Context
+
+	// Dir is the directory patterns are resolved in, as if the go
+	// command were run there. Empty means the current directory.
+	Dir string
+
+	// Tests loads the package together with its _test.go files.
+	// Result.Pkg is then the test variant of the package, which
+	// holds both its regular and its in-package test files, and
+	// Result.XTest the external test package, if there is one.
+	Tests bool
+
+	// Build selects the files that make up each package, as the
+	// go command's -tags flag and GOOS/GOARCH environment do.
+	Build Build
+
+	// Attempts is how many times a load that fails transiently,
+	// such as when concurrent go commands contend for the module
+	// cache, is tried before the error is returned. Zero means
+	// DefaultAttempts; 1 disables retries. Type and syntax errors
+	// are never retried.
+	Attempts int
+
+	// Backoff is the wait before the first retry, doubling for each
+	// one after. Zero means DefaultBackoff.
+	Backoff time.Duration
+}
+
+const (
+	// DefaultAttempts is the number of tries for a transiently
+	// failing load when Options.Attempts is zero.
+	DefaultAttempts = 3
+
+	// DefaultBackoff is the wait before the first retry when
+	// Options.Backoff is zero.
+	DefaultBackoff = 250 * time.Millisecond
+)
+
+// retry returns the attempts and initial backoff o selects.
+func (o Options) retry() (int, time.Duration) {
+	attempts, backoff := o.Attempts, o.Backoff
+	if attempts <= 0 {
+		attempts = DefaultAttempts
+	}
+	if backoff <= 0 {
+		backoff = DefaultBackoff
+	}
+	return attempts, backoff
+}
+
+// loadPackages is packages.Load, replaced in tests to simulate
+// transient failures.
+var loadPackages = packages.Load
+
+// transientMarkers are substrings of go command errors that come
+// from contention or flaky I/O rather than from the code being
+// loaded: a module cache entry being extracted or locked by another
+// go command, or a module download cut short.
+var transientMarkers = []string{
+	"resource temporarily unavailable",
+	"text file busy",
+	"file exists",
+	"i/o timeout",
+	"connection reset",
+	"TLS handshake timeout",
+	"unexpected EOF",
+}
+
+// load calls packages.Load with cfg and patterns, trying again with
+// a doubling backoff while the failure is transient (see
+// isTransient) and attempts remain. The last result is returned
+// as is, so a load that keeps failing reports its own error.
+func load(cfg *packages.Config, opts Options, patterns ...string) ([]*packages.Package, error) {
+	attempts, backoff := opts.retry()
+	for try := 1; ; try++ {
+		pkgs, err := loadPackages(cfg, patterns...)
+		if try >= attempts || !isTransient(pkgs, err) {
+			return pkgs, err
+		}
+		log.Printf("warning: package load failed (attempt %d of %d), retrying in %s", try, attempts, backoff)
+		if !wait(cfg.Context, backoff) {
+			return pkgs, err
+		}
+		backoff *= 2
+	}
+}
+
+// isTransient reports whether a load failed for a reason worth
+// retrying: the go command itself failed, or reported a package
+// listing error, with a message matching transientMarkers. Type
+// and syntax errors, and cancellation, are not transient.
+func isTransient(pkgs []*packages.Package, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		return hasTransientMarker(err.Error())
+	}
+	for _, p := range pkgs {
+		for _, e := range p.Errors {
+			if e.Kind == packages.ListError && hasTransientMarker(e.Msg) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasTransientMarker reports whether msg contains one of
+// transientMarkers.
+func hasTransientMarker(msg string) bool {
+	for _, m := range transientMarkers {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// wait sleeps for d, returning false early if ctx is done first. A
+// nil ctx never is.
+func wait(ctx context.Context, d time.Duration) bool {
+	if ctx == nil {
+		time.Sleep(d)
+		return true
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Build holds the build constraints packages are loaded under. The
+// zero value loads packages for the host platform with no extra
+// tags, as the go command does by default.
+type Build struct {
+	// Tags are additional build tags to satisfy, such as
+	// "integration" for files marked //go:build integration.
+	Tags []string
+
+	// GOOS and GOARCH override the target platform, such as
+	// "linux" and "arm64". Empty means the host's (or the value
+	// already in the environment).
+	GOOS   string
+	GOARCH string
+}
+
+// config returns a packages.Config for loading in dir under the
+// build constraints b. Mode is always LoadMode.
+func (b Build) config(ctx context.Context, dir string, tests bool) *packages.Config {
 	cfg := &packages.Config{
-		Mode:  LoadMode,
-		Tests: false,
+		Mode:    LoadMode,
+		Dir:     dir,
+		Tests:   tests,
+		Context: ctx,
+	}
+	if len(b.Tags) > 0 {
+		cfg.BuildFlags = []string{"-tags=" + strings.Join(b.Tags, ",")}
 	}
+	if b.GOOS != "" || b.GOARCH != "" {
+		cfg.Env = os.Environ()
+		if b.GOOS != "" {
+			cfg.Env = append(cfg.Env, "GOOS="+b.GOOS)
+		}
+		if b.GOARCH != "" {
+			cfg.Env = append(cfg.Env, "GOARCH="+b.GOARCH)
+		}
+	}
+	return cfg
+}
 
-	pkgs, err := packages.Load(cfg, pattern)
+// Load loads a Go package at the given import path or file pattern.
+// The pattern may also be a directory or a single .go file, relative
+// or absolute, as a user would copy it from an editor; a file selects
+// the package in its directory. It returns the loaded package result
+// or an error if loading or type-checking fails. A go command failure
+// that looks transient is retried; see Options.Attempts.
+func Load(pattern string) (*Result, error) {
+	return LoadWithOptions(pattern, Options{})
+}
+
+// LoadWithOptions is like Load but configurable; see Options.
+func LoadWithOptions(pattern string, opts Options) (*Result, error) {
+	query, dir := resolvePattern(pattern, opts.Dir)
+	pkgs, err := load(opts.Build.config(opts.Context, dir, opts.Tests), opts, query)
 	if err != nil {
 		return nil, fmt.Errorf("loading package %q: %w", pattern, err)
 	}
@@ -48,22 +262,207 @@ func Load(pattern string) (*Result, error) {
 		return nil, fmt.Errorf("no packages found for pattern %q", pattern)
 	}
 
-	pkg := pkgs[0]
+	pkg, xtest := pkgs[0], (*packages.Package)(nil)
+	if opts.Tests {
+		pkg, xtest = testVariants(pkgs)
+	}
+	return newResult(pattern, pkg, xtest, opts)
+}
+
+// resolvePattern rewrites a filesystem path argument into a go list
+// query run from that path, returning the pattern and the directory
+// to load it in. A directory becomes "." in that directory, a .go
+// file "." in the directory holding it, and a directory followed by
+// "/..." becomes "./...". Running from the path itself lets go list
+// pick the module it belongs to, so an absolute path works from
+// anywhere and a file loads its whole package rather than a
+// single-file "command-line-arguments" package.
+//
+// Import paths, and paths that do not exist, are returned unchanged
+// with dir, so go list resolves them and reports any error. As with
+// the go command, a relative directory must start with "./" or
+// "../" to be treated as a path; a .go file need not.
+func resolvePattern(pattern, dir string) (string, string) {
+	path, recursive := strings.CutSuffix(pattern, "/...")
+	if !isFilePath(path) {
+		return pattern, dir
+	}
+	if !filepath.IsAbs(path) && dir != "" {
+		path = filepath.Join(dir, path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return pattern, dir
+	}
+	if !info.IsDir() {
+		if recursive || filepath.Ext(path) != ".go" {
+			return pattern, dir
+		}
+		path = filepath.Dir(path)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return pattern, dir
+	}
+	if recursive {
+		return "./...", abs
+	}
+	return ".", abs
+}
+
+// isFilePath reports whether pattern names a filesystem path rather
+// than an import path.
+func isFilePath(pattern string) bool {
+	return filepath.IsAbs(pattern) ||
+		pattern == "." || pattern == ".." ||
+		strings.HasPrefix(pattern, "./") || strings.HasPrefix(pattern, "../") ||
+		strings.HasSuffix(pattern, ".go")
+}
 
+// LoadAll is like LoadWithOptions but returns every package the
+// pattern matches, such as all packages under ./..., sorted by
+// import path. It fails if any of them has errors.
+func LoadAll(pattern string, opts Options) ([]*Result, error) {
+	query, dir := resolvePattern(pattern, opts.Dir)
+	pkgs, err := load(opts.Build.config(opts.Context, dir, opts.Tests), opts, query)
+	if err != nil {
+		return nil, fmt.Errorf("loading package %q: %w", pattern, err)
+	}
+
+	// Group each package with its test variants, keyed by the
+	// import path of the package under test.
+	var paths []string
+	groups := make(map[string][]*packages.Package)
+	for _, p := range pkgs {
+		path := p.PkgPath
+		if opts.Tests {
+			if strings.HasSuffix(p.ID, ".test") {
+				continue // generated test main
+			}
+			path = strings.TrimSuffix(path, "_test")
+		}
+		if _, ok := groups[path]; !ok {
+			paths = append(paths, path)
+		}
+		groups[path] = append(groups[path], p)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no packages found for pattern %q", pattern)
+	}
+	sort.Strings(paths)
+
+	results := make([]*Result, 0, len(paths))
+	for _, path := range paths {
+		group := groups[path]
+		pkg, xtest := group[0], (*packages.Package)(nil)
+		if opts.Tests {
+			pkg, xtest = testVariants(group)
+		}
+		result, err := newResult(path, pkg, xtest, opts)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// newResult checks pkg and xtest for errors and wraps them in a
+// Result, building SSA if requested. name identifies the package in
+// the error.
+func newResult(name string, pkg, xtest *packages.Package, opts Options) (*Result, error) {
 	// Check for package-level errors (syntax, type errors, etc.).
 	var errs []string
-	for _, e := range pkg.Errors {
-		errs = append(errs, e.Error())
+	for _, p := range []*packages.Package{pkg, xtest} {
+		if p == nil {
+			continue
+		}
+		for _, e := range p.Errors {
+			errs = append(errs, e.Error())
+		}
 	}
 	if len(errs) > 0 {
 		return nil, fmt.Errorf("package %q has errors:\n  %s",
-			pattern, strings.Join(errs, "\n  "))
+			name, strings.Join(errs, "\n  "))
 	}
 
-	return &Result{
-		Pkg:  pkg,
-		Fset: pkg.Fset,
-	}, nil
+	result := &Result{
+		Pkg:   pkg,
+		Fset:  pkg.Fset,
+		XTest: xtest,
+	}
+	if opts.SSA {
+		result.SSA = buildSSA(pkg)
+	}
+	return result, nil
+}
+
+// testVariants picks the packages to analyze from a load with
+// Tests set, which returns the package, its test variant (ID
+// "pkg [pkg.test]"), the external test package ("pkg_test
+// [pkg.test]"), and the generated test main ("pkg.test"). The test
+// variant replaces the package when it exists; the external test
+// package is returned separately and may be nil.
+func testVariants(pkgs []*packages.Package) (pkg, xtest *packages.Package) {
+	pkg = pkgs[0]
+	for _, p := range pkgs {
+		if p.ID == p.PkgPath && !strings.HasSuffix(p.ID, ".test") {
+			pkg = p
+			break
+		}
+	}
+	testID := pkg.PkgPath + " [" + pkg.PkgPath + ".test]"
+	base := pkg.PkgPath
+	for _, p := range pkgs {
+		switch {
+		case p.ID == testID:
+			pkg = p
+		case p.PkgPath == base+"_test":
+			xtest = p
+		}
+	}
+	return pkg, xtest
+}
+
+// SSAFunc returns the SSA function for a package-level function or
+// concrete method, or nil if SSA was not built or obj has no SSA
+// function (such as an interface method).
+func (r *Result) SSAFunc(obj *types.Func) *ssa.Function {
+	if r.SSA == nil || obj == nil {
+		return nil
+	}
+	return r.SSA.Prog.FuncValue(obj)
+}
+
+// buildSSA builds the SSA form of pkg, creating its dependencies
+// from type information only. Returns nil if the builder panics,
+// which x/tools does for some generic code; the panic is logged.
+func buildSSA(pkg *packages.Package) *ssa.Package {
+	_, ssaPkgs := ssautil.Packages(
+		[]*packages.Package{pkg},
+		ssa.InstantiateGenerics|ssa.BuildSerially,
+	)
+	if len(ssaPkgs) == 0 || ssaPkgs[0] == nil {
+		return nil
+	}
+	ssaPkg := ssaPkgs[0]
+	if r := safeSSABuild(ssaPkg.Build); r != nil {
+		log.Printf("warning: SSA build skipped for %s: internal panic recovered", pkg.PkgPath)
+		log.Printf("debug: SSA panic value for %s: %v", pkg.PkgPath, r)
+		return nil
+	}
+	return ssaPkg
+}
+
+// safeSSABuild calls buildFn and recovers from any panic it
+// produces, returning the panic value or nil. It is duplicated from
+// internal/analysis, which imports this package.
+func safeSSABuild(buildFn func()) (panicVal any) {
+	defer func() {
+		panicVal = recover()
+	}()
+	buildFn()
+	return nil
 }
 
 // ModuleResult holds all packages loaded from a Go module.
@@ -85,13 +484,21 @@ type ModuleResult struct {
 // all packages have errors. Packages with individual errors are
 // silently excluded from the result.
 func LoadModule(dir string) (*ModuleResult, error) {
-	cfg := &packages.Config{
-		Mode:  LoadMode,
-		Tests: false,
-		Dir:   dir,
-	}
+	return loadModule(context.Background(), dir, false, Build{})
+}
+
+// LoadModuleWithTests is like LoadModule but also loads _test.go
+// files. The result contains the test variants of each package
+// (e.g. "pkg [pkg.test]" and "pkg_test") alongside the regular
+// packages, so callers can inspect test function bodies.
+func LoadModuleWithTests(dir string) (*ModuleResult, error) {
+	return loadModule(context.Background(), dir, true, Build{})
+}
 
-	pkgs, err := packages.Load(cfg, "./...")
+// loadModule implements LoadModule and LoadModuleWithTests. Loading
+// stops with an error if ctx is done.
+func loadModule(ctx context.Context, dir string, tests bool, build Build) (*ModuleResult, error) {
+	pkgs, err := load(build.config(ctx, dir, tests), Options{}, "./...")
 	if err != nil {
 		return nil, fmt.Errorf("loading module packages: %w", err)
 	}
@@ -121,3 +528,191 @@ func LoadModule(dir string) (*ModuleResult, error) {
 		Fset:     fset,
 	}, nil
 }
+
+// Lookup returns the module package with the given import path, or
+// nil if the module has no such (error-free) package.
+func (m *ModuleResult) Lookup(pkgPath string) *packages.Package {
+	for _, pkg := range m.Packages {
+		if pkg.PkgPath == pkgPath {
+			return pkg
+		}
+	}
+	return nil
+}
+
+// Session shares a single module load between the stages of one
+// command. Analysis, classification, and quality assessment all need
+// type-checked packages, and classification additionally needs every
+// package in the module; loading the target package separately from
+// the module type-checks it (and its dependencies) twice. A Session
+// loads ./... once, on first use, and serves the target package from
+// that load.
+//
+// A Session is safe for concurrent use.
+type Session struct {
+	ctx   context.Context
+	dir   string
+	build Build
+	once  sync.Once
+	mod   *ModuleResult
+	err   error
+}
+
+// NewSession returns a Session for the module rooted at dir. If dir
+// is empty, the current directory is used. Nothing is loaded until
+// Module or Load is called.
+func NewSession(dir string) *Session {
+	return NewSessionContext(context.Background(), dir)
+}
+
+// NewSessionContext is like NewSession, but every load the Session
+// performs is bounded by ctx.
+func NewSessionContext(ctx context.Context, dir string) *Session {
+	return NewSessionBuild(ctx, dir, Build{})
+}
+
+// NewSessionBuild is like NewSessionContext, but loads the module
+// and any fallback packages under the build constraints build.
+func NewSessionBuild(ctx context.Context, dir string, build Build) *Session {
+	return &Session{ctx: ctx, dir: dir, build: build}
+}
+
+// Module returns the module packages, loading them on the first
+// call. Later calls return the same result, including the same
+// error if loading failed.
+func (s *Session) Module() (*ModuleResult, error) {
+	s.once.Do(func() {
+		s.mod, s.err = loadModule(s.ctx, s.dir, false, s.build)
+	})
+	return s.mod, s.err
+}
+
+// Load is like the package-level Load, but returns the package from
+// the shared module load when it is part of the module. Patterns are
+// resolved in the Session's directory. Packages outside the module,
+// packages with errors, and any pattern when the module could not be
+// loaded fall back to a separate Load, so errors are reported
+// exactly as Load reports them.
+func (s *Session) Load(pattern string) (*Result, error) {
+	return s.LoadWithOptions(pattern, Options{})
+}
+
+// LoadWithOptions is like Load but configurable; see Options. The
+// module load holds no test files and was resolved in the Session's
+// directory under its build constraints, so a request for test
+// variants (opts.Tests), another directory (opts.Dir), or other
+// constraints (opts.Build) is served by a separate load.
+func (s *Session) LoadWithOptions(pattern string, opts Options) (*Result, error) {
+	opts = s.options(opts)
+	if !s.serves(opts) {
+		return LoadWithOptions(pattern, opts)
+	}
+	pkg := s.lookup(pattern)
+	if pkg == nil {
+		return LoadWithOptions(pattern, opts)
+	}
+	result := &Result{
+		Pkg:  pkg,
+		Fset: pkg.Fset,
+	}
+	if opts.SSA {
+		result.SSA = buildSSA(pkg)
+	}
+	return result, nil
+}
+
+// LoadAll is like the package-level LoadAll, but serves the matched
+// packages from the shared module load when every one of them is
+// part of it, in import path order. Otherwise, and under the same
+// conditions as LoadWithOptions, it falls back to a separate LoadAll.
+func (s *Session) LoadAll(pattern string, opts Options) ([]*Result, error) {
+	opts = s.options(opts)
+	if !s.serves(opts) {
+		return LoadAll(pattern, opts)
+	}
+	pkgs := s.lookupAll(pattern)
+	if pkgs == nil {
+		return LoadAll(pattern, opts)
+	}
+	results := make([]*Result, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		result := &Result{
+			Pkg:  pkg,
+			Fset: pkg.Fset,
+		}
+		if opts.SSA {
+			result.SSA = buildSSA(pkg)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// options fills the context, directory, and build constraints opts
+// leaves unset with the Session's.
+func (s *Session) options(opts Options) Options {
+	if opts.Context == nil {
+		opts.Context = s.ctx
+	}
+	if opts.Dir == "" {
+		opts.Dir = s.dir
+	}
+	if opts.Build.Tags == nil && opts.Build.GOOS == "" && opts.Build.GOARCH == "" {
+		opts.Build = s.build
+	}
+	return opts
+}
+
+// serves reports whether packages loaded with opts can come from the
+// module load: it has no test files and was resolved in s.dir under
+// s.build.
+func (s *Session) serves(opts Options) bool {
+	return !opts.Tests && opts.Dir == s.dir &&
+		slices.Equal(opts.Build.Tags, s.build.Tags) &&
+		opts.Build.GOOS == s.build.GOOS && opts.Build.GOARCH == s.build.GOARCH
+}
+
+// lookup resolves pattern to an import path, the same way Load
+// would, and returns the matching module package, or nil if there
+// is none.
+func (s *Session) lookup(pattern string) *packages.Package {
+	mod, err := s.Module()
+	if err != nil {
+		return nil
+	}
+	query, dir := resolvePattern(pattern, s.dir)
+	pkgs, err := load(&packages.Config{Mode: packages.NeedName, Dir: dir, Context: s.ctx}, Options{}, query)
+	if err != nil || len(pkgs) == 0 || len(pkgs[0].Errors) > 0 {
+		return nil
+	}
+	return mod.Lookup(pkgs[0].PkgPath)
+}
+
+// lookupAll resolves pattern to import paths, the same way LoadAll
+// would, and returns the matching module packages sorted by import
+// path, or nil if the pattern matches nothing or any match is not an
+// error-free module package.
+func (s *Session) lookupAll(pattern string) []*packages.Package {
+	mod, err := s.Module()
+	if err != nil {
+		return nil
+	}
+	query, dir := resolvePattern(pattern, s.dir)
+	listed, err := load(&packages.Config{Mode: packages.NeedName, Dir: dir, Context: s.ctx}, Options{}, query)
+	if err != nil || len(listed) == 0 {
+		return nil
+	}
+	pkgs := make([]*packages.Package, 0, len(listed))
+	for _, p := range listed {
+		if len(p.Errors) > 0 {
+			return nil
+		}
+		pkg := mod.Lookup(p.PkgPath)
+		if pkg == nil {
+			return nil
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].PkgPath < pkgs[j].PkgPath })
+	return pkgs
+}
@@ -1,9 +1,17 @@
 package loader_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"go/types"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
+	"time"
+
+	"golang.org/x/tools/go/packages"
 
 	"github.com/unbound-force/gaze/internal/loader"
 )
@@ -33,6 +41,159 @@ func TestLoad_InvalidPattern(t *testing.T) {
 	}
 }
 
+func TestLoadWithOptions_SSA(t *testing.T) {
+	const path = "github.com/unbound-force/gaze/internal/loader"
+
+	plain, err := loader.Load(path)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if plain.SSA != nil {
+		t.Error("expected no SSA without Options.SSA")
+	}
+
+	result, err := loader.LoadWithOptions(path, loader.Options{SSA: true})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() failed: %v", err)
+	}
+	if result.SSA == nil {
+		t.Skip("SSA construction failed in this environment")
+	}
+	for _, name := range []string{"Load", "LoadWithOptions"} {
+		obj, ok := result.Pkg.Types.Scope().Lookup(name).(*types.Func)
+		if !ok {
+			t.Fatalf("%s not found in package scope", name)
+		}
+		fn := result.SSAFunc(obj)
+		if fn == nil || fn.Name() != name || len(fn.Blocks) == 0 {
+			t.Errorf("SSAFunc(%s) = %v, want a built function", name, fn)
+		}
+	}
+	if plain.SSAFunc(nil) != nil {
+		t.Error("expected nil SSAFunc without SSA")
+	}
+}
+
+func TestLoadWithOptions_Tests(t *testing.T) {
+	const path = "github.com/unbound-force/gaze/internal/analysis/testdata/src/testfiles"
+
+	plain, err := loader.Load(path)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if plain.XTest != nil {
+		t.Error("expected nil XTest without Options.Tests")
+	}
+	if hasFile(plain.Pkg.GoFiles, "setup_test.go") {
+		t.Error("expected no test files without Options.Tests")
+	}
+
+	result, err := loader.LoadWithOptions(path, loader.Options{Tests: true})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() failed: %v", err)
+	}
+	if result.Pkg.PkgPath != path {
+		t.Errorf("Pkg.PkgPath = %q, want %q", result.Pkg.PkgPath, path)
+	}
+	if !hasFile(result.Pkg.GoFiles, "testfiles.go") || !hasFile(result.Pkg.GoFiles, "setup_test.go") {
+		t.Errorf("expected the test variant with regular and in-package test files, got %v", result.Pkg.GoFiles)
+	}
+	if result.XTest == nil {
+		t.Fatal("expected the external test package")
+	}
+	if result.XTest.PkgPath != path+"_test" || !hasFile(result.XTest.GoFiles, "external_test.go") {
+		t.Errorf("unexpected XTest %s with files %v", result.XTest.PkgPath, result.XTest.GoFiles)
+	}
+}
+
+func TestLoadAll_MultiplePackages(t *testing.T) {
+	const base = "github.com/unbound-force/gaze/internal/report/testdata/src/multipkg"
+
+	results, err := loader.LoadAll("../report/testdata/src/multipkg/...", loader.Options{})
+	if err != nil {
+		t.Fatalf("LoadAll() failed: %v", err)
+	}
+	var got []string
+	for _, r := range results {
+		got = append(got, r.Pkg.PkgPath)
+	}
+	if want := []string{base + "/alpha", base + "/beta"}; !slices.Equal(got, want) {
+		t.Errorf("LoadAll() packages = %v, want %v", got, want)
+	}
+
+	results, err = loader.LoadAll("../analysis/testdata/src/testfiles", loader.Options{Tests: true})
+	if err != nil {
+		t.Fatalf("LoadAll() with Tests failed: %v", err)
+	}
+	if len(results) != 1 || results[0].XTest == nil || !hasFile(results[0].Pkg.GoFiles, "setup_test.go") {
+		t.Errorf("expected one test variant with its external test package, got %d result(s)", len(results))
+	}
+}
+
+func TestLoadAll_FilePaths(t *testing.T) {
+	const alpha = "github.com/unbound-force/gaze/internal/report/testdata/src/multipkg/alpha"
+
+	abs, err := filepath.Abs("../report/testdata/src/multipkg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A standalone module outside this one, loadable only from its
+	// own directory.
+	other := t.TempDir()
+	goMod := "module example.com/other\n\ngo 1.21\n"
+	if err := os.WriteFile(filepath.Join(other, "go.mod"), []byte(goMod), 0o644); err != nil {
+		t.Fatalf("writing go.mod: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(other, "other.go"), []byte("package other\n"), 0o644); err != nil {
+		t.Fatalf("writing other.go: %v", err)
+	}
+
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{filepath.Join(abs, "alpha"), []string{alpha}},
+		{"../report/testdata/src/multipkg/alpha/alpha.go", []string{alpha}},
+		{filepath.Join(abs, "alpha", "alpha.go"), []string{alpha}},
+		{abs + "/...", []string{alpha, "github.com/unbound-force/gaze/internal/report/testdata/src/multipkg/beta"}},
+		{filepath.Join(other, "other.go"), []string{"example.com/other"}},
+	}
+	for _, tt := range tests {
+		results, err := loader.LoadAll(tt.pattern, loader.Options{})
+		if err != nil {
+			t.Errorf("LoadAll(%q) failed: %v", tt.pattern, err)
+			continue
+		}
+		var got []string
+		for _, r := range results {
+			got = append(got, r.Pkg.PkgPath)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("LoadAll(%q) packages = %v, want %v", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+// hasFile reports whether files contains a path with the given base
+// name.
+func hasFile(files []string, name string) bool {
+	for _, f := range files {
+		if filepath.Base(f) == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestLoadWithOptions_CanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := loader.LoadWithOptions("github.com/unbound-force/gaze/internal/loader", loader.Options{Context: ctx})
+	if err == nil {
+		t.Error("expected an error loading with a canceled context")
+	}
+}
+
 // findModuleRoot walks up from the current directory to find go.mod.
 func findModuleRoot(t *testing.T) string {
 	t.Helper()
@@ -149,3 +310,180 @@ func TestLoadModule_ExcludesBrokenPackages(t *testing.T) {
 		t.Error("expected valid package 'example.com/testmod/valid' in result")
 	}
 }
+
+func TestSession_LoadSharesModulePackages(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping slow test: loads real Go module via go/packages")
+	}
+
+	const path = "github.com/unbound-force/gaze/internal/loader"
+	s := loader.NewSession(findModuleRoot(t))
+
+	result, err := s.Load(path)
+	if err != nil {
+		t.Fatalf("Session.Load(%q) failed: %v", path, err)
+	}
+	mod, err := s.Module()
+	if err != nil {
+		t.Fatalf("Session.Module() failed: %v", err)
+	}
+	if again, _ := s.Module(); again != mod {
+		t.Error("expected Module() to return the same result on every call")
+	}
+	if want := mod.Lookup(path); result.Pkg != want {
+		t.Errorf("Session.Load returned %p, want the module package %p", result.Pkg, want)
+	}
+
+	if _, err := s.Load("github.com/nonexistent/package/that/does/not/exist"); err == nil {
+		t.Error("expected error for nonexistent package")
+	}
+}
+
+func TestSession_LoadAllSharesModulePackages(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping slow test: loads real Go module via go/packages")
+	}
+
+	const base = "github.com/unbound-force/gaze/internal/"
+	s := loader.NewSession(findModuleRoot(t))
+	results, err := s.LoadAll(base+"c...", loader.Options{})
+	if err != nil {
+		t.Fatalf("Session.LoadAll failed: %v", err)
+	}
+	mod, err := s.Module()
+	if err != nil {
+		t.Fatalf("Session.Module() failed: %v", err)
+	}
+	want := []string{base + "cache", base + "classify", base + "config", base + "crap"}
+	if len(results) != len(want) {
+		t.Fatalf("got %d packages, want %v", len(results), want)
+	}
+	for i, r := range results {
+		if r.Pkg.PkgPath != want[i] {
+			t.Errorf("results[%d] = %s, want %s", i, r.Pkg.PkgPath, want[i])
+		}
+		if r.Pkg != mod.Lookup(want[i]) {
+			t.Errorf("%s not served from the module load", want[i])
+		}
+	}
+}
+
+func TestSession_LoadWithOptionsOutsideModuleLoad(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping slow test: loads real Go module via go/packages")
+	}
+
+	s := loader.NewSession(findModuleRoot(t))
+	mod, err := s.Module()
+	if err != nil {
+		t.Fatalf("Session.Module() failed: %v", err)
+	}
+
+	// Test variants are not in the module load.
+	const testfiles = "github.com/unbound-force/gaze/internal/analysis/testdata/src/testfiles"
+	result, err := s.LoadWithOptions(testfiles, loader.Options{Tests: true})
+	if err != nil {
+		t.Fatalf("LoadWithOptions(Tests) failed: %v", err)
+	}
+	if result.Pkg == mod.Lookup(testfiles) || !hasFile(result.Pkg.GoFiles, "setup_test.go") {
+		t.Errorf("expected the test variant, got %v", result.Pkg.GoFiles)
+	}
+
+	// "." names the package in opts.Dir, not in the session's
+	// directory or the current one.
+	alphaDir, err := filepath.Abs("../report/testdata/src/multipkg/alpha")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err = s.LoadWithOptions(".", loader.Options{Dir: alphaDir})
+	if err != nil {
+		t.Fatalf("LoadWithOptions(Dir) failed: %v", err)
+	}
+	if want := "github.com/unbound-force/gaze/internal/report/testdata/src/multipkg/alpha"; result.Pkg.PkgPath != want {
+		t.Errorf("PkgPath = %q, want %q", result.Pkg.PkgPath, want)
+	}
+}
+
+// flakyLoad returns a packages.Load replacement that fails with err
+// for the first failures calls and then loads for real, counting
+// every call in calls.
+func flakyLoad(failures int, err error, calls *int) func(*packages.Config, ...string) ([]*packages.Package, error) {
+	return func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		*calls++
+		if *calls <= failures {
+			return nil, err
+		}
+		return packages.Load(cfg, patterns...)
+	}
+}
+
+func TestLoadWithOptions_RetriesTransientFailure(t *testing.T) {
+	const path = "github.com/unbound-force/gaze/internal/loader"
+	transient := errors.New("go: open /go/pkg/mod/cache/download/lock: resource temporarily unavailable")
+
+	var calls int
+	restore := loader.SetLoadPackages(flakyLoad(2, transient, &calls))
+	defer restore()
+
+	result, err := loader.LoadWithOptions(path, loader.Options{Attempts: 3, Backoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("LoadWithOptions() failed after retries: %v", err)
+	}
+	if result.Pkg.PkgPath != path {
+		t.Errorf("loaded %q, want %q", result.Pkg.PkgPath, path)
+	}
+	if calls != 3 {
+		t.Errorf("packages.Load called %d times, want 3", calls)
+	}
+}
+
+func TestLoadWithOptions_RetryLimit(t *testing.T) {
+	transient := errors.New("go: downloading example.com/m v1.0.0: unexpected EOF")
+
+	var calls int
+	restore := loader.SetLoadPackages(flakyLoad(5, transient, &calls))
+	defer restore()
+
+	_, err := loader.LoadWithOptions("github.com/unbound-force/gaze/internal/loader",
+		loader.Options{Attempts: 2, Backoff: time.Millisecond})
+	if err == nil || !errors.Is(err, transient) {
+		t.Fatalf("expected the transient error after the last attempt, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("packages.Load called %d times, want 2", calls)
+	}
+}
+
+func TestLoadWithOptions_NoRetryForGenuineErrors(t *testing.T) {
+	genuine := errors.New("go: malformed import path \"%\": invalid char '%'")
+	typeErr := func(*packages.Config, ...string) ([]*packages.Package, error) {
+		return []*packages.Package{{
+			PkgPath: "example.com/broken",
+			Errors:  []packages.Error{{Msg: "undefined: x (resource temporarily unavailable)", Kind: packages.TypeError}},
+		}}, nil
+	}
+
+	for name, load := range map[string]func(*packages.Config, ...string) ([]*packages.Package, error){
+		"go command error": func(*packages.Config, ...string) ([]*packages.Package, error) { return nil, genuine },
+		"type error":       typeErr,
+		"canceled": func(*packages.Config, ...string) ([]*packages.Package, error) {
+			return nil, fmt.Errorf("go list: %w", context.Canceled)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var calls int
+			restore := loader.SetLoadPackages(func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+				calls++
+				return load(cfg, patterns...)
+			})
+			defer restore()
+
+			if _, err := loader.LoadWithOptions("example.com/broken", loader.Options{Attempts: 3, Backoff: time.Millisecond}); err == nil {
+				t.Fatal("expected an error")
+			}
+			if calls != 1 {
+				t.Errorf("packages.Load called %d times, want 1 (no retry)", calls)
+			}
+		})
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"strings"
 
 	"golang.org/x/tools/go/packages"
+
+	"github.com/unbound-force/gaze/internal/config"
 )
 
 // AssertionKind enumerates the kinds of assertion patterns that
@@ -25,17 +27,23 @@ const (
 	AssertionKindStdlibErrorCheck AssertionKind = "stdlib_error_check"
 
 	// AssertionKindTestifyEqual is a testify assert/require
-	// equality call (e.g., assert.Equal(t, got, want)).
+	// equality call (e.g., assert.Equal(t, want, got)), or the
+	// gotest.tools equivalent (e.g., assert.Check(t, cmp.Len(got, 2))).
 	AssertionKindTestifyEqual AssertionKind = "testify_equal"
 
 	// AssertionKindTestifyNoError is a testify assert/require
-	// NoError call (e.g., require.NoError(t, err)).
+	// error call (e.g., require.NoError(t, err)), or the
+	// gotest.tools equivalent (e.g., assert.NilError(t, err)).
 	AssertionKindTestifyNoError AssertionKind = "testify_noerror"
 
 	// AssertionKindTestifyNilCheck is a testify assert/require
 	// Nil or NotNil call (e.g., assert.Nil(t, obj)).
 	AssertionKindTestifyNilCheck AssertionKind = "testify_nil_check"
 
+	// AssertionKindHelper is a call to a function registered in
+	// the quality.assertion_helpers configuration.
+	AssertionKindHelper AssertionKind = "registered_helper"
+
 	// AssertionKindGoCmpDiff is a go-cmp diff check
 	// (e.g., if diff := cmp.Diff(want, got); diff != "").
 	AssertionKindGoCmpDiff AssertionKind = "gocmp_diff"
@@ -65,6 +73,12 @@ type AssertionSite struct {
 	// the assertion.
 	Expr ast.Expr
 
+	// Actual is the argument of a library or registered helper
+	// assertion that holds the value under test (e.g., got in
+	// assert.Equal(t, want, got)). Nil for other assertions. The
+	// mapper tries it before the rest of Expr.
+	Actual ast.Expr
+
 	// CallerArgs is the argument expressions from the call site
 	// that invoked the helper function. Only populated when
 	// Depth > 0. Used to bridge helper parameters back to the
@@ -73,12 +87,15 @@ type AssertionSite struct {
 }
 
 // DetectAssertions walks the test function's AST looking for
-// assertion patterns. It detects stdlib comparisons, testify calls,
-// go-cmp diffs, and recurses into helper functions up to maxDepth.
+// assertion patterns. It detects stdlib comparisons, testify and
+// gotest.tools calls, go-cmp diffs, and calls to the given
+// registered helpers, and recurses into helper functions up to
+// maxDepth.
 func DetectAssertions(
 	testDecl *ast.FuncDecl,
 	pkg *packages.Package,
 	maxDepth int,
+	helpers ...config.AssertionHelper,
 ) []AssertionSite {
 	d := &assertionDetector{
 		pkg:       pkg,
@@ -87,6 +104,12 @@ func DetectAssertions(
 		visited:   make(map[string]bool),
 		funcDecls: buildFuncDeclIndex(pkg),
 	}
+	if len(helpers) > 0 {
+		d.helpers = make(map[string]int, len(helpers))
+		for _, h := range helpers {
+			d.helpers[h.Function] = h.Actual
+		}
+	}
 	return d.detect(testDecl, 0)
 }
 
@@ -97,6 +120,7 @@ type assertionDetector struct {
 	maxDepth  int
 	visited   map[string]bool          // prevents infinite recursion
 	funcDecls map[string]*ast.FuncDecl // cached function declarations by name
+	helpers   map[string]int           // registered helper full name -> actual arg index
 }
 
 // detect walks a function declaration for assertion patterns.
@@ -148,7 +172,9 @@ func (d *assertionDetector) detect(fn *ast.FuncDecl, depth int) []AssertionSite
 			}
 
 			// Check for helper function calls (accepting *testing.T).
-			if depth < d.maxDepth {
+			// Registered helpers are assertions themselves and are
+			// not traversed.
+			if depth < d.maxDepth && !d.isRegisteredHelper(node) {
 				helperSites := d.detectHelperAssertions(node, fn, depth)
 				sites = append(sites, helperSites...)
 			}
@@ -245,13 +271,66 @@ func (d *assertionDetector) bodyContainsTestFail(body *ast.BlockStmt) bool {
 	return found
 }
 
-// detectCallAssertion checks if a function call is a testify
-// assertion (assert.Equal, require.NoError, etc.).
+// Import paths of the assertion libraries recognized by
+// detectCallAssertion.
+var (
+	testifyPkgs = map[string]bool{
+		"github.com/stretchr/testify/assert":  true,
+		"github.com/stretchr/testify/require": true,
+	}
+	gotestToolsPkgs = map[string]bool{
+		"gotest.tools/v3/assert": true,
+		"gotest.tools/assert":    true,
+	}
+)
+
+// detectCallAssertion checks if a function call is a registered
+// helper or a testify or gotest.tools assertion (assert.Equal,
+// require.NoError, assert.Check, etc.). Calls are identified by the
+// callee's import path when type information is available, and by
+// the package name "assert" or "require" otherwise.
 func (d *assertionDetector) detectCallAssertion(
 	call *ast.CallExpr,
 	fn *ast.FuncDecl,
 	depth int,
 ) *AssertionSite {
+	newSite := func(kind AssertionKind, actual int) *AssertionSite {
+		site := &AssertionSite{
+			Location: d.posString(call.Pos()),
+			Kind:     kind,
+			FuncDecl: fn,
+			Depth:    depth,
+			Expr:     call,
+		}
+		if actual >= 0 && actual < len(call.Args) {
+			site.Actual = call.Args[actual]
+		}
+		return site
+	}
+
+	if callee := d.callee(call); callee != nil {
+		if actual, ok := d.helpers[callee.FullName()]; ok {
+			return newSite(AssertionKindHelper, actual)
+		}
+		if pkg := callee.Pkg(); pkg != nil {
+			kind, actual := AssertionKindUnknown, -1
+			switch {
+			case testifyPkgs[pkg.Path()]:
+				kind, actual = classifyTestifyCall(callee.Name())
+			case gotestToolsPkgs[pkg.Path()]:
+				kind, actual = classifyGotestToolsCall(callee.Name())
+			}
+			if kind != AssertionKindUnknown {
+				// Methods such as (*assert.Assertions).Equal
+				// take no testing.T argument.
+				if sig, ok := callee.Type().(*types.Signature); ok && sig.Recv() != nil {
+					actual--
+				}
+				return newSite(kind, actual)
+			}
+		}
+	}
+
 	sel, ok := call.Fun.(*ast.SelectorExpr)
 	if !ok {
 		return nil
@@ -266,65 +345,120 @@ func (d *assertionDetector) detectCallAssertion(
 	methodName := sel.Sel.Name
 	pkgName := pkgIdent.Name
 
-	// testify patterns.
+	// testify patterns, by package name.
 	if pkgName == "assert" || pkgName == "require" {
-		kind := d.classifyTestifyCall(methodName)
-		if kind != AssertionKindUnknown {
-			return &AssertionSite{
-				Location: d.posString(call.Pos()),
-				Kind:     kind,
-				FuncDecl: fn,
-				Depth:    depth,
-				Expr:     call,
-			}
+		if kind, actual := classifyTestifyCall(methodName); kind != AssertionKindUnknown {
+			return newSite(kind, actual)
 		}
 	}
 
 	// go-cmp pattern: direct cmp.Diff call in an expression statement
 	// is unusual, but detect it anyway.
 	if pkgName == "cmp" && methodName == "Diff" {
-		return &AssertionSite{
-			Location: d.posString(call.Pos()),
-			Kind:     AssertionKindGoCmpDiff,
-			FuncDecl: fn,
-			Depth:    depth,
-			Expr:     call,
-		}
+		return newSite(AssertionKindGoCmpDiff, -1)
 	}
 
 	return nil
 }
 
-// classifyTestifyCall maps testify method names to assertion kinds.
-func (d *assertionDetector) classifyTestifyCall(method string) AssertionKind {
+// callee returns the function or method a call statically invokes,
+// or nil without type information or for dynamic calls.
+func (d *assertionDetector) callee(call *ast.CallExpr) *types.Func {
+	if d.pkg.TypesInfo == nil {
+		return nil
+	}
+	var ident *ast.Ident
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return nil
+	}
+	f, _ := d.pkg.TypesInfo.Uses[ident].(*types.Func)
+	return f
+}
+
+// isRegisteredHelper reports whether call invokes a function from
+// the quality.assertion_helpers configuration.
+func (d *assertionDetector) isRegisteredHelper(call *ast.CallExpr) bool {
+	if len(d.helpers) == 0 {
+		return false
+	}
+	callee := d.callee(call)
+	if callee == nil {
+		return false
+	}
+	_, ok := d.helpers[callee.FullName()]
+	return ok
+}
+
+// classifyTestifyCall maps testify function names to assertion
+// kinds and returns the index of the argument holding the actual
+// value. Formatted variants (Equalf, NoErrorf) are classified as
+// their base function.
+func classifyTestifyCall(method string) (AssertionKind, int) {
+	kind, actual := classifyTestifyName(method)
+	if kind == AssertionKindUnknown && strings.HasSuffix(method, "f") {
+		kind, actual = classifyTestifyName(strings.TrimSuffix(method, "f"))
+	}
+	return kind, actual
+}
+
+// classifyTestifyName classifies an unformatted testify function.
+// Testify takes (t, expected, actual) for comparisons against an
+// expected value and (t, actual, ...) otherwise.
+func classifyTestifyName(method string) (AssertionKind, int) {
 	switch method {
 	case "Equal", "EqualValues", "EqualExportedValues",
 		"Exactly", "JSONEq", "YAMLEq",
 		"NotEqual", "NotEqualValues",
-		"Contains", "NotContains",
+		"Same", "NotSame",
+		"InDelta", "InDeltaSlice", "InEpsilon", "InEpsilonSlice",
+		"IsType", "Implements":
+		return AssertionKindTestifyEqual, 2
+
+	case "Contains", "NotContains",
 		"ElementsMatch", "Subset", "NotSubset",
 		"Len", "Empty", "NotEmpty",
 		"True", "False",
 		"Greater", "GreaterOrEqual", "Less", "LessOrEqual",
-		"Regexp", "NotRegexp",
-		"IsType", "Implements",
-		"Same", "NotSame",
-		"InDelta", "InDeltaSlice", "InEpsilon", "InEpsilonSlice",
 		"Zero", "NotZero",
 		"FileExists", "NoFileExists", "DirExists", "NoDirExists":
-		return AssertionKindTestifyEqual
+		return AssertionKindTestifyEqual, 1
+
+	case "Regexp", "NotRegexp":
+		// (t, regexp, string)
+		return AssertionKindTestifyEqual, 2
 
 	case "NoError", "Error", "ErrorIs", "ErrorAs",
 		"ErrorContains", "EqualError":
-		return AssertionKindTestifyNoError
+		return AssertionKindTestifyNoError, 1
 
 	case "Nil", "NotNil":
-		return AssertionKindTestifyNilCheck
+		return AssertionKindTestifyNilCheck, 1
 
-	case "Panics", "PanicsWithValue", "PanicsWithError", "NotPanics":
-		return AssertionKindTestifyEqual
+	case "Panics", "NotPanics":
+		return AssertionKindTestifyEqual, 1
+
+	case "PanicsWithValue", "PanicsWithError":
+		return AssertionKindTestifyEqual, 2
+	}
+	return AssertionKindUnknown, -1
+}
+
+// classifyGotestToolsCall maps gotest.tools assert function names to
+// assertion kinds and returns the index of the argument holding the
+// actual value. gotest.tools takes (t, actual, expected).
+func classifyGotestToolsCall(method string) (AssertionKind, int) {
+	switch method {
+	case "Equal", "DeepEqual", "Assert", "Check":
+		return AssertionKindTestifyEqual, 1
+	case "NilError", "Error", "ErrorContains", "ErrorIs", "ErrorType":
+		return AssertionKindTestifyNoError, 1
 	}
-	return AssertionKindUnknown
+	return AssertionKindUnknown, -1
 }
 
 // detectGoCmpAssign checks for the go-cmp pattern:
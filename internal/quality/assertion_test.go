@@ -0,0 +1,170 @@
+package quality_test
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/unbound-force/gaze/internal/config"
+	"github.com/unbound-force/gaze/internal/quality"
+)
+
+// Stub assertion libraries, type-checked under their real import
+// paths so the detector recognizes them by path rather than name.
+var assertionLibStubs = map[string]string{
+	"github.com/stretchr/testify/assert": `package assert
+import "testing"
+type Assertions struct{}
+func New(t *testing.T) *Assertions { return nil }
+func (a *Assertions) Equal(expected, actual any) bool { return true }
+func Equal(t *testing.T, expected, actual any, msg ...any) bool { return true }
+func Equalf(t *testing.T, expected, actual any, msg string, args ...any) bool { return true }
+func ErrorIs(t *testing.T, err, target error, msg ...any) bool { return true }
+`,
+	"github.com/stretchr/testify/require": `package require
+import "testing"
+func Equal(t *testing.T, expected, actual any, msg ...any) {}
+`,
+	"gotest.tools/v3/assert": `package assert
+import "testing"
+func Equal(t *testing.T, x, y any, msg ...any) {}
+func Check(t *testing.T, comparison any, msg ...any) bool { return true }
+func NilError(t *testing.T, err error, msg ...any) {}
+`,
+	"example.com/testutil": `package testutil
+import "testing"
+func MustMatch(t *testing.T, pattern, got string) {}
+func Log(t *testing.T, v any) {}
+`,
+}
+
+const assertionLibTest = `package target
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tools "gotest.tools/v3/assert"
+	"example.com/testutil"
+)
+
+var errNotFound = errors.New("not found")
+
+func TestLibs(t *testing.T) {
+	got, want := 1, 1
+	var err error
+	assert.Equal(t, want, got)
+	assert.Equalf(t, want, got, "msg")
+	assert.ErrorIs(t, err, errNotFound)
+	require.Equal(t, want, got)
+	a := assert.New(t)
+	a.Equal(want, got)
+	tools.Equal(t, got, want)
+	tools.Check(t, got == want)
+	tools.NilError(t, err)
+	testutil.MustMatch(t, "^a", "abc")
+	testutil.Log(t, got)
+}
+`
+
+// loadAssertionLibPackage type-checks assertionLibTest against the
+// stub libraries and wraps it as a test package.
+func loadAssertionLibPackage(t *testing.T) *packages.Package {
+	t.Helper()
+	fset := token.NewFileSet()
+	std := importer.Default()
+	stubs := make(map[string]*types.Package)
+	imp := importerFunc(func(path string) (*types.Package, error) {
+		if pkg, ok := stubs[path]; ok {
+			return pkg, nil
+		}
+		return std.Import(path)
+	})
+	conf := types.Config{Importer: imp}
+	for path, src := range assertionLibStubs {
+		f, err := parser.ParseFile(fset, path+".go", src, 0)
+		if err != nil {
+			t.Fatalf("parse %s: %v", path, err)
+		}
+		pkg, err := conf.Check(path, fset, []*ast.File{f}, nil)
+		if err != nil {
+			t.Fatalf("type-check %s: %v", path, err)
+		}
+		stubs[path] = pkg
+	}
+
+	f, err := parser.ParseFile(fset, "target_test.go", assertionLibTest, 0)
+	if err != nil {
+		t.Fatalf("parse test: %v", err)
+	}
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Defs:  make(map[*ast.Ident]types.Object),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+	pkg, err := conf.Check("example.com/target", fset, []*ast.File{f}, info)
+	if err != nil {
+		t.Fatalf("type-check test: %v", err)
+	}
+	return &packages.Package{
+		PkgPath:   "example.com/target",
+		Fset:      fset,
+		Syntax:    []*ast.File{f},
+		Types:     pkg,
+		TypesInfo: info,
+	}
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
+
+func TestDetectAssertions_AssertionLibraries(t *testing.T) {
+	pkg := loadAssertionLibPackage(t)
+	decl := pkg.Syntax[0].Decls[len(pkg.Syntax[0].Decls)-1].(*ast.FuncDecl)
+
+	sites := quality.DetectAssertions(decl, pkg, 3, config.AssertionHelper{
+		Function: "example.com/testutil.MustMatch",
+		Actual:   2,
+	})
+
+	want := []struct {
+		kind   quality.AssertionKind
+		actual string
+	}{
+		{quality.AssertionKindTestifyEqual, "got"},         // assert.Equal
+		{quality.AssertionKindTestifyEqual, "got"},         // assert.Equalf
+		{quality.AssertionKindTestifyNoError, "err"},       // assert.ErrorIs
+		{quality.AssertionKindTestifyEqual, "got"},         // require.Equal
+		{quality.AssertionKindTestifyEqual, "got"},         // (*assert.Assertions).Equal
+		{quality.AssertionKindTestifyEqual, "got"},         // gotest.tools assert.Equal
+		{quality.AssertionKindTestifyEqual, "got == want"}, // gotest.tools assert.Check
+		{quality.AssertionKindTestifyNoError, "err"},       // gotest.tools assert.NilError
+		{quality.AssertionKindHelper, `"abc"`},             // registered helper
+	}
+	if len(sites) != len(want) {
+		for _, s := range sites {
+			t.Logf("site %s kind=%s", s.Location, s.Kind)
+		}
+		t.Fatalf("got %d assertion sites, want %d", len(sites), len(want))
+	}
+	for i, w := range want {
+		s := sites[i]
+		if s.Kind != w.kind {
+			t.Errorf("site %d (%s): kind = %s, want %s", i, s.Location, s.Kind, w.kind)
+		}
+		if s.Actual == nil {
+			t.Errorf("site %d (%s): Actual is nil, want %s", i, s.Location, w.actual)
+			continue
+		}
+		if got := types.ExprString(s.Actual); got != w.actual {
+			t.Errorf("site %d (%s): Actual = %s, want %s", i, s.Location, got, w.actual)
+		}
+	}
+}
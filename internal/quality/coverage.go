@@ -9,8 +9,9 @@ import (
 // at least one assertion mapping.
 //
 // Ambiguous side effects are excluded from both numerator and
-// denominator. Effects with no classification are treated as
-// contractual (conservative assumption).
+// denominator; those asserted on are listed in AssertedAmbiguous.
+// Effects with no classification are treated as contractual
+// (conservative assumption).
 //
 // The returned ContractCoverage.GapHints slice is parallel to Gaps:
 // len(GapHints) == len(Gaps) is an enforced postcondition. Each hint
@@ -32,10 +33,14 @@ func ComputeContractCoverage(
 	var coveredCount int
 	var gaps []taxonomy.SideEffect
 	var gapHints []string
+	var assertedAmbiguous []string
 
 	for _, e := range effects {
 		// Skip ambiguous effects — they are excluded from the metric.
 		if e.Classification != nil && e.Classification.Label == taxonomy.Ambiguous {
+			if assertedIDs[e.ID] {
+				assertedAmbiguous = append(assertedAmbiguous, e.ID)
+			}
 			continue
 		}
 
@@ -63,10 +68,94 @@ func ComputeContractCoverage(
 	}
 
 	return taxonomy.ContractCoverage{
-		Percentage:       percentage,
-		CoveredCount:     coveredCount,
-		TotalContractual: totalContractual,
-		Gaps:             gaps,
-		GapHints:         gapHints,
+		Percentage:        percentage,
+		CoveredCount:      coveredCount,
+		TotalContractual:  totalContractual,
+		Gaps:              gaps,
+		GapHints:          gapHints,
+		AssertedAmbiguous: assertedAmbiguous,
 	}
 }
+
+// AggregateFunctionCoverage combines the per-test quality reports
+// into one FunctionCoverage per analysis result, in the order of
+// results. A contractual effect counts as asserted when at least one
+// report for the function does not list it as a gap. Functions with
+// no reports have every contractual effect unasserted; functions
+// with no contractual effects are omitted.
+//
+// Reports without a target (from SSA-degraded assessment) are
+// ignored.
+func AggregateFunctionCoverage(
+	results []taxonomy.AnalysisResult,
+	reports []taxonomy.QualityReport,
+) []taxonomy.FunctionCoverage {
+	byTarget := make(map[string][]taxonomy.QualityReport)
+	for _, r := range reports {
+		if r.TargetFunction.Function == "" {
+			continue
+		}
+		key := r.TargetFunction.Package + ":" + r.TargetFunction.QualifiedName()
+		byTarget[key] = append(byTarget[key], r)
+	}
+
+	var coverage []taxonomy.FunctionCoverage
+	for _, result := range results {
+		contractual := ContractualEffects(result.SideEffects)
+		if len(contractual) == 0 {
+			continue
+		}
+
+		key := result.Target.Package + ":" + result.Target.QualifiedName()
+		targetReports := byTarget[key]
+
+		asserted := make(map[string]bool)
+		tests := make([]string, 0, len(targetReports))
+		seenTests := make(map[string]bool)
+		for _, r := range targetReports {
+			if !seenTests[r.TestFunction] {
+				seenTests[r.TestFunction] = true
+				tests = append(tests, r.TestFunction)
+			}
+			gaps := make(map[string]bool, len(r.ContractCoverage.Gaps))
+			for _, g := range r.ContractCoverage.Gaps {
+				gaps[g.ID] = true
+			}
+			for _, e := range contractual {
+				if !gaps[e.ID] {
+					asserted[e.ID] = true
+				}
+			}
+		}
+
+		fc := taxonomy.FunctionCoverage{
+			Target:           result.Target,
+			Tests:            tests,
+			AssertedCount:    len(asserted),
+			TotalContractual: len(contractual),
+		}
+		for _, e := range contractual {
+			if !asserted[e.ID] {
+				fc.Unasserted = append(fc.Unasserted, e)
+			}
+		}
+		fc.Percentage = float64(fc.AssertedCount) * 100.0 / float64(fc.TotalContractual)
+		coverage = append(coverage, fc)
+	}
+	return coverage
+}
+
+// ContractualEffects returns the effects that count toward contract
+// coverage: those not classified as ambiguous or incidental, as in
+// ComputeContractCoverage.
+func ContractualEffects(effects []taxonomy.SideEffect) []taxonomy.SideEffect {
+	var contractual []taxonomy.SideEffect
+	for _, e := range effects {
+		if e.Classification != nil &&
+			(e.Classification.Label == taxonomy.Ambiguous || e.Classification.Label == taxonomy.Incidental) {
+			continue
+		}
+		contractual = append(contractual, e)
+	}
+	return contractual
+}
@@ -39,6 +39,10 @@ func TestHintForEffect_AllTypes(t *testing.T) {
 		taxonomy.CallbackInvocation,
 		taxonomy.LogWrite,
 		taxonomy.ContextCancellation,
+		taxonomy.ContextValue,
+		taxonomy.MethodValueEscape,
+		taxonomy.LocalPointerEscape,
+		taxonomy.InternalPointerEscape,
 		// P3
 		taxonomy.StdoutWrite,
 		taxonomy.StderrWrite,
@@ -49,6 +53,7 @@ func TestHintForEffect_AllTypes(t *testing.T) {
 		taxonomy.TimeDependency,
 		taxonomy.ProcessExit,
 		taxonomy.RecoverBehavior,
+		taxonomy.DeferredCleanup,
 		// P4
 		taxonomy.ReflectionMutation,
 		taxonomy.UnsafeMutation,
@@ -107,7 +107,17 @@ func mapAssertionsToEffectsImpl(
 
 	// Match assertion expressions to traced values.
 	for _, site := range sites {
-		mapping := matchAssertionToEffect(site, objToEffectID, effectMap, testPkg)
+		var mapping *taxonomy.AssertionMapping
+		if site.Actual != nil {
+			// Try the actual argument of a library assertion first
+			// so the expected value cannot claim the match.
+			actualSite := site
+			actualSite.Expr = site.Actual
+			mapping = matchAssertionToEffect(actualSite, objToEffectID, effectMap, testPkg)
+		}
+		if mapping == nil {
+			mapping = matchAssertionToEffect(site, objToEffectID, effectMap, testPkg)
+		}
 		if mapping == nil && returnEffectID != "" {
 			// Fallback: check if the assertion expression contains
 			// an inline call to the target function (e.g., if f() != x).
@@ -13,6 +13,7 @@ import (
 	"golang.org/x/tools/go/packages"
 	"golang.org/x/tools/go/ssa"
 
+	"github.com/unbound-force/gaze/internal/config"
 	"github.com/unbound-force/gaze/internal/taxonomy"
 )
 
@@ -52,6 +53,11 @@ type Options struct {
 	// store.Set to verify the mutation). Mappings from AI are
 	// assigned confidence 50 (lower than all mechanical passes).
 	AIMapperFunc AIMapperFunc
+
+	// AssertionHelpers are additional functions recognized as
+	// assertions, typically from the quality.assertion_helpers
+	// section of .gaze.yaml.
+	AssertionHelpers []config.AssertionHelper
 }
 
 // DefaultOptions returns options with sensible defaults.
@@ -142,7 +148,7 @@ func Assess(
 		// confidence). Target, coverage, and mapping are zero-valued.
 		for _, tf := range testFuncs {
 			pairStart := time.Now()
-			sites := DetectAssertions(tf.Decl, testPkg, opts.MaxHelperDepth)
+			sites := DetectAssertions(tf.Decl, testPkg, opts.MaxHelperDepth, opts.AssertionHelpers...)
 			detectionConf := computeDetectionConfidence(sites)
 
 			report := taxonomy.QualityReport{
@@ -210,7 +216,7 @@ func Assess(
 				}
 
 				// Detect assertions in the test function.
-				sites := DetectAssertions(tf.Decl, testPkg, opts.MaxHelperDepth)
+				sites := DetectAssertions(tf.Decl, testPkg, opts.MaxHelperDepth, opts.AssertionHelpers...)
 
 				// Map assertions to side effects via SSA data flow.
 				mappings, unmapped, discardedIDs := mapAssertionsToEffectsImpl(
@@ -476,6 +476,20 @@ func TestComputeContractCoverage_AmbiguousExcluded(t *testing.T) {
 	if coverage.TotalContractual != 1 {
 		t.Errorf("expected 1 total contractual, got %d", coverage.TotalContractual)
 	}
+	if len(coverage.AssertedAmbiguous) != 0 {
+		t.Errorf("expected no asserted ambiguous effects, got %v", coverage.AssertedAmbiguous)
+	}
+
+	// An assertion on the ambiguous effect is recorded but still
+	// leaves it out of the metric.
+	mappings = append(mappings, taxonomy.AssertionMapping{SideEffectID: "se-002", Confidence: 80})
+	coverage = quality.ComputeContractCoverage(effects, mappings)
+	if coverage.Percentage != 100 || coverage.TotalContractual != 1 {
+		t.Errorf("coverage = %.0f%% of %d, want 100%% of 1", coverage.Percentage, coverage.TotalContractual)
+	}
+	if len(coverage.AssertedAmbiguous) != 1 || coverage.AssertedAmbiguous[0] != "se-002" {
+		t.Errorf("AssertedAmbiguous = %v, want [se-002]", coverage.AssertedAmbiguous)
+	}
 }
 
 func TestComputeContractCoverage_NoContractualEffects(t *testing.T) {
@@ -670,6 +684,119 @@ func TestWriteText_Output(t *testing.T) {
 	}
 }
 
+func TestAggregateFunctionCoverage_UnionAcrossTests(t *testing.T) {
+	contractual := &taxonomy.Classification{Label: taxonomy.Contractual}
+	incidental := &taxonomy.Classification{Label: taxonomy.Incidental}
+	results := []taxonomy.AnalysisResult{
+		{
+			Target: taxonomy.FunctionTarget{Package: "pkg", Function: "Save"},
+			SideEffects: []taxonomy.SideEffect{
+				{ID: "se-001", Type: taxonomy.ReturnValue, Classification: contractual},
+				{ID: "se-002", Type: taxonomy.ErrorReturn, Classification: contractual},
+				{ID: "se-003", Type: taxonomy.ReceiverMutation},
+				{ID: "se-004", Type: taxonomy.LogWrite, Classification: incidental},
+			},
+		},
+		{
+			Target: taxonomy.FunctionTarget{Package: "pkg", Function: "Load"},
+			SideEffects: []taxonomy.SideEffect{
+				{ID: "se-005", Type: taxonomy.ReturnValue, Classification: contractual},
+			},
+		},
+		{
+			Target:      taxonomy.FunctionTarget{Package: "pkg", Function: "Log"},
+			SideEffects: []taxonomy.SideEffect{{ID: "se-006", Type: taxonomy.LogWrite, Classification: incidental}},
+		},
+	}
+	save := results[0].Target
+	reports := []taxonomy.QualityReport{
+		{
+			TestFunction:     "TestSave",
+			TargetFunction:   save,
+			ContractCoverage: taxonomy.ContractCoverage{Gaps: []taxonomy.SideEffect{{ID: "se-002"}, {ID: "se-003"}}},
+		},
+		{
+			TestFunction:     "TestSave_Error",
+			TargetFunction:   save,
+			ContractCoverage: taxonomy.ContractCoverage{Gaps: []taxonomy.SideEffect{{ID: "se-001"}, {ID: "se-003"}}},
+		},
+		// Degraded reports have no target and are ignored.
+		{TestFunction: "TestDegraded"},
+	}
+
+	got := quality.AggregateFunctionCoverage(results, reports)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 functions (Log has no contractual effects), got %d", len(got))
+	}
+
+	s := got[0]
+	if s.Target.Function != "Save" {
+		t.Fatalf("expected Save first (source order), got %s", s.Target.Function)
+	}
+	if s.AssertedCount != 2 || s.TotalContractual != 3 {
+		t.Errorf("Save: expected 2/3 asserted, got %d/%d", s.AssertedCount, s.TotalContractual)
+	}
+	if len(s.Unasserted) != 1 || s.Unasserted[0].ID != "se-003" {
+		t.Errorf("Save: expected only se-003 unasserted, got %v", s.Unasserted)
+	}
+	if strings.Join(s.Tests, ",") != "TestSave,TestSave_Error" {
+		t.Errorf("Save: unexpected tests %v", s.Tests)
+	}
+
+	l := got[1]
+	if len(l.Tests) != 0 || l.AssertedCount != 0 || l.Percentage != 0 || len(l.Unasserted) != 1 {
+		t.Errorf("Load: expected untested with 1 unasserted effect, got %+v", l)
+	}
+}
+
+func TestWriteCoverage_Output(t *testing.T) {
+	coverage := []taxonomy.FunctionCoverage{
+		{
+			Target:           taxonomy.FunctionTarget{Package: "pkg", Function: "Save", Location: "store.go:10:1"},
+			Tests:            []string{"TestSave"},
+			Percentage:       50,
+			AssertedCount:    1,
+			TotalContractual: 2,
+			Unasserted: []taxonomy.SideEffect{
+				{Type: taxonomy.ErrorReturn, Description: "returns error", Location: "store.go:14:3"},
+			},
+		},
+		{
+			Target:           taxonomy.FunctionTarget{Package: "pkg", Function: "Load"},
+			TotalContractual: 1,
+		},
+	}
+
+	var text bytes.Buffer
+	if err := quality.WriteCoverageText(&text, coverage); err != nil {
+		t.Fatalf("WriteCoverageText failed: %v", err)
+	}
+	for _, want := range []string{"Save", "(1/2)", "ErrorReturn: returns error (store.go:14:3)",
+		"Tests: none", "Functions: 2 (1 without tests)", "asserted: 1/3"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("text output missing %q:\n%s", want, text.String())
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := quality.WriteCoverageJSON(&buf, coverage); err != nil {
+		t.Fatalf("WriteCoverageJSON failed: %v", err)
+	}
+	var output struct {
+		Functions []taxonomy.FunctionCoverage `json:"function_coverage"`
+		Summary   quality.CoverageSummary     `json:"coverage_summary"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(output.Functions) != 2 {
+		t.Errorf("expected 2 functions, got %d", len(output.Functions))
+	}
+	if output.Summary.AssertedCount != 1 || output.Summary.TotalContractual != 3 || output.Summary.Untested != 1 {
+		t.Errorf("unexpected summary: %+v", output.Summary)
+	}
+}
+
 // --- Acceptance Tests ---
 
 func TestSC004_Determinism(t *testing.T) {
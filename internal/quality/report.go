@@ -4,9 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/unbound-force/gaze/internal/report"
 	"github.com/unbound-force/gaze/internal/taxonomy"
 )
 
@@ -27,14 +29,24 @@ func WriteJSON(w io.Writer, reports []taxonomy.QualityReport, summary *taxonomy.
 	return enc.Encode(output)
 }
 
+// WriteCompactJSON is like WriteJSON but writes the document on a
+// single line, for piping to tools such as jq or for storage.
+func WriteCompactJSON(w io.Writer, reports []taxonomy.QualityReport, summary *taxonomy.PackageSummary) error {
+	return json.NewEncoder(w).Encode(qualityOutput{
+		Reports: reports,
+		Summary: summary,
+	})
+}
+
 // WriteText writes a human-readable quality report with lipgloss styling.
 func WriteText(w io.Writer, reports []taxonomy.QualityReport, summary *taxonomy.PackageSummary) error {
 	// Styles.
-	header := lipgloss.NewStyle().Bold(true)
-	good := lipgloss.NewStyle().Foreground(lipgloss.Color("2"))    // green
-	warn := lipgloss.NewStyle().Foreground(lipgloss.Color("3"))    // yellow
-	bad := lipgloss.NewStyle().Foreground(lipgloss.Color("1"))     // red
-	muted := lipgloss.NewStyle().Foreground(lipgloss.Color("240")) // gray
+	renderer := report.ColorAuto.Renderer(w)
+	header := renderer.NewStyle().Bold(true)
+	good := renderer.NewStyle().Foreground(lipgloss.Color("2"))    // green
+	warn := renderer.NewStyle().Foreground(lipgloss.Color("3"))    // yellow
+	bad := renderer.NewStyle().Foreground(lipgloss.Color("1"))     // red
+	muted := renderer.NewStyle().Foreground(lipgloss.Color("240")) // gray
 
 	for i, r := range reports {
 		if i > 0 {
@@ -184,3 +196,113 @@ func WriteText(w io.Writer, reports []taxonomy.QualityReport, summary *taxonomy.
 
 	return nil
 }
+
+// coverageOutput is the top-level JSON structure for per-function
+// contract coverage.
+type coverageOutput struct {
+	Functions []taxonomy.FunctionCoverage `json:"function_coverage"`
+	Summary   CoverageSummary             `json:"coverage_summary"`
+}
+
+// CoverageSummary totals per-function contract coverage for a package.
+type CoverageSummary struct {
+	// Functions is the number of functions with contractual effects.
+	Functions int `json:"functions"`
+
+	// Untested is the number of those functions no test targets.
+	Untested int `json:"untested"`
+
+	// AssertedCount is the number of contractual effects asserted on.
+	AssertedCount int `json:"asserted_count"`
+
+	// TotalContractual is the number of contractual effects.
+	TotalContractual int `json:"total_contractual"`
+
+	// Percentage is AssertedCount over TotalContractual (0-100).
+	Percentage float64 `json:"percentage"`
+}
+
+// SummarizeCoverage totals per-function contract coverage.
+func SummarizeCoverage(coverage []taxonomy.FunctionCoverage) CoverageSummary {
+	s := CoverageSummary{Functions: len(coverage)}
+	for _, fc := range coverage {
+		if len(fc.Tests) == 0 {
+			s.Untested++
+		}
+		s.AssertedCount += fc.AssertedCount
+		s.TotalContractual += fc.TotalContractual
+	}
+	if s.TotalContractual > 0 {
+		s.Percentage = float64(s.AssertedCount) * 100.0 / float64(s.TotalContractual)
+	}
+	return s
+}
+
+// WriteCoverageJSON serializes per-function contract coverage and
+// its summary as formatted JSON.
+func WriteCoverageJSON(w io.Writer, coverage []taxonomy.FunctionCoverage) error {
+	if coverage == nil {
+		coverage = []taxonomy.FunctionCoverage{}
+	}
+	output := coverageOutput{
+		Functions: coverage,
+		Summary:   SummarizeCoverage(coverage),
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(output)
+}
+
+// WriteCoverageText writes per-function contract coverage as a
+// human-readable report, listing each unasserted contractual effect
+// with its location.
+func WriteCoverageText(w io.Writer, coverage []taxonomy.FunctionCoverage) error {
+	renderer := report.ColorAuto.Renderer(w)
+	header := renderer.NewStyle().Bold(true)
+	good := renderer.NewStyle().Foreground(lipgloss.Color("2"))    // green
+	warn := renderer.NewStyle().Foreground(lipgloss.Color("3"))    // yellow
+	bad := renderer.NewStyle().Foreground(lipgloss.Color("1"))     // red
+	muted := renderer.NewStyle().Foreground(lipgloss.Color("240")) // gray
+
+	for i, fc := range coverage {
+		if i > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+		_, _ = fmt.Fprintln(w, header.Render(fmt.Sprintf("=== %s ===", fc.Target.QualifiedName())))
+		_, _ = fmt.Fprintf(w, "    Location: %s\n", fc.Target.Location)
+
+		covStyle := good
+		if fc.Percentage < 50 {
+			covStyle = bad
+		} else if fc.Percentage < 80 {
+			covStyle = warn
+		}
+		_, _ = fmt.Fprintf(w, "    Contract Coverage: %s (%d/%d)\n",
+			covStyle.Render(fmt.Sprintf("%.0f%%", fc.Percentage)),
+			fc.AssertedCount, fc.TotalContractual)
+
+		if len(fc.Tests) == 0 {
+			_, _ = fmt.Fprintf(w, "    Tests: %s\n", bad.Render("none"))
+		} else {
+			_, _ = fmt.Fprintf(w, "    Tests: %s\n", strings.Join(fc.Tests, ", "))
+		}
+
+		if len(fc.Unasserted) > 0 {
+			_, _ = fmt.Fprintln(w, muted.Render("    Unasserted contractual effects:"))
+			for _, e := range fc.Unasserted {
+				_, _ = fmt.Fprintf(w, "      - %s: %s (%s)\n",
+					e.Type, e.Description, e.Location)
+			}
+		}
+	}
+
+	s := SummarizeCoverage(coverage)
+	if len(coverage) > 0 {
+		_, _ = fmt.Fprintln(w)
+	}
+	_, _ = fmt.Fprintln(w, header.Render("=== Summary ==="))
+	_, _ = fmt.Fprintf(w, "    Functions: %d (%d without tests)\n", s.Functions, s.Untested)
+	_, _ = fmt.Fprintf(w, "    Contractual effects asserted: %d/%d (%.0f%%)\n",
+		s.AssertedCount, s.TotalContractual, s.Percentage)
+	return nil
+}
@@ -0,0 +1,67 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// ColorMode selects when text reports use ANSI colors.
+type ColorMode string
+
+const (
+	// ColorAuto colors output only when the writer is a terminal
+	// that supports it. NO_COLOR and CLICOLOR_FORCE are honored.
+	ColorAuto ColorMode = "auto"
+
+	// ColorAlways colors output regardless of the writer.
+	ColorAlways ColorMode = "always"
+
+	// ColorNever writes plain text.
+	ColorNever ColorMode = "never"
+)
+
+// ParseColorMode validates a --color flag value.
+func ParseColorMode(s string) (ColorMode, error) {
+	switch m := ColorMode(s); m {
+	case ColorAuto, ColorAlways, ColorNever:
+		return m, nil
+	}
+	return "", fmt.Errorf("invalid color mode %q: must be 'auto', 'always', or 'never'", s)
+}
+
+// Renderer returns a lipgloss renderer for w with the color profile
+// m selects. The empty mode behaves like ColorAuto: colors are used
+// only when w is a color-capable terminal, so output written to
+// files, pipes, and buffers stays plain. Every colored report is
+// rendered through it, so colorEnabled decides for all of them.
+func (m ColorMode) Renderer(w io.Writer) *lipgloss.Renderer {
+	r := lipgloss.NewRenderer(w)
+	switch {
+	case !colorEnabled(m, w):
+		r.SetColorProfile(termenv.Ascii)
+	case m == ColorAlways:
+		r.SetColorProfile(termenv.ANSI256)
+	}
+	return r
+}
+
+// colorEnabled reports whether output to w should be colored under
+// mode m. An explicit always or never wins; otherwise a non-empty
+// NO_COLOR disables color (https://no-color.org), and color is used
+// only when w is a terminal whose environment supports it.
+func colorEnabled(m ColorMode, w io.Writer) bool {
+	switch m {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return termenv.NewOutput(w).EnvColorProfile() != termenv.Ascii
+}
@@ -0,0 +1,56 @@
+package report
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// DeferTraps reduces results to their DeferredReturnMutation effects
+// — named returns modified by a defer after the body's apparent
+// return — and drops functions without any.
+func DeferTraps(results []taxonomy.AnalysisResult) []taxonomy.AnalysisResult {
+	var out []taxonomy.AnalysisResult
+	for _, r := range results {
+		var traps []taxonomy.SideEffect
+		for _, e := range r.SideEffects {
+			if e.Type == taxonomy.DeferredReturnMutation {
+				traps = append(traps, e)
+			}
+		}
+		if len(traps) > 0 {
+			r.SideEffects = traps
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// WriteDeferTraps writes a focused text report of the defer traps in
+// results: for each affected function, every named return a defer
+// modifies, with the deferred call and its position. Functions
+// without traps are counted in the summary but not listed.
+func WriteDeferTraps(w io.Writer, results []taxonomy.AnalysisResult, opts TextOptions) error {
+	s := NewStyles(opts.Color.Renderer(w))
+	traps := SortResults(DeferTraps(results), opts.Sort)
+
+	total := 0
+	for i, r := range traps {
+		if i > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+		_, _ = fmt.Fprintln(w, s.Header.Render(fmt.Sprintf("=== %s ===", r.Target.QualifiedName())))
+		_, _ = fmt.Fprintln(w, s.SubHeader.Render(fmt.Sprintf("    %s", r.Target.Location)))
+		for _, e := range r.SideEffects {
+			_, _ = fmt.Fprintf(w, "    %s %s\n", s.TierP1.Render(e.Target), e.Description)
+			_, _ = fmt.Fprintln(w, s.Muted.Render(fmt.Sprintf("      at %s", e.Location)))
+			total++
+		}
+	}
+
+	summary := fmt.Sprintf("%d defer trap(s) in %d function(s), %d function(s) analyzed",
+		total, len(traps), len(results))
+	_, _ = fmt.Fprintf(w, "\n%s\n", s.Header.Render(summary))
+	return nil
+}
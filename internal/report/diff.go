@@ -0,0 +1,338 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// DiffMatch selects how side effects are matched between two
+// analyses.
+type DiffMatch string
+
+const (
+	// MatchID matches effects by their stable ID within the same
+	// function. IDs of some effect types (e.g. ReturnValue, Panic)
+	// include the source position, so moving such code shows up as
+	// a removal and an addition.
+	MatchID DiffMatch = "id"
+
+	// MatchTarget matches effects by function, type, and target,
+	// ignoring IDs and positions, so line shifts never count as
+	// changes. Repeated effects with the same key are paired in
+	// source order.
+	MatchTarget DiffMatch = "target"
+)
+
+// ParseDiffMatch validates a --match flag value.
+func ParseDiffMatch(s string) (DiffMatch, error) {
+	switch m := DiffMatch(s); m {
+	case MatchID, MatchTarget:
+		return m, nil
+	}
+	return "", fmt.Errorf("invalid match mode %q: must be 'id' or 'target'", s)
+}
+
+// EffectChange is one side effect that differs between two analyses.
+type EffectChange struct {
+	Package  string `json:"package"`
+	Function string `json:"function"`
+
+	// Old is the effect in the old analysis; nil for added effects.
+	Old *taxonomy.SideEffect `json:"old,omitempty"`
+
+	// New is the effect in the new analysis; nil for removed
+	// effects.
+	New *taxonomy.SideEffect `json:"new,omitempty"`
+}
+
+// EffectDiff is the result of comparing two analyses. Each list is
+// ordered by package, function, and the effect's position in its
+// function.
+type EffectDiff struct {
+	// Added lists effects only in the new analysis.
+	Added []EffectChange `json:"added"`
+
+	// Removed lists effects only in the old analysis.
+	Removed []EffectChange `json:"removed"`
+
+	// Changed lists matched effects whose tier, description, target
+	// type, or classification label differ. Positions are not
+	// compared.
+	Changed []EffectChange `json:"changed"`
+}
+
+// Empty reports whether the analyses have no differences.
+func (d *EffectDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// keyedEffect is a side effect with the function it belongs to.
+type keyedEffect struct {
+	pkg, function string
+	effect        *taxonomy.SideEffect
+}
+
+// DiffResults compares the side effects of two analyses of the same
+// code, such as before and after a change, matching effects with
+// match.
+func DiffResults(old, current []taxonomy.AnalysisResult, match DiffMatch) *EffectDiff {
+	oldKeys, oldOrder := diffKeys(old, match)
+	newKeys, newOrder := diffKeys(current, match)
+
+	d := &EffectDiff{
+		Added:   []EffectChange{},
+		Removed: []EffectChange{},
+		Changed: []EffectChange{},
+	}
+	for _, key := range newOrder {
+		n := newKeys[key]
+		o, ok := oldKeys[key]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, EffectChange{Package: n.pkg, Function: n.function, New: n.effect})
+		case effectChanged(o.effect, n.effect):
+			d.Changed = append(d.Changed, EffectChange{Package: n.pkg, Function: n.function, Old: o.effect, New: n.effect})
+		}
+	}
+	for _, key := range oldOrder {
+		if _, ok := newKeys[key]; !ok {
+			o := oldKeys[key]
+			d.Removed = append(d.Removed, EffectChange{Package: o.pkg, Function: o.function, Old: o.effect})
+		}
+	}
+	sortChanges(d.Added)
+	sortChanges(d.Removed)
+	sortChanges(d.Changed)
+	return d
+}
+
+// diffKeys indexes the side effects of results by their match key
+// and returns the keys in result order.
+func diffKeys(results []taxonomy.AnalysisResult, match DiffMatch) (map[string]keyedEffect, []string) {
+	keys := make(map[string]keyedEffect)
+	var order []string
+	for _, r := range results {
+		function := r.Target.QualifiedName()
+		seen := make(map[string]int)
+		for i := range r.SideEffects {
+			e := &r.SideEffects[i]
+			base := r.Target.Package + "\x00" + function + "\x00"
+			if match == MatchTarget {
+				base += string(e.Type) + "\x00" + e.Target
+			} else {
+				base += e.ID
+			}
+			key := base + "\x00" + strconv.Itoa(seen[base])
+			seen[base]++
+			keys[key] = keyedEffect{pkg: r.Target.Package, function: function, effect: e}
+			order = append(order, key)
+		}
+	}
+	return keys, order
+}
+
+// effectChanged reports whether a matched effect differs in anything
+// but its position.
+func effectChanged(old, current *taxonomy.SideEffect) bool {
+	return old.Tier != current.Tier ||
+		old.Description != current.Description ||
+		old.TargetType != current.TargetType ||
+		classificationLabel(old) != classificationLabel(current)
+}
+
+// classificationLabel returns e's classification label, or "" when
+// it is not classified.
+func classificationLabel(e *taxonomy.SideEffect) taxonomy.ClassificationLabel {
+	if e.Classification == nil {
+		return ""
+	}
+	return e.Classification.Label
+}
+
+// sortChanges orders changes by package and function, keeping the
+// order of effects within a function.
+func sortChanges(changes []EffectChange) {
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Package != changes[j].Package {
+			return changes[i].Package < changes[j].Package
+		}
+		return changes[i].Function < changes[j].Function
+	})
+}
+
+// WriteDiffJSON writes d as formatted JSON.
+func WriteDiffJSON(w io.Writer, d *EffectDiff) error {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// WriteDiffText writes d for a reviewer: a heading per function with
+// a line for each added (+), removed (-), or changed (~) effect, and
+// a summary line.
+func WriteDiffText(w io.Writer, d *EffectDiff) error {
+	type line struct {
+		mark   string
+		change EffectChange
+	}
+	byFunc := make(map[string][]line)
+	var funcs []string
+	add := func(mark string, changes []EffectChange) {
+		for _, c := range changes {
+			name := c.Package + "." + c.Function
+			if _, ok := byFunc[name]; !ok {
+				funcs = append(funcs, name)
+			}
+			byFunc[name] = append(byFunc[name], line{mark: mark, change: c})
+		}
+	}
+	add("+", d.Added)
+	add("-", d.Removed)
+	add("~", d.Changed)
+	sort.Strings(funcs)
+
+	for i, name := range funcs {
+		if i > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+		_, _ = fmt.Fprintf(w, "=== %s ===\n", name)
+		for _, l := range byFunc[name] {
+			switch l.mark {
+			case "+":
+				e := l.change.New
+				_, _ = fmt.Fprintf(w, "  + %s: %s\n      at %s\n", e.Type, e.Description, e.Location)
+			case "-":
+				e := l.change.Old
+				_, _ = fmt.Fprintf(w, "  - %s: %s\n      at %s\n", e.Type, e.Description, e.Location)
+			default:
+				o, n := l.change.Old, l.change.New
+				_, _ = fmt.Fprintf(w, "  ~ %s: %s\n", n.Type, n.Description)
+				for _, diff := range effectFieldDiffs(o, n) {
+					_, _ = fmt.Fprintf(w, "      %s\n", diff)
+				}
+				_, _ = fmt.Fprintf(w, "      at %s\n", n.Location)
+			}
+		}
+	}
+	if len(funcs) > 0 {
+		_, _ = fmt.Fprintln(w)
+	}
+	_, err := fmt.Fprintf(w, "%d added, %d removed, %d changed side effect(s) in %d function(s)\n",
+		len(d.Added), len(d.Removed), len(d.Changed), len(funcs))
+	return err
+}
+
+// effectFieldDiffs describes the fields effectChanged compares that
+// differ between old and current, as "field: old -> new" lines.
+func effectFieldDiffs(old, current *taxonomy.SideEffect) []string {
+	var out []string
+	field := func(name, o, n string) {
+		if o != n {
+			out = append(out, fmt.Sprintf("%s: %q -> %q", name, o, n))
+		}
+	}
+	field("tier", string(old.Tier), string(current.Tier))
+	field("description", old.Description, current.Description)
+	field("target_type", old.TargetType, current.TargetType)
+	field("classification", string(classificationLabel(old)), string(classificationLabel(current)))
+	return out
+}
+
+// ReadJSON decodes an analysis written by WriteJSON, WriteJSONOptions,
+// or StreamJSON, in any of their formats: sentinel errors are
+// returned as "<package>" results, and structured positions are
+// converted back to "file:line:col" strings.
+func ReadJSON(r io.Reader) ([]taxonomy.AnalysisResult, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data, err = flattenLocations(data)
+	if err != nil {
+		return nil, err
+	}
+	var rpt JSONReport
+	if err := json.Unmarshal(data, &rpt); err != nil {
+		return nil, err
+	}
+
+	results := rpt.Results
+	byPkg := make(map[string]int)
+	for _, s := range rpt.Sentinels {
+		desc := fmt.Sprintf("package-level sentinel error '%s'", s.Name)
+		if s.Wrapped {
+			desc += taxonomy.SentinelWrapSuffix
+		}
+		i, ok := byPkg[s.Package]
+		if !ok {
+			i = len(results)
+			byPkg[s.Package] = i
+			results = append(results, taxonomy.AnalysisResult{
+				Target: taxonomy.FunctionTarget{Package: s.Package, Function: "<package>"},
+			})
+		}
+		results[i].SideEffects = append(results[i].SideEffects, taxonomy.SideEffect{
+			ID:             s.ID,
+			Type:           taxonomy.SentinelError,
+			Tier:           taxonomy.TierOf(taxonomy.SentinelError),
+			Location:       s.Location,
+			Description:    desc,
+			Target:         s.Name,
+			Classification: s.Classification,
+		})
+	}
+	return results, nil
+}
+
+// flattenLocations rewrites every structured position in a JSON
+// document (see LocationStructured) to its string form.
+func flattenLocations(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	var walk func(v any)
+	walk = func(v any) {
+		switch t := v.(type) {
+		case map[string]any:
+			for k, child := range t {
+				if pos, ok := child.(map[string]any); ok && locationKeys[k] {
+					t[k] = positionString(pos)
+					continue
+				}
+				walk(child)
+			}
+		case []any:
+			for _, child := range t {
+				walk(child)
+			}
+		}
+	}
+	walk(doc)
+	return json.Marshal(doc)
+}
+
+// positionString formats a decoded Position object as
+// "file:line:col", dropping the parts it does not carry.
+func positionString(pos map[string]any) string {
+	s, _ := pos["file"].(string)
+	for _, k := range []string{"line", "col"} {
+		n, ok := pos[k].(json.Number)
+		if !ok {
+			break
+		}
+		s += ":" + n.String()
+	}
+	return s
+}
@@ -0,0 +1,81 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// WriteGitHubActions writes one GitHub Actions workflow command per
+// side effect, so that each effect is shown as an annotation on the
+// pull request diff. P0 and P1 effects are warnings; lower tiers
+// are notices. File paths are made relative to $GITHUB_WORKSPACE
+// (or, outside Actions, the current directory) because annotations
+// only attach to repository-relative paths.
+func WriteGitHubActions(w io.Writer, results []taxonomy.AnalysisResult) error {
+	root := os.Getenv("GITHUB_WORKSPACE")
+	if root == "" {
+		root, _ = os.Getwd()
+	}
+
+	sw := &stickyWriter{w: w}
+	for _, r := range results {
+		for _, e := range r.SideEffects {
+			file, line, col := splitLocation(e.Location)
+			props := []string{"file=" + escapeProperty(annotationPath(root, file))}
+			if line > 0 {
+				props = append(props, fmt.Sprintf("line=%d", line))
+			}
+			if col > 0 {
+				props = append(props, fmt.Sprintf("col=%d", col))
+			}
+			if endFile, endLine, endCol := splitLocation(e.EndLocation); endFile == file && endLine > 0 {
+				props = append(props, fmt.Sprintf("endLine=%d", endLine))
+				if endLine == line && endCol > 0 {
+					props = append(props, fmt.Sprintf("endColumn=%d", endCol))
+				}
+			}
+			props = append(props, "title="+escapeProperty("gaze: "+r.Target.QualifiedName()))
+
+			sw.printf("::%s %s::%s\n", annotationLevel(e.Tier), strings.Join(props, ","),
+				escapeData(fmt.Sprintf("%s: %s", e.Type, e.Description)))
+		}
+	}
+	return sw.err
+}
+
+// annotationLevel maps a tier to a workflow command.
+func annotationLevel(tier taxonomy.Tier) string {
+	switch tier {
+	case taxonomy.TierP0, taxonomy.TierP1:
+		return "warning"
+	default:
+		return "notice"
+	}
+}
+
+// annotationPath returns file relative to root, with forward
+// slashes. Paths outside root, and relative paths, are returned
+// unchanged apart from the separators.
+func annotationPath(root, file string) string {
+	if root != "" && filepath.IsAbs(file) {
+		if rel, err := filepath.Rel(root, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = rel
+		}
+	}
+	return filepath.ToSlash(file)
+}
+
+// escapeData escapes a workflow command message.
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes a workflow command property value.
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
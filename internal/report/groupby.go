@@ -0,0 +1,112 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// GroupBy selects how text output is organized.
+type GroupBy string
+
+const (
+	// GroupFunction lists each function with its side effects.
+	GroupFunction GroupBy = "function"
+
+	// GroupType lists each side effect type with every function
+	// and location that produces it.
+	GroupType GroupBy = "type"
+
+	// GroupTier lists each tier with every side effect in it.
+	GroupTier GroupBy = "tier"
+)
+
+// ParseGroupBy validates a --group-by flag value.
+func ParseGroupBy(s string) (GroupBy, error) {
+	switch g := GroupBy(s); g {
+	case GroupFunction, GroupType, GroupTier:
+		return g, nil
+	}
+	return "", fmt.Errorf("invalid grouping %q: must be 'function', 'type', or 'tier'", s)
+}
+
+// groupedEffect is a side effect together with the function that
+// produces it.
+type groupedEffect struct {
+	target taxonomy.FunctionTarget
+	effect taxonomy.SideEffect
+}
+
+// writeGroupedText writes results organized by opts.GroupBy (type
+// or tier) instead of by function: a heading per group, highest
+// tier first, and under it every effect in the group with its
+// function and location. Effects keep the order opts.Sort gives
+// them, function by function.
+func writeGroupedText(w io.Writer, results []taxonomy.AnalysisResult, s Styles, opts TextOptions) error {
+	results = SortResults(results, opts.Sort)
+
+	groups := make(map[string][]groupedEffect)
+	tiers := make(map[string]taxonomy.Tier)
+	functions := make(map[string]bool)
+	total := 0
+	for _, r := range results {
+		for _, e := range r.SideEffects {
+			key := string(e.Tier)
+			if opts.GroupBy == GroupType {
+				key = string(e.Type)
+			}
+			groups[key] = append(groups[key], groupedEffect{target: r.Target, effect: e})
+			tiers[key] = e.Tier
+			functions[r.Target.Package+"."+r.Target.QualifiedName()] = true
+			total++
+		}
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if tiers[keys[i]] != tiers[keys[j]] {
+			return tiers[keys[i]] < tiers[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	for i, key := range keys {
+		if i > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+		tier := string(tiers[key])
+		heading := fmt.Sprintf("=== %s (%s) ===", key, tier)
+		if opts.GroupBy == GroupTier {
+			heading = fmt.Sprintf("=== %s ===", tier)
+		}
+		_, _ = fmt.Fprintln(w, s.TierStyle(tier).Bold(true).Render(heading))
+		for _, g := range groups[key] {
+			line := fmt.Sprintf("%s: %s", displayName(g.target), g.effect.Description)
+			if opts.GroupBy == GroupTier {
+				// The cell style pads the type with one space.
+				line = s.effectTypeStyle(g.effect).Render(string(g.effect.Type)) + line
+			}
+			if (opts.Classify || opts.Verbose) && g.effect.Classification != nil {
+				c := g.effect.Classification
+				line += " " + s.ClassificationStyle(string(c.Label)).Render(
+					fmt.Sprintf("[%s/%d%%]", c.Label, c.Confidence))
+			}
+			_, _ = fmt.Fprintf(w, "    %s\n", line)
+			_, _ = fmt.Fprintln(w, s.Muted.Render(fmt.Sprintf("      at %s", g.effect.Location)))
+		}
+	}
+
+	noun := "type(s)"
+	if opts.GroupBy == GroupTier {
+		noun = "tier(s)"
+	}
+	summary := fmt.Sprintf("%d side effect(s) of %d %s in %d function(s), %d function(s) analyzed",
+		total, len(keys), noun, len(functions), len(results))
+	_, _ = fmt.Fprintf(w, "\n%s\n", s.Header.Render(summary))
+	return nil
+}
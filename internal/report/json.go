@@ -1,10 +1,14 @@
 // Package report provides output formatters for Gaze analysis
-// results in JSON and human-readable text formats.
+// results in JSON, YAML, and human-readable text formats.
 package report
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
+	"sort"
+	"strings"
 
 	"github.com/unbound-force/gaze/internal/taxonomy"
 )
@@ -13,24 +17,280 @@ import (
 type JSONReport struct {
 	Version string                    `json:"version"`
 	Results []taxonomy.AnalysisResult `json:"results"`
+
+	// Sentinels lists the package-level sentinel errors found by
+	// the analysis. Absent in legacy output, where sentinels are
+	// attached to a synthetic "<package>" result instead.
+	Sentinels []taxonomy.Sentinel `json:"sentinels"`
+}
+
+// legacyJSONReport is the pre-grouping JSON output structure, used
+// when JSONOptions.LegacySentinels is set.
+type legacyJSONReport struct {
+	Version string                    `json:"version"`
+	Results []taxonomy.AnalysisResult `json:"results"`
+}
+
+// JSONOptions controls the JSON output format.
+type JSONOptions struct {
+	// Version is embedded in the output; if empty, it defaults
+	// to "dev".
+	Version string
+
+	// LegacySentinels keeps sentinel errors in the results array
+	// as a synthetic "<package>" function result, as in earlier
+	// releases, instead of grouping them in a top-level
+	// "sentinels" array.
+	LegacySentinels bool
+
+	// Sort reorders functions and their side effects; see
+	// SortResults. The zero value keeps the order results are in.
+	// When streaming, only the effects within each function are
+	// sorted, since functions are written as they arrive.
+	Sort SortOrder
+
+	// Locations selects how source positions are written. The zero
+	// value, like LocationString, keeps "file:line:col" strings.
+	Locations LocationFormat
+
+	// Compact writes newline-delimited JSON instead of one indented
+	// document: each function result on a single line, in the order
+	// the document would list them. With no top-level array to hold
+	// them, sentinel errors keep their synthetic "<package>" results
+	// as with LegacySentinels, and the version is carried only by
+	// each result's metadata.
+	Compact bool
 }
 
 // WriteJSON writes analysis results as formatted JSON to the writer.
 // The version string is embedded in the JSON output; if empty,
-// it defaults to "dev".
+// it defaults to "dev". Sentinel errors are grouped in the
+// top-level "sentinels" array.
 func WriteJSON(w io.Writer, results []taxonomy.AnalysisResult, version string) error {
-	if results == nil {
-		results = []taxonomy.AnalysisResult{}
+	return WriteJSONOptions(w, results, JSONOptions{Version: version})
+}
+
+// WriteJSONOptions writes analysis results as formatted JSON to the
+// writer using the given options. Results from several packages
+// (e.g. gaze analyze ./...) are written as one document, grouped by
+// package in import path order and ordered by opts.Sort within each
+// package.
+func WriteJSONOptions(w io.Writer, results []taxonomy.AnalysisResult, opts JSONOptions) error {
+	version := opts.Version
+	if version == "" {
+		version = "dev"
+	}
+	results = groupByPackage(SortResults(results, opts.Sort))
+
+	if opts.Compact {
+		sw := &stickyWriter{w: w}
+		for _, r := range results {
+			writeLine(sw, r, opts.Locations)
+		}
+		return sw.err
+	}
+
+	var report any
+	if opts.LegacySentinels {
+		if results == nil {
+			results = []taxonomy.AnalysisResult{}
+		}
+		report = legacyJSONReport{Version: version, Results: results}
+	} else {
+		funcs, sentinels := groupSentinels(results)
+		report = JSONReport{Version: version, Results: funcs, Sentinels: sentinels}
+	}
+
+	data, err := marshalJSON(report, "", opts.Locations)
+	if err != nil {
+		return err
 	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// StreamJSON writes analysis results received on results as the
+// same JSON document WriteJSON produces, encoding each result as
+// soon as it arrives instead of buffering the full slice. It returns
+// once results is closed. If a write fails, the remaining results
+// are drained (so the producer is not blocked) and the first error
+// is returned.
+func StreamJSON(w io.Writer, results <-chan taxonomy.AnalysisResult, version string) error {
+	return StreamJSONOptions(w, results, JSONOptions{Version: version})
+}
+
+// StreamJSONOptions is like StreamJSON but uses the given options,
+// producing the same document as WriteJSONOptions. Sentinel errors
+// are held back and written after the results array. With
+// opts.Compact, each result is written as its line as it arrives.
+func StreamJSONOptions(w io.Writer, results <-chan taxonomy.AnalysisResult, opts JSONOptions) error {
+	if opts.Compact {
+		sw := &stickyWriter{w: w}
+		for r := range results {
+			if opts.Sort != "" {
+				r.SideEffects = sortEffects(r.SideEffects, opts.Sort)
+			}
+			writeLine(sw, r, opts.Locations)
+		}
+		return sw.err
+	}
+
+	version := opts.Version
 	if version == "" {
 		version = "dev"
 	}
-	report := JSONReport{
-		Version: version,
-		Results: results,
+	versionJSON, err := json.Marshal(version)
+	if err != nil {
+		return err
+	}
+
+	sw := &stickyWriter{w: w}
+	sw.printf("{\n  \"version\": %s,\n  \"results\": [", versionJSON)
+
+	n := 0
+	sentinels := []taxonomy.Sentinel{}
+	for r := range results {
+		if sw.err != nil {
+			continue
+		}
+		if !opts.LegacySentinels && isSentinelResult(r) {
+			sentinels = append(sentinels, sentinelsOf(r)...)
+			continue
+		}
+		if opts.Sort != "" {
+			r.SideEffects = sortEffects(r.SideEffects, opts.Sort)
+		}
+		data, err := marshalJSON(r, "    ", opts.Locations)
+		if err != nil {
+			sw.err = err
+			continue
+		}
+		if n > 0 {
+			sw.printf(",")
+		}
+		sw.printf("\n    %s", data)
+		n++
+	}
+
+	if n > 0 {
+		sw.printf("\n  ")
+	}
+	sw.printf("]")
+
+	if !opts.LegacySentinels && sw.err == nil {
+		data, err := marshalJSON(sentinels, "  ", opts.Locations)
+		if err != nil {
+			return err
+		}
+		sw.printf(",\n  \"sentinels\": %s", data)
+	}
+
+	sw.printf("\n}\n")
+	return sw.err
+}
+
+// writeLine writes r to sw as one line of compact JSON. After a
+// failed write or encoding it does nothing, so the caller can keep
+// draining its results.
+func writeLine(sw *stickyWriter, r taxonomy.AnalysisResult, format LocationFormat) {
+	if sw.err != nil {
+		return
+	}
+	data, err := marshalJSON(r, "", format)
+	if err != nil {
+		sw.err = err
+		return
+	}
+	var line bytes.Buffer
+	if err := json.Compact(&line, data); err != nil {
+		sw.err = err
+		return
+	}
+	sw.printf("%s\n", line.Bytes())
+}
+
+// stickyWriter wraps an io.Writer and records the first write
+// error, turning later writes into no-ops.
+type stickyWriter struct {
+	w   io.Writer
+	err error
+}
+
+// printf formats to the underlying writer unless an earlier write
+// has failed.
+func (s *stickyWriter) printf(format string, args ...any) {
+	if s.err != nil {
+		return
+	}
+	_, s.err = fmt.Fprintf(s.w, format, args...)
+}
+
+// groupByPackage returns results stably ordered by package import
+// path. A single-package result set is returned unchanged.
+func groupByPackage(results []taxonomy.AnalysisResult) []taxonomy.AnalysisResult {
+	multi := false
+	for _, r := range results {
+		if r.Target.Package != results[0].Target.Package {
+			multi = true
+			break
+		}
+	}
+	if !multi {
+		return results
 	}
+	grouped := append([]taxonomy.AnalysisResult(nil), results...)
+	sort.SliceStable(grouped, func(i, j int) bool {
+		return grouped[i].Target.Package < grouped[j].Target.Package
+	})
+	return grouped
+}
+
+// groupSentinels splits results into per-function results and the
+// sentinel errors carried by synthetic "<package>" results. Both
+// return values are non-nil.
+func groupSentinels(results []taxonomy.AnalysisResult) ([]taxonomy.AnalysisResult, []taxonomy.Sentinel) {
+	funcs := []taxonomy.AnalysisResult{}
+	sentinels := []taxonomy.Sentinel{}
+	for _, r := range results {
+		if isSentinelResult(r) {
+			sentinels = append(sentinels, sentinelsOf(r)...)
+			continue
+		}
+		funcs = append(funcs, r)
+	}
+	return funcs, sentinels
+}
 
-	enc := json.NewEncoder(w)
-	enc.SetIndent("", "  ")
-	return enc.Encode(report)
+// isSentinelResult reports whether r is the synthetic package-level
+// result that carries a file's sentinel errors.
+func isSentinelResult(r taxonomy.AnalysisResult) bool {
+	return r.Target.Function == "<package>"
+}
+
+// sentinelsOf converts the SentinelError side effects of a
+// "<package>" result into Sentinel entries. Each entry carries the
+// result's warnings that name it, such as a duplicate message.
+func sentinelsOf(r taxonomy.AnalysisResult) []taxonomy.Sentinel {
+	var out []taxonomy.Sentinel
+	for _, e := range r.SideEffects {
+		if e.Type != taxonomy.SentinelError {
+			continue
+		}
+		var warnings []string
+		for _, w := range r.Metadata.Warnings {
+			if strings.Contains(w, "'"+e.Target+"'") {
+				warnings = append(warnings, w)
+			}
+		}
+		out = append(out, taxonomy.Sentinel{
+			ID:             e.ID,
+			Package:        r.Target.Package,
+			Name:           e.Target,
+			Location:       e.Location,
+			Wrapped:        strings.HasSuffix(e.Description, taxonomy.SentinelWrapSuffix),
+			Classification: e.Classification,
+			Warnings:       warnings,
+		})
+	}
+	return out
 }
@@ -0,0 +1,134 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// LocationFormat selects how source positions are written in JSON
+// output.
+type LocationFormat string
+
+const (
+	// LocationString writes positions as packed "file:line:col"
+	// strings.
+	LocationString LocationFormat = "string"
+
+	// LocationStructured writes positions as
+	// {"file": ..., "line": N, "col": N} objects.
+	LocationStructured LocationFormat = "structured"
+)
+
+// ParseLocationFormat validates a --locations flag value.
+func ParseLocationFormat(s string) (LocationFormat, error) {
+	switch f := LocationFormat(s); f {
+	case LocationString, LocationStructured:
+		return f, nil
+	}
+	return "", fmt.Errorf("invalid location format %q: must be 'string' or 'structured'", s)
+}
+
+// Position is the structured form of a source position. Line and
+// Col are omitted when the position does not carry them, such as
+// the bare file name of a legacy "<package>" result.
+type Position struct {
+	File string `json:"file"`
+	Line int    `json:"line,omitempty"`
+	Col  int    `json:"col,omitempty"`
+}
+
+// locationKeys are the JSON object keys whose string values are
+// source positions.
+var locationKeys = map[string]bool{
+	"location":     true,
+	"end_location": true,
+}
+
+// marshalJSON marshals v with the given indent prefix, the way the
+// JSON writers lay out their output, rewriting every position to
+// format.
+func marshalJSON(v any, prefix string, format LocationFormat) ([]byte, error) {
+	data, err := json.MarshalIndent(v, prefix, "  ")
+	if err != nil || format != LocationStructured {
+		return data, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var compact bytes.Buffer
+	if err := structureLocations(dec, &compact, ""); err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, compact.Bytes(), prefix, "  "); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// structureLocations copies the next JSON value from dec to buf,
+// preserving key order, and replaces the string value of any key in
+// locationKeys with its Position object. key is the object key the
+// value belongs to, or "" for array elements and the top level.
+func structureLocations(dec *json.Decoder, buf *bytes.Buffer, key string) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '{' {
+			buf.WriteByte('{')
+			for i := 0; dec.More(); i++ {
+				k, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				if i > 0 {
+					buf.WriteByte(',')
+				}
+				if err := writeJSONValue(buf, k); err != nil {
+					return err
+				}
+				buf.WriteByte(':')
+				if err := structureLocations(dec, buf, k.(string)); err != nil {
+					return err
+				}
+			}
+			buf.WriteByte('}')
+		} else {
+			buf.WriteByte('[')
+			for i := 0; dec.More(); i++ {
+				if i > 0 {
+					buf.WriteByte(',')
+				}
+				if err := structureLocations(dec, buf, ""); err != nil {
+					return err
+				}
+			}
+			buf.WriteByte(']')
+		}
+		// Consume the closing delimiter.
+		_, err := dec.Token()
+		return err
+
+	case string:
+		if locationKeys[key] {
+			file, line, col := splitLocation(t)
+			return writeJSONValue(buf, Position{File: file, Line: line, Col: col})
+		}
+	}
+	return writeJSONValue(buf, tok)
+}
+
+// writeJSONValue appends the JSON encoding of v to buf.
+func writeJSONValue(buf *bytes.Buffer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	return nil
+}
@@ -3,6 +3,8 @@ package report
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
@@ -10,7 +12,9 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/santhosh-tekuri/jsonschema/v6"
+	"gopkg.in/yaml.v3"
 
+	"github.com/unbound-force/gaze/internal/analysis"
 	"github.com/unbound-force/gaze/internal/taxonomy"
 )
 
@@ -46,6 +50,7 @@ func sampleResults() []taxonomy.AnalysisResult {
 					Type:        taxonomy.ReceiverMutation,
 					Tier:        taxonomy.TierP0,
 					Location:    "store.go:55:2",
+					EndLocation: "store.go:55:28",
 					Description: "mutates receiver field 'lastSaved'",
 					Target:      "lastSaved",
 				},
@@ -117,7 +122,7 @@ func TestWriteJSON_ContainsAllFields(t *testing.T) {
 	output := buf.String()
 	requiredFields := []string{
 		`"version"`, `"results"`, `"target"`, `"side_effects"`,
-		`"id"`, `"type"`, `"tier"`, `"location"`,
+		`"id"`, `"type"`, `"tier"`, `"location"`, `"end_location"`,
 		`"description"`, `"package"`, `"function"`,
 		`"signature"`, `"gaze_version"`, `"go_version"`,
 	}
@@ -241,6 +246,64 @@ func TestWriteJSON_ValidAgainstSchema(t *testing.T) {
 	}
 }
 
+func TestWriteJSON_MultiplePackages(t *testing.T) {
+	results, err := analysis.LoadAndAnalyze("./testdata/src/multipkg/...", analysis.Options{})
+	if err != nil {
+		t.Fatalf("LoadAndAnalyze: %v", err)
+	}
+	// Interleave the packages to check that the writer groups them.
+	for i, j := 0, len(results)-1; i < j; i, j = i+1, j-1 {
+		results[i], results[j] = results[j], results[i]
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJSONOptions(&buf, results, JSONOptions{Version: "0.1.0", Sort: SortLocation}); err != nil {
+		t.Fatalf("WriteJSONOptions: %v", err)
+	}
+
+	sch, err := jsonschema.UnmarshalJSON(strings.NewReader(Schema))
+	if err != nil {
+		t.Fatalf("failed to parse schema JSON: %v", err)
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("schema.json", sch); err != nil {
+		t.Fatalf("failed to add schema resource: %v", err)
+	}
+	compiled, err := compiler.Compile("schema.json")
+	if err != nil {
+		t.Fatalf("failed to compile schema: %v", err)
+	}
+	inst, err := jsonschema.UnmarshalJSON(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("failed to parse JSON output: %v", err)
+	}
+	if err := compiled.Validate(inst); err != nil {
+		t.Errorf("JSON output does not conform to schema:\n%v", err)
+	}
+
+	var doc JSONReport
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	var got []string
+	for _, r := range doc.Results {
+		got = append(got, r.Target.Package+"."+r.Target.QualifiedName())
+	}
+	const base = "github.com/unbound-force/gaze/internal/report/testdata/src/multipkg"
+	want := []string{
+		base + "/alpha.First",
+		base + "/alpha.Double",
+		base + "/beta.(*Counter).Inc",
+		base + "/beta.(*Counter).Value",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("results:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if len(doc.Sentinels) != 1 || doc.Sentinels[0].Package != base+"/alpha" {
+		t.Errorf("expected ErrEmpty sentinel in alpha, got %+v", doc.Sentinels)
+	}
+}
+
 // stripANSI removes ANSI escape sequences from text for width measurement.
 var ansiRe = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]`)
 
@@ -313,6 +376,504 @@ func TestWriteTextOptions_ClassifyColumn(t *testing.T) {
 	}
 }
 
+func TestWriteTextOptions_Quiet(t *testing.T) {
+	results := append(sampleResults(), taxonomy.AnalysisResult{
+		Target: taxonomy.FunctionTarget{
+			Package:   "example.com/pkg",
+			Function:  "Pure",
+			Signature: "func Pure()",
+			Location:  "pure.go:1:1",
+		},
+	})
+
+	var buf bytes.Buffer
+	if err := WriteTextOptions(&buf, results, TextOptions{Quiet: true}); err != nil {
+		t.Fatalf("WriteTextOptions quiet failed: %v", err)
+	}
+
+	output := buf.String()
+	if strings.Contains(output, "Pure") || strings.Contains(output, "No side effects detected") {
+		t.Errorf("expected function without side effects to be omitted:\n%s", output)
+	}
+	if !strings.Contains(output, "(*Store).Save") {
+		t.Error("expected function with side effects to be shown")
+	}
+	if !strings.Contains(output, "2 function(s) analyzed, 3 side effect(s) detected (1 without side effects not shown)") {
+		t.Errorf("expected summary to count hidden functions:\n%s", output)
+	}
+}
+
+func TestWriteText_SuppressedCount(t *testing.T) {
+	results := sampleResults()
+	results[0].Suppressed = 2
+
+	var buf bytes.Buffer
+	if err := WriteText(&buf, results); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "3 side effect(s) detected, 2 suppressed by //gaze:ignore") {
+		t.Errorf("expected summary to count suppressed effects:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteSummary(&buf, results); err != nil {
+		t.Fatalf("WriteSummary failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "2 suppressed by //gaze:ignore") {
+		t.Errorf("expected digest to count suppressed effects:\n%s", buf.String())
+	}
+}
+
+func TestParseColorMode(t *testing.T) {
+	for _, v := range []string{"auto", "always", "never"} {
+		if m, err := ParseColorMode(v); err != nil || string(m) != v {
+			t.Errorf("ParseColorMode(%q) = %q, %v", v, m, err)
+		}
+	}
+	if _, err := ParseColorMode("sometimes"); err == nil {
+		t.Error("expected error for invalid color mode")
+	}
+}
+
+func TestParseSortOrder(t *testing.T) {
+	for _, v := range []string{"location", "tier", "type", "confidence"} {
+		if o, err := ParseSortOrder(v); err != nil || string(o) != v {
+			t.Errorf("ParseSortOrder(%q) = %q, %v", v, o, err)
+		}
+	}
+	if _, err := ParseSortOrder("severity"); err == nil {
+		t.Error("expected error for invalid sort order")
+	}
+}
+
+func TestParseGroupBy(t *testing.T) {
+	for _, v := range []string{"function", "type", "tier"} {
+		if g, err := ParseGroupBy(v); err != nil || string(g) != v {
+			t.Errorf("ParseGroupBy(%q) = %q, %v", v, g, err)
+		}
+	}
+	if _, err := ParseGroupBy("package"); err == nil {
+		t.Error("expected error for invalid grouping")
+	}
+}
+
+func TestWriteTextOptions_GroupBy(t *testing.T) {
+	results := []taxonomy.AnalysisResult{
+		{
+			Target: taxonomy.FunctionTarget{Function: "Save", Receiver: "*Store", Location: "store.go:40:1"},
+			SideEffects: []taxonomy.SideEffect{
+				{Type: taxonomy.ErrorReturn, Tier: taxonomy.TierP0, Location: "store.go:40:1", Description: "returns error"},
+				{Type: taxonomy.GlobalMutation, Tier: taxonomy.TierP1, Location: "store.go:44:2", Description: "mutates global 'saves'"},
+			},
+		},
+		{
+			Target: taxonomy.FunctionTarget{Function: "Reset", Location: "store.go:60:1"},
+			SideEffects: []taxonomy.SideEffect{
+				{Type: taxonomy.GlobalMutation, Tier: taxonomy.TierP1, Location: "store.go:61:2", Description: "mutates global 'saves'"},
+			},
+		},
+		{Target: taxonomy.FunctionTarget{Function: "Pure", Location: "store.go:70:1"}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTextOptions(&buf, results, TextOptions{Color: ColorNever, GroupBy: GroupType}); err != nil {
+		t.Fatal(err)
+	}
+	want := `=== ErrorReturn (P0) ===
+    (*Store).Save: returns error
+      at store.go:40:1
+
+=== GlobalMutation (P1) ===
+    (*Store).Save: mutates global 'saves'
+      at store.go:44:2
+    Reset: mutates global 'saves'
+      at store.go:61:2
+
+3 side effect(s) of 2 type(s) in 2 function(s), 3 function(s) analyzed
+`
+	if buf.String() != want {
+		t.Errorf("group by type:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := WriteTextOptions(&buf, results, TextOptions{Color: ColorNever, GroupBy: GroupTier}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"=== P1 ===\n    GlobalMutation (*Store).Save: mutates global 'saves'\n",
+		"    GlobalMutation Reset: mutates global 'saves'\n",
+		"3 side effect(s) of 2 tier(s) in 2 function(s), 3 function(s) analyzed",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("group by tier: expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteGitHubActions(t *testing.T) {
+	t.Setenv("GITHUB_WORKSPACE", "/work/repo")
+	results := []taxonomy.AnalysisResult{{
+		Target: taxonomy.FunctionTarget{Function: "Save", Receiver: "*Store"},
+		SideEffects: []taxonomy.SideEffect{
+			{
+				Type: taxonomy.ReceiverMutation, Tier: taxonomy.TierP0,
+				Location: "/work/repo/store/store.go:55:2", EndLocation: "/work/repo/store/store.go:55:28",
+				Description: "mutates receiver field 'lastSaved'",
+			},
+			{
+				Type: taxonomy.LogWrite, Tier: taxonomy.TierP2,
+				Location: "/elsewhere/log.go:3:1", EndLocation: "/elsewhere/log.go:5:2",
+				Description: "calls log.Printf: 100%, done\nnext",
+			},
+			{
+				Type: taxonomy.SentinelError, Tier: taxonomy.TierP0,
+				Location: "errors.go", Description: "package-level sentinel error 'ErrNotFound'",
+			},
+		},
+	}}
+
+	var buf bytes.Buffer
+	if err := WriteGitHubActions(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	want := "::warning file=store/store.go,line=55,col=2,endLine=55,endColumn=28,title=gaze%3A (*Store).Save::ReceiverMutation: mutates receiver field 'lastSaved'\n" +
+		"::notice file=/elsewhere/log.go,line=3,col=1,endLine=5,title=gaze%3A (*Store).Save::LogWrite: calls log.Printf: 100%25, done%0Anext\n" +
+		"::warning file=errors.go,title=gaze%3A (*Store).Save::SentinelError: package-level sentinel error 'ErrNotFound'\n"
+	if got := buf.String(); got != want {
+		t.Errorf("WriteGitHubActions:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteGitHubActions_WriteError(t *testing.T) {
+	if err := WriteGitHubActions(failingWriter{}, sampleResults()); err == nil {
+		t.Error("expected write error")
+	}
+}
+
+func TestWriteDeferTraps(t *testing.T) {
+	results := []taxonomy.AnalysisResult{
+		{
+			Target: taxonomy.FunctionTarget{Function: "Close", Receiver: "*Store", Location: "store.go:40:1"},
+			SideEffects: []taxonomy.SideEffect{
+				{Type: taxonomy.ErrorReturn, Tier: taxonomy.TierP0, Location: "store.go:40:1", Description: "returns error"},
+				{
+					Type: taxonomy.DeferredReturnMutation, Tier: taxonomy.TierP1,
+					Location: "store.go:41:2", Target: "err",
+					Description: "named return 'err' modified in defer func() {...}(), after the body's apparent return",
+				},
+			},
+		},
+		{
+			Target:      taxonomy.FunctionTarget{Function: "Open", Location: "store.go:10:1"},
+			SideEffects: []taxonomy.SideEffect{{Type: taxonomy.ErrorReturn, Tier: taxonomy.TierP0}},
+		},
+	}
+
+	traps := DeferTraps(results)
+	if len(traps) != 1 || len(traps[0].SideEffects) != 1 || traps[0].SideEffects[0].Target != "err" {
+		t.Fatalf("DeferTraps = %+v, want only (*Store).Close's err", traps)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDeferTraps(&buf, results, TextOptions{Color: ColorNever}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"=== (*Store).Close ===",
+		"err named return 'err' modified in defer func() {...}()",
+		"at store.go:41:2",
+		"1 defer trap(s) in 1 function(s), 2 function(s) analyzed",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Open") {
+		t.Errorf("function without traps listed:\n%s", out)
+	}
+}
+
+func TestParseLocationFormat(t *testing.T) {
+	for _, v := range []string{"string", "structured"} {
+		if f, err := ParseLocationFormat(v); err != nil || string(f) != v {
+			t.Errorf("ParseLocationFormat(%q) = %q, %v", v, f, err)
+		}
+	}
+	if _, err := ParseLocationFormat("lsp"); err == nil {
+		t.Error("expected error for invalid location format")
+	}
+}
+
+func TestSortResults(t *testing.T) {
+	classified := func(conf int) *taxonomy.Classification {
+		return &taxonomy.Classification{Label: taxonomy.Contractual, Confidence: conf}
+	}
+	results := []taxonomy.AnalysisResult{
+		{
+			Target: taxonomy.FunctionTarget{Function: "Log", Location: "a.go:10:1"},
+			SideEffects: []taxonomy.SideEffect{
+				{ID: "log-write", Type: taxonomy.LogWrite, Tier: taxonomy.TierP2, Location: "a.go:11:2", Classification: classified(40)},
+			},
+		},
+		{
+			Target: taxonomy.FunctionTarget{Function: "Pure", Location: "a.go:2:1"},
+		},
+		{
+			Target: taxonomy.FunctionTarget{Function: "Save", Location: "a.go:9:1"},
+			SideEffects: []taxonomy.SideEffect{
+				{ID: "save-err", Type: taxonomy.ErrorReturn, Tier: taxonomy.TierP0, Location: "a.go:9:30", Classification: classified(90)},
+				{ID: "save-fs", Type: taxonomy.FileSystemWrite, Tier: taxonomy.TierP1, Location: "a.go:9:12"},
+				{ID: "save-recv", Type: taxonomy.ReceiverMutation, Tier: taxonomy.TierP0, Location: "a.go:9:5", Classification: classified(90)},
+			},
+		},
+	}
+
+	tests := []struct {
+		order     SortOrder
+		functions []string
+		saveOrder []string
+	}{
+		{"", []string{"Log", "Pure", "Save"}, []string{"save-err", "save-fs", "save-recv"}},
+		// Line 9 before line 10 (numeric, not lexical); columns likewise.
+		{SortLocation, []string{"Pure", "Save", "Log"}, []string{"save-recv", "save-fs", "save-err"}},
+		// Equal tiers break on location.
+		{SortTier, []string{"Save", "Log", "Pure"}, []string{"save-recv", "save-err", "save-fs"}},
+		{SortType, []string{"Save", "Log", "Pure"}, []string{"save-err", "save-fs", "save-recv"}},
+		// Unclassified effects come last.
+		{SortConfidence, []string{"Save", "Log", "Pure"}, []string{"save-recv", "save-err", "save-fs"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.order), func(t *testing.T) {
+			sorted := SortResults(results, tt.order)
+			var functions, saveOrder []string
+			for _, r := range sorted {
+				functions = append(functions, r.Target.Function)
+				if r.Target.Function == "Save" {
+					for _, e := range r.SideEffects {
+						saveOrder = append(saveOrder, e.ID)
+					}
+				}
+			}
+			if strings.Join(functions, ",") != strings.Join(tt.functions, ",") {
+				t.Errorf("functions = %v, want %v", functions, tt.functions)
+			}
+			if strings.Join(saveOrder, ",") != strings.Join(tt.saveOrder, ",") {
+				t.Errorf("Save effects = %v, want %v", saveOrder, tt.saveOrder)
+			}
+		})
+	}
+
+	// The input is never reordered in place.
+	if results[0].Target.Function != "Log" || results[2].SideEffects[0].ID != "save-err" {
+		t.Error("SortResults modified its input")
+	}
+}
+
+func TestWriteTextOptions_Color(t *testing.T) {
+	tests := []struct {
+		mode      ColorMode
+		wantColor bool
+	}{
+		// A bytes.Buffer is not a terminal, so auto stays plain.
+		{"", false},
+		{ColorAuto, false},
+		{ColorNever, false},
+		{ColorAlways, true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteTextOptions(&buf, sampleResults(), TextOptions{Color: tt.mode}); err != nil {
+				t.Fatalf("WriteTextOptions failed: %v", err)
+			}
+			if got := strings.Contains(buf.String(), "\x1b["); got != tt.wantColor {
+				t.Errorf("escape codes present = %v, want %v:\n%q", got, tt.wantColor, buf.String())
+			}
+		})
+	}
+}
+
+func TestColorEnabled(t *testing.T) {
+	// CLICOLOR_FORCE makes a buffer count as color-capable, so auto
+	// would color it if NO_COLOR were not consulted.
+	t.Setenv("CLICOLOR_FORCE", "1")
+	tests := []struct {
+		mode    ColorMode
+		noColor string
+		want    bool
+	}{
+		{ColorAuto, "", true},
+		{"", "", true},
+		{ColorAuto, "1", false},
+		{"", "1", false},
+		{ColorNever, "", false},
+		{ColorAlways, "1", true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode)+"/NO_COLOR="+tt.noColor, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			if got := colorEnabled(tt.mode, &bytes.Buffer{}); got != tt.want {
+				t.Errorf("colorEnabled = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteTextOptions_NoColorEnv(t *testing.T) {
+	t.Setenv("CLICOLOR_FORCE", "1")
+	t.Setenv("NO_COLOR", "1")
+	var buf bytes.Buffer
+	if err := WriteTextOptions(&buf, sampleResults(), TextOptions{Classify: true}); err != nil {
+		t.Fatalf("WriteTextOptions failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("expected no escape codes with NO_COLOR set:\n%q", buf.String())
+	}
+}
+
+func TestWriteText_TerminatingEffectCallout(t *testing.T) {
+	results := []taxonomy.AnalysisResult{{
+		Target: taxonomy.FunctionTarget{
+			Package:   "example.com/pkg",
+			Function:  "Fatal",
+			Signature: "func Fatal(msg string)",
+			Location:  "fatal.go:3:1",
+		},
+		SideEffects: []taxonomy.SideEffect{
+			{
+				ID:          "se-exit",
+				Type:        taxonomy.ProcessExit,
+				Tier:        taxonomy.TierP1,
+				Location:    "fatal.go:5:2",
+				Description: "calls os.Exit with code 1",
+			},
+			{
+				ID:          "se-log",
+				Type:        taxonomy.LogWrite,
+				Tier:        taxonomy.TierP1,
+				Location:    "fatal.go:4:2",
+				Description: "writes to log",
+			},
+		},
+	}}
+
+	var buf bytes.Buffer
+	if err := WriteText(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+	if !strings.Contains(output, "! ProcessExit: calls os.Exit with code 1 (fatal.go:5:2)") {
+		t.Errorf("expected ProcessExit callout:\n%s", output)
+	}
+	if strings.Contains(output, "! LogWrite") {
+		t.Errorf("expected no callout for LogWrite:\n%s", output)
+	}
+}
+
+func TestWriteSummary(t *testing.T) {
+	results := append(sampleResults(),
+		taxonomy.AnalysisResult{
+			Target: taxonomy.FunctionTarget{Package: "example.com/pkg", Function: "Pure"},
+		},
+		taxonomy.AnalysisResult{
+			Target: taxonomy.FunctionTarget{Package: "example.com/pkg", Function: "Logf"},
+			SideEffects: []taxonomy.SideEffect{
+				{Type: taxonomy.LogWrite, Tier: taxonomy.TierP1},
+				{Type: taxonomy.GoroutineSpawn, Tier: taxonomy.TierP2},
+			},
+		},
+	)
+
+	var buf bytes.Buffer
+	if err := WriteSummary(&buf, results); err != nil {
+		t.Fatalf("WriteSummary failed: %v", err)
+	}
+	output := buf.String()
+	for _, want := range []string{
+		"HIGHEST TIER",
+		"(*Store).Save",
+		"Pure",
+		"Logf",
+		"3 function(s) analyzed, 5 side effect(s) detected",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in summary:\n%s", want, output)
+		}
+	}
+	// The per-effect listing is not included.
+	if strings.Contains(output, "DESCRIPTION") || strings.Contains(output, "ReturnValue") {
+		t.Errorf("summary should not list individual effects:\n%s", output)
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(line, "Logf") && !strings.Contains(line, "P1") {
+			t.Errorf("expected Logf highest tier P1, got line %q", line)
+		}
+	}
+
+	buf.Reset()
+	if err := WriteSummaryOptions(&buf, results, TextOptions{Quiet: true}); err != nil {
+		t.Fatalf("WriteSummaryOptions failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "Pure") {
+		t.Errorf("expected quiet summary to omit Pure:\n%s", buf.String())
+	}
+}
+
+func TestWriteJSONSummary(t *testing.T) {
+	results := append(sampleResults(),
+		taxonomy.AnalysisResult{
+			Target: taxonomy.FunctionTarget{Package: "example.com/pkg", Function: "<package>"},
+			SideEffects: []taxonomy.SideEffect{
+				{Type: taxonomy.SentinelError, Tier: taxonomy.TierP0},
+			},
+		},
+		taxonomy.AnalysisResult{
+			Target: taxonomy.FunctionTarget{Package: "example.com/pkg", Function: "Spawn"},
+			SideEffects: []taxonomy.SideEffect{
+				{Type: taxonomy.GoroutineSpawn, Tier: taxonomy.TierP2},
+			},
+		},
+	)
+
+	var buf bytes.Buffer
+	if err := WriteJSONSummary(&buf, results); err != nil {
+		t.Fatalf("WriteJSONSummary failed: %v", err)
+	}
+	var got struct {
+		Functions     int            `json:"functions"`
+		EffectsByTier map[string]int `json:"effects_by_tier"`
+		EffectsByType map[string]int `json:"effects_by_type"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if got.Functions != len(sampleResults())+1 {
+		t.Errorf("functions = %d, want %d (sentinel result excluded)", got.Functions, len(sampleResults())+1)
+	}
+	if got.EffectsByType["SentinelError"] != 1 || got.EffectsByType["GoroutineSpawn"] != 1 {
+		t.Errorf("unexpected effects_by_type: %v", got.EffectsByType)
+	}
+
+	// Every tier and taxonomy type is present, even with no results.
+	buf.Reset()
+	if err := WriteJSONSummary(&buf, nil); err != nil {
+		t.Fatalf("WriteJSONSummary failed: %v", err)
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(got.EffectsByTier) != len(taxonomy.Tiers()) {
+		t.Errorf("effects_by_tier has %d keys, want %d", len(got.EffectsByTier), len(taxonomy.Tiers()))
+	}
+	for _, typ := range taxonomy.Types() {
+		if n, ok := got.EffectsByType[string(typ)]; !ok || n != 0 {
+			t.Errorf("effects_by_type[%s] = %d, %v; want 0, true", typ, n, ok)
+		}
+	}
+}
+
 func TestWriteTextOptions_VerboseSignalBreakdown(t *testing.T) {
 	var buf bytes.Buffer
 	err := WriteTextOptions(&buf, sampleClassifiedResults(), TextOptions{
@@ -702,3 +1263,514 @@ func TestTierStyle_AllTiers(t *testing.T) {
 		}
 	}
 }
+
+// sendResults returns a closed channel pre-filled with results.
+func sendResults(results []taxonomy.AnalysisResult) <-chan taxonomy.AnalysisResult {
+	ch := make(chan taxonomy.AnalysisResult, len(results))
+	for _, r := range results {
+		ch <- r
+	}
+	close(ch)
+	return ch
+}
+
+func TestStreamJSON_MatchesWriteJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []taxonomy.AnalysisResult
+	}{
+		{"empty", nil},
+		{"one", sampleResults()[:1]},
+		{"many", sampleResults()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var want, got bytes.Buffer
+			if err := WriteJSON(&want, tt.results, "0.1.0"); err != nil {
+				t.Fatalf("WriteJSON failed: %v", err)
+			}
+			if err := StreamJSON(&got, sendResults(tt.results), "0.1.0"); err != nil {
+				t.Fatalf("StreamJSON failed: %v", err)
+			}
+			if got.String() != want.String() {
+				t.Errorf("StreamJSON output differs from WriteJSON:\ngot:\n%s\nwant:\n%s",
+					got.String(), want.String())
+			}
+		})
+	}
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestStreamJSON_WriteErrorDrainsChannel(t *testing.T) {
+	// An unbuffered producer must not block when the writer fails.
+	ch := make(chan taxonomy.AnalysisResult)
+	go func() {
+		for _, r := range sampleResults() {
+			ch <- r
+		}
+		close(ch)
+	}()
+
+	err := StreamJSON(failingWriter{}, ch, "")
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("StreamJSON error = %v, want disk full", err)
+	}
+}
+
+// resultsWithSentinels returns sampleResults plus a synthetic
+// "<package>" result carrying one plain and one wrapping sentinel.
+func resultsWithSentinels() []taxonomy.AnalysisResult {
+	return append(sampleResults(), taxonomy.AnalysisResult{
+		Target: taxonomy.FunctionTarget{
+			Package:  "example.com/store",
+			Function: "<package>",
+			Location: "errors.go",
+		},
+		SideEffects: []taxonomy.SideEffect{
+			{
+				ID:          "se-11111111",
+				Type:        taxonomy.SentinelError,
+				Tier:        taxonomy.TierP0,
+				Location:    "errors.go:5:5",
+				Description: "package-level sentinel error 'ErrNotFound'",
+				Target:      "ErrNotFound",
+			},
+			{
+				ID:          "se-22222222",
+				Type:        taxonomy.SentinelError,
+				Tier:        taxonomy.TierP0,
+				Location:    "errors.go:6:5",
+				Description: "package-level sentinel error 'ErrWrapped'" + taxonomy.SentinelWrapSuffix,
+				Target:      "ErrWrapped",
+			},
+		},
+	})
+}
+
+func TestWriteJSON_GroupsSentinels(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, resultsWithSentinels(), "0.1.0"); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+
+	var rpt JSONReport
+	if err := json.Unmarshal(buf.Bytes(), &rpt); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	for _, r := range rpt.Results {
+		if r.Target.Function == "<package>" {
+			t.Error("grouped output should not contain a <package> result")
+		}
+	}
+	if len(rpt.Results) != len(sampleResults()) {
+		t.Errorf("got %d results, want %d", len(rpt.Results), len(sampleResults()))
+	}
+
+	want := []taxonomy.Sentinel{
+		{ID: "se-11111111", Package: "example.com/store", Name: "ErrNotFound", Location: "errors.go:5:5"},
+		{ID: "se-22222222", Package: "example.com/store", Name: "ErrWrapped", Location: "errors.go:6:5", Wrapped: true},
+	}
+	if len(rpt.Sentinels) != len(want) {
+		t.Fatalf("got %d sentinels, want %d", len(rpt.Sentinels), len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(rpt.Sentinels[i], want[i]) {
+			t.Errorf("sentinel[%d] = %+v, want %+v", i, rpt.Sentinels[i], want[i])
+		}
+	}
+}
+
+func TestWriteJSON_SentinelWarnings(t *testing.T) {
+	results := resultsWithSentinels()
+	warning := `sentinel: 'ErrNotFound', 'ErrMissing' share the message "not found"`
+	for i := range results {
+		if results[i].Target.Function == "<package>" {
+			results[i].Metadata.Warnings = []string{warning}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, results, "0.1.0"); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	var rpt JSONReport
+	if err := json.Unmarshal(buf.Bytes(), &rpt); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	for _, s := range rpt.Sentinels {
+		var want []string
+		if s.Name == "ErrNotFound" {
+			want = []string{warning}
+		}
+		if !reflect.DeepEqual(s.Warnings, want) {
+			t.Errorf("%s warnings = %q, want %q", s.Name, s.Warnings, want)
+		}
+	}
+}
+
+func TestWriteJSON_EmptySentinelsArray(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, sampleResults(), "0.1.0"); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"sentinels": []`) {
+		t.Errorf("expected empty sentinels array in output:\n%s", buf.String())
+	}
+}
+
+func TestWriteJSONOptions_LegacySentinels(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteJSONOptions(&buf, resultsWithSentinels(), JSONOptions{
+		Version:         "0.1.0",
+		LegacySentinels: true,
+	})
+	if err != nil {
+		t.Fatalf("WriteJSONOptions failed: %v", err)
+	}
+
+	var parsed map[string]json.RawMessage
+	if err := json.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if _, ok := parsed["sentinels"]; ok {
+		t.Error("legacy output should not contain a sentinels key")
+	}
+	var rpt JSONReport
+	if err := json.Unmarshal(buf.Bytes(), &rpt); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	last := rpt.Results[len(rpt.Results)-1]
+	if last.Target.Function != "<package>" || len(last.SideEffects) != 2 {
+		t.Errorf("legacy output should keep the <package> result, got %+v", last.Target)
+	}
+}
+
+func TestStreamJSONOptions_MatchesWriteJSONOptions(t *testing.T) {
+	for _, legacy := range []bool{false, true} {
+		for _, locations := range []LocationFormat{LocationString, LocationStructured} {
+			opts := JSONOptions{Version: "0.1.0", LegacySentinels: legacy, Locations: locations}
+			var want, got bytes.Buffer
+			if err := WriteJSONOptions(&want, resultsWithSentinels(), opts); err != nil {
+				t.Fatalf("WriteJSONOptions failed: %v", err)
+			}
+			if err := StreamJSONOptions(&got, sendResults(resultsWithSentinels()), opts); err != nil {
+				t.Fatalf("StreamJSONOptions failed: %v", err)
+			}
+			if got.String() != want.String() {
+				t.Errorf("legacy=%v locations=%s: stream output differs:\ngot:\n%s\nwant:\n%s",
+					legacy, locations, got.String(), want.String())
+			}
+		}
+	}
+}
+
+func TestWriteJSONOptions_Compact(t *testing.T) {
+	results := resultsWithSentinels()
+	var buf bytes.Buffer
+	if err := WriteJSONOptions(&buf, results, JSONOptions{Version: "0.1.0", Compact: true}); err != nil {
+		t.Fatalf("WriteJSONOptions failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(results) {
+		t.Fatalf("got %d lines, want one per result (%d):\n%s", len(lines), len(results), buf.String())
+	}
+	// Sentinels keep their <package> result, which sorts last here.
+	for i, line := range lines {
+		var r taxonomy.AnalysisResult
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("line %d is not a JSON result: %v\n%s", i, err, line)
+		}
+		if r.Target != results[i].Target {
+			t.Errorf("line %d target = %+v, want %+v", i, r.Target, results[i].Target)
+		}
+	}
+
+	for _, locations := range []LocationFormat{LocationString, LocationStructured} {
+		opts := JSONOptions{Version: "0.1.0", Compact: true, Locations: locations}
+		var want, got bytes.Buffer
+		if err := WriteJSONOptions(&want, results, opts); err != nil {
+			t.Fatalf("WriteJSONOptions failed: %v", err)
+		}
+		if err := StreamJSONOptions(&got, sendResults(results), opts); err != nil {
+			t.Fatalf("StreamJSONOptions failed: %v", err)
+		}
+		if got.String() != want.String() {
+			t.Errorf("locations=%s: compact stream output differs:\ngot:\n%s\nwant:\n%s",
+				locations, got.String(), want.String())
+		}
+	}
+
+	var empty bytes.Buffer
+	if err := WriteJSONOptions(&empty, nil, JSONOptions{Compact: true}); err != nil || empty.Len() != 0 {
+		t.Errorf("expected no output for no results, got %q (err %v)", empty.String(), err)
+	}
+}
+
+func TestWriteJSONOptions_StructuredLocations(t *testing.T) {
+	var plain, structured bytes.Buffer
+	if err := WriteJSONOptions(&plain, resultsWithSentinels(), JSONOptions{Version: "0.1.0"}); err != nil {
+		t.Fatal(err)
+	}
+	opts := JSONOptions{Version: "0.1.0", Locations: LocationStructured}
+	if err := WriteJSONOptions(&structured, resultsWithSentinels(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	var rpt struct {
+		Results []struct {
+			Target struct {
+				Location Position `json:"location"`
+			} `json:"target"`
+			SideEffects []struct {
+				Location    Position  `json:"location"`
+				EndLocation *Position `json:"end_location"`
+			} `json:"side_effects"`
+		} `json:"results"`
+		Sentinels []struct {
+			Location Position `json:"location"`
+		} `json:"sentinels"`
+	}
+	if err := json.Unmarshal(structured.Bytes(), &rpt); err != nil {
+		t.Fatalf("structured output does not decode: %v\n%s", err, structured.String())
+	}
+	if got, want := rpt.Results[0].Target.Location, (Position{File: "store.go", Line: 42, Col: 1}); got != want {
+		t.Errorf("target location = %+v, want %+v", got, want)
+	}
+	if got, want := rpt.Results[0].SideEffects[2].EndLocation, (Position{File: "store.go", Line: 55, Col: 28}); got == nil || *got != want {
+		t.Errorf("end location = %+v, want %+v", got, want)
+	}
+	if rpt.Results[0].SideEffects[0].EndLocation != nil {
+		t.Error("end_location should stay omitted when empty")
+	}
+	if got, want := rpt.Sentinels[0].Location, (Position{File: "errors.go", Line: 5, Col: 5}); got != want {
+		t.Errorf("sentinel location = %+v, want %+v", got, want)
+	}
+
+	// Apart from the positions, the documents are identical, key
+	// order included.
+	locRe := regexp.MustCompile(`"(end_)?location": ("[^"]*"|\{[^}]*\})`)
+	if a, b := locRe.ReplaceAllString(plain.String(), "LOC"), locRe.ReplaceAllString(structured.String(), "LOC"); a != b {
+		t.Errorf("structured output differs beyond locations:\nstring:\n%s\nstructured:\n%s", a, b)
+	}
+}
+
+func TestWriteJSONOptions_StructuredLocations_ValidAgainstSchema(t *testing.T) {
+	sch, err := jsonschema.UnmarshalJSON(strings.NewReader(Schema))
+	if err != nil {
+		t.Fatalf("failed to parse schema JSON: %v", err)
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("schema.json", sch); err != nil {
+		t.Fatalf("failed to add schema resource: %v", err)
+	}
+	compiled, err := compiler.Compile("schema.json")
+	if err != nil {
+		t.Fatalf("failed to compile schema: %v", err)
+	}
+
+	for _, legacy := range []bool{false, true} {
+		var buf bytes.Buffer
+		opts := JSONOptions{Version: "0.1.0", LegacySentinels: legacy, Locations: LocationStructured}
+		if err := WriteJSONOptions(&buf, resultsWithSentinels(), opts); err != nil {
+			t.Fatal(err)
+		}
+		inst, err := jsonschema.UnmarshalJSON(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatalf("failed to parse JSON output: %v", err)
+		}
+		if err := compiled.Validate(inst); err != nil {
+			t.Errorf("legacy=%v: structured output does not conform to schema:\n%v", legacy, err)
+		}
+	}
+}
+
+func TestWriteJSON_GroupedSentinels_ValidAgainstSchema(t *testing.T) {
+	sch, err := jsonschema.UnmarshalJSON(strings.NewReader(Schema))
+	if err != nil {
+		t.Fatalf("failed to parse schema JSON: %v", err)
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("schema.json", sch); err != nil {
+		t.Fatalf("failed to add schema resource: %v", err)
+	}
+	compiled, err := compiler.Compile("schema.json")
+	if err != nil {
+		t.Fatalf("failed to compile schema: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, resultsWithSentinels(), "0.1.0"); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	inst, err := jsonschema.UnmarshalJSON(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("failed to parse JSON output: %v", err)
+	}
+	if err := compiled.Validate(inst); err != nil {
+		t.Errorf("grouped JSON output does not conform to schema:\n%v", err)
+	}
+}
+
+func TestReadJSON_RoundTrip(t *testing.T) {
+	for _, opts := range []JSONOptions{
+		{},
+		{Locations: LocationStructured},
+		{LegacySentinels: true},
+	} {
+		var buf bytes.Buffer
+		if err := WriteJSONOptions(&buf, resultsWithSentinels(), opts); err != nil {
+			t.Fatalf("WriteJSONOptions(%+v): %v", opts, err)
+		}
+		results, err := ReadJSON(&buf)
+		if err != nil {
+			t.Fatalf("ReadJSON(%+v): %v", opts, err)
+		}
+		if got := results[0].SideEffects[2].EndLocation; got != "store.go:55:28" {
+			t.Errorf("%+v: end location = %q, want store.go:55:28", opts, got)
+		}
+		if d := DiffResults(resultsWithSentinels(), results, MatchID); !d.Empty() {
+			t.Errorf("%+v: round trip differs: %+v", opts, d)
+		}
+	}
+
+	if _, err := ReadJSON(strings.NewReader("not json")); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestDiffResults(t *testing.T) {
+	old := sampleResults()
+	current := sampleResults()
+	// Shift every effect down a line: no change in either mode.
+	for i := range current[0].SideEffects {
+		current[0].SideEffects[i].Location = "store.go:99:1"
+	}
+	// A renamed field: the ID changes along with the target.
+	current[0].SideEffects[2].ID = "se-99999999"
+	current[0].SideEffects[2].Target = "lastWritten"
+	current[0].SideEffects[2].Description = "mutates receiver field 'lastWritten'"
+	// A reworded description on a matched effect.
+	current[0].SideEffects[0].Description = "returns int64 (row count) at position 0"
+	// A new effect.
+	current[0].SideEffects = append(current[0].SideEffects, taxonomy.SideEffect{
+		ID:          "se-12121212",
+		Type:        taxonomy.LogWrite,
+		Tier:        taxonomy.TierP2,
+		Location:    "store.go:60:2",
+		Description: "writes to log",
+		Target:      "log.Printf",
+	})
+
+	for _, match := range []DiffMatch{MatchID, MatchTarget} {
+		d := DiffResults(old, current, match)
+		if len(d.Added) != 2 || d.Added[0].New.Target != "lastWritten" || d.Added[1].New.Type != taxonomy.LogWrite {
+			t.Errorf("%s: added = %+v", match, d.Added)
+		}
+		if len(d.Removed) != 1 || d.Removed[0].Old.Target != "lastSaved" || d.Removed[0].Function != "(*Store).Save" {
+			t.Errorf("%s: removed = %+v", match, d.Removed)
+		}
+		if len(d.Changed) != 1 || d.Changed[0].Old.Type != taxonomy.ReturnValue {
+			t.Errorf("%s: changed = %+v", match, d.Changed)
+		}
+	}
+
+	// An ID that moved with its line matches only by target.
+	current = sampleResults()
+	current[0].SideEffects[1].ID = "se-77777777"
+	if d := DiffResults(old, current, MatchID); len(d.Added) != 1 || len(d.Removed) != 1 {
+		t.Errorf("id: expected one removal and one addition, got %+v", d)
+	}
+	if d := DiffResults(old, current, MatchTarget); !d.Empty() {
+		t.Errorf("target: expected no differences, got %+v", d)
+	}
+}
+
+func TestWriteDiffText(t *testing.T) {
+	current := sampleResults()
+	current[0].SideEffects[0].Description = "returns int64 (row count) at position 0"
+	current[0].SideEffects = current[0].SideEffects[:2]
+
+	var buf bytes.Buffer
+	if err := WriteDiffText(&buf, DiffResults(sampleResults(), current, MatchID)); err != nil {
+		t.Fatalf("WriteDiffText: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"=== example.com/store.(*Store).Save ===",
+		"  - ReceiverMutation: mutates receiver field 'lastSaved'",
+		"  ~ ReturnValue: returns int64 (row count) at position 0",
+		`      description: "returns int64 at position 0" -> "returns int64 (row count) at position 0"`,
+		"0 added, 1 removed, 1 changed side effect(s) in 1 function(s)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestParseDiffMatch(t *testing.T) {
+	for _, s := range []string{"id", "target"} {
+		if m, err := ParseDiffMatch(s); err != nil || string(m) != s {
+			t.Errorf("ParseDiffMatch(%q) = %q, %v", s, m, err)
+		}
+	}
+	if _, err := ParseDiffMatch("location"); err == nil {
+		t.Error("expected error for unknown match mode")
+	}
+}
+
+func TestWriteYAML_MatchesJSON(t *testing.T) {
+	results := append(sampleClassifiedResults(), resultsWithSentinels()...)
+	var jsonBuf, yamlBuf bytes.Buffer
+	if err := WriteJSON(&jsonBuf, results, "0.1.0"); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	if err := WriteYAML(&yamlBuf, results, "0.1.0"); err != nil {
+		t.Fatalf("WriteYAML failed: %v", err)
+	}
+
+	// The YAML document decodes to the same values as the JSON one,
+	// under the same field names.
+	var fromYAML any
+	if err := yaml.Unmarshal(yamlBuf.Bytes(), &fromYAML); err != nil {
+		t.Fatalf("invalid YAML: %v\n%s", err, yamlBuf.String())
+	}
+	roundTrip, err := json.Marshal(fromYAML)
+	if err != nil {
+		t.Fatalf("re-encoding YAML as JSON: %v", err)
+	}
+	var got, want any
+	if err := json.Unmarshal(roundTrip, &got); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(jsonBuf.Bytes(), &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("YAML output differs from JSON output:\n%s", yamlBuf.String())
+	}
+
+	// Keys keep the JSON field order, in block style.
+	out := yamlBuf.String()
+	if !strings.HasPrefix(out, "version: 0.1.0\nresults:\n  - target:\n") {
+		t.Errorf("unexpected YAML layout:\n%s", out)
+	}
+	if strings.Index(out, "\nresults:") > strings.Index(out, "\nsentinels:") {
+		t.Errorf("expected results before sentinels:\n%s", out)
+	}
+
+	var again bytes.Buffer
+	if err := WriteYAML(&again, results, "0.1.0"); err != nil {
+		t.Fatal(err)
+	}
+	if again.String() != out {
+		t.Error("WriteYAML output is not stable across calls")
+	}
+}
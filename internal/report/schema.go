@@ -18,9 +18,30 @@ const Schema = `{
     "results": {
       "type": "array",
       "items": { "$ref": "#/$defs/AnalysisResult" }
+    },
+    "sentinels": {
+      "type": "array",
+      "items": { "$ref": "#/$defs/Sentinel" },
+      "description": "Package-level sentinel errors. Absent when --legacy-sentinels is used."
     }
   },
   "$defs": {
+    "Location": {
+      "description": "A source position: a 'file:line:col' string by default, or an object with --locations=structured",
+      "oneOf": [
+        { "type": "string" },
+        {
+          "type": "object",
+          "required": ["file"],
+          "properties": {
+            "file": { "type": "string" },
+            "line": { "type": "integer", "minimum": 1 },
+            "col": { "type": "integer", "minimum": 1 }
+          },
+          "additionalProperties": false
+        }
+      ]
+    },
     "AnalysisResult": {
       "type": "object",
       "required": ["target", "side_effects", "metadata"],
@@ -30,6 +51,11 @@ const Schema = `{
           "type": "array",
           "items": { "$ref": "#/$defs/SideEffect" }
         },
+        "suppressed": {
+          "type": "integer",
+          "minimum": 0,
+          "description": "Number of side effects suppressed by a //gaze:ignore directive; omitted when zero"
+        },
         "metadata": { "$ref": "#/$defs/Metadata" }
       }
     },
@@ -43,7 +69,7 @@ const Schema = `{
         },
         "function": {
           "type": "string",
-          "description": "Function or method name. The value '<package>' indicates package-level declarations (e.g., sentinel errors) not associated with a specific function."
+          "description": "Function or method name. The value '<package>' indicates package-level declarations (e.g., sentinel errors) not associated with a specific function; it appears only when --legacy-sentinels is used."
         },
         "receiver": {
           "type": "string",
@@ -54,8 +80,47 @@ const Schema = `{
           "description": "Full function signature"
         },
         "location": {
+          "$ref": "#/$defs/Location",
+          "description": "Source position of the function declaration"
+        },
+        "test": {
+          "type": "boolean",
+          "description": "Whether the function is declared in a _test.go file (only with --include-tests)"
+        }
+      }
+    },
+    "Sentinel": {
+      "type": "object",
+      "required": ["id", "package", "name", "location", "wrapped"],
+      "properties": {
+        "id": {
+          "type": "string",
+          "description": "Stable identifier (se-XXXXXXXX)"
+        },
+        "package": {
+          "type": "string",
+          "description": "Full import path of the declaring package"
+        },
+        "name": {
           "type": "string",
-          "description": "Source position (file:line:col)"
+          "description": "Sentinel variable name (e.g., 'ErrNotFound')"
+        },
+        "location": {
+          "$ref": "#/$defs/Location",
+          "description": "Source position of the declaration"
+        },
+        "wrapped": {
+          "type": "boolean",
+          "description": "Whether the sentinel wraps another error via %w"
+        },
+        "classification": {
+          "$ref": "#/$defs/Classification",
+          "description": "Contractual classification (only present when --classify is used)"
+        },
+        "warnings": {
+          "type": "array",
+          "items": { "type": "string" },
+          "description": "Analysis warnings naming this sentinel, e.g. another sentinel sharing its message (omitted when there are none)"
         }
       }
     },
@@ -79,10 +144,13 @@ const Schema = `{
             "FileSystemWrite", "FileSystemDelete", "FileSystemMeta",
             "DatabaseWrite", "DatabaseTransaction",
             "GoroutineSpawn", "Panic", "CallbackInvocation",
-            "LogWrite", "ContextCancellation",
+            "LogWrite", "ContextCancellation", "ContextValue",
+            "MethodValueEscape", "LocalPointerEscape",
+            "InternalPointerEscape",
             "StdoutWrite", "StderrWrite", "EnvVarMutation",
             "MutexOp", "WaitGroupOp", "AtomicOp",
             "TimeDependency", "ProcessExit", "RecoverBehavior",
+            "DeferredCleanup",
             "ReflectionMutation", "UnsafeMutation", "CgoCall",
             "FinalizerRegistration", "SyncPoolOp",
             "ClosureCaptureMutation"
@@ -94,9 +162,13 @@ const Schema = `{
           "description": "Priority tier"
         },
         "location": {
-          "type": "string",
+          "$ref": "#/$defs/Location",
           "description": "Source position"
         },
+        "end_location": {
+          "$ref": "#/$defs/Location",
+          "description": "Source position just past the end of the statement or expression, when known"
+        },
         "description": {
           "type": "string",
           "description": "Human-readable explanation"
@@ -105,6 +177,10 @@ const Schema = `{
           "type": "string",
           "description": "Affected entity (field, variable, type, etc.)"
         },
+        "target_type": {
+          "type": "string",
+          "description": "Go type of the mutated field, variable, or channel element; omitted when unknown"
+        },
         "classification": {
           "$ref": "#/$defs/Classification",
           "description": "Contractual classification (only present when --classify is used)"
@@ -311,6 +387,11 @@ const QualitySchema = `{
             { "type": "null" }
           ],
           "description": "Go code snippets suggesting how to assert on each discarded return. Parallel to discarded_returns: len(discarded_return_hints) == len(discarded_returns). Omitted when there are no discarded returns."
+        },
+        "asserted_ambiguous": {
+          "type": "array",
+          "items": { "type": "string" },
+          "description": "IDs of ambiguous effects asserted on anyway. Excluded from percentage. Omitted when empty."
         }
       }
     },
@@ -0,0 +1,159 @@
+package report
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// SortOrder selects the order side effects are reported in.
+type SortOrder string
+
+const (
+	// SortLocation orders effects, and functions, by source
+	// position.
+	SortLocation SortOrder = "location"
+
+	// SortTier puts the highest tier (P0) first.
+	SortTier SortOrder = "tier"
+
+	// SortType groups effects by side effect type, alphabetically.
+	SortType SortOrder = "type"
+
+	// SortConfidence puts the highest classification confidence
+	// first. Unclassified effects sort last.
+	SortConfidence SortOrder = "confidence"
+)
+
+// ParseSortOrder validates a --sort flag value.
+func ParseSortOrder(s string) (SortOrder, error) {
+	switch o := SortOrder(s); o {
+	case SortLocation, SortTier, SortType, SortConfidence:
+		return o, nil
+	}
+	return "", fmt.Errorf("invalid sort order %q: must be 'location', 'tier', 'type', or 'confidence'", s)
+}
+
+// SortResults returns results reordered by order. The side effects
+// of each function are sorted, and functions are ordered by their
+// first effect after sorting, so with SortTier every function with
+// a P0 effect comes before the rest; functions without effects come
+// last. All ties break on source location, so the output is
+// deterministic. The empty order returns results unchanged; results
+// and their SideEffects slices are never modified in place.
+func SortResults(results []taxonomy.AnalysisResult, order SortOrder) []taxonomy.AnalysisResult {
+	if order == "" {
+		return results
+	}
+	sorted := make([]taxonomy.AnalysisResult, len(results))
+	for i, r := range results {
+		r.SideEffects = sortEffects(r.SideEffects, order)
+		sorted[i] = r
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if order != SortLocation {
+			if len(a.SideEffects) == 0 || len(b.SideEffects) == 0 {
+				if len(a.SideEffects) != len(b.SideEffects) {
+					return len(a.SideEffects) > 0
+				}
+			} else if c := compareEffects(a.SideEffects[0], b.SideEffects[0], order); c != 0 {
+				return c < 0
+			}
+		}
+		return compareLocations(a.Target.Location, b.Target.Location) < 0
+	})
+	return sorted
+}
+
+// sortEffects returns a sorted copy of effects.
+func sortEffects(effects []taxonomy.SideEffect, order SortOrder) []taxonomy.SideEffect {
+	if len(effects) == 0 {
+		return effects
+	}
+	sorted := append([]taxonomy.SideEffect(nil), effects...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return compareEffects(sorted[i], sorted[j], order) < 0
+	})
+	return sorted
+}
+
+// compareEffects compares two effects by order, then by location.
+func compareEffects(a, b taxonomy.SideEffect, order SortOrder) int {
+	var c int
+	switch order {
+	case SortTier:
+		c = strings.Compare(string(a.Tier), string(b.Tier))
+	case SortType:
+		c = strings.Compare(string(a.Type), string(b.Type))
+	case SortConfidence:
+		c = confidenceOf(b) - confidenceOf(a)
+	}
+	if c != 0 {
+		return c
+	}
+	return compareLocations(a.Location, b.Location)
+}
+
+// confidenceOf returns an effect's classification confidence, or -1
+// if it is unclassified.
+func confidenceOf(e taxonomy.SideEffect) int {
+	if e.Classification == nil {
+		return -1
+	}
+	return e.Classification.Confidence
+}
+
+// compareLocations compares "file:line:col" positions by file name,
+// then numerically by line and column. A bare file name, the
+// location of a file's sentinel errors, sorts after every position
+// in that file, matching where analysis reports them.
+func compareLocations(a, b string) int {
+	fa, la, ca := splitLocation(a)
+	fb, lb, cb := splitLocation(b)
+	if la == 0 {
+		la = math.MaxInt
+	}
+	if lb == 0 {
+		lb = math.MaxInt
+	}
+	if c := strings.Compare(fa, fb); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(la, lb); c != 0 {
+		return c
+	}
+	return cmp.Compare(ca, cb)
+}
+
+// splitLocation splits "file:line:col" (or "file:line") into its
+// parts. Missing or non-numeric parts are zero.
+func splitLocation(loc string) (file string, line, col int) {
+	file = loc
+	var nums []int
+	for len(nums) < 2 {
+		i := strings.LastIndexByte(file, ':')
+		if i < 0 {
+			break
+		}
+		n, err := strconv.Atoi(file[i+1:])
+		if err != nil {
+			break
+		}
+		nums = append(nums, n)
+		file = file[:i]
+	}
+	switch len(nums) {
+	case 2:
+		return file, nums[1], nums[0]
+	case 1:
+		return file, nums[0], 0
+	}
+	return file, 0, 0
+}
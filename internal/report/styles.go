@@ -2,11 +2,15 @@
 package report
 
 import (
+	"os"
+
 	"github.com/charmbracelet/lipgloss"
 )
 
 // Styles defines the visual theme for terminal report output.
 // Lipgloss automatically degrades to no-color when output is not a TTY.
+// Styles are bound to the renderer they were created with, which
+// decides the color profile.
 type Styles struct {
 	// Header is used for section headers (e.g. "=== FuncName ===").
 	Header lipgloss.Style
@@ -59,39 +63,51 @@ type Styles struct {
 
 	// ClassAmbiguous styles the "ambiguous" classification label.
 	ClassAmbiguous lipgloss.Style
+
+	// Terminal calls out effects that end the process or unwind the
+	// stack (ProcessExit, Panic).
+	Terminal lipgloss.Style
 }
 
-// DefaultStyles returns the default color scheme for terminal reports.
+// DefaultStyles returns the default color scheme for terminal reports
+// written to stdout, colored as ColorAuto decides.
 func DefaultStyles() Styles {
+	return NewStyles(ColorAuto.Renderer(os.Stdout))
+}
+
+// NewStyles returns the default color scheme bound to renderer r.
+func NewStyles(r *lipgloss.Renderer) Styles {
 	return Styles{
-		Header:    lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("63")),
-		SubHeader: lipgloss.NewStyle().Foreground(lipgloss.Color("241")),
+		Header:    r.NewStyle().Bold(true).Foreground(lipgloss.Color("63")),
+		SubHeader: r.NewStyle().Foreground(lipgloss.Color("241")),
+
+		TierP0: r.NewStyle().Foreground(lipgloss.Color("196")),
+		TierP1: r.NewStyle().Foreground(lipgloss.Color("208")),
+		TierP2: r.NewStyle().Foreground(lipgloss.Color("220")),
+		TierP3: r.NewStyle().Foreground(lipgloss.Color("75")),
+		TierP4: r.NewStyle().Foreground(lipgloss.Color("245")),
 
-		TierP0: lipgloss.NewStyle().Foreground(lipgloss.Color("196")),
-		TierP1: lipgloss.NewStyle().Foreground(lipgloss.Color("208")),
-		TierP2: lipgloss.NewStyle().Foreground(lipgloss.Color("220")),
-		TierP3: lipgloss.NewStyle().Foreground(lipgloss.Color("75")),
-		TierP4: lipgloss.NewStyle().Foreground(lipgloss.Color("245")),
+		TableHeader: r.NewStyle().Bold(true).Foreground(lipgloss.Color("63")),
+		TableCell:   r.NewStyle().PaddingRight(1),
 
-		TableHeader: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("63")),
-		TableCell:   lipgloss.NewStyle().PaddingRight(1),
+		CRAPBad:  r.NewStyle().Foreground(lipgloss.Color("196")).Bold(true),
+		CRAPGood: r.NewStyle().Foreground(lipgloss.Color("40")),
 
-		CRAPBad:  lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true),
-		CRAPGood: lipgloss.NewStyle().Foreground(lipgloss.Color("40")),
+		SummaryLabel: r.NewStyle().Bold(true).Width(20),
+		SummaryValue: r.NewStyle(),
 
-		SummaryLabel: lipgloss.NewStyle().Bold(true).Width(20),
-		SummaryValue: lipgloss.NewStyle(),
+		Pass: r.NewStyle().Foreground(lipgloss.Color("40")).Bold(true),
+		Fail: r.NewStyle().Foreground(lipgloss.Color("196")).Bold(true),
 
-		Pass: lipgloss.NewStyle().Foreground(lipgloss.Color("40")).Bold(true),
-		Fail: lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true),
+		Border: r.NewStyle().Foreground(lipgloss.Color("63")),
 
-		Border: lipgloss.NewStyle().Foreground(lipgloss.Color("63")),
+		Muted: r.NewStyle().Foreground(lipgloss.Color("241")),
 
-		Muted: lipgloss.NewStyle().Foreground(lipgloss.Color("241")),
+		ClassContractual: r.NewStyle().Foreground(lipgloss.Color("40")).Bold(true),
+		ClassIncidental:  r.NewStyle().Foreground(lipgloss.Color("241")),
+		ClassAmbiguous:   r.NewStyle().Foreground(lipgloss.Color("220")),
 
-		ClassContractual: lipgloss.NewStyle().Foreground(lipgloss.Color("40")).Bold(true),
-		ClassIncidental:  lipgloss.NewStyle().Foreground(lipgloss.Color("241")),
-		ClassAmbiguous:   lipgloss.NewStyle().Foreground(lipgloss.Color("220")),
+		Terminal: r.NewStyle().Foreground(lipgloss.Color("196")).Bold(true),
 	}
 }
 
@@ -0,0 +1,145 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// WriteSummary writes a one-table digest of analysis results: one
+// row per function with its side effect count and highest tier,
+// without the per-effect listing of WriteText.
+func WriteSummary(w io.Writer, results []taxonomy.AnalysisResult) error {
+	return WriteSummaryOptions(w, results, TextOptions{})
+}
+
+// WriteSummaryOptions writes the digest with configurable options.
+// Quiet omits functions with no side effects, Color selects the
+// color mode, and Sort orders the rows; Classify and Verbose are
+// ignored.
+func WriteSummaryOptions(w io.Writer, results []taxonomy.AnalysisResult, opts TextOptions) error {
+	s := NewStyles(opts.Color.Renderer(w))
+	results = SortResults(results, opts.Sort)
+
+	var rows [][]string
+	total, suppressed, hidden := 0, 0, 0
+	for _, r := range results {
+		total += len(r.SideEffects)
+		suppressed += r.Suppressed
+		if opts.Quiet && len(r.SideEffects) == 0 {
+			hidden++
+			continue
+		}
+		rows = append(rows, []string{
+			r.Target.Package,
+			displayName(r.Target),
+			strconv.Itoa(len(r.SideEffects)),
+			string(highestTier(r.SideEffects)),
+		})
+	}
+
+	if len(rows) > 0 {
+		t := table.New().
+			Border(lipgloss.NormalBorder()).
+			BorderStyle(s.Border).
+			StyleFunc(func(row, col int) lipgloss.Style {
+				if row == table.HeaderRow {
+					return s.TableHeader
+				}
+				if col == 3 && row >= 0 && row < len(rows) {
+					return s.TableCell.Inherit(s.TierStyle(rows[row][3]))
+				}
+				return s.TableCell
+			}).
+			Headers("PACKAGE", "FUNCTION", "EFFECTS", "HIGHEST TIER").
+			Rows(rows...)
+		_, _ = fmt.Fprintln(w, t)
+	}
+
+	summary := fmt.Sprintf("%d function(s) analyzed, %d side effect(s) detected",
+		len(results), total)
+	if suppressed > 0 {
+		summary += fmt.Sprintf(", %d suppressed by //gaze:ignore", suppressed)
+	}
+	if hidden > 0 {
+		summary += fmt.Sprintf(" (%d without side effects not shown)", hidden)
+	}
+	_, _ = fmt.Fprintf(w, "\n%s\n", s.Header.Render(summary))
+	return nil
+}
+
+// JSONSummary is the count-only JSON digest of analysis results
+// written by WriteJSONSummary.
+type JSONSummary struct {
+	// Functions is the number of functions analyzed.
+	Functions int `json:"functions"`
+
+	// EffectsByTier counts side effects per tier. Every tier is
+	// present, with a zero count when it has no effects.
+	EffectsByTier map[taxonomy.Tier]int `json:"effects_by_tier"`
+
+	// EffectsByType counts side effects per type. Every taxonomy
+	// type is present, with a zero count when it has no effects;
+	// types from registered detectors are added as they occur.
+	EffectsByType map[taxonomy.SideEffectType]int `json:"effects_by_type"`
+}
+
+// summarize counts functions and side effects in results. Sentinel
+// errors are counted as effects; the synthetic "<package>" result
+// that carries them is not counted as a function.
+func summarize(results []taxonomy.AnalysisResult) JSONSummary {
+	sum := JSONSummary{
+		EffectsByTier: make(map[taxonomy.Tier]int),
+		EffectsByType: make(map[taxonomy.SideEffectType]int),
+	}
+	for _, tier := range taxonomy.Tiers() {
+		sum.EffectsByTier[tier] = 0
+	}
+	for _, t := range taxonomy.Types() {
+		sum.EffectsByType[t] = 0
+	}
+	for _, r := range results {
+		if !isSentinelResult(r) {
+			sum.Functions++
+		}
+		for _, e := range r.SideEffects {
+			sum.EffectsByTier[e.Tier]++
+			sum.EffectsByType[e.Type]++
+		}
+	}
+	return sum
+}
+
+// WriteJSONSummary writes the function count and the number of side
+// effects per tier and per type as a small JSON object, for
+// dashboards that track totals over time without the full results.
+// The keys are the same on every run; see JSONSummary.
+func WriteJSONSummary(w io.Writer, results []taxonomy.AnalysisResult) error {
+	data, err := json.MarshalIndent(summarize(results), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// highestTier returns the most severe tier among effects (P0 is the
+// highest), or "-" when there are none.
+func highestTier(effects []taxonomy.SideEffect) taxonomy.Tier {
+	if len(effects) == 0 {
+		return "-"
+	}
+	highest := effects[0].Tier
+	for _, e := range effects[1:] {
+		// Tiers are "P0".."P4", so string order is severity order.
+		if e.Tier < highest {
+			highest = e.Tier
+		}
+	}
+	return highest
+}
@@ -0,0 +1,25 @@
+// Package alpha is one of two fixture packages for multi-package
+// JSON output.
+package alpha
+
+import "errors"
+
+// ErrEmpty is returned by First for an empty slice.
+var ErrEmpty = errors.New("alpha: empty")
+
+// Total counts calls to First.
+var Total int
+
+// First returns the first element of s.
+func First(s []int) (int, error) {
+	Total++
+	if len(s) == 0 {
+		return 0, ErrEmpty
+	}
+	return s[0], nil
+}
+
+// Double returns twice n.
+func Double(n int) int {
+	return n * 2
+}
@@ -0,0 +1,18 @@
+// Package beta is one of two fixture packages for multi-package
+// JSON output.
+package beta
+
+// Counter counts things.
+type Counter struct {
+	n int
+}
+
+// Inc increments the counter.
+func (c *Counter) Inc() {
+	c.n++
+}
+
+// Value returns the current count.
+func (c *Counter) Value() int {
+	return c.n
+}
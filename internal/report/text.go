@@ -21,48 +21,90 @@ type TextOptions struct {
 	// Verbose causes the full signal breakdown to be printed
 	// beneath each function's table (implies Classify).
 	Verbose bool
+
+	// Quiet omits functions with no side effects. They are still
+	// counted in the summary line.
+	Quiet bool
+
+	// Color selects when ANSI colors are used. The zero value
+	// behaves like ColorAuto.
+	Color ColorMode
+
+	// Sort reorders functions and their side effects; see
+	// SortResults. The zero value keeps the order results are in.
+	Sort SortOrder
+
+	// GroupBy organizes the report by side effect type or tier
+	// instead of by function. The zero value, like GroupFunction,
+	// lists each function in turn.
+	GroupBy GroupBy
 }
 
 // WriteText writes analysis results as human-readable styled text
 // to the writer. Output uses lipgloss for color and formatting when
-// the output is a TTY; degrades gracefully for pipes and CI.
+// the writer is a TTY; degrades gracefully for pipes and CI.
 func WriteText(w io.Writer, results []taxonomy.AnalysisResult) error {
 	return WriteTextOptions(w, results, TextOptions{})
 }
 
 // WriteTextOptions writes analysis results with configurable options.
 func WriteTextOptions(w io.Writer, results []taxonomy.AnalysisResult, opts TextOptions) error {
-	s := DefaultStyles()
+	s := NewStyles(opts.Color.Renderer(w))
+	if opts.GroupBy == GroupType || opts.GroupBy == GroupTier {
+		return writeGroupedText(w, results, s, opts)
+	}
+	results = SortResults(results, opts.Sort)
 
-	for i, result := range results {
-		if i > 0 {
+	written, hidden := 0, 0
+	for _, result := range results {
+		if opts.Quiet && len(result.SideEffects) == 0 {
+			hidden++
+			continue
+		}
+		if written > 0 {
 			_, _ = fmt.Fprintln(w)
 		}
 		if err := writeOneResultOpts(w, result, s, opts); err != nil {
 			return err
 		}
+		written++
 	}
 
 	// Summary line.
-	total := 0
+	total, suppressed := 0, 0
 	for _, r := range results {
 		total += len(r.SideEffects)
+		suppressed += r.Suppressed
 	}
-	_, _ = fmt.Fprintf(w, "\n%s\n",
-		s.Header.Render(fmt.Sprintf(
-			"%d function(s) analyzed, %d side effect(s) detected",
-			len(results), total)))
+	summary := fmt.Sprintf("%d function(s) analyzed, %d side effect(s) detected",
+		len(results), total)
+	if suppressed > 0 {
+		summary += fmt.Sprintf(", %d suppressed by //gaze:ignore", suppressed)
+	}
+	if hidden > 0 {
+		summary += fmt.Sprintf(" (%d without side effects not shown)", hidden)
+	}
+	_, _ = fmt.Fprintf(w, "\n%s\n", s.Header.Render(summary))
 
 	return nil
 }
 
+// displayName is the function name shown in text output, marking
+// functions declared in _test.go files.
+func displayName(t taxonomy.FunctionTarget) string {
+	if t.Test {
+		return t.QualifiedName() + " (test)"
+	}
+	return t.QualifiedName()
+}
+
 func writeOneResultOpts(w io.Writer, result taxonomy.AnalysisResult, s Styles, opts TextOptions) error {
 	return writeOneResult(w, result, s, opts.Classify || opts.Verbose, opts.Verbose)
 }
 
 func writeOneResult(w io.Writer, result taxonomy.AnalysisResult, s Styles, showClassify, verbose bool) error {
 	// Header.
-	name := result.Target.QualifiedName()
+	name := displayName(result.Target)
 	_, _ = fmt.Fprintln(w, s.Header.Render(fmt.Sprintf("=== %s ===", name)))
 	_, _ = fmt.Fprintln(w, s.SubHeader.Render(fmt.Sprintf("    %s", result.Target.Signature)))
 	_, _ = fmt.Fprintln(w, s.SubHeader.Render(fmt.Sprintf("    %s", result.Target.Location)))
@@ -113,6 +155,9 @@ func writeOneResult(w io.Writer, result taxonomy.AnalysisResult, s Styles, showC
 				if col == 0 && row >= 0 && row < len(rows) {
 					return s.TierStyle(rows[row][0])
 				}
+				if col == 1 && row >= 0 && row < len(rows) {
+					return s.effectTypeStyle(result.SideEffects[row])
+				}
 				if col == 3 && row >= 0 && row < len(rows) {
 					label := ""
 					if result.SideEffects[row].Classification != nil {
@@ -181,6 +226,9 @@ func writeOneResult(w io.Writer, result taxonomy.AnalysisResult, s Styles, showC
 				if col == 0 && row >= 0 && row < len(rows) {
 					return s.TierStyle(rows[row][0])
 				}
+				if col == 1 && row >= 0 && row < len(rows) {
+					return s.effectTypeStyle(result.SideEffects[row])
+				}
 				return s.TableCell
 			}).
 			Headers("TIER", "TYPE", "DESCRIPTION").
@@ -189,6 +237,8 @@ func writeOneResult(w io.Writer, result taxonomy.AnalysisResult, s Styles, showC
 		_, _ = fmt.Fprintln(w, t)
 	}
 
+	writeTerminatingEffects(w, result.SideEffects, s)
+
 	// Tier summary.
 	tierCounts := make(map[taxonomy.Tier]int)
 	for _, e := range result.SideEffects {
@@ -210,3 +260,34 @@ func writeOneResult(w io.Writer, result taxonomy.AnalysisResult, s Styles, showC
 
 	return nil
 }
+
+// effectTypeStyle highlights the TYPE cell of P0 and P1 effects in
+// their tier color, and of terminating effects in the Terminal style.
+func (s Styles) effectTypeStyle(e taxonomy.SideEffect) lipgloss.Style {
+	switch {
+	case isTerminating(e.Type):
+		return s.TableCell.Inherit(s.Terminal)
+	case e.Tier == taxonomy.TierP0 || e.Tier == taxonomy.TierP1:
+		return s.TableCell.Inherit(s.TierStyle(string(e.Tier)))
+	default:
+		return s.TableCell
+	}
+}
+
+// isTerminating reports whether t ends the process or unwinds the
+// stack, which callers cannot observe through return values.
+func isTerminating(t taxonomy.SideEffectType) bool {
+	return t == taxonomy.ProcessExit || t == taxonomy.Panic
+}
+
+// writeTerminatingEffects calls out ProcessExit and Panic effects
+// beneath the effects table with their full description, which the
+// table may have truncated.
+func writeTerminatingEffects(w io.Writer, effects []taxonomy.SideEffect, s Styles) {
+	for _, e := range effects {
+		if isTerminating(e.Type) {
+			_, _ = fmt.Fprintln(w, s.Terminal.Render(
+				fmt.Sprintf("    ! %s: %s (%s)", e.Type, e.Description, e.Location)))
+		}
+	}
+}
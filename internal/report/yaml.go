@@ -0,0 +1,57 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// WriteYAML writes analysis results as YAML to the writer. The
+// document has the same structure and field names as WriteJSON's,
+// so the JSON schema describes both. The version string defaults to
+// "dev" when empty.
+func WriteYAML(w io.Writer, results []taxonomy.AnalysisResult, version string) error {
+	if version == "" {
+		version = "dev"
+	}
+	funcs, sentinels := groupSentinels(groupByPackage(results))
+	return EncodeYAML(w, JSONReport{Version: version, Results: funcs, Sentinels: sentinels})
+}
+
+// EncodeYAML writes v to w as block-style YAML using v's JSON
+// encoding: keys are the JSON field names, in the order
+// encoding/json writes them (struct fields in declaration order,
+// map keys sorted), so output is stable across runs and diffs
+// cleanly.
+func EncodeYAML(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	// JSON is valid YAML; decoding into a node keeps key order.
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	blockStyle(&doc)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// blockStyle clears the flow and quoting styles the YAML parser
+// recorded from JSON syntax, so the encoder writes block mappings
+// and sequences and quotes only strings that need it.
+func blockStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
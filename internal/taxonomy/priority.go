@@ -1,6 +1,8 @@
 // Package taxonomy defines the side effect type system and domain types.
 package taxonomy
 
+import "sort"
+
 // TierOf returns the priority tier for a given side effect type.
 func TierOf(t SideEffectType) Tier {
 	tier, ok := tierMap[t]
@@ -10,6 +12,34 @@ func TierOf(t SideEffectType) Tier {
 	return tier
 }
 
+// IsKnownType reports whether t is one of the side effect types
+// defined by the taxonomy.
+func IsKnownType(t SideEffectType) bool {
+	_, ok := tierMap[t]
+	return ok
+}
+
+// Tiers returns the priority tiers from most to least severe.
+func Tiers() []Tier {
+	return []Tier{TierP0, TierP1, TierP2, TierP3, TierP4}
+}
+
+// Types returns every side effect type defined by the taxonomy,
+// ordered by tier and then by name.
+func Types() []SideEffectType {
+	types := make([]SideEffectType, 0, len(tierMap))
+	for t := range tierMap {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if tierMap[types[i]] != tierMap[types[j]] {
+			return tierMap[types[i]] < tierMap[types[j]]
+		}
+		return types[i] < types[j]
+	})
+	return types
+}
+
 var tierMap = map[SideEffectType]Tier{
 	// P0
 	ReturnValue:        TierP0,
@@ -29,16 +59,20 @@ var tierMap = map[SideEffectType]Tier{
 	DeferredReturnMutation: TierP1,
 
 	// P2
-	FileSystemWrite:     TierP2,
-	FileSystemDelete:    TierP2,
-	FileSystemMeta:      TierP2,
-	DatabaseWrite:       TierP2,
-	DatabaseTransaction: TierP2,
-	GoroutineSpawn:      TierP2,
-	Panic:               TierP2,
-	CallbackInvocation:  TierP2,
-	LogWrite:            TierP2,
-	ContextCancellation: TierP2,
+	FileSystemWrite:       TierP2,
+	FileSystemDelete:      TierP2,
+	FileSystemMeta:        TierP2,
+	DatabaseWrite:         TierP2,
+	DatabaseTransaction:   TierP2,
+	GoroutineSpawn:        TierP2,
+	Panic:                 TierP2,
+	CallbackInvocation:    TierP2,
+	LogWrite:              TierP2,
+	ContextCancellation:   TierP2,
+	ContextValue:          TierP2,
+	MethodValueEscape:     TierP2,
+	LocalPointerEscape:    TierP2,
+	InternalPointerEscape: TierP2,
 
 	// P3
 	StdoutWrite:     TierP3,
@@ -50,6 +84,7 @@ var tierMap = map[SideEffectType]Tier{
 	TimeDependency:  TierP3,
 	ProcessExit:     TierP3,
 	RecoverBehavior: TierP3,
+	DeferredCleanup: TierP3,
 
 	// P4
 	ReflectionMutation:     TierP4,
@@ -6,6 +6,7 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 )
 
@@ -35,16 +36,20 @@ const (
 
 // P2 — Important.
 const (
-	FileSystemWrite     SideEffectType = "FileSystemWrite"
-	FileSystemDelete    SideEffectType = "FileSystemDelete"
-	FileSystemMeta      SideEffectType = "FileSystemMeta"
-	DatabaseWrite       SideEffectType = "DatabaseWrite"
-	DatabaseTransaction SideEffectType = "DatabaseTransaction"
-	GoroutineSpawn      SideEffectType = "GoroutineSpawn"
-	Panic               SideEffectType = "Panic"
-	CallbackInvocation  SideEffectType = "CallbackInvocation"
-	LogWrite            SideEffectType = "LogWrite"
-	ContextCancellation SideEffectType = "ContextCancellation"
+	FileSystemWrite       SideEffectType = "FileSystemWrite"
+	FileSystemDelete      SideEffectType = "FileSystemDelete"
+	FileSystemMeta        SideEffectType = "FileSystemMeta"
+	DatabaseWrite         SideEffectType = "DatabaseWrite"
+	DatabaseTransaction   SideEffectType = "DatabaseTransaction"
+	GoroutineSpawn        SideEffectType = "GoroutineSpawn"
+	Panic                 SideEffectType = "Panic"
+	CallbackInvocation    SideEffectType = "CallbackInvocation"
+	LogWrite              SideEffectType = "LogWrite"
+	ContextCancellation   SideEffectType = "ContextCancellation"
+	ContextValue          SideEffectType = "ContextValue"
+	MethodValueEscape     SideEffectType = "MethodValueEscape"
+	LocalPointerEscape    SideEffectType = "LocalPointerEscape"
+	InternalPointerEscape SideEffectType = "InternalPointerEscape"
 )
 
 // P3 — Nice to Have.
@@ -58,6 +63,7 @@ const (
 	TimeDependency  SideEffectType = "TimeDependency"
 	ProcessExit     SideEffectType = "ProcessExit"
 	RecoverBehavior SideEffectType = "RecoverBehavior"
+	DeferredCleanup SideEffectType = "DeferredCleanup"
 )
 
 // P4 — Exotic.
@@ -97,7 +103,8 @@ const (
 // classification confidence score.
 type Signal struct {
 	// Source identifies the signal type (e.g., "interface",
-	// "caller", "naming", "godoc", "readme", "architecture_doc").
+	// "caller", "test_caller", "naming", "godoc", "readme",
+	// "architecture_doc", "override").
 	Source string `json:"source"`
 
 	// Weight is the numeric contribution to the confidence score.
@@ -151,6 +158,12 @@ type SideEffect struct {
 	// Location is the source position (file:line:col).
 	Location string `json:"location"`
 
+	// EndLocation is the source position just past the end of the
+	// statement or expression that produces the effect, so that
+	// together with Location it forms a range. Empty when no range
+	// is known.
+	EndLocation string `json:"end_location,omitempty"`
+
 	// Description is a human-readable explanation.
 	Description string `json:"description"`
 
@@ -158,6 +171,14 @@ type SideEffect struct {
 	// channel name, return type, etc.).
 	Target string `json:"target"`
 
+	// TargetType is the Go type of Target where it is a field,
+	// variable, or channel: the field's type for ReceiverMutation,
+	// the pointed-to type for PointerArgMutation, the variable's
+	// type for GlobalMutation, and the element type for ChannelSend
+	// and ChannelClose. Types declared in the analyzed package are
+	// unqualified. Empty when unknown.
+	TargetType string `json:"target_type,omitempty"`
+
 	// Classification is the contractual classification of this
 	// side effect. Nil when classification has not been performed.
 	Classification *Classification `json:"classification,omitempty"`
@@ -180,6 +201,10 @@ type FunctionTarget struct {
 
 	// Location is the source position of the function declaration.
 	Location string `json:"location"`
+
+	// Test is true for functions declared in _test.go files, which
+	// are only analyzed on request (gaze analyze --include-tests).
+	Test bool `json:"test,omitempty"`
 }
 
 // QualifiedName returns the fully qualified function name including
@@ -227,10 +252,47 @@ type AnalysisResult struct {
 	// SideEffects is the list of detected side effects.
 	SideEffects []SideEffect `json:"side_effects"`
 
+	// Suppressed is the number of detected side effects removed by
+	// a //gaze:ignore directive on the function.
+	Suppressed int `json:"suppressed,omitempty"`
+
 	// Metadata contains run information.
 	Metadata Metadata `json:"metadata"`
 }
 
+// SentinelWrapSuffix is appended to the description of a
+// SentinelError side effect whose initializer wraps another error
+// via fmt.Errorf's %w verb.
+const SentinelWrapSuffix = " (wraps via %w)"
+
+// Sentinel describes one package-level sentinel error in the grouped
+// "sentinels" section of the analysis JSON output.
+type Sentinel struct {
+	// ID is the stable side effect identifier of the sentinel.
+	ID string `json:"id"`
+
+	// Package is the full import path of the declaring package.
+	Package string `json:"package"`
+
+	// Name is the variable name (e.g., "ErrNotFound").
+	Name string `json:"name"`
+
+	// Location is the source position of the declaration.
+	Location string `json:"location"`
+
+	// Wrapped reports whether the sentinel wraps another error
+	// via %w.
+	Wrapped bool `json:"wrapped"`
+
+	// Classification is the contractual classification of the
+	// sentinel. Nil when classification has not been performed.
+	Classification *Classification `json:"classification,omitempty"`
+
+	// Warnings lists analysis warnings naming the sentinel, such as
+	// another sentinel in the package sharing its message.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
 // AssertionType enumerates the kinds of test assertions Gaze can detect.
 type AssertionType string
 
@@ -318,6 +380,12 @@ type ContractCoverage struct {
 	// DiscardedReturns: len(DiscardedReturnHints) == len(DiscardedReturns).
 	// Omitted from JSON when there are no discarded returns.
 	DiscardedReturnHints []string `json:"discarded_return_hints,omitempty"`
+
+	// AssertedAmbiguous lists the IDs of ambiguous effects that are
+	// asserted on anyway. They do not count toward Percentage, but
+	// let GazeCRAP count ambiguous effects as contractual on request.
+	// Omitted from JSON when empty.
+	AssertedAmbiguous []string `json:"asserted_ambiguous,omitempty"`
 }
 
 // OverSpecificationScore measures how many incidental side effects
@@ -407,6 +475,31 @@ type PackageSummary struct {
 	SSADegradedPackages []string `json:"ssa_degraded_packages,omitempty"`
 }
 
+// FunctionCoverage is the contract coverage of one function across
+// all of the tests that target it: a contractual effect counts as
+// asserted when any test asserts on it.
+type FunctionCoverage struct {
+	// Target identifies the function.
+	Target FunctionTarget `json:"target"`
+
+	// Tests lists the test functions that target the function.
+	// Empty when the function has no tests.
+	Tests []string `json:"tests"`
+
+	// Percentage is AssertedCount over TotalContractual (0-100).
+	Percentage float64 `json:"percentage"`
+
+	// AssertedCount is the number of contractual effects asserted
+	// on by at least one test.
+	AssertedCount int `json:"asserted_count"`
+
+	// TotalContractual is the number of contractual effects.
+	TotalContractual int `json:"total_contractual"`
+
+	// Unasserted lists the contractual effects no test asserts on.
+	Unasserted []SideEffect `json:"unasserted"`
+}
+
 // GenerateID produces a stable, deterministic ID for a side effect
 // based on its context. The ID is a sha256 hash truncated to 8 hex
 // characters, prefixed with "se-".
@@ -415,3 +508,33 @@ func GenerateID(pkg, function, effectType, location string) string {
 	hash := sha256.Sum256([]byte(input))
 	return fmt.Sprintf("se-%x", hash[:4])
 }
+
+// UniqueIDs makes the IDs of one function's effects distinct. The
+// inputs to GenerateID do not always tell two effects apart, such as
+// the two results of (lo, hi int), which share a type and a
+// position. The first effect with a given ID keeps it; each later
+// one gets an ID derived from it, the effect's Target, and the
+// number of effects before it with the same ID, so IDs stay
+// deterministic for a given effect order.
+func UniqueIDs(effects []SideEffect) {
+	taken := make(map[string]bool, len(effects))
+	for _, e := range effects {
+		taken[e.ID] = true
+	}
+	occurrences := make(map[string]int, len(effects))
+	for i := range effects {
+		id := effects[i].ID
+		occurrences[id]++
+		if occurrences[id] == 1 {
+			continue
+		}
+		n := occurrences[id]
+		next := GenerateID(id, effects[i].Target, "occurrence", strconv.Itoa(n))
+		for taken[next] {
+			n++
+			next = GenerateID(id, effects[i].Target, "occurrence", strconv.Itoa(n))
+		}
+		taken[next] = true
+		effects[i].ID = next
+	}
+}
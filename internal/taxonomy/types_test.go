@@ -25,6 +25,43 @@ func TestGenerateID_Format(t *testing.T) {
 	}
 }
 
+func TestUniqueIDs(t *testing.T) {
+	id := GenerateID("pkg/foo", "Bounds", "ReturnValue", "foo.go:10:2")
+	effects := []SideEffect{
+		{ID: id, Type: ReturnValue, Target: "int"},
+		{ID: id, Type: ReturnValue, Target: "int"},
+		{ID: id, Type: ReturnValue, Target: "int"},
+		{ID: "se-00000001", Type: SliceMutation, Target: "s"},
+	}
+	UniqueIDs(effects)
+
+	if effects[0].ID != id {
+		t.Errorf("first effect ID changed to %q, want %q", effects[0].ID, id)
+	}
+	if effects[3].ID != "se-00000001" {
+		t.Errorf("distinct ID changed to %q", effects[3].ID)
+	}
+	seen := make(map[string]bool)
+	for _, e := range effects {
+		if seen[e.ID] {
+			t.Errorf("duplicate ID %q", e.ID)
+		}
+		seen[e.ID] = true
+	}
+
+	again := []SideEffect{
+		{ID: id, Type: ReturnValue, Target: "int"},
+		{ID: id, Type: ReturnValue, Target: "int"},
+		{ID: id, Type: ReturnValue, Target: "int"},
+	}
+	UniqueIDs(again)
+	for i := range again {
+		if again[i].ID != effects[i].ID {
+			t.Errorf("effect %d: ID %q on second run, want %q", i, again[i].ID, effects[i].ID)
+		}
+	}
+}
+
 func TestGenerateID_UniqueForDifferentInputs(t *testing.T) {
 	id1 := GenerateID("pkg/foo", "Save", "ReceiverMutation", "foo.go:10:2")
 	id2 := GenerateID("pkg/foo", "Save", "ReturnValue", "foo.go:10:2")
@@ -63,10 +100,12 @@ func TestTierOf_AllTypesHaveTiers(t *testing.T) {
 		FileSystemWrite, FileSystemDelete, FileSystemMeta,
 		DatabaseWrite, DatabaseTransaction, GoroutineSpawn,
 		Panic, CallbackInvocation, LogWrite, ContextCancellation,
+		ContextValue, MethodValueEscape, LocalPointerEscape,
+		InternalPointerEscape,
 		// P3
 		StdoutWrite, StderrWrite, EnvVarMutation,
 		MutexOp, WaitGroupOp, AtomicOp, TimeDependency,
-		ProcessExit, RecoverBehavior,
+		ProcessExit, RecoverBehavior, DeferredCleanup,
 		// P4
 		ReflectionMutation, UnsafeMutation, CgoCall,
 		FinalizerRegistration, SyncPoolOp,
@@ -280,3 +319,32 @@ func TestFunctionTarget_QualifiedName(t *testing.T) {
 		})
 	}
 }
+
+func TestIsKnownType(t *testing.T) {
+	for _, st := range []SideEffectType{ReturnValue, GlobalMutation, FileSystemWrite} {
+		if !IsKnownType(st) {
+			t.Errorf("IsKnownType(%s) = false, want true", st)
+		}
+	}
+	for _, st := range []SideEffectType{"", "GlobalMutations", "globalmutation"} {
+		if IsKnownType(st) {
+			t.Errorf("IsKnownType(%q) = true, want false", st)
+		}
+	}
+}
+
+func TestTypes(t *testing.T) {
+	types := Types()
+	if len(types) != len(tierMap) {
+		t.Fatalf("Types() returned %d types, want %d", len(types), len(tierMap))
+	}
+	if types[0] != ErrorReturn {
+		t.Errorf("Types()[0] = %s, want ErrorReturn (first P0 type by name)", types[0])
+	}
+	for i := 1; i < len(types); i++ {
+		prev, cur := types[i-1], types[i]
+		if TierOf(prev) > TierOf(cur) || (TierOf(prev) == TierOf(cur) && prev >= cur) {
+			t.Errorf("Types() not ordered by tier then name: %s before %s", prev, cur)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Package gaze is the supported programmatic entry point to Gaze's
+// side effect analysis. It wraps package loading, analysis, and
+// optional mechanical classification behind a small API that is
+// kept stable across releases, unlike the internal packages it is
+// built on.
+//
+//	results, err := gaze.AnalyzePackage("./internal/store", gaze.Options{Classify: true})
+//	if err != nil {
+//		return err
+//	}
+//	for _, r := range results {
+//		for _, e := range r.SideEffects {
+//			if e.Type == gaze.GlobalMutation {
+//				fmt.Println(r.Target.QualifiedName(), e.Location)
+//			}
+//		}
+//	}
+package gaze
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/unbound-force/gaze/internal/analysis"
+	"github.com/unbound-force/gaze/internal/classify"
+	"github.com/unbound-force/gaze/internal/config"
+	"github.com/unbound-force/gaze/internal/loader"
+)
+
+// Options configures AnalyzePackage. The zero value analyzes the
+// exported functions of the package without classification.
+type Options struct {
+	// IncludeUnexported includes unexported functions.
+	IncludeUnexported bool
+
+	// Function limits analysis to the function or method with this
+	// name. "Type.Method" and "(*Type).Method" select a single
+	// method. Empty means all functions.
+	Function string
+
+	// Depth adds the side effects of called functions in the same
+	// module, following calls up to this many levels deep, with
+	// effects on a callee's receiver or parameters attributed to the
+	// caller's. Zero reports each function's own effects only.
+	Depth int
+
+	// Classify attaches a contractual/incidental/ambiguous
+	// Classification to each side effect. This loads every package
+	// in the module containing the working directory, so it is
+	// considerably slower than analysis alone.
+	Classify bool
+
+	// ConfigPath is the .gaze.yaml used for classification. Empty
+	// discovers one by walking up from the package directory to the
+	// module root, falling back to the defaults.
+	ConfigPath string
+
+	// TestCallers additionally loads the module's test files so that
+	// effects exercised by existing tests count toward contractual
+	// classification. Only used with Classify.
+	TestCallers bool
+
+	// Verbose populates the detail fields (SourceFile, Excerpt,
+	// Reasoning) of classification signals.
+	Verbose bool
+}
+
+// Detector finds side effects in a single function declaration.
+// Register one with RegisterDetector to report domain-specific
+// effects alongside the built-in ones. Detect may be called from
+// several goroutines at once.
+type Detector = analysis.Detector
+
+// RegisterDetector adds d to every analysis run afterwards, including
+// those of the gaze CLI when built into a custom binary. Call it from
+// an init function. It panics if d is nil or name is already
+// registered.
+//
+// Effects may use any SideEffectType; types outside the taxonomy are
+// placed in tier P4. An empty Tier, Location, or ID is filled in from
+// the effect type and the analyzed function.
+func RegisterDetector(name string, d Detector) {
+	analysis.RegisterDetector(name, d)
+}
+
+// AnalyzePackage loads the package matched by pattern (an import
+// path or a relative directory such as "./internal/store"), detects
+// the side effects of its functions, and classifies them when
+// opts.Classify is set. Results are returned in source order.
+//
+// An error is returned if the package cannot be loaded or
+// type-checked, if the configuration is invalid, or if
+// opts.Function names a function that does not exist.
+func AnalyzePackage(pattern string, opts Options) ([]AnalysisResult, error) {
+	// Classification needs the whole module; serve the package from
+	// that one load instead of type-checking it twice.
+	load := loader.Load
+	var session *loader.Session
+	if opts.Classify {
+		session = loader.NewSession(moduleRoot())
+		load = session.Load
+	}
+	loaded, err := load(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := analysis.Analyze(loaded.Pkg, analysis.Options{
+		IncludeUnexported: opts.IncludeUnexported,
+		FunctionFilter:    opts.Function,
+		Depth:             opts.Depth,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 && opts.Function != "" {
+		return nil, fmt.Errorf("function %q not found in package %q", opts.Function, pattern)
+	}
+
+	if !opts.Classify {
+		return results, nil
+	}
+	cfg, err := loadConfig(pattern, opts.ConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	return classifyResults(results, loaded.Pkg, session, cfg, opts), nil
+}
+
+// loadConfig loads the config at path, or discovers one starting
+// from the package directory when path is empty.
+func loadConfig(pattern, path string) (*config.GazeConfig, error) {
+	if path != "" {
+		return config.Load(path)
+	}
+	startDir := "."
+	dir := strings.TrimSuffix(pattern, "/...")
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		startDir = dir
+	}
+	cfg, _, err := config.Discover(startDir)
+	return cfg, err
+}
+
+// moduleRoot returns the working directory, whose module is loaded
+// for classification, or "" if it cannot be determined.
+func moduleRoot() string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	return cwd
+}
+
+// classifyResults runs mechanical classification against the module
+// loaded by session. Module loading failures degrade the caller and
+// interface signals rather than failing, as in the gaze CLI.
+func classifyResults(
+	results []AnalysisResult,
+	target *packages.Package,
+	session *loader.Session,
+	cfg *config.GazeConfig,
+	opts Options,
+) []AnalysisResult {
+	cwd := moduleRoot()
+
+	var modPkgs []*packages.Package
+	if mod, err := session.Module(); err == nil {
+		modPkgs = mod.Packages
+	}
+	var testPkgs []*packages.Package
+	if opts.TestCallers {
+		if mod, err := loader.LoadModuleWithTests(cwd); err == nil {
+			testPkgs = mod.Packages
+		}
+	}
+
+	return classify.Classify(results, classify.Options{
+		Config:             cfg,
+		ModulePackages:     modPkgs,
+		ModuleTestPackages: testPkgs,
+		TargetPkg:          target,
+		ModuleRoot:         cwd,
+		Verbose:            opts.Verbose,
+	})
+}
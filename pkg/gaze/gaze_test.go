@@ -0,0 +1,73 @@
+package gaze_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/unbound-force/gaze/pkg/gaze"
+)
+
+const p1effects = "github.com/unbound-force/gaze/internal/analysis/testdata/src/p1effects"
+
+func TestAnalyzePackage(t *testing.T) {
+	results, err := gaze.AnalyzePackage(p1effects, gaze.Options{})
+	if err != nil {
+		t.Fatalf("AnalyzePackage: %v", err)
+	}
+	var found bool
+	for _, r := range results {
+		if r.Target.Function != "MutateGlobal" {
+			continue
+		}
+		for _, e := range r.SideEffects {
+			if e.Type == gaze.GlobalMutation && e.Tier == gaze.TierP1 {
+				found = true
+			}
+			if e.Classification != nil {
+				t.Errorf("expected no classification without Options.Classify")
+			}
+		}
+	}
+	if !found {
+		t.Error("expected a GlobalMutation effect on MutateGlobal")
+	}
+}
+
+func TestAnalyzePackage_FunctionNotFound(t *testing.T) {
+	_, err := gaze.AnalyzePackage(p1effects, gaze.Options{Function: "NoSuchFunc"})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestAnalyzePackage_LoadError(t *testing.T) {
+	if _, err := gaze.AnalyzePackage("./does-not-exist", gaze.Options{}); err == nil {
+		t.Error("expected error for a missing package")
+	}
+}
+
+func TestAnalyzePackage_Classify(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping module-wide classification in short mode")
+	}
+	results, err := gaze.AnalyzePackage(p1effects, gaze.Options{
+		Function: "MutateGlobal",
+		Classify: true,
+	})
+	if err != nil {
+		t.Fatalf("AnalyzePackage: %v", err)
+	}
+	for _, r := range results {
+		for _, e := range r.SideEffects {
+			if e.Classification == nil {
+				t.Errorf("%s: expected classification", e.Type)
+				continue
+			}
+			switch e.Classification.Label {
+			case gaze.Contractual, gaze.Incidental, gaze.Ambiguous:
+			default:
+				t.Errorf("%s: unexpected label %q", e.Type, e.Classification.Label)
+			}
+		}
+	}
+}
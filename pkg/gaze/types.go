@@ -0,0 +1,115 @@
+package gaze
+
+import "github.com/unbound-force/gaze/internal/taxonomy"
+
+// Result types. These are aliases, so values returned by Gaze can be
+// used wherever the underlying types are expected.
+type (
+	// AnalysisResult is the complete output for one function.
+	AnalysisResult = taxonomy.AnalysisResult
+
+	// FunctionTarget identifies an analyzed function.
+	FunctionTarget = taxonomy.FunctionTarget
+
+	// SideEffect is a single observable side effect of a function.
+	SideEffect = taxonomy.SideEffect
+
+	// SideEffectType enumerates the side effect categories.
+	SideEffectType = taxonomy.SideEffectType
+
+	// Tier is the priority tier (P0-P4) of a side effect type.
+	Tier = taxonomy.Tier
+
+	// Classification is the contractual classification of a side
+	// effect. Only set when Options.Classify is true.
+	Classification = taxonomy.Classification
+
+	// ClassificationLabel is contractual, incidental, or ambiguous.
+	ClassificationLabel = taxonomy.ClassificationLabel
+
+	// Signal is one piece of evidence behind a Classification.
+	Signal = taxonomy.Signal
+
+	// Metadata holds analysis run metadata, including warnings
+	// about incomplete analysis.
+	Metadata = taxonomy.Metadata
+)
+
+// P0 side effect types.
+const (
+	ReturnValue        = taxonomy.ReturnValue
+	ErrorReturn        = taxonomy.ErrorReturn
+	SentinelError      = taxonomy.SentinelError
+	ReceiverMutation   = taxonomy.ReceiverMutation
+	PointerArgMutation = taxonomy.PointerArgMutation
+)
+
+// P1 side effect types.
+const (
+	SliceMutation          = taxonomy.SliceMutation
+	MapMutation            = taxonomy.MapMutation
+	GlobalMutation         = taxonomy.GlobalMutation
+	WriterOutput           = taxonomy.WriterOutput
+	HTTPResponseWrite      = taxonomy.HTTPResponseWrite
+	ChannelSend            = taxonomy.ChannelSend
+	ChannelClose           = taxonomy.ChannelClose
+	DeferredReturnMutation = taxonomy.DeferredReturnMutation
+)
+
+// P2 side effect types.
+const (
+	FileSystemWrite       = taxonomy.FileSystemWrite
+	FileSystemDelete      = taxonomy.FileSystemDelete
+	FileSystemMeta        = taxonomy.FileSystemMeta
+	DatabaseWrite         = taxonomy.DatabaseWrite
+	DatabaseTransaction   = taxonomy.DatabaseTransaction
+	GoroutineSpawn        = taxonomy.GoroutineSpawn
+	Panic                 = taxonomy.Panic
+	CallbackInvocation    = taxonomy.CallbackInvocation
+	LogWrite              = taxonomy.LogWrite
+	ContextCancellation   = taxonomy.ContextCancellation
+	ContextValue          = taxonomy.ContextValue
+	MethodValueEscape     = taxonomy.MethodValueEscape
+	LocalPointerEscape    = taxonomy.LocalPointerEscape
+	InternalPointerEscape = taxonomy.InternalPointerEscape
+)
+
+// P3 side effect types.
+const (
+	StdoutWrite     = taxonomy.StdoutWrite
+	StderrWrite     = taxonomy.StderrWrite
+	EnvVarMutation  = taxonomy.EnvVarMutation
+	MutexOp         = taxonomy.MutexOp
+	WaitGroupOp     = taxonomy.WaitGroupOp
+	AtomicOp        = taxonomy.AtomicOp
+	TimeDependency  = taxonomy.TimeDependency
+	ProcessExit     = taxonomy.ProcessExit
+	RecoverBehavior = taxonomy.RecoverBehavior
+	DeferredCleanup = taxonomy.DeferredCleanup
+)
+
+// P4 side effect types.
+const (
+	ReflectionMutation     = taxonomy.ReflectionMutation
+	UnsafeMutation         = taxonomy.UnsafeMutation
+	CgoCall                = taxonomy.CgoCall
+	FinalizerRegistration  = taxonomy.FinalizerRegistration
+	SyncPoolOp             = taxonomy.SyncPoolOp
+	ClosureCaptureMutation = taxonomy.ClosureCaptureMutation
+)
+
+// Priority tiers.
+const (
+	TierP0 = taxonomy.TierP0
+	TierP1 = taxonomy.TierP1
+	TierP2 = taxonomy.TierP2
+	TierP3 = taxonomy.TierP3
+	TierP4 = taxonomy.TierP4
+)
+
+// Classification labels.
+const (
+	Contractual = taxonomy.Contractual
+	Incidental  = taxonomy.Incidental
+	Ambiguous   = taxonomy.Ambiguous
+)